@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+)
+
+// manages the keys of the encrypted columns. A master key is rotated by adding it to
+// encryption.master_keys, making it encryption.active_master_key and running -rewrap, the old one
+// can be removed from config afterwards. A data key is rotated with -rotate-data-key followed by
+// -reencrypt, which is also what encrypts the rows written before the columns were encrypted.
+func main() {
+	rotateDataKey := flag.Bool("rotate-data-key", false, "create a new active data key")
+	rewrap := flag.Bool("rewrap", false, "wrap every data key with the active master key")
+	reencrypt := flag.Bool("reencrypt", false, "encrypt every encrypted column with the active data key")
+	batchSize := flag.Int("batch-size", 500, "rows read per query while re-encrypting")
+	flag.Parse()
+
+	if !*rotateDataKey && !*rewrap && !*reencrypt {
+		flag.Usage()
+		return
+	}
+
+	viper := config.NewViper()
+	log := config.NewLogrus(viper)
+
+	uc := usecase.FieldEncryptionUseCaseFactory(log, viper)
+
+	if *rewrap {
+		count, err := uc.RewrapDataKeys()
+		if err != nil {
+			log.Fatal("failed to rewrap data keys: ", err)
+		}
+		fmt.Printf("rewrapped %d data keys\n", count)
+	}
+
+	if *rotateDataKey {
+		keyID, err := uc.RotateDataKey()
+		if err != nil {
+			log.Fatal("failed to rotate data key: ", err)
+		}
+		fmt.Printf("data key %s is now active\n", keyID)
+	}
+
+	if *reencrypt {
+		counts, err := uc.Reencrypt(*batchSize)
+		for _, table := range []string{"user_profiles", "document_sendings"} {
+			fmt.Printf("%s: %d rows re-encrypted\n", table, counts[table])
+		}
+		if err != nil {
+			log.Fatal("failed to re-encrypt: ", err)
+		}
+	}
+}
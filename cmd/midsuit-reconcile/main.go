@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+)
+
+// compares hired applicants with their Midsuit employees and reports drifts,
+// exits with status 1 when anything drifted so it can run from cron
+func main() {
+	asJSON := flag.Bool("json", false, "print the full report as JSON")
+	flag.Parse()
+
+	viper := config.NewViper()
+	log := config.NewLogrus(viper)
+
+	uc := usecase.MidsuitSyncUseCaseFactory(log, viper)
+	report, err := uc.ReconcileMidsuitEmployees()
+	if err != nil {
+		log.Fatal("failed to reconcile midsuit employees: ", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatal("failed to encode report: ", err)
+		}
+	} else {
+		fmt.Printf("checked %d hired applicants, %d in sync, %d drifted\n", report.Checked, report.InSync, len(report.Drifts))
+		for _, drift := range report.Drifts {
+			fmt.Printf("- %s (%s) midsuit_id=%q %s: %s\n", drift.Name, drift.UserProfileID, drift.MidsuitID, drift.Kind, drift.Message)
+			for _, field := range drift.Fields {
+				fmt.Printf("    %s: local=%q midsuit=%q\n", field.Field, field.Local, field.Midsuit)
+			}
+		}
+	}
+
+	if len(report.Drifts) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+)
+
+// serves the Midsuit simulator on its own, for services or tests that cannot
+// embed it
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8091", "address to listen on")
+	flag.Parse()
+
+	viper := config.NewViper()
+	log := config.NewLogrus(viper)
+
+	sim := service.MidsuitSimulatorFactory(viper, log)
+	midsuitURL, err := sim.Start(*addr)
+	if err != nil {
+		log.Fatal("failed to start midsuit simulator: ", err)
+	}
+	log.Info("Midsuit simulator running, set midsuit.url to ", midsuitURL)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	if err := sim.Close(); err != nil {
+		log.Error("failed to stop midsuit simulator: ", err)
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
 )
 
 func main() {
@@ -52,6 +54,31 @@ func main() {
 		&entity.DocumentAgreement{},
 		&entity.DocumentVerificationHeader{},
 		&entity.DocumentVerificationLine{},
+		&entity.MidsuitSyncJob{},
+		&entity.MidsuitSyncStep{},
+		&entity.TokenRevocation{},
+		&entity.SchedulerLock{},
+		&entity.SchedulerJobRun{},
+		&entity.ScheduleReminder{},
+		&entity.CandidateSearchDocument{},
+		&entity.ApplicantMatchScore{},
+		&entity.ApplicantMatchScoreItem{},
+		&entity.CurriculumVitaeDraft{},
+		&entity.CurriculumVitaeDraftEducation{},
+		&entity.CurriculumVitaeDraftWorkExperience{},
+		&entity.CurriculumVitaeDraftSkill{},
+		&entity.DuplicateCandidate{},
+		&entity.UserProfileMerge{},
+		&entity.PrivacyNotice{},
+		&entity.PrivacyConsent{},
+		&entity.ErasureRequest{},
+		&entity.EncryptionKey{},
+		&entity.UploadQuarantine{},
+		&entity.ApplicantStageHistory{},
+		&entity.SlaAlert{},
+		&entity.ApplicantSource{},
+		&entity.ReferralLink{},
+		&entity.Referral{},
 	)
 	if err != nil {
 		log.Fatal(err)
@@ -59,6 +86,13 @@ func main() {
 		log.Info("Migration success")
 	}
 
+	// give job postings created before slugs existed their public slug
+	filled, err := usecase.BackfillJobPostingSlugs(log, repository.NewJobPostingRepository(log, db))
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Infof("Backfilled %d job posting slugs", filled)
+
 	// seed answer type
 	answerTypes := []entity.AnswerType{
 		{Name: "Text"},
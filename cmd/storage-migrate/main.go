@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+)
+
+// copies the files under the local storage/ folder to the storage configured in storage.driver,
+// keeping their paths so nothing in the database has to change
+func main() {
+	deleteSource := flag.Bool("delete", false, "remove the local files once they are copied")
+	dryRun := flag.Bool("dry-run", false, "only list the files that would be copied")
+	flag.Parse()
+
+	viper := config.NewViper()
+	log := config.NewLogrus(viper)
+
+	if storage.StorageDriver(viper) == storage.STORAGE_DRIVER_LOCAL {
+		log.Fatal("storage.driver is local, set it to the target storage before migrating")
+	}
+
+	source := storage.NewLocalStorage(storage.LocalStorageRoot(viper))
+	target := storage.StorageFactory(viper, log)
+
+	report, err := storage.Migrate(source, target, *deleteSource, *dryRun, log)
+	if report != nil {
+		fmt.Printf("copied %d files (%d bytes), %d already there, %d local files removed\n", report.Copied, report.Bytes, report.Skipped, report.Deleted)
+	}
+	if err != nil {
+		log.Fatal("failed to migrate storage: ", err)
+	}
+}
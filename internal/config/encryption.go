@@ -0,0 +1,507 @@
+package config
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ENCRYPTED_VALUE_PREFIX marks values written by the encrypted serializer. Values without it were
+// stored before the column was encrypted and are read as plaintext until they are re-encrypted.
+const ENCRYPTED_VALUE_PREFIX = "enc:v1:"
+
+// how long the active data key is cached, so a key rotated from cmd/encryption-keys is picked up by
+// running instances without a restart
+const encryptionKeyCacheTTL = 5 * time.Minute
+
+var (
+	ErrEncryptionNotConfigured = errors.New("encryption master key is not configured")
+	ErrBlindIndexNotConfigured = errors.New("encryption blind index key is not configured")
+)
+
+var (
+	encryptorInstance *FieldEncryptor
+	encryptorOnce     sync.Once
+)
+
+func init() {
+	// must be registered before any entity schema using serializer:encrypted is parsed
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// FieldEncryptor encrypts single column values with envelope encryption: values are sealed with a
+// data key from the encryption_keys table, and the data keys are sealed with a master key from config.
+type FieldEncryptor struct {
+	masterKeys        map[string][]byte
+	activeMasterKeyID string
+	blindIndexKey     []byte
+
+	mu              sync.RWMutex
+	dataKeys        map[uuid.UUID][]byte
+	activeDataKeyID uuid.UUID
+	loadedAt        time.Time
+}
+
+func NewFieldEncryptor() *FieldEncryptor {
+	encryptorOnce.Do(func() {
+		encryptorInstance = newFieldEncryptor(NewViper())
+	})
+
+	return encryptorInstance
+}
+
+func newFieldEncryptor(viper *viper.Viper) *FieldEncryptor {
+	masterKeys := make(map[string][]byte)
+	// viper lowercases map keys, so master key ids are compared in lowercase
+	for id, encoded := range viper.GetStringMapString("encryption.master_keys") {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			panic(fmt.Errorf("Fatal error config file: encryption master key %q must be 32 bytes encoded in base64 \n", id))
+		}
+		masterKeys[strings.ToLower(id)] = key
+	}
+
+	var blindIndexKey []byte
+	if encoded := viper.GetString("encryption.blind_index_key"); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) < 32 {
+			panic(fmt.Errorf("Fatal error config file: encryption blind index key must be at least 32 bytes encoded in base64 \n"))
+		}
+		blindIndexKey = key
+	}
+
+	return &FieldEncryptor{
+		masterKeys:        masterKeys,
+		activeMasterKeyID: strings.ToLower(viper.GetString("encryption.active_master_key")),
+		blindIndexKey:     blindIndexKey,
+		dataKeys:          make(map[uuid.UUID][]byte),
+	}
+}
+
+// Encrypt seals plaintext with the active data key. The associated data binds the value to its
+// column, so a ciphertext copied into another column does not decrypt.
+func (e *FieldEncryptor) Encrypt(plaintext string, associatedData string) (string, error) {
+	keyID, key, err := e.activeDataKey()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := seal(key, []byte(plaintext), []byte(associatedData))
+	if err != nil {
+		return "", err
+	}
+
+	return ENCRYPTED_VALUE_PREFIX + keyID.String() + ":" + sealed, nil
+}
+
+// Decrypt opens a value written by Encrypt, values without the prefix are returned as they are
+func (e *FieldEncryptor) Decrypt(value string, associatedData string) (string, error) {
+	if !IsEncryptedValue(value) {
+		return value, nil
+	}
+
+	keyID, sealed, err := parseEncryptedValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := e.dataKey(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(key, sealed, []byte(associatedData))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsReencryption reports whether a stored value is plaintext or sealed with a retired data key
+func (e *FieldEncryptor) NeedsReencryption(value string) (bool, error) {
+	if !IsEncryptedValue(value) {
+		return true, nil
+	}
+
+	keyID, _, err := parseEncryptedValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	activeKeyID, _, err := e.activeDataKey()
+	if err != nil {
+		return false, err
+	}
+
+	return keyID != activeKeyID, nil
+}
+
+// BlindIndex returns a keyed hash of the value so encrypted columns can still be matched on
+// equality. Values are trimmed and lowercased first, an empty value has no index.
+func (e *FieldEncryptor) BlindIndex(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+	if len(e.blindIndexKey) == 0 {
+		return "", ErrBlindIndexNotConfigured
+	}
+
+	mac := hmac.New(sha256.New, e.blindIndexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// RotateDataKey creates a new data key under the active master key and retires the current one.
+// Values sealed with retired keys stay readable.
+func (e *FieldEncryptor) RotateDataKey() (uuid.UUID, error) {
+	masterKey, ok := e.masterKeys[e.activeMasterKeyID]
+	if !ok {
+		return uuid.Nil, ErrEncryptionNotConfigured
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return uuid.Nil, err
+	}
+
+	key := entity.EncryptionKey{
+		ID:          uuid.New(),
+		MasterKeyID: e.activeMasterKeyID,
+		Active:      true,
+	}
+	wrapped, err := seal(masterKey, raw, []byte(key.ID.String()))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	key.WrappedKey = wrapped
+
+	now := time.Now()
+	err = NewDatabase().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.EncryptionKey{}).Where("active = ?", true).Updates(map[string]interface{}{
+			"active":     false,
+			"retired_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&key).Error
+	})
+	if err != nil {
+		return uuid.Nil, errors.New("[FieldEncryptor.RotateDataKey] " + err.Error())
+	}
+
+	if err := e.loadDataKeys(); err != nil {
+		return uuid.Nil, err
+	}
+
+	return key.ID, nil
+}
+
+// RewrapDataKeys seals every data key that is not under the active master key with it, so an old
+// master key can be removed from config afterwards. It returns the number of rewrapped keys.
+func (e *FieldEncryptor) RewrapDataKeys() (int, error) {
+	masterKey, ok := e.masterKeys[e.activeMasterKeyID]
+	if !ok {
+		return 0, ErrEncryptionNotConfigured
+	}
+
+	var keys []entity.EncryptionKey
+	if err := NewDatabase().Where("master_key_id <> ?", e.activeMasterKeyID).Find(&keys).Error; err != nil {
+		return 0, errors.New("[FieldEncryptor.RewrapDataKeys] " + err.Error())
+	}
+
+	for _, key := range keys {
+		raw, err := e.unwrapDataKey(&key)
+		if err != nil {
+			return 0, err
+		}
+
+		wrapped, err := seal(masterKey, raw, []byte(key.ID.String()))
+		if err != nil {
+			return 0, err
+		}
+
+		if err := NewDatabase().Model(&key).Updates(map[string]interface{}{
+			"master_key_id": e.activeMasterKeyID,
+			"wrapped_key":   wrapped,
+		}).Error; err != nil {
+			return 0, errors.New("[FieldEncryptor.RewrapDataKeys] " + err.Error())
+		}
+	}
+
+	return len(keys), nil
+}
+
+func (e *FieldEncryptor) activeDataKey() (uuid.UUID, []byte, error) {
+	e.mu.RLock()
+	keyID, loadedAt := e.activeDataKeyID, e.loadedAt
+	key := e.dataKeys[keyID]
+	e.mu.RUnlock()
+
+	if keyID != uuid.Nil && time.Since(loadedAt) < encryptionKeyCacheTTL {
+		return keyID, key, nil
+	}
+
+	if err := e.loadDataKeys(); err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	e.mu.RLock()
+	keyID = e.activeDataKeyID
+	key = e.dataKeys[keyID]
+	e.mu.RUnlock()
+
+	if keyID == uuid.Nil {
+		// first encryption, nothing to rotate from yet
+		newKeyID, err := e.RotateDataKey()
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+
+		e.mu.RLock()
+		keyID = newKeyID
+		key = e.dataKeys[keyID]
+		e.mu.RUnlock()
+	}
+
+	return keyID, key, nil
+}
+
+func (e *FieldEncryptor) dataKey(keyID uuid.UUID) ([]byte, error) {
+	e.mu.RLock()
+	key, ok := e.dataKeys[keyID]
+	e.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := e.loadDataKeys(); err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	key, ok = e.dataKeys[keyID]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("encryption data key %s not found", keyID)
+	}
+
+	return key, nil
+}
+
+func (e *FieldEncryptor) loadDataKeys() error {
+	var keys []entity.EncryptionKey
+	if err := NewDatabase().Order("created_at ASC").Find(&keys).Error; err != nil {
+		return errors.New("[FieldEncryptor.loadDataKeys] " + err.Error())
+	}
+
+	dataKeys := make(map[uuid.UUID][]byte, len(keys))
+	activeDataKeyID := uuid.Nil
+	for _, key := range keys {
+		raw, err := e.unwrapDataKey(&key)
+		if err != nil {
+			return err
+		}
+
+		dataKeys[key.ID] = raw
+		if key.Active {
+			activeDataKeyID = key.ID
+		}
+	}
+
+	e.mu.Lock()
+	e.dataKeys = dataKeys
+	e.activeDataKeyID = activeDataKeyID
+	e.loadedAt = time.Now()
+	e.mu.Unlock()
+
+	return nil
+}
+
+func (e *FieldEncryptor) unwrapDataKey(key *entity.EncryptionKey) ([]byte, error) {
+	masterKey, ok := e.masterKeys[key.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption master key %q of data key %s is not configured", key.MasterKeyID, key.ID)
+	}
+
+	raw, err := open(masterKey, key.WrappedKey, []byte(key.ID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap encryption data key %s: %w", key.ID, err)
+	}
+
+	return raw, nil
+}
+
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, ENCRYPTED_VALUE_PREFIX)
+}
+
+func parseEncryptedValue(value string) (uuid.UUID, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(value, ENCRYPTED_VALUE_PREFIX), ":", 2)
+	if len(parts) != 2 {
+		return uuid.Nil, "", errors.New("malformed encrypted value")
+	}
+
+	keyID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, "", errors.New("malformed encrypted value: " + err.Error())
+	}
+
+	return keyID, parts[1], nil
+}
+
+// seal encrypts with AES-256-GCM and returns base64(nonce | ciphertext)
+func seal(key, plaintext, associatedData []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, associatedData)), nil
+}
+
+func open(key []byte, sealed string, associatedData []byte) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value is too short")
+	}
+
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], associatedData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptedSerializer is the gorm serializer behind `serializer:encrypted`. It supports string,
+// integer and float fields, zero values are stored as NULL.
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var stored string
+		switch v := dbValue.(type) {
+		case []byte:
+			stored = string(v)
+		case string:
+			stored = v
+		default:
+			stored = fmt.Sprint(v)
+		}
+
+		plaintext, err := NewFieldEncryptor().Decrypt(stored, encryptedFieldContext(field))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", encryptedFieldContext(field), err)
+		}
+
+		if err := setEncryptedFieldValue(fieldValue.Elem(), plaintext); err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", encryptedFieldContext(field), err)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok, err := encryptedFieldPlaintext(reflect.ValueOf(fieldValue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s: %w", encryptedFieldContext(field), err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return NewFieldEncryptor().Encrypt(plaintext, encryptedFieldContext(field))
+}
+
+func encryptedFieldContext(field *schema.Field) string {
+	return field.Schema.Table + "." + field.DBName
+}
+
+func encryptedFieldPlaintext(value reflect.Value) (string, bool, error) {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return "", false, nil
+	case reflect.Ptr:
+		if value.IsNil() {
+			return "", false, nil
+		}
+		return encryptedFieldPlaintext(value.Elem())
+	case reflect.String:
+		return value.String(), value.String() != "", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), value.Int() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), value.Float() != 0, nil
+	default:
+		return "", false, fmt.Errorf("unsupported field type %s", value.Type())
+	}
+}
+
+func setEncryptedFieldValue(value reflect.Value, plaintext string) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		value.Set(reflect.New(value.Type().Elem()))
+		return setEncryptedFieldValue(value.Elem(), plaintext)
+	case reflect.String:
+		value.SetString(plaintext)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(plaintext, 10, 64)
+		if err != nil {
+			return err
+		}
+		value.SetInt(number)
+	case reflect.Float32, reflect.Float64:
+		number, err := strconv.ParseFloat(plaintext, 64)
+		if err != nil {
+			return err
+		}
+		value.SetFloat(number)
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	testActiveDataKeyID  = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	testRetiredDataKeyID = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+)
+
+// newTestFieldEncryptor seeds the shared encryptor with in-memory data keys, so the serializer
+// neither reads config nor touches the encryption_keys table
+func newTestFieldEncryptor(t *testing.T) *FieldEncryptor {
+	t.Helper()
+
+	encryptorOnce.Do(func() {
+		encryptorInstance = &FieldEncryptor{
+			blindIndexKey: bytes.Repeat([]byte("b"), 32),
+			dataKeys: map[uuid.UUID][]byte{
+				testActiveDataKeyID:  bytes.Repeat([]byte("a"), 32),
+				testRetiredDataKeyID: bytes.Repeat([]byte("r"), 32),
+			},
+			activeDataKeyID: testActiveDataKeyID,
+			// far enough ahead that the cached keys never expire during the tests
+			loadedAt: time.Now().Add(24 * time.Hour),
+		}
+	})
+
+	return encryptorInstance
+}
+
+type encryptedTestRecord struct {
+	ID       uuid.UUID
+	Name     string   `gorm:"serializer:encrypted"`
+	Nickname string   `gorm:"serializer:encrypted"`
+	Age      int      `gorm:"serializer:encrypted"`
+	Salary   float64  `gorm:"serializer:encrypted"`
+	Bonus    *float64 `gorm:"serializer:encrypted"`
+}
+
+func encryptedTestField(t *testing.T, name string) *schema.Field {
+	t.Helper()
+
+	s, err := schema.Parse(&encryptedTestRecord{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("schema.Parse() error = %v", err)
+	}
+
+	field := s.LookUpField(name)
+	if field == nil {
+		t.Fatalf("field %s not found", name)
+	}
+	return field
+}
+
+func TestEncryptedSerializerRoundTrip(t *testing.T) {
+	newTestFieldEncryptor(t)
+	bonus := 1500000.5
+
+	tests := []struct {
+		name  string
+		field string
+		value interface{}
+	}{
+		{name: "string", field: "Name", value: "Budi Santoso"},
+		{name: "int", field: "Age", value: 31},
+		{name: "float", field: "Salary", value: 12500000.75},
+		{name: "pointer", field: "Bonus", value: &bonus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := encryptedTestField(t, tt.field)
+
+			stored, err := EncryptedSerializer{}.Value(context.Background(), field, reflect.Value{}, tt.value)
+			if err != nil {
+				t.Fatalf("Value() error = %v", err)
+			}
+			storedValue, ok := stored.(string)
+			if !ok || !strings.HasPrefix(storedValue, ENCRYPTED_VALUE_PREFIX+testActiveDataKeyID.String()+":") {
+				t.Fatalf("Value() = %v, want a value sealed with the active data key", stored)
+			}
+
+			var record encryptedTestRecord
+			dst := reflect.ValueOf(&record).Elem()
+			if err := (EncryptedSerializer{}).Scan(context.Background(), field, dst, []byte(storedValue)); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			got := dst.FieldByName(tt.field).Interface()
+			if !reflect.DeepEqual(got, tt.value) {
+				t.Fatalf("Scan() = %v, want %v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestEncryptedSerializerValue(t *testing.T) {
+	newTestFieldEncryptor(t)
+
+	tests := []struct {
+		name    string
+		field   string
+		value   interface{}
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "empty string is stored as NULL", field: "Name", value: "", wantNil: true},
+		{name: "zero int is stored as NULL", field: "Age", value: 0, wantNil: true},
+		{name: "nil pointer is stored as NULL", field: "Bonus", value: (*float64)(nil), wantNil: true},
+		{name: "unsupported type", field: "Name", value: []string{"a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := EncryptedSerializer{}.Value(context.Background(), encryptedTestField(t, tt.field), reflect.Value{}, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Value() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantNil && stored != nil {
+				t.Fatalf("Value() = %v, want nil", stored)
+			}
+		})
+	}
+}
+
+func TestEncryptedSerializerScan(t *testing.T) {
+	encryptor := newTestFieldEncryptor(t)
+
+	sealedName, err := EncryptedSerializer{}.Value(context.Background(), encryptedTestField(t, "Name"), reflect.Value{}, "Budi Santoso")
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	retired, err := seal(encryptor.dataKeys[testRetiredDataKeyID], []byte("Budi Santoso"), []byte(encryptedFieldContext(encryptedTestField(t, "Name"))))
+	if err != nil {
+		t.Fatalf("seal() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		dbValue interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "plaintext written before encryption is read as is", field: "Name", dbValue: "Budi Santoso", want: "Budi Santoso"},
+		{name: "plaintext number written before encryption", field: "Age", dbValue: int64(31), want: 31},
+		{name: "NULL is the zero value", field: "Name", dbValue: nil, want: ""},
+		{name: "NULL pointer stays nil", field: "Bonus", dbValue: nil, want: (*float64)(nil)},
+		{name: "sealed with a retired data key", field: "Name", dbValue: ENCRYPTED_VALUE_PREFIX + testRetiredDataKeyID.String() + ":" + retired, want: "Budi Santoso"},
+		{name: "copied into another column", field: "Nickname", dbValue: sealedName, wantErr: true},
+		{name: "tampered ciphertext", field: "Name", dbValue: sealedName.(string)[:len(sealedName.(string))-4] + "AAA=", wantErr: true},
+		{name: "malformed key id", field: "Name", dbValue: ENCRYPTED_VALUE_PREFIX + "not-a-uuid:abc", wantErr: true},
+		{name: "missing ciphertext", field: "Name", dbValue: ENCRYPTED_VALUE_PREFIX + testActiveDataKeyID.String(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var record encryptedTestRecord
+			dst := reflect.ValueOf(&record).Elem()
+
+			err := EncryptedSerializer{}.Scan(context.Background(), encryptedTestField(t, tt.field), dst, tt.dbValue)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := dst.FieldByName(tt.field).Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeedsReencryption(t *testing.T) {
+	encryptor := newTestFieldEncryptor(t)
+
+	active, err := encryptor.Encrypt("Budi Santoso", "users.name")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	retired, err := seal(encryptor.dataKeys[testRetiredDataKeyID], []byte("Budi Santoso"), []byte("users.name"))
+	if err != nil {
+		t.Fatalf("seal() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "plaintext", value: "Budi Santoso", want: true},
+		{name: "active data key", value: active},
+		{name: "retired data key", value: ENCRYPTED_VALUE_PREFIX + testRetiredDataKeyID.String() + ":" + retired, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encryptor.NeedsReencryption(tt.value)
+			if err != nil {
+				t.Fatalf("NeedsReencryption() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("NeedsReencryption() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	encryptor := newTestFieldEncryptor(t)
+	otherKey := &FieldEncryptor{blindIndexKey: bytes.Repeat([]byte("c"), 32)}
+	noKey := &FieldEncryptor{}
+
+	want, err := encryptor.BlindIndex("budi@example.com")
+	if err != nil {
+		t.Fatalf("BlindIndex() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		encryptor *FieldEncryptor
+		value     string
+		want      string
+		wantErr   error
+	}{
+		{name: "same value", encryptor: encryptor, value: "budi@example.com", want: want},
+		{name: "trimmed and lowercased", encryptor: encryptor, value: "  Budi@Example.COM ", want: want},
+		{name: "empty value has no index", encryptor: encryptor, value: "   ", want: ""},
+		{name: "empty value without a key", encryptor: noKey, value: "", want: ""},
+		{name: "no key configured", encryptor: noKey, value: "budi@example.com", wantErr: ErrBlindIndexNotConfigured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.encryptor.BlindIndex(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BlindIndex() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("BlindIndex() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if other, _ := otherKey.BlindIndex("budi@example.com"); other == want {
+		t.Fatalf("BlindIndex() with another key = %q, want a different index", other)
+	}
+}
@@ -17,6 +17,8 @@ type ApplicantDTO struct {
 	JobPostingDTO       IJobPostingDTO
 	Viper               *viper.Viper
 	TemplateQuestionDTO ITemplateQuestionDTO
+	MatchScoreDTO       IApplicantMatchScoreDTO
+	SourceDTO           IApplicantSourceDTO
 }
 
 func NewApplicantDTO(
@@ -25,6 +27,8 @@ func NewApplicantDTO(
 	jobPostingDTO IJobPostingDTO,
 	viper *viper.Viper,
 	tqDTO ITemplateQuestionDTO,
+	matchScoreDTO IApplicantMatchScoreDTO,
+	sourceDTO IApplicantSourceDTO,
 ) IApplicantDTO {
 	return &ApplicantDTO{
 		Log:                 log,
@@ -32,6 +36,8 @@ func NewApplicantDTO(
 		JobPostingDTO:       jobPostingDTO,
 		Viper:               viper,
 		TemplateQuestionDTO: tqDTO,
+		MatchScoreDTO:       matchScoreDTO,
+		SourceDTO:           sourceDTO,
 	}
 }
 
@@ -39,7 +45,9 @@ func ApplicantDTOFactory(log *logrus.Logger, viper *viper.Viper) IApplicantDTO {
 	userProfileDTO := UserProfileDTOFactory(log, viper)
 	jobPostingDTO := JobPostingDTOFactory(log, viper)
 	tqDTO := TemplateQuestionDTOFactory(log)
-	return NewApplicantDTO(log, userProfileDTO, jobPostingDTO, viper, tqDTO)
+	matchScoreDTO := ApplicantMatchScoreDTOFactory(log)
+	sourceDTO := ApplicantSourceDTOFactory(log)
+	return NewApplicantDTO(log, userProfileDTO, jobPostingDTO, viper, tqDTO, matchScoreDTO, sourceDTO)
 }
 
 func (dto *ApplicantDTO) ConvertEntityToResponse(ent *entity.Applicant) (*response.ApplicantResponse, error) {
@@ -80,5 +88,17 @@ func (dto *ApplicantDTO) ConvertEntityToResponse(ent *entity.Applicant) (*respon
 			jobPosting := dto.JobPostingDTO.ConvertEntityToResponse(ent.JobPosting)
 			return jobPosting
 		}(),
+		MatchScore: func() *response.ApplicantMatchScoreResponse {
+			if ent.MatchScore == nil {
+				return nil
+			}
+			return dto.MatchScoreDTO.ConvertEntityToResponse(ent.MatchScore)
+		}(),
+		Source: func() *response.ApplicantSourceResponse {
+			if ent.Source == nil {
+				return nil
+			}
+			return dto.SourceDTO.ConvertEntityToResponse(ent.Source)
+		}(),
 	}, nil
 }
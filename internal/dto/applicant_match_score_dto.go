@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+)
+
+type IApplicantMatchScoreDTO interface {
+	ConvertEntityToResponse(ent *entity.ApplicantMatchScore) *response.ApplicantMatchScoreResponse
+}
+
+type ApplicantMatchScoreDTO struct {
+	Log *logrus.Logger
+}
+
+func NewApplicantMatchScoreDTO(log *logrus.Logger) IApplicantMatchScoreDTO {
+	return &ApplicantMatchScoreDTO{
+		Log: log,
+	}
+}
+
+func ApplicantMatchScoreDTOFactory(log *logrus.Logger) IApplicantMatchScoreDTO {
+	return NewApplicantMatchScoreDTO(log)
+}
+
+func (dto *ApplicantMatchScoreDTO) ConvertEntityToResponse(ent *entity.ApplicantMatchScore) *response.ApplicantMatchScoreResponse {
+	items := []response.ApplicantMatchScoreItemResponse{}
+	for _, item := range ent.Items {
+		items = append(items, response.ApplicantMatchScoreItemResponse{
+			Criterion:      item.Criterion,
+			Weight:         item.Weight,
+			Score:          item.Score,
+			Applicable:     item.Applicable,
+			Requirement:    item.Requirement,
+			CandidateValue: item.CandidateValue,
+			Note:           item.Note,
+		})
+	}
+
+	return &response.ApplicantMatchScoreResponse{
+		Score:    ent.Score,
+		ScoredAt: ent.ScoredAt,
+		Items:    items,
+	}
+}
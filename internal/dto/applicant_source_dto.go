@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+)
+
+type IApplicantSourceDTO interface {
+	ConvertEntityToResponse(ent *entity.ApplicantSource) *response.ApplicantSourceResponse
+}
+
+type ApplicantSourceDTO struct {
+	Log *logrus.Logger
+}
+
+func NewApplicantSourceDTO(log *logrus.Logger) IApplicantSourceDTO {
+	return &ApplicantSourceDTO{
+		Log: log,
+	}
+}
+
+func ApplicantSourceDTOFactory(log *logrus.Logger) IApplicantSourceDTO {
+	return NewApplicantSourceDTO(log)
+}
+
+func (dto *ApplicantSourceDTO) ConvertEntityToResponse(ent *entity.ApplicantSource) *response.ApplicantSourceResponse {
+	return &response.ApplicantSourceResponse{
+		ApplicantID:  ent.ApplicantID,
+		Channel:      ent.Channel,
+		UtmSource:    ent.UtmSource,
+		UtmMedium:    ent.UtmMedium,
+		UtmCampaign:  ent.UtmCampaign,
+		ReferralCode: ent.ReferralCode,
+		Note:         ent.Note,
+		SetBy:        ent.SetBy,
+		UpdatedAt:    ent.UpdatedAt,
+	}
+}
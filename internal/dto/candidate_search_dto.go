@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"html"
+	"strings"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ICandidateSearchDTO interface {
+	ConvertEntityToResponse(ent *entity.CandidateSearchDocument) (*response.CandidateSearchResultResponse, error)
+}
+
+type CandidateSearchDTO struct {
+	Log            *logrus.Logger
+	UserProfileDTO IUserProfileDTO
+}
+
+func NewCandidateSearchDTO(
+	log *logrus.Logger,
+	upDTO IUserProfileDTO,
+) ICandidateSearchDTO {
+	return &CandidateSearchDTO{
+		Log:            log,
+		UserProfileDTO: upDTO,
+	}
+}
+
+func CandidateSearchDTOFactory(log *logrus.Logger, viper *viper.Viper) ICandidateSearchDTO {
+	upDTO := UserProfileDTOFactory(log, viper)
+	return NewCandidateSearchDTO(log, upDTO)
+}
+
+func (dto *CandidateSearchDTO) ConvertEntityToResponse(ent *entity.CandidateSearchDocument) (*response.CandidateSearchResultResponse, error) {
+	res := &response.CandidateSearchResultResponse{
+		Rank:       ent.Rank,
+		Highlights: highlightFragments(ent.Highlight),
+	}
+	if ent.UserProfile != nil {
+		userProfile, err := dto.UserProfileDTO.ConvertEntityToResponseWithoutUser(ent.UserProfile)
+		if err != nil {
+			dto.Log.Error("[CandidateSearchDTO.ConvertEntityToResponse] " + err.Error())
+			return nil, err
+		}
+		res.UserProfile = userProfile
+	}
+
+	return res, nil
+}
+
+// highlightFragments escapes the headline, which is profile text entered by candidates,
+// before the matched words are wrapped in <mark>
+func highlightFragments(headline string) []string {
+	fragments := []string{}
+	if !strings.Contains(headline, entity.CANDIDATE_SEARCH_HIGHLIGHT_START) {
+		return fragments
+	}
+
+	replacer := strings.NewReplacer(
+		entity.CANDIDATE_SEARCH_HIGHLIGHT_START, "<mark>",
+		entity.CANDIDATE_SEARCH_HIGHLIGHT_STOP, "</mark>",
+	)
+	for _, fragment := range strings.Split(headline, entity.CANDIDATE_SEARCH_FRAGMENT_DELIMITER) {
+		fragment = strings.Join(strings.Fields(fragment), " ")
+		if fragment == "" {
+			continue
+		}
+		fragments = append(fragments, replacer.Replace(html.EscapeString(fragment)))
+	}
+
+	return fragments
+}
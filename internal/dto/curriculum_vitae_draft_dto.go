@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ICurriculumVitaeDraftDTO interface {
+	ConvertEntityToResponse(ent *entity.CurriculumVitaeDraft) *response.CurriculumVitaeDraftResponse
+}
+
+type CurriculumVitaeDraftDTO struct {
+	Log   *logrus.Logger
+	Viper *viper.Viper
+}
+
+func NewCurriculumVitaeDraftDTO(log *logrus.Logger, viper *viper.Viper) ICurriculumVitaeDraftDTO {
+	return &CurriculumVitaeDraftDTO{
+		Log:   log,
+		Viper: viper,
+	}
+}
+
+func CurriculumVitaeDraftDTOFactory(log *logrus.Logger, viper *viper.Viper) ICurriculumVitaeDraftDTO {
+	return NewCurriculumVitaeDraftDTO(log, viper)
+}
+
+func (dto *CurriculumVitaeDraftDTO) ConvertEntityToResponse(ent *entity.CurriculumVitaeDraft) *response.CurriculumVitaeDraftResponse {
+	educations := []response.CurriculumVitaeDraftEducationResponse{}
+	for _, education := range ent.Educations {
+		educations = append(educations, response.CurriculumVitaeDraftEducationResponse{
+			EducationLevel: education.EducationLevel,
+			Major:          education.Major,
+			SchoolName:     education.SchoolName,
+			UniversityID:   education.UniversityID,
+			GraduateYear:   education.GraduateYear,
+			Gpa:            education.Gpa,
+		})
+	}
+
+	workExperiences := []response.CurriculumVitaeDraftWorkExperienceResponse{}
+	for _, workExperience := range ent.WorkExperiences {
+		workExperiences = append(workExperiences, response.CurriculumVitaeDraftWorkExperienceResponse{
+			Name:           workExperience.Name,
+			CompanyName:    workExperience.CompanyName,
+			YearExperience: workExperience.YearExperience,
+			JobDescription: workExperience.JobDescription,
+		})
+	}
+
+	skills := []response.CurriculumVitaeDraftSkillResponse{}
+	for _, skill := range ent.Skills {
+		skills = append(skills, response.CurriculumVitaeDraftSkillResponse{
+			Name:        skill.Name,
+			Description: skill.Description,
+		})
+	}
+
+	return &response.CurriculumVitaeDraftResponse{
+		ID:              ent.ID,
+		UserID:          ent.UserID,
+		UserProfileID:   ent.UserProfileID,
+		CurriculumVitae: storage.URL(dto.Viper, ent.FilePath),
+		Status:          ent.Status,
+		Name:            ent.Name,
+		Email:           ent.Email,
+		PhoneNumber:     ent.PhoneNumber,
+		Gender:          ent.Gender,
+		BirthPlace:      ent.BirthPlace,
+		BirthDate:       ent.BirthDate,
+		Address:         ent.Address,
+		AcceptedAt:      ent.AcceptedAt,
+		Educations:      educations,
+		WorkExperiences: workExperiences,
+		Skills:          skills,
+		CreatedAt:       ent.CreatedAt,
+		UpdatedAt:       ent.UpdatedAt,
+	}
+}
@@ -3,6 +3,7 @@ package dto
 import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -39,7 +40,7 @@ func (dto *DocumentAgreementDTOIDocumentAgreementDTO) ConvertEntityToResponse(en
 		DocumentSendingID: ent.DocumentSendingID,
 		ApplicantID:       ent.ApplicantID,
 		Status:            ent.Status,
-		Path:              dto.Viper.GetString("app.url") + ent.Path,
+		Path:              storage.URL(dto.Viper, ent.Path),
 		CreatedAt:         ent.CreatedAt,
 		UpdatedAt:         ent.UpdatedAt,
 		DocumentSending: func() *response.DocumentSendingResponse {
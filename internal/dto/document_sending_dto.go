@@ -5,12 +5,14 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
 type IDocumentSendingDTO interface {
 	ConvertEntityToResponse(ent *entity.DocumentSending) *response.DocumentSendingResponse
+	ConvertEntitiesToResponses(ents []entity.DocumentSending) []response.DocumentSendingResponse
 }
 
 type DocumentSendingDTO struct {
@@ -171,7 +173,7 @@ func (dto *DocumentSendingDTO) ConvertEntityToResponse(ent *entity.DocumentSendi
 		DetailContent:            ent.DetailContent,
 		Path: func() string {
 			if ent.Path != "" {
-				return dto.Viper.GetString("app.url") + ent.Path
+				return storage.URL(dto.Viper, ent.Path)
 			}
 			return ""
 		}(),
@@ -215,3 +217,68 @@ func (dto *DocumentSendingDTO) ConvertEntityToResponse(ent *entity.DocumentSendi
 		AllowanceApprovalName:    &allowanceApprovalName,
 	}
 }
+
+// ConvertEntitiesToResponses resolves the remote lookups of all rows with one
+// batch message per kind before converting, so the per-row lookups are served
+// from the lookup cache.
+func (dto *DocumentSendingDTO) ConvertEntitiesToResponses(ents []entity.DocumentSending) []response.DocumentSendingResponse {
+	jobLevelIDs := make([]string, 0, len(ents))
+	jobIDs := make([]string, 0, len(ents))
+	organizationIDs := make([]string, 0, len(ents))
+	organizationLocationIDs := make([]string, 0, len(ents))
+	gradeIDs := make([]string, 0, len(ents))
+	employeeIDs := make([]string, 0, len(ents))
+	jobPostings := make([]entity.JobPosting, 0, len(ents))
+	for _, ent := range ents {
+		if ent.JobLevelID != nil {
+			jobLevelIDs = append(jobLevelIDs, ent.JobLevelID.String())
+		}
+		if ent.JobID != nil {
+			jobIDs = append(jobIDs, ent.JobID.String())
+		}
+		if ent.ForOrganizationID != nil {
+			organizationIDs = append(organizationIDs, ent.ForOrganizationID.String())
+		}
+		if ent.OrganizationLocationID != nil {
+			organizationLocationIDs = append(organizationLocationIDs, ent.OrganizationLocationID.String())
+		}
+		if ent.GradeID != nil {
+			gradeIDs = append(gradeIDs, ent.GradeID.String())
+		}
+		if ent.AllowanceApproval != nil {
+			employeeIDs = append(employeeIDs, ent.AllowanceApproval.String())
+		}
+		if ent.JobPosting != nil {
+			jobPostings = append(jobPostings, *ent.JobPosting)
+		}
+	}
+
+	if _, err := dto.JobMessage.SendFindJobLevelsByIDsMessage(jobLevelIDs); err != nil {
+		dto.Log.Errorf("[DocumentSendingDTO.ConvertEntitiesToResponses] " + err.Error())
+	}
+	if _, err := dto.JobMessage.SendFindJobsByIDsMessage(jobIDs); err != nil {
+		dto.Log.Errorf("[DocumentSendingDTO.ConvertEntitiesToResponses] " + err.Error())
+	}
+	if _, err := dto.OrganizationMessage.SendFindOrganizationsByIDsMessage(organizationIDs); err != nil {
+		dto.Log.Errorf("[DocumentSendingDTO.ConvertEntitiesToResponses] " + err.Error())
+	}
+	if _, err := dto.OrganizationMessage.SendFindOrganizationLocationsByIDsMessage(organizationLocationIDs); err != nil {
+		dto.Log.Errorf("[DocumentSendingDTO.ConvertEntitiesToResponses] " + err.Error())
+	}
+	if _, err := dto.GradeMessage.SendFindByIDsMessage(gradeIDs); err != nil {
+		dto.Log.Errorf("[DocumentSendingDTO.ConvertEntitiesToResponses] " + err.Error())
+	}
+	if _, err := dto.EmployeeMessage.SendFindEmployeesByIDsMessage(employeeIDs); err != nil {
+		dto.Log.Errorf("[DocumentSendingDTO.ConvertEntitiesToResponses] " + err.Error())
+	}
+	if len(jobPostings) > 0 {
+		dto.JobPostingDTO.PrefetchLookups(jobPostings)
+	}
+
+	responses := make([]response.DocumentSendingResponse, 0, len(ents))
+	for i := range ents {
+		responses = append(responses, *dto.ConvertEntityToResponse(&ents[i]))
+	}
+
+	return responses
+}
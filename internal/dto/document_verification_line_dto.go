@@ -3,6 +3,7 @@ package dto
 import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -41,7 +42,7 @@ func (dto *DocumentVerificationLineDTO) ConvertEntityToResponse(ent *entity.Docu
 		DocumentVerificationID:       ent.DocumentVerificationID,
 		Path: func() string {
 			if ent.Path != "" {
-				return dto.Viper.GetString("app.url") + ent.Path
+				return storage.URL(dto.Viper, ent.Path)
 			}
 			return ""
 		}(),
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IDuplicateCandidateDTO interface {
+	ConvertEntityToResponse(ent *entity.DuplicateCandidate) (*response.DuplicateCandidateResponse, error)
+	ConvertMergeToResponse(ent *entity.UserProfileMerge) *response.UserProfileMergeResponse
+}
+
+type DuplicateCandidateDTO struct {
+	Log            *logrus.Logger
+	UserProfileDTO IUserProfileDTO
+}
+
+func NewDuplicateCandidateDTO(log *logrus.Logger, upDTO IUserProfileDTO) IDuplicateCandidateDTO {
+	return &DuplicateCandidateDTO{
+		Log:            log,
+		UserProfileDTO: upDTO,
+	}
+}
+
+func DuplicateCandidateDTOFactory(log *logrus.Logger, viper *viper.Viper) IDuplicateCandidateDTO {
+	upDTO := UserProfileDTOFactory(log, viper)
+	return NewDuplicateCandidateDTO(log, upDTO)
+}
+
+func (dto *DuplicateCandidateDTO) ConvertEntityToResponse(ent *entity.DuplicateCandidate) (*response.DuplicateCandidateResponse, error) {
+	res := &response.DuplicateCandidateResponse{
+		ID:                  ent.ID,
+		Status:              ent.Status,
+		Score:               ent.Score,
+		MatchKtpNumber:      ent.MatchKtpNumber,
+		MatchPhoneNumber:    ent.MatchPhoneNumber,
+		MatchEmail:          ent.MatchEmail,
+		MatchNameBirthDate:  ent.MatchNameBirthDate,
+		SharedJobPostingIDs: []uuid.UUID{},
+		DetectedAt:          ent.DetectedAt,
+		ResolvedAt:          ent.ResolvedAt,
+		ResolvedBy:          ent.ResolvedBy,
+	}
+
+	if ent.UserProfile != nil {
+		userProfile, err := dto.UserProfileDTO.ConvertEntityToResponseWithoutUser(ent.UserProfile)
+		if err != nil {
+			return nil, err
+		}
+		res.UserProfile = userProfile
+	}
+	if ent.DuplicateProfile != nil {
+		duplicateProfile, err := dto.UserProfileDTO.ConvertEntityToResponseWithoutUser(ent.DuplicateProfile)
+		if err != nil {
+			return nil, err
+		}
+		res.DuplicateProfile = duplicateProfile
+	}
+
+	// the jobs both profiles applied to, the repeated applications the duplicate was made for
+	if ent.UserProfile != nil && ent.DuplicateProfile != nil {
+		applied := map[uuid.UUID]bool{}
+		for _, applicant := range ent.UserProfile.Applicants {
+			applied[applicant.JobPostingID] = true
+		}
+		for _, applicant := range ent.DuplicateProfile.Applicants {
+			if applied[applicant.JobPostingID] {
+				res.SharedJobPostingIDs = append(res.SharedJobPostingIDs, applicant.JobPostingID)
+				applied[applicant.JobPostingID] = false
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (dto *DuplicateCandidateDTO) ConvertMergeToResponse(ent *entity.UserProfileMerge) *response.UserProfileMergeResponse {
+	return &response.UserProfileMergeResponse{
+		ID:                     ent.ID,
+		DuplicateCandidateID:   ent.DuplicateCandidateID,
+		SurvivingProfileID:     ent.SurvivingProfileID,
+		MergedProfileID:        ent.MergedProfileID,
+		MergedUserID:           ent.MergedUserID,
+		MergedBy:               ent.MergedBy,
+		MergedAt:               ent.MergedAt,
+		MovedApplicants:        ent.MovedApplicants,
+		DroppedApplicants:      ent.DroppedApplicants,
+		MovedEducations:        ent.MovedEducations,
+		MovedWorkExperiences:   ent.MovedWorkExperiences,
+		MovedSkills:            ent.MovedSkills,
+		MovedQuestionResponses: ent.MovedQuestionResponses,
+		Note:                   ent.Note,
+	}
+}
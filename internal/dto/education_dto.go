@@ -3,6 +3,7 @@ package dto
 import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -38,7 +39,7 @@ func (dto *EducationDTO) ConvertEntityToResponse(ent *entity.Education) *respons
 		EndDate:        ent.EndDate,
 		Certificate: func() *string {
 			if ent.Certificate != "" {
-				certificateURL := dto.Viper.GetString("app.url") + ent.Certificate
+				certificateURL := storage.URL(dto.Viper, ent.Certificate)
 				return &certificateURL
 			}
 			return nil
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+)
+
+type IErasureRequestDTO interface {
+	ConvertEntityToResponse(ent *entity.ErasureRequest) *response.ErasureRequestResponse
+}
+
+type ErasureRequestDTO struct {
+	Log *logrus.Logger
+}
+
+func NewErasureRequestDTO(log *logrus.Logger) IErasureRequestDTO {
+	return &ErasureRequestDTO{
+		Log: log,
+	}
+}
+
+func ErasureRequestDTOFactory(log *logrus.Logger) IErasureRequestDTO {
+	return NewErasureRequestDTO(log)
+}
+
+func (dto *ErasureRequestDTO) ConvertEntityToResponse(ent *entity.ErasureRequest) *response.ErasureRequestResponse {
+	res := &response.ErasureRequestResponse{
+		ID:            ent.ID,
+		UserProfileID: ent.UserProfileID,
+		Status:        ent.Status,
+		Reason:        ent.Reason,
+		RequestedAt:   ent.RequestedAt,
+		ReviewedBy:    ent.ReviewedBy,
+		ReviewedAt:    ent.ReviewedAt,
+		ReviewNote:    ent.ReviewNote,
+		FailedFiles:   []string{},
+	}
+	if ent.FailedFiles != "" {
+		res.FailedFiles = strings.Split(ent.FailedFiles, "\n")
+	}
+
+	if ent.UserProfile != nil {
+		res.UserProfileName = ent.UserProfile.Name
+		res.ApplicationCount = len(ent.UserProfile.Applicants)
+		for _, applicant := range ent.UserProfile.Applicants {
+			if applicant.Status == entity.APPLICANT_STATUS_HIRED {
+				res.HasHiredApplication = true
+			}
+		}
+	}
+
+	return res
+}
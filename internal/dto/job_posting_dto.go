@@ -7,12 +7,15 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
 type IJobPostingDTO interface {
 	ConvertEntityToResponse(ent *entity.JobPosting) *response.JobPostingResponse
+	ConvertEntitiesToResponses(ents []entity.JobPosting) []response.JobPostingResponse
+	PrefetchLookups(ents []entity.JobPosting)
 }
 
 type JobPostingDTO struct {
@@ -120,17 +123,18 @@ func (dto *JobPostingDTO) ConvertEntityToResponse(ent *entity.JobPosting) *respo
 		MinimumWorkExperience:      ent.MinimumWorkExperience,
 		Name:                       ent.Name,
 		IsShow:                     ent.IsShow,
+		Slug:                       ent.Slug,
 		OrganizationLogo: func() *string {
 			if ent.OrganizationLogo != "" {
 				dto.Log.Info("Organization Logo: ", ent.OrganizationLogo)
-				organizationLogoURL := dto.Viper.GetString("app.url") + ent.OrganizationLogo
+				organizationLogoURL := storage.URL(dto.Viper, ent.OrganizationLogo)
 				return &organizationLogoURL
 			}
 			return nil
 		}(),
 		Poster: func() *string {
 			if ent.Poster != "" {
-				posterURL := dto.Viper.GetString("app.url") + ent.Poster
+				posterURL := storage.URL(dto.Viper, ent.Poster)
 				return &posterURL
 			}
 			return nil
@@ -159,3 +163,38 @@ func (dto *JobPostingDTO) ConvertEntityToResponse(ent *entity.JobPosting) *respo
 		}(),
 	}
 }
+
+// PrefetchLookups resolves the organizations, locations and jobs of all
+// postings with one batch message each, so the per-row lookups done by
+// ConvertEntityToResponse are served from the lookup cache.
+func (dto *JobPostingDTO) PrefetchLookups(ents []entity.JobPosting) {
+	organizationIDs := make([]string, 0, len(ents))
+	organizationLocationIDs := make([]string, 0, len(ents))
+	jobIDs := make([]string, 0, len(ents))
+	for _, ent := range ents {
+		organizationIDs = append(organizationIDs, ent.ForOrganizationID.String())
+		organizationLocationIDs = append(organizationLocationIDs, ent.ForOrganizationLocationID.String())
+		jobIDs = append(jobIDs, ent.JobID.String())
+	}
+
+	if _, err := dto.OrganizationMessage.SendFindOrganizationsByIDsMessage(organizationIDs); err != nil {
+		dto.Log.Errorf("[JobPostingDTO.PrefetchLookups] Failed to find organizations: %s", err.Error())
+	}
+	if _, err := dto.OrganizationMessage.SendFindOrganizationLocationsByIDsMessage(organizationLocationIDs); err != nil {
+		dto.Log.Errorf("[JobPostingDTO.PrefetchLookups] Failed to find organization locations: %s", err.Error())
+	}
+	if _, err := dto.JobMessage.SendFindJobsByIDsMessage(jobIDs); err != nil {
+		dto.Log.Errorf("[JobPostingDTO.PrefetchLookups] Failed to find jobs: %s", err.Error())
+	}
+}
+
+func (dto *JobPostingDTO) ConvertEntitiesToResponses(ents []entity.JobPosting) []response.JobPostingResponse {
+	dto.PrefetchLookups(ents)
+
+	responses := make([]response.JobPostingResponse, 0, len(ents))
+	for i := range ents {
+		responses = append(responses, *dto.ConvertEntityToResponse(&ents[i]))
+	}
+
+	return responses
+}
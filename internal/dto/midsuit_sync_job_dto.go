@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+)
+
+type IMidsuitSyncJobDTO interface {
+	ConvertEntityToResponse(ent *entity.MidsuitSyncJob) *response.MidsuitSyncJobResponse
+}
+
+type MidsuitSyncJobDTO struct {
+	Log *logrus.Logger
+}
+
+func NewMidsuitSyncJobDTO(log *logrus.Logger) IMidsuitSyncJobDTO {
+	return &MidsuitSyncJobDTO{
+		Log: log,
+	}
+}
+
+func MidsuitSyncJobDTOFactory(log *logrus.Logger) IMidsuitSyncJobDTO {
+	return NewMidsuitSyncJobDTO(log)
+}
+
+func (dto *MidsuitSyncJobDTO) ConvertEntityToResponse(ent *entity.MidsuitSyncJob) *response.MidsuitSyncJobResponse {
+	return &response.MidsuitSyncJobResponse{
+		ID:                ent.ID,
+		DocumentSendingID: ent.DocumentSendingID,
+		ApplicantID:       ent.ApplicantID,
+		EmployeeID:        ent.EmployeeID,
+		IdempotencyKey:    ent.IdempotencyKey,
+		Status:            ent.Status,
+		Attempts:          ent.Attempts,
+		MaxAttempts:       ent.MaxAttempts,
+		NextRunAt:         ent.NextRunAt,
+		StartedAt:         ent.StartedAt,
+		FinishedAt:        ent.FinishedAt,
+		LastError:         ent.LastError,
+		MidsuitEmployeeID: ent.MidsuitEmployeeID,
+		CreatedAt:         ent.CreatedAt,
+		UpdatedAt:         ent.UpdatedAt,
+		Steps: func() []response.MidsuitSyncStepResponse {
+			steps := make([]response.MidsuitSyncStepResponse, 0, len(ent.Steps))
+			for _, step := range ent.Steps {
+				steps = append(steps, response.MidsuitSyncStepResponse{
+					ID:              step.ID,
+					Name:            step.Name,
+					Order:           step.Order,
+					IdempotencyKey:  step.IdempotencyKey,
+					Status:          step.Status,
+					Attempts:        step.Attempts,
+					LastError:       step.LastError,
+					MidsuitRecordID: step.MidsuitRecordID,
+					CompletedAt:     step.CompletedAt,
+					UpdatedAt:       step.UpdatedAt,
+				})
+			}
+			return steps
+		}(),
+	}
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+)
+
+type IPrivacyNoticeDTO interface {
+	ConvertEntityToResponse(ent *entity.PrivacyNotice) *response.PrivacyNoticeResponse
+	ConvertConsentToResponse(ent *entity.PrivacyConsent) *response.PrivacyConsentResponse
+}
+
+type PrivacyNoticeDTO struct {
+	Log *logrus.Logger
+}
+
+func NewPrivacyNoticeDTO(log *logrus.Logger) IPrivacyNoticeDTO {
+	return &PrivacyNoticeDTO{
+		Log: log,
+	}
+}
+
+func PrivacyNoticeDTOFactory(log *logrus.Logger) IPrivacyNoticeDTO {
+	return NewPrivacyNoticeDTO(log)
+}
+
+func (dto *PrivacyNoticeDTO) ConvertEntityToResponse(ent *entity.PrivacyNotice) *response.PrivacyNoticeResponse {
+	return &response.PrivacyNoticeResponse{
+		ID:          ent.ID,
+		Version:     ent.Version,
+		Title:       ent.Title,
+		Content:     ent.Content,
+		PublishedAt: ent.PublishedAt,
+		PublishedBy: ent.PublishedBy,
+	}
+}
+
+func (dto *PrivacyNoticeDTO) ConvertConsentToResponse(ent *entity.PrivacyConsent) *response.PrivacyConsentResponse {
+	return &response.PrivacyConsentResponse{
+		ID:              ent.ID,
+		UserProfileID:   ent.UserProfileID,
+		PrivacyNoticeID: ent.PrivacyNoticeID,
+		NoticeVersion:   ent.NoticeVersion,
+		ConsentedAt:     ent.ConsentedAt,
+		IPAddress:       ent.IPAddress,
+		UserAgent:       ent.UserAgent,
+	}
+}
@@ -10,6 +10,10 @@ import (
 
 type IProjectPicDTO interface {
 	ConvertEntityToResponse(ent *entity.ProjectPic) *response.ProjectPicResponse
+	ConvertEntitiesToResponses(ents []entity.ProjectPic) []response.ProjectPicResponse
+	// Prefetch looks the employees of the PICs up in one batch, so converting them afterwards
+	// only hits the lookup cache
+	Prefetch(ents []entity.ProjectPic)
 }
 
 type ProjectPicDTO struct {
@@ -53,3 +57,30 @@ func (dto *ProjectPicDTO) ConvertEntityToResponse(ent *entity.ProjectPic) *respo
 		UpdatedAt:                ent.UpdatedAt,
 	}
 }
+
+func (dto *ProjectPicDTO) Prefetch(ents []entity.ProjectPic) {
+	if len(ents) == 0 {
+		return
+	}
+
+	employeeIDs := make([]string, 0, len(ents))
+	for _, ent := range ents {
+		employeeIDs = append(employeeIDs, ent.EmployeeID.String())
+	}
+
+	if _, err := dto.EmployeeMessage.SendFindEmployeesByIDsMessage(employeeIDs); err != nil {
+		dto.Log.Errorf("[ProjectPicDTO.Prefetch] " + err.Error())
+	}
+}
+
+func (dto *ProjectPicDTO) ConvertEntitiesToResponses(ents []entity.ProjectPic) []response.ProjectPicResponse {
+	// warm the lookup cache so each row below is a cache hit, a no-op when a list prefetched them
+	dto.Prefetch(ents)
+
+	responses := make([]response.ProjectPicResponse, 0, len(ents))
+	for i := range ents {
+		responses = append(responses, *dto.ConvertEntityToResponse(&ents[i]))
+	}
+
+	return responses
+}
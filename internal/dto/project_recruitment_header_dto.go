@@ -11,6 +11,9 @@ import (
 
 type IProjectRecruitmentHeaderDTO interface {
 	ConvertEntityToResponse(ent *entity.ProjectRecruitmentHeader) *response.ProjectRecruitmentHeaderResponse
+	// PrefetchEmployees looks the PICs of every header and of their lines up in one batch, lists
+	// call it before converting their rows
+	PrefetchEmployees(ents []entity.ProjectRecruitmentHeader)
 }
 
 type ProjectRecruitmentHeaderDTO struct {
@@ -41,6 +44,27 @@ func ProjectRecruitmentHeaderDTOFactory(log *logrus.Logger) IProjectRecruitmentH
 	return NewProjectRecruitmentHeaderDTO(log, templateActivityDTO, prlDTO, empMessage)
 }
 
+func (dto *ProjectRecruitmentHeaderDTO) PrefetchEmployees(ents []entity.ProjectRecruitmentHeader) {
+	employeeIDs := make([]string, 0)
+	for _, ent := range ents {
+		if ent.ProjectPicID != nil {
+			employeeIDs = append(employeeIDs, ent.ProjectPicID.String())
+		}
+		for _, line := range ent.ProjectRecruitmentLines {
+			for _, pic := range line.ProjectPics {
+				employeeIDs = append(employeeIDs, pic.EmployeeID.String())
+			}
+		}
+	}
+	if len(employeeIDs) == 0 {
+		return
+	}
+
+	if _, err := dto.EmployeeMessage.SendFindEmployeesByIDsMessage(employeeIDs); err != nil {
+		dto.Log.Errorf("[ProjectRecruitmentHeaderDTO.PrefetchEmployees] " + err.Error())
+	}
+}
+
 func (dto *ProjectRecruitmentHeaderDTO) ConvertEntityToResponse(ent *entity.ProjectRecruitmentHeader) *response.ProjectRecruitmentHeaderResponse {
 	var employeeName string
 	if ent.ProjectPicID != nil {
@@ -10,6 +10,9 @@ import (
 
 type IProjectRecruitmentLineDTO interface {
 	ConvertEntityToResponse(ent *entity.ProjectRecruitmentLine) *response.ProjectRecruitmentLineResponse
+	// PrefetchProjectPics looks the PICs of every line up in one batch, lists call it before
+	// converting their rows
+	PrefetchProjectPics(ents []entity.ProjectRecruitmentLine)
 }
 
 type ProjectRecruitmentLineDTO struct {
@@ -36,6 +39,14 @@ func ProjectRecruitmentLineDTOFactory(log *logrus.Logger) IProjectRecruitmentLin
 	return NewProjectRecruitmentLineDTO(log, projectPicDTO, taDTO)
 }
 
+func (dto *ProjectRecruitmentLineDTO) PrefetchProjectPics(ents []entity.ProjectRecruitmentLine) {
+	pics := make([]entity.ProjectPic, 0)
+	for _, ent := range ents {
+		pics = append(pics, ent.ProjectPics...)
+	}
+	dto.ProjectPicDTO.Prefetch(pics)
+}
+
 func (dto *ProjectRecruitmentLineDTO) ConvertEntityToResponse(ent *entity.ProjectRecruitmentLine) *response.ProjectRecruitmentLineResponse {
 	var startDate *time.Time
 	if !ent.StartDate.IsZero() {
@@ -64,11 +75,7 @@ func (dto *ProjectRecruitmentLineDTO) ConvertEntityToResponse(ent *entity.Projec
 			if ent.ProjectPics == nil {
 				return nil
 			}
-			var projectPicResponses []response.ProjectPicResponse
-			for _, projectPic := range ent.ProjectPics {
-				projectPicResponses = append(projectPicResponses, *dto.ProjectPicDTO.ConvertEntityToResponse(&projectPic))
-			}
-			return projectPicResponses
+			return dto.ProjectPicDTO.ConvertEntitiesToResponses(ent.ProjectPics)
 		}(),
 		TemplateActivityLine: func() *response.TemplateActivityLineResponse {
 			if ent.TemplateActivityLine == nil {
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+)
+
+type ISchedulerDTO interface {
+	ConvertJobRunEntityToResponse(ent *entity.SchedulerJobRun) *response.SchedulerJobRunResponse
+}
+
+type SchedulerDTO struct {
+	Log *logrus.Logger
+}
+
+func NewSchedulerDTO(log *logrus.Logger) ISchedulerDTO {
+	return &SchedulerDTO{
+		Log: log,
+	}
+}
+
+func SchedulerDTOFactory(log *logrus.Logger) ISchedulerDTO {
+	return NewSchedulerDTO(log)
+}
+
+func (dto *SchedulerDTO) ConvertJobRunEntityToResponse(ent *entity.SchedulerJobRun) *response.SchedulerJobRunResponse {
+	return &response.SchedulerJobRunResponse{
+		ID:         ent.ID,
+		JobName:    ent.JobName,
+		Trigger:    ent.Trigger,
+		Owner:      ent.Owner,
+		Status:     ent.Status,
+		StartedAt:  ent.StartedAt,
+		FinishedAt: ent.FinishedAt,
+		Result:     ent.Result,
+		Error:      ent.Error,
+	}
+}
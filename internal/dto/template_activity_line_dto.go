@@ -32,15 +32,17 @@ func TemplateActivityLineDTOFactory(log *logrus.Logger) ITemplateActivityLineDTO
 
 func (dto *TemplateActivityLineDTO) ConvertEntityToResponse(ent *entity.TemplateActivityLine) *response.TemplateActivityLineResponse {
 	return &response.TemplateActivityLineResponse{
-		ID:                 ent.ID,
-		TemplateActivityID: ent.TemplateActivityID,
-		TemplateQuestionID: ent.QuestionTemplateID,
-		Name:               ent.Name,
-		Description:        ent.Description,
-		Status:             ent.Status,
-		ColorHexCode:       ent.ColorHexCode,
-		CreatedAt:          ent.CreatedAt,
-		UpdatedAt:          ent.UpdatedAt,
+		ID:                  ent.ID,
+		TemplateActivityID:  ent.TemplateActivityID,
+		TemplateQuestionID:  ent.QuestionTemplateID,
+		Name:                ent.Name,
+		Description:         ent.Description,
+		Status:              ent.Status,
+		ColorHexCode:        ent.ColorHexCode,
+		MaxDaysInStage:      ent.MaxDaysInStage,
+		MaxDaysToFillResult: ent.MaxDaysToFillResult,
+		CreatedAt:           ent.CreatedAt,
+		UpdatedAt:           ent.UpdatedAt,
 		TemplateQuestion: func() *response.TemplateQuestionResponse {
 			if ent.TemplateQuestion == nil {
 				return nil
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/sirupsen/logrus"
+)
+
+type ITokenRevocationDTO interface {
+	ConvertEntityToResponse(ent *entity.TokenRevocation) *response.TokenRevocationResponse
+}
+
+type TokenRevocationDTO struct {
+	Log *logrus.Logger
+}
+
+func NewTokenRevocationDTO(log *logrus.Logger) ITokenRevocationDTO {
+	return &TokenRevocationDTO{
+		Log: log,
+	}
+}
+
+func TokenRevocationDTOFactory(log *logrus.Logger) ITokenRevocationDTO {
+	return NewTokenRevocationDTO(log)
+}
+
+func (dto *TokenRevocationDTO) ConvertEntityToResponse(ent *entity.TokenRevocation) *response.TokenRevocationResponse {
+	return &response.TokenRevocationResponse{
+		ID:        ent.ID,
+		Kind:      ent.Kind,
+		TokenID:   ent.TokenID,
+		UserID:    ent.UserID,
+		RevokedAt: ent.RevokedAt,
+		ExpiresAt: ent.ExpiresAt,
+		Reason:    ent.Reason,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -86,23 +87,26 @@ func (dto *UserProfileDTO) ConvertEntityToResponse(ent *entity.UserProfile) (*re
 		CurrentSalary:  ent.CurrentSalary,
 		Religion:       ent.Religion,
 		MidsuitID:      ent.MidsuitID,
+		KtpNumber:      ent.KtpNumber,
+		Email:          ent.Email,
+		AnonymisedAt:   ent.AnonymisedAt,
 		Avatar: func() *string {
 			if ent.Avatar != "" {
-				avatarURL := dto.Viper.GetString("app.url") + ent.Avatar
+				avatarURL := storage.URL(dto.Viper, ent.Avatar)
 				return &avatarURL
 			}
 			return nil
 		}(),
 		Ktp: func() *string {
 			if ent.Ktp != "" {
-				ktpURL := dto.Viper.GetString("app.url") + ent.Ktp
+				ktpURL := storage.URL(dto.Viper, ent.Ktp)
 				return &ktpURL
 			}
 			return nil
 		}(),
 		CurriculumVitae: func() *string {
 			if ent.CurriculumVitae != "" {
-				cvURL := dto.Viper.GetString("app.url") + ent.CurriculumVitae
+				cvURL := storage.URL(dto.Viper, ent.CurriculumVitae)
 				return &cvURL
 			}
 			return nil
@@ -146,34 +150,41 @@ func (dto *UserProfileDTO) ConvertEntityToResponse(ent *entity.UserProfile) (*re
 
 func (dto *UserProfileDTO) ConvertEntityToResponseWithoutUser(ent *entity.UserProfile) (*response.UserProfileResponse, error) {
 	return &response.UserProfileResponse{
-		ID:            ent.ID,
-		UserID:        ent.UserID,
-		Name:          ent.Name,
-		MaritalStatus: ent.MaritalStatus,
-		Gender:        ent.Gender,
-		PhoneNumber:   ent.PhoneNumber,
-		Age:           ent.Age,
-		BirthDate:     ent.BirthDate,
-		BirthPlace:    ent.BirthPlace,
-		Address:       ent.Address,
-		Bilingual:     ent.Bilingual,
+		ID:             ent.ID,
+		UserID:         ent.UserID,
+		Name:           ent.Name,
+		MaritalStatus:  ent.MaritalStatus,
+		Gender:         ent.Gender,
+		PhoneNumber:    ent.PhoneNumber,
+		Age:            ent.Age,
+		BirthDate:      ent.BirthDate,
+		BirthPlace:     ent.BirthPlace,
+		Address:        ent.Address,
+		Bilingual:      ent.Bilingual,
+		ExpectedSalary: ent.ExpectedSalary,
+		CurrentSalary:  ent.CurrentSalary,
+		Religion:       ent.Religion,
+		MidsuitID:      ent.MidsuitID,
+		KtpNumber:      ent.KtpNumber,
+		Email:          ent.Email,
+		AnonymisedAt:   ent.AnonymisedAt,
 		Avatar: func() *string {
 			if ent.Avatar != "" {
-				avatarURL := dto.Viper.GetString("app.url") + ent.Avatar
+				avatarURL := storage.URL(dto.Viper, ent.Avatar)
 				return &avatarURL
 			}
 			return nil
 		}(),
 		Ktp: func() *string {
 			if ent.Ktp != "" {
-				ktpURL := dto.Viper.GetString("app.url") + ent.Ktp
+				ktpURL := storage.URL(dto.Viper, ent.Ktp)
 				return &ktpURL
 			}
 			return nil
 		}(),
 		CurriculumVitae: func() *string {
 			if ent.CurriculumVitae != "" {
-				cvURL := dto.Viper.GetString("app.url") + ent.CurriculumVitae
+				cvURL := storage.URL(dto.Viper, ent.CurriculumVitae)
 				return &cvURL
 			}
 			return nil
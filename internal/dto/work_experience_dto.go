@@ -3,6 +3,7 @@ package dto
 import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -37,7 +38,7 @@ func (dto *WorkExperienceDTO) ConvertEntityToResponse(ent *entity.WorkExperience
 		JobDescription: ent.JobDescription,
 		Certificate: func() *string {
 			if ent.Certificate != "" {
-				certificateURL := dto.Viper.GetString("app.url") + ent.Certificate
+				certificateURL := storage.URL(dto.Viper, ent.Certificate)
 				return &certificateURL
 			}
 			return nil
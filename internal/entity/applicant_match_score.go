@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ApplicantMatchCriterion string
+
+const (
+	APPLICANT_MATCH_CRITERION_EDUCATION_LEVEL ApplicantMatchCriterion = "EDUCATION_LEVEL"
+	APPLICANT_MATCH_CRITERION_MAJOR           ApplicantMatchCriterion = "MAJOR"
+	APPLICANT_MATCH_CRITERION_EXPERIENCE      ApplicantMatchCriterion = "EXPERIENCE"
+	APPLICANT_MATCH_CRITERION_SKILLS          ApplicantMatchCriterion = "SKILLS"
+	APPLICANT_MATCH_CRITERION_AGE             ApplicantMatchCriterion = "AGE"
+	APPLICANT_MATCH_CRITERION_EXPECTED_SALARY ApplicantMatchCriterion = "EXPECTED_SALARY"
+)
+
+// ApplicantMatchScore is how well the profile of an applicant matches the requirements of the
+// job posting and its MP request, from 0 to 100. Score is the weighted average of the applicable
+// Items, so every point can be traced back to a requirement.
+type ApplicantMatchScore struct {
+	gorm.Model   `json:"-"`
+	ID           uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;"`
+	ApplicantID  uuid.UUID `json:"applicant_id" gorm:"type:char(36);not null;unique"`
+	JobPostingID uuid.UUID `json:"job_posting_id" gorm:"type:char(36);not null;index"`
+	Score        float64   `json:"score" gorm:"type:float;not null;index"`
+	ScoredAt     time.Time `json:"scored_at" gorm:"type:timestamp;not null"`
+
+	Items []ApplicantMatchScoreItem `json:"items" gorm:"foreignKey:ApplicantMatchScoreID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (s *ApplicantMatchScore) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *ApplicantMatchScore) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ApplicantMatchScore) TableName() string {
+	return "applicant_match_scores"
+}
+
+// ApplicantMatchScoreItem is one criterion of a match score. A criterion the job does not
+// require is kept with Applicable false and does not count towards the score.
+type ApplicantMatchScoreItem struct {
+	gorm.Model            `json:"-"`
+	ID                    uuid.UUID               `json:"id" gorm:"type:char(36);primaryKey;"`
+	ApplicantMatchScoreID uuid.UUID               `json:"applicant_match_score_id" gorm:"type:char(36);not null;index"`
+	Criterion             ApplicantMatchCriterion `json:"criterion" gorm:"type:varchar(50);not null"`
+	Weight                float64                 `json:"weight" gorm:"type:float;not null"`
+	Score                 float64                 `json:"score" gorm:"type:float;not null"`
+	Applicable            bool                    `json:"applicable" gorm:"not null;default:true"`
+	Requirement           string                  `json:"requirement" gorm:"type:text;default:null"`
+	CandidateValue        string                  `json:"candidate_value" gorm:"type:text;default:null"`
+	Note                  string                  `json:"note" gorm:"type:text;default:null"`
+}
+
+func (i *ApplicantMatchScoreItem) BeforeCreate(tx *gorm.DB) (err error) {
+	i.ID = uuid.New()
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+func (i *ApplicantMatchScoreItem) BeforeUpdate(tx *gorm.DB) (err error) {
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ApplicantMatchScoreItem) TableName() string {
+	return "applicant_match_score_items"
+}
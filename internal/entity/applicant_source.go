@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ApplicantSourceChannel string
+
+const (
+	APPLICANT_SOURCE_CHANNEL_CAREERS_SITE ApplicantSourceChannel = "CAREERS_SITE"
+	APPLICANT_SOURCE_CHANNEL_JOB_BOARD    ApplicantSourceChannel = "JOB_BOARD"
+	APPLICANT_SOURCE_CHANNEL_CAMPUS       ApplicantSourceChannel = "CAMPUS"
+	APPLICANT_SOURCE_CHANNEL_SOCIAL_MEDIA ApplicantSourceChannel = "SOCIAL_MEDIA"
+	APPLICANT_SOURCE_CHANNEL_REFERRAL     ApplicantSourceChannel = "REFERRAL"
+	APPLICANT_SOURCE_CHANNEL_OTHER        ApplicantSourceChannel = "OTHER"
+)
+
+// ApplicantSource is where an applicant came from. It is captured from the UTM parameters and
+// referral code of the application, or set by HR, in which case SetBy is the user who did.
+type ApplicantSource struct {
+	gorm.Model   `json:"-"`
+	ID           uuid.UUID              `json:"id" gorm:"type:char(36);primaryKey;"`
+	ApplicantID  uuid.UUID              `json:"applicant_id" gorm:"type:char(36);not null;unique"`
+	JobPostingID uuid.UUID              `json:"job_posting_id" gorm:"type:char(36);not null;index"`
+	Channel      ApplicantSourceChannel `json:"channel" gorm:"type:varchar(20);not null;index"`
+	UtmSource    string                 `json:"utm_source" gorm:"type:varchar(255);default:null"`
+	UtmMedium    string                 `json:"utm_medium" gorm:"type:varchar(255);default:null"`
+	UtmCampaign  string                 `json:"utm_campaign" gorm:"type:varchar(255);default:null"`
+	ReferralCode string                 `json:"referral_code" gorm:"type:varchar(100);default:null"`
+	Note         string                 `json:"note" gorm:"type:text;default:null"`
+	SetBy        *uuid.UUID             `json:"set_by" gorm:"type:char(36);default:null"`
+}
+
+func (s *ApplicantSource) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *ApplicantSource) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ApplicantSource) TableName() string {
+	return "applicant_sources"
+}
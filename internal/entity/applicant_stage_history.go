@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ApplicantStageHistory records an applicant entering a stage (the project recruitment line of
+// the same order) or getting a new status in it. A rejection keeps the order of the stage the
+// applicant was rejected at, the applicant itself is reset to order 0.
+type ApplicantStageHistory struct {
+	gorm.Model         `json:"-"`
+	ID                 uuid.UUID       `json:"id" gorm:"type:char(36);primaryKey;"`
+	ApplicantID        uuid.UUID       `json:"applicant_id" gorm:"type:char(36);not null;index"`
+	JobPostingID       uuid.UUID       `json:"job_posting_id" gorm:"type:char(36);not null;index"`
+	Order              int             `json:"order" gorm:"type:int;not null"`
+	TemplateQuestionID *uuid.UUID      `json:"template_question_id" gorm:"type:char(36);default:null"`
+	Status             ApplicantStatus `json:"status" gorm:"not null"`
+	EnteredAt          time.Time       `json:"entered_at" gorm:"not null"`
+}
+
+func (ash *ApplicantStageHistory) BeforeCreate(tx *gorm.DB) (err error) {
+	ash.ID = uuid.New()
+	ash.CreatedAt = time.Now()
+	ash.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ash *ApplicantStageHistory) BeforeUpdate(tx *gorm.DB) (err error) {
+	ash.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ApplicantStageHistory) TableName() string {
+	return "applicant_stage_histories"
+}
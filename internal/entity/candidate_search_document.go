@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// markers around the matched words and between the fragments of a headline, they can not
+// appear in profile text so they are replaced with markup after the text has been escaped
+const (
+	CANDIDATE_SEARCH_HIGHLIGHT_START    = "\x02"
+	CANDIDATE_SEARCH_HIGHLIGHT_STOP     = "\x03"
+	CANDIDATE_SEARCH_FRAGMENT_DELIMITER = "\x1f"
+)
+
+// CandidateSearchDocument is the full-text search index of a user profile. Content joins the
+// profile, educations, skills, work experiences and CV text, SearchVector is built from the
+// same parts with a weight per part and is kept in sync by the candidate search usecase.
+type CandidateSearchDocument struct {
+	UserProfileID uuid.UUID `json:"user_profile_id" gorm:"type:char(36);primaryKey"`
+	Content       string    `json:"content" gorm:"type:text;default:null"`
+	CvText        string    `json:"cv_text" gorm:"type:text;default:null"`
+	// the expected salary of the profile is encrypted, the band it falls in is kept here so
+	// it can be filtered on
+	ExpectedSalaryBand *int      `json:"-" gorm:"type:int;default:null;index"`
+	SearchVector       string    `json:"-" gorm:"type:tsvector;<-:false;index:idx_candidate_search_documents_search_vector,type:gin"`
+	IndexedAt          time.Time `json:"indexed_at" gorm:"type:timestamp;not null;index"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+
+	// filled by the search query only
+	Rank      float64 `json:"rank" gorm:"->;-:migration"`
+	Highlight string  `json:"highlight" gorm:"->;-:migration"`
+
+	UserProfile *UserProfile `json:"user_profile" gorm:"foreignKey:UserProfileID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (CandidateSearchDocument) TableName() string {
+	return "candidate_search_documents"
+}
@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CurriculumVitaeDraftStatus string
+
+const (
+	CURRICULUM_VITAE_DRAFT_STATUS_PENDING  CurriculumVitaeDraftStatus = "PENDING"
+	CURRICULUM_VITAE_DRAFT_STATUS_ACCEPTED CurriculumVitaeDraftStatus = "ACCEPTED"
+	CURRICULUM_VITAE_DRAFT_STATUS_REJECTED CurriculumVitaeDraftStatus = "REJECTED"
+)
+
+// CurriculumVitaeDraft is what the CV parser read from an uploaded CV. It stays a draft until
+// the candidate reviews and accepts it, only then it is merged into the user profile.
+type CurriculumVitaeDraft struct {
+	gorm.Model    `json:"-"`
+	ID            uuid.UUID                  `json:"id" gorm:"type:char(36);primaryKey;"`
+	UserID        uuid.UUID                  `json:"user_id" gorm:"type:char(36);not null;index"`
+	UserProfileID *uuid.UUID                 `json:"user_profile_id" gorm:"type:char(36);default:null"`
+	FilePath      string                     `json:"file_path" gorm:"type:text;not null"`
+	Status        CurriculumVitaeDraftStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING'"`
+	Name          string                     `json:"name" gorm:"type:varchar(255);default:null"`
+	Email         string                     `json:"email" gorm:"type:varchar(255);default:null"`
+	PhoneNumber   string                     `json:"phone_number" gorm:"type:varchar(255);default:null"`
+	Gender        UserGender                 `json:"gender" gorm:"type:varchar(255);default:null"`
+	BirthPlace    string                     `json:"birth_place" gorm:"type:varchar(255);default:null"`
+	BirthDate     *time.Time                 `json:"birth_date" gorm:"type:date;default:null"`
+	Address       string                     `json:"address" gorm:"type:text;default:null"`
+	RawText       string                     `json:"raw_text" gorm:"type:text;default:null"`
+	AcceptedAt    *time.Time                 `json:"accepted_at" gorm:"type:timestamp;default:null"`
+
+	Educations      []CurriculumVitaeDraftEducation      `json:"educations" gorm:"foreignKey:CurriculumVitaeDraftID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	WorkExperiences []CurriculumVitaeDraftWorkExperience `json:"work_experiences" gorm:"foreignKey:CurriculumVitaeDraftID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Skills          []CurriculumVitaeDraftSkill          `json:"skills" gorm:"foreignKey:CurriculumVitaeDraftID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (d *CurriculumVitaeDraft) BeforeCreate(tx *gorm.DB) (err error) {
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (d *CurriculumVitaeDraft) BeforeUpdate(tx *gorm.DB) (err error) {
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (CurriculumVitaeDraft) TableName() string {
+	return "curriculum_vitae_drafts"
+}
+
+// CurriculumVitaeDraftEducation is an education entry found in a CV. UniversityID is set when
+// the school name matched a university.
+type CurriculumVitaeDraftEducation struct {
+	gorm.Model             `json:"-"`
+	ID                     uuid.UUID          `json:"id" gorm:"type:char(36);primaryKey;"`
+	CurriculumVitaeDraftID uuid.UUID          `json:"curriculum_vitae_draft_id" gorm:"type:char(36);not null;index"`
+	EducationLevel         EducationLevelEnum `json:"education_level" gorm:"type:varchar(255);default:null"`
+	Major                  string             `json:"major" gorm:"type:varchar(255);default:null"`
+	SchoolName             string             `json:"school_name" gorm:"type:varchar(255);default:null"`
+	UniversityID           *uuid.UUID         `json:"university_id" gorm:"type:char(36);default:null"`
+	GraduateYear           int                `json:"graduate_year" gorm:"type:int;default:null"`
+	Gpa                    *float64           `json:"gpa" gorm:"type:float;default:null"`
+}
+
+func (e *CurriculumVitaeDraftEducation) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.New()
+	e.CreatedAt = time.Now()
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+func (e *CurriculumVitaeDraftEducation) BeforeUpdate(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+func (CurriculumVitaeDraftEducation) TableName() string {
+	return "curriculum_vitae_draft_educations"
+}
+
+type CurriculumVitaeDraftWorkExperience struct {
+	gorm.Model             `json:"-"`
+	ID                     uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;"`
+	CurriculumVitaeDraftID uuid.UUID `json:"curriculum_vitae_draft_id" gorm:"type:char(36);not null;index"`
+	Name                   string    `json:"name" gorm:"type:varchar(255);default:null"`
+	CompanyName            string    `json:"company_name" gorm:"type:varchar(255);default:null"`
+	YearExperience         int       `json:"year_experience" gorm:"type:int;default:null"`
+	JobDescription         string    `json:"job_description" gorm:"type:text;default:null"`
+}
+
+func (w *CurriculumVitaeDraftWorkExperience) BeforeCreate(tx *gorm.DB) (err error) {
+	w.ID = uuid.New()
+	w.CreatedAt = time.Now()
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+func (w *CurriculumVitaeDraftWorkExperience) BeforeUpdate(tx *gorm.DB) (err error) {
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+func (CurriculumVitaeDraftWorkExperience) TableName() string {
+	return "curriculum_vitae_draft_work_experiences"
+}
+
+type CurriculumVitaeDraftSkill struct {
+	gorm.Model             `json:"-"`
+	ID                     uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;"`
+	CurriculumVitaeDraftID uuid.UUID `json:"curriculum_vitae_draft_id" gorm:"type:char(36);not null;index"`
+	Name                   string    `json:"name" gorm:"type:varchar(255);not null"`
+	Description            string    `json:"description" gorm:"type:text;default:null"`
+}
+
+func (s *CurriculumVitaeDraftSkill) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *CurriculumVitaeDraftSkill) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (CurriculumVitaeDraftSkill) TableName() string {
+	return "curriculum_vitae_draft_skills"
+}
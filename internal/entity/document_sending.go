@@ -46,11 +46,11 @@ type DocumentSending struct {
 	DocumentNumber           string                 `json:"document_number" gorm:"type:varchar(255);not null;unique"`
 	Status                   DocumentSendingStatus  `json:"status" gorm:"default:'PENDING'"`
 	RecruitmentType          ProjectRecruitmentType `json:"recruitment_type" gorm:"default:null"`
-	BasicWage                float64                `json:"basic_wage" gorm:"type:decimal(10,2);default:null"`
-	PositionalAllowance      float64                `json:"positional_allowance" gorm:"type:decimal(10,2);default:null"`
-	OperationalAllowance     float64                `json:"operational_allowance" gorm:"type:decimal(10,2);default:null"`
-	MealAllowance            float64                `json:"meal_allowance" gorm:"type:decimal(10,2);default:null"`
-	HouseAllowance           float64                `json:"house_allowance" gorm:"type:decimal(10,2);default:null"`
+	BasicWage                float64                `json:"basic_wage" gorm:"type:text;default:null;serializer:encrypted"`
+	PositionalAllowance      float64                `json:"positional_allowance" gorm:"type:text;default:null;serializer:encrypted"`
+	OperationalAllowance     float64                `json:"operational_allowance" gorm:"type:text;default:null;serializer:encrypted"`
+	MealAllowance            float64                `json:"meal_allowance" gorm:"type:text;default:null;serializer:encrypted"`
+	HouseAllowance           float64                `json:"house_allowance" gorm:"type:text;default:null;serializer:encrypted"`
 	JobLocation              string                 `json:"job_location" gorm:"type:text;default:null"`
 	HometripTicket           string                 `json:"hometrip_ticket" gorm:"type:text;default:null"`
 	PeriodAgreement          string                 `json:"period_agreement" gorm:"type:text;default:null"`
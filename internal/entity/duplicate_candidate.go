@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DuplicateCandidateStatus string
+
+const (
+	DUPLICATE_CANDIDATE_STATUS_OPEN      DuplicateCandidateStatus = "OPEN"
+	DUPLICATE_CANDIDATE_STATUS_MERGED    DuplicateCandidateStatus = "MERGED"
+	DUPLICATE_CANDIDATE_STATUS_DISMISSED DuplicateCandidateStatus = "DISMISSED"
+)
+
+// DuplicateCandidate is a pair of profiles that probably belong to the same person. The pair
+// is stored once, with UserProfileID the smaller of the two IDs, and the Match fields tell
+// which details they share. HR either merges the pair or dismisses it, a dismissed pair is not
+// reported again.
+type DuplicateCandidate struct {
+	gorm.Model         `json:"-"`
+	ID                 uuid.UUID                `json:"id" gorm:"type:char(36);primaryKey;"`
+	UserProfileID      uuid.UUID                `json:"user_profile_id" gorm:"type:char(36);not null;uniqueIndex:idx_duplicate_candidates_pair"`
+	DuplicateProfileID uuid.UUID                `json:"duplicate_profile_id" gorm:"type:char(36);not null;uniqueIndex:idx_duplicate_candidates_pair"`
+	Status             DuplicateCandidateStatus `json:"status" gorm:"type:varchar(20);not null;default:'OPEN';index"`
+	Score              int                      `json:"score" gorm:"type:int;not null"`
+	MatchKtpNumber     bool                     `json:"match_ktp_number" gorm:"not null;default:false"`
+	MatchPhoneNumber   bool                     `json:"match_phone_number" gorm:"not null;default:false"`
+	MatchEmail         bool                     `json:"match_email" gorm:"not null;default:false"`
+	MatchNameBirthDate bool                     `json:"match_name_birth_date" gorm:"not null;default:false"`
+	DetectedAt         time.Time                `json:"detected_at" gorm:"type:timestamp;not null"`
+	ResolvedAt         *time.Time               `json:"resolved_at" gorm:"type:timestamp;default:null"`
+	ResolvedBy         *uuid.UUID               `json:"resolved_by" gorm:"type:char(36);default:null"`
+
+	UserProfile      *UserProfile `json:"user_profile" gorm:"foreignKey:UserProfileID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	DuplicateProfile *UserProfile `json:"duplicate_profile" gorm:"foreignKey:DuplicateProfileID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (d *DuplicateCandidate) BeforeCreate(tx *gorm.DB) (err error) {
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (d *DuplicateCandidate) BeforeUpdate(tx *gorm.DB) (err error) {
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+func (DuplicateCandidate) TableName() string {
+	return "duplicate_candidates"
+}
+
+// UserProfileMerge is the audit record of a merge: which profile survived, which one was
+// merged into it, who did it and how many records were moved. Applications of the merged
+// profile to a job the surviving profile had applied to as well are dropped, not moved.
+type UserProfileMerge struct {
+	gorm.Model             `json:"-"`
+	ID                     uuid.UUID  `json:"id" gorm:"type:char(36);primaryKey;"`
+	DuplicateCandidateID   *uuid.UUID `json:"duplicate_candidate_id" gorm:"type:char(36);default:null;index"`
+	SurvivingProfileID     uuid.UUID  `json:"surviving_profile_id" gorm:"type:char(36);not null;index"`
+	MergedProfileID        uuid.UUID  `json:"merged_profile_id" gorm:"type:char(36);not null;index"`
+	MergedUserID           *uuid.UUID `json:"merged_user_id" gorm:"type:char(36);default:null"`
+	MergedBy               uuid.UUID  `json:"merged_by" gorm:"type:char(36);not null"`
+	MergedAt               time.Time  `json:"merged_at" gorm:"type:timestamp;not null"`
+	MovedApplicants        int        `json:"moved_applicants" gorm:"type:int;not null;default:0"`
+	DroppedApplicants      int        `json:"dropped_applicants" gorm:"type:int;not null;default:0"`
+	MovedEducations        int        `json:"moved_educations" gorm:"type:int;not null;default:0"`
+	MovedWorkExperiences   int        `json:"moved_work_experiences" gorm:"type:int;not null;default:0"`
+	MovedSkills            int        `json:"moved_skills" gorm:"type:int;not null;default:0"`
+	MovedQuestionResponses int        `json:"moved_question_responses" gorm:"type:int;not null;default:0"`
+	Note                   string     `json:"note" gorm:"type:text;default:null"`
+}
+
+func (m *UserProfileMerge) BeforeCreate(tx *gorm.DB) (err error) {
+	m.ID = uuid.New()
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *UserProfileMerge) BeforeUpdate(tx *gorm.DB) (err error) {
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+func (UserProfileMerge) TableName() string {
+	return "user_profile_merges"
+}
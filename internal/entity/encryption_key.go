@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EncryptionKey is a data key used for field-level encryption. The key itself is stored wrapped
+// by one of the master keys from config, MasterKeyID tells which one.
+type EncryptionKey struct {
+	gorm.Model  `json:"-"`
+	ID          uuid.UUID  `json:"id" gorm:"type:char(36);primaryKey;"`
+	MasterKeyID string     `json:"master_key_id" gorm:"type:varchar(255);not null"`
+	WrappedKey  string     `json:"-" gorm:"type:text;not null"`
+	Active      bool       `json:"active" gorm:"default:false;index"`
+	RetiredAt   *time.Time `json:"retired_at" gorm:"type:timestamp;default:null"`
+}
+
+func (k *EncryptionKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	k.CreatedAt = time.Now()
+	k.UpdatedAt = time.Now()
+	return nil
+}
+
+func (k *EncryptionKey) BeforeUpdate(tx *gorm.DB) (err error) {
+	k.UpdatedAt = time.Now()
+	return nil
+}
+
+func (EncryptionKey) TableName() string {
+	return "encryption_keys"
+}
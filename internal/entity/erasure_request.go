@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ErasureRequestStatus string
+
+const (
+	ERASURE_REQUEST_STATUS_PENDING   ErasureRequestStatus = "PENDING"
+	ERASURE_REQUEST_STATUS_REJECTED  ErasureRequestStatus = "REJECTED"
+	ERASURE_REQUEST_STATUS_COMPLETED ErasureRequestStatus = "COMPLETED"
+)
+
+// ErasureRequest is a candidate asking for their personal data to be erased. HR reviews it,
+// a request can be rejected when the data has to be kept, e.g. for a hired candidate, and
+// completing it anonymises the profile. Files of the profile that could not be removed afterwards
+// are listed in FailedFiles, one per line, so they can still be removed from the storage by hand.
+type ErasureRequest struct {
+	gorm.Model    `json:"-"`
+	ID            uuid.UUID            `json:"id" gorm:"type:char(36);primaryKey;"`
+	UserProfileID uuid.UUID            `json:"user_profile_id" gorm:"type:char(36);not null;index"`
+	Status        ErasureRequestStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	Reason        string               `json:"reason" gorm:"type:text;default:null"`
+	RequestedAt   time.Time            `json:"requested_at" gorm:"type:timestamp;not null"`
+	ReviewedBy    *uuid.UUID           `json:"reviewed_by" gorm:"type:char(36);default:null"`
+	ReviewedAt    *time.Time           `json:"reviewed_at" gorm:"type:timestamp;default:null"`
+	ReviewNote    string               `json:"review_note" gorm:"type:text;default:null"`
+	FailedFiles   string               `json:"failed_files" gorm:"type:text;default:null"`
+
+	UserProfile *UserProfile `json:"user_profile" gorm:"foreignKey:UserProfileID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (er *ErasureRequest) BeforeCreate(tx *gorm.DB) (err error) {
+	er.ID = uuid.New()
+	er.CreatedAt = time.Now()
+	er.UpdatedAt = time.Now()
+	return nil
+}
+
+func (er *ErasureRequest) BeforeUpdate(tx *gorm.DB) (err error) {
+	er.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ErasureRequest) TableName() string {
+	return "erasure_requests"
+}
@@ -42,6 +42,7 @@ type JobPosting struct {
 	MinimumWorkExperience      string                 `json:"minimum_work_experience" gorm:"type:varchar(255);default:null"`
 	Name                       string                 `json:"name" gorm:"type:text;default:null"`
 	IsShow                     string                 `json:"is_show" gorm:"type:varchar(255);default:YES"`
+	Slug                       string                 `json:"slug" gorm:"type:varchar(255);uniqueIndex;default:null"`
 
 	ProjectRecruitmentHeader *ProjectRecruitmentHeader `json:"project_recruitment_header" gorm:"foreignKey:ProjectRecruitmentHeaderID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	MPRequest                *MPRequest                `json:"mp_request" gorm:"foreignKey:MPRequestID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MidsuitSyncStatus string
+
+const (
+	MIDSUIT_SYNC_STATUS_PENDING   MidsuitSyncStatus = "PENDING"
+	MIDSUIT_SYNC_STATUS_RUNNING   MidsuitSyncStatus = "RUNNING"
+	MIDSUIT_SYNC_STATUS_FAILED    MidsuitSyncStatus = "FAILED"
+	MIDSUIT_SYNC_STATUS_COMPLETED MidsuitSyncStatus = "COMPLETED"
+)
+
+// MidsuitSyncJob tracks the synchronisation of one hired applicant to Midsuit.
+// There is at most one job per DocumentSending, guarded by IdempotencyKey.
+type MidsuitSyncJob struct {
+	gorm.Model        `json:"-"`
+	ID                uuid.UUID         `json:"id" gorm:"type:char(36);primaryKey;"`
+	DocumentSendingID uuid.UUID         `json:"document_sending_id" gorm:"type:char(36);not null;unique"`
+	ApplicantID       uuid.UUID         `json:"applicant_id" gorm:"type:char(36);not null"`
+	EmployeeID        *uuid.UUID        `json:"employee_id" gorm:"type:char(36);default:null"`
+	IdempotencyKey    string            `json:"idempotency_key" gorm:"type:varchar(255);not null;unique"`
+	Status            MidsuitSyncStatus `json:"status" gorm:"type:varchar(50);default:'PENDING'"`
+	Attempts          int               `json:"attempts" gorm:"type:int;default:0"`
+	MaxAttempts       int               `json:"max_attempts" gorm:"type:int;default:5"`
+	NextRunAt         *time.Time        `json:"next_run_at" gorm:"type:timestamp;default:null"`
+	StartedAt         *time.Time        `json:"started_at" gorm:"type:timestamp;default:null"`
+	FinishedAt        *time.Time        `json:"finished_at" gorm:"type:timestamp;default:null"`
+	LastError         string            `json:"last_error" gorm:"type:text;default:null"`
+	MidsuitEmployeeID string            `json:"midsuit_employee_id" gorm:"type:varchar(255);default:null"`
+
+	DocumentSending *DocumentSending  `json:"document_sending" gorm:"foreignKey:DocumentSendingID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Steps           []MidsuitSyncStep `json:"steps" gorm:"foreignKey:MidsuitSyncJobID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (j *MidsuitSyncJob) BeforeCreate(tx *gorm.DB) (err error) {
+	j.ID = uuid.New()
+	j.CreatedAt = time.Now()
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+func (j *MidsuitSyncJob) BeforeUpdate(tx *gorm.DB) (err error) {
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+func (MidsuitSyncJob) TableName() string {
+	return "midsuit_sync_jobs"
+}
+
+// MidsuitSyncStep is one remote call of a MidsuitSyncJob. A completed step is
+// never sent again, which keeps reruns from creating duplicate Midsuit records.
+type MidsuitSyncStep struct {
+	gorm.Model       `json:"-"`
+	ID               uuid.UUID         `json:"id" gorm:"type:char(36);primaryKey;"`
+	MidsuitSyncJobID uuid.UUID         `json:"midsuit_sync_job_id" gorm:"type:char(36);not null"`
+	Name             string            `json:"name" gorm:"type:varchar(255);not null"`
+	Order            int               `json:"order" gorm:"type:int;default:0"`
+	IdempotencyKey   string            `json:"idempotency_key" gorm:"type:varchar(255);not null;unique"`
+	Status           MidsuitSyncStatus `json:"status" gorm:"type:varchar(50);default:'PENDING'"`
+	Attempts         int               `json:"attempts" gorm:"type:int;default:0"`
+	LastError        string            `json:"last_error" gorm:"type:text;default:null"`
+	MidsuitRecordID  string            `json:"midsuit_record_id" gorm:"type:varchar(255);default:null"`
+	CompletedAt      *time.Time        `json:"completed_at" gorm:"type:timestamp;default:null"`
+
+	MidsuitSyncJob *MidsuitSyncJob `json:"midsuit_sync_job" gorm:"foreignKey:MidsuitSyncJobID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (s *MidsuitSyncStep) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *MidsuitSyncStep) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (MidsuitSyncStep) TableName() string {
+	return "midsuit_sync_steps"
+}
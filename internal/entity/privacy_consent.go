@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PrivacyConsent records that a candidate agreed to a version of the privacy notice, with the
+// address and browser the agreement came from
+type PrivacyConsent struct {
+	gorm.Model      `json:"-"`
+	ID              uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;"`
+	UserProfileID   uuid.UUID `json:"user_profile_id" gorm:"type:char(36);not null;index"`
+	PrivacyNoticeID uuid.UUID `json:"privacy_notice_id" gorm:"type:char(36);not null"`
+	NoticeVersion   string    `json:"notice_version" gorm:"type:varchar(50);not null"`
+	ConsentedAt     time.Time `json:"consented_at" gorm:"type:timestamp;not null"`
+	IPAddress       string    `json:"ip_address" gorm:"type:varchar(64);default:null"`
+	UserAgent       string    `json:"user_agent" gorm:"type:text;default:null"`
+
+	UserProfile   *UserProfile   `json:"user_profile" gorm:"foreignKey:UserProfileID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PrivacyNotice *PrivacyNotice `json:"privacy_notice" gorm:"foreignKey:PrivacyNoticeID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+}
+
+func (pc *PrivacyConsent) BeforeCreate(tx *gorm.DB) (err error) {
+	pc.ID = uuid.New()
+	pc.CreatedAt = time.Now()
+	pc.UpdatedAt = time.Now()
+	return nil
+}
+
+func (pc *PrivacyConsent) BeforeUpdate(tx *gorm.DB) (err error) {
+	pc.UpdatedAt = time.Now()
+	return nil
+}
+
+func (PrivacyConsent) TableName() string {
+	return "privacy_consents"
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PrivacyNotice is one version of the privacy notice candidates agree to. The notice with the
+// latest PublishedAt is the current one, a published notice is never edited, a change is
+// published as a new version.
+type PrivacyNotice struct {
+	gorm.Model  `json:"-"`
+	ID          uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;"`
+	Version     string    `json:"version" gorm:"type:varchar(50);not null;uniqueIndex"`
+	Title       string    `json:"title" gorm:"type:varchar(255);not null"`
+	Content     string    `json:"content" gorm:"type:text;not null"`
+	PublishedAt time.Time `json:"published_at" gorm:"type:timestamp;not null;index"`
+	PublishedBy uuid.UUID `json:"published_by" gorm:"type:char(36);not null"`
+}
+
+func (pn *PrivacyNotice) BeforeCreate(tx *gorm.DB) (err error) {
+	pn.ID = uuid.New()
+	pn.CreatedAt = time.Now()
+	pn.UpdatedAt = time.Now()
+	return nil
+}
+
+func (pn *PrivacyNotice) BeforeUpdate(tx *gorm.DB) (err error) {
+	pn.UpdatedAt = time.Now()
+	return nil
+}
+
+func (PrivacyNotice) TableName() string {
+	return "privacy_notices"
+}
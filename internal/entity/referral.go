@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReferralLink is the link an employee shares to refer candidates to a job posting. Candidates
+// applying through it carry its Code as their referral code. UserID is the account of the
+// employee, which tells a referral of the employee themselves apart.
+type ReferralLink struct {
+	gorm.Model   `json:"-"`
+	ID           uuid.UUID   `json:"id" gorm:"type:char(36);primaryKey;"`
+	EmployeeID   uuid.UUID   `json:"employee_id" gorm:"type:char(36);not null;uniqueIndex:idx_referral_links_employee_job_posting"`
+	UserID       uuid.UUID   `json:"user_id" gorm:"type:char(36);not null"`
+	JobPostingID uuid.UUID   `json:"job_posting_id" gorm:"type:char(36);not null;uniqueIndex:idx_referral_links_employee_job_posting"`
+	Code         string      `json:"code" gorm:"type:varchar(100);not null;uniqueIndex"`
+	JobPosting   *JobPosting `json:"job_posting" gorm:"foreignKey:JobPostingID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (l *ReferralLink) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.New()
+	l.CreatedAt = time.Now()
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+func (l *ReferralLink) BeforeUpdate(tx *gorm.DB) (err error) {
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ReferralLink) TableName() string {
+	return "referral_links"
+}
+
+type ReferralStatus string
+
+const (
+	// the candidate has no profile yet, the referral is linked once they apply
+	REFERRAL_STATUS_SUBMITTED ReferralStatus = "SUBMITTED"
+	// the candidate has a profile but has not applied yet
+	REFERRAL_STATUS_LINKED  ReferralStatus = "LINKED"
+	REFERRAL_STATUS_APPLIED ReferralStatus = "APPLIED"
+)
+
+// Referral is a candidate referred by an employee to a job posting, either submitted by the
+// employee or recorded when the candidate applied through the employee's referral link
+type Referral struct {
+	gorm.Model         `json:"-"`
+	ID                 uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey;"`
+	ReferralLinkID     uuid.UUID      `json:"referral_link_id" gorm:"type:char(36);not null;index"`
+	ReferrerEmployeeID uuid.UUID      `json:"referrer_employee_id" gorm:"type:char(36);not null;index"`
+	JobPostingID       uuid.UUID      `json:"job_posting_id" gorm:"type:char(36);not null;index"`
+	CandidateName      string         `json:"candidate_name" gorm:"type:varchar(255);not null"`
+	CandidateEmail     string         `json:"candidate_email" gorm:"type:varchar(255);not null;index"`
+	CandidatePhone     string         `json:"candidate_phone" gorm:"type:varchar(255);default:null"`
+	Note               string         `json:"note" gorm:"type:text;default:null"`
+	UserProfileID      *uuid.UUID     `json:"user_profile_id" gorm:"type:char(36);default:null;index"`
+	ApplicantID        *uuid.UUID     `json:"applicant_id" gorm:"type:char(36);default:null;unique"`
+	Status             ReferralStatus `json:"status" gorm:"type:varchar(20);not null;default:'SUBMITTED'"`
+	ReferralLink       *ReferralLink  `json:"referral_link" gorm:"foreignKey:ReferralLinkID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	JobPosting         *JobPosting    `json:"job_posting" gorm:"foreignKey:JobPostingID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (r *Referral) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *Referral) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (Referral) TableName() string {
+	return "referrals"
+}
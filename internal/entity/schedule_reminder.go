@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleReminderScheduleType string
+
+const (
+	SCHEDULE_REMINDER_SCHEDULE_TYPE_TEST      ScheduleReminderScheduleType = "TEST"
+	SCHEDULE_REMINDER_SCHEDULE_TYPE_INTERVIEW ScheduleReminderScheduleType = "INTERVIEW"
+	SCHEDULE_REMINDER_SCHEDULE_TYPE_FGD       ScheduleReminderScheduleType = "FGD"
+)
+
+type ScheduleReminderRecipientType string
+
+const (
+	SCHEDULE_REMINDER_RECIPIENT_TYPE_APPLICANT ScheduleReminderRecipientType = "APPLICANT"
+	SCHEDULE_REMINDER_RECIPIENT_TYPE_ASSESSOR  ScheduleReminderRecipientType = "ASSESSOR"
+)
+
+type ScheduleReminderChannel string
+
+const (
+	SCHEDULE_REMINDER_CHANNEL_EMAIL        ScheduleReminderChannel = "EMAIL"
+	SCHEDULE_REMINDER_CHANNEL_NOTIFICATION ScheduleReminderChannel = "NOTIFICATION"
+)
+
+type ScheduleReminderStatus string
+
+const (
+	SCHEDULE_REMINDER_STATUS_PENDING   ScheduleReminderStatus = "PENDING"
+	SCHEDULE_REMINDER_STATUS_SENT      ScheduleReminderStatus = "SENT"
+	SCHEDULE_REMINDER_STATUS_FAILED    ScheduleReminderStatus = "FAILED"
+	SCHEDULE_REMINDER_STATUS_CANCELLED ScheduleReminderStatus = "CANCELLED"
+)
+
+// ScheduleReminder is one reminder of one slot, for one recipient on one channel. DedupKey is
+// unique, so a reminder is never planned or sent twice for the same slot.
+type ScheduleReminder struct {
+	gorm.Model    `json:"-"`
+	ID            uuid.UUID                     `json:"id" gorm:"type:char(36);primaryKey;"`
+	DedupKey      string                        `json:"dedup_key" gorm:"type:varchar(255);not null;uniqueIndex"`
+	ScheduleType  ScheduleReminderScheduleType  `json:"schedule_type" gorm:"type:varchar(20);not null"`
+	ScheduleID    uuid.UUID                     `json:"schedule_id" gorm:"type:char(36);not null;index"`
+	RecipientType ScheduleReminderRecipientType `json:"recipient_type" gorm:"type:varchar(20);not null"`
+	RecipientID   uuid.UUID                     `json:"recipient_id" gorm:"type:char(36);not null"`
+	Channel       ScheduleReminderChannel       `json:"channel" gorm:"type:varchar(20);not null"`
+	OffsetMinutes int                           `json:"offset_minutes" gorm:"type:int;not null"`
+	SlotAt        time.Time                     `json:"slot_at" gorm:"type:timestamp;not null"`
+	RemindAt      time.Time                     `json:"remind_at" gorm:"type:timestamp;not null;index"`
+	Status        ScheduleReminderStatus        `json:"status" gorm:"type:varchar(20);not null;default:'PENDING'"`
+	Attempts      int                           `json:"attempts" gorm:"type:int;default:0"`
+	SentAt        *time.Time                    `json:"sent_at" gorm:"type:timestamp;default:null"`
+	LastError     string                        `json:"last_error" gorm:"type:text;default:null"`
+}
+
+func (r *ScheduleReminder) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *ScheduleReminder) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ScheduleReminder) TableName() string {
+	return "schedule_reminders"
+}
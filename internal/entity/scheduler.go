@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SchedulerLock is held by the replica that runs the scheduled jobs. The owner renews it on
+// every tick, another replica takes it over once ExpiresAt has passed.
+type SchedulerLock struct {
+	Name      string    `json:"name" gorm:"type:varchar(100);primaryKey"`
+	Owner     string    `json:"owner" gorm:"type:varchar(255);not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"type:timestamp;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (SchedulerLock) TableName() string {
+	return "scheduler_locks"
+}
+
+type SchedulerJobRunStatus string
+
+const (
+	SCHEDULER_JOB_RUN_STATUS_RUNNING   SchedulerJobRunStatus = "RUNNING"
+	SCHEDULER_JOB_RUN_STATUS_SUCCEEDED SchedulerJobRunStatus = "SUCCEEDED"
+	SCHEDULER_JOB_RUN_STATUS_FAILED    SchedulerJobRunStatus = "FAILED"
+)
+
+type SchedulerJobRunTrigger string
+
+const (
+	SCHEDULER_JOB_RUN_TRIGGER_SCHEDULE SchedulerJobRunTrigger = "SCHEDULE"
+	SCHEDULER_JOB_RUN_TRIGGER_MANUAL   SchedulerJobRunTrigger = "MANUAL"
+)
+
+// SchedulerJobRun is one execution of a scheduled job
+type SchedulerJobRun struct {
+	gorm.Model `json:"-"`
+	ID         uuid.UUID              `json:"id" gorm:"type:char(36);primaryKey;"`
+	JobName    string                 `json:"job_name" gorm:"type:varchar(100);not null;index"`
+	Trigger    SchedulerJobRunTrigger `json:"trigger" gorm:"type:varchar(20);not null"`
+	Owner      string                 `json:"owner" gorm:"type:varchar(255);not null"`
+	Status     SchedulerJobRunStatus  `json:"status" gorm:"type:varchar(20);not null"`
+	StartedAt  time.Time              `json:"started_at" gorm:"type:timestamp;not null"`
+	FinishedAt *time.Time             `json:"finished_at" gorm:"type:timestamp;default:null"`
+	Result     string                 `json:"result" gorm:"type:text;default:null"`
+	Error      string                 `json:"error" gorm:"type:text;default:null"`
+}
+
+func (r *SchedulerJobRun) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *SchedulerJobRun) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (SchedulerJobRun) TableName() string {
+	return "scheduler_job_runs"
+}
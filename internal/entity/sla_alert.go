@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SlaAlertType string
+
+const (
+	SLA_ALERT_TYPE_STAGE  SlaAlertType = "STAGE"
+	SLA_ALERT_TYPE_RESULT SlaAlertType = "RESULT"
+)
+
+type SlaAlertLevel string
+
+const (
+	SLA_ALERT_LEVEL_PIC        SlaAlertLevel = "PIC"
+	SLA_ALERT_LEVEL_ASSESSOR   SlaAlertLevel = "ASSESSOR"
+	SLA_ALERT_LEVEL_ESCALATION SlaAlertLevel = "ESCALATION"
+)
+
+type SlaAlertStatus string
+
+const (
+	SLA_ALERT_STATUS_SENT   SlaAlertStatus = "SENT"
+	SLA_ALERT_STATUS_FAILED SlaAlertStatus = "FAILED"
+)
+
+// SlaAlert is the alert of one overdue item to one employee. DedupKey is unique, so an overdue
+// item is alerted once per recipient and level however many times the SLA job runs.
+type SlaAlert struct {
+	gorm.Model  `json:"-"`
+	ID          uuid.UUID      `json:"id" gorm:"type:char(36);primaryKey;"`
+	DedupKey    string         `json:"dedup_key" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Type        SlaAlertType   `json:"type" gorm:"type:varchar(20);not null"`
+	Level       SlaAlertLevel  `json:"level" gorm:"type:varchar(20);not null"`
+	ReferenceID uuid.UUID      `json:"reference_id" gorm:"type:char(36);not null;index"`
+	EmployeeID  uuid.UUID      `json:"employee_id" gorm:"type:char(36);not null;index"`
+	DueAt       time.Time      `json:"due_at" gorm:"type:timestamp;not null"`
+	Status      SlaAlertStatus `json:"status" gorm:"type:varchar(20);not null"`
+	Attempts    int            `json:"attempts" gorm:"type:int;default:0"`
+	SentAt      *time.Time     `json:"sent_at" gorm:"type:timestamp;default:null"`
+	LastError   string         `json:"last_error" gorm:"type:text;default:null"`
+}
+
+func (a *SlaAlert) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = uuid.New()
+	a.CreatedAt = time.Now()
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+func (a *SlaAlert) BeforeUpdate(tx *gorm.DB) (err error) {
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+func (SlaAlert) TableName() string {
+	return "sla_alerts"
+}
@@ -14,15 +14,19 @@ const (
 	TEMPLATE_ACTIVITY_LINE_STATUS_INACTIVE TemplateActivityLineStatus = "INACTIVE"
 )
 
+// TemplateActivityLine is one stage of a template activity. MaxDaysInStage and MaxDaysToFillResult
+// are its SLAs, 0 means the stage has none.
 type TemplateActivityLine struct {
-	gorm.Model         `json:"-"`
-	ID                 uuid.UUID                  `json:"id" gorm:"type:char(36);primaryKey;"`
-	TemplateActivityID uuid.UUID                  `json:"template_activity_id" gorm:"type:char(36);not null"`
-	Name               string                     `json:"name" gorm:"type:varchar(255);not null"`
-	Description        string                     `json:"description" gorm:"type:text;default:null"`
-	Status             TemplateActivityLineStatus `json:"status" gorm:"default:'ACTIVE'"`
-	QuestionTemplateID uuid.UUID                  `json:"question_template_id" gorm:"type:char(36);not null"`
-	ColorHexCode       string                     `json:"color_hex_code" gorm:"type:varchar(10);default:null"`
+	gorm.Model          `json:"-"`
+	ID                  uuid.UUID                  `json:"id" gorm:"type:char(36);primaryKey;"`
+	TemplateActivityID  uuid.UUID                  `json:"template_activity_id" gorm:"type:char(36);not null"`
+	Name                string                     `json:"name" gorm:"type:varchar(255);not null"`
+	Description         string                     `json:"description" gorm:"type:text;default:null"`
+	Status              TemplateActivityLineStatus `json:"status" gorm:"default:'ACTIVE'"`
+	QuestionTemplateID  uuid.UUID                  `json:"question_template_id" gorm:"type:char(36);not null"`
+	ColorHexCode        string                     `json:"color_hex_code" gorm:"type:varchar(10);default:null"`
+	MaxDaysInStage      int                        `json:"max_days_in_stage" gorm:"type:int;default:0"`
+	MaxDaysToFillResult int                        `json:"max_days_to_fill_result" gorm:"type:int;default:0"`
 
 	TemplateActivity        *TemplateActivity        `json:"template_activity" gorm:"foreignKey:TemplateActivityID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	TemplateQuestion        *TemplateQuestion        `json:"template_question" gorm:"foreignKey:QuestionTemplateID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
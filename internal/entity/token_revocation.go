@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TokenRevocationKind string
+
+const (
+	// a single token, identified by its jti or by the hash of the raw token
+	TOKEN_REVOCATION_KIND_TOKEN TokenRevocationKind = "TOKEN"
+	// every token of a user issued before RevokedAt
+	TOKEN_REVOCATION_KIND_USER TokenRevocationKind = "USER"
+)
+
+// TokenRevocation rejects tokens before they expire. Rows can be purged once ExpiresAt has passed.
+type TokenRevocation struct {
+	gorm.Model `json:"-"`
+	ID         uuid.UUID           `json:"id" gorm:"type:char(36);primaryKey;"`
+	Kind       TokenRevocationKind `json:"kind" gorm:"type:varchar(20);not null"`
+	TokenID    string              `json:"token_id" gorm:"type:varchar(255);index;default:null"`
+	UserID     *uuid.UUID          `json:"user_id" gorm:"type:char(36);index;default:null"`
+	RevokedAt  time.Time           `json:"revoked_at" gorm:"type:timestamp;not null"`
+	ExpiresAt  time.Time           `json:"expires_at" gorm:"type:timestamp;not null"`
+	Reason     string              `json:"reason" gorm:"type:text;default:null"`
+}
+
+func (r *TokenRevocation) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *TokenRevocation) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (TokenRevocation) TableName() string {
+	return "token_revocations"
+}
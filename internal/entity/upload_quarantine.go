@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UploadQuarantine is an upload the malware scanner rejected. The file is kept under
+// storage/quarantine/ for review and is never linked to a record.
+type UploadQuarantine struct {
+	gorm.Model   `json:"-"`
+	ID           uuid.UUID `json:"id" gorm:"type:char(36);primaryKey;"`
+	Purpose      string    `json:"purpose" gorm:"type:varchar(50);not null;index"`
+	OriginalName string    `json:"original_name" gorm:"type:text;not null"`
+	Path         string    `json:"path" gorm:"type:text;not null"`
+	ContentType  string    `json:"content_type" gorm:"type:varchar(255);default:null"`
+	Size         int64     `json:"size" gorm:"type:bigint;not null;default:0"`
+	Scanner      string    `json:"scanner" gorm:"type:varchar(50);not null"`
+	Signature    string    `json:"signature" gorm:"type:text;default:null"`
+}
+
+func (uq *UploadQuarantine) BeforeCreate(tx *gorm.DB) (err error) {
+	uq.ID = uuid.New()
+	uq.CreatedAt = time.Now()
+	uq.UpdatedAt = time.Now()
+	return nil
+}
+
+func (uq *UploadQuarantine) BeforeUpdate(tx *gorm.DB) (err error) {
+	uq.UpdatedAt = time.Now()
+	return nil
+}
+
+func (UploadQuarantine) TableName() string {
+	return "upload_quarantines"
+}
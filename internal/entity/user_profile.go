@@ -31,6 +31,9 @@ const (
 	FEMALE UserGender = "FEMALE"
 )
 
+// ANONYMISED_USER_PROFILE_NAME replaces the name of a profile whose personal data was erased
+const ANONYMISED_USER_PROFILE_NAME = "Anonymised candidate"
+
 type UserProfile struct {
 	gorm.Model      `json:"-"`
 	ID              uuid.UUID         `json:"id" gorm:"type:char(36);primaryKey;"`
@@ -45,13 +48,17 @@ type UserProfile struct {
 	BirthPlace      string            `json:"birth_place" gorm:"type:varchar(255);default:null"`
 	Address         string            `json:"address" gorm:"type:text;default:null"`
 	Ktp             string            `json:"ktp" gorm:"type:varchar(255);default:null"`
+	KtpNumber       string            `json:"ktp_number" gorm:"type:text;default:null;serializer:encrypted"`
+	KtpNumberBidx   string            `json:"-" gorm:"column:ktp_number_bidx;type:varchar(64);default:null;index"`
+	Email           string            `json:"email" gorm:"type:varchar(255);default:null;index"`
 	CurriculumVitae string            `json:"curriculum_vitae" gorm:"type:text;default:null"`
 	Avatar          string            `json:"avatar" gorm:"type:text;default:null"`
 	Bilingual       string            `json:"bilingual" gorm:"type:varchar(255);default:null"`
-	ExpectedSalary  int               `json:"expected_salary" gorm:"type:int;default:null"`
-	CurrentSalary   int               `json:"current_salary" gorm:"type:int;default:null"`
-	Religion        string            `json:"religion" gorm:"type:varchar(255);default:null"`
+	ExpectedSalary  int               `json:"expected_salary" gorm:"type:text;default:null;serializer:encrypted"`
+	CurrentSalary   int               `json:"current_salary" gorm:"type:text;default:null;serializer:encrypted"`
+	Religion        string            `json:"religion" gorm:"type:text;default:null;serializer:encrypted"`
 	MidsuitID       *string           `json:"midsuit_id" gorm:"type:varchar(255);default:null"`
+	AnonymisedAt    *time.Time        `json:"anonymised_at" gorm:"type:timestamp;default:null"`
 
 	Applicants            []Applicant            `json:"applicants" gorm:"foreignKey:UserProfileID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	WorkExperiences       []WorkExperience       `json:"work_experiences" gorm:"foreignKey:UserProfileID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
@@ -0,0 +1,663 @@
+package helper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type ICurriculumVitaeParserHelper interface {
+	Parse(text string) *entity.CurriculumVitaeDraft
+}
+
+type CurriculumVitaeParserHelper struct {
+	Log *logrus.Logger
+}
+
+func NewCurriculumVitaeParserHelper(log *logrus.Logger) ICurriculumVitaeParserHelper {
+	return &CurriculumVitaeParserHelper{
+		Log: log,
+	}
+}
+
+func CurriculumVitaeParserHelperFactory(log *logrus.Logger) ICurriculumVitaeParserHelper {
+	return NewCurriculumVitaeParserHelper(log)
+}
+
+const (
+	cvSectionPersonal   = "personal"
+	cvSectionEducation  = "education"
+	cvSectionExperience = "experience"
+	cvSectionSkills     = "skills"
+	cvSectionOther      = "other"
+)
+
+// cvSectionHeaders are the section titles of Indonesian and English CVs. Sections the draft has
+// no place for are listed as other, so their lines do not leak into the previous section.
+var cvSectionHeaders = map[string]string{
+	"data pribadi":              cvSectionPersonal,
+	"data diri":                 cvSectionPersonal,
+	"biodata":                   cvSectionPersonal,
+	"informasi pribadi":         cvSectionPersonal,
+	"personal information":      cvSectionPersonal,
+	"personal data":             cvSectionPersonal,
+	"personal details":          cvSectionPersonal,
+	"contact":                   cvSectionPersonal,
+	"contacts":                  cvSectionPersonal,
+	"kontak":                    cvSectionPersonal,
+	"profil":                    cvSectionPersonal,
+	"profile":                   cvSectionPersonal,
+	"about me":                  cvSectionPersonal,
+	"tentang saya":              cvSectionPersonal,
+	"summary":                   cvSectionPersonal,
+	"ringkasan":                 cvSectionPersonal,
+	"pendidikan":                cvSectionEducation,
+	"riwayat pendidikan":        cvSectionEducation,
+	"pendidikan formal":         cvSectionEducation,
+	"latar belakang pendidikan": cvSectionEducation,
+	"education":                 cvSectionEducation,
+	"educations":                cvSectionEducation,
+	"education background":      cvSectionEducation,
+	"educational background":    cvSectionEducation,
+	"academic background":       cvSectionEducation,
+	"pengalaman":                cvSectionExperience,
+	"pengalaman kerja":          cvSectionExperience,
+	"pengalaman bekerja":        cvSectionExperience,
+	"pengalaman pekerjaan":      cvSectionExperience,
+	"riwayat pekerjaan":         cvSectionExperience,
+	"experience":                cvSectionExperience,
+	"experiences":               cvSectionExperience,
+	"work experience":           cvSectionExperience,
+	"work experiences":          cvSectionExperience,
+	"working experience":        cvSectionExperience,
+	"professional experience":   cvSectionExperience,
+	"employment":                cvSectionExperience,
+	"employment history":        cvSectionExperience,
+	"career history":            cvSectionExperience,
+	"skill":                     cvSectionSkills,
+	"skills":                    cvSectionSkills,
+	"technical skills":          cvSectionSkills,
+	"hard skills":               cvSectionSkills,
+	"soft skills":               cvSectionSkills,
+	"keahlian":                  cvSectionSkills,
+	"kemampuan":                 cvSectionSkills,
+	"keterampilan":              cvSectionSkills,
+	"kompetensi":                cvSectionSkills,
+	"kemampuan bahasa":          cvSectionSkills,
+	"bahasa":                    cvSectionSkills,
+	"languages":                 cvSectionSkills,
+	"language":                  cvSectionSkills,
+	"pengalaman organisasi":     cvSectionOther,
+	"organisasi":                cvSectionOther,
+	"organization":              cvSectionOther,
+	"organizational experience": cvSectionOther,
+	"sertifikat":                cvSectionOther,
+	"sertifikasi":               cvSectionOther,
+	"certificates":              cvSectionOther,
+	"certifications":            cvSectionOther,
+	"pelatihan":                 cvSectionOther,
+	"training":                  cvSectionOther,
+	"seminar":                   cvSectionOther,
+	"kursus":                    cvSectionOther,
+	"courses":                   cvSectionOther,
+	"prestasi":                  cvSectionOther,
+	"penghargaan":               cvSectionOther,
+	"achievements":              cvSectionOther,
+	"awards":                    cvSectionOther,
+	"projects":                  cvSectionOther,
+	"proyek":                    cvSectionOther,
+	"referensi":                 cvSectionOther,
+	"references":                cvSectionOther,
+	"hobi":                      cvSectionOther,
+	"hobbies":                   cvSectionOther,
+	"interests":                 cvSectionOther,
+}
+
+var (
+	cvEmailPattern        = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	cvPhonePattern        = regexp.MustCompile(`(?:\+62|62|0)[\s\-.]?8[\d\s\-.]{7,14}\d`)
+	cvYearPattern         = regexp.MustCompile(`\b(19[5-9]\d|20\d\d)\b`)
+	cvYearRangePattern    = regexp.MustCompile(`(?i)((?:(?:jan|feb|peb|mar|apr|mei|may|jun|jul|agu|agt|aug|sep|okt|oct|nov|nop|des|dec)[a-z]*\.?\s+)?(?:0?[1-9]|1[0-2])?[/\-.]?(?:19|20)\d\d)\s*(?:-|–|—|to|until|s/d|s\.d\.?|sampai|hingga)\s*((?:(?:jan|feb|peb|mar|apr|mei|may|jun|jul|agu|agt|aug|sep|okt|oct|nov|nop|des|dec)[a-z]*\.?\s+)?(?:0?[1-9]|1[0-2])?[/\-.]?(?:19|20)\d\d|present|sekarang|now|current|saat ini|kini)`)
+	cvGpaPattern          = regexp.MustCompile(`(?i)\b(?:ipk|gpa|nilai)\s*[:\-]?\s*(\d[.,]\d{1,2})`)
+	cvLabelPattern        = regexp.MustCompile(`^([A-Za-z ,/]{2,40}?)\s*[:：]\s*(.+)$`)
+	cvBulletPattern       = regexp.MustCompile(`^[\-–•·*▪●○◦■□✓➢➤>]+\s*`)
+	cvSeparatorPattern    = regexp.MustCompile(`\s+[|–—-]\s+|\s*\|\s*|\t+`)
+	cvNumericMonthPattern = regexp.MustCompile(`^(0?[1-9]|1[0-2])[/\-.]`)
+	cvMajorPattern        = regexp.MustCompile(`(?i)\b(?:jurusan|program studi|prodi|major|majoring in|study program)\s*[:\-]?\s*(.+)`)
+	cvCompanyPattern      = regexp.MustCompile(`(?i)(?:^|\s|\()(pt|cv|tbk|persero|ltd|inc|corp|corporation|company|bank|group|koperasi|yayasan|ud|llc|gmbh|co\.)(?:$|[\s.,)])`)
+	cvAtCompanyPattern    = regexp.MustCompile(`(?i)^(.+?)\s+(?:at|di|@)\s+(.+)$`)
+	cvSchoolPattern       = regexp.MustCompile(`(?i)\b(universitas|university|univ\.?|institut|institute|politeknik|polytechnic|akademi|academy|sekolah|school|college|stie|stmik|stikes|stt|sma|smk|sman|smkn|smp|smpn|sd|sdn|man|mts|madrasah)\b`)
+)
+
+// cvEducationLevels are tried in order, the more specific patterns first
+var cvEducationLevels = []struct {
+	pattern *regexp.Regexp
+	level   entity.EducationLevelEnum
+}{
+	{regexp.MustCompile(`(?i)\b(s3|doktor|doctor(ate)?|ph\.?\s?d)\b`), entity.EDUCATION_LEVEL_ENUM_DOCTORAL},
+	{regexp.MustCompile(`(?i)\b(s2|magister|master|mba|m\.\s?(sc|m|kom|si|t|ak))\b`), entity.EDUCATION_LEVEL_ENUM_MASTER},
+	{regexp.MustCompile(`(?i)\b(d4|d-4|d-iv|diploma\s*(iv|4)|sarjana terapan|s\.?tr)\b`), entity.EDUCATION_LEVEL_ENUM_D4},
+	{regexp.MustCompile(`(?i)\b(s1|s-1|sarjana|bachelor|b\.\s?(sc|a)|s\.\s?(kom|e|t|h|pd|ak|si))\b`), entity.EDUCATION_LEVEL_ENUM_BACHELOR},
+	{regexp.MustCompile(`(?i)\b(d3|d-3|d-iii|diploma\s*(iii|3)|ahli madya|a\.?md)\b`), entity.EDUCATION_LEVEL_ENUM_D3},
+	{regexp.MustCompile(`(?i)\b(d2|d-2|d-ii|diploma\s*(ii|2))\b`), entity.EDUCATION_LEVEL_ENUM_D2},
+	{regexp.MustCompile(`(?i)\b(d1|d-1|d-i|diploma\s*(i|1))\b`), entity.EDUCATION_LEVEL_ENUM_D1},
+	{regexp.MustCompile(`(?i)\b(sma|smk|sman|smkn|slta|man|high school|senior high)\b`), entity.EDUCATION_LEVEL_ENUM_SMA},
+	{regexp.MustCompile(`(?i)\b(smp|smpn|sltp|mts|junior high)\b`), entity.EDUCATION_LEVEL_ENUM_SMP},
+	{regexp.MustCompile(`(?i)\b(sd|sdn|mi|elementary school|primary school)\b`), entity.EDUCATION_LEVEL_ENUM_SD},
+}
+
+var cvMonths = map[string]time.Month{
+	"jan": time.January, "januari": time.January, "january": time.January,
+	"feb": time.February, "februari": time.February, "february": time.February, "pebruari": time.February,
+	"mar": time.March, "maret": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"mei": time.May, "may": time.May,
+	"jun": time.June, "juni": time.June, "june": time.June,
+	"jul": time.July, "juli": time.July, "july": time.July,
+	"agu": time.August, "agt": time.August, "agus": time.August, "agustus": time.August, "aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"okt": time.October, "oct": time.October, "oktober": time.October, "october": time.October,
+	"nov": time.November, "nop": time.November, "november": time.November, "nopember": time.November,
+	"des": time.December, "dec": time.December, "desember": time.December, "december": time.December,
+}
+
+// Parse reads the contact details, education entries, work experiences and skills from the
+// text of a CV. It only guesses: the candidate reviews the draft before it touches the profile,
+// and school names are matched to universities by the caller.
+func (h *CurriculumVitaeParserHelper) Parse(text string) *entity.CurriculumVitaeDraft {
+	draft := &entity.CurriculumVitaeDraft{RawText: text}
+	sections := map[string][]string{}
+	var header []string
+
+	section := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if name, ok := cvSectionOf(line); ok {
+			section = name
+			sections[section] = append(sections[section], "")
+			continue
+		}
+		if section == "" {
+			header = append(header, line)
+			continue
+		}
+		sections[section] = append(sections[section], line)
+	}
+
+	h.parseContact(draft, text, header, sections[cvSectionPersonal])
+	draft.Educations = h.parseEducations(sections[cvSectionEducation])
+	draft.WorkExperiences = h.parseWorkExperiences(sections[cvSectionExperience])
+	draft.Skills = h.parseSkills(sections[cvSectionSkills])
+
+	return draft
+}
+
+func cvSectionOf(line string) (string, bool) {
+	if line == "" || len(strings.Fields(line)) > 5 {
+		return "", false
+	}
+	normalized := strings.ToLower(strings.Trim(line, " :.-•*#"))
+	normalized = strings.Join(strings.Fields(strings.NewReplacer("&", " ", "/", " ").Replace(normalized)), " ")
+	if section, ok := cvSectionHeaders[normalized]; ok {
+		return section, true
+	}
+	// "Pendidikan dan Pelatihan", "Work Experience & Internship"
+	words := strings.Fields(normalized)
+	for size := len(words) - 1; size > 0; size-- {
+		if section, ok := cvSectionHeaders[strings.Join(words[:size], " ")]; ok && (words[size] == "dan" || words[size] == "and") {
+			return section, true
+		}
+	}
+	return "", false
+}
+
+func (h *CurriculumVitaeParserHelper) parseContact(draft *entity.CurriculumVitaeDraft, text string, header, personal []string) {
+	draft.Email = cvEmailPattern.FindString(text)
+	if phone := cvPhonePattern.FindString(text); phone != "" {
+		draft.PhoneNumber = strings.NewReplacer(" ", "", "-", "", ".", "").Replace(phone)
+	}
+
+	lines := append(append([]string{}, header...), personal...)
+	for _, line := range lines {
+		match := cvLabelPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		label := strings.ToLower(strings.TrimSpace(match[1]))
+		value := strings.TrimSpace(match[2])
+		switch {
+		case label == "nama" || label == "nama lengkap" || label == "name" || label == "full name":
+			if draft.Name == "" {
+				draft.Name = cvTitleCase(value)
+			}
+		case strings.Contains(label, "tempat") && strings.Contains(label, "lahir"),
+			label == "ttl",
+			strings.Contains(label, "place") && strings.Contains(label, "birth"):
+			place, date, found := strings.Cut(value, ",")
+			if !found {
+				draft.BirthPlace = value
+				continue
+			}
+			draft.BirthPlace = strings.TrimSpace(place)
+			if birthDate := cvParseDate(date); birthDate != nil {
+				draft.BirthDate = birthDate
+			}
+		case strings.Contains(label, "tanggal lahir") || strings.Contains(label, "tgl lahir") ||
+			strings.Contains(label, "date of birth") || label == "birth date" || label == "birthdate":
+			if birthDate := cvParseDate(value); birthDate != nil {
+				draft.BirthDate = birthDate
+			}
+		case label == "alamat" || label == "address" || label == "domisili" || label == "alamat domisili":
+			if draft.Address == "" {
+				draft.Address = value
+			}
+		case label == "jenis kelamin" || label == "gender" || label == "sex":
+			draft.Gender = cvGender(value)
+		}
+	}
+
+	if draft.Name == "" {
+		for _, line := range header {
+			if cvLooksLikeName(line) {
+				draft.Name = cvTitleCase(line)
+				break
+			}
+		}
+	}
+}
+
+func (h *CurriculumVitaeParserHelper) parseEducations(lines []string) []entity.CurriculumVitaeDraftEducation {
+	var entries [][]string
+	var current []string
+	hasSchool := false
+	for _, line := range lines {
+		line = cvBulletPattern.ReplaceAllString(line, "")
+		if line == "" {
+			if len(current) > 0 {
+				entries = append(entries, current)
+			}
+			current, hasSchool = nil, false
+			continue
+		}
+		isSchool := cvSchoolPattern.MatchString(line)
+		if isSchool && hasSchool {
+			entries = append(entries, current)
+			current, hasSchool = nil, false
+		}
+		current = append(current, line)
+		hasSchool = hasSchool || isSchool
+	}
+	if len(current) > 0 {
+		entries = append(entries, current)
+	}
+
+	// a school line alone is often followed by its major and years, merge the pieces back
+	var merged [][]string
+	for _, entry := range entries {
+		joined := strings.Join(entry, " ")
+		if len(merged) > 0 && !cvSchoolPattern.MatchString(joined) {
+			merged[len(merged)-1] = append(merged[len(merged)-1], entry...)
+			continue
+		}
+		merged = append(merged, entry)
+	}
+
+	educations := []entity.CurriculumVitaeDraftEducation{}
+	for _, entry := range merged {
+		education := cvEducation(entry)
+		if education.SchoolName == "" && education.EducationLevel == "" {
+			continue
+		}
+		educations = append(educations, education)
+	}
+	return educations
+}
+
+func cvEducation(lines []string) entity.CurriculumVitaeDraftEducation {
+	education := entity.CurriculumVitaeDraftEducation{}
+	text := strings.Join(lines, " | ")
+
+	for _, candidate := range cvEducationLevels {
+		if candidate.pattern.MatchString(text) {
+			education.EducationLevel = candidate.level
+			break
+		}
+	}
+
+	if match := cvYearRangePattern.FindStringSubmatch(text); match != nil {
+		if years := cvYearPattern.FindAllString(match[2], -1); len(years) > 0 {
+			education.GraduateYear, _ = strconv.Atoi(years[len(years)-1])
+		}
+	} else {
+		for _, year := range cvYearPattern.FindAllString(text, -1) {
+			if y, _ := strconv.Atoi(year); y > education.GraduateYear && y <= time.Now().Year()+6 {
+				education.GraduateYear = y
+			}
+		}
+	}
+
+	if match := cvGpaPattern.FindStringSubmatch(text); match != nil {
+		if gpa, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64); err == nil && gpa > 0 && gpa <= 4 {
+			education.Gpa = &gpa
+		}
+	}
+
+	for _, line := range lines {
+		if match := cvMajorPattern.FindStringSubmatch(line); match != nil && education.Major == "" {
+			if segments := cvSegments(match[1]); len(segments) > 0 {
+				education.Major = cvCleanSegment(segments[0])
+			}
+		}
+		for _, segment := range cvSegments(cvYearRangePattern.ReplaceAllString(line, " ")) {
+			switch {
+			case cvSchoolPattern.MatchString(segment) && education.SchoolName == "":
+				education.SchoolName = cvCleanSegment(segment)
+			case education.Major == "" && !cvSchoolPattern.MatchString(segment):
+				education.Major = cvMajorOf(segment)
+			}
+		}
+	}
+
+	if education.EducationLevel == "" && education.SchoolName != "" {
+		school := strings.ToLower(education.SchoolName)
+		switch {
+		case strings.Contains(school, "politeknik") || strings.Contains(school, "polytechnic") || strings.Contains(school, "akademi") || strings.Contains(school, "academy"):
+			education.EducationLevel = entity.EDUCATION_LEVEL_ENUM_D3
+		case strings.Contains(school, "univ") || strings.Contains(school, "institut") || strings.Contains(school, "sekolah tinggi"):
+			education.EducationLevel = entity.EDUCATION_LEVEL_ENUM_BACHELOR
+		}
+	}
+
+	return education
+}
+
+// cvMajorOf returns what follows the education level in "S1 Akuntansi" or "Bachelor of
+// Computer Science", the major of SMA entries is IPA or IPS
+func cvMajorOf(segment string) string {
+	for _, candidate := range cvEducationLevels {
+		loc := candidate.pattern.FindStringIndex(segment)
+		if loc == nil || loc[0] > 0 {
+			continue
+		}
+		rest := strings.TrimSpace(segment[loc[1]:])
+		rest = strings.TrimSpace(strings.TrimLeft(rest, ".,:-–"))
+		lower := strings.ToLower(rest)
+		for _, prefix := range []string{"of ", "in ", "degree in ", "degree of ", "jurusan "} {
+			if strings.HasPrefix(lower, prefix) {
+				rest = rest[len(prefix):]
+				break
+			}
+		}
+		return cvCleanSegment(rest)
+	}
+	switch strings.ToUpper(strings.TrimSpace(segment)) {
+	case "IPA", "IPS", "BAHASA":
+		return strings.ToUpper(strings.TrimSpace(segment))
+	}
+	return ""
+}
+
+func (h *CurriculumVitaeParserHelper) parseWorkExperiences(lines []string) []entity.CurriculumVitaeDraftWorkExperience {
+	type workEntry struct {
+		headers      []string
+		descriptions []string
+	}
+	var entries []*workEntry
+	var current *workEntry
+	for _, line := range lines {
+		if line == "" {
+			current = nil
+			continue
+		}
+		isBullet := cvBulletPattern.MatchString(line)
+		isDescription := isBullet || len(strings.Fields(line)) > 10
+		if isDescription {
+			if current == nil {
+				if len(entries) == 0 {
+					continue
+				}
+				current = entries[len(entries)-1]
+			}
+			current.descriptions = append(current.descriptions, cvBulletPattern.ReplaceAllString(line, ""))
+			continue
+		}
+		// a header line after the description, or a second date range, starts the next job
+		if current == nil || len(current.descriptions) > 0 ||
+			(cvYearRangePattern.MatchString(line) && cvYearRangePattern.MatchString(strings.Join(current.headers, " "))) {
+			current = &workEntry{}
+			entries = append(entries, current)
+		}
+		current.headers = append(current.headers, line)
+	}
+
+	experiences := []entity.CurriculumVitaeDraftWorkExperience{}
+	for _, entry := range entries {
+		experience := entity.CurriculumVitaeDraftWorkExperience{
+			JobDescription: strings.Join(entry.descriptions, "\n"),
+		}
+		var segments []string
+		for _, line := range entry.headers {
+			if match := cvYearRangePattern.FindStringSubmatch(line); match != nil {
+				experience.YearExperience = cvYearsBetween(match[1], match[2])
+				line = strings.Replace(line, match[0], " ", 1)
+			}
+			for _, segment := range cvSegments(line) {
+				if segment = cvCleanSegment(segment); segment != "" {
+					segments = append(segments, segment)
+				}
+			}
+		}
+
+		var others []string
+		for _, segment := range segments {
+			if match := cvAtCompanyPattern.FindStringSubmatch(segment); match != nil && experience.CompanyName == "" {
+				experience.Name = strings.TrimSpace(match[1])
+				experience.CompanyName = strings.TrimSpace(match[2])
+				continue
+			}
+			if cvCompanyPattern.MatchString(segment) && experience.CompanyName == "" {
+				experience.CompanyName = segment
+				continue
+			}
+			others = append(others, segment)
+		}
+		if experience.CompanyName == "" && len(others) >= 2 {
+			experience.CompanyName, others = others[0], others[1:]
+		}
+		if experience.Name == "" && len(others) > 0 {
+			experience.Name = others[0]
+		}
+
+		if experience.Name == "" && experience.CompanyName == "" {
+			continue
+		}
+		experiences = append(experiences, experience)
+	}
+	return experiences
+}
+
+func (h *CurriculumVitaeParserHelper) parseSkills(lines []string) []entity.CurriculumVitaeDraftSkill {
+	skills := []entity.CurriculumVitaeDraftSkill{}
+	seen := map[string]bool{}
+	for _, line := range lines {
+		line = cvBulletPattern.ReplaceAllString(line, "")
+		if line == "" {
+			continue
+		}
+		description := ""
+		if match := cvLabelPattern.FindStringSubmatch(line); match != nil {
+			description = strings.TrimSpace(match[1])
+			line = match[2]
+		}
+		for _, item := range cvSplitList(line) {
+			item = strings.TrimSpace(strings.Trim(item, ".-–•· "))
+			if item == "" || len(item) > 100 || len(strings.Fields(item)) > 6 {
+				continue
+			}
+			key := strings.ToLower(item)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			skills = append(skills, entity.CurriculumVitaeDraftSkill{
+				Name:        item,
+				Description: description,
+			})
+		}
+	}
+	return skills
+}
+
+// cvSplitList splits on commas, semicolons and bullets outside parentheses, so "Microsoft
+// Office (Word, Excel)" stays one skill
+func cvSplitList(line string) []string {
+	var items []string
+	var current strings.Builder
+	depth := 0
+	for _, r := range line {
+		switch {
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && (r == ',' || r == ';' || r == '•' || r == '·' || r == '|'):
+			items = append(items, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	return append(items, current.String())
+}
+
+func cvSegments(line string) []string {
+	var segments []string
+	for _, part := range cvSeparatorPattern.Split(line, -1) {
+		for _, piece := range strings.Split(part, ", ") {
+			if piece = strings.TrimSpace(piece); piece != "" {
+				segments = append(segments, piece)
+			}
+		}
+	}
+	return segments
+}
+
+// cvCleanSegment drops the years, GPA and dangling punctuation around a name
+func cvCleanSegment(segment string) string {
+	segment = cvYearRangePattern.ReplaceAllString(segment, "")
+	segment = cvGpaPattern.ReplaceAllString(segment, "")
+	segment = cvYearPattern.ReplaceAllString(segment, "")
+	segment = strings.NewReplacer("()", "", "( )", "", "[]", "").Replace(segment)
+	return strings.TrimSpace(strings.Trim(strings.Join(strings.Fields(segment), " "), " ,;:-–|()"))
+}
+
+func cvYearsBetween(from, to string) int {
+	start := cvParseMonthYear(from)
+	end := time.Now()
+	if years := cvYearPattern.FindString(to); years != "" {
+		end = cvParseMonthYear(to)
+	}
+	if start.IsZero() || end.Before(start) {
+		return 0
+	}
+	months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+	years := (months + 6) / 12
+	if years < 1 {
+		years = 1
+	}
+	return years
+}
+
+func cvParseMonthYear(value string) time.Time {
+	year, err := strconv.Atoi(cvYearPattern.FindString(value))
+	if err != nil {
+		return time.Time{}
+	}
+	month := time.January
+	for _, word := range strings.Fields(strings.ToLower(strings.Trim(value, " ."))) {
+		if m, ok := cvMonths[strings.Trim(word, ".")]; ok {
+			month = m
+			break
+		}
+	}
+	if match := cvNumericMonthPattern.FindStringSubmatch(strings.TrimSpace(value)); match != nil {
+		m, _ := strconv.Atoi(match[1])
+		month = time.Month(m)
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// cvParseDate reads "17 Agustus 1995", "17 August 1995", "17-08-1995", "17/08/1995" and
+// "1995-08-17"
+func cvParseDate(value string) *time.Time {
+	value = strings.TrimSpace(strings.Trim(value, " ."))
+	for _, layout := range []string{"2006-01-02", "02-01-2006", "02/01/2006", "2-1-2006", "2/1/2006", "02.01.2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date
+		}
+	}
+	fields := strings.Fields(strings.ToLower(strings.ReplaceAll(value, ",", " ")))
+	if len(fields) != 3 {
+		return nil
+	}
+	day, err1 := strconv.Atoi(fields[0])
+	year, err2 := strconv.Atoi(fields[2])
+	month, ok := cvMonths[strings.Trim(fields[1], ".")]
+	if err1 != nil || err2 != nil || !ok || day < 1 || day > 31 {
+		return nil
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
+
+func cvGender(value string) entity.UserGender {
+	value = strings.ToLower(value)
+	switch {
+	case strings.Contains(value, "perempuan") || strings.Contains(value, "wanita") || strings.Contains(value, "female"):
+		return entity.FEMALE
+	case strings.Contains(value, "laki") || strings.Contains(value, "pria") || strings.Contains(value, "male"):
+		return entity.MALE
+	}
+	return ""
+}
+
+func cvLooksLikeName(line string) bool {
+	words := strings.Fields(line)
+	if len(words) < 1 || len(words) > 5 || strings.ContainsAny(line, "@:/0123456789") {
+		return false
+	}
+	lower := strings.ToLower(line)
+	for _, title := range []string{"curriculum", "vitae", "resume", "riwayat hidup", "cv"} {
+		if strings.Contains(lower, title) {
+			return false
+		}
+	}
+	for _, r := range line {
+		if !(r == ' ' || r == '.' || r == '\'' || r == ',' || r == '-' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r > 0x7f) {
+			return false
+		}
+	}
+	return true
+}
+
+// cvTitleCase turns names written in capitals into "Budi Santoso", names already in mixed case
+// are left as written
+func cvTitleCase(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if name != strings.ToUpper(name) {
+		return name
+	}
+	words := strings.Fields(strings.ToLower(name))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = []rune(strings.ToUpper(string(runes[0])))[0]
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
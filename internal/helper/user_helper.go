@@ -17,6 +17,8 @@ type IUserHelper interface {
 	GetUserName(user map[string]interface{}) (string, error)
 	GetUserEmail(user map[string]interface{}) (string, error)
 	GetUserProfileEducationMajors(user map[string]interface{}) ([]string, error)
+	GetUserRoles(user map[string]interface{}) ([]string, error)
+	GetUserPermissions(user map[string]interface{}) ([]string, error)
 }
 
 type UserHelper struct {
@@ -315,3 +317,63 @@ func (h *UserHelper) GetUserProfileEducationMajors(user map[string]interface{})
 	h.Log.Infof("User Profile Education Majors: %v", majors)
 	return majors, nil
 }
+
+func (h *UserHelper) GetUserRoles(user map[string]interface{}) ([]string, error) {
+	// Check if the "user" key exists and is a map
+	userData, ok := user["user"].(map[string]interface{})
+	if !ok {
+		h.Log.Errorf("User information is missing or invalid")
+		return nil, errors.New("User information is missing or invalid")
+	}
+
+	roles, _ := userData["roles"].([]interface{})
+	return namesOf(roles), nil
+}
+
+func (h *UserHelper) GetUserPermissions(user map[string]interface{}) ([]string, error) {
+	// Check if the "user" key exists and is a map
+	userData, ok := user["user"].(map[string]interface{})
+	if !ok {
+		h.Log.Errorf("User information is missing or invalid")
+		return nil, errors.New("User information is missing or invalid")
+	}
+
+	// permissions can be given directly on the user or nested in its roles
+	permissions, _ := userData["permissions"].([]interface{})
+	names := namesOf(permissions)
+	if roles, ok := userData["roles"].([]interface{}); ok {
+		for _, role := range roles {
+			if roleMap, ok := role.(map[string]interface{}); ok {
+				rolePermissions, _ := roleMap["permissions"].([]interface{})
+				names = append(names, namesOf(rolePermissions)...)
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(names))
+	var unique []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique, nil
+}
+
+// namesOf reads a list that is either plain strings or objects with a "name" key
+func namesOf(items []interface{}) []string {
+	var names []string
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			names = append(names, v)
+		case map[string]interface{}:
+			if name, ok := v["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -191,7 +191,7 @@ func (h *AdministrativeSelectionHandler) FindAllPaginated(ctx *gin.Context) {
 		filter["status"] = status
 	}
 
-	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, filter)
+	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, filter, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Error("[AdministrativeSelectionHandler.FindAllPaginated] " + err.Error())
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Error when finding all administrative selection", err.Error())
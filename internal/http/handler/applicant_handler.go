@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
@@ -80,6 +82,10 @@ func ApplicantHandlerFactory(
 // @Accept json
 // @Produce json
 // @Param job_posting_id query string true "Job Posting ID"
+// @Param utm_source query string false "UTM source of the link the candidate followed"
+// @Param utm_medium query string false "UTM medium of the link the candidate followed"
+// @Param utm_campaign query string false "UTM campaign of the link the candidate followed"
+// @Param ref query string false "Referral code"
 // @Success 200 {object} response.ApplicantResponse
 // @Security BearerAuth
 // @Router /applicants/apply [post]
@@ -91,6 +97,18 @@ func (h *ApplicantHandler) ApplyJobPosting(ctx *gin.Context) {
 		return
 	}
 
+	var source request.ApplyJobPostingSourceRequest
+	if err := ctx.ShouldBindQuery(&source); err != nil {
+		h.Log.Errorf("[ApplicantHandler.ApplyJobPosting] error when binding source: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+	if err := h.Validate.Struct(source); err != nil {
+		h.Log.Errorf("[ApplicantHandler.ApplyJobPosting] error when validating source: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+
 	user, err := middleware.GetUser(ctx, h.Log)
 	if err != nil {
 		h.Log.Errorf("Error when getting user: %v", err)
@@ -128,7 +146,7 @@ func (h *ApplicantHandler) ApplyJobPosting(ctx *gin.Context) {
 		return
 	}
 
-	applicant, err := h.UseCase.ApplyJobPosting(userProfile.ID, jobPostingID)
+	applicant, err := h.UseCase.ApplyJobPosting(userProfile.ID, jobPostingID, &source)
 	if err != nil {
 		h.Log.Errorf("[ApplicantHandler.ApplyJobPosting] error when applying job posting: %v", err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to apply job posting", err.Error())
@@ -158,6 +176,8 @@ func (h *ApplicantHandler) ApplyJobPosting(ctx *gin.Context) {
 // @Param page_size query int false "Page Size"
 // @Param search query string false "Search"
 // @Param created_at query string false "Created At"
+// @Param match_score query string false "Sort by match score (ASC, DESC)"
+// @Param match_score_min query number false "Minimum Match Score"
 // @Success 200 {array} response.ApplicantResponse
 // @Security BearerAuth
 // @Router /applicants/job-posting/{job_posting_id} [get]
@@ -202,7 +222,28 @@ func (h *ApplicantHandler) GetApplicantsByJobPostingID(ctx *gin.Context) {
 	sort := map[string]interface{}{
 		"created_at": createdAt,
 	}
+	// match_score=DESC lists the best matching applicants first
+	matchScore := strings.ToUpper(ctx.Query("match_score"))
+	if matchScore != "" {
+		if matchScore != "ASC" && matchScore != "DESC" {
+			utils.BadRequestResponse(ctx, "match_score must be ASC or DESC", nil)
+			return
+		}
+		sort = map[string]interface{}{
+			"match_score": matchScore,
+		}
+	}
 	filter := make(map[string]interface{})
+	matchScoreMin := ctx.Query("match_score_min")
+	if matchScoreMin != "" {
+		minimum, err := strconv.ParseFloat(matchScoreMin, 64)
+		if err != nil {
+			h.Log.Errorf("[ApplicantHandler.GetApplicantsByJobPostingID] error when parsing match_score_min: %v", err)
+			utils.BadRequestResponse(ctx, "match_score_min is not a valid number", err)
+			return
+		}
+		filter["match_score_min"] = minimum
+	}
 	// filter by user_profile.name, job_posting.name, status, start_date, end_date
 	userProfileName := ctx.Query("user_profile.name")
 	if userProfileName != "" {
@@ -253,7 +294,7 @@ func (h *ApplicantHandler) GetApplicantsByJobPostingID(ctx *gin.Context) {
 		return
 	}
 
-	applicants, totalData, err := h.UseCase.GetApplicantsByJobPostingID(jobPostingID, orderStr, total, page, pageSize, search, sort, filter)
+	applicants, totalData, err := h.UseCase.GetApplicantsByJobPostingID(jobPostingID, orderStr, total, page, pageSize, search, sort, filter, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Errorf("[ApplicantHandler.GetApplicantsByJobPostingID] error when getting applicants: %v", err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to get applicants", err.Error())
@@ -332,6 +373,10 @@ func (h *ApplicantHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, id, middleware.OrganizationScopeRepository(h.Log).HasApplicant) {
+		return
+	}
+
 	applicant, err := h.UseCase.FindByID(id)
 	if err != nil {
 		h.Log.Errorf("[ApplicantHandler.FindByID] error when finding applicant: %v", err)
@@ -339,6 +384,10 @@ func (h *ApplicantHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	if applicant != nil && !middleware.AuthorizeUserProfiles(ctx, h.Log, applicant.UserProfileID) {
+		return
+	}
+
 	utils.SuccessResponse(ctx, http.StatusOK, "Successfully find applicant", applicant)
 }
 
@@ -360,7 +409,7 @@ func (h *ApplicantHandler) ExportApplicantsByJobPosting(ctx *gin.Context) {
 		return
 	}
 
-	applicants, err := h.UseCase.GetApplicantsByJobPostingIDForExport(jobPostingID)
+	applicants, err := h.UseCase.GetApplicantsByJobPostingIDForExport(jobPostingID, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Errorf("[ApplicantHandler.ExportApplicantsByJobPosting] error when exporting applicants: %v", err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to export applicants", err.Error())
@@ -439,8 +488,12 @@ func (h *ApplicantHandler) ExportApplicantsByJobPosting(ctx *gin.Context) {
 		}
 		f.SetCellValue("Applicants", fmt.Sprintf("D%d", i+2), applicant.AppliedDate)
 		f.SetCellValue("Applicants", fmt.Sprintf("E%d", i+2), applicant.UserProfile.PhoneNumber)
-		f.SetCellValue("Applicants", fmt.Sprintf("F%d", i+2), applicant.UserProfile.ExpectedSalary)
-		f.SetCellValue("Applicants", fmt.Sprintf("G%d", i+2), applicant.UserProfile.CurrentSalary)
+		var ownerID string
+		if applicant.UserProfile.UserID != nil {
+			ownerID = applicant.UserProfile.UserID.String()
+		}
+		f.SetCellValue("Applicants", fmt.Sprintf("F%d", i+2), middleware.SensitiveCellValue(ctx, "expected_salary", ownerID, applicant.UserProfile.ExpectedSalary))
+		f.SetCellValue("Applicants", fmt.Sprintf("G%d", i+2), middleware.SensitiveCellValue(ctx, "current_salary", ownerID, applicant.UserProfile.CurrentSalary))
 	}
 
 	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IApplicantMatchScoreHandler interface {
+	RecalculateByJobPostingID(ctx *gin.Context)
+}
+
+type ApplicantMatchScoreHandler struct {
+	Log      *logrus.Logger
+	Viper    *viper.Viper
+	Validate *validator.Validate
+	UseCase  usecase.IApplicantMatchScoreUseCase
+}
+
+func NewApplicantMatchScoreHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IApplicantMatchScoreUseCase,
+) IApplicantMatchScoreHandler {
+	return &ApplicantMatchScoreHandler{
+		Log:      log,
+		Viper:    viper,
+		Validate: validate,
+		UseCase:  useCase,
+	}
+}
+
+func ApplicantMatchScoreHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IApplicantMatchScoreHandler {
+	useCase := usecase.ApplicantMatchScoreUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	return NewApplicantMatchScoreHandler(log, viper, validate, useCase)
+}
+
+// RecalculateByJobPostingID recalculate the match scores of a job posting
+//
+// @Summary recalculate the match scores of a job posting
+// @Description rescores every applicant of the job posting against its current requirements, e.g. after the MP request changed
+// @Tags Applicants
+// @Accept json
+// @Produce json
+// @Param job_posting_id path string true "Job Posting ID"
+// @Success 200 {object} map[string]int "Success"
+// @Security BearerAuth
+// @Router /applicants/job-posting/{job_posting_id}/match-scores [post]
+func (h *ApplicantMatchScoreHandler) RecalculateByJobPostingID(ctx *gin.Context) {
+	jobPostingID, err := uuid.Parse(ctx.Param("job_posting_id"))
+	if err != nil {
+		h.Log.Errorf("[ApplicantMatchScoreHandler.RecalculateByJobPostingID] error when parsing job_posting_id: %v", err)
+		utils.BadRequestResponse(ctx, "job_posting_id is not a valid UUID", err)
+		return
+	}
+
+	scored, err := h.UseCase.RecalculateByJobPostingID(jobPostingID, middleware.GetOrganizationScope(ctx))
+	if err != nil {
+		h.Log.Errorf("[ApplicantMatchScoreHandler.RecalculateByJobPostingID] error when recalculating match scores: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to recalculate match scores", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Match scores recalculated", gin.H{
+		"scored": scored,
+	})
+}
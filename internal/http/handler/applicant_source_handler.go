@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IApplicantSourceHandler interface {
+	SetApplicantSource(ctx *gin.Context)
+	GetSourceReport(ctx *gin.Context)
+}
+
+type ApplicantSourceHandler struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Validate   *validator.Validate
+	UseCase    usecase.IApplicantSourceUseCase
+	UserHelper helper.IUserHelper
+}
+
+func NewApplicantSourceHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IApplicantSourceUseCase,
+	userHelper helper.IUserHelper,
+) IApplicantSourceHandler {
+	return &ApplicantSourceHandler{
+		Log:        log,
+		Viper:      viper,
+		Validate:   validate,
+		UseCase:    useCase,
+		UserHelper: userHelper,
+	}
+}
+
+func ApplicantSourceHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IApplicantSourceHandler {
+	useCase := usecase.ApplicantSourceUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	userHelper := helper.UserHelperFactory(log)
+	return NewApplicantSourceHandler(log, viper, validate, useCase, userHelper)
+}
+
+// SetApplicantSource set applicant source
+//
+// @Summary Set applicant source
+// @Description Sets the source of a candidate added by HR, or corrects the source captured when the candidate applied
+// @Tags Applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param payload body request.SetApplicantSourceRequest true "Source"
+// @Security BearerAuth
+// @Success 200 {object} response.ApplicantSourceResponse
+// @Router /applicants/{id}/source [put]
+func (h *ApplicantSourceHandler) SetApplicantSource(ctx *gin.Context) {
+	applicantID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		h.Log.Errorf("[ApplicantSourceHandler.SetApplicantSource] error when parsing id: %v", err)
+		utils.BadRequestResponse(ctx, "id is not a valid UUID", err)
+		return
+	}
+
+	var req request.SetApplicantSourceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.Log.Errorf("[ApplicantSourceHandler.SetApplicantSource] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[ApplicantSourceHandler.SetApplicantSource] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	user, err := middleware.GetUser(ctx, h.Log)
+	if err != nil {
+		h.Log.Errorf("[ApplicantSourceHandler.SetApplicantSource] error when getting user: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+	if user == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+		return
+	}
+	userID, err := h.UserHelper.GetUserId(user)
+	if err != nil {
+		h.Log.Errorf("[ApplicantSourceHandler.SetApplicantSource] error when getting user id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	res, err := h.UseCase.SetApplicantSource(applicantID, &req, userID, middleware.GetOrganizationScope(ctx))
+	if err != nil {
+		if errors.Is(err, usecase.ErrApplicantSourceApplicantNotFound) {
+			utils.ErrorResponse(ctx, http.StatusNotFound, "error", err.Error())
+			return
+		}
+		h.Log.Errorf("[ApplicantSourceHandler.SetApplicantSource] error when setting the source: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to set applicant source", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully set applicant source", res)
+}
+
+// GetSourceReport get applicant source report
+//
+// @Summary Get applicant source report
+// @Description Applications, conversions past the first stage and hires per source, overall and per job posting
+// @Tags Dashboard
+// @Accept json
+// @Produce json
+// @Param job_posting_id query string false "Job Posting ID"
+// @Param start_date query string false "Applied from (YYYY-MM-DD), required with end_date"
+// @Param end_date query string false "Applied until (YYYY-MM-DD), required with start_date"
+// @Param for_organization_id query string false "Organization ID"
+// @Param organization_location_id query string false "Organization Location ID"
+// @Param recruitment_type query string false "MT, PH or NS"
+// @Param project_recruitment_header_id query string false "Project Recruitment Header ID"
+// @Security BearerAuth
+// @Success 200 {object} response.ApplicantSourceReportResponse
+// @Router /dashboard/sources [get]
+func (h *ApplicantSourceHandler) GetSourceReport(ctx *gin.Context) {
+	var req request.ApplicantSourceReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		h.Log.Errorf("[ApplicantSourceHandler.GetSourceReport] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[ApplicantSourceHandler.GetSourceReport] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+
+	res, err := h.UseCase.GetSourceReport(&req, middleware.GetOrganizationScope(ctx))
+	if err != nil {
+		if errors.Is(err, usecase.ErrDashboardInvalidPeriod) {
+			utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+			return
+		}
+		h.Log.Errorf("[ApplicantSourceHandler.GetSourceReport] error when getting the report: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully get applicant source report", res)
+}
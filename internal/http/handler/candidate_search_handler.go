@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ICandidateSearchHandler interface {
+	Search(ctx *gin.Context)
+}
+
+type CandidateSearchHandler struct {
+	Log      *logrus.Logger
+	Viper    *viper.Viper
+	Validate *validator.Validate
+	UseCase  usecase.ICandidateSearchUseCase
+}
+
+func NewCandidateSearchHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.ICandidateSearchUseCase,
+) ICandidateSearchHandler {
+	return &CandidateSearchHandler{
+		Log:      log,
+		Viper:    viper,
+		Validate: validate,
+		UseCase:  useCase,
+	}
+}
+
+func CandidateSearchHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) ICandidateSearchHandler {
+	useCase := usecase.CandidateSearchUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	return NewCandidateSearchHandler(log, viper, validate, useCase)
+}
+
+// Search search candidates
+//
+// @Summary search candidates
+// @Description full-text search over profile, education, skills, work experience and CV, ranked with highlighted snippets and facet counts
+// @Tags Candidates
+// @Accept json
+// @Produce json
+// @Param page query int false "Page"
+// @Param page_size query int false "Page Size"
+// @Param q query string false "Search, e.g. D3 akuntansi SAP Medan, supports \"phrases\", or and -excluded words"
+// @Param education_level query string false "Education levels, comma separated (S1,D3)"
+// @Param graduate_year_min query int false "Graduate Year From"
+// @Param graduate_year_max query int false "Graduate Year To"
+// @Param age_min query int false "Age From"
+// @Param age_max query int false "Age To"
+// @Param expected_salary_min query int false "Expected Salary From"
+// @Param expected_salary_max query int false "Expected Salary To"
+// @Param gender query string false "Gender (MALE, FEMALE)"
+// @Param location query string false "Location, matched against address and birth place"
+// @Success 200 {object} response.CandidateSearchResponse "Success"
+// @Security BearerAuth
+// @Router /candidates/search [get]
+func (h *CandidateSearchHandler) Search(ctx *gin.Context) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(ctx.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	filter := map[string]interface{}{
+		"gender":   strings.ToUpper(ctx.Query("gender")),
+		"location": ctx.Query("location"),
+	}
+	if levels := ctx.Query("education_level"); levels != "" {
+		var educationLevels []string
+		for _, level := range strings.Split(levels, ",") {
+			if level = strings.TrimSpace(level); level != "" {
+				educationLevels = append(educationLevels, level)
+			}
+		}
+		filter["education_levels"] = educationLevels
+	}
+	for _, key := range []string{"graduate_year_min", "graduate_year_max", "age_min", "age_max", "expected_salary_min", "expected_salary_max"} {
+		value := ctx.Query(key)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			utils.BadRequestResponse(ctx, key+" must be a positive number", nil)
+			return
+		}
+		filter[key] = number
+	}
+
+	res, err := h.UseCase.Search(page, pageSize, strings.TrimSpace(ctx.Query("q")), filter)
+	if err != nil {
+		h.Log.Errorf("[CandidateSearchHandler.Search] error when searching candidates: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to search candidates", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Candidates found", res)
+}
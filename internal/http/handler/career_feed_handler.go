@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ICareerFeedHandler interface {
+	RssFeed(ctx *gin.Context)
+	AtomFeed(ctx *gin.Context)
+	Sitemap(ctx *gin.Context)
+	FindBySlug(ctx *gin.Context)
+	JsonLD(ctx *gin.Context)
+}
+
+type CareerFeedHandler struct {
+	Log      *logrus.Logger
+	Viper    *viper.Viper
+	Validate *validator.Validate
+	UseCase  usecase.ICareerFeedUseCase
+}
+
+func NewCareerFeedHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.ICareerFeedUseCase,
+) ICareerFeedHandler {
+	return &CareerFeedHandler{
+		Log:      log,
+		Viper:    viper,
+		Validate: validate,
+		UseCase:  useCase,
+	}
+}
+
+func CareerFeedHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) ICareerFeedHandler {
+	useCase := usecase.CareerFeedUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	return NewCareerFeedHandler(log, viper, validate, useCase)
+}
+
+// RssFeed RSS feed of the open job postings
+//
+// @Summary RSS feed of the open job postings
+// @Description approved and opened job postings shown to candidates that have not ended yet
+// @Tags Careers
+// @Produce xml
+// @Success 200 {object} response.RssFeedResponse
+// @Router /careers/feed.rss [get]
+func (h *CareerFeedHandler) RssFeed(ctx *gin.Context) {
+	feed, err := h.UseCase.GetRssFeed()
+	if err != nil {
+		h.Log.Errorf("[CareerFeedHandler.RssFeed] error when building feed: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to build feed", err.Error())
+		return
+	}
+	h.writeXML(ctx, "application/rss+xml; charset=utf-8", feed)
+}
+
+// AtomFeed Atom feed of the open job postings
+//
+// @Summary Atom feed of the open job postings
+// @Description approved and opened job postings shown to candidates that have not ended yet
+// @Tags Careers
+// @Produce xml
+// @Success 200 {object} response.AtomFeedResponse
+// @Router /careers/feed.atom [get]
+func (h *CareerFeedHandler) AtomFeed(ctx *gin.Context) {
+	feed, err := h.UseCase.GetAtomFeed()
+	if err != nil {
+		h.Log.Errorf("[CareerFeedHandler.AtomFeed] error when building feed: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to build feed", err.Error())
+		return
+	}
+	h.writeXML(ctx, "application/atom+xml; charset=utf-8", feed)
+}
+
+// Sitemap XML sitemap of the careers site
+//
+// @Summary XML sitemap of the careers site
+// @Description careers page and every open job posting page
+// @Tags Careers
+// @Produce xml
+// @Success 200 {object} response.SitemapResponse
+// @Router /sitemap.xml [get]
+func (h *CareerFeedHandler) Sitemap(ctx *gin.Context) {
+	sitemap, err := h.UseCase.GetSitemap()
+	if err != nil {
+		h.Log.Errorf("[CareerFeedHandler.Sitemap] error when building sitemap: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to build sitemap", err.Error())
+		return
+	}
+	h.writeXML(ctx, "application/xml; charset=utf-8", sitemap)
+}
+
+// FindBySlug find an open job posting by its slug
+//
+// @Summary find an open job posting by its slug
+// @Description job posting with its schema.org JobPosting JSON-LD
+// @Tags Careers
+// @Produce json
+// @Param slug path string true "Slug"
+// @Success 200 {object} response.PublicJobPostingResponse
+// @Router /api/no-auth/job-postings/slug/{slug} [get]
+func (h *CareerFeedHandler) FindBySlug(ctx *gin.Context) {
+	res, err := h.UseCase.FindPublishedBySlug(ctx.Param("slug"))
+	if err != nil {
+		h.Log.Errorf("[CareerFeedHandler.FindBySlug] error when finding job posting: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find job posting", err.Error())
+		return
+	}
+	if res == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Job posting not found", "job posting not found")
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Success find job posting by slug", res)
+}
+
+// JsonLD schema.org JobPosting of an open job posting
+//
+// @Summary schema.org JobPosting of an open job posting
+// @Description JSON-LD to embed in the job page
+// @Tags Careers
+// @Produce json
+// @Param slug path string true "Slug"
+// @Success 200 {object} response.JobPostingSchemaResponse
+// @Router /careers/jobs/{slug}/schema.json [get]
+func (h *CareerFeedHandler) JsonLD(ctx *gin.Context) {
+	res, err := h.UseCase.FindPublishedBySlug(ctx.Param("slug"))
+	if err != nil {
+		h.Log.Errorf("[CareerFeedHandler.JsonLD] error when finding job posting: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find job posting", err.Error())
+		return
+	}
+	if res == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Job posting not found", "job posting not found")
+		return
+	}
+
+	body, err := json.Marshal(res.JsonLD)
+	if err != nil {
+		h.Log.Errorf("[CareerFeedHandler.JsonLD] error when encoding json-ld: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to encode json-ld", err.Error())
+		return
+	}
+	ctx.Data(http.StatusOK, "application/ld+json; charset=utf-8", body)
+}
+
+func (h *CareerFeedHandler) writeXML(ctx *gin.Context, contentType string, v interface{}) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		h.Log.Errorf("[CareerFeedHandler.writeXML] error when encoding xml: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to encode xml", err.Error())
+		return
+	}
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.Data(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
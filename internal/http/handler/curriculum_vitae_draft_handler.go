@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ICurriculumVitaeDraftHandler interface {
+	CreateDraft(ctx *gin.Context)
+	FindLatest(ctx *gin.Context)
+	AcceptDraft(ctx *gin.Context)
+	RejectDraft(ctx *gin.Context)
+}
+
+type CurriculumVitaeDraftHandler struct {
+	Log           *logrus.Logger
+	Viper         *viper.Viper
+	Validate      *validator.Validate
+	UseCase       usecase.ICurriculumVitaeDraftUseCase
+	UserHelper    helper.IUserHelper
+	UploadService service.IUploadService
+}
+
+func NewCurriculumVitaeDraftHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.ICurriculumVitaeDraftUseCase,
+	userHelper helper.IUserHelper,
+	uploadService service.IUploadService,
+) ICurriculumVitaeDraftHandler {
+	return &CurriculumVitaeDraftHandler{
+		Log:           log,
+		Viper:         viper,
+		Validate:      validate,
+		UseCase:       useCase,
+		UserHelper:    userHelper,
+		UploadService: uploadService,
+	}
+}
+
+func CurriculumVitaeDraftHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) ICurriculumVitaeDraftHandler {
+	useCase := usecase.CurriculumVitaeDraftUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	userHelper := helper.UserHelperFactory(log)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewCurriculumVitaeDraftHandler(log, viper, validate, useCase, userHelper, uploadService)
+}
+
+// CreateDraft parse a curriculum vitae into a profile draft
+//
+//	@Summary		Parse a curriculum vitae into a profile draft
+//	@Description	Reads contact details, educations, work experiences and skills from a PDF or DOCX CV. Without a file the CV already on the profile is read.
+//	@Tags			User Profiles
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			curriculum_vitae	formData	file	false	"Curriculum Vitae (PDF or DOCX)"
+//	@Success		201	{object}	response.CurriculumVitaeDraftResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/cv-drafts [post]
+func (h *CurriculumVitaeDraftHandler) CreateDraft(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	var cvPath string
+	file, err := ctx.FormFile("curriculum_vitae")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		h.Log.Error("Failed to parse form-data: ", err)
+		utils.BadRequestResponse(ctx, "bad request", err.Error())
+		return
+	}
+	if file != nil {
+		if ext := strings.ToLower(filepath.Ext(file.Filename)); ext != ".pdf" && ext != ".docx" {
+			utils.BadRequestResponse(ctx, "curriculum vitae must be a PDF or DOCX file", nil)
+			return
+		}
+		cvPath, err = h.UploadService.Save(file, storage.UPLOAD_PURPOSE_CURRICULUM_VITAE, "storage/user_profiles/cv")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
+			return
+		}
+	}
+
+	res, err := h.UseCase.CreateDraft(userUUID, cvPath)
+	if err != nil {
+		h.Log.Error("[CurriculumVitaeDraftHandler.CreateDraft] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusUnprocessableEntity, "failed to read curriculum vitae", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "success", res)
+}
+
+// FindLatest find the latest curriculum vitae draft
+//
+//	@Summary		Find the latest curriculum vitae draft
+//	@Description	Find the latest curriculum vitae draft of the logged in candidate
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.CurriculumVitaeDraftResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/cv-drafts/latest [get]
+func (h *CurriculumVitaeDraftHandler) FindLatest(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := h.UseCase.FindLatestByUserID(userUUID)
+	if err != nil {
+		h.Log.Error("[CurriculumVitaeDraftHandler.FindLatest] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+	if res == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "Curriculum vitae draft not found")
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+// AcceptDraft accept a curriculum vitae draft
+//
+//	@Summary		Accept a curriculum vitae draft
+//	@Description	Merges the reviewed draft into the profile. Fields already filled are kept, sent fields and entry lists replace what the parser read.
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string	true	"Draft ID"
+//	@Param			body	body	request.AcceptCurriculumVitaeDraftRequest	false	"Corrections"
+//	@Success		200	{object}	response.UserProfileResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/cv-drafts/{id}/accept [post]
+func (h *CurriculumVitaeDraftHandler) AcceptDraft(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(ctx, "invalid id", err.Error())
+		return
+	}
+
+	var req request.AcceptCurriculumVitaeDraftRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.BadRequestResponse(ctx, "bad request", err.Error())
+			return
+		}
+		if err := h.Validate.Struct(req); err != nil {
+			utils.BadRequestResponse(ctx, "bad request", err.Error())
+			return
+		}
+	}
+
+	res, err := h.UseCase.AcceptDraft(id, userUUID, &req)
+	if err != nil {
+		h.Log.Error("[CurriculumVitaeDraftHandler.AcceptDraft] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+// RejectDraft reject a curriculum vitae draft
+//
+//	@Summary		Reject a curriculum vitae draft
+//	@Description	Discards the draft, the profile is left as it is
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"Draft ID"
+//	@Success		200	{object}	response.CurriculumVitaeDraftResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/cv-drafts/{id}/reject [post]
+func (h *CurriculumVitaeDraftHandler) RejectDraft(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(ctx, "invalid id", err.Error())
+		return
+	}
+
+	res, err := h.UseCase.RejectDraft(id, userUUID)
+	if err != nil {
+		h.Log.Error("[CurriculumVitaeDraftHandler.RejectDraft] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+func (h *CurriculumVitaeDraftHandler) userID(ctx *gin.Context) (uuid.UUID, bool) {
+	user, err := middleware.GetUser(ctx, h.Log)
+	if err != nil {
+		h.Log.Errorf("Error when getting user: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	if user == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+		return uuid.Nil, false
+	}
+	userUUID, err := h.UserHelper.GetUserId(user)
+	if err != nil {
+		h.Log.Errorf("Error when getting user id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	return userUUID, true
+}
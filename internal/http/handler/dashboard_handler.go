@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
@@ -49,16 +52,39 @@ func DashboardHandlerFactory(
 // GetDashboard get dashboard data
 //
 // @Summary Get dashboard data
-// @Description Get dashboard data
+// @Description Get dashboard data. Every metric is limited to the given filters. With a period the headline figures are compared with the period of equal length before it.
 // @Tags Dashboard
 // @Accept json
 // @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD), required with end_date"
+// @Param end_date query string false "End date (YYYY-MM-DD), required with start_date"
+// @Param for_organization_id query string false "Organization ID"
+// @Param organization_location_id query string false "Organization location ID"
+// @Param recruitment_type query string false "Recruitment type" Enums(MT, PH, NS)
+// @Param project_recruitment_header_id query string false "Project recruitment header ID"
 // @Security BearerAuth
 // @Success 200 {object} response.DashboardResponse
 // @Router /dashboard [get]
 func (h *DashboardHandler) GetDashboard(ctx *gin.Context) {
-	res, err := h.UseCase.GetDashboard()
+	var req request.DashboardFilterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		h.Log.Errorf("[DashboardHandler.GetDashboard] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[DashboardHandler.GetDashboard] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+
+	res, err := h.UseCase.GetDashboard(&req, middleware.GetOrganizationScope(ctx))
 	if err != nil {
+		if errors.Is(err, usecase.ErrDashboardInvalidPeriod) {
+			utils.BadRequestResponse(ctx, err.Error(), err)
+			return
+		}
 		h.Log.Errorf("[DashboardHandler.GetDashboard] " + err.Error())
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
 		return
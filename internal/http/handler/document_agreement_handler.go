@@ -4,14 +4,15 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -37,6 +38,7 @@ type DocumentAgreementHandler struct {
 	ProjectRecruitmentLineUseCase usecase.IProjectRecruitmentLineUseCase
 	UserMessage                   messaging.IUserMessage
 	NotificationService           service.INotificationService
+	UploadService                 service.IUploadService
 }
 
 func NewDocumentAgreementHandler(
@@ -47,6 +49,7 @@ func NewDocumentAgreementHandler(
 	projectRecruitmentLineUseCase usecase.IProjectRecruitmentLineUseCase,
 	userMessage messaging.IUserMessage,
 	notificationService service.INotificationService,
+	uploadService service.IUploadService,
 ) IDocumentAgreementHandler {
 	return &DocumentAgreementHandler{
 		Log:                           log,
@@ -56,6 +59,7 @@ func NewDocumentAgreementHandler(
 		ProjectRecruitmentLineUseCase: projectRecruitmentLineUseCase,
 		UserMessage:                   userMessage,
 		NotificationService:           notificationService,
+		UploadService:                 uploadService,
 	}
 }
 
@@ -65,6 +69,7 @@ func DocumentAgreementHandlerFactory(log *logrus.Logger, viper *viper.Viper) IDo
 	projectRecruitmentLineUseCase := usecase.ProjectRecruitmentLineUseCaseFactory(log)
 	userMessage := messaging.UserMessageFactory(log)
 	notificationService := service.NotificationServiceFactory(viper, log)
+	uploadService := service.UploadServiceFactory(viper, log)
 	return NewDocumentAgreementHandler(
 		log,
 		viper,
@@ -73,6 +78,7 @@ func DocumentAgreementHandlerFactory(log *logrus.Logger, viper *viper.Viper) IDo
 		projectRecruitmentLineUseCase,
 		userMessage,
 		notificationService,
+		uploadService,
 	)
 }
 
@@ -103,11 +109,9 @@ func (h *DocumentAgreementHandler) CreateDocumentAgreement(ctx *gin.Context) {
 
 	// handle file uploads
 	if req.File != nil {
-		timestamp := time.Now().UnixNano()
-		filePath := "storage/document-agreement/" + strconv.FormatInt(timestamp, 10) + "_" + req.File.Filename
-		if err := ctx.SaveUploadedFile(req.File, filePath); err != nil {
-			h.Log.Error("[DocumentAgreementHandler.CreateDocumentAgreement] " + err.Error())
-			utils.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Failed to upload file", err.Error())
+		filePath, err := h.UploadService.Save(req.File, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/document-agreement")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		req.Path = filePath
@@ -188,11 +192,9 @@ func (h *DocumentAgreementHandler) UpdateDocumentAgreement(ctx *gin.Context) {
 
 	// handle file uploads
 	if req.File != nil {
-		timestamp := time.Now().UnixNano()
-		filePath := "storage/document-agreement/" + strconv.FormatInt(timestamp, 10) + "_" + req.File.Filename
-		if err := ctx.SaveUploadedFile(req.File, filePath); err != nil {
-			h.Log.Error("[DocumentAgreementHandler.UpdateDocumentAgreement] " + err.Error())
-			utils.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Failed to upload file", err.Error())
+		filePath, err := h.UploadService.Save(req.File, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/document-agreement")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		req.Path = filePath
@@ -324,7 +326,7 @@ func (h *DocumentAgreementHandler) FindAllPaginated(ctx *gin.Context) {
 		documentTypeID = ""
 	}
 
-	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, filter, documentTypeID)
+	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, filter, documentTypeID, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Error("[DocumentAgreementHandler.FindAllPaginated] " + err.Error())
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all document agreement", err.Error())
@@ -1,18 +1,21 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
@@ -48,6 +51,7 @@ type DocumentSendingHandler struct {
 	OrganizationMessage messaging.IOrganizationMessage
 	UserMessage         messaging.IUserMessage
 	UserHelper          helper.IUserHelper
+	Storage             storage.IStorage
 }
 
 func NewDocumentSendingHandler(
@@ -58,6 +62,7 @@ func NewDocumentSendingHandler(
 	orgMessage messaging.IOrganizationMessage,
 	userMessage messaging.IUserMessage,
 	userHelper helper.IUserHelper,
+	fileStorage storage.IStorage,
 ) *DocumentSendingHandler {
 	return &DocumentSendingHandler{
 		Log:                 log,
@@ -67,6 +72,7 @@ func NewDocumentSendingHandler(
 		OrganizationMessage: orgMessage,
 		UserMessage:         userMessage,
 		UserHelper:          userHelper,
+		Storage:             fileStorage,
 	}
 }
 
@@ -79,7 +85,8 @@ func DocumentSendingHandlerFactory(
 	orgMessage := messaging.OrganizationMessageFactory(log)
 	userMessage := messaging.UserMessageFactory(log)
 	userHelper := helper.UserHelperFactory(log)
-	return NewDocumentSendingHandler(log, viper, validate, useCase, orgMessage, userMessage, userHelper)
+	fileStorage := storage.StorageFactory(viper, log)
+	return NewDocumentSendingHandler(log, viper, validate, useCase, orgMessage, userMessage, userHelper, fileStorage)
 }
 
 // CreateDocumentSending  create document sending
@@ -142,6 +149,10 @@ func (h *DocumentSendingHandler) UpdateDocumentSending(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, uuid.MustParse(payload.ID), middleware.OrganizationScopeRepository(h.Log).HasDocumentSending) {
+		return
+	}
+
 	res, err := h.UseCase.UpdateDocumentSending(&payload)
 	if err != nil {
 		h.Log.Errorf("[DocumentSendingHandler.UpdateDocumentSending] error when updating document sending: %v", err)
@@ -203,7 +214,7 @@ func (h *DocumentSendingHandler) FindAllPaginatedByDocumentTypeID(ctx *gin.Conte
 		"created_at": createdAt,
 	}
 
-	res, total, err := h.UseCase.FindAllPaginatedByDocumentTypeID(parsedDocumentTypeID, page, pageSize, search, sort)
+	res, total, err := h.UseCase.FindAllPaginatedByDocumentTypeID(parsedDocumentTypeID, page, pageSize, search, sort, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Errorf("[DocumentSendingHandler.FindAllPaginatedByDocumentTypeID] error when finding all paginated by document type id: %v", err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all paginated by document type id", err.Error())
@@ -234,6 +245,15 @@ func (h *DocumentSendingHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "ID is not a valid UUID", err.Error())
+		return
+	}
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasDocumentSending) {
+		return
+	}
+
 	res, err := h.UseCase.FindByID(id)
 	if err != nil {
 		h.Log.Errorf("[DocumentSendingHandler.FindByID] error when finding by id: %v", err)
@@ -241,9 +261,24 @@ func (h *DocumentSendingHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	// offers and contracts are only shown to staff and the candidate they were sent to
+	if !h.authorizeApplicant(ctx, res) {
+		return
+	}
+
 	utils.SuccessResponse(ctx, http.StatusOK, "Document sending found", res)
 }
 
+// authorizeApplicant aborts with 403 unless the caller is staff or owns the profile of the
+// applicant the document was sent to
+func (h *DocumentSendingHandler) authorizeApplicant(ctx *gin.Context, res *response.DocumentSendingResponse) bool {
+	var userProfileID uuid.UUID
+	if res != nil && res.Applicant != nil {
+		userProfileID = res.Applicant.UserProfileID
+	}
+	return middleware.AuthorizeUserProfiles(ctx, h.Log, userProfileID)
+}
+
 // DeleteDocumentSending  delete document sending
 //
 // @Summary delete document sending
@@ -262,7 +297,16 @@ func (h *DocumentSendingHandler) DeleteDocumentSending(ctx *gin.Context) {
 		return
 	}
 
-	err := h.UseCase.DeleteDocumentSending(id)
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "ID is not a valid UUID", err.Error())
+		return
+	}
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasDocumentSending) {
+		return
+	}
+
+	err = h.UseCase.DeleteDocumentSending(id)
 	if err != nil {
 		h.Log.Errorf("[DocumentSendingHandler.DeleteDocumentSending] error when deleting document sending: %v", err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to delete document sending", err.Error())
@@ -364,6 +408,10 @@ func (h *DocumentSendingHandler) FindByDocumentTypeIDAndApplicantID(ctx *gin.Con
 		return
 	}
 
+	if !h.authorizeApplicant(ctx, res) {
+		return
+	}
+
 	utils.SuccessResponse(ctx, http.StatusOK, "Document sending found", res)
 }
 
@@ -387,24 +435,24 @@ func (h *DocumentSendingHandler) TestGeneratePDF(ctx *gin.Context) {
 	// Define the file path
 	filePath := "storage/hello.pdf"
 
-	// Ensure the directory exists
-	err := os.MkdirAll("storage", os.ModePerm)
+	var buffer bytes.Buffer
+	err := pdf.Output(&buffer)
 	if err != nil {
-		h.Log.Errorf("[DocumentSendingHandler.TestGeneratePDF] error when creating directory: %v", err)
-		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to create directory", err.Error())
+		h.Log.Errorf("[DocumentSendingHandler.TestGeneratePDF] error when generating pdf: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to generate pdf", err.Error())
 		return
 	}
 
-	// Save the PDF to the file
-	err = pdf.OutputFileAndClose(filePath)
+	// Save the PDF to the storage
+	err = storage.PutBytes(h.Storage, filePath, buffer.Bytes())
 	if err != nil {
-		h.Log.Errorf("[DocumentSendingHandler.TestGeneratePDF] error when generating pdf: %v", err)
-		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to generate pdf", err.Error())
+		h.Log.Errorf("[DocumentSendingHandler.TestGeneratePDF] error when saving pdf: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to save pdf", err.Error())
 		return
 	}
 
 	// Return the generated PDF file as a response
-	ctx.File(filePath)
+	ctx.Data(http.StatusOK, "application/pdf", buffer.Bytes())
 }
 
 // TestSendEmail  test send email
@@ -672,6 +720,10 @@ func (h *DocumentSendingHandler) GeneratePdfBufferForDocumentSending(ctx *gin.Co
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedDocSendingID, middleware.OrganizationScopeRepository(h.Log).HasDocumentSending) {
+		return
+	}
+
 	documentSending, err := h.UseCase.FindByID(parsedDocSendingID.String())
 	if err != nil {
 		h.Log.Errorf("[DocumentSendingHandler.GeneratePdfBufferForDocumentSending] error when finding document sending: %v", err)
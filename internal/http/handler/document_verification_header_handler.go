@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"bytes"
 	"net/http"
 	"strconv"
 
@@ -9,7 +10,9 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -39,6 +42,8 @@ type DocumentVerificationHeaderHandler struct {
 	UserHelper         helper.IUserHelper
 	EmployeeMessage    messaging.IEmployeeMessage
 	UserProfileUseCase usecase.IUserProfileUseCase
+	Storage            storage.IStorage
+	UploadService      service.IUploadService
 }
 
 func NewDocumentVerificationHeaderHandler(
@@ -50,6 +55,8 @@ func NewDocumentVerificationHeaderHandler(
 	userHelper helper.IUserHelper,
 	employeeMessage messaging.IEmployeeMessage,
 	userProfileUseCase usecase.IUserProfileUseCase,
+	fileStorage storage.IStorage,
+	uploadService service.IUploadService,
 ) IDocumentVerificationHeaderHandler {
 	return &DocumentVerificationHeaderHandler{
 		Log:                log,
@@ -60,6 +67,8 @@ func NewDocumentVerificationHeaderHandler(
 		UserHelper:         userHelper,
 		EmployeeMessage:    employeeMessage,
 		UserProfileUseCase: userProfileUseCase,
+		Storage:            fileStorage,
+		UploadService:      uploadService,
 	}
 }
 
@@ -73,6 +82,8 @@ func DocumentVerificationHeaderHandlerFactory(
 	userHelper := helper.UserHelperFactory(log)
 	employeeMessage := messaging.EmployeeMessageFactory(log)
 	userProfileUseCase := usecase.UserProfileUseCaseFactory(log, viper)
+	fileStorage := storage.StorageFactory(viper, log)
+	uploadService := service.UploadServiceFactory(viper, log)
 	return NewDocumentVerificationHeaderHandler(
 		log,
 		viper,
@@ -82,6 +93,8 @@ func DocumentVerificationHeaderHandlerFactory(
 		userHelper,
 		employeeMessage,
 		userProfileUseCase,
+		fileStorage,
+		uploadService,
 	)
 }
 
@@ -211,7 +224,7 @@ func (h *DocumentVerificationHeaderHandler) FindAllPaginated(ctx *gin.Context) {
 		"created_at": createdAt,
 	}
 
-	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort)
+	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Errorf("[DocumentVerificationHeaderHandler.FindAllPaginated] error when finding all document verification header: %v", err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Error when finding all document verification header", err.Error())
@@ -362,7 +375,14 @@ func (h *DocumentVerificationHeaderHandler) ExportBPJSTenagaKerja(ctx *gin.Conte
 		return
 	}
 
-	f, err := excelize.OpenFile("./storage/template_tk_14005857.xlsx")
+	template, err := storage.ReadFile(h.Storage, "storage/template_tk_14005857.xlsx")
+	if err != nil {
+		h.Log.Errorf("Error when reading template: %v", err)
+		utils.ErrorResponse(ctx, 500, "error", err.Error())
+		return
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(template))
 	if err != nil {
 		h.Log.Errorf("Error when opening file: %v", err)
 		utils.ErrorResponse(ctx, 500, "error", err.Error())
@@ -394,7 +414,13 @@ func (h *DocumentVerificationHeaderHandler) ExportBPJSTenagaKerja(ctx *gin.Conte
 
 	// Save the spreadsheet by the given path.
 	fileName := "bpjs_tk_" + userId.String() + ".xlsx"
-	if err := f.SaveAs("./storage/bpjs" + fileName); err != nil {
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		h.Log.Errorf("Error when saving file: %v", err)
+		utils.ErrorResponse(ctx, 500, "error", err.Error())
+		return
+	}
+	if err := storage.PutBytes(h.Storage, "storage/bpjs"+fileName, buffer.Bytes()); err != nil {
 		h.Log.Errorf("Error when saving file: %v", err)
 		utils.ErrorResponse(ctx, 500, "error", err.Error())
 		return
@@ -430,10 +456,9 @@ func (h *DocumentVerificationHeaderHandler) ImportBPJSTenagaKerja(ctx *gin.Conte
 		return
 	}
 
-	err = ctx.SaveUploadedFile(file, "./storage/"+file.Filename)
-	if err != nil {
-		h.Log.Errorf("Error when saving file: %v", err)
-		utils.ErrorResponse(ctx, 500, "error", err.Error())
+	// stored under its own name, the export reads storage/template_tk_14005857.xlsx
+	if _, err := h.UploadService.SaveAs(file, storage.UPLOAD_PURPOSE_SPREADSHEET, "storage/"+storage.SanitizeFilename(file.Filename, ".xlsx")); err != nil {
+		respondUploadError(ctx, h.Log, err)
 		return
 	}
 
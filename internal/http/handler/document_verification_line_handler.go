@@ -2,12 +2,12 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -25,10 +25,11 @@ type IDocumentVerificationLineHandler interface {
 }
 
 type DocumentVerificationLineHandler struct {
-	Log      *logrus.Logger
-	Viper    *viper.Viper
-	Validate *validator.Validate
-	UseCase  usecase.IDocumentVerificationLineUsecase
+	Log           *logrus.Logger
+	Viper         *viper.Viper
+	Validate      *validator.Validate
+	UseCase       usecase.IDocumentVerificationLineUsecase
+	UploadService service.IUploadService
 }
 
 func NewDocumentVerificationLineHandler(
@@ -36,12 +37,14 @@ func NewDocumentVerificationLineHandler(
 	viper *viper.Viper,
 	validate *validator.Validate,
 	useCase usecase.IDocumentVerificationLineUsecase,
+	uploadService service.IUploadService,
 ) IDocumentVerificationLineHandler {
 	return &DocumentVerificationLineHandler{
-		Log:      log,
-		Viper:    viper,
-		Validate: validate,
-		UseCase:  useCase,
+		Log:           log,
+		Viper:         viper,
+		Validate:      validate,
+		UseCase:       useCase,
+		UploadService: uploadService,
 	}
 }
 
@@ -51,7 +54,8 @@ func DocumentVerificationLineHandlerFactory(
 ) IDocumentVerificationLineHandler {
 	useCase := usecase.DocumentVerificationLineFactory(log, viper)
 	validate := config.NewValidator(viper)
-	return NewDocumentVerificationLineHandler(log, viper, validate, useCase)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewDocumentVerificationLineHandler(log, viper, validate, useCase, uploadService)
 }
 
 // CreateOrUpdateDocumentVerificationLine create or update document verification line
@@ -174,11 +178,9 @@ func (h *DocumentVerificationLineHandler) UploadDocumentVerificationLine(ctx *gi
 		return
 	}
 
-	timestamp := time.Now().UnixNano()
-	filePath := "storage/document_verification_lines/" + strconv.FormatInt(timestamp, 10) + "_" + file.Filename
-	if err := ctx.SaveUploadedFile(file, filePath); err != nil {
-		h.Log.Error("Failed to save file: ", err)
-		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Internal Server Error", err.Error())
+	filePath, err := h.UploadService.Save(file, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/document_verification_lines")
+	if err != nil {
+		respondUploadError(ctx, h.Log, err)
 		return
 	}
 
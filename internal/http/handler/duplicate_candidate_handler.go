@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IDuplicateCandidateHandler interface {
+	FindAllPaginated(ctx *gin.Context)
+	Merge(ctx *gin.Context)
+	Dismiss(ctx *gin.Context)
+}
+
+type DuplicateCandidateHandler struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Validate   *validator.Validate
+	UseCase    usecase.IDuplicateCandidateUseCase
+	UserHelper helper.IUserHelper
+}
+
+func NewDuplicateCandidateHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IDuplicateCandidateUseCase,
+	userHelper helper.IUserHelper,
+) IDuplicateCandidateHandler {
+	return &DuplicateCandidateHandler{
+		Log:        log,
+		Viper:      viper,
+		Validate:   validate,
+		UseCase:    useCase,
+		UserHelper: userHelper,
+	}
+}
+
+func DuplicateCandidateHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IDuplicateCandidateHandler {
+	useCase := usecase.DuplicateCandidateUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	userHelper := helper.UserHelperFactory(log)
+	return NewDuplicateCandidateHandler(log, viper, validate, useCase, userHelper)
+}
+
+// FindAllPaginated find probable duplicate candidates
+//
+//	@Summary		Find probable duplicate candidates
+//	@Description	Pairs of profiles sharing a KTP number, phone number, email or name and birth date, highest score first. Detection runs every 15 minutes as the detect_duplicate_candidates scheduler job.
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query	int		false	"Page"
+//	@Param			page_size	query	int		false	"Page Size"
+//	@Param			status		query	string	false	"Status (OPEN, MERGED, DISMISSED), defaults to OPEN"
+//	@Success		200	{object}	response.DuplicateCandidateResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/duplicates [get]
+func (h *DuplicateCandidateHandler) FindAllPaginated(ctx *gin.Context) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(ctx.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	status := strings.ToUpper(ctx.Query("status"))
+	if status == "" {
+		status = "OPEN"
+	}
+
+	duplicates, total, err := h.UseCase.FindAllPaginated(page, pageSize, status)
+	if err != nil {
+		h.Log.Error("[DuplicateCandidateHandler.FindAllPaginated] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", gin.H{
+		"duplicate_candidates": duplicates,
+		"total":                total,
+	})
+}
+
+// Merge merge a duplicate candidate
+//
+//	@Summary		Merge a duplicate candidate
+//	@Description	Moves the applicants, educations, work experiences, skills and question responses of the other profile to the surviving profile and deletes the other profile
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string	true	"Duplicate Candidate ID"
+//	@Param			body	body	request.MergeDuplicateCandidateRequest	true	"Surviving profile"
+//	@Success		200	{object}	response.UserProfileMergeResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/duplicates/{id}/merge [post]
+func (h *DuplicateCandidateHandler) Merge(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(ctx, "invalid id", err.Error())
+		return
+	}
+
+	var req request.MergeDuplicateCandidateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "bad request", err.Error())
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		utils.BadRequestResponse(ctx, "bad request", err.Error())
+		return
+	}
+
+	res, err := h.UseCase.Merge(id, uuid.MustParse(req.SurvivingProfileID), userUUID)
+	if err != nil {
+		h.respondError(ctx, "[DuplicateCandidateHandler.Merge] ", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+// Dismiss dismiss a duplicate candidate
+//
+//	@Summary		Dismiss a duplicate candidate
+//	@Description	Marks the pair as different people, it is not reported again
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"Duplicate Candidate ID"
+//	@Success		200	{object}	response.DuplicateCandidateResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/duplicates/{id}/dismiss [post]
+func (h *DuplicateCandidateHandler) Dismiss(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(ctx, "invalid id", err.Error())
+		return
+	}
+
+	res, err := h.UseCase.Dismiss(id, userUUID)
+	if err != nil {
+		h.respondError(ctx, "[DuplicateCandidateHandler.Dismiss] ", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+func (h *DuplicateCandidateHandler) respondError(ctx *gin.Context, prefix string, err error) {
+	if errors.Is(err, usecase.ErrDuplicateCandidateNotFound) {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", err.Error())
+		return
+	}
+	if errors.Is(err, usecase.ErrDuplicateCandidateResolved) {
+		utils.ErrorResponse(ctx, http.StatusConflict, "error", err.Error())
+		return
+	}
+	h.Log.Error(prefix + err.Error())
+	utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+}
+
+func (h *DuplicateCandidateHandler) userID(ctx *gin.Context) (uuid.UUID, bool) {
+	user, err := middleware.GetUser(ctx, h.Log)
+	if err != nil {
+		h.Log.Errorf("Error when getting user: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	if user == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+		return uuid.Nil, false
+	}
+	userUUID, err := h.UserHelper.GetUserId(user)
+	if err != nil {
+		h.Log.Errorf("Error when getting user id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	return userUUID, true
+}
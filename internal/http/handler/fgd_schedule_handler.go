@@ -11,7 +11,9 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -48,6 +50,7 @@ type FgdScheduleHandler struct {
 	ProjectRecruitmentLineUseCase usecase.IProjectRecruitmentLineUseCase
 	DB                            *gorm.DB
 	FgdApplicantUseCase           usecase.IFgdApplicantUseCase
+	UploadService                 service.IUploadService
 }
 
 func NewFgdScheduleHandler(
@@ -60,6 +63,7 @@ func NewFgdScheduleHandler(
 	prlUseCase usecase.IProjectRecruitmentLineUseCase,
 	db *gorm.DB,
 	iaUseCase usecase.IFgdApplicantUseCase,
+	uploadService service.IUploadService,
 ) IFgdScheduleHandler {
 	return &FgdScheduleHandler{
 		Log:                           log,
@@ -71,6 +75,7 @@ func NewFgdScheduleHandler(
 		ProjectRecruitmentLineUseCase: prlUseCase,
 		DB:                            db,
 		FgdApplicantUseCase:           iaUseCase,
+		UploadService:                 uploadService,
 	}
 }
 
@@ -85,7 +90,8 @@ func FgdScheduleHandlerFactory(
 	prlUseCase := usecase.ProjectRecruitmentLineUseCaseFactory(log)
 	db := config.NewDatabase()
 	iaUseCase := usecase.FgdApplicantUseCaseFactory(log, viper)
-	return NewFgdScheduleHandler(log, viper, validate, useCase, userHelper, upUseCase, prlUseCase, db, iaUseCase)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewFgdScheduleHandler(log, viper, validate, useCase, userHelper, upUseCase, prlUseCase, db, iaUseCase, uploadService)
 }
 
 // CreateFgdSchedule creates a new FgdSchedule
@@ -148,6 +154,10 @@ func (h *FgdScheduleHandler) UpdateFgdSchedule(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, uuid.MustParse(req.ID), middleware.OrganizationScopeRepository(h.Log).HasFgdSchedule) {
+		return
+	}
+
 	res, err := h.UseCase.UpdateFgdSchedule(&req)
 	if err != nil {
 		h.Log.Error("[FgdScheduleHandler.UpdateFgdSchedule] " + err.Error())
@@ -197,7 +207,7 @@ func (h *FgdScheduleHandler) FindAllPaginated(ctx *gin.Context) {
 		"created_at": createdAt,
 	}
 
-	FgdSchedules, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort)
+	FgdSchedules, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Error(err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find FgdSchedules", err.Error())
@@ -231,6 +241,10 @@ func (h *FgdScheduleHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasFgdSchedule) {
+		return
+	}
+
 	res, err := h.UseCase.FindByID(parsedID)
 	if err != nil {
 		h.Log.Error("[FgdScheduleHandler.FindByID] " + err.Error())
@@ -268,6 +282,10 @@ func (h *FgdScheduleHandler) DeleteFgdSchedule(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasFgdSchedule) {
+		return
+	}
+
 	err = h.UseCase.DeleteByID(parsedID)
 	if err != nil {
 		h.Log.Error("[FgdScheduleHandler.DeleteFgdSchedule] " + err.Error())
@@ -325,6 +343,10 @@ func (h *FgdScheduleHandler) UpdateStatusFgdSchedule(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, uuid.MustParse(req.ID), middleware.OrganizationScopeRepository(h.Log).HasFgdSchedule) {
+		return
+	}
+
 	res, err := h.UseCase.UpdateStatusFgdSchedule(&req)
 	if err != nil {
 		h.Log.Error("[FgdScheduleHandler.UpdateStatusFgdSchedule] " + err.Error())
@@ -599,6 +621,10 @@ func (h *FgdScheduleHandler) ExportFgdScheduleAnswer(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasFgdSchedule) {
+		return
+	}
+
 	FgdSchedule, err := h.UseCase.FindByIDForAnswer(testScheduleHeaderID, jobPostingUUID)
 	if err != nil {
 		h.Log.Error("[FgdScheduleHandler.ExportFgdScheduleScheduleAnswer] " + err.Error())
@@ -698,7 +724,7 @@ func (h *FgdScheduleHandler) ExportFgdScheduleAnswer(ctx *gin.Context) {
 					if questionResponse.AnswerFile == "" {
 						cellValue = questionResponse.Answer
 					} else {
-						cellValue = h.Viper.GetString("app.url") + questionResponse.AnswerFile
+						cellValue = storage.URL(h.Viper, questionResponse.AnswerFile)
 					}
 
 					if concatenatedValue != "" {
@@ -772,6 +798,10 @@ func (h *FgdScheduleHandler) ExportResultTemplate(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasFgdSchedule) {
+		return
+	}
+
 	FgdSchedule, err := h.UseCase.FindByIDForAnswer(testScheduleHeaderID, jobPostingUUID)
 	if err != nil {
 		h.Log.Error("[FgdScheduleHandler.ExportResultTemplate] " + err.Error())
@@ -878,15 +908,21 @@ func (h *FgdScheduleHandler) ReadResultTemplate(ctx *gin.Context) {
 		return
 	}
 
-	timestamp := time.Now().UnixNano()
-	filePath := fmt.Sprintf("storage/tests/results/%s", strconv.FormatInt(timestamp, 10)+"_"+file.Filename)
-	if err := ctx.SaveUploadedFile(file, filePath); err != nil {
+	if _, err := h.UploadService.Save(file, storage.UPLOAD_PURPOSE_SPREADSHEET, "storage/tests/results"); err != nil {
+		respondUploadError(ctx, h.Log, err)
+		return
+	}
+
+	// read from the upload, the stored copy may not be on this disk
+	src, err := file.Open()
+	if err != nil {
 		h.Log.Error(err)
-		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to save file", err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to open file", err.Error())
 		return
 	}
+	defer src.Close()
 
-	f, err := excelize.OpenFile(filePath)
+	f, err := excelize.OpenReader(src)
 	if err != nil {
 		h.Log.Error(err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to open file", err.Error())
@@ -11,7 +11,9 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -48,6 +50,7 @@ type InterviewHandler struct {
 	ProjectRecruitmentLineUseCase usecase.IProjectRecruitmentLineUseCase
 	DB                            *gorm.DB
 	InterviewApplicantUseCase     usecase.IInterviewApplicantUseCase
+	UploadService                 service.IUploadService
 }
 
 func NewInterviewHandler(
@@ -60,6 +63,7 @@ func NewInterviewHandler(
 	prlUseCase usecase.IProjectRecruitmentLineUseCase,
 	db *gorm.DB,
 	iaUseCase usecase.IInterviewApplicantUseCase,
+	uploadService service.IUploadService,
 ) IInterviewHandler {
 	return &InterviewHandler{
 		Log:                           log,
@@ -71,6 +75,7 @@ func NewInterviewHandler(
 		ProjectRecruitmentLineUseCase: prlUseCase,
 		DB:                            db,
 		InterviewApplicantUseCase:     iaUseCase,
+		UploadService:                 uploadService,
 	}
 }
 
@@ -85,7 +90,8 @@ func InterviewHandlerFactory(
 	prlUseCase := usecase.ProjectRecruitmentLineUseCaseFactory(log)
 	db := config.NewDatabase()
 	iaUseCase := usecase.InterviewApplicantUseCaseFactory(log, viper)
-	return NewInterviewHandler(log, viper, validate, useCase, userHelper, upUseCase, prlUseCase, db, iaUseCase)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewInterviewHandler(log, viper, validate, useCase, userHelper, upUseCase, prlUseCase, db, iaUseCase, uploadService)
 }
 
 // CreateInterview creates a new interview
@@ -148,6 +154,10 @@ func (h *InterviewHandler) UpdateInterview(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, uuid.MustParse(req.ID), middleware.OrganizationScopeRepository(h.Log).HasInterview) {
+		return
+	}
+
 	res, err := h.UseCase.UpdateInterview(&req)
 	if err != nil {
 		h.Log.Error("[InterviewHandler.UpdateInterview] " + err.Error())
@@ -197,7 +207,7 @@ func (h *InterviewHandler) FindAllPaginated(ctx *gin.Context) {
 		"created_at": createdAt,
 	}
 
-	interviews, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort)
+	interviews, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Error(err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find interviews", err.Error())
@@ -231,6 +241,10 @@ func (h *InterviewHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasInterview) {
+		return
+	}
+
 	res, err := h.UseCase.FindByID(parsedID)
 	if err != nil {
 		h.Log.Error("[InterviewHandler.FindByID] " + err.Error())
@@ -268,6 +282,10 @@ func (h *InterviewHandler) DeleteInterview(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasInterview) {
+		return
+	}
+
 	err = h.UseCase.DeleteByID(parsedID)
 	if err != nil {
 		h.Log.Error("[InterviewHandler.DeleteInterview] " + err.Error())
@@ -325,6 +343,10 @@ func (h *InterviewHandler) UpdateStatusInterview(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, uuid.MustParse(req.ID), middleware.OrganizationScopeRepository(h.Log).HasInterview) {
+		return
+	}
+
 	res, err := h.UseCase.UpdateStatusInterview(&req)
 	if err != nil {
 		h.Log.Error("[InterviewHandler.UpdateStatusInterview] " + err.Error())
@@ -599,6 +621,10 @@ func (h *InterviewHandler) ExportInterviewScheduleAnswer(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasInterview) {
+		return
+	}
+
 	interview, err := h.UseCase.FindByIDForAnswer(testScheduleHeaderID, jobPostingUUID)
 	if err != nil {
 		h.Log.Error("[InterviewHandler.ExportInterviewScheduleAnswer] " + err.Error())
@@ -698,7 +724,7 @@ func (h *InterviewHandler) ExportInterviewScheduleAnswer(ctx *gin.Context) {
 					if questionResponse.AnswerFile == "" {
 						cellValue = questionResponse.Answer
 					} else {
-						cellValue = h.Viper.GetString("app.url") + questionResponse.AnswerFile
+						cellValue = storage.URL(h.Viper, questionResponse.AnswerFile)
 					}
 
 					if concatenatedValue != "" {
@@ -772,6 +798,10 @@ func (h *InterviewHandler) ExportResultTemplate(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasInterview) {
+		return
+	}
+
 	interview, err := h.UseCase.FindByIDForAnswer(testScheduleHeaderID, jobPostingUUID)
 	if err != nil {
 		h.Log.Error("[InterviewHandler.ExportResultTemplate] " + err.Error())
@@ -878,15 +908,21 @@ func (h *InterviewHandler) ReadResultTemplate(ctx *gin.Context) {
 		return
 	}
 
-	timestamp := time.Now().UnixNano()
-	filePath := fmt.Sprintf("storage/tests/results/%s", strconv.FormatInt(timestamp, 10)+"_"+file.Filename)
-	if err := ctx.SaveUploadedFile(file, filePath); err != nil {
+	if _, err := h.UploadService.Save(file, storage.UPLOAD_PURPOSE_SPREADSHEET, "storage/tests/results"); err != nil {
+		respondUploadError(ctx, h.Log, err)
+		return
+	}
+
+	// read from the upload, the stored copy may not be on this disk
+	src, err := file.Open()
+	if err != nil {
 		h.Log.Error(err)
-		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to save file", err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to open file", err.Error())
 		return
 	}
+	defer src.Close()
 
-	f, err := excelize.OpenFile(filePath)
+	f, err := excelize.OpenReader(src)
 	if err != nil {
 		h.Log.Error(err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to open file", err.Error())
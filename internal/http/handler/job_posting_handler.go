@@ -9,7 +9,9 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -42,6 +44,7 @@ type JobPostingHandler struct {
 	UseCase            usecase.IJobPostingUseCase
 	UserHelper         helper.IUserHelper
 	UserProfileUseCase usecase.IUserProfileUseCase
+	UploadService      service.IUploadService
 }
 
 func NewJobPostingHandler(
@@ -51,6 +54,7 @@ func NewJobPostingHandler(
 	useCase usecase.IJobPostingUseCase,
 	userHelper helper.IUserHelper,
 	userProfileUseCase usecase.IUserProfileUseCase,
+	uploadService service.IUploadService,
 ) IJobPostingHandler {
 	return &JobPostingHandler{
 		Log:                log,
@@ -59,6 +63,7 @@ func NewJobPostingHandler(
 		UseCase:            useCase,
 		UserHelper:         userHelper,
 		UserProfileUseCase: userProfileUseCase,
+		UploadService:      uploadService,
 	}
 }
 
@@ -70,7 +75,8 @@ func JobPostingHandlerFactory(
 	validate := config.NewValidator(viper)
 	userHelper := helper.UserHelperFactory(log)
 	userProfileUseCase := usecase.UserProfileUseCaseFactory(log, viper)
-	return NewJobPostingHandler(log, viper, validate, useCase, userHelper, userProfileUseCase)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewJobPostingHandler(log, viper, validate, useCase, userHelper, userProfileUseCase, uploadService)
 }
 
 // CreateJobPosting create job posting
@@ -116,11 +122,9 @@ func (h *JobPostingHandler) CreateJobPosting(ctx *gin.Context) {
 
 	// Handle file uploads
 	if req.OrganizationLogo != nil {
-		timestamp := time.Now().UnixNano()
-		organizationLogoPath := "storage/job_posting/logos/" + strconv.FormatInt(timestamp, 10) + "_" + req.OrganizationLogo.Filename
-		if err := ctx.SaveUploadedFile(req.OrganizationLogo, organizationLogoPath); err != nil {
-			h.Log.Error("failed to save organization logo: ", err)
-			utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save organization logo", err.Error())
+		organizationLogoPath, err := h.UploadService.Save(req.OrganizationLogo, storage.UPLOAD_PURPOSE_IMAGE, "storage/job_posting/logos")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		req.OrganizationLogo = nil
@@ -128,11 +132,9 @@ func (h *JobPostingHandler) CreateJobPosting(ctx *gin.Context) {
 	}
 
 	if req.Poster != nil {
-		timestamp := time.Now().UnixNano()
-		posterPath := "storage/job_posting/posters/" + strconv.FormatInt(timestamp, 10) + "_" + req.Poster.Filename
-		if err := ctx.SaveUploadedFile(req.Poster, posterPath); err != nil {
-			h.Log.Error("failed to save poster: ", err)
-			utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save poster", err.Error())
+		posterPath, err := h.UploadService.Save(req.Poster, storage.UPLOAD_PURPOSE_IMAGE, "storage/job_posting/posters")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		req.Poster = nil
@@ -228,7 +230,7 @@ func (h *JobPostingHandler) FindAllPaginated(ctx *gin.Context) {
 		filter["recruitment_type"] = recruitmentType
 	}
 
-	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, filter, userUUID)
+	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, filter, userUUID, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Error("failed to find all paginated job postings: ", err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to find all paginated job postings", err.Error())
@@ -492,6 +494,10 @@ func (h *JobPostingHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedUUID, middleware.OrganizationScopeRepository(h.Log).HasJobPosting) {
+		return
+	}
+
 	res, err := h.UseCase.FindByID(parsedUUID, userUUID)
 	if err != nil {
 		h.Log.Error("failed to find job posting by id: ", err)
@@ -555,11 +561,9 @@ func (h *JobPostingHandler) UpdateJobPosting(ctx *gin.Context) {
 
 	// Handle file uploads
 	if req.OrganizationLogo != nil {
-		timestamp := time.Now().UnixNano()
-		organizationLogoPath := "storage/job_posting/logos/" + strconv.FormatInt(timestamp, 10) + "_" + req.OrganizationLogo.Filename
-		if err := ctx.SaveUploadedFile(req.OrganizationLogo, organizationLogoPath); err != nil {
-			h.Log.Error("failed to save organization logo: ", err)
-			utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save organization logo", err.Error())
+		organizationLogoPath, err := h.UploadService.Save(req.OrganizationLogo, storage.UPLOAD_PURPOSE_IMAGE, "storage/job_posting/logos")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		req.OrganizationLogo = nil
@@ -567,11 +571,9 @@ func (h *JobPostingHandler) UpdateJobPosting(ctx *gin.Context) {
 	}
 
 	if req.Poster != nil {
-		timestamp := time.Now().UnixNano()
-		posterPath := "storage/job_posting/posters/" + strconv.FormatInt(timestamp, 10) + "_" + req.Poster.Filename
-		if err := ctx.SaveUploadedFile(req.Poster, posterPath); err != nil {
-			h.Log.Error("failed to save poster: ", err)
-			utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save poster", err.Error())
+		posterPath, err := h.UploadService.Save(req.Poster, storage.UPLOAD_PURPOSE_IMAGE, "storage/job_posting/posters")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		req.Poster = nil
@@ -585,6 +587,10 @@ func (h *JobPostingHandler) UpdateJobPosting(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedUUID, middleware.OrganizationScopeRepository(h.Log).HasJobPosting) {
+		return
+	}
+
 	parsedBoolDeletedOrganizationLogo, err := strconv.ParseBool(req.DeletedOrganizationLogo)
 	if err != nil {
 		h.Log.Error("failed to parse deleted organization logo: ", err)
@@ -643,6 +649,10 @@ func (h *JobPostingHandler) DeleteJobPosting(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedUUID, middleware.OrganizationScopeRepository(h.Log).HasJobPosting) {
+		return
+	}
+
 	err = h.UseCase.DeleteJobPosting(parsedUUID)
 	if err != nil {
 		h.Log.Error("failed to delete job posting: ", err)
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IMidsuitSyncHandler interface {
+	FindAllPaginated(ctx *gin.Context)
+	FindByDocumentSendingID(ctx *gin.Context)
+	RerunByDocumentSendingID(ctx *gin.Context)
+	PreviewByDocumentSendingID(ctx *gin.Context)
+}
+
+type MidsuitSyncHandler struct {
+	Log      *logrus.Logger
+	Viper    *viper.Viper
+	Validate *validator.Validate
+	UseCase  usecase.IMidsuitSyncUseCase
+}
+
+func NewMidsuitSyncHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IMidsuitSyncUseCase,
+) IMidsuitSyncHandler {
+	return &MidsuitSyncHandler{
+		Log:      log,
+		Viper:    viper,
+		Validate: validate,
+		UseCase:  useCase,
+	}
+}
+
+func MidsuitSyncHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IMidsuitSyncHandler {
+	useCase := usecase.MidsuitSyncUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	return NewMidsuitSyncHandler(log, viper, validate, useCase)
+}
+
+// FindAllPaginated find all midsuit sync jobs
+//
+// @Summary find all midsuit sync jobs
+// @Description find all midsuit sync jobs, optionally filtered by status
+// @Tags Midsuit Sync Jobs
+// @Accept json
+// @Produce json
+// @Param page query int true "Page"
+// @Param page_size query int true "Page Size"
+// @Param status query string false "Status (PENDING, RUNNING, FAILED, COMPLETED)"
+// @Param created_at query string false "Sort"
+// @Success 200 {object} response.MidsuitSyncJobResponse "Success"
+// @Security BearerAuth
+// @Router /midsuit-sync-jobs [get]
+func (h *MidsuitSyncHandler) FindAllPaginated(ctx *gin.Context) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(ctx.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	createdAt := ctx.Query("created_at")
+	if createdAt == "" {
+		createdAt = "DESC"
+	}
+
+	sort := map[string]interface{}{
+		"created_at": createdAt,
+	}
+
+	filter := map[string]interface{}{
+		"status": ctx.Query("status"),
+	}
+
+	res, total, err := h.UseCase.FindAllPaginated(page, pageSize, sort, filter)
+	if err != nil {
+		h.Log.Errorf("[MidsuitSyncHandler.FindAllPaginated] error when finding all paginated: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all midsuit sync jobs", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Midsuit sync jobs found", gin.H{
+		"midsuit_sync_jobs": res,
+		"total":             total,
+	})
+}
+
+// FindByDocumentSendingID find midsuit sync job by document sending id
+//
+// @Summary find midsuit sync job by document sending id
+// @Description find the midsuit sync job of a document sending together with its steps
+// @Tags Midsuit Sync Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Document Sending ID"
+// @Success 200 {object} response.MidsuitSyncJobResponse "Success"
+// @Security BearerAuth
+// @Router /document-sending/{id}/midsuit-sync [get]
+func (h *MidsuitSyncHandler) FindByDocumentSendingID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		utils.BadRequestResponse(ctx, "ID is required", nil)
+		return
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid id", err)
+		return
+	}
+
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasDocumentSending) {
+		return
+	}
+
+	res, err := h.UseCase.FindByDocumentSendingID(parsedID)
+	if err != nil {
+		h.Log.Errorf("[MidsuitSyncHandler.FindByDocumentSendingID] error when finding by document sending id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find midsuit sync job", err.Error())
+		return
+	}
+	if res == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "Midsuit sync job not found", "midsuit sync job not found")
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Midsuit sync job found", res)
+}
+
+// RerunByDocumentSendingID rerun midsuit sync job
+//
+// @Summary rerun midsuit sync job
+// @Description rerun a failed midsuit sync job, completed steps are skipped
+// @Tags Midsuit Sync Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Document Sending ID"
+// @Success 200 {object} response.MidsuitSyncJobResponse "Success"
+// @Security BearerAuth
+// @Router /document-sending/{id}/midsuit-sync/retry [post]
+func (h *MidsuitSyncHandler) RerunByDocumentSendingID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		utils.BadRequestResponse(ctx, "ID is required", nil)
+		return
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid id", err)
+		return
+	}
+
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasDocumentSending) {
+		return
+	}
+
+	res, err := h.UseCase.RerunByDocumentSendingID(parsedID)
+	if err != nil {
+		h.Log.Errorf("[MidsuitSyncHandler.RerunByDocumentSendingID] error when rerunning midsuit sync job: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to rerun midsuit sync job", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Midsuit sync job rerun", res)
+}
+
+// PreviewByDocumentSendingID preview midsuit sync
+//
+// @Summary preview midsuit sync
+// @Description build the payloads that would be sent to midsuit for a document sending, without sending them
+// @Tags Midsuit Sync Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Document Sending ID"
+// @Success 200 {object} response.MidsuitSyncPreviewResponse "Success"
+// @Security BearerAuth
+// @Router /document-sending/{id}/midsuit-sync/preview [get]
+func (h *MidsuitSyncHandler) PreviewByDocumentSendingID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		utils.BadRequestResponse(ctx, "ID is required", nil)
+		return
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		utils.BadRequestResponse(ctx, "Invalid id", err)
+		return
+	}
+
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, parsedID, middleware.OrganizationScopeRepository(h.Log).HasDocumentSending) {
+		return
+	}
+
+	res, err := h.UseCase.PreviewMidsuitSync(parsedID)
+	if err != nil {
+		h.Log.Errorf("[MidsuitSyncHandler.PreviewByDocumentSendingID] error when previewing midsuit sync: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to preview midsuit sync", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Midsuit sync preview", res)
+}
@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IPersonalDataHandler interface {
+	Consent(ctx *gin.Context)
+	FindConsents(ctx *gin.Context)
+	Export(ctx *gin.Context)
+	RequestErasure(ctx *gin.Context)
+	FindLatestErasureRequest(ctx *gin.Context)
+	FindErasureRequestsPaginated(ctx *gin.Context)
+	ApproveErasureRequest(ctx *gin.Context)
+	RejectErasureRequest(ctx *gin.Context)
+}
+
+type PersonalDataHandler struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Validate   *validator.Validate
+	UseCase    usecase.IPersonalDataUseCase
+	UserHelper helper.IUserHelper
+}
+
+func NewPersonalDataHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IPersonalDataUseCase,
+	userHelper helper.IUserHelper,
+) IPersonalDataHandler {
+	return &PersonalDataHandler{
+		Log:        log,
+		Viper:      viper,
+		Validate:   validate,
+		UseCase:    useCase,
+		UserHelper: userHelper,
+	}
+}
+
+func PersonalDataHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IPersonalDataHandler {
+	useCase := usecase.PersonalDataUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	userHelper := helper.UserHelperFactory(log)
+	return NewPersonalDataHandler(log, viper, validate, useCase, userHelper)
+}
+
+// Consent consent to the current privacy notice
+//
+//	@Summary		Consent to the current privacy notice
+//	@Description	Records the consent of the logged in candidate to the current privacy notice version
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body	request.ConsentPrivacyNoticeRequest	true	"Privacy notice version"
+//	@Success		201	{object}	response.PrivacyConsentResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/privacy-consents [post]
+func (h *PersonalDataHandler) Consent(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.ConsentPrivacyNoticeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "bad request", err.Error())
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		utils.BadRequestResponse(ctx, "bad request", err.Error())
+		return
+	}
+
+	res, err := h.UseCase.Consent(userUUID, req.Version, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		h.respondError(ctx, "[PersonalDataHandler.Consent] ", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "success", res)
+}
+
+// FindConsents find the privacy consents of the candidate
+//
+//	@Summary		Find the privacy consents of the candidate
+//	@Description	Find the privacy notice versions the logged in candidate consented to, latest first
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.PrivacyConsentResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/privacy-consents [get]
+func (h *PersonalDataHandler) FindConsents(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := h.UseCase.FindConsents(userUUID)
+	if err != nil {
+		h.respondError(ctx, "[PersonalDataHandler.FindConsents] ", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+// Export export the personal data of the candidate
+//
+//	@Summary		Export the personal data of the candidate
+//	@Description	A ZIP with the profile, applications, answers and consents as JSON and the stored documents of the logged in candidate
+//	@Tags			User Profiles
+//	@Produce		application/zip
+//	@Success		200	{file}	file
+//	@Security		BearerAuth
+//	@Router			/user-profiles/export [get]
+func (h *PersonalDataHandler) Export(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := h.UseCase.Export(userUUID)
+	if err != nil {
+		h.respondError(ctx, "[PersonalDataHandler.Export] ", err)
+		return
+	}
+
+	fileName := "personal-data-" + time.Now().Format("20060102") + ".zip"
+	ctx.Header("Content-Disposition", "attachment; filename="+fileName)
+	ctx.Data(http.StatusOK, "application/zip", data)
+}
+
+// RequestErasure request the erasure of the personal data of the candidate
+//
+//	@Summary		Request the erasure of the personal data of the candidate
+//	@Description	HR reviews the request, approving it anonymises the profile and deletes its documents
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body	request.CreateErasureRequestRequest	false	"Reason"
+//	@Success		201	{object}	response.ErasureRequestResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/erasure-requests [post]
+func (h *PersonalDataHandler) RequestErasure(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.CreateErasureRequestRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.BadRequestResponse(ctx, "bad request", err.Error())
+			return
+		}
+		if err := h.Validate.Struct(req); err != nil {
+			utils.BadRequestResponse(ctx, "bad request", err.Error())
+			return
+		}
+	}
+
+	res, err := h.UseCase.RequestErasure(userUUID, req.Reason)
+	if err != nil {
+		h.respondError(ctx, "[PersonalDataHandler.RequestErasure] ", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "success", res)
+}
+
+// FindLatestErasureRequest find the latest erasure request of the candidate
+//
+//	@Summary		Find the latest erasure request of the candidate
+//	@Description	Find the latest erasure request of the logged in candidate
+//	@Tags			User Profiles
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.ErasureRequestResponse
+//	@Security		BearerAuth
+//	@Router			/user-profiles/erasure-requests/latest [get]
+func (h *PersonalDataHandler) FindLatestErasureRequest(ctx *gin.Context) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := h.UseCase.FindLatestErasureRequest(userUUID)
+	if err != nil {
+		h.respondError(ctx, "[PersonalDataHandler.FindLatestErasureRequest] ", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+// FindErasureRequestsPaginated find erasure requests
+//
+//	@Summary		Find erasure requests
+//	@Description	Find erasure requests, oldest first
+//	@Tags			Erasure Requests
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query	int		false	"Page"
+//	@Param			page_size	query	int		false	"Page Size"
+//	@Param			status		query	string	false	"Status (PENDING, REJECTED, COMPLETED), defaults to PENDING"
+//	@Success		200	{object}	response.ErasureRequestResponse
+//	@Security		BearerAuth
+//	@Router			/erasure-requests [get]
+func (h *PersonalDataHandler) FindErasureRequestsPaginated(ctx *gin.Context) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(ctx.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	status := strings.ToUpper(ctx.Query("status"))
+	if status == "" {
+		status = "PENDING"
+	}
+
+	requests, total, err := h.UseCase.FindErasureRequestsPaginated(page, pageSize, status)
+	if err != nil {
+		h.Log.Error("[PersonalDataHandler.FindErasureRequestsPaginated] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", gin.H{
+		"erasure_requests": requests,
+		"total":            total,
+	})
+}
+
+// ApproveErasureRequest approve an erasure request
+//
+//	@Summary		Approve an erasure request
+//	@Description	Anonymises the profile and deletes its documents. Applications and their statuses are kept for the statistics.
+//	@Tags			Erasure Requests
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string	true	"Erasure Request ID"
+//	@Param			body	body	request.ReviewErasureRequestRequest	false	"Note"
+//	@Success		200	{object}	response.ErasureRequestResponse
+//	@Security		BearerAuth
+//	@Router			/erasure-requests/{id}/approve [post]
+func (h *PersonalDataHandler) ApproveErasureRequest(ctx *gin.Context) {
+	h.reviewErasureRequest(ctx, h.UseCase.ApproveErasureRequest, "[PersonalDataHandler.ApproveErasureRequest] ")
+}
+
+// RejectErasureRequest reject an erasure request
+//
+//	@Summary		Reject an erasure request
+//	@Description	Rejects the request when the data has to be kept, the note tells the candidate why
+//	@Tags			Erasure Requests
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string	true	"Erasure Request ID"
+//	@Param			body	body	request.ReviewErasureRequestRequest	false	"Note"
+//	@Success		200	{object}	response.ErasureRequestResponse
+//	@Security		BearerAuth
+//	@Router			/erasure-requests/{id}/reject [post]
+func (h *PersonalDataHandler) RejectErasureRequest(ctx *gin.Context) {
+	h.reviewErasureRequest(ctx, h.UseCase.RejectErasureRequest, "[PersonalDataHandler.RejectErasureRequest] ")
+}
+
+func (h *PersonalDataHandler) reviewErasureRequest(
+	ctx *gin.Context,
+	review func(id uuid.UUID, reviewedBy uuid.UUID, note string) (*response.ErasureRequestResponse, error),
+	prefix string,
+) {
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(ctx, "invalid id", err.Error())
+		return
+	}
+
+	var req request.ReviewErasureRequestRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.BadRequestResponse(ctx, "bad request", err.Error())
+			return
+		}
+		if err := h.Validate.Struct(req); err != nil {
+			utils.BadRequestResponse(ctx, "bad request", err.Error())
+			return
+		}
+	}
+
+	res, err := review(id, userUUID, req.Note)
+	if err != nil {
+		h.respondError(ctx, prefix, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+func (h *PersonalDataHandler) respondError(ctx *gin.Context, prefix string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrPersonalDataProfileNotFound),
+		errors.Is(err, usecase.ErrErasureRequestNotFound),
+		errors.Is(err, usecase.ErrPrivacyNoticeNotFound):
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", err.Error())
+	case errors.Is(err, usecase.ErrPrivacyNoticeVersionOutdated):
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+	case errors.Is(err, usecase.ErrErasureRequestPending),
+		errors.Is(err, usecase.ErrErasureRequestReviewed):
+		utils.ErrorResponse(ctx, http.StatusConflict, "error", err.Error())
+	default:
+		h.Log.Error(prefix + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+	}
+}
+
+func (h *PersonalDataHandler) userID(ctx *gin.Context) (uuid.UUID, bool) {
+	user, err := middleware.GetUser(ctx, h.Log)
+	if err != nil {
+		h.Log.Errorf("Error when getting user: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	if user == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+		return uuid.Nil, false
+	}
+	userUUID, err := h.UserHelper.GetUserId(user)
+	if err != nil {
+		h.Log.Errorf("Error when getting user id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	return userUUID, true
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IPrivacyNoticeHandler interface {
+	PublishPrivacyNotice(ctx *gin.Context)
+	FindCurrent(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+}
+
+type PrivacyNoticeHandler struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Validate   *validator.Validate
+	UseCase    usecase.IPrivacyNoticeUseCase
+	UserHelper helper.IUserHelper
+}
+
+func NewPrivacyNoticeHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IPrivacyNoticeUseCase,
+	userHelper helper.IUserHelper,
+) IPrivacyNoticeHandler {
+	return &PrivacyNoticeHandler{
+		Log:        log,
+		Viper:      viper,
+		Validate:   validate,
+		UseCase:    useCase,
+		UserHelper: userHelper,
+	}
+}
+
+func PrivacyNoticeHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IPrivacyNoticeHandler {
+	useCase := usecase.PrivacyNoticeUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	userHelper := helper.UserHelperFactory(log)
+	return NewPrivacyNoticeHandler(log, viper, validate, useCase, userHelper)
+}
+
+// PublishPrivacyNotice publish a privacy notice version
+//
+//	@Summary		Publish a privacy notice version
+//	@Description	The published version becomes the current privacy notice, profiles created from then on have to consent to it
+//	@Tags			Privacy Notices
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body	request.PublishPrivacyNoticeRequest	true	"Privacy notice"
+//	@Success		201	{object}	response.PrivacyNoticeResponse
+//	@Security		BearerAuth
+//	@Router			/privacy-notices [post]
+func (h *PrivacyNoticeHandler) PublishPrivacyNotice(ctx *gin.Context) {
+	user, err := middleware.GetUser(ctx, h.Log)
+	if err != nil {
+		h.Log.Errorf("Error when getting user: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+	if user == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+		return
+	}
+	userUUID, err := h.UserHelper.GetUserId(user)
+	if err != nil {
+		h.Log.Errorf("Error when getting user id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	var req request.PublishPrivacyNoticeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(ctx, "bad request", err.Error())
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		utils.BadRequestResponse(ctx, "bad request", err.Error())
+		return
+	}
+
+	res, err := h.UseCase.PublishPrivacyNotice(&req, userUUID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPrivacyNoticeVersionExists) {
+			utils.ErrorResponse(ctx, http.StatusConflict, "error", err.Error())
+			return
+		}
+		h.Log.Error("[PrivacyNoticeHandler.PublishPrivacyNotice] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "success", res)
+}
+
+// FindCurrent find the current privacy notice
+//
+//	@Summary		Find the current privacy notice
+//	@Description	The privacy notice candidates consent to when creating their profile
+//	@Tags			Privacy Notices
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.PrivacyNoticeResponse
+//	@Security		BearerAuth
+//	@Router			/privacy-notices/current [get]
+func (h *PrivacyNoticeHandler) FindCurrent(ctx *gin.Context) {
+	res, err := h.UseCase.FindCurrent()
+	if err != nil {
+		if errors.Is(err, usecase.ErrPrivacyNoticeNotFound) {
+			utils.ErrorResponse(ctx, http.StatusNotFound, "error", err.Error())
+			return
+		}
+		h.Log.Error("[PrivacyNoticeHandler.FindCurrent] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
+
+// FindAll find all privacy notice versions
+//
+//	@Summary		Find all privacy notice versions
+//	@Description	Find all privacy notice versions, latest first
+//	@Tags			Privacy Notices
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.PrivacyNoticeResponse
+//	@Security		BearerAuth
+//	@Router			/privacy-notices [get]
+func (h *PrivacyNoticeHandler) FindAll(ctx *gin.Context) {
+	res, err := h.UseCase.FindAll()
+	if err != nil {
+		h.Log.Error("[PrivacyNoticeHandler.FindAll] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", res)
+}
@@ -1,16 +1,17 @@
 package handler
 
 import (
-	"strconv"
-	"time"
-
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -22,11 +23,12 @@ type IQuestionResponseHandler interface {
 }
 
 type QuestionResponseHandler struct {
-	Log        *logrus.Logger
-	Viper      *viper.Viper
-	Validate   *validator.Validate
-	UseCase    usecase.IQuestionResponseUseCase
-	UserHelper helper.IUserHelper
+	Log           *logrus.Logger
+	Viper         *viper.Viper
+	Validate      *validator.Validate
+	UseCase       usecase.IQuestionResponseUseCase
+	UserHelper    helper.IUserHelper
+	UploadService service.IUploadService
 }
 
 func NewQuestionResponseHandler(
@@ -35,13 +37,15 @@ func NewQuestionResponseHandler(
 	validate *validator.Validate,
 	useCase usecase.IQuestionResponseUseCase,
 	userHelper helper.IUserHelper,
+	uploadService service.IUploadService,
 ) IQuestionResponseHandler {
 	return &QuestionResponseHandler{
-		Log:        log,
-		Viper:      viper,
-		Validate:   validate,
-		UseCase:    useCase,
-		UserHelper: userHelper,
+		Log:           log,
+		Viper:         viper,
+		Validate:      validate,
+		UseCase:       useCase,
+		UserHelper:    userHelper,
+		UploadService: uploadService,
 	}
 }
 
@@ -52,7 +56,8 @@ func QuestionResponseHandlerFactory(
 	useCase := usecase.QuestionResponseUseCaseFactory(log, viper)
 	validate := config.NewValidator(viper)
 	userHelper := helper.UserHelperFactory(log)
-	return NewQuestionResponseHandler(log, viper, validate, useCase, userHelper)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewQuestionResponseHandler(log, viper, validate, useCase, userHelper, uploadService)
 }
 
 // CreateOrUpdateQuestionResponses create or update question responses
@@ -85,6 +90,9 @@ func (h *QuestionResponseHandler) CreateOrUpdateQuestionResponses(ctx *gin.Conte
 	userProfileIDs := ctx.PostFormArray("answers.user_profile_id")
 	answers := ctx.PostFormArray("answers.answer")
 	answerFiles := ctx.Request.MultipartForm.File["answers[][answer_file]"]
+	if !h.authorizeAnswers(ctx, userProfileIDs, ctx.Request.Form["deleted_answer_ids[]"]) {
+		return
+	}
 	// Process each answer
 	var payload request.QuestionResponseRequest
 	payload.QuestionID = questionID
@@ -111,11 +119,9 @@ func (h *QuestionResponseHandler) CreateOrUpdateQuestionResponses(ctx *gin.Conte
 
 		if len(answerFiles) > i {
 			file := answerFiles[i]
-			timestamp := time.Now().UnixNano()
-			filePath := "storage/answers/files/" + strconv.FormatInt(timestamp, 10) + "_" + file.Filename
-			if err := ctx.SaveUploadedFile(file, filePath); err != nil {
-				h.Log.Error("Failed to save answer file: ", err)
-				utils.ErrorResponse(ctx, 500, "error", "Failed to save answer file")
+			filePath, err := h.UploadService.Save(file, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/answers/files")
+			if err != nil {
+				respondUploadError(ctx, h.Log, err)
 				return
 			}
 			answerFilePath = filePath
@@ -153,7 +159,7 @@ func (h *QuestionResponseHandler) CreateOrUpdateQuestionResponses(ctx *gin.Conte
 	// embed url to answer file
 	for i, qr := range *questionResponse.QuestionResponses {
 		if qr.AnswerFile != "" {
-			(*questionResponse.QuestionResponses)[i].AnswerFile = h.Viper.GetString("app.url") + qr.AnswerFile
+			(*questionResponse.QuestionResponses)[i].AnswerFile = storage.URL(h.Viper, qr.AnswerFile)
 		}
 	}
 
@@ -185,6 +191,16 @@ func (h *QuestionResponseHandler) AnswerInterviewQuestionResponses(ctx *gin.Cont
 		return
 	}
 
+	var interviewUserProfileIDs []string
+	for _, question := range payload.Questions {
+		for _, answer := range question.Answers {
+			interviewUserProfileIDs = append(interviewUserProfileIDs, answer.UserProfileID)
+		}
+	}
+	if !h.authorizeAnswers(ctx, interviewUserProfileIDs, payload.DeletedAnswerIDs) {
+		return
+	}
+
 	questionResponse, err := h.UseCase.AnswerInterviewQuestionResponses(&payload)
 	if err != nil {
 		h.Log.Errorf("Error when answering interview question responses: %v", err)
@@ -220,6 +236,16 @@ func (h *QuestionResponseHandler) AnswerFgdQuestionResponses(ctx *gin.Context) {
 		return
 	}
 
+	var fgdUserProfileIDs []string
+	for _, question := range payload.Questions {
+		for _, answer := range question.Answers {
+			fgdUserProfileIDs = append(fgdUserProfileIDs, answer.UserProfileID)
+		}
+	}
+	if !h.authorizeAnswers(ctx, fgdUserProfileIDs, payload.DeletedAnswerIDs) {
+		return
+	}
+
 	questionResponse, err := h.UseCase.AnswerFgdQuestionResponses(&payload)
 	if err != nil {
 		h.Log.Errorf("Error when answering fgd question responses: %v", err)
@@ -229,3 +255,29 @@ func (h *QuestionResponseHandler) AnswerFgdQuestionResponses(ctx *gin.Context) {
 
 	utils.SuccessResponse(ctx, 201, "success answer question", questionResponse)
 }
+
+// authorizeAnswers makes sure candidates only answer for, and delete answers of, their own profile
+func (h *QuestionResponseHandler) authorizeAnswers(ctx *gin.Context, userProfileIDs []string, deletedAnswerIDs []string) bool {
+	var ownerIDs []uuid.UUID
+	for _, id := range userProfileIDs {
+		// malformed ids are rejected by the validator
+		if parsedID, err := uuid.Parse(id); err == nil {
+			ownerIDs = append(ownerIDs, parsedID)
+		}
+	}
+
+	var validDeletedIDs []string
+	for _, id := range deletedAnswerIDs {
+		if _, err := uuid.Parse(id); err == nil {
+			validDeletedIDs = append(validDeletedIDs, id)
+		}
+	}
+	deletedOwnerIDs, err := h.UseCase.FindUserProfileIDsByIDs(validDeletedIDs)
+	if err != nil {
+		h.Log.Errorf("Error when finding question response owners: %v", err)
+		utils.ErrorResponse(ctx, 500, "error", err.Error())
+		return false
+	}
+
+	return middleware.AuthorizeUserProfiles(ctx, h.Log, append(ownerIDs, deletedOwnerIDs...)...)
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/xuri/excelize/v2"
+)
+
+type IRecruitmentFunnelHandler interface {
+	GetFunnel(ctx *gin.Context)
+	ExportFunnel(ctx *gin.Context)
+}
+
+type RecruitmentFunnelHandler struct {
+	Log      *logrus.Logger
+	Viper    *viper.Viper
+	Validate *validator.Validate
+	UseCase  usecase.IRecruitmentFunnelUseCase
+}
+
+func NewRecruitmentFunnelHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IRecruitmentFunnelUseCase,
+) IRecruitmentFunnelHandler {
+	return &RecruitmentFunnelHandler{
+		Log:      log,
+		Viper:    viper,
+		Validate: validate,
+		UseCase:  useCase,
+	}
+}
+
+func RecruitmentFunnelHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IRecruitmentFunnelHandler {
+	useCase := usecase.RecruitmentFunnelUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	return NewRecruitmentFunnelHandler(log, viper, validate, useCase)
+}
+
+// GetFunnel get recruitment funnel
+//
+// @Summary Get recruitment funnel
+// @Description Counts and conversion rates per stage of a job posting or of every job posting of a project, with the average days spent in each stage and the rejection and withdrawal reasons
+// @Tags Dashboard
+// @Accept json
+// @Produce json
+// @Param job_posting_id query string false "Job Posting ID, required without project_recruitment_header_id"
+// @Param project_recruitment_header_id query string false "Project Recruitment Header ID, required without job_posting_id"
+// @Security BearerAuth
+// @Success 200 {object} response.RecruitmentFunnelResponse
+// @Router /dashboard/funnel [get]
+func (h *RecruitmentFunnelHandler) GetFunnel(ctx *gin.Context) {
+	res, ok := h.funnel(ctx)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully get recruitment funnel", res)
+}
+
+// ExportFunnel export recruitment funnel
+//
+// @Summary Export recruitment funnel
+// @Description Exports the recruitment funnel as an XLSX file with a sheet for the stages and one for the rejection reasons
+// @Tags Dashboard
+// @Accept json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param job_posting_id query string false "Job Posting ID, required without project_recruitment_header_id"
+// @Param project_recruitment_header_id query string false "Project Recruitment Header ID, required without job_posting_id"
+// @Security BearerAuth
+// @Router /dashboard/funnel/export [get]
+func (h *RecruitmentFunnelHandler) ExportFunnel(ctx *gin.Context) {
+	res, ok := h.funnel(ctx)
+	if !ok {
+		return
+	}
+
+	f, err := funnelWorkbook(res)
+	if err != nil {
+		h.Log.Errorf("[RecruitmentFunnelHandler.ExportFunnel] error when creating the workbook: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to export recruitment funnel", err.Error())
+		return
+	}
+	defer f.Close()
+
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", "attachment; filename=recruitment_funnel_exported.xlsx")
+	ctx.Header("Content-Transfer-Encoding", "binary")
+
+	if err := f.Write(ctx.Writer); err != nil {
+		h.Log.Errorf("[RecruitmentFunnelHandler.ExportFunnel] error when writing the workbook: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to export recruitment funnel", err.Error())
+		return
+	}
+}
+
+func (h *RecruitmentFunnelHandler) funnel(ctx *gin.Context) (*response.RecruitmentFunnelResponse, bool) {
+	var req request.RecruitmentFunnelRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		h.Log.Errorf("[RecruitmentFunnelHandler] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return nil, false
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[RecruitmentFunnelHandler] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return nil, false
+	}
+
+	res, err := h.UseCase.GetFunnel(&req, middleware.GetOrganizationScope(ctx))
+	if err != nil {
+		if errors.Is(err, usecase.ErrFunnelJobPostingNotFound) || errors.Is(err, usecase.ErrFunnelProjectRecruitmentHeaderNotFound) {
+			utils.ErrorResponse(ctx, http.StatusNotFound, "error", err.Error())
+			return nil, false
+		}
+		h.Log.Errorf("[RecruitmentFunnelHandler] error when getting the funnel: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return nil, false
+	}
+
+	return res, true
+}
+
+func funnelWorkbook(res *response.RecruitmentFunnelResponse) (*excelize.File, error) {
+	f := excelize.NewFile()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Border: []excelize.Border{
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#00FF00"},
+			Pattern: 1,
+		},
+		Font: &excelize.Font{
+			Bold: true,
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	f.SetSheetName("Sheet1", "Funnel")
+	f.SetCellValue("Funnel", "A1", "Project")
+	f.SetCellValue("Funnel", "B1", res.ProjectRecruitmentHeaderName)
+	f.SetCellValue("Funnel", "A2", "Applicants")
+	f.SetCellValue("Funnel", "B2", res.TotalApplicants)
+	f.SetCellValue("Funnel", "A3", "Hired")
+	f.SetCellValue("Funnel", "B3", res.TotalHired)
+	f.SetCellValue("Funnel", "A4", "Hired Rate (%)")
+	f.SetCellValue("Funnel", "B4", res.HiredRate)
+
+	stageHeaders := []string{"Order", "Stage", "Form Type", "Reached", "Passed", "Rejected", "In Progress", "Conversion Rate (%)", "Cumulative Rate (%)", "Average Days"}
+	for i, header := range stageHeaders {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 6)
+		f.SetCellValue("Funnel", cell, header)
+		f.SetCellStyle("Funnel", cell, cell, headerStyle)
+	}
+	for i, stage := range res.Stages {
+		row := i + 7
+		f.SetCellValue("Funnel", fmt.Sprintf("A%d", row), stage.Order)
+		f.SetCellValue("Funnel", fmt.Sprintf("B%d", row), stage.Name)
+		f.SetCellValue("Funnel", fmt.Sprintf("C%d", row), stage.FormType)
+		f.SetCellValue("Funnel", fmt.Sprintf("D%d", row), stage.Reached)
+		f.SetCellValue("Funnel", fmt.Sprintf("E%d", row), stage.Passed)
+		f.SetCellValue("Funnel", fmt.Sprintf("F%d", row), stage.Rejected)
+		f.SetCellValue("Funnel", fmt.Sprintf("G%d", row), stage.InProgress)
+		f.SetCellValue("Funnel", fmt.Sprintf("H%d", row), stage.ConversionRate)
+		f.SetCellValue("Funnel", fmt.Sprintf("I%d", row), stage.CumulativeRate)
+		if stage.AvgDays != nil {
+			f.SetCellValue("Funnel", fmt.Sprintf("J%d", row), *stage.AvgDays)
+		}
+	}
+
+	if _, err := f.NewSheet("Rejection Reasons"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	reasonHeaders := []string{"Type", "Reason", "Stage", "Total"}
+	for i, header := range reasonHeaders {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue("Rejection Reasons", cell, header)
+		f.SetCellStyle("Rejection Reasons", cell, cell, headerStyle)
+	}
+	for i, reason := range res.RejectionReasons {
+		row := i + 2
+		f.SetCellValue("Rejection Reasons", fmt.Sprintf("A%d", row), reason.Type)
+		f.SetCellValue("Rejection Reasons", fmt.Sprintf("B%d", row), reason.Reason)
+		f.SetCellValue("Rejection Reasons", fmt.Sprintf("C%d", row), reason.Stage)
+		f.SetCellValue("Rejection Reasons", fmt.Sprintf("D%d", row), reason.Total)
+	}
+
+	return f, nil
+}
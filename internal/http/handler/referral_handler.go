@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IReferralHandler interface {
+	CreateReferralLink(ctx *gin.Context)
+	FindReferralLinks(ctx *gin.Context)
+	CreateReferral(ctx *gin.Context)
+	FindReferrals(ctx *gin.Context)
+	GetBonusReport(ctx *gin.Context)
+}
+
+type ReferralHandler struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Validate   *validator.Validate
+	UseCase    usecase.IReferralUseCase
+	UserHelper helper.IUserHelper
+}
+
+func NewReferralHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.IReferralUseCase,
+	userHelper helper.IUserHelper,
+) IReferralHandler {
+	return &ReferralHandler{
+		Log:        log,
+		Viper:      viper,
+		Validate:   validate,
+		UseCase:    useCase,
+		UserHelper: userHelper,
+	}
+}
+
+func ReferralHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IReferralHandler {
+	useCase := usecase.ReferralUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	userHelper := helper.UserHelperFactory(log)
+	return NewReferralHandler(log, viper, validate, useCase, userHelper)
+}
+
+// employee is the employee and user of the logged in user, referrals are made by employees only
+func (h *ReferralHandler) employee(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	user, err := middleware.GetUser(ctx, h.Log)
+	if err != nil {
+		h.Log.Errorf("[ReferralHandler.employee] error when getting user: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	if user == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+		return uuid.Nil, uuid.Nil, false
+	}
+	employeeID, err := h.UserHelper.GetEmployeeId(user)
+	if err != nil {
+		h.Log.Errorf("[ReferralHandler.employee] error when getting employee id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusForbidden, "Only employees can refer candidates", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := h.UserHelper.GetUserId(user)
+	if err != nil {
+		h.Log.Errorf("[ReferralHandler.employee] error when getting user id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	return employeeID, userID, true
+}
+
+func (h *ReferralHandler) referralError(ctx *gin.Context, method string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrReferralJobPostingNotFound):
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", err.Error())
+	case errors.Is(err, usecase.ErrReferralJobPostingNotOpen), errors.Is(err, usecase.ErrReferralSelfReferral):
+		utils.ErrorResponse(ctx, http.StatusUnprocessableEntity, "error", err.Error())
+	case errors.Is(err, usecase.ErrReferralAlreadyReferred), errors.Is(err, usecase.ErrReferralCandidateAlreadyApplied):
+		utils.ErrorResponse(ctx, http.StatusConflict, "error", err.Error())
+	default:
+		h.Log.Errorf("[ReferralHandler.%s] %v", method, err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+	}
+}
+
+// CreateReferralLink create referral link
+//
+// @Summary Create referral link
+// @Description Returns the referral link of the logged in employee for an open job posting, making it the first time
+// @Tags Referrals
+// @Accept json
+// @Produce json
+// @Param payload body request.CreateReferralLinkRequest true "Job posting"
+// @Security BearerAuth
+// @Success 200 {object} response.ReferralLinkResponse
+// @Router /referrals/links [post]
+func (h *ReferralHandler) CreateReferralLink(ctx *gin.Context) {
+	var req request.CreateReferralLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.Log.Errorf("[ReferralHandler.CreateReferralLink] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[ReferralHandler.CreateReferralLink] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	employeeID, userID, ok := h.employee(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := h.UseCase.CreateReferralLink(&req, employeeID, userID)
+	if err != nil {
+		h.referralError(ctx, "CreateReferralLink", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully create referral link", res)
+}
+
+// FindReferralLinks find referral links
+//
+// @Summary Find referral links
+// @Description Referral links of the logged in employee
+// @Tags Referrals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} response.ReferralLinkResponse
+// @Router /referrals/links [get]
+func (h *ReferralHandler) FindReferralLinks(ctx *gin.Context) {
+	employeeID, _, ok := h.employee(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := h.UseCase.FindReferralLinks(employeeID)
+	if err != nil {
+		h.referralError(ctx, "FindReferralLinks", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully find referral links", res)
+}
+
+// CreateReferral create referral
+//
+// @Summary Create referral
+// @Description Refers a candidate to an open job posting and emails them the referral link of the logged in employee. A candidate with a profile is linked to it, the others are linked when they apply.
+// @Tags Referrals
+// @Accept json
+// @Produce json
+// @Param payload body request.CreateReferralRequest true "Candidate"
+// @Security BearerAuth
+// @Success 201 {object} response.ReferralResponse
+// @Router /referrals [post]
+func (h *ReferralHandler) CreateReferral(ctx *gin.Context) {
+	var req request.CreateReferralRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.Log.Errorf("[ReferralHandler.CreateReferral] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[ReferralHandler.CreateReferral] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	employeeID, userID, ok := h.employee(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := h.UseCase.CreateReferral(&req, employeeID, userID)
+	if err != nil {
+		h.referralError(ctx, "CreateReferral", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Successfully create referral", res)
+}
+
+// FindReferrals find referrals
+//
+// @Summary Find referrals
+// @Description Candidates referred by the logged in employee and how far each got: not applied, the stage they are in, rejected or hired, with the bonus status once hired
+// @Tags Referrals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} response.ReferralResponse
+// @Router /referrals [get]
+func (h *ReferralHandler) FindReferrals(ctx *gin.Context) {
+	employeeID, _, ok := h.employee(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := h.UseCase.FindReferrals(employeeID)
+	if err != nil {
+		h.referralError(ctx, "FindReferrals", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully find referrals", res)
+}
+
+// GetBonusReport get referral bonus report
+//
+// @Summary Get referral bonus report
+// @Description Hired referrals with whether the referrer is due a bonus, which is once the candidate joined and got through the probation period
+// @Tags Referrals
+// @Accept json
+// @Produce json
+// @Param status query string false "ELIGIBLE, ON_PROBATION or NOT_JOINED"
+// @Security BearerAuth
+// @Success 200 {object} response.ReferralBonusReportResponse
+// @Router /referrals/bonus-eligibility [get]
+func (h *ReferralHandler) GetBonusReport(ctx *gin.Context) {
+	var req request.ReferralBonusReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		h.Log.Errorf("[ReferralHandler.GetBonusReport] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[ReferralHandler.GetBonusReport] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+
+	res, err := h.UseCase.GetBonusReport(&req, middleware.GetOrganizationScope(ctx))
+	if err != nil {
+		h.referralError(ctx, "GetBonusReport", err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully get referral bonus report", res)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ISchedulerHandler interface {
+	FindAllJobs(ctx *gin.Context)
+	FindAllJobRunsPaginated(ctx *gin.Context)
+	RunJob(ctx *gin.Context)
+}
+
+type SchedulerHandler struct {
+	Log      *logrus.Logger
+	Viper    *viper.Viper
+	Validate *validator.Validate
+	UseCase  usecase.ISchedulerUseCase
+}
+
+func NewSchedulerHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.ISchedulerUseCase,
+) ISchedulerHandler {
+	return &SchedulerHandler{
+		Log:      log,
+		Viper:    viper,
+		Validate: validate,
+		UseCase:  useCase,
+	}
+}
+
+func SchedulerHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) ISchedulerHandler {
+	useCase := usecase.SchedulerUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	return NewSchedulerHandler(log, viper, validate, useCase)
+}
+
+// FindAllJobs find all scheduler jobs
+//
+// @Summary find all scheduler jobs
+// @Description registered jobs with their schedule, next run and last run
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.SchedulerJobResponse "Success"
+// @Security BearerAuth
+// @Router /scheduler/jobs [get]
+func (h *SchedulerHandler) FindAllJobs(ctx *gin.Context) {
+	res, err := h.UseCase.FindAllJobs()
+	if err != nil {
+		h.Log.Errorf("[SchedulerHandler.FindAllJobs] error when finding all jobs: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all scheduler jobs", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Scheduler jobs found", res)
+}
+
+// FindAllJobRunsPaginated find all scheduler job runs
+//
+// @Summary find all scheduler job runs
+// @Description run history of the scheduler jobs, optionally filtered by job name and status
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param page query int true "Page"
+// @Param page_size query int true "Page Size"
+// @Param job_name query string false "Job Name"
+// @Param status query string false "Status (RUNNING, SUCCEEDED, FAILED)"
+// @Param created_at query string false "Sort"
+// @Success 200 {object} response.SchedulerJobRunResponse "Success"
+// @Security BearerAuth
+// @Router /scheduler/runs [get]
+func (h *SchedulerHandler) FindAllJobRunsPaginated(ctx *gin.Context) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(ctx.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	createdAt := ctx.Query("created_at")
+	if createdAt == "" {
+		createdAt = "DESC"
+	}
+
+	sort := map[string]interface{}{
+		"created_at": createdAt,
+	}
+
+	filter := map[string]interface{}{
+		"job_name": ctx.Query("job_name"),
+		"status":   ctx.Query("status"),
+	}
+
+	res, total, err := h.UseCase.FindAllJobRunsPaginated(page, pageSize, sort, filter)
+	if err != nil {
+		h.Log.Errorf("[SchedulerHandler.FindAllJobRunsPaginated] error when finding all paginated: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find all scheduler job runs", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Scheduler job runs found", gin.H{
+		"scheduler_job_runs": res,
+		"total":              total,
+	})
+}
+
+// RunJob run a scheduler job now
+//
+// @Summary run a scheduler job now
+// @Description runs the job in the background on this replica, outside of its schedule
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param name path string true "Job Name"
+// @Success 202 {object} response.SchedulerJobRunResponse "Accepted"
+// @Security BearerAuth
+// @Router /scheduler/jobs/{name}/run [post]
+func (h *SchedulerHandler) RunJob(ctx *gin.Context) {
+	name := ctx.Param("name")
+	if name == "" {
+		utils.BadRequestResponse(ctx, "Name is required", nil)
+		return
+	}
+
+	res, err := h.UseCase.RunJob(name)
+	if err != nil {
+		if errors.Is(err, usecase.ErrSchedulerJobNotFound) {
+			utils.ErrorResponse(ctx, http.StatusNotFound, "Scheduler job not found", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrSchedulerJobRunning) {
+			utils.ErrorResponse(ctx, http.StatusConflict, "Scheduler job is already running", err.Error())
+			return
+		}
+		h.Log.Errorf("[SchedulerHandler.RunJob] error when running job: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to run scheduler job", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusAccepted, "Scheduler job started", res)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ISlaHandler interface {
+	FindOverdueQueue(ctx *gin.Context)
+}
+
+type SlaHandler struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Validate   *validator.Validate
+	UseCase    usecase.ISlaUseCase
+	UserHelper helper.IUserHelper
+}
+
+func NewSlaHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.ISlaUseCase,
+	userHelper helper.IUserHelper,
+) ISlaHandler {
+	return &SlaHandler{
+		Log:        log,
+		Viper:      viper,
+		Validate:   validate,
+		UseCase:    useCase,
+		UserHelper: userHelper,
+	}
+}
+
+func SlaHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) ISlaHandler {
+	useCase := usecase.SlaUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	userHelper := helper.UserHelperFactory(log)
+	return NewSlaHandler(log, viper, validate, useCase, userHelper)
+}
+
+// FindOverdueQueue find overdue work queue
+//
+// @Summary Find overdue work queue
+// @Description Applicants sitting in a stage past its SLA and interview or FGD results not filled in time, for the stages an employee is PIC of or assesses. Defaults to the queue of the logged in employee.
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param employee_id query string false "Employee ID"
+// @Param type query string false "STAGE or RESULT"
+// @Security BearerAuth
+// @Success 200 {object} response.SlaOverdueQueueResponse
+// @Router /sla/overdue [get]
+func (h *SlaHandler) FindOverdueQueue(ctx *gin.Context) {
+	var req request.SlaOverdueQueueRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		h.Log.Errorf("[SlaHandler.FindOverdueQueue] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+	if err := h.Validate.Struct(req); err != nil {
+		h.Log.Errorf("[SlaHandler.FindOverdueQueue] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+		return
+	}
+
+	var employeeID uuid.UUID
+	if req.EmployeeID != "" {
+		id, err := uuid.Parse(req.EmployeeID)
+		if err != nil {
+			utils.BadRequestResponse(ctx, "Invalid query parameters", err)
+			return
+		}
+		employeeID = id
+	} else {
+		user, err := middleware.GetUser(ctx, h.Log)
+		if err != nil {
+			h.Log.Errorf("[SlaHandler.FindOverdueQueue] error when getting user: %v", err)
+			utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+			return
+		}
+		if user == nil {
+			utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+			return
+		}
+		employeeID, err = h.UserHelper.GetEmployeeId(user)
+		if err != nil {
+			h.Log.Errorf("[SlaHandler.FindOverdueQueue] error when getting employee id: %v", err)
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "employee_id is required when the user is not an employee", err.Error())
+			return
+		}
+	}
+
+	res, err := h.UseCase.FindOverdueQueue(&req, employeeID, middleware.GetOrganizationScope(ctx))
+	if err != nil {
+		h.Log.Errorf("[SlaHandler.FindOverdueQueue] error when finding the overdue queue: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Successfully find overdue work queue", res)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IStorageHandler interface {
+	Download(ctx *gin.Context)
+	SignURL(ctx *gin.Context)
+}
+
+type StorageHandler struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	UseCase    usecase.IStorageUseCase
+	UserHelper helper.IUserHelper
+}
+
+func NewStorageHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	useCase usecase.IStorageUseCase,
+	userHelper helper.IUserHelper,
+) IStorageHandler {
+	return &StorageHandler{
+		Log:        log,
+		Viper:      viper,
+		UseCase:    useCase,
+		UserHelper: userHelper,
+	}
+}
+
+func StorageHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IStorageHandler {
+	useCase := usecase.StorageUseCaseFactory(log, viper)
+	userHelper := helper.UserHelperFactory(log)
+	return NewStorageHandler(log, viper, useCase, userHelper)
+}
+
+// Download download a stored file
+//
+//	@Summary		Download a stored file
+//	@Description	Serves a file from the storage. Public files (job posting logos and posters) need no signature, any other file needs the expires and signature of a signed link.
+//	@Tags			Files
+//	@Produce		octet-stream
+//	@Param			path		path	string	true	"File path below storage/"
+//	@Param			expires		query	string	false	"Expiry of the signed link (unix seconds)"
+//	@Param			signature	query	string	false	"Signature of the signed link"
+//	@Success		200
+//	@Router			/storage/{path} [get]
+func (h *StorageHandler) Download(ctx *gin.Context) {
+	body, key, err := h.UseCase.Open("storage"+ctx.Param("path"), ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	defer body.Close()
+
+	cacheControl := "private, no-store"
+	if storage.IsPublic(h.Viper, key) {
+		cacheControl = "public, max-age=86400"
+	}
+
+	ctx.DataFromReader(http.StatusOK, -1, storage.ContentType(key), body, map[string]string{
+		"Cache-Control":       cacheControl,
+		"Content-Disposition": "inline; filename=\"" + path.Base(key) + "\"",
+	})
+}
+
+// SignURL create a signed download link
+//
+//	@Summary		Create a signed download link
+//	@Description	Returns a new time-limited link to a stored file, e.g. when a link from an earlier response has expired. Candidates can only link public files and the files of their own profile.
+//	@Tags			Files
+//	@Produce		json
+//	@Param			path	query	string	true	"Stored file path, e.g. storage/user_profiles/ktp/1700000000_ktp.jpg"
+//	@Success		200	{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/api/files/signed-url [get]
+func (h *StorageHandler) SignURL(ctx *gin.Context) {
+	filePath := ctx.Query("path")
+	if filePath == "" {
+		utils.BadRequestResponse(ctx, "path is required", nil)
+		return
+	}
+
+	userUUID, ok := h.userID(ctx)
+	if !ok {
+		return
+	}
+
+	// without the authorization middleware every caller is trusted like staff
+	access, ok := middleware.GetAccess(ctx)
+	staff := !ok || access.IsStaff()
+
+	url, err := h.UseCase.SignURL(filePath, userUUID, staff)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "success", gin.H{"url": url})
+}
+
+func (h *StorageHandler) respondError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrStorageInvalidKey):
+		utils.BadRequestResponse(ctx, err.Error(), nil)
+	case errors.Is(err, storage.ErrStorageSignatureInvalid), errors.Is(err, storage.ErrStorageURLExpired), errors.Is(err, usecase.ErrStorageFileForbidden):
+		utils.ErrorResponse(ctx, http.StatusForbidden, "Forbidden", err.Error())
+	case errors.Is(err, storage.ErrStorageFileNotFound):
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", err.Error())
+	default:
+		h.Log.Error("[StorageHandler] " + err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+	}
+}
+
+func (h *StorageHandler) userID(ctx *gin.Context) (uuid.UUID, bool) {
+	user, err := middleware.GetUser(ctx, h.Log)
+	if err != nil {
+		h.Log.Errorf("Error when getting user: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	if user == nil {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "error", "User not found")
+		return uuid.Nil, false
+	}
+	userUUID, err := h.UserHelper.GetUserId(user)
+	if err != nil {
+		h.Log.Errorf("Error when getting user id: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "error", err.Error())
+		return uuid.Nil, false
+	}
+	return userUUID, true
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -47,6 +49,7 @@ type TestScheduleHeaderHandler struct {
 	ProjectRecruitmentLineUseCase usecase.IProjectRecruitmentLineUseCase
 	DB                            *gorm.DB
 	TestApplicantUseCase          usecase.ITestApplicantUseCase
+	UploadService                 service.IUploadService
 }
 
 func NewTestScheduleHeaderHandler(
@@ -59,6 +62,7 @@ func NewTestScheduleHeaderHandler(
 	prlUseCase usecase.IProjectRecruitmentLineUseCase,
 	db *gorm.DB,
 	taUseCase usecase.ITestApplicantUseCase,
+	uploadService service.IUploadService,
 ) ITestScheduleHeaderHandler {
 	return &TestScheduleHeaderHandler{
 		Log:                           log,
@@ -70,6 +74,7 @@ func NewTestScheduleHeaderHandler(
 		ProjectRecruitmentLineUseCase: prlUseCase,
 		DB:                            db,
 		TestApplicantUseCase:          taUseCase,
+		UploadService:                 uploadService,
 	}
 }
 
@@ -84,7 +89,8 @@ func TestScheduleHeaderHandlerFactory(
 	prlUseCase := usecase.ProjectRecruitmentLineUseCaseFactory(log)
 	db := config.NewDatabase()
 	taUseCase := usecase.TestApplicantUseCaseFactory(log, viper)
-	return NewTestScheduleHeaderHandler(log, viper, validate, useCase, userHelper, upUseCase, prlUseCase, db, taUseCase)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewTestScheduleHeaderHandler(log, viper, validate, useCase, userHelper, upUseCase, prlUseCase, db, taUseCase, uploadService)
 }
 
 // CreateTestScheduleHeader create test schedule header
@@ -147,6 +153,10 @@ func (h *TestScheduleHeaderHandler) UpdateTestScheduleHeader(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, uuid.MustParse(req.ID), middleware.OrganizationScopeRepository(h.Log).HasTestScheduleHeader) {
+		return
+	}
+
 	res, err := h.UseCase.UpdateTestScheduleHeader(&req)
 	if err != nil {
 		h.Log.Error(err)
@@ -196,7 +206,7 @@ func (h *TestScheduleHeaderHandler) FindAllPaginated(ctx *gin.Context) {
 		"created_at": createdAt,
 	}
 
-	testScheduleHeaders, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort)
+	testScheduleHeaders, total, err := h.UseCase.FindAllPaginated(page, pageSize, search, sort, middleware.GetOrganizationScope(ctx))
 	if err != nil {
 		h.Log.Error(err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to find test schedule headers", err.Error())
@@ -235,6 +245,10 @@ func (h *TestScheduleHeaderHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasTestScheduleHeader) {
+		return
+	}
+
 	res, err := h.UseCase.FindByID(testScheduleHeaderID)
 	if err != nil {
 		h.Log.Error(err)
@@ -271,6 +285,10 @@ func (h *TestScheduleHeaderHandler) DeleteTestScheduleHeader(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasTestScheduleHeader) {
+		return
+	}
+
 	err = h.UseCase.DeleteTestScheduleHeader(testScheduleHeaderID)
 	if err != nil {
 		h.Log.Error(err)
@@ -329,6 +347,10 @@ func (h *TestScheduleHeaderHandler) UpdateStatusTestScheduleHeader(ctx *gin.Cont
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, uuid.MustParse(req.ID), middleware.OrganizationScopeRepository(h.Log).HasTestScheduleHeader) {
+		return
+	}
+
 	err := h.UseCase.UpdateStatusTestScheduleHeader(&req)
 	if err != nil {
 		h.Log.Error(err)
@@ -495,6 +517,10 @@ func (h *TestScheduleHeaderHandler) ExportTestScheduleAnswer(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasTestScheduleHeader) {
+		return
+	}
+
 	tsh, err := h.UseCase.FindByIDForAnswer(testScheduleHeaderID, jobPostingUUID)
 	if err != nil {
 		h.Log.Error(err)
@@ -592,7 +618,7 @@ func (h *TestScheduleHeaderHandler) ExportTestScheduleAnswer(ctx *gin.Context) {
 				if questionResponse.AnswerFile == "" {
 					cellValue = questionResponse.Answer
 				} else {
-					cellValue = h.Viper.GetString("app.url") + questionResponse.AnswerFile
+					cellValue = storage.URL(h.Viper, questionResponse.AnswerFile)
 				}
 
 				if concatenatedValue != "" {
@@ -669,6 +695,10 @@ func (h *TestScheduleHeaderHandler) ExportResultTemplate(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeInOrganizationScope(ctx, h.Log, testScheduleHeaderID, middleware.OrganizationScopeRepository(h.Log).HasTestScheduleHeader) {
+		return
+	}
+
 	tsh, err := h.UseCase.FindByIDForAnswer(testScheduleHeaderID, jobPostingUUID)
 	if err != nil {
 		h.Log.Error(err)
@@ -778,15 +808,21 @@ func (h *TestScheduleHeaderHandler) ReadResultTemplate(ctx *gin.Context) {
 		return
 	}
 
-	timestamp := time.Now().UnixNano()
-	filePath := fmt.Sprintf("storage/tests/results/%s", strconv.FormatInt(timestamp, 10)+"_"+file.Filename)
-	if err := ctx.SaveUploadedFile(file, filePath); err != nil {
+	if _, err := h.UploadService.Save(file, storage.UPLOAD_PURPOSE_SPREADSHEET, "storage/tests/results"); err != nil {
+		respondUploadError(ctx, h.Log, err)
+		return
+	}
+
+	// read from the upload, the stored copy may not be on this disk
+	src, err := file.Open()
+	if err != nil {
 		h.Log.Error(err)
-		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to save file", err.Error())
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to open file", err.Error())
 		return
 	}
+	defer src.Close()
 
-	f, err := excelize.OpenFile(filePath)
+	f, err := excelize.OpenReader(src)
 	if err != nil {
 		h.Log.Error(err)
 		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to open file", err.Error())
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ITokenRevocationHandler interface {
+	Logout(ctx *gin.Context)
+	RevokeToken(ctx *gin.Context)
+}
+
+type TokenRevocationHandler struct {
+	Log      *logrus.Logger
+	Viper    *viper.Viper
+	Validate *validator.Validate
+	UseCase  usecase.ITokenRevocationUseCase
+}
+
+func NewTokenRevocationHandler(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	validate *validator.Validate,
+	useCase usecase.ITokenRevocationUseCase,
+) ITokenRevocationHandler {
+	return &TokenRevocationHandler{
+		Log:      log,
+		Viper:    viper,
+		Validate: validate,
+		UseCase:  useCase,
+	}
+}
+
+func TokenRevocationHandlerFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) ITokenRevocationHandler {
+	useCase := usecase.TokenRevocationUseCaseFactory(log, viper)
+	validate := config.NewValidator(viper)
+	return NewTokenRevocationHandler(log, viper, validate, useCase)
+}
+
+// Logout revoke the current token
+//
+// @Summary revoke the current token
+// @Description revoke the token used for this request, it is rejected from now on even before it expires
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.TokenRevocationResponse "Success"
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func (h *TokenRevocationHandler) Logout(ctx *gin.Context) {
+	claims, rawToken, err := middleware.GetTokenClaims(ctx)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	payload := request.RevokeTokenRequest{
+		TokenID: middleware.TokenID(claims, rawToken),
+		Reason:  "logout",
+	}
+	if userID, ok := claims["id"].(string); ok {
+		payload.UserID = userID
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		payload.ExpiresAt = &exp.Time
+	}
+
+	res, err := h.UseCase.RevokeToken(&payload)
+	if err != nil {
+		h.Log.Errorf("[TokenRevocationHandler.Logout] error when revoking token: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to revoke token", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Token revoked", res)
+}
+
+// RevokeToken revoke a token or every token of a user
+//
+// @Summary revoke a token or every token of a user
+// @Description revoke a token by its id (jti), or with only user_id every token of that user issued until now
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body request.RevokeTokenRequest true "Revoke token"
+// @Success 201 {object} response.TokenRevocationResponse "Success"
+// @Security BearerAuth
+// @Router /auth/revoke [post]
+func (h *TokenRevocationHandler) RevokeToken(ctx *gin.Context) {
+	var payload request.RevokeTokenRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		h.Log.Errorf("[TokenRevocationHandler.RevokeToken] error when binding request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	if err := h.Validate.Struct(payload); err != nil {
+		h.Log.Errorf("[TokenRevocationHandler.RevokeToken] error when validating request: %v", err)
+		utils.BadRequestResponse(ctx, "Invalid request payload", err)
+		return
+	}
+
+	res, err := h.UseCase.RevokeToken(&payload)
+	if err != nil {
+		h.Log.Errorf("[TokenRevocationHandler.RevokeToken] error when revoking token: %v", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to revoke token", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Token revoked", res)
+}
@@ -1,12 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -19,20 +20,23 @@ type IUploadHandler interface {
 }
 
 type UploadHandler struct {
-	Log      *logrus.Logger
-	Viper    *viper.Viper
-	Validate *validator.Validate
+	Log           *logrus.Logger
+	Viper         *viper.Viper
+	Validate      *validator.Validate
+	UploadService service.IUploadService
 }
 
 func NewUploadHandler(
 	log *logrus.Logger,
 	viper *viper.Viper,
 	validate *validator.Validate,
+	uploadService service.IUploadService,
 ) IUploadHandler {
 	return &UploadHandler{
-		Log:      log,
-		Viper:    viper,
-		Validate: validate,
+		Log:           log,
+		Viper:         viper,
+		Validate:      validate,
+		UploadService: uploadService,
 	}
 }
 
@@ -41,7 +45,8 @@ func UploadHandlerFactory(
 	viper *viper.Viper,
 ) IUploadHandler {
 	validate := config.NewValidator(viper)
-	return NewUploadHandler(log, viper, validate)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewUploadHandler(log, viper, validate, uploadService)
 }
 
 func (h *UploadHandler) UploadFile(ctx *gin.Context) {
@@ -60,11 +65,9 @@ func (h *UploadHandler) UploadFile(ctx *gin.Context) {
 
 	// handle file upload
 	if req.File != nil {
-		timestamp := time.Now().UnixNano()
-		filePath := "storage/custom/" + strconv.FormatInt(timestamp, 10) + "_" + req.File.Filename
-		if err := ctx.SaveUploadedFile(req.File, filePath); err != nil {
-			h.Log.Error("failed to save file: ", err)
-			utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save file", err.Error())
+		filePath, err := h.UploadService.Save(req.File, storage.UPLOAD_PURPOSE_GENERIC, "storage/custom")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 
@@ -73,7 +76,26 @@ func (h *UploadHandler) UploadFile(ctx *gin.Context) {
 	}
 
 	utils.SuccessResponse(ctx, http.StatusCreated, "success upload file", gin.H{
-		"path":        h.Viper.GetString("app.url") + req.Path,
+		"path":        storage.URL(h.Viper, req.Path),
 		"path_origin": req.Path,
 	})
 }
+
+// respondUploadError answers an upload the UploadService refused or could not store
+func respondUploadError(ctx *gin.Context, log *logrus.Logger, err error) {
+	switch {
+	case errors.Is(err, storage.ErrUploadEmpty):
+		utils.BadRequestResponse(ctx, err.Error(), err.Error())
+	case errors.Is(err, storage.ErrUploadTooLarge):
+		utils.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, "file too large", err.Error())
+	case errors.Is(err, storage.ErrUploadTypeNotAllowed):
+		utils.ErrorResponse(ctx, http.StatusUnsupportedMediaType, "file type not allowed", err.Error())
+	case errors.Is(err, storage.ErrUploadInfected):
+		utils.ErrorResponse(ctx, http.StatusUnprocessableEntity, "file rejected", err.Error())
+	case errors.Is(err, storage.ErrUploadScanUnavailable):
+		utils.ErrorResponse(ctx, http.StatusServiceUnavailable, "error", storage.ErrUploadScanUnavailable.Error())
+	default:
+		log.Error("failed to save file: ", err)
+		utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save file", err.Error())
+	}
+}
@@ -1,15 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -29,11 +31,12 @@ type IUserProfileHandler interface {
 }
 
 type UserProfileHandler struct {
-	Log        *logrus.Logger
-	Viper      *viper.Viper
-	Validate   *validator.Validate
-	UseCase    usecase.IUserProfileUseCase
-	UserHelper helper.IUserHelper
+	Log           *logrus.Logger
+	Viper         *viper.Viper
+	Validate      *validator.Validate
+	UseCase       usecase.IUserProfileUseCase
+	UserHelper    helper.IUserHelper
+	UploadService service.IUploadService
 }
 
 func NewUserProfileHandler(
@@ -42,13 +45,15 @@ func NewUserProfileHandler(
 	validate *validator.Validate,
 	useCase usecase.IUserProfileUseCase,
 	userHelper helper.IUserHelper,
+	uploadService service.IUploadService,
 ) IUserProfileHandler {
 	return &UserProfileHandler{
-		Log:        log,
-		Viper:      viper,
-		Validate:   validate,
-		UseCase:    useCase,
-		UserHelper: userHelper,
+		Log:           log,
+		Viper:         viper,
+		Validate:      validate,
+		UseCase:       useCase,
+		UserHelper:    userHelper,
+		UploadService: uploadService,
 	}
 }
 
@@ -59,7 +64,8 @@ func UserProfileHandlerFactory(
 	useCase := usecase.UserProfileUseCaseFactory(log, viper)
 	validate := config.NewValidator(viper)
 	userHelper := helper.UserHelperFactory(log)
-	return NewUserProfileHandler(log, viper, validate, useCase, userHelper)
+	uploadService := service.UploadServiceFactory(viper, log)
+	return NewUserProfileHandler(log, viper, validate, useCase, userHelper, uploadService)
 }
 
 // FillUserProfile fill user profile
@@ -81,6 +87,8 @@ func UserProfileHandlerFactory(
 //	 @Param      address       formData  string        false "Address"
 //		@Param			curriculum_vitae	formData	file					false	"Curriculum Vitae"
 //		@Param			ktp_path			formData	string					false	"KTP Path"
+//		@Param			ktp_number			formData	string					false	"KTP Number (NIK)"
+//		@Param			privacy_notice_version	formData	string				false	"Version of the privacy notice agreed to, required when creating a profile"
 //		@Param			cv_path				formData	string					false	"CV Path"
 //		@Param			work_experiences.id					formData	string	false	"Work Experience ID"
 //		@Param			work_experiences.name				formData	string	false	"Work Experience Name"
@@ -150,6 +158,14 @@ func (h *UserProfileHandler) FillUserProfile(ctx *gin.Context) {
 	payload.ExpectedSalary, _ = strconv.Atoi(ctx.PostForm("expected_salary"))
 	payload.CurrentSalary, _ = strconv.Atoi(ctx.PostForm("current_salary"))
 	payload.Religion = ctx.PostForm("religion")
+	payload.KtpNumber = ctx.PostForm("ktp_number")
+	payload.PrivacyNoticeVersion = ctx.PostForm("privacy_notice_version")
+	payload.ConsentIPAddress = ctx.ClientIP()
+	payload.ConsentUserAgent = ctx.Request.UserAgent()
+	// the email of the account, kept on the profile to find candidates with several accounts
+	if userEmail, err := h.UserHelper.GetUserEmail(user); err == nil {
+		payload.Email = userEmail
+	}
 	if files, ok := ctx.Request.MultipartForm.File["ktp"]; ok && len(files) > 0 {
 		payload.Ktp = files[0]
 	} else {
@@ -178,11 +194,9 @@ func (h *UserProfileHandler) FillUserProfile(ctx *gin.Context) {
 			var certificatePath string
 			file, err := ctx.FormFile("work_experiences.certificate[" + strconv.Itoa(i) + "]")
 			if err == nil && file != nil {
-				timestamp := time.Now().UnixNano()
-				certificatePath = "storage/user_profiles/work_experience/certificate/" + strconv.FormatInt(timestamp, 10) + "_" + file.Filename
-				if err := ctx.SaveUploadedFile(file, certificatePath); err != nil {
-					h.Log.Error("failed to save work experience certificate: ", err)
-					utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save work experience certificate", err.Error())
+				certificatePath, err = h.UploadService.Save(file, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/user_profiles/work_experience/certificate")
+				if err != nil {
+					respondUploadError(ctx, h.Log, err)
 					return
 				}
 			}
@@ -243,11 +257,9 @@ func (h *UserProfileHandler) FillUserProfile(ctx *gin.Context) {
 		var certificatePath string
 		file, err := ctx.FormFile("educations.certificate[" + strconv.Itoa(i) + "]")
 		if err == nil && file != nil {
-			timestamp := time.Now().UnixNano()
-			certificatePath = "storage/user_profiles/education/certificate/" + strconv.FormatInt(timestamp, 10) + "_" + file.Filename
-			if err := ctx.SaveUploadedFile(file, certificatePath); err != nil {
-				h.Log.Error("failed to save education certificate: ", err)
-				utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save education certificate", err.Error())
+			certificatePath, err = h.UploadService.Save(file, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/user_profiles/education/certificate")
+			if err != nil {
+				respondUploadError(ctx, h.Log, err)
 				return
 			}
 		}
@@ -306,11 +318,9 @@ func (h *UserProfileHandler) FillUserProfile(ctx *gin.Context) {
 		var certificatePath string
 		file, err := ctx.FormFile("skills.certificate[" + strconv.Itoa(i) + "]")
 		if err == nil && file != nil {
-			timestamp := time.Now().UnixNano()
-			certificatePath = "storage/user_profiles/skill/certificate/" + strconv.FormatInt(timestamp, 10) + "_" + file.Filename
-			if err := ctx.SaveUploadedFile(file, certificatePath); err != nil {
-				h.Log.Error("failed to save skill certificate: ", err)
-				utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save skill certificate", err.Error())
+			certificatePath, err = h.UploadService.Save(file, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/user_profiles/skill/certificate")
+			if err != nil {
+				respondUploadError(ctx, h.Log, err)
 				return
 			}
 		}
@@ -358,15 +368,26 @@ func (h *UserProfileHandler) FillUserProfile(ctx *gin.Context) {
 		return
 	}
 
+	// only the owner of a profile, or staff, may update it
+	if payload.ID != "" {
+		profileID, err := uuid.Parse(payload.ID)
+		if err != nil {
+			h.Log.Error("[UserProfileHandler.FillUserProfile] " + err.Error())
+			utils.BadRequestResponse(ctx, "id is not a valid UUID", err.Error())
+			return
+		}
+		if !middleware.AuthorizeUserProfiles(ctx, h.Log, profileID) {
+			return
+		}
+	}
+
 	h.Log.Infof("Isi payload: %v", payload)
 
 	// handle file uploads
 	if payload.Ktp != nil {
-		timestamp := time.Now().UnixNano()
-		ktpPath := "storage/user_profiles/ktp/" + strconv.FormatInt(timestamp, 10) + "_" + payload.Ktp.Filename
-		if err := ctx.SaveUploadedFile(payload.Ktp, ktpPath); err != nil {
-			h.Log.Error("failed to save organization logo: ", err)
-			utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save organization logo", err.Error())
+		ktpPath, err := h.UploadService.Save(payload.Ktp, storage.UPLOAD_PURPOSE_DOCUMENT, "storage/user_profiles/ktp")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		payload.Ktp = nil
@@ -374,11 +395,9 @@ func (h *UserProfileHandler) FillUserProfile(ctx *gin.Context) {
 	}
 
 	if payload.CurriculumVitae != nil {
-		timestamp := time.Now().UnixNano()
-		cvPath := "storage/user_profiles/cv/" + strconv.FormatInt(timestamp, 10) + "_" + payload.CurriculumVitae.Filename
-		if err := ctx.SaveUploadedFile(payload.CurriculumVitae, cvPath); err != nil {
-			h.Log.Error("failed to save organization logo: ", err)
-			utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save organization logo", err.Error())
+		cvPath, err := h.UploadService.Save(payload.CurriculumVitae, storage.UPLOAD_PURPOSE_CURRICULUM_VITAE, "storage/user_profiles/cv")
+		if err != nil {
+			respondUploadError(ctx, h.Log, err)
 			return
 		}
 		payload.CurriculumVitae = nil
@@ -387,6 +406,10 @@ func (h *UserProfileHandler) FillUserProfile(ctx *gin.Context) {
 
 	up, err := h.UseCase.FillUserProfile(&payload, userUUID)
 	if err != nil {
+		if errors.Is(err, usecase.ErrPrivacyConsentRequired) {
+			utils.BadRequestResponse(ctx, err.Error(), nil)
+			return
+		}
 		h.Log.Error("[UserProfileHandler.FillUserProfile] " + err.Error())
 		utils.ErrorResponse(ctx, 500, "internal server error", err.Error())
 		return
@@ -486,6 +509,10 @@ func (h *UserProfileHandler) FindByID(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeUserProfiles(ctx, h.Log, userProfile.ID) {
+		return
+	}
+
 	utils.SuccessResponse(ctx, 200, "success", userProfile)
 }
 
@@ -641,6 +668,10 @@ func (h *UserProfileHandler) UpdateAvatar(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeUserProfiles(ctx, h.Log, parsedID) {
+		return
+	}
+
 	avatar, err := ctx.FormFile("avatar")
 	if err != nil {
 		h.Log.Error("[UserProfileHandler.UpdateAvatar] " + err.Error())
@@ -648,11 +679,9 @@ func (h *UserProfileHandler) UpdateAvatar(ctx *gin.Context) {
 		return
 	}
 
-	timestamp := time.Now().UnixNano()
-	avatarPath := "storage/user_profiles/avatar/" + strconv.FormatInt(timestamp, 10) + "_" + avatar.Filename
-	if err := ctx.SaveUploadedFile(avatar, avatarPath); err != nil {
-		h.Log.Error("failed to save avatar: ", err)
-		utils.ErrorResponse(ctx, http.StatusInternalServerError, "failed to save avatar", err.Error())
+	avatarPath, err := h.UploadService.Save(avatar, storage.UPLOAD_PURPOSE_AVATAR, "storage/user_profiles/avatar")
+	if err != nil {
+		respondUploadError(ctx, h.Log, err)
 		return
 	}
 
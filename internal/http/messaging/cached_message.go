@@ -0,0 +1,382 @@
+package messaging
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+)
+
+// The cached messages below wrap the plain RabbitMQ messages and keep the
+// results of read-only lookups in the shared LookupCache. Every other method
+// is passed through to the embedded message untouched.
+
+type CachedOrganizationMessage struct {
+	IOrganizationMessage
+	Cache ILookupCache
+}
+
+func NewCachedOrganizationMessage(message IOrganizationMessage, cache ILookupCache) IOrganizationMessage {
+	return &CachedOrganizationMessage{
+		IOrganizationMessage: message,
+		Cache:                cache,
+	}
+}
+
+func (m *CachedOrganizationMessage) SendFindOrganizationByIDMessage(req request.SendFindOrganizationByIDMessageRequest) (*response.SendFindOrganizationByIDMessageResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_ORGANIZATION + req.ID); ok {
+		organization := *cached.(*response.SendFindOrganizationByIDMessageResponse)
+		return &organization, nil
+	}
+
+	organization, err := m.IOrganizationMessage.SendFindOrganizationByIDMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_ORGANIZATION+req.ID, organization)
+	return organization, nil
+}
+
+func (m *CachedOrganizationMessage) SendFindOrganizationLocationByIDMessage(req request.SendFindOrganizationLocationByIDMessageRequest) (*response.SendFindOrganizationLocationByIDMessageResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_ORGANIZATION_LOCATION + req.ID); ok {
+		organizationLocation := *cached.(*response.SendFindOrganizationLocationByIDMessageResponse)
+		return &organizationLocation, nil
+	}
+
+	organizationLocation, err := m.IOrganizationMessage.SendFindOrganizationLocationByIDMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_ORGANIZATION_LOCATION+req.ID, organizationLocation)
+	return organizationLocation, nil
+}
+
+func (m *CachedOrganizationMessage) SendFindOrganizationStructureByIDMessage(req request.SendFindOrganizationStructureByIDMessageRequest) (*response.SendFindOrganizationStructureByIDMessageResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_ORGANIZATION_STRUCTURE + req.ID); ok {
+		organizationStructure := *cached.(*response.SendFindOrganizationStructureByIDMessageResponse)
+		return &organizationStructure, nil
+	}
+
+	organizationStructure, err := m.IOrganizationMessage.SendFindOrganizationStructureByIDMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_ORGANIZATION_STRUCTURE+req.ID, organizationStructure)
+	return organizationStructure, nil
+}
+
+func (m *CachedOrganizationMessage) SendFindOrganizationsByIDsMessage(ids []string) (map[string]*response.SendFindOrganizationByIDMessageResponse, error) {
+	result := make(map[string]*response.SendFindOrganizationByIDMessageResponse)
+	missingIDs := make([]string, 0)
+	for _, id := range uniqueIDs(ids) {
+		if cached, ok := m.Cache.Get(CACHE_PREFIX_ORGANIZATION + id); ok {
+			organization := *cached.(*response.SendFindOrganizationByIDMessageResponse)
+			result[id] = &organization
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+
+	if len(missingIDs) == 0 {
+		return result, nil
+	}
+
+	organizations, err := m.IOrganizationMessage.SendFindOrganizationsByIDsMessage(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, organization := range organizations {
+		m.Cache.Set(CACHE_PREFIX_ORGANIZATION+id, organization)
+		result[id] = organization
+	}
+
+	return result, nil
+}
+
+func (m *CachedOrganizationMessage) SendFindOrganizationLocationsByIDsMessage(ids []string) (map[string]*response.SendFindOrganizationLocationByIDMessageResponse, error) {
+	result := make(map[string]*response.SendFindOrganizationLocationByIDMessageResponse)
+	missingIDs := make([]string, 0)
+	for _, id := range uniqueIDs(ids) {
+		if cached, ok := m.Cache.Get(CACHE_PREFIX_ORGANIZATION_LOCATION + id); ok {
+			organizationLocation := *cached.(*response.SendFindOrganizationLocationByIDMessageResponse)
+			result[id] = &organizationLocation
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+
+	if len(missingIDs) == 0 {
+		return result, nil
+	}
+
+	organizationLocations, err := m.IOrganizationMessage.SendFindOrganizationLocationsByIDsMessage(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, organizationLocation := range organizationLocations {
+		m.Cache.Set(CACHE_PREFIX_ORGANIZATION_LOCATION+id, organizationLocation)
+		result[id] = organizationLocation
+	}
+
+	return result, nil
+}
+
+type CachedJobPlafonMessage struct {
+	IJobPlafonMessage
+	Cache ILookupCache
+}
+
+func NewCachedJobPlafonMessage(message IJobPlafonMessage, cache ILookupCache) IJobPlafonMessage {
+	return &CachedJobPlafonMessage{
+		IJobPlafonMessage: message,
+		Cache:             cache,
+	}
+}
+
+func (m *CachedJobPlafonMessage) SendFindJobByIDMessage(req request.SendFindJobByIDMessageRequest) (*response.SendFindJobByIDMessageResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_JOB + req.ID); ok {
+		job := *cached.(*response.SendFindJobByIDMessageResponse)
+		return &job, nil
+	}
+
+	job, err := m.IJobPlafonMessage.SendFindJobByIDMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_JOB+req.ID, job)
+	return job, nil
+}
+
+func (m *CachedJobPlafonMessage) SendFindJobLevelByIDMessage(req request.SendFindJobLevelByIDMessageRequest) (*response.SendFindJobLevelByIDMessageResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_JOB_LEVEL + req.ID); ok {
+		jobLevel := *cached.(*response.SendFindJobLevelByIDMessageResponse)
+		return &jobLevel, nil
+	}
+
+	jobLevel, err := m.IJobPlafonMessage.SendFindJobLevelByIDMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_JOB_LEVEL+req.ID, jobLevel)
+	return jobLevel, nil
+}
+
+func (m *CachedJobPlafonMessage) SendFindJobsByIDsMessage(ids []string) (map[string]*response.SendFindJobByIDMessageResponse, error) {
+	result := make(map[string]*response.SendFindJobByIDMessageResponse)
+	missingIDs := make([]string, 0)
+	for _, id := range uniqueIDs(ids) {
+		if cached, ok := m.Cache.Get(CACHE_PREFIX_JOB + id); ok {
+			job := *cached.(*response.SendFindJobByIDMessageResponse)
+			result[id] = &job
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+
+	if len(missingIDs) == 0 {
+		return result, nil
+	}
+
+	jobs, err := m.IJobPlafonMessage.SendFindJobsByIDsMessage(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, job := range jobs {
+		m.Cache.Set(CACHE_PREFIX_JOB+id, job)
+		result[id] = job
+	}
+
+	return result, nil
+}
+
+func (m *CachedJobPlafonMessage) SendFindJobLevelsByIDsMessage(ids []string) (map[string]*response.SendFindJobLevelByIDMessageResponse, error) {
+	result := make(map[string]*response.SendFindJobLevelByIDMessageResponse)
+	missingIDs := make([]string, 0)
+	for _, id := range uniqueIDs(ids) {
+		if cached, ok := m.Cache.Get(CACHE_PREFIX_JOB_LEVEL + id); ok {
+			jobLevel := *cached.(*response.SendFindJobLevelByIDMessageResponse)
+			result[id] = &jobLevel
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+
+	if len(missingIDs) == 0 {
+		return result, nil
+	}
+
+	jobLevels, err := m.IJobPlafonMessage.SendFindJobLevelsByIDsMessage(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, jobLevel := range jobLevels {
+		m.Cache.Set(CACHE_PREFIX_JOB_LEVEL+id, jobLevel)
+		result[id] = jobLevel
+	}
+
+	return result, nil
+}
+
+type CachedGradeMessage struct {
+	IGradeMessage
+	Cache ILookupCache
+}
+
+func NewCachedGradeMessage(message IGradeMessage, cache ILookupCache) IGradeMessage {
+	return &CachedGradeMessage{
+		IGradeMessage: message,
+		Cache:         cache,
+	}
+}
+
+func (m *CachedGradeMessage) SendFindByIDMessage(id string) (*response.GradeResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_GRADE + id); ok {
+		grade := *cached.(*response.GradeResponse)
+		return &grade, nil
+	}
+
+	grade, err := m.IGradeMessage.SendFindByIDMessage(id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_GRADE+id, grade)
+	return grade, nil
+}
+
+func (m *CachedGradeMessage) SendFindByIDsMessage(ids []string) (map[string]*response.GradeResponse, error) {
+	result := make(map[string]*response.GradeResponse)
+	missingIDs := make([]string, 0)
+	for _, id := range uniqueIDs(ids) {
+		if cached, ok := m.Cache.Get(CACHE_PREFIX_GRADE + id); ok {
+			grade := *cached.(*response.GradeResponse)
+			result[id] = &grade
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+
+	if len(missingIDs) == 0 {
+		return result, nil
+	}
+
+	grades, err := m.IGradeMessage.SendFindByIDsMessage(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, grade := range grades {
+		m.Cache.Set(CACHE_PREFIX_GRADE+id, grade)
+		result[id] = grade
+	}
+
+	return result, nil
+}
+
+type CachedEmployeeMessage struct {
+	IEmployeeMessage
+	Cache ILookupCache
+}
+
+func NewCachedEmployeeMessage(message IEmployeeMessage, cache ILookupCache) IEmployeeMessage {
+	return &CachedEmployeeMessage{
+		IEmployeeMessage: message,
+		Cache:            cache,
+	}
+}
+
+func (m *CachedEmployeeMessage) SendFindEmployeeByIDMessage(req request.SendFindEmployeeByIDMessageRequest) (*response.EmployeeResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_EMPLOYEE + req.ID); ok {
+		employee := *cached.(*response.EmployeeResponse)
+		return &employee, nil
+	}
+
+	employee, err := m.IEmployeeMessage.SendFindEmployeeByIDMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_EMPLOYEE+req.ID, employee)
+	return employee, nil
+}
+
+func (m *CachedEmployeeMessage) SendFindEmployeesByIDsMessage(ids []string) (map[string]*response.EmployeeResponse, error) {
+	result := make(map[string]*response.EmployeeResponse)
+	missingIDs := make([]string, 0)
+	for _, id := range uniqueIDs(ids) {
+		if cached, ok := m.Cache.Get(CACHE_PREFIX_EMPLOYEE + id); ok {
+			employee := *cached.(*response.EmployeeResponse)
+			result[id] = &employee
+			continue
+		}
+		missingIDs = append(missingIDs, id)
+	}
+
+	if len(missingIDs) == 0 {
+		return result, nil
+	}
+
+	employees, err := m.IEmployeeMessage.SendFindEmployeesByIDsMessage(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, employee := range employees {
+		m.Cache.Set(CACHE_PREFIX_EMPLOYEE+id, employee)
+		result[id] = employee
+	}
+
+	return result, nil
+}
+
+// SendUpdateEmployeeMidsuitMessage changes the employee, so the cached copy is
+// dropped once the update went through.
+func (m *CachedEmployeeMessage) SendUpdateEmployeeMidsuitMessage(id string, midsuitID string) (*string, error) {
+	res, err := m.IEmployeeMessage.SendUpdateEmployeeMidsuitMessage(id, midsuitID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Delete(CACHE_PREFIX_EMPLOYEE + id)
+	return res, nil
+}
+
+type CachedUserMessage struct {
+	IUserMessage
+	Cache ILookupCache
+}
+
+func NewCachedUserMessage(message IUserMessage, cache ILookupCache) IUserMessage {
+	return &CachedUserMessage{
+		IUserMessage: message,
+		Cache:        cache,
+	}
+}
+
+func (m *CachedUserMessage) SendFindUserByIDMessage(req request.SendFindUserByIDMessageRequest) (*response.SendFindUserByIDResponse, error) {
+	if cached, ok := m.Cache.Get(CACHE_PREFIX_USER + req.ID); ok {
+		user := *cached.(*response.SendFindUserByIDResponse)
+		return &user, nil
+	}
+
+	user, err := m.IUserMessage.SendFindUserByIDMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Cache.Set(CACHE_PREFIX_USER+req.ID, user)
+	return user, nil
+}
+
+// SendGetUserMe is not cached: it carries the roles and permissions of the user, which must
+// take effect as soon as they are changed
+func (m *CachedUserMessage) SendGetUserMe(req request.SendFindUserByIDMessageRequest) (*response.SendGetUserMeResponse, error) {
+	return m.IUserMessage.SendGetUserMe(req)
+}
@@ -18,6 +18,7 @@ type IEmployeeMessage interface {
 	SendCreateEmployeeTaskMessage(req request.SendCreateEmployeeTaskMessageRequest) (*string, error)
 	SendGetChartEmployeeOrganizationStructureMessage() (*response.ChartDepartmentResponse, error)
 	SendUpdateEmployeeMidsuitMessage(id string, midsuidID string) (*string, error)
+	SendFindEmployeesByIDsMessage(ids []string) (map[string]*response.EmployeeResponse, error)
 }
 
 type EmployeeMessage struct {
@@ -45,8 +46,8 @@ func (m *EmployeeMessage) SendFindEmployeeByIDMessage(req request.SendFindEmploy
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -97,8 +98,39 @@ func convertInterfaceToEmployeeResponse(data map[string]interface{}) *response.E
 		MidsuitID:      midsuitID,
 	}
 }
+// SendFindEmployeesByIDsMessage falls back to one lookup per ID when the peer
+// does not understand the batch message.
+func (m *EmployeeMessage) SendFindEmployeesByIDsMessage(ids []string) (map[string]*response.EmployeeResponse, error) {
+	ids = uniqueIDs(ids)
+	result := make(map[string]*response.EmployeeResponse, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	items, err := sendLookupBatchMessage("find_employees_by_ids", "employees", ids)
+	if err != nil {
+		m.Log.Warnf("[EmployeeMessage.SendFindEmployeesByIDsMessage] batch lookup failed, falling back: %s", err.Error())
+		for _, id := range ids {
+			employee, err := m.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{ID: id})
+			if err != nil {
+				m.Log.Errorf("[EmployeeMessage.SendFindEmployeesByIDsMessage] %s", err.Error())
+				continue
+			}
+			result[id] = employee
+		}
+		return result, nil
+	}
+
+	for _, item := range items {
+		employee := convertInterfaceToEmployeeResponse(item)
+		result[employee.ID.String()] = employee
+	}
+
+	return result, nil
+}
+
 func EmployeeMessageFactory(log *logrus.Logger) IEmployeeMessage {
-	return NewEmployeeMessage(log)
+	return NewCachedEmployeeMessage(NewEmployeeMessage(log), LookupCacheFactory())
 }
 
 func (m *EmployeeMessage) SendCreateEmployeeMessage(req request.SendCreateEmployeeMessageRequest) (*string, error) {
@@ -123,8 +155,8 @@ func (m *EmployeeMessage) SendCreateEmployeeMessage(req request.SendCreateEmploy
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -176,8 +208,8 @@ func (m *EmployeeMessage) SendCreateEmployeeTaskMessage(req request.SendCreateEm
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -220,8 +252,8 @@ func (m *EmployeeMessage) SendGetChartEmployeeOrganizationStructureMessage() (*r
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -303,8 +335,8 @@ func (m *EmployeeMessage) SendUpdateEmployeeMidsuitMessage(id string, midsuitID
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
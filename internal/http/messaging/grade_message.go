@@ -13,6 +13,7 @@ import (
 
 type IGradeMessage interface {
 	SendFindByIDMessage(id string) (*response.GradeResponse, error)
+	SendFindByIDsMessage(ids []string) (map[string]*response.GradeResponse, error)
 }
 
 type GradeMessage struct {
@@ -40,8 +41,8 @@ func (m *GradeMessage) SendFindByIDMessage(id string) (*response.GradeResponse,
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -73,6 +74,43 @@ func (m *GradeMessage) SendFindByIDMessage(id string) (*response.GradeResponse,
 	return grade, nil
 }
 
+// SendFindByIDsMessage falls back to one lookup per ID when the peer does not
+// understand the batch message.
+func (m *GradeMessage) SendFindByIDsMessage(ids []string) (map[string]*response.GradeResponse, error) {
+	ids = uniqueIDs(ids)
+	result := make(map[string]*response.GradeResponse, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	items, err := sendLookupBatchMessage("find_grades_by_ids", "grades", ids)
+	if err != nil {
+		m.Log.Warnf("[GradeMessage.SendFindByIDsMessage] batch lookup failed, falling back: %s", err.Error())
+		for _, id := range ids {
+			grade, err := m.SendFindByIDMessage(id)
+			if err != nil {
+				m.Log.Errorf("[GradeMessage.SendFindByIDsMessage] %s", err.Error())
+				continue
+			}
+			result[id] = grade
+		}
+		return result, nil
+	}
+
+	for _, item := range items {
+		grade := &response.GradeResponse{
+			ID:           mapString(item, "id"),
+			Name:         mapString(item, "name"),
+			JobLevelID:   mapString(item, "job_level_id"),
+			JobLevelName: mapString(item, "job_level_name"),
+			MidsuitID:    mapString(item, "midsuit_id"),
+		}
+		result[grade.ID] = grade
+	}
+
+	return result, nil
+}
+
 func GradeMessageFactory(log *logrus.Logger) IGradeMessage {
-	return NewGradeMessage(log)
+	return NewCachedGradeMessage(NewGradeMessage(log), LookupCacheFactory())
 }
@@ -19,6 +19,8 @@ type IJobPlafonMessage interface {
 	SendFindJobByIDMessage(request request.SendFindJobByIDMessageRequest) (*jobResponse.SendFindJobByIDMessageResponse, error)
 	SendFindJobLevelByIDMessage(request request.SendFindJobLevelByIDMessageRequest) (*jobResponse.SendFindJobLevelByIDMessageResponse, error)
 	SendCheckJobByJobLevelMessage(request request.CheckJobByJobLevelRequest) (*jobResponse.CheckJobExistMessageResponse, error)
+	SendFindJobsByIDsMessage(ids []string) (map[string]*jobResponse.SendFindJobByIDMessageResponse, error)
+	SendFindJobLevelsByIDsMessage(ids []string) (map[string]*jobResponse.SendFindJobLevelByIDMessageResponse, error)
 }
 
 type JobPlafonMessage struct {
@@ -46,8 +48,8 @@ func (m *JobPlafonMessage) SendCheckJobExistMessage(req request.CheckJobExistMes
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -91,8 +93,8 @@ func (m *JobPlafonMessage) SendFindJobByIDMessage(req request.SendFindJobByIDMes
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -135,8 +137,8 @@ func (m *JobPlafonMessage) SendFindJobLevelByIDMessage(req request.SendFindJobLe
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -181,8 +183,8 @@ func (m *JobPlafonMessage) SendCheckJobByJobLevelMessage(req request.CheckJobByJ
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -211,6 +213,86 @@ func (m *JobPlafonMessage) SendCheckJobByJobLevelMessage(req request.CheckJobByJ
 	}, nil
 }
 
+// SendFindJobsByIDsMessage falls back to one lookup per ID when the peer does
+// not understand the batch message.
+func (m *JobPlafonMessage) SendFindJobsByIDsMessage(ids []string) (map[string]*jobResponse.SendFindJobByIDMessageResponse, error) {
+	ids = uniqueIDs(ids)
+	result := make(map[string]*jobResponse.SendFindJobByIDMessageResponse, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	items, err := sendLookupBatchMessage("find_jobs_by_ids", "jobs", ids)
+	if err != nil {
+		m.Log.Warnf("[JobPlafonMessage.SendFindJobsByIDsMessage] batch lookup failed, falling back: %s", err.Error())
+		for _, id := range ids {
+			job, err := m.SendFindJobByIDMessage(request.SendFindJobByIDMessageRequest{ID: id})
+			if err != nil {
+				m.Log.Errorf("[JobPlafonMessage.SendFindJobsByIDsMessage] %s", err.Error())
+				continue
+			}
+			result[id] = job
+		}
+		return result, nil
+	}
+
+	for _, item := range items {
+		jobID, err := uuid.Parse(mapString(item, "job_id"))
+		if err != nil {
+			m.Log.Errorf("[JobPlafonMessage.SendFindJobsByIDsMessage] %s", err.Error())
+			continue
+		}
+		result[jobID.String()] = &jobResponse.SendFindJobByIDMessageResponse{
+			JobID:     jobID,
+			Name:      mapString(item, "name"),
+			MidsuitID: mapString(item, "midsuit_id"),
+		}
+	}
+
+	return result, nil
+}
+
+// SendFindJobLevelsByIDsMessage falls back to one lookup per ID when the peer
+// does not understand the batch message.
+func (m *JobPlafonMessage) SendFindJobLevelsByIDsMessage(ids []string) (map[string]*jobResponse.SendFindJobLevelByIDMessageResponse, error) {
+	ids = uniqueIDs(ids)
+	result := make(map[string]*jobResponse.SendFindJobLevelByIDMessageResponse, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	items, err := sendLookupBatchMessage("find_job_levels_by_ids", "job_levels", ids)
+	if err != nil {
+		m.Log.Warnf("[JobPlafonMessage.SendFindJobLevelsByIDsMessage] batch lookup failed, falling back: %s", err.Error())
+		for _, id := range ids {
+			jobLevel, err := m.SendFindJobLevelByIDMessage(request.SendFindJobLevelByIDMessageRequest{ID: id})
+			if err != nil {
+				m.Log.Errorf("[JobPlafonMessage.SendFindJobLevelsByIDsMessage] %s", err.Error())
+				continue
+			}
+			result[id] = jobLevel
+		}
+		return result, nil
+	}
+
+	for _, item := range items {
+		jobLevelID, err := uuid.Parse(mapString(item, "job_level_id"))
+		if err != nil {
+			m.Log.Errorf("[JobPlafonMessage.SendFindJobLevelsByIDsMessage] %s", err.Error())
+			continue
+		}
+		level, _ := item["level"].(float64)
+		result[jobLevelID.String()] = &jobResponse.SendFindJobLevelByIDMessageResponse{
+			JobLevelID: jobLevelID,
+			Name:       mapString(item, "name"),
+			Level:      level,
+			MidsuitID:  mapString(item, "midsuit_id"),
+		}
+	}
+
+	return result, nil
+}
+
 func JobPlafonMessageFactory(log *logrus.Logger) IJobPlafonMessage {
-	return NewJobPlafonMessage(log)
+	return NewCachedJobPlafonMessage(NewJobPlafonMessage(log), LookupCacheFactory())
 }
@@ -0,0 +1,164 @@
+package messaging
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+)
+
+// cache key prefixes, one per remote lookup
+const (
+	CACHE_PREFIX_ORGANIZATION           = "organization:"
+	CACHE_PREFIX_ORGANIZATION_LOCATION  = "organization_location:"
+	CACHE_PREFIX_ORGANIZATION_STRUCTURE = "organization_structure:"
+	CACHE_PREFIX_JOB                    = "job:"
+	CACHE_PREFIX_JOB_LEVEL              = "job_level:"
+	CACHE_PREFIX_GRADE                  = "grade:"
+	CACHE_PREFIX_EMPLOYEE               = "employee:"
+	CACHE_PREFIX_USER                   = "user:"
+)
+
+// inbound event message types and the cache entries they make stale
+var lookupCacheEvents = map[string][]string{
+	"organization_updated":           {CACHE_PREFIX_ORGANIZATION},
+	"organization_location_updated":  {CACHE_PREFIX_ORGANIZATION_LOCATION},
+	"organization_structure_updated": {CACHE_PREFIX_ORGANIZATION_STRUCTURE},
+	"job_updated":                    {CACHE_PREFIX_JOB},
+	"job_level_updated":              {CACHE_PREFIX_JOB_LEVEL},
+	"grade_updated":                  {CACHE_PREFIX_GRADE},
+	"employee_updated":               {CACHE_PREFIX_EMPLOYEE},
+	"user_updated":                   {CACHE_PREFIX_USER},
+}
+
+type ILookupCache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{})
+	Delete(key string)
+	DeletePrefix(prefix string)
+	Flush()
+	InvalidateByEvent(messageType string, id string) bool
+}
+
+type lookupCacheItem struct {
+	value     interface{}
+	expiredAt time.Time
+}
+
+// LookupCache drops expired entries when they are read, and sweeps the rest on the first write
+// after each TTL so keys that are never read again do not pile up
+type LookupCache struct {
+	TTL     time.Duration
+	mu      sync.RWMutex
+	items   map[string]lookupCacheItem
+	sweptAt time.Time
+}
+
+var (
+	lookupCacheInstance *LookupCache
+	lookupCacheOnce     sync.Once
+)
+
+func NewLookupCache(ttl time.Duration) *LookupCache {
+	return &LookupCache{
+		TTL:     ttl,
+		items:   make(map[string]lookupCacheItem),
+		sweptAt: time.Now(),
+	}
+}
+
+// LookupCacheFactory returns the process wide cache, so every messaging
+// instance built by the factories shares the same entries.
+func LookupCacheFactory() ILookupCache {
+	lookupCacheOnce.Do(func() {
+		viper := config.NewViper()
+		ttl := viper.GetInt("messaging.cache.ttl")
+		if ttl <= 0 {
+			ttl = 300 // Default to 5 minutes (in seconds)
+		}
+		lookupCacheInstance = NewLookupCache(time.Second * time.Duration(ttl))
+	})
+
+	return lookupCacheInstance
+}
+
+func (c *LookupCache) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(item.expiredAt) {
+		c.Delete(key)
+		return nil, false
+	}
+
+	return item.value, true
+}
+
+func (c *LookupCache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.sweptAt) >= c.TTL {
+		for k, item := range c.items {
+			if now.After(item.expiredAt) {
+				delete(c.items, k)
+			}
+		}
+		c.sweptAt = now
+	}
+
+	c.items[key] = lookupCacheItem{
+		value:     value,
+		expiredAt: now.Add(c.TTL),
+	}
+}
+
+func (c *LookupCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+}
+
+func (c *LookupCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.items, key)
+		}
+	}
+}
+
+func (c *LookupCache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]lookupCacheItem)
+}
+
+// InvalidateByEvent drops the entries affected by an inbound event. An empty id
+// drops every entry of the affected kinds. It returns false for unknown events.
+func (c *LookupCache) InvalidateByEvent(messageType string, id string) bool {
+	prefixes, ok := lookupCacheEvents[messageType]
+	if !ok {
+		return false
+	}
+
+	for _, prefix := range prefixes {
+		if id == "" {
+			c.DeletePrefix(prefix)
+		} else {
+			c.Delete(prefix + id)
+		}
+	}
+
+	return true
+}
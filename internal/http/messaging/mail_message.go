@@ -48,8 +48,8 @@ func (m *MailMessage) SendMail(req *request.MailRequest) (string, error) {
 
 	log.Printf("INFO: document message: %v", docMsg)
 
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
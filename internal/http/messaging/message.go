@@ -3,28 +3,169 @@ package messaging
 import (
 	"errors"
 	"log"
+	"sync"
 	"time"
 
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/google/uuid"
 )
 
 func waitReply(id string, rchan chan response.RabbitMQResponse) (response.RabbitMQResponse, error) {
+	return waitReplyTimeout(id, rchan, 100*time.Second)
+}
+
+func waitReplyTimeout(id string, rchan chan response.RabbitMQResponse, timeout time.Duration) (response.RabbitMQResponse, error) {
 	for {
 		select {
 		case docReply := <-rchan:
 			// responses received
 			log.Printf("INFO: received reply: %v uid: %s", docReply, id)
 
-			delete(utils.Rchans, id)
+			utils.Rchans.Delete(id)
 			return docReply, nil
-		case <-time.After(100 * time.Second):
+		case <-time.After(timeout):
 			// timeout
 			log.Printf("ERROR: request timeout uid: %s", id)
 
 			// remove channel from rchans
-			delete(utils.Rchans, id)
+			utils.Rchans.Delete(id)
 			return response.RabbitMQResponse{}, errors.New("request timeout")
 		}
 	}
 }
+
+type lookupBatchSettings struct {
+	queueName  string
+	timeout    time.Duration
+	retryAfter time.Duration
+}
+
+var (
+	lookupBatchSettingsOnce     sync.Once
+	lookupBatchSettingsInstance lookupBatchSettings
+	// unsupportedLookupBatches holds, per message type, until when the batch is not tried again
+	unsupportedLookupBatches sync.Map
+)
+
+// batchSettings reads messaging.sso_queue, messaging.batch.timeout and
+// messaging.batch.retry_after once per process
+func batchSettings() lookupBatchSettings {
+	lookupBatchSettingsOnce.Do(func() {
+		viper := config.NewViper()
+		queueName := viper.GetString("messaging.sso_queue")
+		if queueName == "" {
+			queueName = "julong_sso"
+		}
+		timeout := viper.GetInt("messaging.batch.timeout")
+		if timeout <= 0 {
+			timeout = 5
+		}
+		retryAfter := viper.GetInt("messaging.batch.retry_after")
+		if retryAfter <= 0 {
+			retryAfter = 600
+		}
+		lookupBatchSettingsInstance = lookupBatchSettings{
+			queueName:  queueName,
+			timeout:    time.Second * time.Duration(timeout),
+			retryAfter: time.Second * time.Duration(retryAfter),
+		}
+	})
+
+	return lookupBatchSettingsInstance
+}
+
+// sendLookupBatchMessage resolves many IDs with a single round trip. The peer
+// answers with the matching records under itemsKey, each shaped like the reply
+// of the equivalent single lookup. A peer that does not answer a batch within
+// messaging.batch.timeout is not asked again for that message type until
+// messaging.batch.retry_after has passed, so callers fall back right away.
+func sendLookupBatchMessage(messageType string, itemsKey string, ids []string) ([]map[string]interface{}, error) {
+	settings := batchSettings()
+	if until, ok := unsupportedLookupBatches.Load(messageType); ok && time.Now().Before(until.(time.Time)) {
+		return nil, errors.New("[" + messageType + "] batch lookup is not supported by the peer")
+	}
+
+	items, err := requestLookupBatch(settings, messageType, itemsKey, ids)
+	if err != nil {
+		unsupportedLookupBatches.Store(messageType, time.Now().Add(settings.retryAfter))
+		return nil, err
+	}
+	unsupportedLookupBatches.Delete(messageType)
+
+	return items, nil
+}
+
+func requestLookupBatch(settings lookupBatchSettings, messageType string, itemsKey string, ids []string) ([]map[string]interface{}, error) {
+	payload := map[string]interface{}{
+		"ids": ids,
+	}
+
+	docMsg := &request.RabbitMQRequest{
+		ID:          uuid.New().String(),
+		MessageType: messageType,
+		MessageData: payload,
+		ReplyTo:     "julong_recruitment",
+	}
+
+	log.Printf("INFO: document message: %v", docMsg)
+
+	// create channel and add to rchans with uid
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
+
+	// publish rabbit message
+	msg := utils.RabbitMsgPublisher{
+		QueueName: settings.queueName,
+		Message:   *docMsg,
+	}
+	utils.Pchan <- msg
+
+	// wait for reply
+	resp, err := waitReplyTimeout(docMsg.ID, rchan, settings.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("INFO: response: %v", resp)
+
+	if errMsg, ok := resp.MessageData["error"].(string); ok && errMsg != "" {
+		return nil, errors.New("[" + messageType + "] " + errMsg)
+	}
+
+	rawItems, ok := resp.MessageData[itemsKey].([]interface{})
+	if !ok {
+		return nil, errors.New("[" + messageType + "] missing " + itemsKey + " in reply")
+	}
+
+	items := make([]map[string]interface{}, 0, len(rawItems))
+	for _, rawItem := range rawItems {
+		if item, ok := rawItem.(map[string]interface{}); ok {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func mapString(data map[string]interface{}, key string) string {
+	if value, ok := data[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
@@ -53,8 +53,8 @@ func (m *MPRequestMessage) SendFindByIdMessage(id string) (*response.MPRequestHe
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -100,8 +100,8 @@ func (m *MPRequestMessage) SendFindByIdTidakLengkapMessage(id string) (*response
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -148,8 +148,8 @@ func (m *MPRequestMessage) SendFindIdsByMajorsMessage(majors []string, education
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
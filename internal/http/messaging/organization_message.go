@@ -20,6 +20,8 @@ type IOrganizationMessage interface {
 	SendFindAllOrganizationMessage(includedIDs []string) (*[]orgResponse.OrganizationResponse, error)
 	SendFindAllOrganizationLocationsMessage(includedIDs []string) (*[]orgResponse.OrganizationLocationResponse, error)
 	SendFindAllOrgStructureChildrenIDsMessage(parentID string) (*[]string, error)
+	SendFindOrganizationsByIDsMessage(ids []string) (map[string]*orgResponse.SendFindOrganizationByIDMessageResponse, error)
+	SendFindOrganizationLocationsByIDsMessage(ids []string) (map[string]*orgResponse.SendFindOrganizationLocationByIDMessageResponse, error)
 }
 
 type OrganizationMessage struct {
@@ -47,8 +49,8 @@ func (m *OrganizationMessage) SendFindOrganizationByIDMessage(req request.SendFi
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -94,8 +96,8 @@ func (m *OrganizationMessage) SendFindAllOrganizationMessage(includedIDs []strin
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -145,8 +147,8 @@ func (m *OrganizationMessage) SendFindOrganizationLocationByIDMessage(req reques
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -189,8 +191,8 @@ func (m *OrganizationMessage) SendFindOrganizationStructureByIDMessage(req reque
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -239,8 +241,8 @@ func (m *OrganizationMessage) SendFindOrganizationLocationsPaginatedMessage(page
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -295,8 +297,8 @@ func (m *OrganizationMessage) SendFindAllOrganizationLocationsMessage(includedID
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -349,8 +351,8 @@ func (m *OrganizationMessage) SendFindAllOrgStructureChildrenIDsMessage(parentID
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -380,6 +382,79 @@ func (m *OrganizationMessage) SendFindAllOrgStructureChildrenIDsMessage(parentID
 	return &ids, nil
 }
 
+// SendFindOrganizationsByIDsMessage falls back to one lookup per ID when the
+// peer does not understand the batch message.
+func (m *OrganizationMessage) SendFindOrganizationsByIDsMessage(ids []string) (map[string]*orgResponse.SendFindOrganizationByIDMessageResponse, error) {
+	ids = uniqueIDs(ids)
+	result := make(map[string]*orgResponse.SendFindOrganizationByIDMessageResponse, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	items, err := sendLookupBatchMessage("find_organizations_by_ids", "organizations", ids)
+	if err != nil {
+		m.Log.Warnf("[OrganizationMessage.SendFindOrganizationsByIDsMessage] batch lookup failed, falling back: %s", err.Error())
+		for _, id := range ids {
+			organization, err := m.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{ID: id})
+			if err != nil {
+				m.Log.Errorf("[OrganizationMessage.SendFindOrganizationsByIDsMessage] %s", err.Error())
+				continue
+			}
+			result[id] = organization
+		}
+		return result, nil
+	}
+
+	for _, item := range items {
+		organization := &orgResponse.SendFindOrganizationByIDMessageResponse{
+			OrganizationID:       mapString(item, "organization_id"),
+			Name:                 mapString(item, "name"),
+			OrganizationCategory: mapString(item, "organization_category"),
+			OrganizationType:     mapString(item, "organization_type"),
+			Logo:                 mapString(item, "logo"),
+			MidsuitID:            mapString(item, "midsuit_id"),
+		}
+		result[organization.OrganizationID] = organization
+	}
+
+	return result, nil
+}
+
+// SendFindOrganizationLocationsByIDsMessage falls back to one lookup per ID when
+// the peer does not understand the batch message.
+func (m *OrganizationMessage) SendFindOrganizationLocationsByIDsMessage(ids []string) (map[string]*orgResponse.SendFindOrganizationLocationByIDMessageResponse, error) {
+	ids = uniqueIDs(ids)
+	result := make(map[string]*orgResponse.SendFindOrganizationLocationByIDMessageResponse, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	items, err := sendLookupBatchMessage("find_organization_locations_by_ids", "organization_locations", ids)
+	if err != nil {
+		m.Log.Warnf("[OrganizationMessage.SendFindOrganizationLocationsByIDsMessage] batch lookup failed, falling back: %s", err.Error())
+		for _, id := range ids {
+			organizationLocation, err := m.SendFindOrganizationLocationByIDMessage(request.SendFindOrganizationLocationByIDMessageRequest{ID: id})
+			if err != nil {
+				m.Log.Errorf("[OrganizationMessage.SendFindOrganizationLocationsByIDsMessage] %s", err.Error())
+				continue
+			}
+			result[id] = organizationLocation
+		}
+		return result, nil
+	}
+
+	for _, item := range items {
+		organizationLocation := &orgResponse.SendFindOrganizationLocationByIDMessageResponse{
+			OrganizationLocationID: mapString(item, "organization_location_id"),
+			Name:                   mapString(item, "name"),
+			MidsuitID:              mapString(item, "midsuit_id"),
+		}
+		result[organizationLocation.OrganizationLocationID] = organizationLocation
+	}
+
+	return result, nil
+}
+
 func OrganizationMessageFactory(log *logrus.Logger) IOrganizationMessage {
-	return NewOrganizationMessage(log)
+	return NewCachedOrganizationMessage(NewOrganizationMessage(log), LookupCacheFactory())
 }
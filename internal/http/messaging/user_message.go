@@ -7,6 +7,7 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -35,7 +36,7 @@ func NewUserMessage(log *logrus.Logger, userProfileRepository repository.IUserPr
 
 func UserMessageFactory(log *logrus.Logger) IUserMessage {
 	userProfileRepository := repository.UserProfileRepositoryFactory(log)
-	return NewUserMessage(log, userProfileRepository)
+	return NewCachedUserMessage(NewUserMessage(log, userProfileRepository), LookupCacheFactory())
 }
 
 func (m *UserMessage) SendFindUserByIDMessage(req request.SendFindUserByIDMessageRequest) (*response.SendFindUserByIDResponse, error) {
@@ -53,8 +54,8 @@ func (m *UserMessage) SendFindUserByIDMessage(req request.SendFindUserByIDMessag
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -96,8 +97,8 @@ func (m *UserMessage) SendGetUserMe(req request.SendFindUserByIDMessageRequest)
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -164,21 +165,21 @@ func (m *UserMessage) FindUserProfileByUserIDMessage(userId string) (*response.U
 		MidsuitID:     ent.MidsuitID,
 		Avatar: func() *string {
 			if ent.Avatar != "" {
-				avatarURL := configData.GetString("app.url") + ent.Avatar
+				avatarURL := storage.URL(configData, ent.Avatar)
 				return &avatarURL
 			}
 			return nil
 		}(),
 		Ktp: func() *string {
 			if ent.Ktp != "" {
-				ktpURL := configData.GetString("app.url") + ent.Ktp
+				ktpURL := storage.URL(configData, ent.Ktp)
 				return &ktpURL
 			}
 			return nil
 		}(),
 		CurriculumVitae: func() *string {
 			if ent.CurriculumVitae != "" {
-				cvURL := configData.GetString("app.url") + ent.CurriculumVitae
+				cvURL := storage.URL(configData, ent.CurriculumVitae)
 				return &cvURL
 			}
 			return nil
@@ -256,8 +257,8 @@ func (m *UserMessage) SendGetUserIDsByPermissionNames(permissionNames []string)
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
@@ -301,8 +302,8 @@ func (m *UserMessage) SendFindUserByEmployeeIDMessage(employeeID string) (*respo
 	log.Printf("INFO: document message: %v", docMsg)
 
 	// create channel and add to rchans with uid
-	rchan := make(chan response.RabbitMQResponse)
-	utils.Rchans[docMsg.ID] = rchan
+	rchan := make(chan response.RabbitMQResponse, 1)
+	utils.Rchans.Store(docMsg.ID, rchan)
 
 	// publish rabbit message
 	msg := utils.RabbitMsgPublisher{
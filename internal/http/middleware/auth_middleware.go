@@ -1,20 +1,32 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func NewAuth(viper *viper.Viper) gin.HandlerFunc {
+const AUTH_TOKEN_CONTEXT_KEY = "auth_token"
+
+// NewAuth verifies the bearer token. HMAC tokens are checked with jwt.secret and RS256/ES256 tokens
+// with the JWKS document from jwt.jwks, then issuer, audience, expiry and the revocation list are checked.
+func NewAuth(log *logrus.Logger, viper *viper.Viper) gin.HandlerFunc {
+	jwksService := service.JwksServiceFactory(viper, log)
+	revocationService := service.TokenRevocationServiceFactory(viper, log)
+
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -31,11 +43,19 @@ func NewAuth(viper *viper.Viper) gin.HandlerFunc {
 		}
 
 		token, err := jwt.Parse(bearerToken[1], func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			switch token.Method.(type) {
+			case *jwt.SigningMethodHMAC:
+				secret := viper.GetString("jwt.secret")
+				if secret == "" {
+					return nil, errors.New("HMAC signed tokens are not accepted")
+				}
+				return []byte(secret), nil
+			case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+				kid, _ := token.Header["kid"].(string)
+				return jwksService.GetKey(kid, token.Method.Alg())
 			}
-			return []byte(viper.GetString("jwt.secret")), nil
-		})
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}, tokenParserOptions(viper, jwksService)...)
 
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
@@ -43,18 +63,90 @@ func NewAuth(viper *viper.Viper) gin.HandlerFunc {
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			c.Set("auth", claims)
-		} else {
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		if !viper.IsSet("jwt.revocation.enabled") || viper.GetBool("jwt.revocation.enabled") {
+			userID, _ := claims["id"].(string)
+			parsedUserID, _ := uuid.Parse(userID)
+			var issuedAt *time.Time
+			if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+				issuedAt = &iat.Time
+			}
+
+			revoked, err := revocationService.IsRevoked(TokenID(claims, bearerToken[1]), parsedUserID, issuedAt)
+			if err != nil {
+				log.Errorf("[NewAuth] error when checking token revocation: %v", err)
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to check token revocation"})
+				c.Abort()
+				return
+			}
+			if revoked {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set("auth", claims)
+		c.Set(AUTH_TOKEN_CONTEXT_KEY, bearerToken[1])
 		c.Next()
 	}
 }
 
+func tokenParserOptions(viper *viper.Viper, jwksService service.IJwksService) []jwt.ParserOption {
+	methods := viper.GetStringSlice("jwt.algorithms")
+	if len(methods) == 0 {
+		if viper.GetString("jwt.secret") != "" {
+			methods = append(methods, "HS256")
+		}
+		if jwksService.Enabled() {
+			methods = append(methods, "RS256", "ES256")
+		}
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(time.Duration(viper.GetInt("jwt.leeway")) * time.Second),
+	}
+	if issuer := viper.GetString("jwt.issuer"); issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	if audience := viper.GetString("jwt.audience"); audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+	if viper.GetBool("jwt.require_expiry") {
+		options = append(options, jwt.WithExpirationRequired())
+	}
+	return options
+}
+
+// TokenID identifies a token on the revocation list, its jti or else the hash of the raw token
+func TokenID(claims jwt.MapClaims, rawToken string) string {
+	if jti, ok := claims["jti"].(string); ok && jti != "" {
+		return jti
+	}
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetTokenClaims returns the verified claims and the raw token of the caller
+func GetTokenClaims(c *gin.Context) (jwt.MapClaims, string, error) {
+	auth, exists := c.Get("auth")
+	if !exists {
+		return nil, "", errors.New("auth key not found in context")
+	}
+	claims, ok := auth.(jwt.MapClaims)
+	if !ok {
+		return nil, "", errors.New("invalid auth claims type")
+	}
+	return claims, c.GetString(AUTH_TOKEN_CONTEXT_KEY), nil
+}
+
 func GetUser(c *gin.Context, log *logrus.Logger) (map[string]interface{}, error) {
 	auth, exists := c.Get("auth")
 	if !exists {
@@ -66,10 +158,15 @@ func GetUser(c *gin.Context, log *logrus.Logger) (map[string]interface{}, error)
 		return nil, errors.New("invalid auth claims type")
 	}
 
+	userID, ok := claims["id"].(string)
+	if !ok || userID == "" {
+		return nil, errors.New("token has no user id")
+	}
+
 	message := messaging.UserMessageFactory(log)
 
 	messageResponse, err := message.SendGetUserMe(request.SendFindUserByIDMessageRequest{
-		ID: claims["id"].(string),
+		ID: userID,
 	})
 
 	if err != nil {
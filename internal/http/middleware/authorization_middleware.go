@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const ACCESS_CONTEXT_KEY = "access"
+
+// RoutePermissions maps "METHOD /full/route/path" to the permissions allowed to call it,
+// any one of them is enough. Routes that are not listed only need a valid token.
+type RoutePermissions map[string][]string
+
+// Access is what the caller is allowed to do, resolved once per token
+type Access struct {
+	UserID         uuid.UUID
+	EmployeeID     uuid.UUID
+	OrganizationID uuid.UUID
+	Roles          []string
+	Permissions    []string
+	BypassRole     bool
+	CrossCompany   bool
+}
+
+func (a *Access) HasRole(role string) bool {
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the caller holds any of the given permissions
+func (a *Access) HasPermission(permissions ...string) bool {
+	if a.BypassRole {
+		return true
+	}
+	for _, permission := range permissions {
+		for _, p := range a.Permissions {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// staffPermissions are the recruitment permissions, holding any one of them makes the caller staff
+var staffPermissions = []string{"read-recruitment", "create-recruitment", "update-recruitment", "delete-recruitment"}
+
+// IsStaff reports whether the caller works on other people's records. Candidates are not staff,
+// neither are employees without a recruitment permission, e.g. those referring candidates.
+func (a *Access) IsStaff() bool {
+	return a.HasPermission(staffPermissions...)
+}
+
+var (
+	accessCacheInstance messaging.ILookupCache
+	accessCacheOnce     sync.Once
+)
+
+func accessCache(viper *viper.Viper) messaging.ILookupCache {
+	accessCacheOnce.Do(func() {
+		ttl := viper.GetInt("authorization.cache.ttl")
+		if ttl <= 0 {
+			ttl = 60
+		}
+		accessCacheInstance = messaging.NewLookupCache(time.Second * time.Duration(ttl))
+	})
+	return accessCacheInstance
+}
+
+func authorizationEnabled(viper *viper.Viper) bool {
+	return !viper.IsSet("authorization.enabled") || viper.GetBool("authorization.enabled")
+}
+
+func bypassRoles(viper *viper.Viper) []string {
+	roles := viper.GetStringSlice("authorization.bypass_roles")
+	if len(roles) == 0 {
+		roles = []string{"superadmin"}
+	}
+	return roles
+}
+
+// ResolveAccess resolves the caller's roles and permissions, cached per token
+func ResolveAccess(c *gin.Context, log *logrus.Logger, viper *viper.Viper) (*Access, error) {
+	if access, ok := GetAccess(c); ok {
+		return access, nil
+	}
+
+	cache := accessCache(viper)
+	sum := sha256.Sum256([]byte(c.GetHeader("Authorization")))
+	key := hex.EncodeToString(sum[:])
+	if cached, ok := cache.Get(key); ok {
+		access := cached.(*Access)
+		c.Set(ACCESS_CONTEXT_KEY, access)
+		return access, nil
+	}
+
+	user, err := GetUser(c, log)
+	if err != nil {
+		return nil, err
+	}
+
+	userHelper := helper.UserHelperFactory(log)
+	userID, err := userHelper.GetUserId(user)
+	if err != nil {
+		return nil, err
+	}
+	// candidates have no employee, so the errors are expected here
+	employeeID, _ := userHelper.GetEmployeeId(user)
+	organizationID, _ := userHelper.GetOrganizationID(user)
+	roles, err := userHelper.GetUserRoles(user)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := userHelper.GetUserPermissions(user)
+	if err != nil {
+		return nil, err
+	}
+
+	access := &Access{
+		UserID:         userID,
+		EmployeeID:     employeeID,
+		OrganizationID: organizationID,
+		Roles:          roles,
+		Permissions:    permissions,
+	}
+	for _, role := range bypassRoles(viper) {
+		if access.HasRole(role) {
+			access.BypassRole = true
+			break
+		}
+	}
+	for _, role := range viper.GetStringSlice("authorization.cross_company_roles") {
+		if access.HasRole(role) {
+			access.CrossCompany = true
+			break
+		}
+	}
+
+	cache.Set(key, access)
+	c.Set(ACCESS_CONTEXT_KEY, access)
+	return access, nil
+}
+
+// GetAccess returns the access resolved by the authorization middleware, if any
+func GetAccess(c *gin.Context) (*Access, bool) {
+	value, exists := c.Get(ACCESS_CONTEXT_KEY)
+	if !exists {
+		return nil, false
+	}
+	access, ok := value.(*Access)
+	return access, ok
+}
+
+// NewAuthorization checks the caller against the permissions declared for the matched route
+func NewAuthorization(log *logrus.Logger, viper *viper.Viper, permissions RoutePermissions) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !authorizationEnabled(viper) {
+			ctx.Next()
+			return
+		}
+
+		access, err := ResolveAccess(ctx, log, viper)
+		if err != nil {
+			log.Errorf("[AuthorizationMiddleware] %v", err)
+			utils.ErrorResponse(ctx, http.StatusUnauthorized, "Unauthorized", err.Error())
+			ctx.Abort()
+			return
+		}
+
+		required, ok := permissions[ctx.Request.Method+" "+ctx.FullPath()]
+		if ok && !access.HasPermission(required...) {
+			utils.ErrorResponse(ctx, http.StatusForbidden, "Forbidden", "missing permission: "+strings.Join(required, " or "))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// GetOrganizationScope returns the companies whose data the caller may list. Staff only see their
+// own organization unless they hold a bypass or cross company role, candidates are not scoped.
+func GetOrganizationScope(c *gin.Context) *repository.OrganizationScope {
+	access, ok := GetAccess(c)
+	if !ok || access.BypassRole || access.CrossCompany || !access.IsStaff() {
+		return nil
+	}
+
+	scope := &repository.OrganizationScope{}
+	if access.OrganizationID != uuid.Nil {
+		scope.OrganizationIDs = append(scope.OrganizationIDs, access.OrganizationID)
+	}
+	return scope
+}
+
+// CanAccessUserProfile reports whether the caller may touch records that belong to the
+// given user profile. Staff may touch any, candidates only their own.
+func CanAccessUserProfile(c *gin.Context, log *logrus.Logger, userProfileID uuid.UUID) (bool, error) {
+	access, ok := GetAccess(c)
+	if !ok {
+		// authorization is disabled
+		return true, nil
+	}
+	if access.IsStaff() {
+		return true, nil
+	}
+
+	userProfile, err := repository.UserProfileRepositoryFactory(log).FindByUserID(access.UserID)
+	if err != nil {
+		return false, err
+	}
+	if userProfile == nil {
+		return false, nil
+	}
+
+	return userProfile.ID == userProfileID, nil
+}
+
+// AuthorizeUserProfiles aborts with 403 unless the caller may touch all the given user profiles
+func AuthorizeUserProfiles(c *gin.Context, log *logrus.Logger, userProfileIDs ...uuid.UUID) bool {
+	for _, userProfileID := range userProfileIDs {
+		allowed, err := CanAccessUserProfile(c, log, userProfileID)
+		if err != nil {
+			log.Errorf("[AuthorizeUserProfiles] %v", err)
+			utils.ErrorResponse(c, http.StatusInternalServerError, "error", err.Error())
+			return false
+		}
+		if !allowed {
+			utils.ErrorResponse(c, http.StatusForbidden, "Forbidden", "you are not allowed to access this resource")
+			return false
+		}
+	}
+	return true
+}
+
+// AuthorizeInOrganizationScope answers 404 unless the record passes the organization scope of the
+// caller, has is one of the checks of the organization scope repository
+func AuthorizeInOrganizationScope(c *gin.Context, log *logrus.Logger, id uuid.UUID, has func(id uuid.UUID, orgScope *repository.OrganizationScope) (bool, error)) bool {
+	allowed, err := has(id, GetOrganizationScope(c))
+	if err != nil {
+		log.Errorf("[AuthorizeInOrganizationScope] %v", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "error", err.Error())
+		return false
+	}
+	if !allowed {
+		utils.ErrorResponse(c, http.StatusNotFound, "data not found", "data not found")
+		return false
+	}
+	return true
+}
+
+// OrganizationScopeRepository is used by the handlers to hold single records to the organization scope
+func OrganizationScopeRepository(log *logrus.Logger) repository.IOrganizationScopeRepository {
+	return repository.OrganizationScopeRepositoryFactory(log)
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const DEFAULT_RATE_LIMIT_POLICY = "default"
+
+// RateLimitPolicy limits a route per client IP and, once the token is verified, per user.
+// A limit without requests is not applied.
+type RateLimitPolicy struct {
+	IP   RateLimit
+	User RateLimit
+}
+
+// RouteRateLimits maps "METHOD /full/route/path" to the name of its policy,
+// routes that are not listed use the default policy.
+type RouteRateLimits map[string]string
+
+// NewRateLimit throttles requests with the policies given in code, each limit can be overridden
+// with rate_limit.policies.<name>.<ip|user>.requests and .window (seconds). The client IP is the
+// peer address unless it is one of web.trusted_proxies. The user limit only applies when the
+// token was verified before, on authenticated routes it goes first so that requests with bad
+// tokens are throttled too, and NewUserRateLimit follows the authentication.
+// Allowed requests get RateLimit-* headers, rejected ones a 429 with Retry-After.
+func NewRateLimit(log *logrus.Logger, viper *viper.Viper, store IRateLimitStore, policies map[string]RateLimitPolicy, routes RouteRateLimits) gin.HandlerFunc {
+	return newRateLimit(log, viper, store, policies, routes, true)
+}
+
+// NewUserRateLimit only applies the user limits, for routes whose IP limit ran before the token
+// was verified
+func NewUserRateLimit(log *logrus.Logger, viper *viper.Viper, store IRateLimitStore, policies map[string]RateLimitPolicy, routes RouteRateLimits) gin.HandlerFunc {
+	return newRateLimit(log, viper, store, policies, routes, false)
+}
+
+func newRateLimit(log *logrus.Logger, viper *viper.Viper, store IRateLimitStore, policies map[string]RateLimitPolicy, routes RouteRateLimits, limitIP bool) gin.HandlerFunc {
+	resolved := make(map[string]RateLimitPolicy, len(policies))
+	for name, policy := range policies {
+		resolved[name] = RateLimitPolicy{
+			IP:   configuredRateLimit(viper, "rate_limit.policies."+name+".ip", policy.IP),
+			User: configuredRateLimit(viper, "rate_limit.policies."+name+".user", policy.User),
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		if viper.IsSet("rate_limit.enabled") && !viper.GetBool("rate_limit.enabled") {
+			ctx.Next()
+			return
+		}
+
+		name, ok := routes[ctx.Request.Method+" "+ctx.FullPath()]
+		if !ok {
+			name = DEFAULT_RATE_LIMIT_POLICY
+		}
+		policy, ok := resolved[name]
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		var results []RateLimitResult
+		if limitIP && policy.IP.Enabled() {
+			result, err := store.Take("rl:"+name+":ip:"+ctx.ClientIP(), policy.IP)
+			if err != nil {
+				// the limiter must not take the api down with it
+				log.Errorf("[RateLimitMiddleware] %v", err)
+			} else {
+				results = append(results, result)
+			}
+		}
+		if userID := rateLimitUserID(ctx); userID != "" && policy.User.Enabled() {
+			result, err := store.Take("rl:"+name+":user:"+userID, policy.User)
+			if err != nil {
+				log.Errorf("[RateLimitMiddleware] %v", err)
+			} else {
+				results = append(results, result)
+			}
+		}
+		if len(results) == 0 {
+			ctx.Next()
+			return
+		}
+
+		result := strictestRateLimitResult(results)
+		// the headers of an earlier limiter stay when they are stricter
+		remaining, err := strconv.Atoi(ctx.Writer.Header().Get("RateLimit-Remaining"))
+		if err != nil || !result.Allowed || result.Remaining < remaining {
+			ctx.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+			ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			ctx.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		}
+
+		if !result.Allowed {
+			retryAfter := strconv.Itoa(ceilSeconds(result.RetryAfter))
+			ctx.Header("Retry-After", retryAfter)
+			utils.ErrorResponse(ctx, http.StatusTooManyRequests, "Too Many Requests", "rate limit exceeded, retry in "+retryAfter+" seconds")
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func configuredRateLimit(viper *viper.Viper, key string, fallback RateLimit) RateLimit {
+	limit := fallback
+	if viper.IsSet(key + ".requests") {
+		limit.Requests = viper.GetInt(key + ".requests")
+	}
+	if viper.IsSet(key + ".window") {
+		limit.Window = time.Duration(viper.GetInt(key+".window")) * time.Second
+	}
+	return limit
+}
+
+// rateLimitUserID returns the user of the verified token, empty on public routes
+func rateLimitUserID(ctx *gin.Context) string {
+	claims, _, err := GetTokenClaims(ctx)
+	if err != nil {
+		return ""
+	}
+	userID, _ := claims["id"].(string)
+	return userID
+}
+
+// strictestRateLimitResult returns the rejection with the longest wait, or the result with the fewest
+// remaining requests when every limit allowed the request
+func strictestRateLimitResult(results []RateLimitResult) RateLimitResult {
+	strictest := results[0]
+	for _, result := range results[1:] {
+		switch {
+		case !result.Allowed && (strictest.Allowed || result.RetryAfter > strictest.RetryAfter):
+			strictest = result
+		case result.Allowed && strictest.Allowed && result.Remaining < strictest.Remaining:
+			strictest = result
+		}
+	}
+	return strictest
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
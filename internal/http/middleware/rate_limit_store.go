@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// RateLimit is a token bucket holding Requests tokens that refills completely in Window
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next token, zero when the request was allowed
+	RetryAfter time.Duration
+}
+
+// IRateLimitStore keeps the token buckets. The in-memory store only limits a single replica,
+// deployments running several replicas can plug a shared backend in behind this interface.
+type IRateLimitStore interface {
+	Take(key string, limit RateLimit) (RateLimitResult, error)
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	window    time.Duration
+}
+
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	sweptAt time.Time
+	now     func() time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*memoryBucket),
+		sweptAt: time.Now(),
+		now:     time.Now,
+	}
+}
+
+var (
+	rateLimitStoreInstance IRateLimitStore
+	rateLimitStoreOnce     sync.Once
+)
+
+// RateLimitStoreFactory returns the store configured in rate_limit.store, shared by every route
+func RateLimitStoreFactory(log *logrus.Logger, viper *viper.Viper) IRateLimitStore {
+	rateLimitStoreOnce.Do(func() {
+		switch store := viper.GetString("rate_limit.store"); store {
+		case "", "memory":
+		default:
+			log.Warnf("[RateLimitStoreFactory] unknown rate limit store %q, using memory", store)
+		}
+		rateLimitStoreInstance = NewMemoryRateLimitStore()
+	})
+	return rateLimitStoreInstance
+}
+
+func (s *MemoryRateLimitStore) Take(key string, limit RateLimit) (RateLimitResult, error) {
+	now := s.now()
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Window.Seconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: capacity, updatedAt: now}
+		s.buckets[key] = bucket
+	}
+	bucket.window = limit.Window
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*perSecond)
+	bucket.updatedAt = now
+
+	result := RateLimitResult{Limit: limit.Requests}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / perSecond)
+	}
+	result.Remaining = int(math.Floor(bucket.tokens))
+	result.Reset = secondsToDuration((capacity - bucket.tokens) / perSecond)
+	return result, nil
+}
+
+// sweep drops buckets that refilled completely, they behave the same as a new bucket.
+// It must be called with s.mu held.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < time.Minute {
+		return
+	}
+	s.sweptAt = now
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.updatedAt) >= bucket.window {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
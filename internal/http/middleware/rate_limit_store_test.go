@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitStoreTake(t *testing.T) {
+	// 4 requests per minute, one token every 15 seconds
+	limit := RateLimit{Requests: 4, Window: time.Minute}
+
+	tests := []struct {
+		name  string
+		steps []struct {
+			after          time.Duration
+			wantAllowed    bool
+			wantRemaining  int
+			wantRetryAfter time.Duration
+		}
+	}{
+		{
+			name: "a full bucket allows a burst up to the limit",
+			steps: []struct {
+				after          time.Duration
+				wantAllowed    bool
+				wantRemaining  int
+				wantRetryAfter time.Duration
+			}{
+				{wantAllowed: true, wantRemaining: 3},
+				{wantAllowed: true, wantRemaining: 2},
+				{wantAllowed: true, wantRemaining: 1},
+				{wantAllowed: true, wantRemaining: 0},
+				{wantAllowed: false, wantRemaining: 0, wantRetryAfter: 15 * time.Second},
+			},
+		},
+		{
+			name: "tokens come back one per window share",
+			steps: []struct {
+				after          time.Duration
+				wantAllowed    bool
+				wantRemaining  int
+				wantRetryAfter time.Duration
+			}{
+				{wantAllowed: true, wantRemaining: 3},
+				{wantAllowed: true, wantRemaining: 2},
+				{wantAllowed: true, wantRemaining: 1},
+				{wantAllowed: true, wantRemaining: 0},
+				{after: 5 * time.Second, wantAllowed: false, wantRemaining: 0, wantRetryAfter: 10 * time.Second},
+				{after: 10 * time.Second, wantAllowed: true, wantRemaining: 0},
+				{after: 30 * time.Second, wantAllowed: true, wantRemaining: 1},
+			},
+		},
+		{
+			name: "refilling stops at the limit",
+			steps: []struct {
+				after          time.Duration
+				wantAllowed    bool
+				wantRemaining  int
+				wantRetryAfter time.Duration
+			}{
+				{wantAllowed: true, wantRemaining: 3},
+				{after: 10 * time.Minute, wantAllowed: true, wantRemaining: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			store := NewMemoryRateLimitStore()
+			store.now = func() time.Time { return now }
+
+			for i, step := range tt.steps {
+				now = now.Add(step.after)
+				result, err := store.Take("ip:10.0.0.1", limit)
+				if err != nil {
+					t.Fatalf("step %d: Take() error = %v", i, err)
+				}
+				if result.Allowed != step.wantAllowed || result.Remaining != step.wantRemaining || result.RetryAfter != step.wantRetryAfter {
+					t.Fatalf("step %d: Take() = allowed %t, remaining %d, retry after %s, want %t, %d, %s",
+						i, result.Allowed, result.Remaining, result.RetryAfter, step.wantAllowed, step.wantRemaining, step.wantRetryAfter)
+				}
+				if result.Limit != limit.Requests {
+					t.Fatalf("step %d: Take() limit = %d, want %d", i, result.Limit, limit.Requests)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryRateLimitStoreKeysAreSeparate(t *testing.T) {
+	limit := RateLimit{Requests: 1, Window: time.Hour}
+	store := NewMemoryRateLimitStore()
+
+	tests := []struct {
+		key         string
+		wantAllowed bool
+	}{
+		{key: "user:a", wantAllowed: true},
+		{key: "user:a", wantAllowed: false},
+		{key: "user:b", wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		result, err := store.Take(tt.key, limit)
+		if err != nil {
+			t.Fatalf("Take(%q) error = %v", tt.key, err)
+		}
+		if result.Allowed != tt.wantAllowed {
+			t.Fatalf("Take(%q) allowed = %t, want %t", tt.key, result.Allowed, tt.wantAllowed)
+		}
+	}
+}
+
+func TestMemoryRateLimitStoreReset(t *testing.T) {
+	limit := RateLimit{Requests: 4, Window: time.Minute}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+
+	tests := []struct {
+		takes     int
+		wantReset time.Duration
+	}{
+		{takes: 1, wantReset: 15 * time.Second},
+		{takes: 2, wantReset: 45 * time.Second},
+		{takes: 1, wantReset: time.Minute},
+	}
+
+	for i, tt := range tests {
+		var result RateLimitResult
+		for j := 0; j < tt.takes; j++ {
+			result, _ = store.Take("ip:10.0.0.1", limit)
+		}
+		if result.Reset != tt.wantReset {
+			t.Fatalf("step %d: Reset = %s, want %s", i, result.Reset, tt.wantReset)
+		}
+	}
+}
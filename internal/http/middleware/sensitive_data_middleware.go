@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const SENSITIVE_DATA_MASKED_CONTEXT_KEY = "sensitive_data_masked"
+
+// sensitiveResponseFields are the JSON keys masked for callers without the sensitive data permission
+var sensitiveResponseFields = map[string]bool{
+	"ktp_number":            true,
+	"current_salary":        true,
+	"expected_salary":       true,
+	"religion":              true,
+	"basic_wage":            true,
+	"positional_allowance":  true,
+	"operational_allowance": true,
+	"meal_allowance":        true,
+	"house_allowance":       true,
+}
+
+// maskingResponseWriter holds JSON responses back so they can be masked before they are sent,
+// anything else is written through
+type maskingResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *maskingResponseWriter) isJSON() bool {
+	return strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+func (w *maskingResponseWriter) Write(data []byte) (int, error) {
+	if !w.isJSON() {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *maskingResponseWriter) WriteString(s string) (int, error) {
+	if !w.isJSON() {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+// NewSensitiveDataMasking masks KTP numbers, salaries, religion and compensation in the JSON
+// responses of every caller lacking the given permission. Records owned by the caller, a profile
+// or anything holding their profile, are left in full.
+func NewSensitiveDataMasking(log *logrus.Logger, viper *viper.Viper, permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		access, ok := GetAccess(ctx)
+		if !ok || access.HasPermission(permission) {
+			ctx.Next()
+			return
+		}
+
+		ctx.Set(SENSITIVE_DATA_MASKED_CONTEXT_KEY, true)
+		writer := &maskingResponseWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+		ctx.Writer = writer.ResponseWriter
+
+		if writer.body.Len() == 0 {
+			return
+		}
+
+		body := writer.body.Bytes()
+		var userID string
+		if access.UserID != uuid.Nil {
+			userID = access.UserID.String()
+		}
+		masked, err := maskSensitiveJSON(body, userID)
+		if err != nil {
+			log.Errorf("[SensitiveDataMasking] %v", err)
+		} else {
+			body = masked
+		}
+
+		if _, err := writer.ResponseWriter.Write(body); err != nil {
+			log.Errorf("[SensitiveDataMasking] %v", err)
+		}
+	}
+}
+
+// IsSensitiveDataMasked reports whether sensitive fields have to be left out of the response, for
+// handlers writing something other than JSON such as spreadsheets
+func IsSensitiveDataMasked(c *gin.Context) bool {
+	return c.GetBool(SENSITIVE_DATA_MASKED_CONTEXT_KEY)
+}
+
+// SensitiveCellValue is the value a spreadsheet export writes for the given JSON field, nil when
+// the field is sensitive and the caller does not own the record and lacks the permission
+func SensitiveCellValue(c *gin.Context, field string, ownerID string, value interface{}) interface{} {
+	if !sensitiveResponseFields[field] || !IsSensitiveDataMasked(c) {
+		return value
+	}
+	if access, ok := GetAccess(c); ok && access.UserID != uuid.Nil && access.UserID.String() == ownerID {
+		return value
+	}
+	return nil
+}
+
+func maskSensitiveJSON(body []byte, userID string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// keep numbers as they were written, float64 would round large ids and amounts
+	decoder.UseNumber()
+
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(maskSensitiveValue(payload, userID))
+}
+
+func maskSensitiveValue(value interface{}, userID string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ownedBy(v, userID) {
+			return value
+		}
+		for key, field := range v {
+			if sensitiveResponseFields[key] {
+				v[key] = maskSensitiveField(key, field)
+			} else {
+				v[key] = maskSensitiveValue(field, userID)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = maskSensitiveValue(item, userID)
+		}
+	}
+
+	return value
+}
+
+// ownedBy reports whether the record is the caller's profile, or an applicant or document
+// sending carrying it
+func ownedBy(record map[string]interface{}, userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if id, ok := record["user_id"].(string); ok && id == userID {
+		return true
+	}
+	for _, key := range []string{"user_profile", "applicant"} {
+		if nested, ok := record[key].(map[string]interface{}); ok && ownedBy(nested, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+func maskSensitiveField(key string, value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		// amounts are dropped, a partly masked number would still read as a number
+		return nil
+	}
+	if text == "" {
+		return text
+	}
+
+	// the last digits of a KTP number are enough to tell candidates apart
+	if key == "ktp_number" && len(text) > 4 {
+		return strings.Repeat("*", len(text)-4) + text[len(text)-4:]
+	}
+
+	return "******"
+}
@@ -92,8 +92,12 @@ func InitConsumer(viper *viper.Viper, log *logrus.Logger) {
 			}
 
 			// find waiting channel(with uid) and forward the reply to it
-			if rchan, ok := utils.Rchans[docRply.ID]; ok {
-				rchan <- *docRply
+			if rchan, ok := utils.Rchans.Load(docRply.ID); ok {
+				// the waiter may have timed out and gone, the reply is then dropped
+				select {
+				case rchan.(chan response.RabbitMQResponse) <- *docRply:
+				default:
+				}
 			}
 
 			handleMsg(docMsg, log, viper)
@@ -345,6 +349,50 @@ func handleMsg(docMsg *request.RabbitMQRequest, log *logrus.Logger, viper *viper
 		msgData = map[string]interface{}{
 			"message": "success",
 		}
+	case "organization_updated", "organization_location_updated", "organization_structure_updated",
+		"job_updated", "job_level_updated", "grade_updated", "employee_updated", "user_updated":
+		// an empty id drops every cached entry of that kind
+		id, _ := docMsg.MessageData["id"].(string)
+		messaging.LookupCacheFactory().InvalidateByEvent(docMsg.MessageType, id)
+		log.Printf("INFO: invalidated lookup cache for %s: %s", docMsg.MessageType, id)
+
+		msgData = map[string]interface{}{
+			"message": "success",
+		}
+	case "token_revoked":
+		// sent by the auth service on logout, or with only user_id when every token of a user is revoked
+		tokenID, _ := docMsg.MessageData["token_id"].(string)
+		userID, _ := docMsg.MessageData["user_id"].(string)
+		reason, _ := docMsg.MessageData["reason"].(string)
+		if tokenID == "" && userID == "" {
+			log.Errorf("Invalid request format: missing 'token_id' or 'user_id'")
+			msgData = map[string]interface{}{
+				"error": errors.New("missing 'token_id' or 'user_id'").Error(),
+			}
+			break
+		}
+
+		payload := &request.RevokeTokenRequest{
+			TokenID: tokenID,
+			UserID:  userID,
+			Reason:  reason,
+		}
+		if expiresAt, ok := docMsg.MessageData["expires_at"].(string); ok {
+			if parsed, err := time.Parse(time.RFC3339, expiresAt); err == nil {
+				payload.ExpiresAt = &parsed
+			}
+		}
+
+		if _, err := usecase.TokenRevocationUseCaseFactory(log, viper).RevokeToken(payload); err != nil {
+			log.Errorf("Error when revoking token: %v", err)
+			msgData = map[string]interface{}{
+				"error": err.Error(),
+			}
+			break
+		}
+		msgData = map[string]interface{}{
+			"message": "success",
+		}
 	default:
 		log.Printf("Unknown message type, please recheck your type: %s", docMsg.MessageType)
 
@@ -0,0 +1,649 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MessagingFixtures is the data set the fake responder answers from. It mirrors
+// the payloads julong_sso, julong_manpower and julong_onboarding reply with.
+type MessagingFixtures struct {
+	Users                  []map[string]interface{} `json:"users"`
+	Employees              []map[string]interface{} `json:"employees"`
+	Organizations          []map[string]interface{} `json:"organizations"`
+	OrganizationLocations  []map[string]interface{} `json:"organization_locations"`
+	OrganizationStructures []map[string]interface{} `json:"organization_structures"`
+	Jobs                   []map[string]interface{} `json:"jobs"`
+	JobLevels              []map[string]interface{} `json:"job_levels"`
+	Grades                 []map[string]interface{} `json:"grades"`
+	MPRequestHeaders       []map[string]interface{} `json:"mp_request_headers"`
+	ChartDepartment        map[string]interface{}   `json:"chart_department"`
+}
+
+type IFakeResponder interface {
+	CanHandle(messageType string) bool
+	Handle(docMsg *request.RabbitMQRequest) map[string]interface{}
+}
+
+type FakeResponder struct {
+	Log         *logrus.Logger
+	Fixtures    *MessagingFixtures
+	DeliverMail bool
+	mu          sync.RWMutex
+	handlers    map[string]func(data map[string]interface{}) map[string]interface{}
+}
+
+func NewFakeResponder(log *logrus.Logger, fixtures *MessagingFixtures, deliverMail bool) IFakeResponder {
+	r := &FakeResponder{
+		Log:         log,
+		Fixtures:    fixtures,
+		DeliverMail: deliverMail,
+	}
+	r.handlers = map[string]func(data map[string]interface{}) map[string]interface{}{
+		// julong_sso: users
+		"find_user_by_id":                  r.findUserByID,
+		"get_user_me":                      r.getUserMe,
+		"get_user_ids_by_permission_names": r.getUserIDsByPermissionNames,
+		"find_user_by_employee_id":         r.findUserByEmployeeID,
+		// julong_sso: employees
+		"find_employee_by_id":                       r.findEmployeeByID,
+		"create_employee":                           r.createEmployee,
+		"get_chart_employee_organization_structure": r.getChartEmployeeOrganizationStructure,
+		"update_employee_midsuit":                   r.updateEmployeeMidsuit,
+		// julong_sso: organizations
+		"find_organization_by_id":                r.findOrganizationByID,
+		"find_all_organization":                  r.findAllOrganization,
+		"find_organization_location_by_id":       r.findOrganizationLocationByID,
+		"find_organization_structure_by_id":      r.findOrganizationStructureByID,
+		"find_organization_locations_paginated":  r.findOrganizationLocationsPaginated,
+		"find_all_organization_locations_by_ids": r.findAllOrganizationLocationsByIDs,
+		"find_all_org_structure_children_ids":    r.findAllOrgStructureChildrenIDs,
+		// julong_sso: jobs and grades
+		"find_job_by_id":         r.findJobByID,
+		"find_job_level_by_id":   r.findJobLevelByID,
+		"check_job_by_job_level": r.checkJobByJobLevel,
+		"find_grade_by_id":       r.findGradeByID,
+		// julong_sso: batched lookups
+		"find_organizations_by_ids":          r.batchLookup(r.findOrganizationByID, "organization_id", "organizations", ""),
+		"find_organization_locations_by_ids": r.batchLookup(r.findOrganizationLocationByID, "organization_location_id", "organization_locations", ""),
+		"find_jobs_by_ids":                   r.batchLookup(r.findJobByID, "job_id", "jobs", ""),
+		"find_job_levels_by_ids":             r.batchLookup(r.findJobLevelByID, "job_level_id", "job_levels", ""),
+		"find_grades_by_ids":                 r.batchLookup(r.findGradeByID, "id", "grades", ""),
+		"find_employees_by_ids":              r.batchLookup(r.findEmployeeByID, "employee_id", "employees", "employee"),
+		// julong_manpower
+		"find_mp_request_header_by_id":               r.findMPRequestHeaderByID,
+		"find_mp_request_header_by_id_tidak_lengkap": r.findMPRequestHeaderByID,
+		"find_mp_request_headers_by_majors":          r.findMPRequestHeadersByMajors,
+		// julong_onboarding
+		"create_employee_tasks": r.createEmployeeTasks,
+	}
+
+	return r
+}
+
+func FakeResponderFactory(log *logrus.Logger, fixturesPath string, deliverMail bool) (IFakeResponder, error) {
+	fixtures, err := LoadMessagingFixtures(fixturesPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewFakeResponder(log, fixtures, deliverMail), nil
+}
+
+func LoadMessagingFixtures(path string) (*MessagingFixtures, error) {
+	if path == "" {
+		return nil, errors.New("[LoadMessagingFixtures] fixtures path is empty")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New("[LoadMessagingFixtures] " + err.Error())
+	}
+
+	fixtures := &MessagingFixtures{}
+	if err := json.Unmarshal(content, fixtures); err != nil {
+		return nil, errors.New("[LoadMessagingFixtures] " + err.Error())
+	}
+
+	return fixtures, nil
+}
+
+func (r *FakeResponder) CanHandle(messageType string) bool {
+	if messageType == "send_mail" {
+		return !r.DeliverMail
+	}
+	_, ok := r.handlers[messageType]
+	return ok
+}
+
+func (r *FakeResponder) Handle(docMsg *request.RabbitMQRequest) map[string]interface{} {
+	if docMsg.MessageType == "send_mail" {
+		r.Log.Printf("INFO: [FakeResponder] mail to %v subject %v captured", docMsg.MessageData["to"], docMsg.MessageData["subject"])
+		return map[string]interface{}{
+			"message": "success",
+		}
+	}
+
+	handler, ok := r.handlers[docMsg.MessageType]
+	if !ok {
+		return errorData("unknown message type")
+	}
+
+	r.Log.Printf("INFO: [FakeResponder] handling %s", docMsg.MessageType)
+	return handler(docMsg.MessageData)
+}
+
+// batchLookup answers a "*_by_ids" message by running the single lookup for
+// every ID. Unknown IDs are left out of the reply, like the real services do.
+func (r *FakeResponder) batchLookup(single func(data map[string]interface{}) map[string]interface{}, idKey string, itemsKey string, itemKey string) func(data map[string]interface{}) map[string]interface{} {
+	return func(data map[string]interface{}) map[string]interface{} {
+		items := make([]interface{}, 0)
+		for _, id := range stringSliceValue(data, "ids") {
+			result := single(map[string]interface{}{idKey: id})
+			if _, failed := result["error"]; failed {
+				continue
+			}
+			if itemKey != "" {
+				items = append(items, result[itemKey])
+				continue
+			}
+			items = append(items, result)
+		}
+
+		return map[string]interface{}{
+			itemsKey: items,
+		}
+	}
+}
+
+func (r *FakeResponder) findUserByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user := findFixtureByID(r.Fixtures.Users, stringValue(data, "user_id"))
+	if user == nil {
+		return errorData("user not found")
+	}
+
+	return map[string]interface{}{
+		"user_id": user["id"],
+		"name":    user["name"],
+	}
+}
+
+func (r *FakeResponder) getUserMe(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user := findFixtureByID(r.Fixtures.Users, stringValue(data, "user_id"))
+	if user == nil {
+		return errorData("user not found")
+	}
+
+	userData := copyFixture(user)
+	if employeeID, ok := user["employee_id"].(string); ok && employeeID != "" {
+		if employee := findFixtureByID(r.Fixtures.Employees, employeeID); employee != nil {
+			userData["employee"] = copyFixture(employee)
+		}
+	}
+
+	return map[string]interface{}{
+		"user": userData,
+	}
+}
+
+func (r *FakeResponder) getUserIDsByPermissionNames(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	permissionNames := stringSliceValue(data, "permission_names")
+	userIDs := make([]string, 0)
+	for _, user := range r.Fixtures.Users {
+		permissions := stringSliceValue(user, "permissions")
+		if id, ok := user["id"].(string); ok && containsAny(permissions, permissionNames) {
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	return map[string]interface{}{
+		"user_ids": userIDs,
+	}
+}
+
+func (r *FakeResponder) findUserByEmployeeID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	employeeID := stringValue(data, "employee_id")
+	for _, user := range r.Fixtures.Users {
+		if user["employee_id"] == employeeID {
+			return map[string]interface{}{
+				"user_id": user["id"],
+				"name":    user["name"],
+			}
+		}
+	}
+
+	return errorData("user not found")
+}
+
+func (r *FakeResponder) findEmployeeByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	employee := findFixtureByID(r.Fixtures.Employees, stringValue(data, "employee_id"))
+	if employee == nil {
+		return errorData("employee not found")
+	}
+
+	return map[string]interface{}{
+		"employee": copyFixture(employee),
+	}
+}
+
+func (r *FakeResponder) createEmployee(data map[string]interface{}) map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	employeeID := uuid.New().String()
+	r.Fixtures.Employees = append(r.Fixtures.Employees, map[string]interface{}{
+		"id":              employeeID,
+		"organization_id": stringValue(data, "organization_id"),
+		"name":            stringValue(data, "name"),
+		"email":           stringValue(data, "email"),
+		"mobile_phone":    "",
+		"end_date":        "",
+		"retirement_date": "",
+		"midsuit_id":      "",
+		"employee_job": map[string]interface{}{
+			"job_id":                    stringValue(data, "job_id"),
+			"job_level_id":              stringValue(data, "job_level_id"),
+			"organization_location_id":  stringValue(data, "organization_location_id"),
+			"organization_structure_id": stringValue(data, "organization_structure_id"),
+		},
+	})
+
+	for _, user := range r.Fixtures.Users {
+		if user["id"] == stringValue(data, "user_id") {
+			user["employee_id"] = employeeID
+		}
+	}
+
+	return map[string]interface{}{
+		"employee_id": employeeID,
+	}
+}
+
+func (r *FakeResponder) getChartEmployeeOrganizationStructure(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chart := r.Fixtures.ChartDepartment
+	if chart == nil {
+		chart = map[string]interface{}{
+			"labels":   []string{},
+			"datasets": []int{},
+		}
+	}
+
+	return map[string]interface{}{
+		"chart": chart,
+	}
+}
+
+func (r *FakeResponder) updateEmployeeMidsuit(data map[string]interface{}) map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	employee := findFixtureByID(r.Fixtures.Employees, stringValue(data, "employee_id"))
+	if employee == nil {
+		return errorData("employee not found")
+	}
+	employee["midsuit_id"] = stringValue(data, "midsuit_id")
+
+	return map[string]interface{}{
+		"message": "success",
+	}
+}
+
+func (r *FakeResponder) findOrganizationByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	org := findFixtureByID(r.Fixtures.Organizations, stringValue(data, "organization_id"))
+	if org == nil {
+		return errorData("organization not found")
+	}
+
+	return map[string]interface{}{
+		"organization_id":       org["id"],
+		"name":                  org["name"],
+		"organization_category": org["organization_category"],
+		"organization_type":     org["organization_type"],
+		"logo":                  org["logo"],
+		"midsuit_id":            org["midsuit_id"],
+	}
+}
+
+func (r *FakeResponder) findAllOrganization(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return map[string]interface{}{
+		"organizations": filterFixturesByIDs(r.Fixtures.Organizations, stringSliceValue(data, "included_ids")),
+	}
+}
+
+func (r *FakeResponder) findOrganizationLocationByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orgLoc := findFixtureByID(r.Fixtures.OrganizationLocations, stringValue(data, "organization_location_id"))
+	if orgLoc == nil {
+		return errorData("organization location not found")
+	}
+
+	return map[string]interface{}{
+		"organization_location_id": orgLoc["id"],
+		"name":                     orgLoc["name"],
+		"midsuit_id":               orgLoc["midsuit_id"],
+	}
+}
+
+func (r *FakeResponder) findOrganizationStructureByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orgStructure := findFixtureByID(r.Fixtures.OrganizationStructures, stringValue(data, "organization_structure_id"))
+	if orgStructure == nil {
+		return errorData("organization structure not found")
+	}
+
+	return map[string]interface{}{
+		"organization_structure_id": orgStructure["id"],
+		"name":                      orgStructure["name"],
+		"midsuit_id":                orgStructure["midsuit_id"],
+	}
+}
+
+func (r *FakeResponder) findOrganizationLocationsPaginated(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	page := intValue(data, "page", 1)
+	pageSize := intValue(data, "page_size", 10)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	search := strings.ToLower(stringValue(data, "search"))
+	orgID := stringValue(data, "organization_id")
+
+	filtered := make([]map[string]interface{}, 0)
+	for _, orgLoc := range filterFixturesByIDs(r.Fixtures.OrganizationLocations, stringSliceValue(data, "included_ids")) {
+		if orgID != "" && orgLoc["organization_id"] != orgID {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(stringValue(orgLoc, "name")), search) {
+			continue
+		}
+		filtered = append(filtered, orgLoc)
+	}
+
+	// compared before multiplying, a huge page or page size would overflow into a negative index
+	start := len(filtered)
+	if page-1 <= len(filtered)/pageSize {
+		start = min((page-1)*pageSize, len(filtered))
+	}
+	end := len(filtered)
+	if pageSize < end-start {
+		end = start + pageSize
+	}
+
+	return map[string]interface{}{
+		"organization_locations": filtered[start:end],
+		"total":                  len(filtered),
+	}
+}
+
+func (r *FakeResponder) findAllOrganizationLocationsByIDs(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return map[string]interface{}{
+		"organization_locations": filterFixturesByIDs(r.Fixtures.OrganizationLocations, stringSliceValue(data, "included_ids")),
+	}
+}
+
+func (r *FakeResponder) findAllOrgStructureChildrenIDs(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	childrenIDs := make([]string, 0)
+	parents := []string{stringValue(data, "parent_id")}
+	for len(parents) > 0 {
+		parentID := parents[0]
+		parents = parents[1:]
+		for _, orgStructure := range r.Fixtures.OrganizationStructures {
+			if orgStructure["parent_id"] == parentID {
+				childID := orgStructure["id"].(string)
+				childrenIDs = append(childrenIDs, childID)
+				parents = append(parents, childID)
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"children_ids": childrenIDs,
+	}
+}
+
+func (r *FakeResponder) findJobByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job := findFixtureByID(r.Fixtures.Jobs, stringValue(data, "job_id"))
+	if job == nil {
+		return errorData("job not found")
+	}
+
+	return map[string]interface{}{
+		"job_id":     job["id"],
+		"name":       job["name"],
+		"midsuit_id": job["midsuit_id"],
+	}
+}
+
+func (r *FakeResponder) findJobLevelByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobLevel := findFixtureByID(r.Fixtures.JobLevels, stringValue(data, "job_level_id"))
+	if jobLevel == nil {
+		return errorData("job level not found")
+	}
+
+	return map[string]interface{}{
+		"job_level_id": jobLevel["id"],
+		"name":         jobLevel["name"],
+		"level":        jobLevel["level"],
+		"midsuit_id":   jobLevel["midsuit_id"],
+	}
+}
+
+func (r *FakeResponder) checkJobByJobLevel(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobID := stringValue(data, "job_id")
+	jobLevelID := stringValue(data, "job_level_id")
+	for _, job := range r.Fixtures.Jobs {
+		if job["id"] == jobID && (jobLevelID == "" || job["job_level_id"] == jobLevelID) {
+			return map[string]interface{}{
+				"job_id": jobID,
+			}
+		}
+	}
+
+	return errorData("job not found")
+}
+
+func (r *FakeResponder) findGradeByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	grade := findFixtureByID(r.Fixtures.Grades, stringValue(data, "id"))
+	if grade == nil {
+		return errorData("grade not found")
+	}
+
+	return copyFixture(grade)
+}
+
+func (r *FakeResponder) findMPRequestHeaderByID(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mpRequestHeader := findFixtureByID(r.Fixtures.MPRequestHeaders, stringValue(data, "mp_request_header_id"))
+	if mpRequestHeader == nil {
+		return errorData("mp request header not found")
+	}
+
+	return map[string]interface{}{
+		"mp_request_header": copyFixture(mpRequestHeader),
+	}
+}
+
+func (r *FakeResponder) findMPRequestHeadersByMajors(data map[string]interface{}) map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	majors := stringSliceValue(data, "majors")
+	educationLevels := stringSliceValue(data, "education_levels")
+
+	headers := make([]map[string]interface{}, 0)
+	for _, mpRequestHeader := range r.Fixtures.MPRequestHeaders {
+		if len(educationLevels) > 0 && !containsAny(educationLevels, []string{stringValue(mpRequestHeader, "minimum_education")}) {
+			continue
+		}
+		if len(majors) > 0 && !containsAny(collectStrings(mpRequestHeader["request_majors"]), majors) {
+			continue
+		}
+		headers = append(headers, map[string]interface{}{
+			"id": mpRequestHeader["id"],
+		})
+	}
+
+	return map[string]interface{}{
+		"mp_request_headers": headers,
+	}
+}
+
+func (r *FakeResponder) createEmployeeTasks(data map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"message": "success",
+	}
+}
+
+func errorData(message string) map[string]interface{} {
+	return map[string]interface{}{
+		"error": message,
+	}
+}
+
+func findFixtureByID(fixtures []map[string]interface{}, id string) map[string]interface{} {
+	for _, fixture := range fixtures {
+		if fixture["id"] == id {
+			return fixture
+		}
+	}
+	return nil
+}
+
+func filterFixturesByIDs(fixtures []map[string]interface{}, ids []string) []map[string]interface{} {
+	if len(ids) == 0 {
+		return fixtures
+	}
+
+	filtered := make([]map[string]interface{}, 0)
+	for _, fixture := range fixtures {
+		if id, ok := fixture["id"].(string); ok && containsAny(ids, []string{id}) {
+			filtered = append(filtered, fixture)
+		}
+	}
+	return filtered
+}
+
+func copyFixture(fixture map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(fixture))
+	for key, value := range fixture {
+		copied[key] = value
+	}
+	return copied
+}
+
+func stringValue(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
+
+func intValue(data map[string]interface{}, key string, fallback int) int {
+	switch value := data[key].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+	return fallback
+}
+
+func stringSliceValue(data map[string]interface{}, key string) []string {
+	values := make([]string, 0)
+	switch raw := data[key].(type) {
+	case []string:
+		values = append(values, raw...)
+	case []interface{}:
+		for _, value := range raw {
+			if str, ok := value.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+	return values
+}
+
+func collectStrings(value interface{}) []string {
+	values := make([]string, 0)
+	switch raw := value.(type) {
+	case string:
+		values = append(values, raw)
+	case []interface{}:
+		for _, item := range raw {
+			values = append(values, collectStrings(item)...)
+		}
+	case map[string]interface{}:
+		for _, item := range raw {
+			values = append(values, collectStrings(item)...)
+		}
+	}
+	return values
+}
+
+func containsAny(values []string, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if strings.EqualFold(value, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
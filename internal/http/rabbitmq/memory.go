@@ -0,0 +1,94 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// InitMemoryBroker replaces InitConsumer and InitProducer when rabbitmq.driver is
+// "memory". Outgoing messages are answered by the fixture-backed fake responder,
+// messages addressed to our own queue are dispatched to handleMsg, and every
+// payload is passed through JSON so handlers see the same types as over AMQP.
+func InitMemoryBroker(viper *viper.Viper, log *logrus.Logger) {
+	responder, err := FakeResponderFactory(log, viper.GetString("rabbitmq.memory.fixtures"), viper.GetBool("rabbitmq.memory.deliver_mail"))
+	if err != nil {
+		log.Printf("ERROR: fail init memory broker: %s", err.Error())
+		os.Exit(1)
+	}
+
+	ownQueue := viper.GetString("rabbitmq.queue")
+
+	log.Printf("INFO: done init memory broker")
+
+	for {
+		select {
+		case msg := <-utils.Pchan:
+			docMsg := &request.RabbitMQRequest{}
+			if err := roundTripJSON(&msg.Message, docMsg); err != nil {
+				log.Printf("ERROR: fail marshal: %s", err.Error())
+				continue
+			}
+			log.Printf("INFO: published msg: %v to: %s (memory)", docMsg, msg.QueueName)
+
+			if responder.CanHandle(docMsg.MessageType) {
+				go deliverMemoryReply(log, response.RabbitMQResponse{
+					ID:          docMsg.ID,
+					MessageType: "reply",
+					MessageData: responder.Handle(docMsg),
+				})
+				continue
+			}
+
+			if msg.QueueName == ownQueue {
+				go handleMsg(docMsg, log, viper)
+				continue
+			}
+
+			log.Printf("Unknown message type, please recheck your type: %s", docMsg.MessageType)
+			go deliverMemoryReply(log, response.RabbitMQResponse{
+				ID:          docMsg.ID,
+				MessageType: "reply",
+				MessageData: map[string]interface{}{
+					"error": errors.New("unknown message type").Error(),
+				},
+			})
+		case msg := <-utils.Rchan:
+			go deliverMemoryReply(log, msg.Reply)
+		}
+	}
+}
+
+func deliverMemoryReply(log *logrus.Logger, reply response.RabbitMQResponse) {
+	docRply := &response.RabbitMQResponse{}
+	if err := roundTripJSON(&reply, docRply); err != nil {
+		log.Printf("ERROR: fail marshal: %s", err.Error())
+		return
+	}
+
+	// find waiting channel(with uid) and forward the reply to it
+	if rchan, ok := utils.Rchans.Load(docRply.ID); ok {
+		// the waiter may have timed out and gone, the reply is then dropped
+		select {
+		case rchan.(chan response.RabbitMQResponse) <- *docRply:
+		default:
+		}
+		return
+	}
+
+	log.Printf("INFO: no waiting channel for reply uid: %s", docRply.ID)
+}
+
+func roundTripJSON(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,24 @@
+package request
+
+// ApplyJobPostingSourceRequest is the source of an application, taken from the UTM parameters of
+// the link the candidate followed and the referral code if there was one
+type ApplyJobPostingSourceRequest struct {
+	UtmSource    string `form:"utm_source" validate:"omitempty,max=255"`
+	UtmMedium    string `form:"utm_medium" validate:"omitempty,max=255"`
+	UtmCampaign  string `form:"utm_campaign" validate:"omitempty,max=255"`
+	ReferralCode string `form:"ref" validate:"omitempty,max=100"`
+}
+
+type SetApplicantSourceRequest struct {
+	Channel      string `json:"channel" validate:"required,oneof=CAREERS_SITE JOB_BOARD CAMPUS SOCIAL_MEDIA REFERRAL OTHER"`
+	UtmSource    string `json:"utm_source" validate:"omitempty,max=255"`
+	UtmMedium    string `json:"utm_medium" validate:"omitempty,max=255"`
+	UtmCampaign  string `json:"utm_campaign" validate:"omitempty,max=255"`
+	ReferralCode string `json:"referral_code" validate:"omitempty,max=100"`
+	Note         string `json:"note" validate:"omitempty"`
+}
+
+type ApplicantSourceReportRequest struct {
+	DashboardFilterRequest
+	JobPostingID string `form:"job_posting_id" validate:"omitempty,uuid"`
+}
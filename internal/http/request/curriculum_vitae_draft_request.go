@@ -0,0 +1,37 @@
+package request
+
+// AcceptCurriculumVitaeDraftRequest holds the corrections the candidate made while reviewing a
+// CV draft. Fields left out keep what the parser read, an entry list that is sent replaces
+// the entries of the draft.
+type AcceptCurriculumVitaeDraftRequest struct {
+	Name            *string                                     `json:"name" validate:"omitempty"`
+	PhoneNumber     *string                                     `json:"phone_number" validate:"omitempty"`
+	Gender          *string                                     `json:"gender" validate:"omitempty,user_gender_validation"`
+	BirthPlace      *string                                     `json:"birth_place" validate:"omitempty"`
+	BirthDate       *string                                     `json:"birth_date" validate:"omitempty,datetime=2006-01-02"`
+	Address         *string                                     `json:"address" validate:"omitempty"`
+	Educations      []CurriculumVitaeDraftEducationRequest      `json:"educations" validate:"omitempty,dive"`
+	WorkExperiences []CurriculumVitaeDraftWorkExperienceRequest `json:"work_experiences" validate:"omitempty,dive"`
+	Skills          []CurriculumVitaeDraftSkillRequest          `json:"skills" validate:"omitempty,dive"`
+}
+
+type CurriculumVitaeDraftEducationRequest struct {
+	EducationLevel string   `json:"education_level" validate:"required,education_level_validation"`
+	Major          string   `json:"major" validate:"required"`
+	SchoolName     string   `json:"school_name" validate:"required"`
+	UniversityID   *string  `json:"university_id" validate:"omitempty,uuid"`
+	GraduateYear   int      `json:"graduate_year" validate:"required,gte=1950"`
+	Gpa            *float64 `json:"gpa" validate:"omitempty,gte=0"`
+}
+
+type CurriculumVitaeDraftWorkExperienceRequest struct {
+	Name           string `json:"name" validate:"required"`
+	CompanyName    string `json:"company_name" validate:"required"`
+	YearExperience int    `json:"year_experience" validate:"omitempty,gte=0"`
+	JobDescription string `json:"job_description" validate:"omitempty"`
+}
+
+type CurriculumVitaeDraftSkillRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description" validate:"omitempty"`
+}
@@ -0,0 +1,10 @@
+package request
+
+type DashboardFilterRequest struct {
+	StartDate                  string `form:"start_date" validate:"required_with=EndDate,omitempty,datetime=2006-01-02"`
+	EndDate                    string `form:"end_date" validate:"required_with=StartDate,omitempty,datetime=2006-01-02"`
+	ForOrganizationID          string `form:"for_organization_id" validate:"omitempty,uuid"`
+	OrganizationLocationID     string `form:"organization_location_id" validate:"omitempty,uuid"`
+	RecruitmentType            string `form:"recruitment_type" validate:"omitempty,oneof=MT PH NS"`
+	ProjectRecruitmentHeaderID string `form:"project_recruitment_header_id" validate:"omitempty,uuid"`
+}
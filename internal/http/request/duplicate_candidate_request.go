@@ -0,0 +1,5 @@
+package request
+
+type MergeDuplicateCandidateRequest struct {
+	SurvivingProfileID string `json:"surviving_profile_id" validate:"required,uuid"`
+}
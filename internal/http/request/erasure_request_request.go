@@ -0,0 +1,9 @@
+package request
+
+type CreateErasureRequestRequest struct {
+	Reason string `json:"reason" validate:"omitempty,max=2000"`
+}
+
+type ReviewErasureRequestRequest struct {
+	Note string `json:"note" validate:"omitempty,max=2000"`
+}
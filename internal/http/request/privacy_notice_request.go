@@ -0,0 +1,11 @@
+package request
+
+type PublishPrivacyNoticeRequest struct {
+	Version string `json:"version" validate:"required,max=50"`
+	Title   string `json:"title" validate:"required,max=255"`
+	Content string `json:"content" validate:"required"`
+}
+
+type ConsentPrivacyNoticeRequest struct {
+	Version string `json:"version" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type RecruitmentFunnelRequest struct {
+	JobPostingID               string `form:"job_posting_id" validate:"required_without=ProjectRecruitmentHeaderID,omitempty,uuid"`
+	ProjectRecruitmentHeaderID string `form:"project_recruitment_header_id" validate:"required_without=JobPostingID,omitempty,uuid"`
+}
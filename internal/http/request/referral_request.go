@@ -0,0 +1,17 @@
+package request
+
+type CreateReferralLinkRequest struct {
+	JobPostingID string `json:"job_posting_id" validate:"required,uuid"`
+}
+
+type CreateReferralRequest struct {
+	JobPostingID string `json:"job_posting_id" validate:"required,uuid"`
+	Name         string `json:"name" validate:"required,max=255"`
+	Email        string `json:"email" validate:"required,email,max=255"`
+	PhoneNumber  string `json:"phone_number" validate:"omitempty,max=255"`
+	Note         string `json:"note" validate:"omitempty"`
+}
+
+type ReferralBonusReportRequest struct {
+	Status string `form:"status" validate:"omitempty,oneof=ELIGIBLE ON_PROBATION NOT_JOINED"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type SlaOverdueQueueRequest struct {
+	EmployeeID string `form:"employee_id" validate:"omitempty,uuid"`
+	Type       string `form:"type" validate:"omitempty,oneof=STAGE RESULT"`
+}
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+type RevokeTokenRequest struct {
+	TokenID   string     `json:"token_id" validate:"required_without=UserID"`
+	UserID    string     `json:"user_id" validate:"required_without=TokenID,omitempty,uuid"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty"`
+	Reason    string     `json:"reason" validate:"omitempty"`
+}
@@ -16,6 +16,8 @@ type FillUserProfileRequest struct {
 	ExpectedSalary  int                   `form:"expected_salary" validate:"required"`
 	CurrentSalary   int                   `form:"current_salary" validate:"omitempty"`
 	Religion        string                `form:"religion" validate:"omitempty"`
+	KtpNumber       string                `form:"ktp_number" validate:"omitempty,numeric,len=16"`
+	Email           string                `form:"-" validate:"omitempty,email"`
 	Ktp             *multipart.FileHeader `form:"ktp" validate:"omitempty"`
 	CurriculumVitae *multipart.FileHeader `form:"curriculum_vitae" validate:"omitempty"`
 	KtpPath         string                `form:"ktp_path" validate:"omitempty"`
@@ -23,6 +25,11 @@ type FillUserProfileRequest struct {
 	WorkExperiences []WorkExperience      `form:"work_experiences" validate:"omitempty,dive"`
 	Educations      []Education           `form:"educations" validate:"omitempty,dive"`
 	Skills          []Skill               `form:"skills" validate:"omitempty,dive"`
+
+	// the version of the privacy notice the candidate agreed to, required to create a profile
+	PrivacyNoticeVersion string `form:"privacy_notice_version" validate:"omitempty,max=50"`
+	ConsentIPAddress     string `form:"-"`
+	ConsentUserAgent     string `form:"-"`
 }
 
 type UpdateStatusUserProfileRequest struct {
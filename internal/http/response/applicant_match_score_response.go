@@ -0,0 +1,23 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+)
+
+type ApplicantMatchScoreResponse struct {
+	Score    float64                           `json:"score"`
+	ScoredAt time.Time                         `json:"scored_at"`
+	Items    []ApplicantMatchScoreItemResponse `json:"items"`
+}
+
+type ApplicantMatchScoreItemResponse struct {
+	Criterion      entity.ApplicantMatchCriterion `json:"criterion"`
+	Weight         float64                        `json:"weight"`
+	Score          float64                        `json:"score"`
+	Applicable     bool                           `json:"applicable"`
+	Requirement    string                         `json:"requirement"`
+	CandidateValue string                         `json:"candidate_value"`
+	Note           string                         `json:"note"`
+}
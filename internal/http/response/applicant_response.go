@@ -21,4 +21,6 @@ type ApplicantResponse struct {
 	TemplateQuestion *TemplateQuestionResponse     `json:"template_question"`
 	JobPosting       *JobPostingResponse           `json:"job_posting"`
 	UserProfile      *UserProfileResponse          `json:"user_profile"`
+	MatchScore       *ApplicantMatchScoreResponse  `json:"match_score"`
+	Source           *ApplicantSourceResponse      `json:"source"`
 }
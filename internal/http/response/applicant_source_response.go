@@ -0,0 +1,44 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type ApplicantSourceResponse struct {
+	ApplicantID  uuid.UUID                     `json:"applicant_id"`
+	Channel      entity.ApplicantSourceChannel `json:"channel"`
+	UtmSource    string                        `json:"utm_source"`
+	UtmMedium    string                        `json:"utm_medium"`
+	UtmCampaign  string                        `json:"utm_campaign"`
+	ReferralCode string                        `json:"referral_code"`
+	Note         string                        `json:"note"`
+	SetBy        *uuid.UUID                    `json:"set_by"`
+	UpdatedAt    time.Time                     `json:"updated_at"`
+}
+
+type ApplicantSourceReportResponse struct {
+	Totals      []ApplicantSourceStatsResponse           `json:"totals"`
+	JobPostings []ApplicantSourceJobPostingStatsResponse `json:"job_postings"`
+}
+
+type ApplicantSourceJobPostingStatsResponse struct {
+	JobPostingID   uuid.UUID                      `json:"job_posting_id"`
+	JobPostingName string                         `json:"job_posting_name"`
+	Sources        []ApplicantSourceStatsResponse `json:"sources"`
+}
+
+// ApplicantSourceStatsResponse counts the applications of a source, the ones that got past the
+// first stage (Conversions) and the hires. Channel is UNKNOWN for applicants who applied before
+// sources were tracked.
+type ApplicantSourceStatsResponse struct {
+	Channel        string  `json:"channel"`
+	UtmSource      string  `json:"utm_source"`
+	Applications   int     `json:"applications"`
+	Conversions    int     `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"`
+	Hires          int     `json:"hires"`
+	HireRate       float64 `json:"hire_rate"`
+}
@@ -0,0 +1,18 @@
+package response
+
+type CandidateSearchResultResponse struct {
+	UserProfile *UserProfileResponse `json:"user_profile"`
+	Rank        float64              `json:"rank"`
+	Highlights  []string             `json:"highlights"`
+}
+
+type CandidateSearchFacetResponse struct {
+	Value string `json:"value"`
+	Total int64  `json:"total"`
+}
+
+type CandidateSearchResponse struct {
+	Candidates []CandidateSearchResultResponse           `json:"candidates"`
+	Total      int64                                     `json:"total"`
+	Facets     map[string][]CandidateSearchFacetResponse `json:"facets"`
+}
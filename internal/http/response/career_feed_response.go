@@ -0,0 +1,149 @@
+package response
+
+import "encoding/xml"
+
+// RSS 2.0
+
+type RssFeedResponse struct {
+	XMLName   xml.Name           `xml:"rss"`
+	Version   string             `xml:"version,attr"`
+	AtomXmlns string             `xml:"xmlns:atom,attr"`
+	Channel   RssChannelResponse `xml:"channel"`
+}
+
+type RssChannelResponse struct {
+	Title         string            `xml:"title"`
+	Link          string            `xml:"link"`
+	Description   string            `xml:"description"`
+	Language      string            `xml:"language,omitempty"`
+	LastBuildDate string            `xml:"lastBuildDate"`
+	AtomLink      AtomLinkResponse  `xml:"atom:link"`
+	Items         []RssItemResponse `xml:"item"`
+}
+
+type RssItemResponse struct {
+	Title       string          `xml:"title"`
+	Link        string          `xml:"link"`
+	GUID        RssGUIDResponse `xml:"guid"`
+	Description string          `xml:"description"`
+	Category    string          `xml:"category,omitempty"`
+	PubDate     string          `xml:"pubDate"`
+}
+
+type RssGUIDResponse struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Atom
+
+type AtomFeedResponse struct {
+	XMLName xml.Name            `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string              `xml:"id"`
+	Title   string              `xml:"title"`
+	Updated string              `xml:"updated"`
+	Links   []AtomLinkResponse  `xml:"link"`
+	Entries []AtomEntryResponse `xml:"entry"`
+}
+
+type AtomLinkResponse struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntryResponse struct {
+	ID        string                `xml:"id"`
+	Title     string                `xml:"title"`
+	Updated   string                `xml:"updated"`
+	Published string                `xml:"published"`
+	Link      AtomLinkResponse      `xml:"link"`
+	Author    AtomAuthorResponse    `xml:"author"`
+	Content   AtomContentResponse   `xml:"content"`
+	Category  *AtomCategoryResponse `xml:"category,omitempty"`
+}
+
+type AtomAuthorResponse struct {
+	Name string `xml:"name"`
+}
+
+type AtomContentResponse struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type AtomCategoryResponse struct {
+	Term string `xml:"term,attr"`
+}
+
+// Sitemap
+
+type SitemapResponse struct {
+	XMLName xml.Name             `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURLResponse `xml:"url"`
+}
+
+type SitemapURLResponse struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+}
+
+// schema.org JobPosting, embedded by the careers site as JSON-LD
+
+type JobPostingSchemaResponse struct {
+	Context            string                        `json:"@context"`
+	Type               string                        `json:"@type"`
+	Title              string                        `json:"title"`
+	Description        string                        `json:"description"`
+	Identifier         SchemaPropertyValueResponse   `json:"identifier"`
+	DatePosted         string                        `json:"datePosted"`
+	ValidThrough       string                        `json:"validThrough"`
+	EmploymentType     string                        `json:"employmentType,omitempty"`
+	HiringOrganization SchemaOrganizationResponse    `json:"hiringOrganization"`
+	JobLocation        SchemaPlaceResponse           `json:"jobLocation"`
+	BaseSalary         *SchemaMonetaryAmountResponse `json:"baseSalary,omitempty"`
+	URL                string                        `json:"url"`
+	Image              string                        `json:"image,omitempty"`
+}
+
+type SchemaPropertyValueResponse struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type SchemaOrganizationResponse struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	Logo string `json:"logo,omitempty"`
+}
+
+type SchemaPlaceResponse struct {
+	Type    string                      `json:"@type"`
+	Address SchemaPostalAddressResponse `json:"address"`
+}
+
+type SchemaPostalAddressResponse struct {
+	Type            string `json:"@type"`
+	AddressLocality string `json:"addressLocality,omitempty"`
+	AddressCountry  string `json:"addressCountry"`
+}
+
+type SchemaMonetaryAmountResponse struct {
+	Type     string                          `json:"@type"`
+	Currency string                          `json:"currency"`
+	Value    SchemaQuantitativeValueResponse `json:"value"`
+}
+
+type SchemaQuantitativeValueResponse struct {
+	Type     string   `json:"@type"`
+	MinValue *float64 `json:"minValue,omitempty"`
+	MaxValue *float64 `json:"maxValue,omitempty"`
+	UnitText string   `json:"unitText"`
+}
+
+type PublicJobPostingResponse struct {
+	JobPosting JobPostingResponse       `json:"job_posting"`
+	JsonLD     JobPostingSchemaResponse `json:"json_ld"`
+}
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type CurriculumVitaeDraftResponse struct {
+	ID              uuid.UUID                                    `json:"id"`
+	UserID          uuid.UUID                                    `json:"user_id"`
+	UserProfileID   *uuid.UUID                                   `json:"user_profile_id"`
+	CurriculumVitae string                                       `json:"curriculum_vitae"`
+	Status          entity.CurriculumVitaeDraftStatus            `json:"status"`
+	Name            string                                       `json:"name"`
+	Email           string                                       `json:"email"`
+	PhoneNumber     string                                       `json:"phone_number"`
+	Gender          entity.UserGender                            `json:"gender"`
+	BirthPlace      string                                       `json:"birth_place"`
+	BirthDate       *time.Time                                   `json:"birth_date"`
+	Address         string                                       `json:"address"`
+	AcceptedAt      *time.Time                                   `json:"accepted_at"`
+	Educations      []CurriculumVitaeDraftEducationResponse      `json:"educations"`
+	WorkExperiences []CurriculumVitaeDraftWorkExperienceResponse `json:"work_experiences"`
+	Skills          []CurriculumVitaeDraftSkillResponse          `json:"skills"`
+	CreatedAt       time.Time                                    `json:"created_at"`
+	UpdatedAt       time.Time                                    `json:"updated_at"`
+}
+
+type CurriculumVitaeDraftEducationResponse struct {
+	EducationLevel entity.EducationLevelEnum `json:"education_level"`
+	Major          string                    `json:"major"`
+	SchoolName     string                    `json:"school_name"`
+	UniversityID   *uuid.UUID                `json:"university_id"`
+	GraduateYear   int                       `json:"graduate_year"`
+	Gpa            *float64                  `json:"gpa"`
+}
+
+type CurriculumVitaeDraftWorkExperienceResponse struct {
+	Name           string `json:"name"`
+	CompanyName    string `json:"company_name"`
+	YearExperience int    `json:"year_experience"`
+	JobDescription string `json:"job_description"`
+}
+
+type CurriculumVitaeDraftSkillResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -8,6 +8,7 @@ type DashboardResponse struct {
 	ChartJobLevelResponse               ChartJobLevelResponse               `json:"chart_job_level"`
 	ChartDepartmentResponse             ChartDepartmentResponse             `json:"chart_department"`
 	AvgTimeToHireResponse               AvgTimeToHireResponse               `json:"avg_time_to_hire"`
+	Comparison                          *DashboardComparisonResponse        `json:"comparison,omitempty"`
 }
 
 type TotalRecruitmentTargetResponse struct {
@@ -50,3 +51,22 @@ type ChartDepartmentMessageResponse struct {
 type AvgTimeToHireResponse struct {
 	AvgTimeToHire int `json:"avg_time_to_hire"`
 }
+
+// DashboardComparisonResponse compares the headline figures with the period of equal length
+// right before the requested one
+type DashboardComparisonResponse struct {
+	PreviousStartDate           string                            `json:"previous_start_date"`
+	PreviousEndDate             string                            `json:"previous_end_date"`
+	TotalRecruitmentTarget      DashboardMetricComparisonResponse `json:"total_recruitment_target"`
+	TotalRecruitmentRealization DashboardMetricComparisonResponse `json:"total_recruitment_realization"`
+	TotalBilingual              DashboardMetricComparisonResponse `json:"total_bilingual"`
+	AvgTimeToHire               DashboardMetricComparisonResponse `json:"avg_time_to_hire"`
+}
+
+type DashboardMetricComparisonResponse struct {
+	Current  int `json:"current"`
+	Previous int `json:"previous"`
+	Change   int `json:"change"`
+	// nil when there is nothing to compare with
+	ChangePercentage *float64 `json:"change_percentage"`
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type DuplicateCandidateResponse struct {
+	ID                  uuid.UUID                       `json:"id"`
+	Status              entity.DuplicateCandidateStatus `json:"status"`
+	Score               int                             `json:"score"`
+	MatchKtpNumber      bool                            `json:"match_ktp_number"`
+	MatchPhoneNumber    bool                            `json:"match_phone_number"`
+	MatchEmail          bool                            `json:"match_email"`
+	MatchNameBirthDate  bool                            `json:"match_name_birth_date"`
+	SharedJobPostingIDs []uuid.UUID                     `json:"shared_job_posting_ids"`
+	DetectedAt          time.Time                       `json:"detected_at"`
+	ResolvedAt          *time.Time                      `json:"resolved_at"`
+	ResolvedBy          *uuid.UUID                      `json:"resolved_by"`
+	UserProfile         *UserProfileResponse            `json:"user_profile"`
+	DuplicateProfile    *UserProfileResponse            `json:"duplicate_profile"`
+}
+
+type UserProfileMergeResponse struct {
+	ID                     uuid.UUID  `json:"id"`
+	DuplicateCandidateID   *uuid.UUID `json:"duplicate_candidate_id"`
+	SurvivingProfileID     uuid.UUID  `json:"surviving_profile_id"`
+	MergedProfileID        uuid.UUID  `json:"merged_profile_id"`
+	MergedUserID           *uuid.UUID `json:"merged_user_id"`
+	MergedBy               uuid.UUID  `json:"merged_by"`
+	MergedAt               time.Time  `json:"merged_at"`
+	MovedApplicants        int        `json:"moved_applicants"`
+	DroppedApplicants      int        `json:"dropped_applicants"`
+	MovedEducations        int        `json:"moved_educations"`
+	MovedWorkExperiences   int        `json:"moved_work_experiences"`
+	MovedSkills            int        `json:"moved_skills"`
+	MovedQuestionResponses int        `json:"moved_question_responses"`
+	Note                   string     `json:"note"`
+}
@@ -0,0 +1,23 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type ErasureRequestResponse struct {
+	ID                  uuid.UUID                   `json:"id"`
+	UserProfileID       uuid.UUID                   `json:"user_profile_id"`
+	UserProfileName     string                      `json:"user_profile_name"`
+	Status              entity.ErasureRequestStatus `json:"status"`
+	Reason              string                      `json:"reason"`
+	RequestedAt         time.Time                   `json:"requested_at"`
+	ReviewedBy          *uuid.UUID                  `json:"reviewed_by"`
+	ReviewedAt          *time.Time                  `json:"reviewed_at"`
+	ReviewNote          string                      `json:"review_note"`
+	FailedFiles         []string                    `json:"failed_files"`
+	ApplicationCount    int                         `json:"application_count"`
+	HasHiredApplication bool                        `json:"has_hired_application"`
+}
@@ -34,6 +34,7 @@ type JobPostingResponse struct {
 	MinimumWorkExperience      string                        `json:"minimum_work_experience"`
 	Name                       string                        `json:"name"`
 	IsShow                     string                        `json:"is_show"`
+	Slug                       string                        `json:"slug"`
 
 	ForOrganizationName     string `json:"for_organization_name"`
 	ForOrganizationLocation string `json:"for_organization_location"`
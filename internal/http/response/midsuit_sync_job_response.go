@@ -0,0 +1,86 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/google/uuid"
+)
+
+type MidsuitSyncJobResponse struct {
+	ID                uuid.UUID                `json:"id"`
+	DocumentSendingID uuid.UUID                `json:"document_sending_id"`
+	ApplicantID       uuid.UUID                `json:"applicant_id"`
+	EmployeeID        *uuid.UUID               `json:"employee_id"`
+	IdempotencyKey    string                   `json:"idempotency_key"`
+	Status            entity.MidsuitSyncStatus `json:"status"`
+	Attempts          int                      `json:"attempts"`
+	MaxAttempts       int                      `json:"max_attempts"`
+	NextRunAt         *time.Time               `json:"next_run_at"`
+	StartedAt         *time.Time               `json:"started_at"`
+	FinishedAt        *time.Time               `json:"finished_at"`
+	LastError         string                   `json:"last_error"`
+	MidsuitEmployeeID string                   `json:"midsuit_employee_id"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+
+	Steps []MidsuitSyncStepResponse `json:"steps"`
+}
+
+type MidsuitSyncStepResponse struct {
+	ID              uuid.UUID                `json:"id"`
+	Name            string                   `json:"name"`
+	Order           int                      `json:"order"`
+	IdempotencyKey  string                   `json:"idempotency_key"`
+	Status          entity.MidsuitSyncStatus `json:"status"`
+	Attempts        int                      `json:"attempts"`
+	LastError       string                   `json:"last_error"`
+	MidsuitRecordID string                   `json:"midsuit_record_id"`
+	CompletedAt     *time.Time               `json:"completed_at"`
+	UpdatedAt       time.Time                `json:"updated_at"`
+}
+
+type MidsuitLookupResponse struct {
+	Field     string `json:"field"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MidsuitID string `json:"midsuit_id"`
+	Resolved  bool   `json:"resolved"`
+}
+
+type MidsuitSyncPreviewResponse struct {
+	DocumentSendingID uuid.UUID               `json:"document_sending_id"`
+	Ready             bool                    `json:"ready"`
+	Warnings          []string                `json:"warnings"`
+	Lookups           []MidsuitLookupResponse `json:"lookups"`
+	Job               *MidsuitSyncJobResponse `json:"job"`
+
+	Employee        request.SyncEmployeeMidsuitRequest                 `json:"employee"`
+	EmployeeJob     request.SyncEmployeeJobMidsuitRequest              `json:"employee_job"`
+	WorkExperiences []request.SyncEmployeeWorkExperienceMidsuitRequest `json:"work_experiences"`
+	Educations      []request.SyncEmployeeEducationMidsuitRequest      `json:"educations"`
+	Allowances      []request.SyncEmployeeAllowanceMidsuitRequest      `json:"allowances"`
+}
+
+type MidsuitFieldDriftResponse struct {
+	Field   string `json:"field"`
+	Local   string `json:"local"`
+	Midsuit string `json:"midsuit"`
+}
+
+type MidsuitReconciliationDriftResponse struct {
+	UserProfileID uuid.UUID                   `json:"user_profile_id"`
+	Name          string                      `json:"name"`
+	MidsuitID     string                      `json:"midsuit_id"`
+	Kind          string                      `json:"kind"`
+	Message       string                      `json:"message"`
+	Fields        []MidsuitFieldDriftResponse `json:"fields"`
+}
+
+type MidsuitReconciliationReportResponse struct {
+	CheckedAt time.Time                            `json:"checked_at"`
+	Checked   int                                  `json:"checked"`
+	InSync    int                                  `json:"in_sync"`
+	Drifts    []MidsuitReconciliationDriftResponse `json:"drifts"`
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+// PersonalDataApplicationResponse is an application in the personal data export
+type PersonalDataApplicationResponse struct {
+	ID             uuid.UUID                     `json:"id"`
+	JobPostingID   uuid.UUID                     `json:"job_posting_id"`
+	JobPostingName string                        `json:"job_posting_name"`
+	AppliedDate    time.Time                     `json:"applied_date"`
+	Status         entity.ApplicantStatus        `json:"status"`
+	ProcessStatus  entity.ApplicantProcessStatus `json:"process_status"`
+	HiredStatus    entity.HiredStatusEnum        `json:"hired_status"`
+}
+
+// PersonalDataAnswerResponse is an answer to a question in the personal data export
+type PersonalDataAnswerResponse struct {
+	ID             uuid.UUID `json:"id"`
+	JobPostingID   uuid.UUID `json:"job_posting_id"`
+	JobPostingName string    `json:"job_posting_name"`
+	Question       string    `json:"question"`
+	Answer         string    `json:"answer"`
+	AnswerFile     string    `json:"answer_file"`
+}
+
+// PersonalDataFileResponse lists a stored file in the personal data export, File is its path
+// in the archive and is empty when the file could not be found
+type PersonalDataFileResponse struct {
+	Category string `json:"category"`
+	Label    string `json:"label"`
+	File     string `json:"file"`
+}
+
+// PersonalDataReferralResponse is a referral of the candidate by an employee in the personal data
+// export, the referrer is not part of it
+type PersonalDataReferralResponse struct {
+	ID             uuid.UUID             `json:"id"`
+	JobPostingID   uuid.UUID             `json:"job_posting_id"`
+	JobPostingName string                `json:"job_posting_name"`
+	CandidateName  string                `json:"candidate_name"`
+	CandidateEmail string                `json:"candidate_email"`
+	CandidatePhone string                `json:"candidate_phone"`
+	Note           string                `json:"note"`
+	Status         entity.ReferralStatus `json:"status"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
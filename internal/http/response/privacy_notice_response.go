@@ -0,0 +1,26 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PrivacyNoticeResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Version     string    `json:"version"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	PublishedAt time.Time `json:"published_at"`
+	PublishedBy uuid.UUID `json:"published_by"`
+}
+
+type PrivacyConsentResponse struct {
+	ID              uuid.UUID `json:"id"`
+	UserProfileID   uuid.UUID `json:"user_profile_id"`
+	PrivacyNoticeID uuid.UUID `json:"privacy_notice_id"`
+	NoticeVersion   string    `json:"notice_version"`
+	ConsentedAt     time.Time `json:"consented_at"`
+	IPAddress       string    `json:"ip_address"`
+	UserAgent       string    `json:"user_agent"`
+}
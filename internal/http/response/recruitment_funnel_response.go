@@ -0,0 +1,36 @@
+package response
+
+import "github.com/google/uuid"
+
+type RecruitmentFunnelResponse struct {
+	ProjectRecruitmentHeaderID   uuid.UUID                         `json:"project_recruitment_header_id"`
+	ProjectRecruitmentHeaderName string                            `json:"project_recruitment_header_name"`
+	JobPostingIDs                []uuid.UUID                       `json:"job_posting_ids"`
+	TotalApplicants              int                               `json:"total_applicants"`
+	TotalHired                   int                               `json:"total_hired"`
+	HiredRate                    float64                           `json:"hired_rate"`
+	Stages                       []RecruitmentFunnelStageResponse  `json:"stages"`
+	RejectionReasons             []RecruitmentFunnelReasonResponse `json:"rejection_reasons"`
+}
+
+// RecruitmentFunnelStageResponse is one project recruitment line. Rates are percentages,
+// conversion_rate of the applicants who reached the stage and cumulative_rate of all applicants.
+type RecruitmentFunnelStageResponse struct {
+	Order          int      `json:"order"`
+	Name           string   `json:"name"`
+	FormType       string   `json:"form_type"`
+	Reached        int      `json:"reached"`
+	Passed         int      `json:"passed"`
+	Rejected       int      `json:"rejected"`
+	InProgress     int      `json:"in_progress"`
+	ConversionRate float64  `json:"conversion_rate"`
+	CumulativeRate float64  `json:"cumulative_rate"`
+	AvgDays        *float64 `json:"avg_days"`
+}
+
+type RecruitmentFunnelReasonResponse struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+	Stage  string `json:"stage"`
+	Total  int    `json:"total"`
+}
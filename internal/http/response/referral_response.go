@@ -0,0 +1,56 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type ReferralLinkResponse struct {
+	ID             uuid.UUID `json:"id"`
+	EmployeeID     uuid.UUID `json:"employee_id"`
+	JobPostingID   uuid.UUID `json:"job_posting_id"`
+	JobPostingName string    `json:"job_posting_name"`
+	Code           string    `json:"code"`
+	URL            string    `json:"url"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ReferralResponse is a referral as its referrer sees it: how far the candidate got, without
+// the assessments of the selection
+type ReferralResponse struct {
+	ID             uuid.UUID             `json:"id"`
+	JobPostingID   uuid.UUID             `json:"job_posting_id"`
+	JobPostingName string                `json:"job_posting_name"`
+	CandidateName  string                `json:"candidate_name"`
+	CandidateEmail string                `json:"candidate_email"`
+	Status         entity.ReferralStatus `json:"status"`
+	Progress       string                `json:"progress"`
+	StageName      string                `json:"stage_name,omitempty"`
+	AppliedDate    *time.Time            `json:"applied_date"`
+	JoinedDate     *time.Time            `json:"joined_date"`
+	BonusStatus    string                `json:"bonus_status,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+type ReferralBonusReportResponse struct {
+	ProbationDays int                         `json:"probation_days"`
+	Total         int                         `json:"total"`
+	Eligible      int                         `json:"eligible"`
+	Items         []ReferralBonusItemResponse `json:"items"`
+}
+
+type ReferralBonusItemResponse struct {
+	ReferralID         uuid.UUID  `json:"referral_id"`
+	ReferrerEmployeeID uuid.UUID  `json:"referrer_employee_id"`
+	ReferrerName       string     `json:"referrer_name"`
+	JobPostingID       uuid.UUID  `json:"job_posting_id"`
+	JobPostingName     string     `json:"job_posting_name"`
+	ApplicantID        uuid.UUID  `json:"applicant_id"`
+	CandidateName      string     `json:"candidate_name"`
+	CandidateEmail     string     `json:"candidate_email"`
+	JoinedDate         *time.Time `json:"joined_date"`
+	ProbationEndsAt    *time.Time `json:"probation_ends_at"`
+	Status             string     `json:"status"`
+}
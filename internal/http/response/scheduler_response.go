@@ -0,0 +1,28 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SchedulerJobResponse struct {
+	Name      string                   `json:"name"`
+	Schedule  string                   `json:"schedule"`
+	Running   bool                     `json:"running"`
+	NextRunAt *time.Time               `json:"next_run_at"`
+	LastRun   *SchedulerJobRunResponse `json:"last_run"`
+}
+
+type SchedulerJobRunResponse struct {
+	ID         uuid.UUID                     `json:"id"`
+	JobName    string                        `json:"job_name"`
+	Trigger    entity.SchedulerJobRunTrigger `json:"trigger"`
+	Owner      string                        `json:"owner"`
+	Status     entity.SchedulerJobRunStatus  `json:"status"`
+	StartedAt  time.Time                     `json:"started_at"`
+	FinishedAt *time.Time                    `json:"finished_at"`
+	Result     string                        `json:"result"`
+	Error      string                        `json:"error"`
+}
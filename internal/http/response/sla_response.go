@@ -0,0 +1,37 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SlaOverdueQueueResponse struct {
+	EmployeeID uuid.UUID                `json:"employee_id"`
+	Total      int                      `json:"total"`
+	Escalated  int                      `json:"escalated"`
+	Items      []SlaOverdueItemResponse `json:"items"`
+}
+
+// SlaOverdueItemResponse is an applicant sitting in a stage past its SLA (STAGE), or an
+// assessor with results still to fill past the stage SLA (RESULT)
+type SlaOverdueItemResponse struct {
+	Type                     entity.SlaAlertType `json:"type"`
+	ProjectRecruitmentLineID uuid.UUID           `json:"project_recruitment_line_id"`
+	StageName                string              `json:"stage_name"`
+	JobPostingID             uuid.UUID           `json:"job_posting_id"`
+	JobPostingName           string              `json:"job_posting_name"`
+	ApplicantID              *uuid.UUID          `json:"applicant_id,omitempty"`
+	ApplicantName            string              `json:"applicant_name,omitempty"`
+	ScheduleType             string              `json:"schedule_type,omitempty"`
+	ScheduleID               *uuid.UUID          `json:"schedule_id,omitempty"`
+	ScheduleName             string              `json:"schedule_name,omitempty"`
+	AssessorEmployeeID       *uuid.UUID          `json:"assessor_employee_id,omitempty"`
+	PendingResults           int                 `json:"pending_results,omitempty"`
+	PicEmployeeIDs           []uuid.UUID         `json:"pic_employee_ids"`
+	StartedAt                time.Time           `json:"started_at"`
+	DueAt                    time.Time           `json:"due_at"`
+	DaysOverdue              int                 `json:"days_overdue"`
+	Escalated                bool                `json:"escalated"`
+}
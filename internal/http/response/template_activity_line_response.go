@@ -8,15 +8,17 @@ import (
 )
 
 type TemplateActivityLineResponse struct {
-	ID                 uuid.UUID                         `json:"id"`
-	TemplateActivityID uuid.UUID                         `json:"template_activity_id"`
-	Name               string                            `json:"name"`
-	Description        string                            `json:"description"`
-	Status             entity.TemplateActivityLineStatus `json:"status"`
-	TemplateQuestionID uuid.UUID                         `json:"question_template_id"`
-	ColorHexCode       string                            `json:"color_hex_code"`
-	CreatedAt          time.Time                         `json:"created_at"`
-	UpdatedAt          time.Time                         `json:"updated_at"`
+	ID                  uuid.UUID                         `json:"id"`
+	TemplateActivityID  uuid.UUID                         `json:"template_activity_id"`
+	Name                string                            `json:"name"`
+	Description         string                            `json:"description"`
+	Status              entity.TemplateActivityLineStatus `json:"status"`
+	TemplateQuestionID  uuid.UUID                         `json:"question_template_id"`
+	ColorHexCode        string                            `json:"color_hex_code"`
+	MaxDaysInStage      int                               `json:"max_days_in_stage"`
+	MaxDaysToFillResult int                               `json:"max_days_to_fill_result"`
+	CreatedAt           time.Time                         `json:"created_at"`
+	UpdatedAt           time.Time                         `json:"updated_at"`
 
 	TemplateActivity *TemplateActivityResponse `json:"template_activity"`
 	TemplateQuestion *TemplateQuestionResponse `json:"template_question"`
@@ -0,0 +1,18 @@
+package response
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+)
+
+type TokenRevocationResponse struct {
+	ID        uuid.UUID                  `json:"id"`
+	Kind      entity.TokenRevocationKind `json:"kind"`
+	TokenID   string                     `json:"token_id"`
+	UserID    *uuid.UUID                 `json:"user_id"`
+	RevokedAt time.Time                  `json:"revoked_at"`
+	ExpiresAt time.Time                  `json:"expires_at"`
+	Reason    string                     `json:"reason"`
+}
@@ -23,6 +23,8 @@ type UserProfileResponse struct {
 	CurrentSalary   int                       `json:"current_salary"`
 	Religion        string                    `json:"religion"`
 	Ktp             *string                   `json:"ktp"`
+	KtpNumber       string                    `json:"ktp_number"`
+	Email           string                    `json:"email"`
 	Avatar          *string                   `json:"avatar"`
 	CurriculumVitae *string                   `json:"curriculum_vitae"`
 	Status          entity.UserStatus         `json:"status"`
@@ -32,5 +34,6 @@ type UserProfileResponse struct {
 	Educations      *[]EducationResponse      `json:"educations"`
 	Skills          *[]SkillResponse          `json:"skills"`
 	MidsuitID       *string                   `json:"midsuit_id"`
+	AnonymisedAt    *time.Time                `json:"anonymised_at"`
 	User            *map[string]interface{}   `json:"user"`
 }
@@ -0,0 +1,189 @@
+package route
+
+import "github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+
+const (
+	PERMISSION_READ_RECRUITMENT   = "read-recruitment"
+	PERMISSION_CREATE_RECRUITMENT = "create-recruitment"
+	PERMISSION_UPDATE_RECRUITMENT = "update-recruitment"
+	PERMISSION_DELETE_RECRUITMENT = "delete-recruitment"
+	PERMISSION_REVOKE_TOKEN       = "revoke-token"
+	// staff without it get KTP numbers, salaries, religion and compensation masked
+	PERMISSION_READ_SENSITIVE_DATA = "read-sensitive-data"
+)
+
+var (
+	canRead   = []string{PERMISSION_READ_RECRUITMENT}
+	canCreate = []string{PERMISSION_CREATE_RECRUITMENT}
+	canUpdate = []string{PERMISSION_UPDATE_RECRUITMENT}
+	canDelete = []string{PERMISSION_DELETE_RECRUITMENT}
+	canRevoke = []string{PERMISSION_REVOKE_TOKEN}
+)
+
+// routePermissions lists the back office routes per route group. Candidate facing routes
+// (applying, answering, own schedules and profile) are left out and only need a valid token,
+// record ownership is checked in their handlers.
+var routePermissions = middleware.RoutePermissions{
+	// mp requests
+	"GET /api/mp-requests":             canRead,
+	"GET /api/mp-requests/job-posting": canRead,
+	// template questions
+	"POST /api/template-questions":       canCreate,
+	"PUT /api/template-questions/update": canUpdate,
+	"DELETE /api/template-questions/:id": canDelete,
+	// questions
+	"POST /api/questions": canCreate,
+	// document setup
+	"POST /api/document-setup":       canCreate,
+	"PUT /api/document-setup/update": canUpdate,
+	"DELETE /api/document-setup/:id": canDelete,
+	// document verifications
+	"POST /api/document-verifications":       canCreate,
+	"PUT /api/document-verifications/update": canUpdate,
+	"DELETE /api/document-verifications/:id": canDelete,
+	// template activities
+	"POST /api/template-activities":       canCreate,
+	"PUT /api/template-activities/update": canUpdate,
+	"DELETE /api/template-activities/:id": canDelete,
+	// template activity lines
+	"POST /api/template-activity-lines": canCreate,
+	// project recruitment headers
+	"GET /api/project-recruitment-headers":                 canRead,
+	"GET /api/project-recruitment-headers/document-number": canCreate,
+	"POST /api/project-recruitment-headers":                canCreate,
+	"PUT /api/project-recruitment-headers/update":          canUpdate,
+	"DELETE /api/project-recruitment-headers/:id":          canDelete,
+	// project recruitment lines
+	"POST /api/project-recruitment-lines": canCreate,
+	// job postings
+	"GET /api/job-postings/document-number": canCreate,
+	"POST /api/job-postings":                canCreate,
+	"PUT /api/job-postings/update":          canUpdate,
+	"DELETE /api/job-postings/:id":          canDelete,
+	// mail templates
+	"POST /api/mail-templates":       canCreate,
+	"PUT /api/mail-templates/update": canUpdate,
+	"DELETE /api/mail-templates/:id": canDelete,
+	// user profiles
+	"GET /api/user-profiles":                         canRead,
+	"PUT /api/user-profiles/update/status":           canUpdate,
+	"DELETE /api/user-profiles/:id":                  canDelete,
+	"GET /api/user-profiles/duplicates":              canRead,
+	"POST /api/user-profiles/duplicates/:id/merge":   canUpdate,
+	"POST /api/user-profiles/duplicates/:id/dismiss": canUpdate,
+	// applicants
+	"GET /api/applicants/job-posting/:job_posting_id/export":        canRead,
+	"GET /api/applicants/job-posting/:job_posting_id":               canRead,
+	"POST /api/applicants/job-posting/:job_posting_id/match-scores": canUpdate,
+	"PUT /api/applicants/:id/source":                                canUpdate,
+	// test types
+	"POST /api/test-types":       canCreate,
+	"PUT /api/test-types/update": canUpdate,
+	"DELETE /api/test-types/:id": canDelete,
+	// test schedule headers
+	"GET /api/test-schedule-headers":                        canRead,
+	"GET /api/test-schedule-headers/export-answer":          canRead,
+	"GET /api/test-schedule-headers/export-result-template": canRead,
+	"GET /api/test-schedule-headers/document-number":        canCreate,
+	"POST /api/test-schedule-headers":                       canCreate,
+	"POST /api/test-schedule-headers/read-result-template":  canUpdate,
+	"PUT /api/test-schedule-headers/update":                 canUpdate,
+	"PUT /api/test-schedule-headers/update-status":          canUpdate,
+	"DELETE /api/test-schedule-headers/:id":                 canDelete,
+	// test applicants
+	"GET /api/test-applicants/test-schedule-header/:test_schedule_header_id": canRead,
+	"POST /api/test-applicants":              canCreate,
+	"PUT /api/test-applicants/update-status": canUpdate,
+	// administrative selections
+	"GET /api/administrative-selections":                 canRead,
+	"GET /api/administrative-selections/document-number": canCreate,
+	"GET /api/administrative-selections/verify/:id":      canUpdate,
+	"POST /api/administrative-selections":                canCreate,
+	"PUT /api/administrative-selections/update":          canUpdate,
+	"DELETE /api/administrative-selections/:id":          canDelete,
+	// administrative results
+	"GET /api/administrative-results/administrative-selection/:administrative_selection_id": canRead,
+	"GET /api/administrative-results/:id/update-status":                                     canUpdate,
+	"POST /api/administrative-results":                                                      canUpdate,
+	// interviews
+	"GET /api/interviews":                        canRead,
+	"GET /api/interviews/export-answers":         canRead,
+	"GET /api/interviews/export-result-template": canRead,
+	"GET /api/interviews/document-number":        canCreate,
+	"POST /api/interviews":                       canCreate,
+	"POST /api/interviews/read-result-template":  canUpdate,
+	"PUT /api/interviews/update":                 canUpdate,
+	"PUT /api/interviews/update-status":          canUpdate,
+	"DELETE /api/interviews/:id":                 canDelete,
+	// interview applicants
+	"GET /api/interview-applicants/interview/:interview_id": canRead,
+	"POST /api/interview-applicants":                        canCreate,
+	"PUT /api/interview-applicants/update-status":           canUpdate,
+	"PUT /api/interview-applicants/update-final-result":     canUpdate,
+	// fgd schedules
+	"GET /api/fgd-schedules":                        canRead,
+	"GET /api/fgd-schedules/export-answers":         canRead,
+	"GET /api/fgd-schedules/export-result-template": canRead,
+	"GET /api/fgd-schedules/document-number":        canCreate,
+	"POST /api/fgd-schedules":                       canCreate,
+	"POST /api/fgd-schedules/read-result-template":  canUpdate,
+	"PUT /api/fgd-schedules/update":                 canUpdate,
+	"PUT /api/fgd-schedules/update-status":          canUpdate,
+	"DELETE /api/fgd-schedules/:id":                 canDelete,
+	// fgd applicants
+	"GET /api/fgd-applicants/fgd-schedule/:fgd_id": canRead,
+	"POST /api/fgd-applicants":                     canCreate,
+	"PUT /api/fgd-applicants/update-status":        canUpdate,
+	"PUT /api/fgd-applicants/update-final-result":  canUpdate,
+	// document sending, GET /api/document-sending/:id is left out as the handler lets staff and
+	// the candidate the document was sent to read it
+	"GET /api/document-sending":                                   canRead,
+	"POST /api/document-sending/generate-pdf":                     canCreate,
+	"POST /api/document-sending/generate-pdf-kop":                 canCreate,
+	"GET /api/document-sending/test-generate-pdf":                 canCreate,
+	"GET /api/document-sending/test-send-email":                   canCreate,
+	"GET /api/document-sending/document-number":                   canCreate,
+	"GET /api/document-sending/document-setup/:document_setup_id": canRead,
+	"GET /api/document-sending/:id/midsuit-sync":                  canRead,
+	"GET /api/document-sending/:id/midsuit-sync/preview":          canRead,
+	"POST /api/document-sending/:id/midsuit-sync/retry":           canUpdate,
+	"POST /api/document-sending":                                  canCreate,
+	"PUT /api/document-sending/update":                            canUpdate,
+	"DELETE /api/document-sending/:id":                            canDelete,
+	// document agreement
+	"GET /api/document-agreement":               canRead,
+	"PUT /api/document-agreement/update-status": canUpdate,
+	// document verification headers
+	"GET /api/document-verification-headers":          canRead,
+	"GET /api/document-verification-headers/bpjs-tk":  canRead,
+	"POST /api/document-verification-headers/bpjs-tk": canUpdate,
+	"POST /api/document-verification-headers":         canCreate,
+	"PUT /api/document-verification-headers/update":   canUpdate,
+	"DELETE /api/document-verification-headers/:id":   canDelete,
+	// dashboard
+	"GET /api/dashboard":               canRead,
+	"GET /api/dashboard/funnel":        canRead,
+	"GET /api/dashboard/funnel/export": canRead,
+	"GET /api/dashboard/sources":       canRead,
+	// stage SLAs
+	"GET /api/sla/overdue": canRead,
+	// employee referrals, making and following referrals is open to every employee
+	"GET /api/referrals/bonus-eligibility": canRead,
+	// midsuit sync jobs
+	"GET /api/midsuit-sync-jobs": canRead,
+	// auth
+	"POST /api/auth/revoke": canRevoke,
+	// scheduler
+	"GET /api/scheduler/jobs":            canRead,
+	"POST /api/scheduler/jobs/:name/run": canUpdate,
+	"GET /api/scheduler/runs":            canRead,
+	// candidates
+	"GET /api/candidates/search": canRead,
+	// privacy notices
+	"GET /api/privacy-notices":  canRead,
+	"POST /api/privacy-notices": canCreate,
+	// erasure requests
+	"GET /api/erasure-requests":              canRead,
+	"POST /api/erasure-requests/:id/approve": canDelete,
+	"POST /api/erasure-requests/:id/reject":  canUpdate,
+}
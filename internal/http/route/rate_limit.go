@@ -0,0 +1,55 @@
+package route
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/middleware"
+)
+
+const (
+	RATE_LIMIT_POLICY_PUBLIC  = "public"
+	RATE_LIMIT_POLICY_APPLY   = "apply"
+	RATE_LIMIT_POLICY_UPLOAD  = "upload"
+	RATE_LIMIT_POLICY_PROFILE = "profile"
+)
+
+// rateLimitPolicies are the default limits, each one can be overridden in rate_limit.policies
+var rateLimitPolicies = map[string]middleware.RateLimitPolicy{
+	middleware.DEFAULT_RATE_LIMIT_POLICY: {
+		IP:   middleware.RateLimit{Requests: 600, Window: time.Minute},
+		User: middleware.RateLimit{Requests: 300, Window: time.Minute},
+	},
+	RATE_LIMIT_POLICY_PUBLIC: {
+		IP: middleware.RateLimit{Requests: 60, Window: time.Minute},
+	},
+	RATE_LIMIT_POLICY_APPLY: {
+		IP:   middleware.RateLimit{Requests: 30, Window: time.Hour},
+		User: middleware.RateLimit{Requests: 10, Window: time.Hour},
+	},
+	RATE_LIMIT_POLICY_UPLOAD: {
+		IP:   middleware.RateLimit{Requests: 60, Window: 10 * time.Minute},
+		User: middleware.RateLimit{Requests: 20, Window: 10 * time.Minute},
+	},
+	// POST /api/user-profiles creates the profile but also saves every later edit, so the limit
+	// leaves room for a candidate working on their profile and only stops scripted sign ups
+	RATE_LIMIT_POLICY_PROFILE: {
+		IP:   middleware.RateLimit{Requests: 120, Window: time.Hour},
+		User: middleware.RateLimit{Requests: 60, Window: time.Hour},
+	},
+}
+
+// routeRateLimits lists the routes that need a stricter policy than the default one
+var routeRateLimits = middleware.RouteRateLimits{
+	"GET /api/no-auth/job-postings/show-only":  RATE_LIMIT_POLICY_PUBLIC,
+	"GET /api/no-auth/job-postings/slug/:slug": RATE_LIMIT_POLICY_PUBLIC,
+	"GET /careers/feed.rss":                    RATE_LIMIT_POLICY_PUBLIC,
+	"GET /careers/feed.atom":                   RATE_LIMIT_POLICY_PUBLIC,
+	"GET /careers/jobs/:slug/schema.json":      RATE_LIMIT_POLICY_PUBLIC,
+	"GET /sitemap.xml":                         RATE_LIMIT_POLICY_PUBLIC,
+	"GET /api/applicants/apply":                RATE_LIMIT_POLICY_APPLY,
+	"POST /api/uploads/file":                   RATE_LIMIT_POLICY_UPLOAD,
+	"PUT /api/user-profiles/update-avatar":     RATE_LIMIT_POLICY_UPLOAD,
+	"POST /api/user-profiles/cv-drafts":        RATE_LIMIT_POLICY_UPLOAD,
+	"GET /api/user-profiles/export":            RATE_LIMIT_POLICY_UPLOAD,
+	"POST /api/user-profiles":                  RATE_LIMIT_POLICY_PROFILE,
+}
@@ -15,6 +15,10 @@ type RouteConfig struct {
 	Log                               *logrus.Logger
 	Viper                             *viper.Viper
 	AuthMiddleware                    gin.HandlerFunc
+	AuthorizationMiddleware           gin.HandlerFunc
+	RateLimitMiddleware               gin.HandlerFunc
+	UserRateLimitMiddleware           gin.HandlerFunc
+	SensitiveDataMiddleware           gin.HandlerFunc
 	UserProfileVerifiedMiddleware     gin.HandlerFunc
 	MPRequestHandler                  handler.IMPRequestHandler
 	RecruitmentTypeHandler            handler.IRecruitmentTypeHandler
@@ -51,7 +55,22 @@ type RouteConfig struct {
 	DocumentVerificationHeaderHandler handler.IDocumentVerificationHeaderHandler
 	DocumentVerificationLineHandler   handler.IDocumentVerificationLineHandler
 	DashboardHandler                  handler.IDashboardHandler
+	RecruitmentFunnelHandler          handler.IRecruitmentFunnelHandler
+	SlaHandler                        handler.ISlaHandler
 	UploadHandler                     handler.IUploadHandler
+	MidsuitSyncHandler                handler.IMidsuitSyncHandler
+	TokenRevocationHandler            handler.ITokenRevocationHandler
+	CareerFeedHandler                 handler.ICareerFeedHandler
+	SchedulerHandler                  handler.ISchedulerHandler
+	CandidateSearchHandler            handler.ICandidateSearchHandler
+	ApplicantMatchScoreHandler        handler.IApplicantMatchScoreHandler
+	ApplicantSourceHandler            handler.IApplicantSourceHandler
+	ReferralHandler                   handler.IReferralHandler
+	CurriculumVitaeDraftHandler       handler.ICurriculumVitaeDraftHandler
+	DuplicateCandidateHandler         handler.IDuplicateCandidateHandler
+	PrivacyNoticeHandler              handler.IPrivacyNoticeHandler
+	PersonalDataHandler               handler.IPersonalDataHandler
+	StorageHandler                    handler.IStorageHandler
 }
 
 func (c *RouteConfig) SetupRoutes() {
@@ -62,14 +81,20 @@ func (c *RouteConfig) SetupRoutes() {
 	})
 
 	c.App.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
-	c.App.GET("/api/no-auth/job-postings/show-only", c.JobPostingHandler.FindAllPaginatedWithoutUserIDShowOnly)
+	c.App.GET("/api/no-auth/job-postings/show-only", c.RateLimitMiddleware, c.JobPostingHandler.FindAllPaginatedWithoutUserIDShowOnly)
+	c.App.GET("/api/no-auth/job-postings/slug/:slug", c.RateLimitMiddleware, c.CareerFeedHandler.FindBySlug)
+	c.App.GET("/careers/feed.rss", c.RateLimitMiddleware, c.CareerFeedHandler.RssFeed)
+	c.App.GET("/careers/feed.atom", c.RateLimitMiddleware, c.CareerFeedHandler.AtomFeed)
+	c.App.GET("/careers/jobs/:slug/schema.json", c.RateLimitMiddleware, c.CareerFeedHandler.JsonLD)
+	c.App.GET("/sitemap.xml", c.RateLimitMiddleware, c.CareerFeedHandler.Sitemap)
+	c.App.GET("/storage/*path", c.RateLimitMiddleware, c.StorageHandler.Download)
 	c.SetupAPIRoutes()
 }
 
 func (c *RouteConfig) SetupAPIRoutes() {
 	apiRoute := c.App.Group("/api")
 	{
-		apiRoute.Use(c.AuthMiddleware)
+		apiRoute.Use(c.RateLimitMiddleware, c.AuthMiddleware, c.UserRateLimitMiddleware, c.AuthorizationMiddleware, c.SensitiveDataMiddleware)
 		{
 			// mp requests
 			mpRequestRoute := apiRoute.Group("/mp-requests")
@@ -203,6 +228,18 @@ func (c *RouteConfig) SetupAPIRoutes() {
 			{
 				userProfileRoute.GET("", c.UserProfileHandler.FindAllPaginated)
 				userProfileRoute.GET("/user", c.UserProfileHandler.FindByUserID)
+				userProfileRoute.GET("/cv-drafts/latest", c.CurriculumVitaeDraftHandler.FindLatest)
+				userProfileRoute.POST("/cv-drafts", c.CurriculumVitaeDraftHandler.CreateDraft)
+				userProfileRoute.POST("/cv-drafts/:id/accept", c.CurriculumVitaeDraftHandler.AcceptDraft)
+				userProfileRoute.POST("/cv-drafts/:id/reject", c.CurriculumVitaeDraftHandler.RejectDraft)
+				userProfileRoute.GET("/duplicates", c.DuplicateCandidateHandler.FindAllPaginated)
+				userProfileRoute.POST("/duplicates/:id/merge", c.DuplicateCandidateHandler.Merge)
+				userProfileRoute.POST("/duplicates/:id/dismiss", c.DuplicateCandidateHandler.Dismiss)
+				userProfileRoute.GET("/privacy-consents", c.PersonalDataHandler.FindConsents)
+				userProfileRoute.POST("/privacy-consents", c.PersonalDataHandler.Consent)
+				userProfileRoute.GET("/export", c.PersonalDataHandler.Export)
+				userProfileRoute.GET("/erasure-requests/latest", c.PersonalDataHandler.FindLatestErasureRequest)
+				userProfileRoute.POST("/erasure-requests", c.PersonalDataHandler.RequestErasure)
 				userProfileRoute.GET("/:id", c.UserProfileHandler.FindByID)
 				userProfileRoute.POST("", c.UserProfileHandler.FillUserProfile)
 				userProfileRoute.PUT("/update/status", c.UserProfileHandler.UpdateStatusUserProfile)
@@ -219,6 +256,8 @@ func (c *RouteConfig) SetupAPIRoutes() {
 					applicantRoute.GET("/me/:job_posting_id", c.ApplicantHandler.FindApplicantByJobPostingIDAndUserID)
 					applicantRoute.GET("/job-posting/:job_posting_id/export", c.ApplicantHandler.ExportApplicantsByJobPosting)
 					applicantRoute.GET("/job-posting/:job_posting_id", c.ApplicantHandler.GetApplicantsByJobPostingID)
+					applicantRoute.POST("/job-posting/:job_posting_id/match-scores", c.ApplicantMatchScoreHandler.RecalculateByJobPostingID)
+					applicantRoute.PUT("/:id/source", c.ApplicantSourceHandler.SetApplicantSource)
 					applicantRoute.GET("/:id", c.ApplicantHandler.FindByID)
 				}
 			}
@@ -364,6 +403,9 @@ func (c *RouteConfig) SetupAPIRoutes() {
 				documentSendingRoute.GET("/document-number", c.DocumentSendingHandler.GenerateDocumentNumber)
 				documentSendingRoute.GET("/document-setup/:document_setup_id", c.DocumentSendingHandler.FindAllByDocumentSetupID)
 				documentSendingRoute.GET("/:id", c.DocumentSendingHandler.FindByID)
+				documentSendingRoute.GET("/:id/midsuit-sync", c.MidsuitSyncHandler.FindByDocumentSendingID)
+				documentSendingRoute.GET("/:id/midsuit-sync/preview", c.MidsuitSyncHandler.PreviewByDocumentSendingID)
+				documentSendingRoute.POST("/:id/midsuit-sync/retry", c.MidsuitSyncHandler.RerunByDocumentSendingID)
 				documentSendingRoute.POST("", c.DocumentSendingHandler.CreateDocumentSending)
 				documentSendingRoute.PUT("/update", c.DocumentSendingHandler.UpdateDocumentSending)
 				documentSendingRoute.DELETE("/:id", c.DocumentSendingHandler.DeleteDocumentSending)
@@ -404,18 +446,82 @@ func (c *RouteConfig) SetupAPIRoutes() {
 			dashboardRoute := apiRoute.Group("/dashboard")
 			{
 				dashboardRoute.GET("", c.DashboardHandler.GetDashboard)
+				dashboardRoute.GET("/funnel", c.RecruitmentFunnelHandler.GetFunnel)
+				dashboardRoute.GET("/funnel/export", c.RecruitmentFunnelHandler.ExportFunnel)
+				dashboardRoute.GET("/sources", c.ApplicantSourceHandler.GetSourceReport)
+			}
+			// stage SLAs
+			slaRoute := apiRoute.Group("/sla")
+			{
+				slaRoute.GET("/overdue", c.SlaHandler.FindOverdueQueue)
+			}
+			// employee referrals
+			referralRoute := apiRoute.Group("/referrals")
+			{
+				referralRoute.GET("", c.ReferralHandler.FindReferrals)
+				referralRoute.POST("", c.ReferralHandler.CreateReferral)
+				referralRoute.GET("/links", c.ReferralHandler.FindReferralLinks)
+				referralRoute.POST("/links", c.ReferralHandler.CreateReferralLink)
+				referralRoute.GET("/bonus-eligibility", c.ReferralHandler.GetBonusReport)
 			}
 			// uploads
 			uploadRoute := apiRoute.Group("/uploads")
 			{
 				uploadRoute.POST("file", c.UploadHandler.UploadFile)
 			}
+			// midsuit sync jobs
+			midsuitSyncJobRoute := apiRoute.Group("/midsuit-sync-jobs")
+			{
+				midsuitSyncJobRoute.GET("", c.MidsuitSyncHandler.FindAllPaginated)
+			}
+			// auth
+			authRoute := apiRoute.Group("/auth")
+			{
+				authRoute.POST("/logout", c.TokenRevocationHandler.Logout)
+				authRoute.POST("/revoke", c.TokenRevocationHandler.RevokeToken)
+			}
+			// scheduler
+			schedulerRoute := apiRoute.Group("/scheduler")
+			{
+				schedulerRoute.GET("/jobs", c.SchedulerHandler.FindAllJobs)
+				schedulerRoute.POST("/jobs/:name/run", c.SchedulerHandler.RunJob)
+				schedulerRoute.GET("/runs", c.SchedulerHandler.FindAllJobRunsPaginated)
+			}
+			// candidates
+			candidateRoute := apiRoute.Group("/candidates")
+			{
+				candidateRoute.GET("/search", c.CandidateSearchHandler.Search)
+			}
+			// privacy notices
+			privacyNoticeRoute := apiRoute.Group("/privacy-notices")
+			{
+				privacyNoticeRoute.GET("", c.PrivacyNoticeHandler.FindAll)
+				privacyNoticeRoute.GET("/current", c.PrivacyNoticeHandler.FindCurrent)
+				privacyNoticeRoute.POST("", c.PrivacyNoticeHandler.PublishPrivacyNotice)
+			}
+			// erasure requests
+			erasureRequestRoute := apiRoute.Group("/erasure-requests")
+			{
+				erasureRequestRoute.GET("", c.PersonalDataHandler.FindErasureRequestsPaginated)
+				erasureRequestRoute.POST("/:id/approve", c.PersonalDataHandler.ApproveErasureRequest)
+				erasureRequestRoute.POST("/:id/reject", c.PersonalDataHandler.RejectErasureRequest)
+			}
+			// files
+			fileRoute := apiRoute.Group("/files")
+			{
+				fileRoute.GET("/signed-url", c.StorageHandler.SignURL)
+			}
 		}
 	}
 }
 
 func NewRouteConfig(app *gin.Engine, viper *viper.Viper, log *logrus.Logger) *RouteConfig {
-	authMiddleware := middleware.NewAuth(viper)
+	authMiddleware := middleware.NewAuth(log, viper)
+	authorizationMiddleware := middleware.NewAuthorization(log, viper, routePermissions)
+	rateLimitStore := middleware.RateLimitStoreFactory(log, viper)
+	rateLimitMiddleware := middleware.NewRateLimit(log, viper, rateLimitStore, rateLimitPolicies, routeRateLimits)
+	userRateLimitMiddleware := middleware.NewUserRateLimit(log, viper, rateLimitStore, rateLimitPolicies, routeRateLimits)
+	sensitiveDataMiddleware := middleware.NewSensitiveDataMasking(log, viper, PERMISSION_READ_SENSITIVE_DATA)
 	userProfileVerifiedMiddleware := middleware.UserProfileVerifiedMiddleware(log, viper)
 	mpRequestHandler := handler.MPRequestHandlerFactory(log, viper)
 	recruitmentTypeHandler := handler.RecruitmentTypeHandlerFactory(log, viper)
@@ -451,12 +557,31 @@ func NewRouteConfig(app *gin.Engine, viper *viper.Viper, log *logrus.Logger) *Ro
 	documentVerificationHeaderHandler := handler.DocumentVerificationHeaderHandlerFactory(log, viper)
 	documentVerificationLineHandler := handler.DocumentVerificationLineHandlerFactory(log, viper)
 	dashboardHandler := handler.DashboardHandlerFactory(log, viper)
+	recruitmentFunnelHandler := handler.RecruitmentFunnelHandlerFactory(log, viper)
+	slaHandler := handler.SlaHandlerFactory(log, viper)
 	uploadHandler := handler.UploadHandlerFactory(log, viper)
+	midsuitSyncHandler := handler.MidsuitSyncHandlerFactory(log, viper)
+	tokenRevocationHandler := handler.TokenRevocationHandlerFactory(log, viper)
+	careerFeedHandler := handler.CareerFeedHandlerFactory(log, viper)
+	schedulerHandler := handler.SchedulerHandlerFactory(log, viper)
+	candidateSearchHandler := handler.CandidateSearchHandlerFactory(log, viper)
+	applicantMatchScoreHandler := handler.ApplicantMatchScoreHandlerFactory(log, viper)
+	applicantSourceHandler := handler.ApplicantSourceHandlerFactory(log, viper)
+	referralHandler := handler.ReferralHandlerFactory(log, viper)
+	curriculumVitaeDraftHandler := handler.CurriculumVitaeDraftHandlerFactory(log, viper)
+	duplicateCandidateHandler := handler.DuplicateCandidateHandlerFactory(log, viper)
+	privacyNoticeHandler := handler.PrivacyNoticeHandlerFactory(log, viper)
+	personalDataHandler := handler.PersonalDataHandlerFactory(log, viper)
+	storageHandler := handler.StorageHandlerFactory(log, viper)
 	return &RouteConfig{
 		App:                               app,
 		Log:                               log,
 		Viper:                             viper,
 		AuthMiddleware:                    authMiddleware,
+		AuthorizationMiddleware:           authorizationMiddleware,
+		RateLimitMiddleware:               rateLimitMiddleware,
+		UserRateLimitMiddleware:           userRateLimitMiddleware,
+		SensitiveDataMiddleware:           sensitiveDataMiddleware,
 		UserProfileVerifiedMiddleware:     userProfileVerifiedMiddleware,
 		MPRequestHandler:                  mpRequestHandler,
 		RecruitmentTypeHandler:            recruitmentTypeHandler,
@@ -493,6 +618,21 @@ func NewRouteConfig(app *gin.Engine, viper *viper.Viper, log *logrus.Logger) *Ro
 		DocumentVerificationHeaderHandler: documentVerificationHeaderHandler,
 		DocumentVerificationLineHandler:   documentVerificationLineHandler,
 		DashboardHandler:                  dashboardHandler,
+		RecruitmentFunnelHandler:          recruitmentFunnelHandler,
+		SlaHandler:                        slaHandler,
 		UploadHandler:                     uploadHandler,
+		MidsuitSyncHandler:                midsuitSyncHandler,
+		TokenRevocationHandler:            tokenRevocationHandler,
+		CareerFeedHandler:                 careerFeedHandler,
+		SchedulerHandler:                  schedulerHandler,
+		CandidateSearchHandler:            candidateSearchHandler,
+		ApplicantMatchScoreHandler:        applicantMatchScoreHandler,
+		ApplicantSourceHandler:            applicantSourceHandler,
+		ReferralHandler:                   referralHandler,
+		CurriculumVitaeDraftHandler:       curriculumVitaeDraftHandler,
+		DuplicateCandidateHandler:         duplicateCandidateHandler,
+		PrivacyNoticeHandler:              privacyNoticeHandler,
+		PersonalDataHandler:               personalDataHandler,
+		StorageHandler:                    storageHandler,
 	}
 }
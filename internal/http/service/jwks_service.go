@@ -0,0 +1,331 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IJwksService interface {
+	// GetKey returns the public key for kid, an empty kid is accepted when the set has a single key.
+	// A key published with an alg is only returned for tokens signed with that alg.
+	GetKey(kid string, alg string) (interface{}, error)
+	Enabled() bool
+}
+
+type JwksKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type JwksDocument struct {
+	Keys []JwksKey `json:"keys"`
+}
+
+// JwksPublicKey is a parsed signing key and the alg it was published for, empty when any
+type JwksPublicKey struct {
+	Key interface{}
+	Alg string
+}
+
+// jwksMaxBodySize caps the JWKS document read from the network, a key set is a few kilobytes
+const jwksMaxBodySize = 1 << 20
+
+// JwksService loads the JWKS document from jwt.jwks.url or jwt.jwks.file and keeps the parsed
+// keys in memory for jwt.jwks.cache_ttl seconds. An unknown kid triggers a refresh, at most once
+// per jwt.jwks.min_refresh_interval seconds, so rotated keys are picked up without a restart.
+// Fetched documents are written to jwt.jwks.cache_file, which is used when the URL is unreachable.
+type JwksService struct {
+	Viper *viper.Viper
+	Log   *logrus.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]JwksPublicKey
+	fetchedAt time.Time
+	triedAt   time.Time
+}
+
+func NewJwksService(
+	viper *viper.Viper,
+	log *logrus.Logger,
+) *JwksService {
+	return &JwksService{
+		Viper: viper,
+		Log:   log,
+		keys:  make(map[string]JwksPublicKey),
+	}
+}
+
+var (
+	jwksServiceInstance *JwksService
+	jwksServiceOnce     sync.Once
+)
+
+// JwksServiceFactory returns a shared instance so the key cache survives between requests
+func JwksServiceFactory(
+	viper *viper.Viper,
+	log *logrus.Logger,
+) IJwksService {
+	jwksServiceOnce.Do(func() {
+		jwksServiceInstance = NewJwksService(viper, log)
+	})
+	return jwksServiceInstance
+}
+
+func (s *JwksService) Enabled() bool {
+	return s.Viper.GetString("jwt.jwks.url") != "" || s.Viper.GetString("jwt.jwks.file") != ""
+}
+
+func (s *JwksService) GetKey(kid string, alg string) (interface{}, error) {
+	key, err := s.getKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.Alg != "" && key.Alg != alg {
+		return nil, fmt.Errorf("[JwksService.GetKey] key id %q is for %s, not %s", kid, key.Alg, alg)
+	}
+	return key.Key, nil
+}
+
+func (s *JwksService) getKey(kid string) (JwksPublicKey, error) {
+	if !s.Enabled() {
+		return JwksPublicKey{}, errors.New("[JwksService.GetKey] jwks is not configured")
+	}
+
+	s.mu.RLock()
+	key, found := s.lookup(kid)
+	fresh := time.Since(s.fetchedAt) < s.seconds("jwt.jwks.cache_ttl", 300)
+	canRetry := time.Since(s.triedAt) >= s.seconds("jwt.jwks.min_refresh_interval", 30)
+	s.mu.RUnlock()
+
+	if found && fresh {
+		return key, nil
+	}
+	if !canRetry {
+		if found {
+			return key, nil
+		}
+		return JwksPublicKey{}, fmt.Errorf("[JwksService.GetKey] unknown key id %q", kid)
+	}
+
+	if err := s.refresh(); err != nil {
+		s.Log.Warnf("[JwksService.GetKey] %v", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, found := s.lookup(kid); found {
+		return key, nil
+	}
+	return JwksPublicKey{}, fmt.Errorf("[JwksService.GetKey] unknown key id %q", kid)
+}
+
+// lookup must be called with s.mu held
+func (s *JwksService) lookup(kid string) (JwksPublicKey, bool) {
+	if kid == "" {
+		if len(s.keys) != 1 {
+			return JwksPublicKey{}, false
+		}
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *JwksService) seconds(configKey string, fallback int) time.Duration {
+	value := s.Viper.GetInt(configKey)
+	if value <= 0 {
+		value = fallback
+	}
+	return time.Duration(value) * time.Second
+}
+
+func (s *JwksService) refresh() error {
+	s.mu.Lock()
+	s.triedAt = time.Now()
+	s.mu.Unlock()
+
+	body, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	keys, err := ParseJwks(body)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *JwksService) load() ([]byte, error) {
+	if file := s.Viper.GetString("jwt.jwks.file"); file != "" {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.New("[JwksService.load] error when reading jwks file: " + err.Error())
+		}
+		return body, nil
+	}
+
+	cacheFile := s.Viper.GetString("jwt.jwks.cache_file")
+	body, err := s.fetch(s.Viper.GetString("jwt.jwks.url"))
+	if err != nil {
+		if cacheFile == "" {
+			return nil, err
+		}
+		s.Log.Warnf("[JwksService.load] %v, falling back to %s", err, cacheFile)
+		cached, readErr := os.ReadFile(cacheFile)
+		if readErr != nil {
+			return nil, err
+		}
+		return cached, nil
+	}
+
+	if cacheFile != "" {
+		if err := os.WriteFile(cacheFile, body, 0600); err != nil {
+			s.Log.Warnf("[JwksService.load] error when writing jwks cache file: %v", err)
+		}
+	}
+	return body, nil
+}
+
+func (s *JwksService) fetch(url string) ([]byte, error) {
+	client := &http.Client{
+		Timeout: s.seconds("jwt.jwks.timeout", 5),
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.New("[JwksService.fetch] error when fetching jwks: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[JwksService.fetch] unexpected status code when fetching jwks: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, jwksMaxBodySize+1))
+	if err != nil {
+		return nil, errors.New("[JwksService.fetch] error when reading jwks: " + err.Error())
+	}
+	if len(body) > jwksMaxBodySize {
+		return nil, errors.New("[JwksService.fetch] jwks is larger than 1MB")
+	}
+	return body, nil
+}
+
+// ParseJwks parses the RSA and EC signing keys of a JWKS document, keyed by kid
+func ParseJwks(body []byte) (map[string]JwksPublicKey, error) {
+	var doc JwksDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, errors.New("[ParseJwks] error when parsing jwks: " + err.Error())
+	}
+
+	keys := make(map[string]JwksPublicKey)
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var (
+			key interface{}
+			err error
+		)
+		switch jwk.Kty {
+		case "RSA":
+			key, err = parseRSAJwk(jwk)
+		case "EC":
+			key, err = parseECJwk(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[ParseJwks] key %q: %v", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = JwksPublicKey{Key: key, Alg: jwk.Alg}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("[ParseJwks] jwks has no usable signing keys")
+	}
+	return keys, nil
+}
+
+func parseRSAJwk(jwk JwksKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, errors.New("invalid modulus: " + err.Error())
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, errors.New("invalid exponent: " + err.Error())
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+func parseECJwk(jwk JwksKey) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, errors.New("invalid x coordinate: " + err.Error())
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, errors.New("invalid y coordinate: " + err.Error())
+	}
+
+	key := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return key, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func rsaJwk(kid string, alg string, key *rsa.PublicKey) JwksKey {
+	return JwksKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: alg,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJwk(kid string, alg string, key *ecdsa.PublicKey) JwksKey {
+	return JwksKey{
+		Kty: "EC",
+		Kid: kid,
+		Alg: alg,
+		Crv: key.Curve.Params().Name,
+		X:   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
+
+func marshalJwks(t *testing.T, keys ...JwksKey) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(JwksDocument{Keys: keys})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return body
+}
+
+func TestParseJwks(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+
+	encryptionKey := rsaJwk("enc", "RSA-OAEP", &rsaKey.PublicKey)
+	encryptionKey.Use = "enc"
+	symmetricKey := JwksKey{Kty: "oct", Kid: "oct"}
+	unknownCurve := ecJwk("ec-unknown", "", &ecKey.PublicKey)
+	unknownCurve.Crv = "secp256k1"
+	offCurve := ecJwk("ec-off", "", &ecKey.PublicKey)
+	offCurve.Y = base64.RawURLEncoding.EncodeToString(new(big.Int).Add(ecKey.Y, big.NewInt(1)).Bytes())
+	smallExponent := rsaJwk("rsa-small", "", &rsaKey.PublicKey)
+	smallExponent.E = base64.RawURLEncoding.EncodeToString([]byte{1})
+
+	tests := []struct {
+		name     string
+		body     []byte
+		wantKids map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "rsa and ec signing keys",
+			body:     marshalJwks(t, rsaJwk("rsa-1", "RS256", &rsaKey.PublicKey), ecJwk("ec-1", "ES256", &ecKey.PublicKey)),
+			wantKids: map[string]string{"rsa-1": "RS256", "ec-1": "ES256"},
+		},
+		{
+			name:     "encryption and symmetric keys are skipped",
+			body:     marshalJwks(t, rsaJwk("rsa-1", "", &rsaKey.PublicKey), encryptionKey, symmetricKey),
+			wantKids: map[string]string{"rsa-1": ""},
+		},
+		{name: "only unusable keys", body: marshalJwks(t, encryptionKey, symmetricKey), wantErr: true},
+		{name: "no keys", body: marshalJwks(t), wantErr: true},
+		{name: "not json", body: []byte("<html>"), wantErr: true},
+		{name: "unsupported curve", body: marshalJwks(t, unknownCurve), wantErr: true},
+		{name: "point not on the curve", body: marshalJwks(t, offCurve), wantErr: true},
+		{name: "exponent too small", body: marshalJwks(t, smallExponent), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseJwks(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJwks() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if len(keys) != len(tt.wantKids) {
+				t.Fatalf("ParseJwks() = %d keys, want %d", len(keys), len(tt.wantKids))
+			}
+			for kid, alg := range tt.wantKids {
+				key, ok := keys[kid]
+				if !ok {
+					t.Fatalf("ParseJwks() is missing key %q", kid)
+				}
+				if key.Alg != alg {
+					t.Fatalf("ParseJwks() key %q alg = %q, want %q", kid, key.Alg, alg)
+				}
+			}
+		})
+	}
+}
+
+func TestJwksServiceVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	body := marshalJwks(t,
+		rsaJwk("rsa-1", "RS256", &rsaKey.PublicKey),
+		rsaJwk("rsa-any", "", &rsaKey.PublicKey),
+		ecJwk("ec-1", "ES256", &ecKey.PublicKey),
+	)
+	if err := os.WriteFile(file, body, 0600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	v := viper.New()
+	v.Set("jwt.jwks.file", file)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	svc := NewJwksService(v, log)
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		key     interface{}
+		kid     string
+		wantErr bool
+	}{
+		{name: "RS256", method: jwt.SigningMethodRS256, key: rsaKey, kid: "rsa-1"},
+		{name: "ES256", method: jwt.SigningMethodES256, key: ecKey, kid: "ec-1"},
+		{name: "key published without alg", method: jwt.SigningMethodRS384, key: rsaKey, kid: "rsa-any"},
+		{name: "alg other than the published one", method: jwt.SigningMethodRS384, key: rsaKey, kid: "rsa-1", wantErr: true},
+		{name: "PS256 with an RS256 key", method: jwt.SigningMethodPS256, key: rsaKey, kid: "rsa-1", wantErr: true},
+		{name: "ES256 token on an RSA key", method: jwt.SigningMethodES256, key: ecKey, kid: "rsa-any", wantErr: true},
+		{name: "signed with another key", method: jwt.SigningMethodRS256, key: otherRSAKey, kid: "rsa-1", wantErr: true},
+		{name: "unknown kid", method: jwt.SigningMethodRS256, key: rsaKey, kid: "rsa-2", wantErr: true},
+		{name: "no kid with several keys", method: jwt.SigningMethodRS256, key: rsaKey, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(tt.method, jwt.MapClaims{
+				"id":  "6f1c2a0e-3f0b-4b8a-9a3c-1f5d9d0e7b21",
+				"exp": time.Now().Add(time.Hour).Unix(),
+			})
+			if tt.kid != "" {
+				token.Header["kid"] = tt.kid
+			}
+			signed, err := token.SignedString(tt.key)
+			if err != nil {
+				t.Fatalf("SignedString() error = %v", err)
+			}
+
+			parsed, err := jwt.Parse(signed, func(token *jwt.Token) (interface{}, error) {
+				kid, _ := token.Header["kid"].(string)
+				return svc.GetKey(kid, token.Method.Alg())
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jwt.Parse() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !parsed.Valid {
+				t.Fatalf("jwt.Parse() token is not valid")
+			}
+		})
+	}
+}
+
+func TestJwksServiceSingleKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, marshalJwks(t, ecJwk("ec-1", "ES256", &ecKey.PublicKey)), 0600); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		kid     string
+		alg     string
+		wantErr bool
+	}{
+		{name: "kid", file: file, kid: "ec-1", alg: "ES256"},
+		{name: "no kid with a single key", file: file, alg: "ES256"},
+		{name: "no kid with a single key and another alg", file: file, alg: "ES384", wantErr: true},
+		{name: "jwks not configured", kid: "ec-1", alg: "ES256", wantErr: true},
+		{name: "missing file", file: filepath.Join(t.TempDir(), "missing.json"), kid: "ec-1", alg: "ES256", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			v.Set("jwt.jwks.file", tt.file)
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+
+			_, err := NewJwksService(v, log).GetKey(tt.kid, tt.alg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetKey() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
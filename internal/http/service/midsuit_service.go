@@ -36,6 +36,7 @@ type IMidsuitService interface {
 	RecruitmentTypeMidsuitAPIWithoutFilter(jwtToken string) (*RecruitmentTypeMidsuitAPIResponse, error)
 	RecruitmentTypeMidsuitAPI(filter string, jwtToken string) (*RecruitmentTypeMidsuitAPIResponse, error)
 	SyncGenerateUserMidsuit(empMidsuitID int, jwtToken string) (*string, error)
+	FindEmployeeMidsuit(midsuitID int, jwtToken string) (*EmployeeMidsuitResponse, error)
 }
 
 type MidsuitService struct {
@@ -799,13 +800,13 @@ func (s *MidsuitService) RecruitmentTypeMidsuitAPIWithoutFilter(jwtToken string)
 }
 
 func (s *MidsuitService) RecruitmentTypeMidsuitAPI(filter string, jwtToken string) (*RecruitmentTypeMidsuitAPIResponse, error) {
-	// Properly encode the filter value to handle special characters
-	encodedFilter := url.QueryEscape(filter)
+	// Properly encode the whole filter expression, spaces included
+	encodedFilter := url.QueryEscape(fmt.Sprintf("Value eq '%s'", filter))
 
 	baseURL := strings.TrimRight(s.Viper.GetString("midsuit.url"), "/")
 	endpoint := strings.TrimLeft(s.Viper.GetString("midsuit.api_endpoint"), "/")
 
-	urlStr := fmt.Sprintf("%s/%s/models/HC_RecruitmentType?$filter=Value eq '%s'",
+	urlStr := fmt.Sprintf("%s/%s/models/HC_RecruitmentType?$filter=%s",
 		baseURL,
 		endpoint,
 		encodedFilter)
@@ -962,3 +963,78 @@ func (s *MidsuitService) SyncGenerateUserMidsuit(empMidsuitID int, jwtToken stri
 
 	return &hcEmployeeID, nil
 }
+
+type MidsuitReferenceResponse struct {
+	ID         interface{} `json:"id"`
+	Identifier string      `json:"identifier"`
+}
+
+type EmployeeMidsuitResponse struct {
+	ID              int                       `json:"id"`
+	Name            string                    `json:"Name"`
+	Birthday        string                    `json:"Birthday"`
+	City            string                    `json:"City"`
+	Email           string                    `json:"EMail"`
+	HcNationalID1   string                    `json:"HC_NationalID1"`
+	HcGender        *MidsuitReferenceResponse `json:"HC_Gender"`
+	HCWorkStartDate string                    `json:"HC_WorkStartDate"`
+}
+
+// FindEmployeeMidsuit returns nil when Midsuit has no employee with the given id
+func (s *MidsuitService) FindEmployeeMidsuit(midsuitID int, jwtToken string) (*EmployeeMidsuitResponse, error) {
+	baseURL := strings.TrimRight(s.Viper.GetString("midsuit.url"), "/")
+	endpoint := strings.TrimLeft(s.Viper.GetString("midsuit.api_endpoint"), "/")
+
+	urlStr := fmt.Sprintf("%s/%s/models/hc_employee/%d",
+		baseURL,
+		endpoint,
+		midsuitID)
+
+	method := "GET"
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	req, err := http.NewRequest(method, urlStr, nil)
+	if err != nil {
+		s.Log.Error(err)
+		return nil, fmt.Errorf("[MidsuitService.FindEmployeeMidsuit] Error when creating request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+jwtToken)
+
+	res, err := client.Do(req)
+	if err != nil {
+		s.Log.Error(err)
+		return nil, fmt.Errorf("[MidsuitService.FindEmployeeMidsuit] Error when fetching response: %w", err)
+	}
+	defer res.Body.Close()
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.Log.Error(err)
+		return nil, fmt.Errorf("[MidsuitService.FindEmployeeMidsuit] Error reading response body: %w", err)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		s.Log.Error(string(bodyBytes))
+		return nil, fmt.Errorf("[MidsuitService.FindEmployeeMidsuit] Error response from API: %s", string(bodyBytes))
+	}
+
+	var employeeResponse EmployeeMidsuitResponse
+	if err := json.Unmarshal(bodyBytes, &employeeResponse); err != nil {
+		s.Log.Error(err)
+		return nil, fmt.Errorf("[MidsuitService.FindEmployeeMidsuit] Error when unmarshalling response: %w", err)
+	}
+
+	return &employeeResponse, nil
+}
@@ -0,0 +1,556 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// endpoints of the simulator, used as keys for failure injection
+const (
+	MIDSUIT_SIMULATOR_ENDPOINT_AUTH             = "auth"
+	MIDSUIT_SIMULATOR_ENDPOINT_EMPLOYEE         = "hc_employee"
+	MIDSUIT_SIMULATOR_ENDPOINT_EMPLOYEE_JOB     = "hc_employeejob"
+	MIDSUIT_SIMULATOR_ENDPOINT_WORK_HISTORY     = "hc_workhistory"
+	MIDSUIT_SIMULATOR_ENDPOINT_EDUCATION        = "hc_employeeeducation"
+	MIDSUIT_SIMULATOR_ENDPOINT_ALLOWANCE        = "hc_allowanceprovision"
+	MIDSUIT_SIMULATOR_ENDPOINT_IMAGE            = "ad_image"
+	MIDSUIT_SIMULATOR_ENDPOINT_RECRUITMENT_TYPE = "hc_recruitmenttype"
+	MIDSUIT_SIMULATOR_ENDPOINT_GENERATE_USER    = "hcm_generateuser"
+)
+
+// ids handed out by the simulator start after this one
+const MIDSUIT_SIMULATOR_FIRST_ID = 1000000
+
+// MidsuitSimulatorFailure makes the next Times calls of an endpoint fail with
+// Status and Body. Times 0 keeps failing until the failure is cleared. Delay
+// (milliseconds) is applied before answering, to provoke client timeouts.
+type MidsuitSimulatorFailure struct {
+	Endpoint string `json:"endpoint" mapstructure:"endpoint"`
+	Method   string `json:"method" mapstructure:"method"`
+	Status   int    `json:"status" mapstructure:"status"`
+	Body     string `json:"body" mapstructure:"body"`
+	Times    int    `json:"times" mapstructure:"times"`
+	DelayMS  int    `json:"delay_ms" mapstructure:"delay_ms"`
+}
+
+type MidsuitSimulatorCall struct {
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	Path     string    `json:"path"`
+	Status   int       `json:"status"`
+	At       time.Time `json:"at"`
+}
+
+// MidsuitSimulator is an in-memory stand-in for the Midsuit REST API covering
+// the endpoints MidsuitService uses. It is an http.Handler, so it can be
+// mounted on httptest.NewServer or started on its own with Start.
+type MidsuitSimulator struct {
+	Log           *logrus.Logger
+	APIEndpoint   string
+	AuthEndpoint  string
+	mu            sync.Mutex
+	nextID        int
+	tokens        map[string]bool
+	records       map[string]map[int]map[string]interface{}
+	users         map[int]int
+	failures      []*MidsuitSimulatorFailure
+	calls         []MidsuitSimulatorCall
+	recruitTypes  []map[string]interface{}
+	listener      net.Listener
+	server        *http.Server
+	serverStarted bool
+}
+
+func NewMidsuitSimulator(log *logrus.Logger, apiEndpoint string, authEndpoint string) *MidsuitSimulator {
+	if apiEndpoint == "" {
+		apiEndpoint = "/api/v1"
+	}
+	if authEndpoint == "" {
+		authEndpoint = "/auth/tokens"
+	}
+
+	sim := &MidsuitSimulator{
+		Log:          log,
+		APIEndpoint:  "/" + strings.Trim(apiEndpoint, "/"),
+		AuthEndpoint: "/" + strings.Trim(authEndpoint, "/"),
+	}
+	sim.Reset()
+	return sim
+}
+
+// MidsuitSimulatorFactory builds a simulator from the midsuit config, including
+// the failures listed in midsuit.simulator.failures.
+func MidsuitSimulatorFactory(viper *viper.Viper, log *logrus.Logger) *MidsuitSimulator {
+	sim := NewMidsuitSimulator(log, viper.GetString("midsuit.api_endpoint"), viper.GetString("midsuit.auth_endpoint"))
+
+	var failures []MidsuitSimulatorFailure
+	if err := viper.UnmarshalKey("midsuit.simulator.failures", &failures); err != nil {
+		log.Errorf("[MidsuitSimulator] invalid midsuit.simulator.failures: %v", err)
+	}
+	for _, failure := range failures {
+		sim.Fail(failure)
+	}
+
+	return sim
+}
+
+// Reset drops every record, token, failure and recorded call.
+func (s *MidsuitSimulator) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID = MIDSUIT_SIMULATOR_FIRST_ID
+	s.tokens = make(map[string]bool)
+	s.records = make(map[string]map[int]map[string]interface{})
+	s.users = make(map[int]int)
+	s.failures = nil
+	s.calls = nil
+	s.recruitTypes = []map[string]interface{}{
+		{"id": 1000001, "Name": "Management Trainee", "Value": "MT"},
+		{"id": 1000002, "Name": "Professional Hire", "Value": "PH"},
+		{"id": 1000003, "Name": "Non Staff", "Value": "NS"},
+	}
+}
+
+// Fail injects a failure for an endpoint, see MidsuitSimulatorFailure.
+func (s *MidsuitSimulator) Fail(failure MidsuitSimulatorFailure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if failure.Status == 0 {
+		failure.Status = http.StatusInternalServerError
+	}
+	if failure.Body == "" {
+		failure.Body = `{"title":"Simulated failure","status":` + strconv.Itoa(failure.Status) + `}`
+	}
+	failure.Endpoint = strings.ToLower(failure.Endpoint)
+	failure.Method = strings.ToUpper(failure.Method)
+	s.failures = append(s.failures, &failure)
+}
+
+func (s *MidsuitSimulator) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// Record returns a copy of a stored record, or nil when it does not exist.
+func (s *MidsuitSimulator) Record(model string, id int) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[strings.ToLower(model)][id]
+	if !ok {
+		return nil
+	}
+	return copyMidsuitRecord(record)
+}
+
+// Records returns copies of every stored record of a model.
+func (s *MidsuitSimulator) Records(model string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]map[string]interface{}, 0)
+	for _, record := range s.records[strings.ToLower(model)] {
+		res = append(res, copyMidsuitRecord(record))
+	}
+	return res
+}
+
+func (s *MidsuitSimulator) Calls() []MidsuitSimulatorCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]MidsuitSimulatorCall(nil), s.calls...)
+}
+
+// Start serves the simulator on addr (e.g. "127.0.0.1:0") and returns its base
+// url, to be used as midsuit.url.
+func (s *MidsuitSimulator) Start(addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	s.listener = listener
+	s.server = &http.Server{Handler: s}
+	s.serverStarted = true
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.Log.Errorf("[MidsuitSimulator] server stopped: %v", err)
+		}
+	}()
+
+	s.Log.Infof("[MidsuitSimulator] listening on %s", listener.Addr().String())
+	return "http://" + listener.Addr().String(), nil
+}
+
+func (s *MidsuitSimulator) Close() error {
+	if !s.serverStarted {
+		return nil
+	}
+	return s.server.Close()
+}
+
+func (s *MidsuitSimulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimRight(r.URL.Path, "/")
+
+	// control endpoints, so failures can be injected into a running simulator
+	if strings.HasPrefix(path, "/__simulator") {
+		s.serveControl(w, r, strings.TrimPrefix(path, "/__simulator"))
+		return
+	}
+
+	if !strings.HasPrefix(path, s.APIEndpoint) {
+		s.writeJSON(w, r, "", http.StatusNotFound, map[string]interface{}{"title": "Not Found", "status": http.StatusNotFound})
+		return
+	}
+	path = strings.TrimPrefix(path, s.APIEndpoint)
+
+	if path == s.AuthEndpoint && r.Method == http.MethodPost {
+		if s.injectFailure(w, r, MIDSUIT_SIMULATOR_ENDPOINT_AUTH) {
+			return
+		}
+		s.handleAuth(w, r)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 {
+		s.writeJSON(w, r, "", http.StatusNotFound, map[string]interface{}{"title": "Not Found", "status": http.StatusNotFound})
+		return
+	}
+
+	kind, endpoint := segments[0], strings.ToLower(segments[1])
+	if s.injectFailure(w, r, endpoint) {
+		return
+	}
+	if !s.authorized(r) {
+		s.writeJSON(w, r, endpoint, http.StatusUnauthorized, map[string]interface{}{"title": "Unauthorized", "status": http.StatusUnauthorized})
+		return
+	}
+
+	switch {
+	case kind == "processes" && endpoint == MIDSUIT_SIMULATOR_ENDPOINT_GENERATE_USER && r.Method == http.MethodPost:
+		s.handleGenerateUser(w, r)
+	case kind == "models" && endpoint == MIDSUIT_SIMULATOR_ENDPOINT_RECRUITMENT_TYPE && r.Method == http.MethodGet:
+		s.handleRecruitmentTypes(w, r)
+	case kind == "models" && len(segments) == 2 && r.Method == http.MethodPost:
+		s.handleCreate(w, r, endpoint)
+	case kind == "models" && len(segments) == 3 && (r.Method == http.MethodPut || r.Method == http.MethodGet):
+		id, err := strconv.Atoi(segments[2])
+		if err != nil {
+			s.writeJSON(w, r, endpoint, http.StatusBadRequest, map[string]interface{}{"title": "Invalid id", "status": http.StatusBadRequest})
+			return
+		}
+		if r.Method == http.MethodGet {
+			s.handleGet(w, r, endpoint, id)
+		} else {
+			s.handleUpdate(w, r, endpoint, id)
+		}
+	default:
+		s.writeJSON(w, r, endpoint, http.StatusNotFound, map[string]interface{}{"title": "Not Found", "status": http.StatusNotFound})
+	}
+}
+
+func (s *MidsuitSimulator) handleAuth(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		s.writeJSON(w, r, MIDSUIT_SIMULATOR_ENDPOINT_AUTH, http.StatusBadRequest, map[string]interface{}{"title": "Invalid payload", "status": http.StatusBadRequest})
+		return
+	}
+	if userName, _ := payload["userName"].(string); userName == "" {
+		s.writeJSON(w, r, MIDSUIT_SIMULATOR_ENDPOINT_AUTH, http.StatusUnauthorized, map[string]interface{}{"title": "Invalid credentials", "status": http.StatusUnauthorized})
+		return
+	}
+
+	token := "sim-" + uuid.New().String()
+	s.mu.Lock()
+	s.tokens[token] = true
+	s.mu.Unlock()
+
+	s.writeJSON(w, r, MIDSUIT_SIMULATOR_ENDPOINT_AUTH, http.StatusOK, map[string]interface{}{
+		"userId":        100,
+		"language":      "en_US",
+		"menuTreeId":    10,
+		"token":         token,
+		"refresh_token": "sim-refresh-" + uuid.New().String(),
+	})
+}
+
+func (s *MidsuitSimulator) handleCreate(w http.ResponseWriter, r *http.Request, model string) {
+	record, ok := s.decodeRecord(w, r, model)
+	if !ok {
+		return
+	}
+
+	// child records must point to an existing employee, as they do in Midsuit
+	referenceKey := "HC_Employee_ID"
+	if model == MIDSUIT_SIMULATOR_ENDPOINT_ALLOWANCE {
+		referenceKey = "HC_Employee2_ID"
+	}
+	if model != MIDSUIT_SIMULATOR_ENDPOINT_EMPLOYEE && model != MIDSUIT_SIMULATOR_ENDPOINT_IMAGE {
+		employeeID := midsuitReferenceID(record[referenceKey])
+		if s.Record(MIDSUIT_SIMULATOR_ENDPOINT_EMPLOYEE, employeeID) == nil {
+			s.writeJSON(w, r, model, http.StatusBadRequest, map[string]interface{}{
+				"title":  "Invalid " + referenceKey,
+				"status": http.StatusBadRequest,
+				"detail": fmt.Sprintf("No HC_Employee with id %d", employeeID),
+			})
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	record["id"] = id
+	record["uid"] = uuid.New().String()
+	if s.records[model] == nil {
+		s.records[model] = make(map[int]map[string]interface{})
+	}
+	s.records[model][id] = record
+	res := copyMidsuitRecord(record)
+	s.mu.Unlock()
+
+	s.writeJSON(w, r, model, http.StatusCreated, res)
+}
+
+func (s *MidsuitSimulator) handleUpdate(w http.ResponseWriter, r *http.Request, model string, id int) {
+	changes, ok := s.decodeRecord(w, r, model)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	record, exists := s.records[model][id]
+	if exists {
+		for key, value := range changes {
+			record[key] = value
+		}
+	}
+	res := copyMidsuitRecord(record)
+	s.mu.Unlock()
+
+	if !exists {
+		s.writeJSON(w, r, model, http.StatusNotFound, map[string]interface{}{"title": "Not Found", "status": http.StatusNotFound})
+		return
+	}
+	s.writeJSON(w, r, model, http.StatusOK, res)
+}
+
+func (s *MidsuitSimulator) handleGet(w http.ResponseWriter, r *http.Request, model string, id int) {
+	record := s.Record(model, id)
+	if record == nil {
+		s.writeJSON(w, r, model, http.StatusNotFound, map[string]interface{}{"title": "Not Found", "status": http.StatusNotFound})
+		return
+	}
+	s.writeJSON(w, r, model, http.StatusOK, record)
+}
+
+func (s *MidsuitSimulator) handleRecruitmentTypes(w http.ResponseWriter, r *http.Request) {
+	// supports the only filter MidsuitService sends: Value eq 'XX'
+	filter := r.URL.Query().Get("$filter")
+	value := ""
+	if strings.HasPrefix(filter, "Value eq ") {
+		value = strings.Trim(strings.TrimPrefix(filter, "Value eq "), "'")
+	}
+
+	s.mu.Lock()
+	records := make([]map[string]interface{}, 0)
+	for _, recType := range s.recruitTypes {
+		if value == "" || recType["Value"] == value {
+			records = append(records, copyMidsuitRecord(recType))
+		}
+	}
+	s.mu.Unlock()
+
+	s.writeJSON(w, r, MIDSUIT_SIMULATOR_ENDPOINT_RECRUITMENT_TYPE, http.StatusOK, map[string]interface{}{
+		"page-count":   1,
+		"records-size": len(records),
+		"skip-records": 0,
+		"row-count":    len(records),
+		"array-count":  len(records),
+		"records":      records,
+	})
+}
+
+func (s *MidsuitSimulator) handleGenerateUser(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		s.writeJSON(w, r, MIDSUIT_SIMULATOR_ENDPOINT_GENERATE_USER, http.StatusBadRequest, map[string]interface{}{"title": "Invalid payload", "status": http.StatusBadRequest})
+		return
+	}
+
+	employeeID := midsuitReferenceID(payload["HC_Employee_ID"])
+	s.mu.Lock()
+	s.nextID++
+	instanceID := s.nextID
+	_, exists := s.records[MIDSUIT_SIMULATOR_ENDPOINT_EMPLOYEE][employeeID]
+	if exists {
+		s.users[employeeID] = instanceID
+	}
+	s.mu.Unlock()
+
+	// Midsuit answers process errors with 200 and isError
+	if !exists {
+		s.writeJSON(w, r, MIDSUIT_SIMULATOR_ENDPOINT_GENERATE_USER, http.StatusOK, map[string]interface{}{
+			"AD_PInstance_ID": instanceID,
+			"isError":         true,
+			"summary":         fmt.Sprintf("Employee %d not found", employeeID),
+		})
+		return
+	}
+
+	s.writeJSON(w, r, MIDSUIT_SIMULATOR_ENDPOINT_GENERATE_USER, http.StatusOK, map[string]interface{}{
+		"AD_PInstance_ID": instanceID,
+		"isError":         false,
+		"summary":         fmt.Sprintf("User generated {HC_Employee_ID:%d}", employeeID),
+	})
+}
+
+func (s *MidsuitSimulator) serveControl(w http.ResponseWriter, r *http.Request, path string) {
+	switch {
+	case path == "/failures" && r.Method == http.MethodPost:
+		var failure MidsuitSimulatorFailure
+		if err := json.NewDecoder(r.Body).Decode(&failure); err != nil || failure.Endpoint == "" {
+			http.Error(w, "invalid failure", http.StatusBadRequest)
+			return
+		}
+		s.Fail(failure)
+		w.WriteHeader(http.StatusNoContent)
+	case path == "/failures" && r.Method == http.MethodDelete:
+		s.ClearFailures()
+		w.WriteHeader(http.StatusNoContent)
+	case path == "/reset" && r.Method == http.MethodPost:
+		s.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	case path == "/state" && r.Method == http.MethodGet:
+		s.mu.Lock()
+		state := map[string]interface{}{
+			"records":  s.records,
+			"users":    s.users,
+			"failures": s.failures,
+			"calls":    s.calls,
+		}
+		body, err := json.Marshal(state)
+		s.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// injectFailure answers the request with a pending failure for the endpoint,
+// if there is one.
+func (s *MidsuitSimulator) injectFailure(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+	s.mu.Lock()
+	var failure *MidsuitSimulatorFailure
+	for i, f := range s.failures {
+		if f.Endpoint != endpoint || (f.Method != "" && f.Method != r.Method) {
+			continue
+		}
+		failure = f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		break
+	}
+	s.mu.Unlock()
+
+	if failure == nil {
+		return false
+	}
+	if failure.DelayMS > 0 {
+		time.Sleep(time.Duration(failure.DelayMS) * time.Millisecond)
+	}
+
+	s.recordCall(r, endpoint, failure.Status)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(failure.Status)
+	io.WriteString(w, failure.Body)
+	return true
+}
+
+func (s *MidsuitSimulator) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[token]
+}
+
+func (s *MidsuitSimulator) decodeRecord(w http.ResponseWriter, r *http.Request, model string) (map[string]interface{}, bool) {
+	var record map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+		s.writeJSON(w, r, model, http.StatusBadRequest, map[string]interface{}{"title": "Invalid payload", "status": http.StatusBadRequest, "detail": err.Error()})
+		return nil, false
+	}
+	delete(record, "id")
+	return record, true
+}
+
+func (s *MidsuitSimulator) recordCall(r *http.Request, endpoint string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, MidsuitSimulatorCall{
+		Method:   r.Method,
+		Endpoint: endpoint,
+		Path:     r.URL.Path,
+		Status:   status,
+		At:       time.Now(),
+	})
+}
+
+func (s *MidsuitSimulator) writeJSON(w http.ResponseWriter, r *http.Request, endpoint string, status int, body interface{}) {
+	s.recordCall(r, endpoint, status)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.Log.Errorf("[MidsuitSimulator] failed to write response: %v", err)
+	}
+}
+
+// midsuitReferenceID reads the id of a reference such as {"id": 1000001}.
+func midsuitReferenceID(value interface{}) int {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return midsuitReferenceID(v["id"])
+	case float64:
+		return int(v)
+	case string:
+		id, _ := strconv.Atoi(v)
+		return id
+	}
+	return 0
+}
+
+func copyMidsuitRecord(record map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(record))
+	for key, value := range record {
+		res[key] = value
+	}
+	return res
+}
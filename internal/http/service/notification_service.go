@@ -13,6 +13,8 @@ type INotificationService interface {
 	ApplicantAppliedNotification(createdBy string) error
 	CreateAdministrativeSelectionNotification(createdBy, userID string) error
 	CreateDocumentAgreementNotification(createdBy string, userIDs []string, documentName string) error
+	CreateScheduleReminderNotification(createdBy string, userIDs []string, name, message, url string) error
+	CreateSlaOverdueNotification(createdBy string, userIDs []string, message, url string) error
 }
 
 type NotificationService struct {
@@ -107,3 +109,41 @@ func (s *NotificationService) CreateDocumentAgreementNotification(createdBy stri
 
 	return nil
 }
+
+func (s *NotificationService) CreateScheduleReminderNotification(createdBy string, userIDs []string, name, message, url string) error {
+	payload := &request.CreateNotificationRequest{
+		Application: "RECRUITMENT",
+		Name:        name,
+		URL:         url,
+		Message:     message,
+		UserIDs:     userIDs,
+		CreatedBy:   createdBy,
+	}
+
+	err := s.JulongService.CreateJulongNotification(payload)
+	if err != nil {
+		s.Log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *NotificationService) CreateSlaOverdueNotification(createdBy string, userIDs []string, message, url string) error {
+	payload := &request.CreateNotificationRequest{
+		Application: "RECRUITMENT",
+		Name:        "Overdue Recruitment Stage",
+		URL:         url,
+		Message:     message,
+		UserIDs:     userIDs,
+		CreatedBy:   createdBy,
+	}
+
+	err := s.JulongService.CreateJulongNotification(payload)
+	if err != nil {
+		s.Log.Error(err)
+		return err
+	}
+
+	return nil
+}
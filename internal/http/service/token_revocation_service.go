@@ -0,0 +1,186 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/singleflight"
+)
+
+type ITokenRevocationService interface {
+	// IsRevoked reports whether the token was revoked on its own or together with every
+	// token of its user. A user revocation also rejects tokens without an issued at claim.
+	IsRevoked(tokenID string, userID uuid.UUID, issuedAt *time.Time) (bool, error)
+	// Add puts a revocation made by this replica on the list right away
+	Add(ent *entity.TokenRevocation)
+}
+
+// TokenRevocationService keeps the in-memory copy of the active revocations and reloads it
+// every jwt.revocation.refresh_interval seconds so revocations made by other replicas are
+// picked up. Concurrent requests finding the list stale share a single reload, and a failed
+// reload is only retried after the next interval.
+type TokenRevocationService struct {
+	Viper      *viper.Viper
+	Log        *logrus.Logger
+	Repository repository.ITokenRevocationRepository
+
+	mu       sync.RWMutex
+	tokens   map[string]bool
+	users    map[uuid.UUID]time.Time
+	loadedAt time.Time
+	purgedAt time.Time
+	reloads  singleflight.Group
+	// pending holds the revocations added while a reload is reading the database, they are
+	// put on the reloaded list as the snapshot may predate them
+	loading bool
+	pending []*entity.TokenRevocation
+}
+
+func NewTokenRevocationService(
+	viper *viper.Viper,
+	log *logrus.Logger,
+	repo repository.ITokenRevocationRepository,
+) *TokenRevocationService {
+	return &TokenRevocationService{
+		Viper:      viper,
+		Log:        log,
+		Repository: repo,
+	}
+}
+
+var (
+	tokenRevocationServiceInstance *TokenRevocationService
+	tokenRevocationServiceOnce     sync.Once
+)
+
+// TokenRevocationServiceFactory returns the process wide service, so the middleware and the
+// revocation use case share the same list
+func TokenRevocationServiceFactory(
+	viper *viper.Viper,
+	log *logrus.Logger,
+) ITokenRevocationService {
+	tokenRevocationServiceOnce.Do(func() {
+		repo := repository.TokenRevocationRepositoryFactory(log)
+		tokenRevocationServiceInstance = NewTokenRevocationService(viper, log, repo)
+	})
+	return tokenRevocationServiceInstance
+}
+
+func (s *TokenRevocationService) Add(ent *entity.TokenRevocation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tokens != nil {
+		s.add(ent)
+	}
+	if s.loading {
+		s.pending = append(s.pending, ent)
+	}
+}
+
+func (s *TokenRevocationService) add(ent *entity.TokenRevocation) {
+	switch ent.Kind {
+	case entity.TOKEN_REVOCATION_KIND_TOKEN:
+		s.tokens[ent.TokenID] = true
+	case entity.TOKEN_REVOCATION_KIND_USER:
+		if ent.UserID != nil && ent.RevokedAt.After(s.users[*ent.UserID]) {
+			s.users[*ent.UserID] = ent.RevokedAt
+		}
+	}
+}
+
+func (s *TokenRevocationService) IsRevoked(tokenID string, userID uuid.UUID, issuedAt *time.Time) (bool, error) {
+	if err := s.reload(); err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if tokenID != "" && s.tokens[tokenID] {
+		return true, nil
+	}
+	if revokedAt, ok := s.users[userID]; ok {
+		if issuedAt == nil || !issuedAt.After(revokedAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reload refreshes the list when it is stale. A failed reload keeps serving the previous
+// list, it only fails when nothing was ever loaded.
+func (s *TokenRevocationService) reload() error {
+	interval := s.Viper.GetInt("jwt.revocation.refresh_interval")
+	if interval <= 0 {
+		interval = 30
+	}
+
+	s.mu.RLock()
+	stale := time.Since(s.loadedAt) >= time.Duration(interval)*time.Second
+	s.mu.RUnlock()
+	if !stale {
+		return nil
+	}
+
+	_, err, _ := s.reloads.Do("revocations", func() (interface{}, error) {
+		return nil, s.load()
+	})
+	return err
+}
+
+func (s *TokenRevocationService) load() error {
+	s.mu.Lock()
+	loaded := s.tokens != nil
+	s.loading = true
+	s.pending = nil
+	s.mu.Unlock()
+
+	now := time.Now()
+	active, err := s.Repository.FindAllActive(now)
+	if err != nil {
+		s.Log.Error("[TokenRevocationService.load] " + err.Error())
+		s.mu.Lock()
+		s.loading = false
+		s.pending = nil
+		if loaded {
+			// back off until the next interval instead of hitting a failing database on every request
+			s.loadedAt = now
+		}
+		s.mu.Unlock()
+		if loaded {
+			return nil
+		}
+		return err
+	}
+
+	s.mu.Lock()
+	s.tokens = make(map[string]bool, len(active))
+	s.users = make(map[uuid.UUID]time.Time)
+	for i := range active {
+		s.add(&active[i])
+	}
+	for _, ent := range s.pending {
+		s.add(ent)
+	}
+	s.loading = false
+	s.pending = nil
+	s.loadedAt = now
+	purge := now.Sub(s.purgedAt) >= time.Hour
+	if purge {
+		s.purgedAt = now
+	}
+	s.mu.Unlock()
+
+	if purge {
+		if _, err := s.Repository.DeleteExpired(now); err != nil {
+			s.Log.Error("[TokenRevocationService.load] " + err.Error())
+		}
+	}
+	return nil
+}
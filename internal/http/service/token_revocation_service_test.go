@@ -0,0 +1,266 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type fakeTokenRevocationRepository struct {
+	active []entity.TokenRevocation
+	err    error
+	finds  int
+	// onFind runs while the service is reading the list, like a request revoking a token mid reload
+	onFind func()
+}
+
+func (r *fakeTokenRevocationRepository) CreateTokenRevocation(ent *entity.TokenRevocation) (*entity.TokenRevocation, error) {
+	r.active = append(r.active, *ent)
+	return ent, nil
+}
+
+func (r *fakeTokenRevocationRepository) FindAllActive(now time.Time) ([]entity.TokenRevocation, error) {
+	r.finds++
+	if r.onFind != nil {
+		r.onFind()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return append([]entity.TokenRevocation(nil), r.active...), nil
+}
+
+func (r *fakeTokenRevocationRepository) DeleteExpired(now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestTokenRevocationService(repo *fakeTokenRevocationRepository) *TokenRevocationService {
+	v := viper.New()
+	v.Set("jwt.revocation.refresh_interval", 30)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewTokenRevocationService(v, log, repo)
+}
+
+func tokenRevocation(tokenID string) entity.TokenRevocation {
+	return entity.TokenRevocation{
+		Kind:      entity.TOKEN_REVOCATION_KIND_TOKEN,
+		TokenID:   tokenID,
+		RevokedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func userRevocation(userID uuid.UUID, revokedAt time.Time) entity.TokenRevocation {
+	return entity.TokenRevocation{
+		Kind:      entity.TOKEN_REVOCATION_KIND_USER,
+		UserID:    &userID,
+		RevokedAt: revokedAt,
+		ExpiresAt: revokedAt.Add(time.Hour),
+	}
+}
+
+func TestTokenRevocationServiceIsRevoked(t *testing.T) {
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	revokedAt := time.Now().Add(-10 * time.Minute)
+	before := revokedAt.Add(-time.Minute)
+	after := revokedAt.Add(time.Minute)
+
+	svc := newTestTokenRevocationService(&fakeTokenRevocationRepository{
+		active: []entity.TokenRevocation{
+			tokenRevocation("revoked-jti"),
+			userRevocation(userID, revokedAt.Add(-time.Hour)),
+			userRevocation(userID, revokedAt),
+		},
+	})
+
+	tests := []struct {
+		name     string
+		tokenID  string
+		userID   uuid.UUID
+		issuedAt *time.Time
+		want     bool
+	}{
+		{name: "revoked token", tokenID: "revoked-jti", userID: otherUserID, issuedAt: &after, want: true},
+		{name: "other token", tokenID: "other-jti", userID: otherUserID, issuedAt: &after},
+		{name: "token without id", userID: otherUserID, issuedAt: &after},
+		{name: "user token issued before the revocation", tokenID: "other-jti", userID: userID, issuedAt: &before, want: true},
+		{name: "user token issued at the revocation", tokenID: "other-jti", userID: userID, issuedAt: &revokedAt, want: true},
+		{name: "user token issued after the latest revocation", tokenID: "other-jti", userID: userID, issuedAt: &after},
+		{name: "user token without issued at", tokenID: "other-jti", userID: userID, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.IsRevoked(tt.tokenID, tt.userID, tt.issuedAt)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("IsRevoked() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenRevocationServiceReload(t *testing.T) {
+	userID := uuid.New()
+	errDatabase := errors.New("database is down")
+
+	tests := []struct {
+		name string
+		// setup runs after the first IsRevoked loaded the list
+		setup     func(svc *TokenRevocationService, repo *fakeTokenRevocationRepository)
+		want      bool
+		wantFinds int
+	}{
+		{
+			name: "revocations of other replicas wait for the interval",
+			setup: func(svc *TokenRevocationService, repo *fakeTokenRevocationRepository) {
+				repo.active = append(repo.active, tokenRevocation("jti-1"))
+			},
+			wantFinds: 1,
+		},
+		{
+			name: "revocations of other replicas are picked up after the interval",
+			setup: func(svc *TokenRevocationService, repo *fakeTokenRevocationRepository) {
+				repo.active = append(repo.active, tokenRevocation("jti-1"))
+				svc.loadedAt = svc.loadedAt.Add(-time.Minute)
+			},
+			want:      true,
+			wantFinds: 2,
+		},
+		{
+			name: "a failed reload keeps the loaded list",
+			setup: func(svc *TokenRevocationService, repo *fakeTokenRevocationRepository) {
+				svc.Add(&entity.TokenRevocation{Kind: entity.TOKEN_REVOCATION_KIND_TOKEN, TokenID: "jti-1"})
+				repo.err = errDatabase
+				svc.loadedAt = svc.loadedAt.Add(-time.Minute)
+			},
+			want:      true,
+			wantFinds: 2,
+		},
+		{
+			name: "an added revocation survives a reload that started before it",
+			setup: func(svc *TokenRevocationService, repo *fakeTokenRevocationRepository) {
+				added := userRevocation(userID, time.Now())
+				repo.onFind = func() {
+					svc.Add(&added)
+				}
+				svc.loadedAt = svc.loadedAt.Add(-time.Minute)
+			},
+			want:      true,
+			wantFinds: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTokenRevocationRepository{}
+			svc := newTestTokenRevocationService(repo)
+			if _, err := svc.IsRevoked("jti-0", userID, nil); err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+
+			tt.setup(svc, repo)
+
+			got, err := svc.IsRevoked("jti-1", userID, nil)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("IsRevoked() = %t, want %t", got, tt.want)
+			}
+			if repo.finds != tt.wantFinds {
+				t.Fatalf("FindAllActive() called %d times, want %d", repo.finds, tt.wantFinds)
+			}
+		})
+	}
+}
+
+func TestTokenRevocationServiceFailedReload(t *testing.T) {
+	errDatabase := errors.New("database is down")
+
+	tests := []struct {
+		name string
+		// loaded makes a first successful reload before the database fails
+		loaded    bool
+		wantErr   bool
+		wantFinds int
+	}{
+		{name: "nothing loaded yet fails and retries on the next request", wantErr: true, wantFinds: 2},
+		{name: "loaded list backs off until the next interval", loaded: true, wantFinds: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTokenRevocationRepository{}
+			svc := newTestTokenRevocationService(repo)
+			if tt.loaded {
+				if _, err := svc.IsRevoked("jti", uuid.New(), nil); err != nil {
+					t.Fatalf("IsRevoked() error = %v", err)
+				}
+				svc.loadedAt = svc.loadedAt.Add(-time.Minute)
+			}
+
+			repo.err = errDatabase
+			for i := 0; i < 2; i++ {
+				_, err := svc.IsRevoked("jti", uuid.New(), nil)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("IsRevoked() call %d error = %v, wantErr %t", i, err, tt.wantErr)
+				}
+			}
+			if repo.finds != tt.wantFinds {
+				t.Fatalf("FindAllActive() called %d times, want %d", repo.finds, tt.wantFinds)
+			}
+		})
+	}
+}
+
+func TestTokenRevocationServiceAdd(t *testing.T) {
+	userID := uuid.New()
+	issuedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		add     entity.TokenRevocation
+		tokenID string
+		want    bool
+	}{
+		{name: "token", add: tokenRevocation("jti-1"), tokenID: "jti-1", want: true},
+		{name: "other token", add: tokenRevocation("jti-2"), tokenID: "jti-1"},
+		{name: "user", add: userRevocation(userID, time.Now()), tokenID: "jti-1", want: true},
+		{name: "user revoked before the token was issued", add: userRevocation(userID, issuedAt.Add(-time.Minute)), tokenID: "jti-1"},
+		{name: "user revocation without user", add: entity.TokenRevocation{Kind: entity.TOKEN_REVOCATION_KIND_USER, RevokedAt: time.Now()}, tokenID: "jti-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTokenRevocationRepository{}
+			svc := newTestTokenRevocationService(repo)
+			if _, err := svc.IsRevoked("jti-0", userID, &issuedAt); err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+
+			svc.Add(&tt.add)
+
+			got, err := svc.IsRevoked(tt.tokenID, userID, &issuedAt)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("IsRevoked() = %t, want %t", got, tt.want)
+			}
+			if repo.finds != 1 {
+				t.Fatalf("FindAllActive() called %d times, want the added revocation without a reload", repo.finds)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IUploadService interface {
+	Save(file *multipart.FileHeader, purpose storage.UploadPurpose, dir string) (string, error)
+	SaveAs(file *multipart.FileHeader, purpose storage.UploadPurpose, filePath string) (string, error)
+}
+
+type UploadService struct {
+	Viper                      *viper.Viper
+	Log                        *logrus.Logger
+	Storage                    storage.IStorage
+	Scanner                    storage.IMalwareScanner
+	UploadQuarantineRepository repository.IUploadQuarantineRepository
+}
+
+func NewUploadService(
+	viper *viper.Viper,
+	log *logrus.Logger,
+	fileStorage storage.IStorage,
+	scanner storage.IMalwareScanner,
+	uqRepository repository.IUploadQuarantineRepository,
+) IUploadService {
+	return &UploadService{
+		Viper:                      viper,
+		Log:                        log,
+		Storage:                    fileStorage,
+		Scanner:                    scanner,
+		UploadQuarantineRepository: uqRepository,
+	}
+}
+
+func UploadServiceFactory(viper *viper.Viper, log *logrus.Logger) IUploadService {
+	fileStorage := storage.StorageFactory(viper, log)
+	scanner := storage.MalwareScannerFactory(viper, log)
+	uqRepository := repository.UploadQuarantineRepositoryFactory(log)
+	return NewUploadService(viper, log, fileStorage, scanner, uqRepository)
+}
+
+// Save checks an upload against the policy of its purpose and stores it under dir. The type is
+// sniffed from the content, the filename is cleaned and gets the extension of that type, images
+// are re-encoded without their metadata and every file is scanned for malware first. Infected
+// files are moved to the quarantine and ErrUploadInfected is returned. It returns the stored path.
+func (s *UploadService) Save(file *multipart.FileHeader, purpose storage.UploadPurpose, dir string) (string, error) {
+	return s.store(file, purpose, func(filename string) string {
+		return strings.TrimRight(dir, "/") + "/" + timestamped(filename)
+	})
+}
+
+// SaveAs is Save for a file that has to keep a fixed path, e.g. a template read by its name
+func (s *UploadService) SaveAs(file *multipart.FileHeader, purpose storage.UploadPurpose, filePath string) (string, error) {
+	return s.store(file, purpose, func(string) string {
+		return filePath
+	})
+}
+
+func (s *UploadService) store(file *multipart.FileHeader, purpose storage.UploadPurpose, pathFor func(filename string) string) (string, error) {
+	policy := storage.UploadPolicyFor(s.Viper, purpose)
+	if file.Size > policy.MaxSize {
+		return "", storage.TooLargeError(policy.MaxSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	// one byte over the limit is enough to tell the file is too large
+	data, err := io.ReadAll(io.LimitReader(src, policy.MaxSize+1))
+	if err != nil {
+		return "", err
+	}
+
+	contentType, ext, err := storage.SniffUpload(policy, data)
+	if err != nil {
+		return "", err
+	}
+	filename := storage.SanitizeFilename(file.Filename, ext)
+
+	result, err := s.Scanner.Scan(data)
+	if err != nil {
+		if !s.Viper.GetBool("upload.scanner.fail_open") {
+			s.Log.Error("[UploadService.store] " + err.Error())
+			if !errors.Is(err, storage.ErrUploadScanUnavailable) {
+				err = fmt.Errorf("%w: %v", storage.ErrUploadScanUnavailable, err)
+			}
+			return "", err
+		}
+		s.Log.Warn("[UploadService.store] storing " + filename + " unscanned: " + err.Error())
+	} else if result.Infected {
+		s.quarantine(file.Filename, filename, purpose, contentType, data, result.Signature)
+		return "", fmt.Errorf("%w (%s)", storage.ErrUploadInfected, result.Signature)
+	}
+
+	if storage.IsImageContentType(contentType) {
+		data, err = storage.StripImageMetadata(data, contentType)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	filePath := pathFor(filename)
+	if err := s.Storage.Put(filePath, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		s.Log.Error("[UploadService.store] " + err.Error())
+		return "", err
+	}
+
+	return filePath, nil
+}
+
+// quarantine keeps a rejected upload for review. Failures are only logged, the upload is refused
+// either way.
+func (s *UploadService) quarantine(originalName string, filename string, purpose storage.UploadPurpose, contentType string, data []byte, signature string) {
+	filePath := storage.QUARANTINE_KEY_PREFIX + timestamped(filename)
+	s.Log.Warnf("[UploadService.quarantine] %s upload %q matched %s, moved to %s", purpose, originalName, signature, filePath)
+
+	if err := s.Storage.Put(filePath, bytes.NewReader(data), int64(len(data)), "application/octet-stream"); err != nil {
+		s.Log.Error("[UploadService.quarantine] " + err.Error())
+		return
+	}
+
+	if _, err := s.UploadQuarantineRepository.CreateUploadQuarantine(&entity.UploadQuarantine{
+		Purpose:      string(purpose),
+		OriginalName: originalName,
+		Path:         filePath,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Scanner:      s.Scanner.Name(),
+		Signature:    signature,
+	}); err != nil {
+		s.Log.Error("[UploadService.quarantine] " + err.Error())
+	}
+}
+
+func timestamped(filename string) string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10) + "_" + filename
+}
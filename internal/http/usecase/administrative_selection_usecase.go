@@ -16,7 +16,7 @@ import (
 )
 
 type IAdministrativeSelectionUsecase interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.AdministrativeSelectionResponse, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.AdministrativeSelectionResponse, int64, error)
 	FindAllPaginatedPic(employeeID uuid.UUID, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.AdministrativeSelectionResponse, int64, error)
 	CreateAdministrativeSelection(req *request.CreateAdministrativeSelectionRequest) (*response.AdministrativeSelectionResponse, error)
 	FindByID(id string) (*response.AdministrativeSelectionResponse, error)
@@ -61,8 +61,8 @@ func AdministrativeSelectionUsecaseFactory(log *logrus.Logger, viper *viper.Vipe
 	return NewAdministrativeSelectionUsecase(log, repo, asDto, viper, jpRepo, ppRepo)
 }
 
-func (uc *AdministrativeSelectionUsecase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.AdministrativeSelectionResponse, int64, error) {
-	entities, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter)
+func (uc *AdministrativeSelectionUsecase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.AdministrativeSelectionResponse, int64, error) {
+	entities, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter, orgScope)
 	if err != nil {
 		uc.Log.Error("[AdministrativeSelectionUsecase.FindAllPaginated] " + err.Error())
 		return nil, 0, err
@@ -0,0 +1,524 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// defaultApplicantMatchWeights can be overridden per criterion in match_scoring.weights
+var defaultApplicantMatchWeights = map[entity.ApplicantMatchCriterion]float64{
+	entity.APPLICANT_MATCH_CRITERION_EDUCATION_LEVEL: 25,
+	entity.APPLICANT_MATCH_CRITERION_MAJOR:           20,
+	entity.APPLICANT_MATCH_CRITERION_EXPERIENCE:      20,
+	entity.APPLICANT_MATCH_CRITERION_SKILLS:          15,
+	entity.APPLICANT_MATCH_CRITERION_AGE:             10,
+	entity.APPLICANT_MATCH_CRITERION_EXPECTED_SALARY: 10,
+}
+
+// educationLevelRanks orders the education levels, D4 is equal to S1
+var educationLevelRanks = map[string]int{
+	string(entity.EDUCATION_LEVEL_ENUM_TK):       0,
+	string(entity.EDUCATION_LEVEL_ENUM_SD):       1,
+	string(entity.EDUCATION_LEVEL_ENUM_SMP):      2,
+	string(entity.EDUCATION_LEVEL_ENUM_SMA):      3,
+	string(entity.EDUCATION_LEVEL_ENUM_D1):       4,
+	string(entity.EDUCATION_LEVEL_ENUM_D2):       5,
+	string(entity.EDUCATION_LEVEL_ENUM_D3):       6,
+	string(entity.EDUCATION_LEVEL_ENUM_D4):       7,
+	string(entity.EDUCATION_LEVEL_ENUM_BACHELOR): 7,
+	string(entity.EDUCATION_LEVEL_ENUM_MASTER):   8,
+	string(entity.EDUCATION_LEVEL_ENUM_DOCTORAL): 9,
+}
+
+var (
+	matchSkillSeparator = regexp.MustCompile(`(?i)[,;/\n]|\s+(?:and|dan|or|atau)\s+`)
+	matchWordSeparator  = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+	matchLeadingNumber  = regexp.MustCompile(`\d+`)
+	matchAmountCents    = regexp.MustCompile(`[.,]\d{2}$`)
+)
+
+// IApplicantMatchScoreUseCase scores how well applicants match their job posting
+type IApplicantMatchScoreUseCase interface {
+	RegisterJobs(scheduler ISchedulerUseCase) error
+	ScoreStaleApplicants(ctx context.Context) (string, error)
+	RecalculateByJobPostingID(jobPostingID uuid.UUID, orgScope *repository.OrganizationScope) (int, error)
+}
+
+type ApplicantMatchScoreUseCase struct {
+	Log                  *logrus.Logger
+	Viper                *viper.Viper
+	Repository           repository.IApplicantMatchScoreRepository
+	JobPostingRepository repository.IJobPostingRepository
+	MPRequestMessage     messaging.IMPRequestMessage
+}
+
+func NewApplicantMatchScoreUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IApplicantMatchScoreRepository,
+	jpRepository repository.IJobPostingRepository,
+	mprMessage messaging.IMPRequestMessage,
+) IApplicantMatchScoreUseCase {
+	return &ApplicantMatchScoreUseCase{
+		Log:                  log,
+		Viper:                viper,
+		Repository:           repo,
+		JobPostingRepository: jpRepository,
+		MPRequestMessage:     mprMessage,
+	}
+}
+
+func ApplicantMatchScoreUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IApplicantMatchScoreUseCase {
+	repo := repository.ApplicantMatchScoreRepositoryFactory(log)
+	jpRepository := repository.JobPostingRepositoryFactory(log)
+	mprMessage := messaging.MPRequestMessageFactory(log)
+	return NewApplicantMatchScoreUseCase(log, viper, repo, jpRepository, mprMessage)
+}
+
+func (uc *ApplicantMatchScoreUseCase) RegisterJobs(scheduler ISchedulerUseCase) error {
+	if err := scheduler.RegisterJob("score_applicants", "*/5 * * * *", uc.ScoreStaleApplicants); err != nil {
+		uc.Log.Error("[ApplicantMatchScoreUseCase.RegisterJobs] " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// ScoreStaleApplicants scores new applicants and rescores the ones whose profile or job posting
+// changed. The MP request of a job posting is fetched once per run.
+func (uc *ApplicantMatchScoreUseCase) ScoreStaleApplicants(ctx context.Context) (string, error) {
+	batchSize := uc.Viper.GetInt("match_scoring.batch_size")
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+
+	applicants, err := uc.Repository.FindStaleApplicants(batchSize)
+	if err != nil {
+		uc.Log.Error("[ApplicantMatchScoreUseCase.ScoreStaleApplicants] " + err.Error())
+		return "", err
+	}
+
+	scored, failed := uc.scoreApplicants(ctx, applicants)
+	if failed > 0 && scored == 0 {
+		return "", fmt.Errorf("failed to score %d applicants", failed)
+	}
+
+	return fmt.Sprintf("scored %d applicants, %d failed", scored, failed), nil
+}
+
+// RecalculateByJobPostingID rescores every applicant of a job posting, e.g. after its MP request changed
+func (uc *ApplicantMatchScoreUseCase) RecalculateByJobPostingID(jobPostingID uuid.UUID, orgScope *repository.OrganizationScope) (int, error) {
+	jobPosting, err := uc.JobPostingRepository.FindByID(jobPostingID)
+	if err != nil {
+		uc.Log.Error("[ApplicantMatchScoreUseCase.RecalculateByJobPostingID] " + err.Error())
+		return 0, err
+	}
+	if jobPosting == nil || !orgScope.Allows(jobPosting.ForOrganizationID) {
+		uc.Log.Error("[ApplicantMatchScoreUseCase.RecalculateByJobPostingID] " + "Job Posting not found")
+		return 0, errors.New("job posting not found")
+	}
+
+	applicants, err := uc.Repository.FindApplicantsByJobPostingID(jobPostingID)
+	if err != nil {
+		uc.Log.Error("[ApplicantMatchScoreUseCase.RecalculateByJobPostingID] " + err.Error())
+		return 0, err
+	}
+
+	scored, failed := uc.scoreApplicants(context.Background(), applicants)
+	if failed > 0 {
+		return scored, fmt.Errorf("failed to score %d of %d applicants", failed, len(applicants))
+	}
+
+	return scored, nil
+}
+
+func (uc *ApplicantMatchScoreUseCase) scoreApplicants(ctx context.Context, applicants []entity.Applicant) (int, int) {
+	mpRequests := map[uuid.UUID]*response.MPRequestHeaderResponse{}
+	mpRequestErrors := map[uuid.UUID]error{}
+	scored, failed := 0, 0
+
+	for i := range applicants {
+		if ctx.Err() != nil {
+			break
+		}
+		applicant := &applicants[i]
+		if applicant.UserProfile == nil || applicant.JobPosting == nil {
+			failed++
+			continue
+		}
+
+		if _, ok := mpRequests[applicant.JobPostingID]; !ok {
+			mpRequests[applicant.JobPostingID], mpRequestErrors[applicant.JobPostingID] = uc.findMPRequest(applicant.JobPosting)
+		}
+		// scored later, a score without the MP request requirements would be misleading
+		if mpRequestErrors[applicant.JobPostingID] != nil {
+			failed++
+			continue
+		}
+
+		matchScore := scoreApplicantMatch(applicant, mpRequests[applicant.JobPostingID], uc.weights(), time.Now())
+		if _, err := uc.Repository.SaveMatchScore(matchScore); err != nil {
+			uc.Log.Error("[ApplicantMatchScoreUseCase.scoreApplicants] " + err.Error())
+			failed++
+			continue
+		}
+		scored++
+	}
+
+	return scored, failed
+}
+
+// findMPRequest returns nil for a job posting without an MP request, the applicant is then
+// scored on what the job posting itself requires
+func (uc *ApplicantMatchScoreUseCase) findMPRequest(jobPosting *entity.JobPosting) (*response.MPRequestHeaderResponse, error) {
+	if jobPosting.MPRequest == nil || jobPosting.MPRequest.MPRCloneID == nil {
+		return nil, nil
+	}
+	mpRequest, err := uc.MPRequestMessage.SendFindByIdMessage(jobPosting.MPRequest.MPRCloneID.String())
+	if err != nil {
+		uc.Log.Error("[ApplicantMatchScoreUseCase.findMPRequest] " + err.Error())
+		return nil, err
+	}
+	return mpRequest, nil
+}
+
+func (uc *ApplicantMatchScoreUseCase) weights() map[entity.ApplicantMatchCriterion]float64 {
+	weights := map[entity.ApplicantMatchCriterion]float64{}
+	for criterion, weight := range defaultApplicantMatchWeights {
+		key := "match_scoring.weights." + strings.ToLower(string(criterion))
+		if uc.Viper.IsSet(key) {
+			weight = uc.Viper.GetFloat64(key)
+		}
+		weights[criterion] = weight
+	}
+	return weights
+}
+
+// scoreApplicantMatch compares the profile of the applicant with the job posting and its MP request
+func scoreApplicantMatch(applicant *entity.Applicant, mpRequest *response.MPRequestHeaderResponse, weights map[entity.ApplicantMatchCriterion]float64, now time.Time) *entity.ApplicantMatchScore {
+	if mpRequest == nil {
+		mpRequest = &response.MPRequestHeaderResponse{}
+	}
+	profile := applicant.UserProfile
+
+	items := []entity.ApplicantMatchScoreItem{
+		scoreEducationLevel(profile, mpRequest),
+		scoreMajor(profile, mpRequest),
+		scoreExperience(profile, applicant.JobPosting, mpRequest),
+		scoreSkills(profile, mpRequest),
+		scoreAge(profile, mpRequest, now),
+		scoreExpectedSalary(profile, applicant.JobPosting, mpRequest),
+	}
+
+	var total, totalWeight float64
+	for i := range items {
+		items[i].Weight = weights[items[i].Criterion]
+		items[i].Score = roundScore(items[i].Score)
+		if !items[i].Applicable || items[i].Weight <= 0 {
+			continue
+		}
+		total += items[i].Score * items[i].Weight
+		totalWeight += items[i].Weight
+	}
+
+	score := 0.0
+	if totalWeight > 0 {
+		score = roundScore(total / totalWeight)
+	}
+
+	return &entity.ApplicantMatchScore{
+		ApplicantID:  applicant.ID,
+		JobPostingID: applicant.JobPostingID,
+		Score:        score,
+		ScoredAt:     now,
+		Items:        items,
+	}
+}
+
+func scoreEducationLevel(profile *entity.UserProfile, mpRequest *response.MPRequestHeaderResponse) entity.ApplicantMatchScoreItem {
+	item := entity.ApplicantMatchScoreItem{
+		Criterion:   entity.APPLICANT_MATCH_CRITERION_EDUCATION_LEVEL,
+		Requirement: mpRequest.MinimumEducation,
+	}
+	required, ok := educationLevelRanks[strings.ToUpper(strings.TrimSpace(mpRequest.MinimumEducation))]
+	if !ok {
+		item.Note = "no minimum education required"
+		return item
+	}
+	item.Applicable = true
+
+	highest, highestLevel := -1, ""
+	for _, education := range profile.Educations {
+		if rank, ok := educationLevelRanks[string(education.EducationLevel)]; ok && rank > highest {
+			highest, highestLevel = rank, string(education.EducationLevel)
+		}
+	}
+	item.CandidateValue = highestLevel
+
+	switch {
+	case highest < 0:
+		item.Note = "no education filled in"
+	case highest >= required:
+		item.Score = 100
+	case highest == required-1:
+		item.Score = 50
+		item.Note = "one level below the minimum"
+	default:
+		item.Note = "below the minimum"
+	}
+	return item
+}
+
+func scoreMajor(profile *entity.UserProfile, mpRequest *response.MPRequestHeaderResponse) entity.ApplicantMatchScoreItem {
+	item := entity.ApplicantMatchScoreItem{
+		Criterion: entity.APPLICANT_MATCH_CRITERION_MAJOR,
+	}
+	var required []string
+	for _, requestMajor := range mpRequest.RequestMajors {
+		if major, ok := requestMajor["major"].(map[string]interface{}); ok {
+			if name, ok := major["major"].(string); ok && strings.TrimSpace(name) != "" {
+				required = append(required, strings.TrimSpace(name))
+			}
+		}
+	}
+	item.Requirement = strings.Join(required, ", ")
+	if len(required) == 0 {
+		item.Note = "no major required"
+		return item
+	}
+	item.Applicable = true
+
+	var majors []string
+	for _, education := range profile.Educations {
+		if strings.TrimSpace(education.Major) == "" {
+			continue
+		}
+		majors = append(majors, education.Major)
+		for _, requiredMajor := range required {
+			a, b := strings.ToLower(education.Major), strings.ToLower(requiredMajor)
+			switch {
+			case a == b || strings.Contains(a, b) || strings.Contains(b, a):
+				item.Score = 100
+			case sharesWord(a, b) && item.Score < 50:
+				item.Score = 50
+				item.Note = "related major"
+			}
+		}
+	}
+	item.CandidateValue = strings.Join(majors, ", ")
+	if item.Score == 100 {
+		item.Note = ""
+	} else if item.Score == 0 {
+		item.Note = "major does not match"
+	}
+	return item
+}
+
+func scoreExperience(profile *entity.UserProfile, jobPosting *entity.JobPosting, mpRequest *response.MPRequestHeaderResponse) entity.ApplicantMatchScoreItem {
+	item := entity.ApplicantMatchScoreItem{
+		Criterion: entity.APPLICANT_MATCH_CRITERION_EXPERIENCE,
+	}
+	required := mpRequest.MinimumExperience
+	if required <= 0 && jobPosting != nil {
+		required = parseLeadingNumber(jobPosting.MinimumWorkExperience)
+	}
+	if required <= 0 {
+		item.Note = "no minimum experience required"
+		return item
+	}
+	item.Applicable = true
+	item.Requirement = fmt.Sprintf("%d years", required)
+
+	years := 0
+	for _, workExperience := range profile.WorkExperiences {
+		years += workExperience.YearExperience
+	}
+	item.CandidateValue = fmt.Sprintf("%d years", years)
+	item.Score = math.Min(100, float64(years)/float64(required)*100)
+	if years < required {
+		item.Note = fmt.Sprintf("%d years short", required-years)
+	}
+	return item
+}
+
+func scoreSkills(profile *entity.UserProfile, mpRequest *response.MPRequestHeaderResponse) entity.ApplicantMatchScoreItem {
+	item := entity.ApplicantMatchScoreItem{
+		Criterion: entity.APPLICANT_MATCH_CRITERION_SKILLS,
+	}
+	seen := map[string]bool{}
+	var required []string
+	for _, text := range []string{mpRequest.ComputerSkill, mpRequest.LanguageSkill, mpRequest.OtherSkill} {
+		for _, skill := range matchSkillSeparator.Split(text, -1) {
+			skill = strings.TrimSpace(skill)
+			if skill == "" || seen[strings.ToLower(skill)] {
+				continue
+			}
+			seen[strings.ToLower(skill)] = true
+			required = append(required, skill)
+		}
+	}
+	item.Requirement = strings.Join(required, ", ")
+	if len(required) == 0 {
+		item.Note = "no skills required"
+		return item
+	}
+	item.Applicable = true
+
+	var texts []string
+	for _, skill := range profile.Skills {
+		texts = append(texts, skill.Name, skill.Description)
+	}
+	for _, workExperience := range profile.WorkExperiences {
+		texts = append(texts, workExperience.Name, workExperience.JobDescription)
+	}
+	candidateText := " " + strings.Join(matchWordSeparator.Split(strings.ToLower(strings.Join(texts, " ")), -1), " ") + " "
+
+	var matched, missing []string
+	for _, skill := range required {
+		words := strings.Join(matchWordSeparator.Split(strings.ToLower(skill), -1), " ")
+		if strings.Contains(candidateText, " "+strings.TrimSpace(words)+" ") {
+			matched = append(matched, skill)
+		} else {
+			missing = append(missing, skill)
+		}
+	}
+	item.CandidateValue = strings.Join(matched, ", ")
+	item.Score = float64(len(matched)) / float64(len(required)) * 100
+	if len(missing) > 0 {
+		item.Note = "missing: " + strings.Join(missing, ", ")
+	}
+	return item
+}
+
+func scoreAge(profile *entity.UserProfile, mpRequest *response.MPRequestHeaderResponse, now time.Time) entity.ApplicantMatchScoreItem {
+	item := entity.ApplicantMatchScoreItem{
+		Criterion: entity.APPLICANT_MATCH_CRITERION_AGE,
+	}
+	minimum, maximum := mpRequest.MinimumAge, mpRequest.MaximumAge
+	if minimum <= 0 && maximum <= 0 {
+		item.Note = "no age range required"
+		return item
+	}
+	item.Applicable = true
+	switch {
+	case minimum > 0 && maximum > 0:
+		item.Requirement = fmt.Sprintf("%d - %d", minimum, maximum)
+	case minimum > 0:
+		item.Requirement = fmt.Sprintf(">= %d", minimum)
+	default:
+		item.Requirement = fmt.Sprintf("<= %d", maximum)
+	}
+
+	age := profile.Age
+	if !profile.BirthDate.IsZero() {
+		age = now.Year() - profile.BirthDate.Year()
+		if now.YearDay() < profile.BirthDate.YearDay() {
+			age--
+		}
+	}
+	if age <= 0 {
+		item.Note = "age unknown"
+		return item
+	}
+	item.CandidateValue = strconv.Itoa(age)
+
+	// every year outside of the range costs 20 points
+	outside := 0
+	if minimum > 0 && age < minimum {
+		outside = minimum - age
+	}
+	if maximum > 0 && age > maximum {
+		outside = age - maximum
+	}
+	item.Score = math.Max(0, 100-float64(outside)*20)
+	if outside > 0 {
+		item.Note = fmt.Sprintf("%d years outside the range", outside)
+	}
+	return item
+}
+
+func scoreExpectedSalary(profile *entity.UserProfile, jobPosting *entity.JobPosting, mpRequest *response.MPRequestHeaderResponse) entity.ApplicantMatchScoreItem {
+	item := entity.ApplicantMatchScoreItem{
+		Criterion: entity.APPLICANT_MATCH_CRITERION_EXPECTED_SALARY,
+	}
+	salaryMin, salaryMax := mpRequest.SalaryMin, mpRequest.SalaryMax
+	if jobPosting != nil && parseAmount(jobPosting.SalaryMax) > 0 {
+		salaryMin, salaryMax = jobPosting.SalaryMin, jobPosting.SalaryMax
+	}
+	maximum := parseAmount(salaryMax)
+	if maximum <= 0 {
+		item.Note = "no salary band"
+		return item
+	}
+	item.Requirement = fmt.Sprintf("%d - %d", parseAmount(salaryMin), maximum)
+	if profile.ExpectedSalary <= 0 {
+		item.Note = "no expected salary filled in"
+		return item
+	}
+	item.Applicable = true
+	item.CandidateValue = strconv.Itoa(profile.ExpectedSalary)
+
+	// 1% above the band costs 2 points, 50% above scores nothing
+	item.Score = 100
+	if expected := int64(profile.ExpectedSalary); expected > maximum {
+		item.Score = math.Max(0, 100-float64(expected-maximum)/float64(maximum)*200)
+		item.Note = "above the salary band"
+	}
+	return item
+}
+
+func sharesWord(a, b string) bool {
+	words := map[string]bool{}
+	for _, word := range matchWordSeparator.Split(a, -1) {
+		if len(word) >= 4 {
+			words[word] = true
+		}
+	}
+	for _, word := range matchWordSeparator.Split(b, -1) {
+		if words[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func parseLeadingNumber(value string) int {
+	number, err := strconv.Atoi(matchLeadingNumber.FindString(value))
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// parseAmount reads amounts such as "5000000", "5.000.000" or "Rp 5,000,000.00"
+func parseAmount(value string) int64 {
+	value = matchAmountCents.ReplaceAllString(strings.TrimSpace(value), "")
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
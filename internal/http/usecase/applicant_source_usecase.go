@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var ErrApplicantSourceApplicantNotFound = errors.New("applicant not found")
+
+// default channels of the utm_medium and utm_source values, applicant_sources.mediums and
+// applicant_sources.sources replace them
+var (
+	defaultApplicantSourceMediums = map[string]string{
+		"job_board": string(entity.APPLICANT_SOURCE_CHANNEL_JOB_BOARD),
+		"jobboard":  string(entity.APPLICANT_SOURCE_CHANNEL_JOB_BOARD),
+		"campus":    string(entity.APPLICANT_SOURCE_CHANNEL_CAMPUS),
+		"event":     string(entity.APPLICANT_SOURCE_CHANNEL_CAMPUS),
+		"social":    string(entity.APPLICANT_SOURCE_CHANNEL_SOCIAL_MEDIA),
+		"referral":  string(entity.APPLICANT_SOURCE_CHANNEL_REFERRAL),
+	}
+	defaultApplicantSourceSources = map[string]string{
+		"jobstreet": string(entity.APPLICANT_SOURCE_CHANNEL_JOB_BOARD),
+		"glints":    string(entity.APPLICANT_SOURCE_CHANNEL_JOB_BOARD),
+		"kalibrr":   string(entity.APPLICANT_SOURCE_CHANNEL_JOB_BOARD),
+		"indeed":    string(entity.APPLICANT_SOURCE_CHANNEL_JOB_BOARD),
+		"linkedin":  string(entity.APPLICANT_SOURCE_CHANNEL_JOB_BOARD),
+		"instagram": string(entity.APPLICANT_SOURCE_CHANNEL_SOCIAL_MEDIA),
+		"facebook":  string(entity.APPLICANT_SOURCE_CHANNEL_SOCIAL_MEDIA),
+		"tiktok":    string(entity.APPLICANT_SOURCE_CHANNEL_SOCIAL_MEDIA),
+	}
+)
+
+type IApplicantSourceUseCase interface {
+	SetApplicantSource(applicantID uuid.UUID, req *request.SetApplicantSourceRequest, setBy uuid.UUID, orgScope *repository.OrganizationScope) (*response.ApplicantSourceResponse, error)
+	GetSourceReport(req *request.ApplicantSourceReportRequest, orgScope *repository.OrganizationScope) (*response.ApplicantSourceReportResponse, error)
+}
+
+type ApplicantSourceUseCase struct {
+	Log                 *logrus.Logger
+	Viper               *viper.Viper
+	Repository          repository.IApplicantSourceRepository
+	DTO                 dto.IApplicantSourceDTO
+	ApplicantRepository repository.IApplicantRepository
+}
+
+func NewApplicantSourceUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IApplicantSourceRepository,
+	sourceDTO dto.IApplicantSourceDTO,
+	applicantRepository repository.IApplicantRepository,
+) IApplicantSourceUseCase {
+	return &ApplicantSourceUseCase{
+		Log:                 log,
+		Viper:               viper,
+		Repository:          repo,
+		DTO:                 sourceDTO,
+		ApplicantRepository: applicantRepository,
+	}
+}
+
+func ApplicantSourceUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IApplicantSourceUseCase {
+	repo := repository.ApplicantSourceRepositoryFactory(log)
+	sourceDTO := dto.ApplicantSourceDTOFactory(log)
+	applicantRepository := repository.ApplicantRepositoryFactory(log)
+	return NewApplicantSourceUseCase(log, viper, repo, sourceDTO, applicantRepository)
+}
+
+// applicantSourceChannel tells the channel of an application: a referral code makes it a
+// referral, then utm_medium and utm_source are looked up, an application without any of them
+// came straight from the careers site
+func applicantSourceChannel(viper *viper.Viper, req *request.ApplyJobPostingSourceRequest) entity.ApplicantSourceChannel {
+	if req.ReferralCode != "" {
+		return entity.APPLICANT_SOURCE_CHANNEL_REFERRAL
+	}
+	if req.UtmSource == "" && req.UtmMedium == "" && req.UtmCampaign == "" {
+		return entity.APPLICANT_SOURCE_CHANNEL_CAREERS_SITE
+	}
+
+	lookups := []struct {
+		Value    string
+		Key      string
+		Defaults map[string]string
+	}{
+		{req.UtmMedium, "applicant_sources.mediums", defaultApplicantSourceMediums},
+		{req.UtmSource, "applicant_sources.sources", defaultApplicantSourceSources},
+	}
+	for _, lookup := range lookups {
+		if lookup.Value == "" {
+			continue
+		}
+		channels := viper.GetStringMapString(lookup.Key)
+		if len(channels) == 0 {
+			channels = lookup.Defaults
+		}
+		if channel, ok := channels[strings.ToLower(lookup.Value)]; ok {
+			return entity.ApplicantSourceChannel(strings.ToUpper(channel))
+		}
+	}
+
+	return entity.APPLICANT_SOURCE_CHANNEL_OTHER
+}
+
+// newApplicantSource is the source of an application made through ApplyJobPosting
+func newApplicantSource(viper *viper.Viper, applicantID, jobPostingID uuid.UUID, req *request.ApplyJobPostingSourceRequest) *entity.ApplicantSource {
+	if req == nil {
+		req = &request.ApplyJobPostingSourceRequest{}
+	}
+	return &entity.ApplicantSource{
+		ApplicantID:  applicantID,
+		JobPostingID: jobPostingID,
+		Channel:      applicantSourceChannel(viper, req),
+		UtmSource:    strings.TrimSpace(req.UtmSource),
+		UtmMedium:    strings.TrimSpace(req.UtmMedium),
+		UtmCampaign:  strings.TrimSpace(req.UtmCampaign),
+		ReferralCode: strings.TrimSpace(req.ReferralCode),
+	}
+}
+
+// SetApplicantSource sets the source of a candidate added by HR, or corrects the captured one
+func (uc *ApplicantSourceUseCase) SetApplicantSource(applicantID uuid.UUID, req *request.SetApplicantSourceRequest, setBy uuid.UUID, orgScope *repository.OrganizationScope) (*response.ApplicantSourceResponse, error) {
+	applicant, err := uc.ApplicantRepository.FindByKeys(map[string]interface{}{"id": applicantID})
+	if err != nil {
+		uc.Log.Error("[ApplicantSourceUseCase.SetApplicantSource] " + err.Error())
+		return nil, err
+	}
+	if applicant == nil || applicant.JobPosting == nil || !orgScope.Allows(applicant.JobPosting.ForOrganizationID) {
+		return nil, ErrApplicantSourceApplicantNotFound
+	}
+
+	source, err := uc.Repository.SaveApplicantSource(&entity.ApplicantSource{
+		ApplicantID:  applicant.ID,
+		JobPostingID: applicant.JobPostingID,
+		Channel:      entity.ApplicantSourceChannel(req.Channel),
+		UtmSource:    strings.TrimSpace(req.UtmSource),
+		UtmMedium:    strings.TrimSpace(req.UtmMedium),
+		UtmCampaign:  strings.TrimSpace(req.UtmCampaign),
+		ReferralCode: strings.TrimSpace(req.ReferralCode),
+		Note:         req.Note,
+		SetBy:        &setBy,
+	})
+	if err != nil {
+		uc.Log.Error("[ApplicantSourceUseCase.SetApplicantSource] " + err.Error())
+		return nil, err
+	}
+
+	return uc.DTO.ConvertEntityToResponse(source), nil
+}
+
+// GetSourceReport counts the applications, conversions and hires per source, overall and per
+// job posting, for the job postings matching the dashboard filter
+func (uc *ApplicantSourceUseCase) GetSourceReport(req *request.ApplicantSourceReportRequest, orgScope *repository.OrganizationScope) (*response.ApplicantSourceReportResponse, error) {
+	filter, err := dashboardFilter(&req.DashboardFilterRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobPostingID *uuid.UUID
+	if req.JobPostingID != "" {
+		id, err := uuid.Parse(req.JobPostingID)
+		if err != nil {
+			return nil, err
+		}
+		jobPostingID = &id
+	}
+
+	counts, err := uc.Repository.CountBySource(filter, jobPostingID, orgScope)
+	if err != nil {
+		uc.Log.Error("[ApplicantSourceUseCase.GetSourceReport] " + err.Error())
+		return nil, err
+	}
+
+	res := &response.ApplicantSourceReportResponse{
+		Totals:      []response.ApplicantSourceStatsResponse{},
+		JobPostings: []response.ApplicantSourceJobPostingStatsResponse{},
+	}
+	totals := make(map[string]*response.ApplicantSourceStatsResponse)
+	var totalKeys []string
+	jobPostings := make(map[uuid.UUID]int)
+	for _, count := range counts {
+		i, ok := jobPostings[count.JobPostingID]
+		if !ok {
+			i = len(res.JobPostings)
+			jobPostings[count.JobPostingID] = i
+			res.JobPostings = append(res.JobPostings, response.ApplicantSourceJobPostingStatsResponse{
+				JobPostingID:   count.JobPostingID,
+				JobPostingName: count.JobPostingName,
+				Sources:        []response.ApplicantSourceStatsResponse{},
+			})
+		}
+		res.JobPostings[i].Sources = append(res.JobPostings[i].Sources, applicantSourceStats(count.Channel, count.UtmSource, count.Applications, count.Conversions, count.Hires))
+
+		key := count.Channel + "\x00" + count.UtmSource
+		total, ok := totals[key]
+		if !ok {
+			total = &response.ApplicantSourceStatsResponse{Channel: count.Channel, UtmSource: count.UtmSource}
+			totals[key] = total
+			totalKeys = append(totalKeys, key)
+		}
+		total.Applications += count.Applications
+		total.Conversions += count.Conversions
+		total.Hires += count.Hires
+	}
+	for _, key := range totalKeys {
+		total := totals[key]
+		res.Totals = append(res.Totals, applicantSourceStats(total.Channel, total.UtmSource, total.Applications, total.Conversions, total.Hires))
+	}
+	// the sources with the most applications first
+	sort.SliceStable(res.Totals, func(i, j int) bool {
+		return res.Totals[i].Applications > res.Totals[j].Applications
+	})
+
+	return res, nil
+}
+
+func applicantSourceStats(channel, utmSource string, applications, conversions, hires int) response.ApplicantSourceStatsResponse {
+	return response.ApplicantSourceStatsResponse{
+		Channel:        channel,
+		UtmSource:      utmSource,
+		Applications:   applications,
+		Conversions:    conversions,
+		ConversionRate: funnelRate(conversions, applications),
+		Hires:          hires,
+		HireRate:       funnelRate(hires, applications),
+	}
+}
@@ -2,10 +2,12 @@ package usecase
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
 	"github.com/google/uuid"
@@ -14,9 +16,9 @@ import (
 )
 
 type IApplicantUseCase interface {
-	ApplyJobPosting(applicantID, jobPostingID uuid.UUID) (*response.ApplicantResponse, error)
-	GetApplicantsByJobPostingID(jobPostingID uuid.UUID, order string, total int, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.ApplicantResponse, int64, error)
-	GetApplicantsByJobPostingIDForExport(jobPostingID uuid.UUID) (*[]response.ApplicantResponse, error)
+	ApplyJobPosting(applicantID, jobPostingID uuid.UUID, source *request.ApplyJobPostingSourceRequest) (*response.ApplicantResponse, error)
+	GetApplicantsByJobPostingID(jobPostingID uuid.UUID, order string, total int, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.ApplicantResponse, int64, error)
+	GetApplicantsByJobPostingIDForExport(jobPostingID uuid.UUID, orgScope *repository.OrganizationScope) (*[]response.ApplicantResponse, error)
 	FindApplicantByJobPostingIDAndUserID(jobPostingID, userID uuid.UUID) (*response.ApplicantResponse, error)
 	FindByID(id uuid.UUID) (*entity.Applicant, error)
 	GetApplicantsForCoverLetter(jobPostingID, projectRecruitmentLineID uuid.UUID) (*[]response.ApplicantResponse, error)
@@ -35,6 +37,8 @@ type ApplicantUseCase struct {
 	ProjectRecruitmentLineRepository  repository.IProjectRecruitmentLineRepository
 	InterviewApplicantRepository      repository.IInterviewApplicantRepository
 	DocumentSendingRepository         repository.IDocumentSendingRepository
+	ApplicantSourceRepository         repository.IApplicantSourceRepository
+	ReferralRepository                repository.IReferralRepository
 }
 
 func NewApplicantUseCase(
@@ -50,6 +54,8 @@ func NewApplicantUseCase(
 	prlRepo repository.IProjectRecruitmentLineRepository,
 	iaRepo repository.IInterviewApplicantRepository,
 	documentSendingRepo repository.IDocumentSendingRepository,
+	applicantSourceRepo repository.IApplicantSourceRepository,
+	referralRepo repository.IReferralRepository,
 ) IApplicantUseCase {
 	return &ApplicantUseCase{
 		Log:                               log,
@@ -64,6 +70,8 @@ func NewApplicantUseCase(
 		ProjectRecruitmentLineRepository:  prlRepo,
 		InterviewApplicantRepository:      iaRepo,
 		DocumentSendingRepository:         documentSendingRepo,
+		ApplicantSourceRepository:         applicantSourceRepo,
+		ReferralRepository:                referralRepo,
 	}
 }
 
@@ -78,10 +86,12 @@ func ApplicantUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IApplicantU
 	prlRepo := repository.ProjectRecruitmentLineRepositoryFactory(log)
 	iaRepo := repository.InterviewApplicantRepositoryFactory(log)
 	documentSendingRepo := repository.DocumentSendingRepositoryFactory(log)
-	return NewApplicantUseCase(log, repo, applicantDTO, viper, jpRepo, upRepo, asRepo, arRepo, taRepo, prlRepo, iaRepo, documentSendingRepo)
+	applicantSourceRepo := repository.ApplicantSourceRepositoryFactory(log)
+	referralRepo := repository.ReferralRepositoryFactory(log)
+	return NewApplicantUseCase(log, repo, applicantDTO, viper, jpRepo, upRepo, asRepo, arRepo, taRepo, prlRepo, iaRepo, documentSendingRepo, applicantSourceRepo, referralRepo)
 }
 
-func (uc *ApplicantUseCase) ApplyJobPosting(applicantID, jobPostingID uuid.UUID) (*response.ApplicantResponse, error) {
+func (uc *ApplicantUseCase) ApplyJobPosting(applicantID, jobPostingID uuid.UUID, source *request.ApplyJobPostingSourceRequest) (*response.ApplicantResponse, error) {
 	jpExist, err := uc.JobPostingRepository.FindByID(jobPostingID)
 	if err != nil {
 		uc.Log.Error("[ApplicantUseCase.ApplyJobPosting] " + err.Error())
@@ -194,6 +204,28 @@ func (uc *ApplicantUseCase) ApplyJobPosting(applicantID, jobPostingID uuid.UUID)
 		return nil, err
 	}
 
+	// a referred candidate is credited to the referrer even without following the referral link
+	var referralCode string
+	if source != nil {
+		referralCode = strings.TrimSpace(source.ReferralCode)
+	}
+	if code, err := linkReferral(uc.ReferralRepository, applicant, upExist, referralCode); err != nil {
+		uc.Log.Error("[ApplicantUseCase.ApplyJobPosting] " + err.Error())
+	} else if code != referralCode {
+		referred := request.ApplyJobPostingSourceRequest{}
+		if source != nil {
+			referred = *source
+		}
+		referred.ReferralCode = code
+		source = &referred
+	}
+
+	// the application stands without its source, HR can still set it afterwards
+	applicant.Source, err = uc.ApplicantSourceRepository.SaveApplicantSource(newApplicantSource(uc.Viper, applicant.ID, jobPostingID, source))
+	if err != nil {
+		uc.Log.Error("[ApplicantUseCase.ApplyJobPosting] " + err.Error())
+	}
+
 	applicantResponse, err := uc.DTO.ConvertEntityToResponse(applicant)
 	if err != nil {
 		uc.Log.Error("[ApplicantUseCase.ApplyJobPosting] " + err.Error())
@@ -203,14 +235,14 @@ func (uc *ApplicantUseCase) ApplyJobPosting(applicantID, jobPostingID uuid.UUID)
 	return applicantResponse, nil
 }
 
-func (uc *ApplicantUseCase) GetApplicantsByJobPostingID(jobPostingID uuid.UUID, order string, total int, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.ApplicantResponse, int64, error) {
+func (uc *ApplicantUseCase) GetApplicantsByJobPostingID(jobPostingID uuid.UUID, order string, total int, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.ApplicantResponse, int64, error) {
 	// find job posting
 	jobPosting, err := uc.JobPostingRepository.FindByID(jobPostingID)
 	if err != nil {
 		uc.Log.Error("[ApplicantUseCase.GetApplicantsByJobPostingID] " + err.Error())
 		return nil, 0, err
 	}
-	if jobPosting == nil {
+	if jobPosting == nil || !orgScope.Allows(jobPosting.ForOrganizationID) {
 		uc.Log.Error("[ApplicantUseCase.GetApplicantsByJobPostingID] " + "Job Posting not found")
 		return nil, 0, errors.New("job posting not found")
 	}
@@ -221,7 +253,7 @@ func (uc *ApplicantUseCase) GetApplicantsByJobPostingID(jobPostingID uuid.UUID,
 	if order == "" {
 		applicants, totalData, err = uc.Repository.GetAllByKeysPaginated(map[string]interface{}{
 			"job_posting_id": jobPostingID,
-		}, page, pageSize, search, sort, filter)
+		}, page, pageSize, search, sort, filter, orgScope)
 		if err != nil {
 			uc.Log.Error("[ApplicantUseCase.GetApplicantsByJobPostingID] " + err.Error())
 			return nil, 0, err
@@ -230,7 +262,7 @@ func (uc *ApplicantUseCase) GetApplicantsByJobPostingID(jobPostingID uuid.UUID,
 		applicants, totalData, err = uc.Repository.GetAllByKeysPaginated(map[string]interface{}{
 			"job_posting_id": jobPostingID,
 			"order":          order,
-		}, page, pageSize, search, sort, filter)
+		}, page, pageSize, search, sort, filter, orgScope)
 		if err != nil {
 			uc.Log.Error("[ApplicantUseCase.GetApplicantsByJobPostingID] " + err.Error())
 			return nil, 0, err
@@ -411,14 +443,14 @@ func (uc *ApplicantUseCase) FindByID(id uuid.UUID) (*entity.Applicant, error) {
 	return applicant, nil
 }
 
-func (uc *ApplicantUseCase) GetApplicantsByJobPostingIDForExport(jobPostingID uuid.UUID) (*[]response.ApplicantResponse, error) {
+func (uc *ApplicantUseCase) GetApplicantsByJobPostingIDForExport(jobPostingID uuid.UUID, orgScope *repository.OrganizationScope) (*[]response.ApplicantResponse, error) {
 	// find job posting
 	jobPosting, err := uc.JobPostingRepository.FindByID(jobPostingID)
 	if err != nil {
 		uc.Log.Error("[ApplicantUseCase.GetApplicantsByJobPostingIDForExport] " + err.Error())
 		return nil, err
 	}
-	if jobPosting == nil {
+	if jobPosting == nil || !orgScope.Allows(jobPosting.ForOrganizationID) {
 		uc.Log.Error("[ApplicantUseCase.GetApplicantsByJobPostingIDForExport] " + "Job Posting not found")
 		return nil, errors.New("job posting not found")
 	}
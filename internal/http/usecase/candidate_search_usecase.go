@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// words recruiters type around the terms that matter, "D3 akuntansi with SAP experience in Medan"
+// has to find candidates that never wrote "with", "experience" or "in" anywhere
+var defaultCandidateSearchStopWords = []string{
+	"a", "an", "and", "at", "for", "from", "in", "of", "on", "the", "to", "with",
+	"experience", "experienced", "candidate", "candidates",
+	"dan", "dari", "dengan", "di", "ke", "untuk", "yang",
+	"pengalaman", "berpengalaman", "kandidat",
+}
+
+// ICandidateSearchUseCase searches candidates over their profile, educations, skills, work experiences and CV
+type ICandidateSearchUseCase interface {
+	RegisterJobs(scheduler ISchedulerUseCase) error
+	RefreshIndex(ctx context.Context) (string, error)
+	Search(page, pageSize int, search string, filter map[string]interface{}) (*response.CandidateSearchResponse, error)
+}
+
+type CandidateSearchUseCase struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Repository repository.ICandidateSearchRepository
+	DTO        dto.ICandidateSearchDTO
+}
+
+func NewCandidateSearchUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.ICandidateSearchRepository,
+	csDTO dto.ICandidateSearchDTO,
+) ICandidateSearchUseCase {
+	return &CandidateSearchUseCase{
+		Log:        log,
+		Viper:      viper,
+		Repository: repo,
+		DTO:        csDTO,
+	}
+}
+
+func CandidateSearchUseCaseFactory(log *logrus.Logger, viper *viper.Viper) ICandidateSearchUseCase {
+	repo := repository.CandidateSearchRepositoryFactory(log)
+	csDTO := dto.CandidateSearchDTOFactory(log, viper)
+	return NewCandidateSearchUseCase(log, viper, repo, csDTO)
+}
+
+func (uc *CandidateSearchUseCase) RegisterJobs(scheduler ISchedulerUseCase) error {
+	if err := scheduler.RegisterJob("refresh_candidate_search", "* * * * *", uc.RefreshIndex); err != nil {
+		uc.Log.Error("[CandidateSearchUseCase.RegisterJobs] " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// RefreshIndex rebuilds the documents of new and changed profiles and drops the ones of deleted
+// profiles. Changes are found from the updated_at columns, so every way a profile is written
+// (the candidate, the back office, the Midsuit sync) ends up in the index.
+func (uc *CandidateSearchUseCase) RefreshIndex(ctx context.Context) (string, error) {
+	batchSize := uc.Viper.GetInt("candidate_search.batch_size")
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	var refreshed int64
+	for ctx.Err() == nil {
+		ids, err := uc.Repository.FindStaleUserProfileIDs(batchSize)
+		if err != nil {
+			uc.Log.Error("[CandidateSearchUseCase.RefreshIndex] " + err.Error())
+			return "", err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		count, err := uc.Repository.RefreshDocuments(ids)
+		if err != nil {
+			uc.Log.Error("[CandidateSearchUseCase.RefreshIndex] " + err.Error())
+			return "", err
+		}
+		refreshed += count
+
+		if len(ids) < batchSize {
+			break
+		}
+	}
+
+	deleted, err := uc.Repository.DeleteOrphanDocuments()
+	if err != nil {
+		uc.Log.Error("[CandidateSearchUseCase.RefreshIndex] " + err.Error())
+		return "", err
+	}
+
+	return fmt.Sprintf("refreshed %d, deleted %d candidate search documents", refreshed, deleted), nil
+}
+
+func (uc *CandidateSearchUseCase) Search(page, pageSize int, search string, filter map[string]interface{}) (*response.CandidateSearchResponse, error) {
+	search = uc.searchTerms(search)
+
+	documents, total, err := uc.Repository.Search(page, pageSize, search, filter)
+	if err != nil {
+		uc.Log.Error("[CandidateSearchUseCase.Search] " + err.Error())
+		return nil, err
+	}
+
+	facetCounts, err := uc.Repository.FindFacetCounts(search, filter)
+	if err != nil {
+		uc.Log.Error("[CandidateSearchUseCase.Search] " + err.Error())
+		return nil, err
+	}
+
+	res := &response.CandidateSearchResponse{
+		Candidates: []response.CandidateSearchResultResponse{},
+		Total:      total,
+		Facets:     map[string][]response.CandidateSearchFacetResponse{},
+	}
+	for _, document := range *documents {
+		candidate, err := uc.DTO.ConvertEntityToResponse(&document)
+		if err != nil {
+			uc.Log.Error("[CandidateSearchUseCase.Search] " + err.Error())
+			return nil, err
+		}
+		res.Candidates = append(res.Candidates, *candidate)
+	}
+	for name, counts := range facetCounts {
+		res.Facets[name] = []response.CandidateSearchFacetResponse{}
+		for _, count := range counts {
+			res.Facets[name] = append(res.Facets[name], response.CandidateSearchFacetResponse{
+				Value: count.Value,
+				Total: count.Total,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// searchTerms drops the stop words outside of quoted phrases, the rest keeps the web search
+// syntax: "quoted phrases", or, and -excluded words
+func (uc *CandidateSearchUseCase) searchTerms(search string) string {
+	stopWords := uc.Viper.GetStringSlice("candidate_search.stop_words")
+	if !uc.Viper.IsSet("candidate_search.stop_words") {
+		stopWords = defaultCandidateSearchStopWords
+	}
+	isStopWord := map[string]bool{}
+	for _, word := range stopWords {
+		isStopWord[strings.ToLower(word)] = true
+	}
+
+	var terms []string
+	inPhrase := false
+	for _, word := range strings.Fields(search) {
+		quotes := strings.Count(word, `"`)
+		bare := strings.ToLower(strings.Trim(word, `"-.,;:!?()`))
+		if inPhrase || quotes > 0 || !isStopWord[bare] {
+			terms = append(terms, word)
+		}
+		if quotes%2 == 1 {
+			inPhrase = !inPhrase
+		}
+	}
+
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,395 @@
+package usecase
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ICareerFeedUseCase interface {
+	GetRssFeed() (*response.RssFeedResponse, error)
+	GetAtomFeed() (*response.AtomFeedResponse, error)
+	GetSitemap() (*response.SitemapResponse, error)
+	FindPublishedBySlug(slug string) (*response.PublicJobPostingResponse, error)
+}
+
+type CareerFeedUseCase struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Repository repository.IJobPostingRepository
+	DTO        dto.IJobPostingDTO
+}
+
+func NewCareerFeedUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IJobPostingRepository,
+	jpDTO dto.IJobPostingDTO,
+) ICareerFeedUseCase {
+	return &CareerFeedUseCase{
+		Log:        log,
+		Viper:      viper,
+		Repository: repo,
+		DTO:        jpDTO,
+	}
+}
+
+func CareerFeedUseCaseFactory(log *logrus.Logger, viper *viper.Viper) ICareerFeedUseCase {
+	repo := repository.JobPostingRepositoryFactory(log)
+	jpDTO := dto.JobPostingDTOFactory(log, viper)
+	return NewCareerFeedUseCase(log, viper, repo, jpDTO)
+}
+
+type publishedJobPosting struct {
+	Entity   entity.JobPosting
+	Response response.JobPostingResponse
+}
+
+// publishedJobPostings is shared by every instance of the use case, crawlers hit the feeds often
+// and every posting needs organization, location and job lookups. Kept for careers.cache_ttl seconds.
+var publishedJobPostings = struct {
+	mu       sync.Mutex
+	items    []publishedJobPosting
+	loadedAt time.Time
+}{}
+
+func (uc *CareerFeedUseCase) published() ([]publishedJobPosting, error) {
+	ttl := uc.Viper.GetInt("careers.cache_ttl")
+	if !uc.Viper.IsSet("careers.cache_ttl") {
+		ttl = 300
+	}
+
+	publishedJobPostings.mu.Lock()
+	defer publishedJobPostings.mu.Unlock()
+
+	if publishedJobPostings.items != nil && time.Since(publishedJobPostings.loadedAt) < time.Duration(ttl)*time.Second {
+		return publishedJobPostings.items, nil
+	}
+
+	jobPostings, err := uc.Repository.FindAllPublished(time.Now().In(uc.location()))
+	if err != nil {
+		uc.Log.Error("[CareerFeedUseCase.published] " + err.Error())
+		return nil, err
+	}
+
+	// a posting without a slug has no public link, it is left out rather than failing the feed
+	withSlug := make([]entity.JobPosting, 0, len(*jobPostings))
+	for i := range *jobPostings {
+		if err := uc.ensureSlug(&(*jobPostings)[i]); err != nil {
+			uc.Log.Errorf("[CareerFeedUseCase.published] skipping job posting %s: %v", (*jobPostings)[i].ID, err)
+			continue
+		}
+		withSlug = append(withSlug, (*jobPostings)[i])
+	}
+
+	responses := uc.DTO.ConvertEntitiesToResponses(withSlug)
+	items := make([]publishedJobPosting, 0, len(responses))
+	for i := range responses {
+		items = append(items, publishedJobPosting{Entity: withSlug[i], Response: responses[i]})
+	}
+
+	publishedJobPostings.items = items
+	publishedJobPostings.loadedAt = time.Now()
+	return items, nil
+}
+
+// ensureSlug gives postings the migration has not reached yet one
+func (uc *CareerFeedUseCase) ensureSlug(ent *entity.JobPosting) error {
+	if ent.Slug != "" {
+		return nil
+	}
+	return assignJobPostingSlug(uc.Repository, ent)
+}
+
+func (uc *CareerFeedUseCase) GetRssFeed() (*response.RssFeedResponse, error) {
+	items, err := uc.published()
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &response.RssFeedResponse{
+		Version:   "2.0",
+		AtomXmlns: "http://www.w3.org/2005/Atom",
+		Channel: response.RssChannelResponse{
+			Title:         uc.title(),
+			Link:          uc.careersURL(),
+			Description:   uc.Viper.GetString("careers.description"),
+			Language:      uc.Viper.GetString("careers.language"),
+			LastBuildDate: time.Now().Format(time.RFC1123Z),
+			AtomLink: response.AtomLinkResponse{
+				Href: uc.feedURL("/careers/feed.rss"),
+				Rel:  "self",
+				Type: "application/rss+xml",
+			},
+			Items: make([]response.RssItemResponse, 0),
+		},
+	}
+
+	for _, item := range uc.limit(items) {
+		link := uc.jobURL(item.Entity.Slug)
+		feed.Channel.Items = append(feed.Channel.Items, response.RssItemResponse{
+			Title:       uc.jobTitle(item),
+			Link:        link,
+			GUID:        response.RssGUIDResponse{IsPermaLink: true, Value: link},
+			Description: item.Entity.ContentDescription,
+			Category:    item.Response.ForOrganizationName,
+			PubDate:     item.Entity.StartDate.Format(time.RFC1123Z),
+		})
+	}
+
+	return feed, nil
+}
+
+func (uc *CareerFeedUseCase) GetAtomFeed() (*response.AtomFeedResponse, error) {
+	items, err := uc.published()
+	if err != nil {
+		return nil, err
+	}
+
+	updated := time.Time{}
+	for _, item := range items {
+		if item.Entity.UpdatedAt.After(updated) {
+			updated = item.Entity.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	feed := &response.AtomFeedResponse{
+		ID:      uc.feedURL("/careers/feed.atom"),
+		Title:   uc.title(),
+		Updated: updated.Format(time.RFC3339),
+		Links: []response.AtomLinkResponse{
+			{Href: uc.feedURL("/careers/feed.atom"), Rel: "self", Type: "application/atom+xml"},
+			{Href: uc.careersURL(), Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]response.AtomEntryResponse, 0),
+	}
+
+	for _, item := range uc.limit(items) {
+		link := uc.jobURL(item.Entity.Slug)
+		entry := response.AtomEntryResponse{
+			ID:        link,
+			Title:     uc.jobTitle(item),
+			Updated:   item.Entity.UpdatedAt.Format(time.RFC3339),
+			Published: item.Entity.StartDate.Format(time.RFC3339),
+			Link:      response.AtomLinkResponse{Href: link, Rel: "alternate", Type: "text/html"},
+			Author:    response.AtomAuthorResponse{Name: item.Response.ForOrganizationName},
+			Content:   response.AtomContentResponse{Type: "html", Value: item.Entity.ContentDescription},
+		}
+		if item.Response.ForOrganizationLocation != "" {
+			entry.Category = &response.AtomCategoryResponse{Term: item.Response.ForOrganizationLocation}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed, nil
+}
+
+func (uc *CareerFeedUseCase) GetSitemap() (*response.SitemapResponse, error) {
+	items, err := uc.published()
+	if err != nil {
+		return nil, err
+	}
+
+	sitemap := &response.SitemapResponse{
+		URLs: []response.SitemapURLResponse{
+			{Loc: uc.careersURL(), ChangeFreq: "daily"},
+		},
+	}
+	for _, item := range items {
+		sitemap.URLs = append(sitemap.URLs, response.SitemapURLResponse{
+			Loc:        uc.jobURL(item.Entity.Slug),
+			LastMod:    item.Entity.UpdatedAt.Format("2006-01-02"),
+			ChangeFreq: "daily",
+		})
+	}
+
+	return sitemap, nil
+}
+
+// FindPublishedBySlug returns nil when the posting does not exist or is not open to candidates
+func (uc *CareerFeedUseCase) FindPublishedBySlug(slug string) (*response.PublicJobPostingResponse, error) {
+	jobPosting, err := uc.Repository.FindBySlug(slug)
+	if err != nil {
+		uc.Log.Error("[CareerFeedUseCase.FindPublishedBySlug] " + err.Error())
+		return nil, err
+	}
+	if jobPosting == nil || !uc.isPublished(jobPosting) {
+		return nil, nil
+	}
+
+	item := publishedJobPosting{
+		Entity:   *jobPosting,
+		Response: *uc.DTO.ConvertEntityToResponse(jobPosting),
+	}
+	return &response.PublicJobPostingResponse{
+		JobPosting: item.Response,
+		JsonLD:     uc.jobPostingSchema(item),
+	}, nil
+}
+
+func (uc *CareerFeedUseCase) isPublished(ent *entity.JobPosting) bool {
+	today := time.Now().In(uc.location()).Format("2006-01-02")
+	open := ent.Status == entity.JOB_POSTING_STATUS_APPROVED || ent.Status == entity.JOB_POSTING_STATUS_IN_PROGRESS
+	return open && ent.IsShow == "YES" && ent.StartDate.Format("2006-01-02") <= today && ent.EndDate.Format("2006-01-02") >= today
+}
+
+func (uc *CareerFeedUseCase) jobPostingSchema(item publishedJobPosting) response.JobPostingSchemaResponse {
+	end := item.Entity.EndDate
+	validThrough := time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, uc.location())
+
+	employmentType := uc.Viper.GetString("careers.employment_type")
+	if employmentType == "" {
+		employmentType = "FULL_TIME"
+	}
+	country := uc.Viper.GetString("careers.country")
+	if country == "" {
+		country = "ID"
+	}
+
+	schema := response.JobPostingSchemaResponse{
+		Context:     "https://schema.org/",
+		Type:        "JobPosting",
+		Title:       uc.jobTitle(item),
+		Description: item.Entity.ContentDescription,
+		Identifier: response.SchemaPropertyValueResponse{
+			Type:  "PropertyValue",
+			Name:  item.Response.ForOrganizationName,
+			Value: item.Entity.DocumentNumber,
+		},
+		DatePosted:     item.Entity.StartDate.Format("2006-01-02"),
+		ValidThrough:   validThrough.Format(time.RFC3339),
+		EmploymentType: employmentType,
+		HiringOrganization: response.SchemaOrganizationResponse{
+			Type: "Organization",
+			Name: item.Response.ForOrganizationName,
+		},
+		JobLocation: response.SchemaPlaceResponse{
+			Type: "Place",
+			Address: response.SchemaPostalAddressResponse{
+				Type:            "PostalAddress",
+				AddressLocality: item.Response.ForOrganizationLocation,
+				AddressCountry:  country,
+			},
+		},
+		URL: uc.jobURL(item.Entity.Slug),
+	}
+	if item.Response.OrganizationLogo != nil {
+		schema.HiringOrganization.Logo = *item.Response.OrganizationLogo
+	}
+	if item.Response.Poster != nil {
+		schema.Image = *item.Response.Poster
+	}
+
+	minSalary := parseSalary(item.Entity.SalaryMin)
+	maxSalary := parseSalary(item.Entity.SalaryMax)
+	if minSalary != nil && maxSalary != nil && *maxSalary < *minSalary {
+		maxSalary = nil
+	}
+	if minSalary != nil || maxSalary != nil {
+		currency := uc.Viper.GetString("careers.currency")
+		if currency == "" {
+			currency = "IDR"
+		}
+		schema.BaseSalary = &response.SchemaMonetaryAmountResponse{
+			Type:     "MonetaryAmount",
+			Currency: currency,
+			Value: response.SchemaQuantitativeValueResponse{
+				Type:     "QuantitativeValue",
+				MinValue: minSalary,
+				MaxValue: maxSalary,
+				UnitText: "MONTH",
+			},
+		}
+	}
+
+	return schema
+}
+
+// parseSalary reads amounts written as "5000000", "5.000.000" or "Rp 5.000.000,00",
+// it returns nil for empty or non numeric values
+func parseSalary(value string) *float64 {
+	if idx := strings.Index(value, ","); idx != -1 {
+		value = value[:idx]
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+	amount, err := strconv.ParseFloat(digits, 64)
+	if err != nil || amount <= 0 {
+		return nil
+	}
+	return &amount
+}
+
+func (uc *CareerFeedUseCase) jobTitle(item publishedJobPosting) string {
+	if item.Entity.Name != "" {
+		return item.Entity.Name
+	}
+	return item.Response.JobName
+}
+
+func (uc *CareerFeedUseCase) limit(items []publishedJobPosting) []publishedJobPosting {
+	limit := uc.Viper.GetInt("careers.feed_limit")
+	if limit <= 0 {
+		limit = 100
+	}
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+func (uc *CareerFeedUseCase) title() string {
+	if title := uc.Viper.GetString("careers.title"); title != "" {
+		return title
+	}
+	return "Careers"
+}
+
+// careersURL is the public careers site, job pages live under careers.job_path
+func (uc *CareerFeedUseCase) careersURL() string {
+	url := uc.Viper.GetString("careers.url")
+	if url == "" {
+		url = uc.Viper.GetString("app.url")
+	}
+	return strings.TrimRight(url, "/")
+}
+
+func (uc *CareerFeedUseCase) jobURL(slug string) string {
+	path := uc.Viper.GetString("careers.job_path")
+	if path == "" {
+		path = "/jobs"
+	}
+	return uc.careersURL() + "/" + strings.Trim(path, "/") + "/" + slug
+}
+
+func (uc *CareerFeedUseCase) feedURL(path string) string {
+	return strings.TrimRight(uc.Viper.GetString("app.url"), "/") + path
+}
+
+func (uc *CareerFeedUseCase) location() *time.Location {
+	name := uc.Viper.GetString("careers.timezone")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		uc.Log.Warnf("[CareerFeedUseCase.location] %v, using UTC+7", err)
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
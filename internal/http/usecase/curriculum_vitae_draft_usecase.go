@@ -0,0 +1,570 @@
+package usecase
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ICurriculumVitaeDraftUseCase interface {
+	CreateDraft(userID uuid.UUID, filePath string) (*response.CurriculumVitaeDraftResponse, error)
+	FindLatestByUserID(userID uuid.UUID) (*response.CurriculumVitaeDraftResponse, error)
+	AcceptDraft(id, userID uuid.UUID, req *request.AcceptCurriculumVitaeDraftRequest) (*response.UserProfileResponse, error)
+	RejectDraft(id, userID uuid.UUID) (*response.CurriculumVitaeDraftResponse, error)
+}
+
+type CurriculumVitaeDraftUseCase struct {
+	Log                       *logrus.Logger
+	Repository                repository.ICurriculumVitaeDraftRepository
+	DTO                       dto.ICurriculumVitaeDraftDTO
+	Parser                    helper.ICurriculumVitaeParserHelper
+	UserProfileRepository     repository.IUserProfileRepository
+	UserProfileDTO            dto.IUserProfileDTO
+	EducationRepository       repository.IEducationRepository
+	WorkExperienceRepository  repository.IWorkExperienceRepository
+	SkillRepository           repository.ISkillRepository
+	UniversityRepository      repository.IUniversityRepository
+	CandidateSearchRepository repository.ICandidateSearchRepository
+	Storage                   storage.IStorage
+	Viper                     *viper.Viper
+}
+
+func NewCurriculumVitaeDraftUseCase(
+	log *logrus.Logger,
+	repo repository.ICurriculumVitaeDraftRepository,
+	cvDTO dto.ICurriculumVitaeDraftDTO,
+	parser helper.ICurriculumVitaeParserHelper,
+	upRepository repository.IUserProfileRepository,
+	upDTO dto.IUserProfileDTO,
+	edRepository repository.IEducationRepository,
+	weRepository repository.IWorkExperienceRepository,
+	sRepository repository.ISkillRepository,
+	uRepository repository.IUniversityRepository,
+	csRepository repository.ICandidateSearchRepository,
+	fileStorage storage.IStorage,
+	viper *viper.Viper,
+) ICurriculumVitaeDraftUseCase {
+	return &CurriculumVitaeDraftUseCase{
+		Log:                       log,
+		Repository:                repo,
+		DTO:                       cvDTO,
+		Parser:                    parser,
+		UserProfileRepository:     upRepository,
+		UserProfileDTO:            upDTO,
+		EducationRepository:       edRepository,
+		WorkExperienceRepository:  weRepository,
+		SkillRepository:           sRepository,
+		UniversityRepository:      uRepository,
+		CandidateSearchRepository: csRepository,
+		Storage:                   fileStorage,
+		Viper:                     viper,
+	}
+}
+
+func CurriculumVitaeDraftUseCaseFactory(log *logrus.Logger, viper *viper.Viper) ICurriculumVitaeDraftUseCase {
+	repo := repository.CurriculumVitaeDraftRepositoryFactory(log)
+	cvDTO := dto.CurriculumVitaeDraftDTOFactory(log, viper)
+	parser := helper.CurriculumVitaeParserHelperFactory(log)
+	upRepository := repository.UserProfileRepositoryFactory(log)
+	upDTO := dto.UserProfileDTOFactory(log, viper)
+	edRepository := repository.EducationRepositoryFactory(log)
+	weRepository := repository.WorkExperienceRepositoryFactory(log)
+	sRepository := repository.SkillRepositoryFactory(log)
+	uRepository := repository.UniversityRepositoryFactory(log)
+	csRepository := repository.CandidateSearchRepositoryFactory(log)
+	fileStorage := storage.StorageFactory(viper, log)
+	return NewCurriculumVitaeDraftUseCase(log, repo, cvDTO, parser, upRepository, upDTO, edRepository, weRepository, sRepository, uRepository, csRepository, fileStorage, viper)
+}
+
+// CreateDraft reads the CV at filePath, or the CV already on the profile when filePath is
+// empty, into a draft the candidate can review
+func (uc *CurriculumVitaeDraftUseCase) CreateDraft(userID uuid.UUID, filePath string) (*response.CurriculumVitaeDraftResponse, error) {
+	profile, err := uc.UserProfileRepository.FindByUserID(userID)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.CreateDraft] error when finding user profile: %v", err)
+		return nil, err
+	}
+	if filePath == "" {
+		if profile == nil || profile.CurriculumVitae == "" {
+			return nil, errors.New("no curriculum vitae uploaded")
+		}
+		filePath = profile.CurriculumVitae
+	}
+
+	data, err := storage.ReadFile(uc.Storage, filePath)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.CreateDraft] error when reading %s: %v", filePath, err)
+		return nil, errors.New("the curriculum vitae could not be read: " + err.Error())
+	}
+
+	maxDecodedSize := uc.Viper.GetInt64("curriculum_vitae.max_decoded_size")
+	if maxDecodedSize <= 0 {
+		maxDecodedSize = 32 << 20
+	}
+	text, err := utils.ExtractDocumentText(filePath, data, maxDecodedSize)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.CreateDraft] error when reading %s: %v", filePath, err)
+		return nil, errors.New("the curriculum vitae could not be read: " + err.Error())
+	}
+	if text == "" {
+		return nil, errors.New("no text found in the curriculum vitae, scanned documents cannot be read")
+	}
+
+	draft := uc.Parser.Parse(text)
+	draft.UserID = userID
+	draft.FilePath = filePath
+	draft.Status = entity.CURRICULUM_VITAE_DRAFT_STATUS_PENDING
+	if profile != nil {
+		draft.UserProfileID = &profile.ID
+	}
+	uc.matchUniversities(draft)
+
+	created, err := uc.Repository.CreateDraft(draft)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.CreateDraft] error when creating draft: %v", err)
+		return nil, err
+	}
+
+	if profile != nil {
+		if err := uc.CandidateSearchRepository.SaveCvText(profile.ID, text); err != nil {
+			uc.Log.Errorf("[CurriculumVitaeDraftUseCase.CreateDraft] error when saving cv text: %v", err)
+		}
+	}
+
+	return uc.DTO.ConvertEntityToResponse(created), nil
+}
+
+func (uc *CurriculumVitaeDraftUseCase) FindLatestByUserID(userID uuid.UUID) (*response.CurriculumVitaeDraftResponse, error) {
+	draft, err := uc.Repository.FindLatestByUserID(userID)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.FindLatestByUserID] error when finding draft: %v", err)
+		return nil, err
+	}
+	if draft == nil {
+		return nil, nil
+	}
+
+	return uc.DTO.ConvertEntityToResponse(draft), nil
+}
+
+// AcceptDraft applies the corrections of the candidate to the draft and merges it into the
+// profile. Fields already filled on the profile are kept, and entries the profile already has
+// or that miss what the profile requires are skipped.
+func (uc *CurriculumVitaeDraftUseCase) AcceptDraft(id, userID uuid.UUID, req *request.AcceptCurriculumVitaeDraftRequest) (*response.UserProfileResponse, error) {
+	draft, err := uc.findPendingDraft(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyDraftCorrections(draft, req); err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.UserProfileRepository.FindByUserID(userID)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.AcceptDraft] error when finding user profile: %v", err)
+		return nil, err
+	}
+	if profile == nil {
+		profile, err = uc.UserProfileRepository.CreateUserProfile(&entity.UserProfile{
+			UserID:          &userID,
+			Name:            draft.Name,
+			Status:          entity.USER_INACTIVE,
+			PhoneNumber:     draft.PhoneNumber,
+			Gender:          draft.Gender,
+			BirthPlace:      draft.BirthPlace,
+			BirthDate:       draftBirthDate(draft),
+			Age:             draftAge(draft),
+			Address:         draft.Address,
+			CurriculumVitae: draft.FilePath,
+		})
+		if err != nil {
+			uc.Log.Errorf("[CurriculumVitaeDraftUseCase.AcceptDraft] error when creating user profile: %v", err)
+			return nil, err
+		}
+	} else if update, changed := draftProfileUpdate(profile, draft); changed {
+		if _, err := uc.UserProfileRepository.UpdateUserProfile(update); err != nil {
+			uc.Log.Errorf("[CurriculumVitaeDraftUseCase.AcceptDraft] error when updating user profile: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := uc.mergeEntries(profile, draft); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	draft.Status = entity.CURRICULUM_VITAE_DRAFT_STATUS_ACCEPTED
+	draft.AcceptedAt = &now
+	draft.UserProfileID = &profile.ID
+	if _, err := uc.Repository.UpdateDraft(draft); err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.AcceptDraft] error when updating draft: %v", err)
+		return nil, err
+	}
+
+	if err := uc.CandidateSearchRepository.SaveCvText(profile.ID, draft.RawText); err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.AcceptDraft] error when saving cv text: %v", err)
+	}
+
+	merged, err := uc.UserProfileRepository.FindByID(profile.ID)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.AcceptDraft] error when finding user profile: %v", err)
+		return nil, err
+	}
+
+	return uc.UserProfileDTO.ConvertEntityToResponse(merged)
+}
+
+func (uc *CurriculumVitaeDraftUseCase) RejectDraft(id, userID uuid.UUID) (*response.CurriculumVitaeDraftResponse, error) {
+	draft, err := uc.findPendingDraft(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.Status = entity.CURRICULUM_VITAE_DRAFT_STATUS_REJECTED
+	updated, err := uc.Repository.UpdateDraft(draft)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.RejectDraft] error when updating draft: %v", err)
+		return nil, err
+	}
+
+	return uc.DTO.ConvertEntityToResponse(updated), nil
+}
+
+func (uc *CurriculumVitaeDraftUseCase) findPendingDraft(id, userID uuid.UUID) (*entity.CurriculumVitaeDraft, error) {
+	draft, err := uc.Repository.FindByID(id)
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.findPendingDraft] error when finding draft: %v", err)
+		return nil, err
+	}
+	// the draft of another candidate is reported the same way as a missing one
+	if draft == nil || draft.UserID != userID {
+		return nil, errors.New("curriculum vitae draft not found")
+	}
+	if draft.Status != entity.CURRICULUM_VITAE_DRAFT_STATUS_PENDING {
+		return nil, errors.New("curriculum vitae draft is already " + strings.ToLower(string(draft.Status)))
+	}
+
+	return draft, nil
+}
+
+func (uc *CurriculumVitaeDraftUseCase) mergeEntries(profile *entity.UserProfile, draft *entity.CurriculumVitaeDraft) error {
+	for _, education := range draft.Educations {
+		if education.EducationLevel == "" || education.SchoolName == "" || education.GraduateYear == 0 {
+			continue
+		}
+		exists := false
+		for _, current := range profile.Educations {
+			if current.EducationLevel == education.EducationLevel && normalizeSchoolName(current.SchoolName) == normalizeSchoolName(education.SchoolName) {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		gpa := education.Gpa
+		if gpa == nil {
+			gpa = new(float64)
+		}
+		if _, err := uc.EducationRepository.CreateEducation(&entity.Education{
+			UserProfileID:  profile.ID,
+			EducationLevel: education.EducationLevel,
+			Major:          education.Major,
+			SchoolName:     education.SchoolName,
+			GraduateYear:   education.GraduateYear,
+			EndDate:        time.Date(education.GraduateYear, time.December, 31, 0, 0, 0, 0, time.UTC),
+			Gpa:            gpa,
+		}); err != nil {
+			uc.Log.Errorf("[CurriculumVitaeDraftUseCase.mergeEntries] error when creating education: %v", err)
+			return err
+		}
+	}
+
+	for _, workExperience := range draft.WorkExperiences {
+		if workExperience.Name == "" || workExperience.CompanyName == "" {
+			continue
+		}
+		exists := false
+		for _, current := range profile.WorkExperiences {
+			if strings.EqualFold(current.Name, workExperience.Name) && strings.EqualFold(current.CompanyName, workExperience.CompanyName) {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		if _, err := uc.WorkExperienceRepository.CreateWorkExperience(&entity.WorkExperience{
+			UserProfileID:  profile.ID,
+			Name:           workExperience.Name,
+			CompanyName:    workExperience.CompanyName,
+			YearExperience: workExperience.YearExperience,
+			JobDescription: workExperience.JobDescription,
+		}); err != nil {
+			uc.Log.Errorf("[CurriculumVitaeDraftUseCase.mergeEntries] error when creating work experience: %v", err)
+			return err
+		}
+	}
+
+	for _, skill := range draft.Skills {
+		exists := false
+		for _, current := range profile.Skills {
+			if strings.EqualFold(current.Name, skill.Name) {
+				exists = true
+				break
+			}
+		}
+		if exists || skill.Name == "" {
+			continue
+		}
+		if _, err := uc.SkillRepository.CreateSkill(&entity.Skill{
+			UserProfileID: profile.ID,
+			Name:          skill.Name,
+			Description:   skill.Description,
+		}); err != nil {
+			uc.Log.Errorf("[CurriculumVitaeDraftUseCase.mergeEntries] error when creating skill: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchUniversities replaces the school names of higher education entries with the name of
+// the university they match, so the profile uses the names of the university list
+func (uc *CurriculumVitaeDraftUseCase) matchUniversities(draft *entity.CurriculumVitaeDraft) {
+	universities, err := uc.UniversityRepository.FindAll()
+	if err != nil {
+		uc.Log.Errorf("[CurriculumVitaeDraftUseCase.matchUniversities] error when finding universities: %v", err)
+		return
+	}
+
+	byName := make(map[string]*entity.University, len(universities))
+	for _, university := range universities {
+		byName[normalizeSchoolName(university.Name)] = university
+	}
+
+	for i := range draft.Educations {
+		education := &draft.Educations[i]
+		switch education.EducationLevel {
+		case entity.EDUCATION_LEVEL_ENUM_SMA, entity.EDUCATION_LEVEL_ENUM_SMP, entity.EDUCATION_LEVEL_ENUM_SD, entity.EDUCATION_LEVEL_ENUM_TK:
+			continue
+		}
+		name := normalizeSchoolName(education.SchoolName)
+		if name == "" {
+			continue
+		}
+
+		match := byName[name]
+		if match == nil {
+			words := strings.Fields(name)
+			best := 0.0
+			for normalized, university := range byName {
+				if similarity := wordSimilarity(words, strings.Fields(normalized)); similarity > best {
+					best, match = similarity, university
+				}
+			}
+			if best < 0.75 {
+				match = nil
+			}
+		}
+		if match != nil {
+			education.SchoolName = match.Name
+			education.UniversityID = &match.ID
+		}
+	}
+}
+
+var schoolNameReplacer = strings.NewReplacer(".", " ", ",", " ", "-", " ", "(", " ", ")", " ", "'", "")
+
+// schoolNameSynonyms lets "University of Indonesia" and "Univ. Indonesia" meet "Universitas
+// Indonesia"
+var schoolNameSynonyms = map[string]string{
+	"univ":        "universitas",
+	"university":  "universitas",
+	"institute":   "institut",
+	"polytechnic": "politeknik",
+	"academy":     "akademi",
+	"state":       "negeri",
+	"technology":  "teknologi",
+	"of":          "",
+	"the":         "",
+}
+
+var schoolNameWordPattern = regexp.MustCompile(`\s+`)
+
+func normalizeSchoolName(name string) string {
+	var words []string
+	for _, word := range schoolNameWordPattern.Split(strings.TrimSpace(schoolNameReplacer.Replace(strings.ToLower(name))), -1) {
+		if synonym, ok := schoolNameSynonyms[word]; ok {
+			word = synonym
+		}
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// wordSimilarity is the share of words two names have in common
+func wordSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, word := range a {
+		set[word] = true
+	}
+	common := 0
+	union := len(set)
+	seen := map[string]bool{}
+	for _, word := range b {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if set[word] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+func applyDraftCorrections(draft *entity.CurriculumVitaeDraft, req *request.AcceptCurriculumVitaeDraftRequest) error {
+	if req == nil {
+		return nil
+	}
+	if req.Name != nil {
+		draft.Name = *req.Name
+	}
+	if req.PhoneNumber != nil {
+		draft.PhoneNumber = *req.PhoneNumber
+	}
+	if req.Gender != nil {
+		draft.Gender = entity.UserGender(*req.Gender)
+	}
+	if req.BirthPlace != nil {
+		draft.BirthPlace = *req.BirthPlace
+	}
+	if req.BirthDate != nil {
+		birthDate, err := time.Parse("2006-01-02", *req.BirthDate)
+		if err != nil {
+			return err
+		}
+		draft.BirthDate = &birthDate
+	}
+	if req.Address != nil {
+		draft.Address = *req.Address
+	}
+
+	if req.Educations != nil {
+		draft.Educations = []entity.CurriculumVitaeDraftEducation{}
+		for _, education := range req.Educations {
+			var universityID *uuid.UUID
+			if education.UniversityID != nil {
+				parsed, err := uuid.Parse(*education.UniversityID)
+				if err != nil {
+					return err
+				}
+				universityID = &parsed
+			}
+			draft.Educations = append(draft.Educations, entity.CurriculumVitaeDraftEducation{
+				EducationLevel: entity.EducationLevelEnum(education.EducationLevel),
+				Major:          education.Major,
+				SchoolName:     education.SchoolName,
+				UniversityID:   universityID,
+				GraduateYear:   education.GraduateYear,
+				Gpa:            education.Gpa,
+			})
+		}
+	}
+	if req.WorkExperiences != nil {
+		draft.WorkExperiences = []entity.CurriculumVitaeDraftWorkExperience{}
+		for _, workExperience := range req.WorkExperiences {
+			draft.WorkExperiences = append(draft.WorkExperiences, entity.CurriculumVitaeDraftWorkExperience{
+				Name:           workExperience.Name,
+				CompanyName:    workExperience.CompanyName,
+				YearExperience: workExperience.YearExperience,
+				JobDescription: workExperience.JobDescription,
+			})
+		}
+	}
+	if req.Skills != nil {
+		draft.Skills = []entity.CurriculumVitaeDraftSkill{}
+		for _, skill := range req.Skills {
+			draft.Skills = append(draft.Skills, entity.CurriculumVitaeDraftSkill{
+				Name:        skill.Name,
+				Description: skill.Description,
+			})
+		}
+	}
+
+	return nil
+}
+
+// draftProfileUpdate returns the profile fields the draft can fill, only empty ones are
+// touched so what the candidate typed before wins over the parser
+func draftProfileUpdate(profile *entity.UserProfile, draft *entity.CurriculumVitaeDraft) (*entity.UserProfile, bool) {
+	update := &entity.UserProfile{}
+	update.ID = profile.ID
+	changed := false
+	fill := func(current string, value string, target *string) {
+		if current == "" && value != "" {
+			*target = value
+			changed = true
+		}
+	}
+
+	fill(profile.Name, draft.Name, &update.Name)
+	fill(profile.PhoneNumber, draft.PhoneNumber, &update.PhoneNumber)
+	fill(profile.BirthPlace, draft.BirthPlace, &update.BirthPlace)
+	fill(profile.Address, draft.Address, &update.Address)
+	fill(profile.CurriculumVitae, draft.FilePath, &update.CurriculumVitae)
+	if profile.Gender == "" && draft.Gender != "" {
+		update.Gender = draft.Gender
+		changed = true
+	}
+	if profile.BirthDate.IsZero() && draft.BirthDate != nil {
+		update.BirthDate = *draft.BirthDate
+		changed = true
+		if profile.Age == 0 {
+			update.Age = draftAge(draft)
+		}
+	}
+
+	return update, changed
+}
+
+func draftBirthDate(draft *entity.CurriculumVitaeDraft) time.Time {
+	if draft.BirthDate == nil {
+		return time.Time{}
+	}
+	return *draft.BirthDate
+}
+
+func draftAge(draft *entity.CurriculumVitaeDraft) int {
+	if draft.BirthDate == nil {
+		return 0
+	}
+	now := time.Now()
+	age := now.Year() - draft.BirthDate.Year()
+	if now.YearDay() < draft.BirthDate.YearDay() {
+		age--
+	}
+	return age
+}
@@ -1,72 +1,58 @@
 package usecase
 
 import (
+	"errors"
 	"math"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
-	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
-	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+var ErrDashboardInvalidPeriod = errors.New("start_date must not be after end_date")
+
+var dashboardRecruitmentTypes = map[string]entity.ProjectRecruitmentType{
+	"MT": entity.PROJECT_RECRUITMENT_TYPE_MT,
+	"PH": entity.PROJECT_RECRUITMENT_TYPE_PH,
+	"NS": entity.PROJECT_RECRUITMENT_TYPE_NS,
+}
+
 type IDashboardUseCase interface {
-	GetDashboard() (*response.DashboardResponse, error)
+	GetDashboard(req *request.DashboardFilterRequest, orgScope *repository.OrganizationScope) (*response.DashboardResponse, error)
 }
 
 type DashboardUseCase struct {
-	Log                                *logrus.Logger
-	Viper                              *viper.Viper
-	JobPostingRepository               repository.IJobPostingRepository
-	ProjectRecruitmentHeaderRepository repository.IProjectRecruitmentHeaderRepository
-	MPRequestRepository                repository.IMPRequestRepository
-	ProjectRecruitmentLineRepository   repository.IProjectRecruitmentLineRepository
-	ApplicantRepository                repository.IApplicantRepository
-	DocumentSendingRepository          repository.IDocumentSendingRepository
-	MPRequestMessage                   messaging.IMPRequestMessage
-	MPRequestService                   service.IMPRequestService
-	UserHelper                         helper.IUserHelper
-	MPRequestHelper                    helper.IMPRequestHelper
-	EmployeeMessage                    messaging.IEmployeeMessage
-	JobPlafonMessage                   messaging.IJobPlafonMessage
+	Log                 *logrus.Logger
+	Viper               *viper.Viper
+	DashboardRepository repository.IDashboardRepository
+	MPRequestMessage    messaging.IMPRequestMessage
+	JobPlafonMessage    messaging.IJobPlafonMessage
+	OrganizationMessage messaging.IOrganizationMessage
 }
 
 func NewDashboardUseCase(
 	log *logrus.Logger,
 	viper *viper.Viper,
-	jobPostingRepository repository.IJobPostingRepository,
-	projectRecruitmentHeaderRepository repository.IProjectRecruitmentHeaderRepository,
-	MPRequestRepository repository.IMPRequestRepository,
-	projectRecruitmentLineRepository repository.IProjectRecruitmentLineRepository,
-	applicantRepository repository.IApplicantRepository,
-	documentSendingRepository repository.IDocumentSendingRepository,
+	dashboardRepository repository.IDashboardRepository,
 	MPRequestMessage messaging.IMPRequestMessage,
-	MPRequestService service.IMPRequestService,
-	userHelper helper.IUserHelper,
-	MPRequestHelper helper.IMPRequestHelper,
-	employeeMessage messaging.IEmployeeMessage,
 	jobPlafonMessage messaging.IJobPlafonMessage,
+	organizationMessage messaging.IOrganizationMessage,
 ) IDashboardUseCase {
 	return &DashboardUseCase{
-		Log:                                log,
-		Viper:                              viper,
-		JobPostingRepository:               jobPostingRepository,
-		ProjectRecruitmentHeaderRepository: projectRecruitmentHeaderRepository,
-		MPRequestRepository:                MPRequestRepository,
-		ProjectRecruitmentLineRepository:   projectRecruitmentLineRepository,
-		ApplicantRepository:                applicantRepository,
-		DocumentSendingRepository:          documentSendingRepository,
-		MPRequestMessage:                   MPRequestMessage,
-		MPRequestService:                   MPRequestService,
-		UserHelper:                         userHelper,
-		MPRequestHelper:                    MPRequestHelper,
-		EmployeeMessage:                    employeeMessage,
-		JobPlafonMessage:                   jobPlafonMessage,
+		Log:                 log,
+		Viper:               viper,
+		DashboardRepository: dashboardRepository,
+		MPRequestMessage:    MPRequestMessage,
+		JobPlafonMessage:    jobPlafonMessage,
+		OrganizationMessage: organizationMessage,
 	}
 }
 
@@ -74,261 +60,319 @@ func DashboardUseCaseFactory(
 	log *logrus.Logger,
 	viper *viper.Viper,
 ) IDashboardUseCase {
-	jobPostingRepository := repository.JobPostingRepositoryFactory(log)
-	projectRecruitmentHeaderRepository := repository.ProjectRecruitmentHeaderRepositoryFactory(log)
-	MPRequestRepository := repository.MPRequestRepositoryFactory(log)
-	projectRecruitmentLineRepository := repository.ProjectRecruitmentLineRepositoryFactory(log)
-	applicantRepository := repository.ApplicantRepositoryFactory(log)
-	documentSendingRepository := repository.DocumentSendingRepositoryFactory(log)
+	dashboardRepository := repository.DashboardRepositoryFactory(log)
 	MPRequestMessage := messaging.MPRequestMessageFactory(log)
-	MPRequestService := service.MPRequestServiceFactory(log)
-	userHelper := helper.UserHelperFactory(log)
-	MPRequestHelper := helper.MPRequestHelperFactory(log)
-	employeeMessage := messaging.EmployeeMessageFactory(log)
 	jobPlafonMessage := messaging.JobPlafonMessageFactory(log)
+	organizationMessage := messaging.OrganizationMessageFactory(log)
 	return NewDashboardUseCase(
 		log,
 		viper,
-		jobPostingRepository,
-		projectRecruitmentHeaderRepository,
-		MPRequestRepository,
-		projectRecruitmentLineRepository,
-		applicantRepository,
-		documentSendingRepository,
+		dashboardRepository,
 		MPRequestMessage,
-		MPRequestService,
-		userHelper,
-		MPRequestHelper,
-		employeeMessage,
 		jobPlafonMessage,
+		organizationMessage,
 	)
 }
 
-func (uc *DashboardUseCase) GetDashboard() (*response.DashboardResponse, error) {
-	// get total recruitment target
-	totalRecruitmentTarget, err := uc.getTotalRecruitmentTarget()
-	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get total recruitment target")
-		return nil, err
-	}
+// dashboardFigures are the headline numbers of one period
+type dashboardFigures struct {
+	Target         int
+	Applicants     int
+	Hired          int
+	HiredBilingual int
+	DaysToHire     *repository.DashboardDaysToHire
+}
 
-	// get total recruitment realization
-	totalRecruitmentRealization, err := uc.getTotalRecruitmentRealization()
+// GetDashboard computes every metric with the same filter. When a period is given the headline
+// numbers are compared with the period of equal length right before it.
+func (uc *DashboardUseCase) GetDashboard(req *request.DashboardFilterRequest, orgScope *repository.OrganizationScope) (*response.DashboardResponse, error) {
+	filter, err := dashboardFilter(req)
 	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get total recruitment realization")
 		return nil, err
 	}
 
-	// get total bilingual
-	totalBilingual, err := uc.getTotalBilingual()
-	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get total bilingual")
-		return nil, err
-	}
+	// manpower requests are looked up once per request, the comparison and the department chart
+	// need the same ones
+	mpRequests := make(map[uuid.UUID]*response.MPRequestHeaderResponse)
 
-	// get chart duration recruitment
-	chartDurationRecruitment, err := uc.getChartDurationRecruitment()
+	figures, err := uc.getFigures(filter, orgScope, mpRequests)
 	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get chart duration recruitment")
+		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get dashboard figures")
 		return nil, err
 	}
 
 	// get chart job level
-	chartJobLevel, err := uc.getChartJobLevel()
+	chartJobLevel, err := uc.getChartJobLevel(filter, orgScope)
 	if err != nil {
 		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get chart job level")
 		return nil, err
 	}
 
 	// get chart department
-	chartDepartment, err := uc.getChartDepartment()
+	chartDepartment, err := uc.getChartDepartment(filter, orgScope, mpRequests)
 	if err != nil {
 		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get chart department")
 		return nil, err
 	}
 
-	// get avg time to hire
-	avgTimeToHire, err := uc.getAvgTimeToHire()
-	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get avg time to hire")
-		return nil, err
+	res := &response.DashboardResponse{
+		TotalRecruitmentTargetResponse: response.TotalRecruitmentTargetResponse{
+			TotalRecruitmentTarget: figures.Target,
+			Percentage:             percentage(figures.Hired, figures.Target),
+		},
+		TotalRecruitmentRealizationResponse: response.TotalRecruitmentRealizationResponse{
+			TotalRecruitmentRealization: figures.Hired,
+			Percentage:                  percentage(figures.Hired, figures.Applicants),
+		},
+		TotalBilingualResponse: response.TotalBilingualResponse{
+			TotalBilingual:    figures.HiredBilingual,
+			TotalNonBilingual: figures.Hired - figures.HiredBilingual,
+		},
+		ChartDurationRecruitmentResponse: response.ChartDurationRecruitmentResponse{
+			Labels: []string{
+				"> 30 Hari",
+				"21 - 30 Hari",
+				"11 - 20 Hari",
+				"1 - 10 Hari",
+			},
+			Datasets: []int{
+				figures.DaysToHire.Over30,
+				figures.DaysToHire.From21To30,
+				figures.DaysToHire.From11To20,
+				figures.DaysToHire.UpTo10,
+			},
+		},
+		ChartJobLevelResponse:   *chartJobLevel,
+		ChartDepartmentResponse: *chartDepartment,
+		AvgTimeToHireResponse: response.AvgTimeToHireResponse{
+			AvgTimeToHire: int(math.Round(figures.DaysToHire.AverageDays)),
+		},
 	}
 
-	return &response.DashboardResponse{
-		TotalRecruitmentTargetResponse:      *totalRecruitmentTarget,
-		TotalRecruitmentRealizationResponse: *totalRecruitmentRealization,
-		TotalBilingualResponse:              *totalBilingual,
-		ChartDurationRecruitmentResponse:    *chartDurationRecruitment,
-		ChartJobLevelResponse:               *chartJobLevel,
-		ChartDepartmentResponse:             *chartDepartment,
-		AvgTimeToHireResponse:               *avgTimeToHire,
-	}, nil
+	if previousFilter := filter.Previous(); previousFilter != nil {
+		previous, err := uc.getFigures(previousFilter, orgScope, mpRequests)
+		if err != nil {
+			uc.Log.WithError(err).Error("[DashboardUseCase.GetDashboard] failed to get dashboard figures of the previous period")
+			return nil, err
+		}
+		res.Comparison = &response.DashboardComparisonResponse{
+			PreviousStartDate:           previousFilter.StartDate.Format("2006-01-02"),
+			PreviousEndDate:             previousFilter.EndDate.Format("2006-01-02"),
+			TotalRecruitmentTarget:      compareMetric(figures.Target, previous.Target),
+			TotalRecruitmentRealization: compareMetric(figures.Hired, previous.Hired),
+			TotalBilingual:              compareMetric(figures.HiredBilingual, previous.HiredBilingual),
+			AvgTimeToHire:               compareMetric(int(math.Round(figures.DaysToHire.AverageDays)), int(math.Round(previous.DaysToHire.AverageDays))),
+		}
+	}
+
+	return res, nil
 }
 
-func (uc *DashboardUseCase) getTotalRecruitmentTarget() (*response.TotalRecruitmentTargetResponse, error) {
-	countMpr := 0
-	mpRequests, err := uc.MPRequestRepository.FindAll()
-	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getTotalRecruitmentTarget] failed to get all MP requests")
-		return nil, err
+// dashboardFilter parses the dashboard filter parameters, shared by the reports built on the
+// dashboard filter
+func dashboardFilter(req *request.DashboardFilterRequest) (*repository.DashboardFilter, error) {
+	filter := &repository.DashboardFilter{}
+	if req == nil {
+		return filter, nil
 	}
-	for _, mpRequest := range *mpRequests {
-		_, err := uc.MPRequestMessage.SendFindByIdMessage(mpRequest.MPRCloneID.String())
+
+	if req.StartDate != "" && req.EndDate != "" {
+		startDate, err := time.Parse("2006-01-02", req.StartDate)
 		if err != nil {
-			uc.Log.Errorf("[MPRequestUseCase.FindAllPaginated] error when send find by id message: %v", err)
-			continue
+			return nil, err
+		}
+		endDate, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		if startDate.After(endDate) {
+			return nil, ErrDashboardInvalidPeriod
 		}
-		countMpr++
+		filter.StartDate = &startDate
+		filter.EndDate = &endDate
 	}
-	applicantsHired, err := uc.ApplicantRepository.GetAllByKeys(map[string]interface{}{
-		"status": entity.APPLICANT_STATUS_HIRED,
-	})
-	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getTotalRecruitmentTarget] failed to get all hired applicants")
-		return nil, err
+
+	ids := []struct {
+		Value string
+		Field **uuid.UUID
+	}{
+		{req.ForOrganizationID, &filter.ForOrganizationID},
+		{req.OrganizationLocationID, &filter.OrganizationLocationID},
+		{req.ProjectRecruitmentHeaderID, &filter.ProjectRecruitmentHeaderID},
+	}
+	for _, id := range ids {
+		if id.Value == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(id.Value)
+		if err != nil {
+			return nil, err
+		}
+		*id.Field = &parsed
 	}
-	totalRecruitmentTarget := countMpr
-	var percentage float64
-	if totalRecruitmentTarget > 0 {
-		percentage = (float64(len(applicantsHired)) / float64(totalRecruitmentTarget)) * 100
+
+	if req.RecruitmentType != "" {
+		filter.RecruitmentType = dashboardRecruitmentTypes[req.RecruitmentType]
 	}
 
-	return &response.TotalRecruitmentTargetResponse{
-		TotalRecruitmentTarget: totalRecruitmentTarget,
-		Percentage:             int(percentage),
-	}, nil
+	return filter, nil
 }
 
-func (uc *DashboardUseCase) getTotalRecruitmentRealization() (*response.TotalRecruitmentRealizationResponse, error) {
-	applicants, err := uc.ApplicantRepository.GetAllByKeys(map[string]interface{}{})
+func (uc *DashboardUseCase) getFigures(filter *repository.DashboardFilter, orgScope *repository.OrganizationScope, mpRequests map[uuid.UUID]*response.MPRequestHeaderResponse) (*dashboardFigures, error) {
+	// the target counts the manpower requests still known to the manpower service
+	mprs, err := uc.DashboardRepository.FindMPRequests(filter, orgScope)
 	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getTotalRecruitmentRealization] failed to get all applicants")
+		uc.Log.WithError(err).Error("[DashboardUseCase.getFigures] failed to get MP requests")
 		return nil, err
 	}
+	target := 0
+	for _, mpr := range mprs {
+		if mpr.MPRCloneID == nil {
+			continue
+		}
+		if uc.findMPRequest(*mpr.MPRCloneID, mpRequests) != nil {
+			target++
+		}
+	}
 
-	applicantsHired, err := uc.ApplicantRepository.GetAllByKeys(map[string]interface{}{
-		"status": entity.APPLICANT_STATUS_HIRED,
-	})
+	applicants, err := uc.DashboardRepository.CountApplicants(filter, orgScope)
 	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getTotalRecruitmentRealization] failed to get all hired applicants")
+		uc.Log.WithError(err).Error("[DashboardUseCase.getFigures] failed to count applicants")
 		return nil, err
 	}
 
-	totalRecruitmentRealization := len(applicantsHired)
-	var percentage float64
-	if len(applicants) > 0 {
-		percentage = (float64(len(applicantsHired)) / float64(len(applicants))) * 100
+	daysToHire, err := uc.DashboardRepository.CountDaysToHire(filter, orgScope)
+	if err != nil {
+		uc.Log.WithError(err).Error("[DashboardUseCase.getFigures] failed to count days to hire")
+		return nil, err
 	}
 
-	return &response.TotalRecruitmentRealizationResponse{
-		TotalRecruitmentRealization: totalRecruitmentRealization,
-		Percentage:                  int(percentage),
+	return &dashboardFigures{
+		Target:         target,
+		Applicants:     applicants.Total,
+		Hired:          applicants.Hired,
+		HiredBilingual: applicants.HiredBilingual,
+		DaysToHire:     daysToHire,
 	}, nil
 }
 
-func (uc *DashboardUseCase) getTotalBilingual() (*response.TotalBilingualResponse, error) {
-	applicants, err := uc.ApplicantRepository.GetAllByKeys(map[string]interface{}{
-		"status": entity.APPLICANT_STATUS_HIRED,
-	})
+func (uc *DashboardUseCase) getChartJobLevel(filter *repository.DashboardFilter, orgScope *repository.OrganizationScope) (*response.ChartJobLevelResponse, error) {
+	counts, err := uc.DashboardRepository.CountDocumentSendingsByJobLevel(filter, orgScope)
 	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getTotalBilingual] failed to get all applicants")
+		uc.Log.WithError(err).Error("[DashboardUseCase.getChartJobLevel] failed to count document sendings by job level")
 		return nil, err
 	}
-	var totalBilingual int
-	var TotalNonBilingual int
-	for _, applicant := range applicants {
-		if applicant.UserProfile.Bilingual == "yes" {
-			totalBilingual++
-		} else {
-			TotalNonBilingual++
-		}
-	}
-
-	return &response.TotalBilingualResponse{
-		TotalBilingual:    totalBilingual,
-		TotalNonBilingual: TotalNonBilingual,
-	}, nil
-}
 
-func (uc *DashboardUseCase) getChartDurationRecruitment() (*response.ChartDurationRecruitmentResponse, error) {
-	labels := []string{
-		"> 30 Hari",
-		"21 - 30 Hari",
-		"11 - 20 Hari",
-		"1 - 10 Hari",
+	ids := make([]string, 0, len(counts))
+	for _, count := range counts {
+		ids = append(ids, count.JobLevelID.String())
 	}
-	datasets := make([]int, 0)
-	for _, label := range labels {
-		count, err := uc.ProjectRecruitmentHeaderRepository.CountDaysToHireByTotalDays(label)
-		if err != nil {
-			uc.Log.WithError(err).Error("[DashboardUseCase.getChartDurationRecruitment] failed to count days to hire by total days")
-			return nil, err
+	jobLevels, err := uc.JobPlafonMessage.SendFindJobLevelsByIDsMessage(ids)
+	if err != nil {
+		uc.Log.WithError(err).Error("[DashboardUseCase.getChartJobLevel] failed to find job levels")
+		return nil, err
+	}
+
+	labels := make([]string, 0, len(counts))
+	datasets := make([]int, 0, len(counts))
+	for _, count := range counts {
+		jobLevel, ok := jobLevels[count.JobLevelID.String()]
+		if !ok || jobLevel == nil {
+			uc.Log.Warnf("[DashboardUseCase.getChartJobLevel] job level %s not found", count.JobLevelID)
+			continue
 		}
-		datasets = append(datasets, count)
+		labels = append(labels, strconv.Itoa(int(jobLevel.Level))+" - "+jobLevel.Name)
+		datasets = append(datasets, count.Total)
 	}
 
-	return &response.ChartDurationRecruitmentResponse{
+	return &response.ChartJobLevelResponse{
 		Labels:   labels,
 		Datasets: datasets,
 	}, nil
 }
 
-func (uc *DashboardUseCase) getChartJobLevel() (*response.ChartJobLevelResponse, error) {
-	jobLevelIDs, err := uc.DocumentSendingRepository.GetJobLevelIdDistinct()
+// getChartDepartment counts the hires per organization structure their manpower request was made for
+func (uc *DashboardUseCase) getChartDepartment(filter *repository.DashboardFilter, orgScope *repository.OrganizationScope, mpRequests map[uuid.UUID]*response.MPRequestHeaderResponse) (*response.ChartDepartmentResponse, error) {
+	counts, err := uc.DashboardRepository.CountHiredByMPRequest(filter, orgScope)
 	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getChartJobLevel] failed to get job level id distinct")
+		uc.Log.WithError(err).Error("[DashboardUseCase.getChartDepartment] failed to count hired applicants by MP request")
 		return nil, err
 	}
 
-	var labels []string
-	var datasets []int
+	totals := make(map[uuid.UUID]int)
+	for _, count := range counts {
+		mpr := uc.findMPRequest(count.MPRCloneID, mpRequests)
+		if mpr == nil {
+			continue
+		}
+		totals[mpr.ForOrganizationStructureID] += count.Total
+	}
 
-	for _, jobLevelID := range jobLevelIDs {
-		jobLevelResp, err := uc.JobPlafonMessage.SendFindJobLevelByIDMessage(request.SendFindJobLevelByIDMessageRequest{
-			ID: jobLevelID.String(),
+	type department struct {
+		Name  string
+		Total int
+	}
+	departments := make([]department, 0, len(totals))
+	for id, total := range totals {
+		orgStructure, err := uc.OrganizationMessage.SendFindOrganizationStructureByIDMessage(request.SendFindOrganizationStructureByIDMessageRequest{
+			ID: id.String(),
 		})
 		if err != nil {
-			uc.Log.Errorf("[MPPlanningUseCase.FindAllLinesByHeaderIdPaginated Message] " + err.Error())
-			// return nil, err
+			uc.Log.Errorf("[DashboardUseCase.getChartDepartment] organization structure %s: %v", id, err)
 			continue
-		} else {
-			name := strconv.Itoa(int(jobLevelResp.Level)) + " - " + jobLevelResp.Name
-			labels = append(labels, name)
-
-			count, err := uc.DocumentSendingRepository.CountByJobLevelID(jobLevelID)
-			if err != nil {
-				uc.Log.WithError(err).Error("[DashboardUseCase.getChartJobLevel] failed to count by job level id")
-				return nil, err
-			}
-
-			datasets = append(datasets, count)
 		}
+		departments = append(departments, department{Name: orgStructure.Name, Total: total})
 	}
+	sort.Slice(departments, func(i, j int) bool {
+		if departments[i].Total != departments[j].Total {
+			return departments[i].Total > departments[j].Total
+		}
+		return departments[i].Name < departments[j].Name
+	})
 
-	return &response.ChartJobLevelResponse{
+	labels := make([]string, 0, len(departments))
+	datasets := make([]int, 0, len(departments))
+	for _, d := range departments {
+		labels = append(labels, d.Name)
+		datasets = append(datasets, d.Total)
+	}
+
+	return &response.ChartDepartmentResponse{
 		Labels:   labels,
 		Datasets: datasets,
 	}, nil
 }
 
-func (uc *DashboardUseCase) getChartDepartment() (*response.ChartDepartmentResponse, error) {
-	chartResp, err := uc.EmployeeMessage.SendGetChartEmployeeOrganizationStructureMessage()
+// findMPRequest returns the manpower request from the manpower service, nil when it is gone
+func (uc *DashboardUseCase) findMPRequest(cloneID uuid.UUID, mpRequests map[uuid.UUID]*response.MPRequestHeaderResponse) *response.MPRequestHeaderResponse {
+	if mpr, ok := mpRequests[cloneID]; ok {
+		return mpr
+	}
+	mpr, err := uc.MPRequestMessage.SendFindByIdMessage(cloneID.String())
 	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getChartDepartment] failed to get chart employee organization structure message")
-		return nil, err
+		uc.Log.Errorf("[DashboardUseCase.findMPRequest] error when send find by id message: %v", err)
+		mpr = nil
 	}
-
-	return chartResp, nil
+	mpRequests[cloneID] = mpr
+	return mpr
 }
 
-func (uc *DashboardUseCase) getAvgTimeToHire() (*response.AvgTimeToHireResponse, error) {
-	avg, err := uc.ProjectRecruitmentHeaderRepository.CountAverageDaysToHireAll()
-	if err != nil {
-		uc.Log.WithError(err).Error("[DashboardUseCase.getAvgTimeToHire] failed to count average days to hire all")
-		return nil, err
+func percentage(part int, total int) int {
+	if total <= 0 {
+		return 0
 	}
+	return int(float64(part) / float64(total) * 100)
+}
 
-	return &response.AvgTimeToHireResponse{
-		AvgTimeToHire: int(math.Round(avg)),
-	}, nil
+func compareMetric(current int, previous int) response.DashboardMetricComparisonResponse {
+	comparison := response.DashboardMetricComparisonResponse{
+		Current:  current,
+		Previous: previous,
+		Change:   current - previous,
+	}
+	if previous != 0 {
+		changePercentage := math.Round(float64(current-previous)/float64(previous)*10000) / 100
+		comparison.ChangePercentage = &changePercentage
+	}
+	return comparison
 }
@@ -18,7 +18,7 @@ type IDocumentAgreementUseCase interface {
 	UpdateDocumentAgreement(req *request.UpdateDocumentAgreementRequest) (*response.DocumentAgreementResponse, error)
 	FindByDocumentSendingIDAndApplicantID(documentSendingID string, applicantID string) (*response.DocumentAgreementResponse, error)
 	UpdateStatusDocumentAgreement(req *request.UpdateStatusDocumentAgreementRequest) (*response.DocumentAgreementResponse, error)
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, documentTypeID string) (*[]response.DocumentAgreementResponse, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, documentTypeID string, orgScope *repository.OrganizationScope) (*[]response.DocumentAgreementResponse, int64, error)
 	FindByID(id uuid.UUID) (*response.DocumentAgreementResponse, error)
 }
 
@@ -221,7 +221,7 @@ func (uc *DocumentAgreementUseCase) UpdateStatusDocumentAgreement(req *request.U
 	return uc.DTO.ConvertEntityToResponse(result), nil
 }
 
-func (uc *DocumentAgreementUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, documentTypeID string) (*[]response.DocumentAgreementResponse, int64, error) {
+func (uc *DocumentAgreementUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, documentTypeID string, orgScope *repository.OrganizationScope) (*[]response.DocumentAgreementResponse, int64, error) {
 	var docType *entity.DocumentType
 	var err error
 	documentAgreementIDs := make([]uuid.UUID, 0)
@@ -278,7 +278,7 @@ func (uc *DocumentAgreementUseCase) FindAllPaginated(page, pageSize int, search
 		}
 	}
 
-	documentAgreements, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter, documentAgreementIDs)
+	documentAgreements, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter, documentAgreementIDs, orgScope)
 	if err != nil {
 		uc.Log.Error(err)
 		return nil, 0, err
@@ -5,9 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +18,7 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 	"github.com/google/uuid"
@@ -31,7 +30,7 @@ import (
 
 type IDocumentSendingUseCase interface {
 	CreateDocumentSending(req *request.CreateDocumentSendingRequest) (*response.DocumentSendingResponse, error)
-	FindAllPaginatedByDocumentTypeID(documentTypeID uuid.UUID, page int, pageSize int, search string, sort map[string]interface{}) (*[]response.DocumentSendingResponse, int64, error)
+	FindAllPaginatedByDocumentTypeID(documentTypeID uuid.UUID, page int, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.DocumentSendingResponse, int64, error)
 	FindByDocumentTypeIDAndApplicantID(documentTypeID uuid.UUID, applicantID uuid.UUID) (*response.DocumentSendingResponse, error)
 	FindByID(id string) (*response.DocumentSendingResponse, error)
 	UpdateDocumentSending(req *request.UpdateDocumentSendingRequest) (*response.DocumentSendingResponse, error)
@@ -69,6 +68,8 @@ type DocumentSendingUseCase struct {
 	MidsuitService                   service.IMidsuitService
 	GradeMessage                     messaging.IGradeMessage
 	UserProfileRepository            repository.IUserProfileRepository
+	MidsuitSyncUseCase               IMidsuitSyncUseCase
+	Storage                          storage.IStorage
 }
 
 func NewDocumentSendingUseCase(
@@ -95,6 +96,8 @@ func NewDocumentSendingUseCase(
 	midsuitService service.IMidsuitService,
 	gradeMessage messaging.IGradeMessage,
 	userProfileRepository repository.IUserProfileRepository,
+	midsuitSyncUseCase IMidsuitSyncUseCase,
+	fileStorage storage.IStorage,
 ) IDocumentSendingUseCase {
 	return &DocumentSendingUseCase{
 		Log:                              log,
@@ -120,6 +123,8 @@ func NewDocumentSendingUseCase(
 		MidsuitService:                   midsuitService,
 		GradeMessage:                     gradeMessage,
 		UserProfileRepository:            userProfileRepository,
+		MidsuitSyncUseCase:               midsuitSyncUseCase,
+		Storage:                          fileStorage,
 	}
 }
 
@@ -145,6 +150,8 @@ func DocumentSendingUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IDocu
 	midsuitService := service.MidsuitServiceFactory(viper, log)
 	gradeMessage := messaging.GradeMessageFactory(log)
 	userProfileRepository := repository.UserProfileRepositoryFactory(log)
+	midsuitSyncUseCase := MidsuitSyncUseCaseFactory(log, viper)
+	fileStorage := storage.StorageFactory(viper, log)
 	return NewDocumentSendingUseCase(
 		log,
 		repo,
@@ -169,6 +176,8 @@ func DocumentSendingUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IDocu
 		midsuitService,
 		gradeMessage,
 		userProfileRepository,
+		midsuitSyncUseCase,
+		fileStorage,
 	)
 }
 
@@ -362,7 +371,7 @@ func (uc *DocumentSendingUseCase) CreateDocumentSending(req *request.CreateDocum
 	return uc.DTO.ConvertEntityToResponse(documentSending), nil
 }
 
-func (uc *DocumentSendingUseCase) FindAllPaginatedByDocumentTypeID(documentTypeID uuid.UUID, page int, pageSize int, search string, sort map[string]interface{}) (*[]response.DocumentSendingResponse, int64, error) {
+func (uc *DocumentSendingUseCase) FindAllPaginatedByDocumentTypeID(documentTypeID uuid.UUID, page int, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.DocumentSendingResponse, int64, error) {
 	docType, err := uc.DocumentTypeRepository.FindByID(documentTypeID)
 	if err != nil {
 		uc.Log.Error("[DocumentSendingUseCase.FindAllPaginatedByDocumentTypeID] " + err.Error())
@@ -385,16 +394,13 @@ func (uc *DocumentSendingUseCase) FindAllPaginatedByDocumentTypeID(documentTypeI
 		documentSetupIDs = append(documentSetupIDs, documentSetup.ID)
 	}
 
-	documentSendings, total, err := uc.Repository.FindAllPaginatedByDocumentSetupIDs(documentSetupIDs, page, pageSize, search, sort)
+	documentSendings, total, err := uc.Repository.FindAllPaginatedByDocumentSetupIDs(documentSetupIDs, page, pageSize, search, sort, orgScope)
 	if err != nil {
 		uc.Log.Error("[DocumentSendingUseCase.FindAllPaginatedByDocumentTypeID] " + err.Error())
 		return nil, 0, err
 	}
 
-	documentSendingResponses := make([]response.DocumentSendingResponse, 0)
-	for _, documentSending := range *documentSendings {
-		documentSendingResponses = append(documentSendingResponses, *uc.DTO.ConvertEntityToResponse(&documentSending))
-	}
+	documentSendingResponses := uc.DTO.ConvertEntitiesToResponses(*documentSendings)
 
 	return &documentSendingResponses, total, nil
 }
@@ -659,12 +665,7 @@ body {
 
 	timestamp := time.Now().UnixNano()
 	filePath := fmt.Sprintf("storage/generated_pdf/%s", strconv.FormatInt(timestamp, 10)+"_document.pdf")
-	err = os.MkdirAll("storage/generated_pdf", os.ModePerm)
-	if err != nil {
-		uc.Log.Errorf("Gagal membuat direktori: %v", err)
-		return nil, err
-	}
-	err = ioutil.WriteFile(filePath, pdfBuffer, 0644)
+	err = storage.PutBytes(uc.Storage, filePath, pdfBuffer)
 	if err != nil {
 		uc.Log.Errorf("Gagal membuat PDF: %v", err)
 		return nil, err
@@ -1446,7 +1447,7 @@ func (uc *DocumentSendingUseCase) UpdateDocumentSending(req *request.UpdateDocum
 		}
 
 		// Construct the full URL to the document
-		documentURL := uc.Viper.GetString("app.url") + *filePath
+		documentURL := storage.MailURL(uc.Viper, *filePath)
 
 		// Update the email body with a proper message and a button
 		emailBody := fmt.Sprintf(`
@@ -1697,74 +1698,6 @@ func (uc *DocumentSendingUseCase) employeeHired(applicant entity.Applicant, temp
 			return err
 		}
 
-		umResponse, err := uc.UserMessage.SendGetUserMe(request.SendFindUserByIDMessageRequest{
-			ID: userID.String(),
-		})
-		if err != nil {
-			uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-			return err
-		}
-		if umResponse.User == nil {
-			uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] user not found")
-			return errors.New("user not found")
-		}
-		employeeID, err := uc.UserHelper.GetEmployeeId(umResponse.User)
-		if err != nil {
-			uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-			return err
-		}
-
-		organizationID, err := uc.UserHelper.GetOrganizationID(umResponse.User)
-		if err != nil {
-			uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-			return err
-		}
-
-		organizationResp, err := uc.OrganizationMessage.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{
-			ID: organizationID.String(),
-		})
-		if err != nil {
-			uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-			return err
-		}
-
-		empResp, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
-			ID: employeeID.String(),
-		})
-		if err != nil {
-			uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] error sending find employee by id message: ", err)
-			return err
-		}
-		if empResp == nil {
-			uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] employee not found in midsuit")
-			return errors.New("employee not found in midsuit")
-		}
-
-		orgResp, err := uc.OrganizationMessage.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{
-			ID: empResp.OrganizationID.String(),
-		})
-		if err != nil {
-			uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] error sending find organization by id message: ", err)
-			return err
-		}
-		if orgResp == nil {
-			uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] organization not found in midsuit")
-			return errors.New("organization not found in midsuit")
-		}
-
-		orgStructureId := empResp.EmployeeJob["organization_structure_id"].(string)
-		orgStructureResp, err := uc.OrganizationMessage.SendFindOrganizationStructureByIDMessage(request.SendFindOrganizationStructureByIDMessageRequest{
-			ID: orgStructureId,
-		})
-		if err != nil {
-			uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] error sending find organization structure by id message: ", err)
-			return err
-		}
-		if orgStructureResp == nil {
-			uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] organization structure not found in midsuit")
-			return errors.New("organization structure not found in midsuit")
-		}
-
 		_, err = uc.ApplicantRepository.UpdateApplicant(&entity.Applicant{
 			ID:            applicant.ID,
 			Status:        entity.APPLICANT_STATUS_HIRED,
@@ -1775,670 +1708,10 @@ func (uc *DocumentSendingUseCase) employeeHired(applicant entity.Applicant, temp
 			return err
 		}
 
-		jobResp, err := uc.JobPlafonMessage.SendFindJobByIDMessage(request.SendFindJobByIDMessageRequest{
-			ID: jobPosting.JobID.String(),
-		})
-		if err != nil {
-			uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-			return err
-		}
-
-		jobLevelResp, err := uc.JobPlafonMessage.SendFindJobLevelByIDMessage(request.SendFindJobLevelByIDMessageRequest{
-			ID: documentSending.JobLevelID.String(),
-		})
-		if err != nil {
-			uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-			return err
-		}
-
-		// sync to midsuit
+		// sync to midsuit, the job retries on its own and can be inspected through
+		// the midsuit sync endpoints of the document sending
 		if uc.Viper.GetString("midsuit.sync") == "ACTIVE" {
-			authResp, err := uc.MidsuitService.AuthOneStep()
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			var filter string
-			if documentSending.RecruitmentType == entity.PROJECT_RECRUITMENT_TYPE_MT {
-				filter = "MT"
-			} else if documentSending.RecruitmentType == entity.PROJECT_RECRUITMENT_TYPE_PH {
-				filter = "PH"
-			} else if documentSending.RecruitmentType == entity.PROJECT_RECRUITMENT_TYPE_NS {
-				filter = "NS"
-			}
-
-			recTypeResp, err := uc.MidsuitService.RecruitmentTypeMidsuitAPIWithoutFilter(authResp.Token)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			if recTypeResp == nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] recruitment type not found")
-				return errors.New("recruitment type not found")
-			}
-
-			var recTypeID int
-			for _, recType := range recTypeResp.Records {
-				if recType.Value == filter {
-					recTypeID = recType.ID
-					break
-				}
-			}
-
-			uc.Log.Info("Recruitment Type ID cok: ", recTypeID)
-			uc.Log.Info("Education Level cok:", strings.TrimSpace(string(applicant.UserProfile.Educations[0].EducationLevel)))
-
-			eduLevel := strings.TrimSpace(string(applicant.UserProfile.Educations[0].EducationLevel))
-			if eduLevel != "S3" && eduLevel != "S2" && eduLevel != "S1" && eduLevel != "D4" && eduLevel != "D3" && eduLevel != "D2" && eduLevel != "D1" && eduLevel != "SMA" && eduLevel != "SMP" && eduLevel != "SD" && eduLevel != "TS" {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] education level not found")
-				return errors.New("education level not found")
-			}
-
-			// sync to midsuit employee
-			midsuitPayload := &request.SyncEmployeeMidsuitRequest{
-				AdOrgId: request.AdOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(organizationResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-					Identifier: organizationResp.Name,
-				},
-				Name:     applicant.UserProfile.Name,
-				Birthday: applicant.UserProfile.BirthDate.Format("2006-01-02"),
-				City:     applicant.UserProfile.BirthPlace,
-				Email:    userEmail,
-				HcGender: func() request.HcGender {
-					if applicant.UserProfile.Gender == entity.MALE {
-						return request.HcGender{
-							ID: "M",
-						}
-					} else {
-						return request.HcGender{
-							ID: "F",
-						}
-					}
-				}(),
-				HcMaritalStatus: request.HcMaritalStatus{
-					Identifier: strings.Title(string(applicant.UserProfile.MaritalStatus)),
-				},
-				HcNationalID1: applicant.UserProfile.Ktp,
-				HcReligionID: request.HcReligionId{
-					ID: 1000002,
-					// Identifier: string(applicant.UserProfile.Religion),
-				},
-				HcStatus: request.HcStatus{
-					ID: "A",
-				},
-				HcBasicAcceptance: request.HcBasicAcceptance{
-					ID:         strings.TrimSpace(string(applicant.UserProfile.Educations[0].EducationLevel)),
-					Identifier: strings.TrimSpace(string(applicant.UserProfile.Educations[0].EducationLevel)),
-				},
-				HCWorkStartDate: documentSending.JoinedDate.Format("2006-01-02"),
-				HcRecruitmentTypeId: request.HcRecruitmentTypeId{
-					ID: recTypeID,
-				},
-			}
-
-			midsuitEmpID, err := uc.MidsuitService.SyncEmployeeMidsuit(*midsuitPayload, authResp.Token)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			_, err = uc.EmployeeMessage.SendUpdateEmployeeMidsuitMessage(employeeID.String(), *midsuitEmpID)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-			uc.Log.Info("Midsuit Employee ID: ", *midsuitEmpID)
-
-			orgStructure, err := uc.OrganizationMessage.SendFindOrganizationStructureByIDMessage(request.SendFindOrganizationStructureByIDMessageRequest{
-				ID: convertedData.ForOrganizationStructureID.String(),
-			})
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			forOrganization, err := uc.OrganizationMessage.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{
-				ID: documentSending.ForOrganizationID.String(),
-			})
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			organizationLocation, err := uc.OrganizationMessage.SendFindOrganizationLocationByIDMessage(request.SendFindOrganizationLocationByIDMessageRequest{
-				ID: documentSending.OrganizationLocationID.String(),
-			})
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			var gradeMidsuitID *string
-			if documentSending.GradeID != nil {
-				gradeResp, err := uc.GradeMessage.SendFindByIDMessage(documentSending.GradeID.String())
-				if err != nil {
-					uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-					return err
-				}
-
-				if gradeResp == nil {
-					uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] grade not found")
-					return errors.New("grade not found")
-				}
-
-				gradeMidsuitID = &gradeResp.MidsuitID
-			}
-
-			// sync to midsuit employee job
-			midsuitEmployeeJobPayload := &request.SyncEmployeeJobMidsuitRequest{
-				AdOrgId: request.AdOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(organizationResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCCompensation1: int(documentSending.BasicWage),
-				HCEmployeeID: request.HcEmployeeId{
-					ID: func() int {
-						id, err := strconv.Atoi(*midsuitEmpID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert midsuitEmpID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCEmployeeCategoryID: func() *request.HcEmployeeCategoryId {
-					if documentSending.HiredStatus == "" {
-						return nil
-					}
-					return &request.HcEmployeeCategoryId{
-						Identifier: string(documentSending.HiredStatus),
-					}
-				}(),
-				HCJobID: request.HcJobId{
-					ID: func() int {
-						id, err := strconv.Atoi(jobResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCJobLevelID: request.HcJobLevelId{
-					ID: func() int {
-						id, err := strconv.Atoi(jobLevelResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCOrgID: request.HcOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(orgStructure.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCWorkStartDate: documentSending.JoinedDate.Format("2006-01-02"),
-				// HCRecruitmentTypeID: request.HcRecruitmentTypeId{
-				// 	// Identifier: string(documentSending.RecruitmentType),
-				// 	ID: recTypeID,
-				// },
-				ADEmploymentOrgID: request.AdOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(forOrganization.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCWorkSiteID: request.HcWorkSiteId{
-					ID: func() int {
-						id, err := strconv.Atoi(organizationLocation.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				IsPrimary: true,
-				HCEmployeeGradeID: request.HcEmployeeGradeId{
-					ID: func() int {
-						if gradeMidsuitID == nil {
-							return 0
-						}
-						id, err := strconv.Atoi(*gradeMidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				ModelName: "hc_employeejob",
-			}
-
-			uc.Log.Info("Midsuit Employee Job Payload cok: ", *midsuitEmployeeJobPayload)
-
-			_, err = uc.MidsuitService.SyncEmployeeJobMidsuit(*midsuitEmployeeJobPayload, authResp.Token)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			// sync to midsuit employee work experiences
-			if len(applicant.UserProfile.WorkExperiences) > 0 {
-				for _, workExperience := range applicant.UserProfile.WorkExperiences {
-					workExperiencePayload := &request.SyncEmployeeWorkExperienceMidsuitRequest{
-						AdOrgId: request.AdOrgId{
-							ID: func() int {
-								id, err := strconv.Atoi(organizationResp.MidsuitID)
-								if err != nil {
-									uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-									return 0
-								}
-								return id
-							}(),
-						},
-						HCEmployeeID: request.HcEmployeeId{
-							ID: func() int {
-								id, err := strconv.Atoi(*midsuitEmpID)
-								if err != nil {
-									uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert midsuitEmpID to int: " + err.Error())
-									return 0
-								}
-								return id
-							}(),
-						},
-						Name:           workExperience.Name,
-						Description:    "Mwehehe",
-						YearExperience: string(workExperience.YearExperience),
-						ModelName:      "hc_workhistory",
-					}
-
-					_, err = uc.MidsuitService.SyncEmployeeWorkExperienceMidsuit(*workExperiencePayload, authResp.Token)
-					if err != nil {
-						uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-						return err
-					}
-				}
-			}
-
-			// sync to midsuit employee educations
-			if len(applicant.UserProfile.Educations) > 0 {
-				for i, education := range applicant.UserProfile.Educations {
-					educationPayload := &request.SyncEmployeeEducationMidsuitRequest{
-						AdOrgId: request.AdOrgId{
-							ID: func() int {
-								id, err := strconv.Atoi(organizationResp.MidsuitID)
-								if err != nil {
-									uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-									return 0
-								}
-								return id
-							}(),
-						},
-						HCEmployeeID: request.HcEmployeeId{
-							ID: func() int {
-								id, err := strconv.Atoi(*midsuitEmpID)
-								if err != nil {
-									uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert midsuitEmpID to int: " + err.Error())
-									return 0
-								}
-								return id
-							}(),
-						},
-						BidangPendidikanAkhir: education.Major,
-						HcEducationInstitute:  education.SchoolName,
-						HcGpaScore:            int(*education.Gpa),
-						SeqNo:                 10,
-						HCBasicAcceptance: request.HcBasicAcceptance{
-							// Identifier: applicant.UserProfile.Educations[i].Major,
-							ID: strings.TrimSpace(string(applicant.UserProfile.Educations[i].EducationLevel)),
-						},
-						ModelName: "hc_employeeeducation",
-					}
-
-					_, err = uc.MidsuitService.SyncEmployeeEducationMidsuit(*educationPayload, authResp.Token)
-					if err != nil {
-						uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-						return err
-					}
-				}
-			}
-
-			// sync to midsuit employee operation
-			empRespVerifiedBy, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
-				ID: documentSending.AllowanceApproval.String(),
-			})
-			if err != nil {
-				uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] error sending find employee by id message: ", err)
-				return err
-			}
-			if empRespVerifiedBy == nil {
-				uc.Log.Error("[EmployeeTaskUseCase.UpdateEmployeeTaskUseCase] employee not found in midsuit")
-				return errors.New("employee not found in midsuit")
-			}
-
-			var joinedDate time.Time
-			if documentSending.JoinedDate.Day() == 25 {
-				joinedDate = documentSending.JoinedDate.AddDate(0, 1, 0)
-			} else {
-				joinedDate = *documentSending.JoinedDate
-			}
-
-			cPeriodID := joinedDate.Format("Jan-06")
-			fmt.Println("Formatted cPeriodID:", cPeriodID)
-			// cPeriodID := documentSending.JoinedDate.Format("Jan-06")
-
-			allowanceOperationPayload := &request.SyncEmployeeAllowanceMidsuitRequest{
-				AdOrgId: request.AdOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(organizationResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				CDocTypeID: request.CDocTypeID{
-					Identifier: "Allowance Provision",
-					ModelName:  "c_doctype",
-				},
-				CPeriodID: request.CPeriodID{
-					// Identifier: "Apr-25",
-					Identifier: cPeriodID,
-					ModelName:  "c_period",
-				},
-				DateDoc: documentSending.JoinedDate.Format("2006-01-02"),
-				HCEmployee2ID: request.HcEmployeeId{
-					ID: func() int {
-						id, err := strconv.Atoi(*midsuitEmpID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert midsuitEmpID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCJob2ID: request.HcJobId{
-					ID: func() int {
-						id, err := strconv.Atoi(jobResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCEmployeeID: request.HcEmployeeId{
-					ID: func() int {
-						id, err := strconv.Atoi(empRespVerifiedBy.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCOrg2ID: request.HcOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(orgStructure.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCAllowanceType: request.HCAllowanceType{
-					ID: "OPR",
-				},
-				Distance: 1,
-				Amount:   int(documentSending.OperationalAllowance),
-				HCUOM: request.HCUOM{
-					ID: "MO",
-				},
-				IsUseDate: false,
-				HCProvisionType: request.HCProvisionType{
-					ID: "GRA",
-				},
-				IsGenerated: true,
-				ModelName:   "hc_allowanceprovision",
-				DocAction:   "CO",
-			}
-
-			_, err = uc.MidsuitService.SyncEmployeeAllowanceMidsuit(*allowanceOperationPayload, authResp.Token)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending Allowance Operation] " + err.Error())
-				return err
-			}
-
-			// sync to midsuit employee allowance meal
-			allowanceMealPayload := &request.SyncEmployeeAllowanceMidsuitRequest{
-				AdOrgId: request.AdOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(organizationResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				CDocTypeID: request.CDocTypeID{
-					Identifier: "Allowance Provision",
-					ModelName:  "c_doctype",
-				},
-				CPeriodID: request.CPeriodID{
-					// Identifier: "Apr-25",
-					Identifier: cPeriodID,
-					ModelName:  "c_period",
-				},
-				DateDoc: documentSending.JoinedDate.Format("2006-01-02"),
-				HCEmployee2ID: request.HcEmployeeId{
-					ID: func() int {
-						id, err := strconv.Atoi(*midsuitEmpID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert midsuitEmpID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCJob2ID: request.HcJobId{
-					ID: func() int {
-						id, err := strconv.Atoi(jobResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCEmployeeID: request.HcEmployeeId{
-					ID: func() int {
-						id, err := strconv.Atoi(empRespVerifiedBy.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCOrg2ID: request.HcOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(orgStructure.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCAllowanceType: request.HCAllowanceType{
-					ID: "MEL",
-				},
-				Distance: 1,
-				Amount:   int(documentSending.MealAllowance),
-				HCUOM: request.HCUOM{
-					ID: "MO",
-				},
-				IsUseDate: false,
-				HCProvisionType: request.HCProvisionType{
-					ID: "GRA",
-				},
-				IsGenerated: true,
-				ModelName:   "hc_allowanceprovision",
-				// DocAction:   "CO",
-			}
-
-			_, err = uc.MidsuitService.SyncEmployeeAllowanceMidsuit(*allowanceMealPayload, authResp.Token)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending Allowance Meal] " + err.Error())
-				return err
-			}
-
-			// sync to midsuit employee allowance meal
-			allowanceHousePayload := &request.SyncEmployeeAllowanceMidsuitRequest{
-				AdOrgId: request.AdOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(organizationResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				CDocTypeID: request.CDocTypeID{
-					Identifier: "Allowance Provision",
-					ModelName:  "c_doctype",
-				},
-				CPeriodID: request.CPeriodID{
-					// Identifier: "Apr-25",
-					Identifier: cPeriodID,
-					ModelName:  "c_period",
-				},
-				DateDoc: documentSending.JoinedDate.Format("2006-01-02"),
-				HCEmployee2ID: request.HcEmployeeId{
-					ID: func() int {
-						id, err := strconv.Atoi(*midsuitEmpID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert midsuitEmpID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCJob2ID: request.HcJobId{
-					ID: func() int {
-						id, err := strconv.Atoi(jobResp.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCEmployeeID: request.HcEmployeeId{
-					ID: func() int {
-						id, err := strconv.Atoi(empRespVerifiedBy.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCOrg2ID: request.HcOrgId{
-					ID: func() int {
-						id, err := strconv.Atoi(orgStructure.MidsuitID)
-						if err != nil {
-							uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert MidsuitID to int: " + err.Error())
-							return 0
-						}
-						return id
-					}(),
-				},
-				HCAllowanceType: request.HCAllowanceType{
-					ID: "HOS",
-				},
-				Distance: 1,
-				Amount:   int(documentSending.HouseAllowance),
-				HCUOM: request.HCUOM{
-					ID: "MO",
-				},
-				IsUseDate: false,
-				HCProvisionType: request.HCProvisionType{
-					ID: "GRA",
-				},
-				IsGenerated: true,
-				ModelName:   "hc_allowanceprovision",
-				DocAction:   "CO",
-			}
-
-			_, err = uc.MidsuitService.SyncEmployeeAllowanceMidsuit(*allowanceHousePayload, authResp.Token)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending Allowance House] " + err.Error())
-				return err
-			}
-
-			midsuitEmpIDInt, err := strconv.Atoi(*midsuitEmpID)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] failed to convert midsuitEmpID to int: " + err.Error())
-				return err
-			}
-			userProfileMidsuitID, err := uc.MidsuitService.SyncGenerateUserMidsuit(midsuitEmpIDInt, authResp.Token)
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			// update user profile
-			_, err = uc.UserProfileRepository.UpdateUserProfile(&entity.UserProfile{
-				ID:        applicant.UserProfile.ID,
-				MidsuitID: userProfileMidsuitID,
-			})
-			if err != nil {
-				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
-				return err
-			}
-
-			_, err = uc.EmployeeMessage.SendCreateEmployeeTaskMessage(request.SendCreateEmployeeTaskMessageRequest{
-				EmployeeID:            employeeID.String(),
-				JoinedDate:            documentSending.JoinedDate.String(),
-				OrganizationType:      organizationResp.OrganizationType,
-				EmployeeMidsuitID:     *midsuitEmpID,
-				JobMidsuitID:          jobResp.MidsuitID,
-				JobLevelMidsuitID:     jobLevelResp.MidsuitID,
-				OrgMidsuitID:          orgResp.MidsuitID,
-				OrgStructureMidsuitID: orgStructureResp.MidsuitID,
-			})
+			_, err = uc.MidsuitSyncUseCase.EnqueueMidsuitSync(documentSending.ID)
 			if err != nil {
 				uc.Log.Error("[DocumentSendingUseCase.UpdateDocumentSending] " + err.Error())
 				return err
@@ -2482,10 +1755,7 @@ func (uc *DocumentSendingUseCase) FindAllByDocumentSetupID(documentSetupID strin
 		return nil, err
 	}
 
-	documentSendingResponses := make([]response.DocumentSendingResponse, 0)
-	for _, documentSending := range *documentSendings {
-		documentSendingResponses = append(documentSendingResponses, *uc.DTO.ConvertEntityToResponse(&documentSending))
-	}
+	documentSendingResponses := uc.DTO.ConvertEntitiesToResponses(*documentSendings)
 
 	return &documentSendingResponses, nil
 }
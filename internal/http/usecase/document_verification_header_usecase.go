@@ -17,7 +17,7 @@ type IDocumentVerificationHeaderUseCase interface {
 	CreateDocumentVerificationHeader(req *request.CreateDocumentVerificationHeaderRequest) (*response.DocumentVerificationHeaderResponse, error)
 	UpdateDocumentVerificationHeader(req *request.UpdateDocumentVerificationHeaderRequest) (*response.DocumentVerificationHeaderResponse, error)
 	FindByID(id string) (*response.DocumentVerificationHeaderResponse, error)
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.DocumentVerificationHeaderResponse, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.DocumentVerificationHeaderResponse, int64, error)
 	DeleteDocumentVerificationHeader(id string) error
 	FindByJobPostingAndApplicant(jobPostingID, applicantID uuid.UUID) (*response.DocumentVerificationHeaderResponse, error)
 }
@@ -247,8 +247,8 @@ func (uc *DocumentVerificationHeaderUseCase) FindByID(id string) (*response.Docu
 	return uc.DTO.ConvertEntityToResponse(ent), nil
 }
 
-func (uc *DocumentVerificationHeaderUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.DocumentVerificationHeaderResponse, int64, error) {
-	documentVerifications, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort)
+func (uc *DocumentVerificationHeaderUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.DocumentVerificationHeaderResponse, int64, error) {
+	documentVerifications, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, orgScope)
 	if err != nil {
 		uc.Log.Error("[DocumentVerificationHeaderUseCase.FindAllPaginated] " + err.Error())
 		return nil, 0, err
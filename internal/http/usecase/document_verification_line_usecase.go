@@ -3,7 +3,6 @@ package usecase
 import (
 	"encoding/base64"
 	"errors"
-	"os"
 	"path/filepath"
 	"strconv"
 
@@ -15,6 +14,7 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -42,6 +42,7 @@ type DocumentVerificationLineUsecase struct {
 	EmployeeMessage                      messaging.IEmployeeMessage
 	MidsuitService                       service.IMidsuitService
 	UserHelper                           helper.IUserHelper
+	Storage                              storage.IStorage
 }
 
 func NewDocumentVerificationLineUsecase(
@@ -58,6 +59,7 @@ func NewDocumentVerificationLineUsecase(
 	employeeMessage messaging.IEmployeeMessage,
 	midsuitService service.IMidsuitService,
 	userHelper helper.IUserHelper,
+	fileStorage storage.IStorage,
 ) IDocumentVerificationLineUsecase {
 	return &DocumentVerificationLineUsecase{
 		Log:                                  log,
@@ -73,6 +75,7 @@ func NewDocumentVerificationLineUsecase(
 		EmployeeMessage:                      employeeMessage,
 		MidsuitService:                       midsuitService,
 		UserHelper:                           userHelper,
+		Storage:                              fileStorage,
 	}
 }
 
@@ -88,6 +91,7 @@ func DocumentVerificationLineFactory(log *logrus.Logger, viper *viper.Viper) IDo
 	employeeMessage := messaging.EmployeeMessageFactory(log)
 	midsuitService := service.MidsuitServiceFactory(viper, log)
 	userHelper := helper.UserHelperFactory(log)
+	fileStorage := storage.StorageFactory(viper, log)
 	return NewDocumentVerificationLineUsecase(
 		log,
 		dvlRepo,
@@ -102,6 +106,7 @@ func DocumentVerificationLineFactory(log *logrus.Logger, viper *viper.Viper) IDo
 		employeeMessage,
 		midsuitService,
 		userHelper,
+		fileStorage,
 	)
 }
 
@@ -365,7 +370,7 @@ func (uc *DocumentVerificationLineUsecase) UploadDocumentVerificationLine(req *r
 
 	if uc.Viper.GetString("midsuit.sync") == "ACTIVE" {
 		if documentVerificationLine.DocumentVerification.Name == "Foto Formal" {
-			fileContent, err := os.ReadFile(req.Path)
+			fileContent, err := storage.ReadFile(uc.Storage, req.Path)
 			if err != nil {
 				uc.Log.Error("[EmployeeTaskUseCase.CreateEmployeeTaskUseCase] error reading file: ", err)
 				return nil, err
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// how much each shared detail adds to the score of a pair, a KTP number is issued once per
+// person while a phone number or an email can be passed on to family members
+const (
+	DUPLICATE_SCORE_KTP_NUMBER      = 60
+	DUPLICATE_SCORE_EMAIL           = 50
+	DUPLICATE_SCORE_PHONE_NUMBER    = 40
+	DUPLICATE_SCORE_NAME_BIRTH_DATE = 40
+)
+
+var (
+	ErrDuplicateCandidateNotFound = errors.New("duplicate candidate not found")
+	ErrDuplicateCandidateResolved = errors.New("duplicate candidate is already resolved")
+)
+
+type IDuplicateCandidateUseCase interface {
+	RegisterJobs(scheduler ISchedulerUseCase) error
+	DetectDuplicates(ctx context.Context) (string, error)
+	FindAllPaginated(page, pageSize int, status string) (*[]response.DuplicateCandidateResponse, int64, error)
+	Merge(id uuid.UUID, survivingProfileID uuid.UUID, mergedBy uuid.UUID) (*response.UserProfileMergeResponse, error)
+	Dismiss(id uuid.UUID, dismissedBy uuid.UUID) (*response.DuplicateCandidateResponse, error)
+}
+
+type DuplicateCandidateUseCase struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Repository repository.IDuplicateCandidateRepository
+	DTO        dto.IDuplicateCandidateDTO
+}
+
+func NewDuplicateCandidateUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IDuplicateCandidateRepository,
+	dcDTO dto.IDuplicateCandidateDTO,
+) IDuplicateCandidateUseCase {
+	return &DuplicateCandidateUseCase{
+		Log:        log,
+		Viper:      viper,
+		Repository: repo,
+		DTO:        dcDTO,
+	}
+}
+
+func DuplicateCandidateUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IDuplicateCandidateUseCase {
+	repo := repository.DuplicateCandidateRepositoryFactory(log)
+	dcDTO := dto.DuplicateCandidateDTOFactory(log, viper)
+	return NewDuplicateCandidateUseCase(log, viper, repo, dcDTO)
+}
+
+func (uc *DuplicateCandidateUseCase) RegisterJobs(scheduler ISchedulerUseCase) error {
+	if err := scheduler.RegisterJob("detect_duplicate_candidates", "*/15 * * * *", uc.DetectDuplicates); err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.RegisterJobs] " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// DetectDuplicates scores every pair of profiles sharing a KTP number, a phone number, an email
+// or a name with the same birth date. Open pairs that no longer share anything, because one of
+// the profiles was corrected or deleted, are removed. Merged and dismissed pairs are kept as
+// they are.
+func (uc *DuplicateCandidateUseCase) DetectDuplicates(ctx context.Context) (string, error) {
+	runStart := time.Now()
+
+	pairs, err := uc.Repository.FindProbableDuplicates()
+	if err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.DetectDuplicates] " + err.Error())
+		return "", err
+	}
+
+	for i := range pairs {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		pair := &pairs[i]
+		pair.Status = entity.DUPLICATE_CANDIDATE_STATUS_OPEN
+		pair.Score = duplicateScore(pair)
+		pair.DetectedAt = time.Now()
+		if err := uc.Repository.UpsertOpenCandidate(pair); err != nil {
+			uc.Log.Error("[DuplicateCandidateUseCase.DetectDuplicates] " + err.Error())
+			return "", err
+		}
+	}
+
+	deleted, err := uc.Repository.DeleteStaleOpenCandidates(runStart)
+	if err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.DetectDuplicates] " + err.Error())
+		return "", err
+	}
+
+	return fmt.Sprintf("found %d probable duplicates, removed %d stale", len(pairs), deleted), nil
+}
+
+func (uc *DuplicateCandidateUseCase) FindAllPaginated(page, pageSize int, status string) (*[]response.DuplicateCandidateResponse, int64, error) {
+	candidates, total, err := uc.Repository.FindAllPaginated(page, pageSize, status)
+	if err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.FindAllPaginated] " + err.Error())
+		return nil, 0, err
+	}
+
+	res := make([]response.DuplicateCandidateResponse, 0, len(*candidates))
+	for i := range *candidates {
+		resp, err := uc.DTO.ConvertEntityToResponse(&(*candidates)[i])
+		if err != nil {
+			uc.Log.Error("[DuplicateCandidateUseCase.FindAllPaginated] " + err.Error())
+			return nil, 0, err
+		}
+		res = append(res, *resp)
+	}
+
+	return &res, total, nil
+}
+
+// Merge keeps survivingProfileID, which has to be one of the two profiles of the pair, and
+// merges the other profile into it
+func (uc *DuplicateCandidateUseCase) Merge(id uuid.UUID, survivingProfileID uuid.UUID, mergedBy uuid.UUID) (*response.UserProfileMergeResponse, error) {
+	candidate, err := uc.findOpenCandidate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var mergedProfileID uuid.UUID
+	switch survivingProfileID {
+	case candidate.UserProfileID:
+		mergedProfileID = candidate.DuplicateProfileID
+	case candidate.DuplicateProfileID:
+		mergedProfileID = candidate.UserProfileID
+	default:
+		return nil, errors.New("surviving profile must be one of the profiles of the duplicate candidate")
+	}
+
+	merge, err := uc.Repository.Merge(&candidate.ID, survivingProfileID, mergedProfileID, mergedBy)
+	if err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.Merge] " + err.Error())
+		return nil, err
+	}
+
+	return uc.DTO.ConvertMergeToResponse(merge), nil
+}
+
+func (uc *DuplicateCandidateUseCase) Dismiss(id uuid.UUID, dismissedBy uuid.UUID) (*response.DuplicateCandidateResponse, error) {
+	if _, err := uc.findOpenCandidate(id); err != nil {
+		return nil, err
+	}
+
+	if err := uc.Repository.Dismiss(id, dismissedBy); err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.Dismiss] " + err.Error())
+		return nil, err
+	}
+
+	candidate, err := uc.Repository.FindByID(id)
+	if err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.Dismiss] " + err.Error())
+		return nil, err
+	}
+
+	return uc.DTO.ConvertEntityToResponse(candidate)
+}
+
+func (uc *DuplicateCandidateUseCase) findOpenCandidate(id uuid.UUID) (*entity.DuplicateCandidate, error) {
+	candidate, err := uc.Repository.FindByID(id)
+	if err != nil {
+		uc.Log.Error("[DuplicateCandidateUseCase.findOpenCandidate] " + err.Error())
+		return nil, err
+	}
+	if candidate == nil {
+		return nil, ErrDuplicateCandidateNotFound
+	}
+	if candidate.Status != entity.DUPLICATE_CANDIDATE_STATUS_OPEN {
+		return nil, ErrDuplicateCandidateResolved
+	}
+	return candidate, nil
+}
+
+func duplicateScore(pair *entity.DuplicateCandidate) int {
+	score := 0
+	if pair.MatchKtpNumber {
+		score += DUPLICATE_SCORE_KTP_NUMBER
+	}
+	if pair.MatchEmail {
+		score += DUPLICATE_SCORE_EMAIL
+	}
+	if pair.MatchPhoneNumber {
+		score += DUPLICATE_SCORE_PHONE_NUMBER
+	}
+	if pair.MatchNameBirthDate {
+		score += DUPLICATE_SCORE_NAME_BIRTH_DATE
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
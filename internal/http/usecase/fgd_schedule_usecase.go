@@ -16,7 +16,7 @@ import (
 )
 
 type IFgdScheduleUseCase interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.FgdScheduleResponse, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.FgdScheduleResponse, int64, error)
 	CreateFgdSchedule(req *request.CreateFgdScheduleRequest) (*response.FgdScheduleResponse, error)
 	UpdateFgdSchedule(req *request.UpdateFgdScheduleRequest) (*response.FgdScheduleResponse, error)
 	FindByID(id uuid.UUID) (*response.FgdScheduleResponse, error)
@@ -42,6 +42,7 @@ type FgdScheduleUseCase struct {
 	FgdAssessorRepository              repository.IFgdAssessorRepository
 	FgdApplicantRepository             repository.IFgdApplicantRepository
 	ApplicantRepository                repository.IApplicantRepository
+	ScheduleReminderRepository         repository.IScheduleReminderRepository
 }
 
 func NewFgdUseCase(
@@ -57,6 +58,7 @@ func NewFgdUseCase(
 	fgdAssessorRepository repository.IFgdAssessorRepository,
 	fgdApplicantRepository repository.IFgdApplicantRepository,
 	applicantRepository repository.IApplicantRepository,
+	scheduleReminderRepository repository.IScheduleReminderRepository,
 ) IFgdScheduleUseCase {
 	return &FgdScheduleUseCase{
 		Log:                                log,
@@ -71,6 +73,7 @@ func NewFgdUseCase(
 		FgdAssessorRepository:              fgdAssessorRepository,
 		FgdApplicantRepository:             fgdApplicantRepository,
 		ApplicantRepository:                applicantRepository,
+		ScheduleReminderRepository:         scheduleReminderRepository,
 	}
 }
 
@@ -88,6 +91,7 @@ func FgdScheduleUseCaseFactory(
 	fgdAssessorRepository := repository.FgdAssessorRepositoryFactory(log)
 	fgdApplicantRepository := repository.FgdApplicantRepositoryFactory(log)
 	applicantRepository := repository.ApplicantRepositoryFactory(log)
+	scheduleReminderRepository := repository.ScheduleReminderRepositoryFactory(log)
 	return NewFgdUseCase(
 		log,
 		FgdRepository,
@@ -101,11 +105,12 @@ func FgdScheduleUseCaseFactory(
 		fgdAssessorRepository,
 		fgdApplicantRepository,
 		applicantRepository,
+		scheduleReminderRepository,
 	)
 }
 
-func (uc *FgdScheduleUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.FgdScheduleResponse, int64, error) {
-	fgdSchedules, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort)
+func (uc *FgdScheduleUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.FgdScheduleResponse, int64, error) {
+	fgdSchedules, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, orgScope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -528,6 +533,8 @@ func (uc *FgdScheduleUseCase) UpdateFgdSchedule(req *request.UpdateFgdScheduleRe
 		}
 	}
 
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_FGD, fgdSchedule.ID)
+
 	findByID, err := uc.Repository.FindByID(fgdSchedule.ID)
 	if err != nil {
 		uc.Log.Error("[FgdScheduleUseCase.UpdateFgdScheduleRequest] " + err.Error())
@@ -581,6 +588,7 @@ func (uc *FgdScheduleUseCase) DeleteByID(id uuid.UUID) error {
 		uc.Log.Error("[FgdScheduleUseCase.DeleteByID] " + err.Error())
 		return err
 	}
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_FGD, id)
 
 	return nil
 }
@@ -623,6 +631,7 @@ func (uc *FgdScheduleUseCase) UpdateStatusFgdSchedule(req *request.UpdateStatusF
 		uc.Log.Error("[FgdScheduleUseCase.UpdateStatusFgdScheduleRequest] " + err.Error())
 		return nil, err
 	}
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_FGD, parsedID)
 
 	resp, err := uc.DTO.ConvertEntityToResponse(FgdSchedule)
 	if err != nil {
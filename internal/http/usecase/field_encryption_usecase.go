@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IFieldEncryptionUseCase interface {
+	RotateDataKey() (uuid.UUID, error)
+	RewrapDataKeys() (int, error)
+	Reencrypt(batchSize int) (map[string]int, error)
+}
+
+type FieldEncryptionUseCase struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Repository repository.IFieldEncryptionRepository
+	Encryptor  *config.FieldEncryptor
+}
+
+func NewFieldEncryptionUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IFieldEncryptionRepository,
+	encryptor *config.FieldEncryptor,
+) IFieldEncryptionUseCase {
+	return &FieldEncryptionUseCase{
+		Log:        log,
+		Viper:      viper,
+		Repository: repo,
+		Encryptor:  encryptor,
+	}
+}
+
+func FieldEncryptionUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IFieldEncryptionUseCase {
+	repo := repository.FieldEncryptionRepositoryFactory(log)
+	return NewFieldEncryptionUseCase(log, viper, repo, config.NewFieldEncryptor())
+}
+
+// RotateDataKey makes a new data key the active one, values already written keep their key until
+// they are re-encrypted
+func (uc *FieldEncryptionUseCase) RotateDataKey() (uuid.UUID, error) {
+	keyID, err := uc.Encryptor.RotateDataKey()
+	if err != nil {
+		uc.Log.Error("[FieldEncryptionUseCase.RotateDataKey] " + err.Error())
+		return uuid.Nil, err
+	}
+
+	return keyID, nil
+}
+
+// RewrapDataKeys moves the data keys to the active master key
+func (uc *FieldEncryptionUseCase) RewrapDataKeys() (int, error) {
+	count, err := uc.Encryptor.RewrapDataKeys()
+	if err != nil {
+		uc.Log.Error("[FieldEncryptionUseCase.RewrapDataKeys] " + err.Error())
+		return count, err
+	}
+
+	return count, nil
+}
+
+// Reencrypt seals every encrypted column with the active data key, plaintext written before the
+// columns were encrypted included. It returns the number of rows written per table.
+func (uc *FieldEncryptionUseCase) Reencrypt(batchSize int) (map[string]int, error) {
+	res := make(map[string]int)
+
+	count, err := uc.Repository.ReencryptUserProfiles(batchSize)
+	res["user_profiles"] = count
+	if err != nil {
+		uc.Log.Error("[FieldEncryptionUseCase.Reencrypt] " + err.Error())
+		return res, err
+	}
+
+	count, err = uc.Repository.ReencryptDocumentSendings(batchSize)
+	res["document_sendings"] = count
+	if err != nil {
+		uc.Log.Error("[FieldEncryptionUseCase.Reencrypt] " + err.Error())
+		return res, err
+	}
+
+	return res, nil
+}
@@ -17,7 +17,7 @@ import (
 )
 
 type IInterviewUseCase interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.InterviewResponse, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.InterviewResponse, int64, error)
 	CreateInterview(req *request.CreateInterviewRequest) (*response.InterviewResponse, error)
 	UpdateInterview(req *request.UpdateInterviewRequest) (*response.InterviewResponse, error)
 	FindByID(id uuid.UUID) (*response.InterviewResponse, error)
@@ -43,6 +43,7 @@ type InterviewUseCase struct {
 	InterviewAssessorRepository        repository.IInterviewAssessorRepository
 	InterviewApplicantRepository       repository.IInterviewApplicantRepository
 	ApplicantRepository                repository.IApplicantRepository
+	ScheduleReminderRepository         repository.IScheduleReminderRepository
 }
 
 func NewInterviewUseCase(
@@ -58,6 +59,7 @@ func NewInterviewUseCase(
 	interviewAssessorRepository repository.IInterviewAssessorRepository,
 	interviewApplicantRepository repository.IInterviewApplicantRepository,
 	applicantRepository repository.IApplicantRepository,
+	scheduleReminderRepository repository.IScheduleReminderRepository,
 ) IInterviewUseCase {
 	return &InterviewUseCase{
 		Log:                                log,
@@ -72,6 +74,7 @@ func NewInterviewUseCase(
 		InterviewAssessorRepository:        interviewAssessorRepository,
 		InterviewApplicantRepository:       interviewApplicantRepository,
 		ApplicantRepository:                applicantRepository,
+		ScheduleReminderRepository:         scheduleReminderRepository,
 	}
 }
 
@@ -89,6 +92,7 @@ func InterviewUseCaseFactory(
 	interviewAssessorRepository := repository.InterviewAssessorRepositoryFactory(log)
 	interviewApplicantRepository := repository.InterviewApplicantRepositoryFactory(log)
 	applicantRepository := repository.ApplicantRepositoryFactory(log)
+	scheduleReminderRepository := repository.ScheduleReminderRepositoryFactory(log)
 	return NewInterviewUseCase(
 		log,
 		interviewRepository,
@@ -102,11 +106,12 @@ func InterviewUseCaseFactory(
 		interviewAssessorRepository,
 		interviewApplicantRepository,
 		applicantRepository,
+		scheduleReminderRepository,
 	)
 }
 
-func (uc *InterviewUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.InterviewResponse, int64, error) {
-	interviews, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort)
+func (uc *InterviewUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.InterviewResponse, int64, error) {
+	interviews, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, orgScope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -525,6 +530,8 @@ func (uc *InterviewUseCase) UpdateInterview(req *request.UpdateInterviewRequest)
 		}
 	}
 
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_INTERVIEW, interview.ID)
+
 	findByID, err := uc.Repository.FindByID(interview.ID)
 	if err != nil {
 		uc.Log.Error("[InterviewUseCase.UpdateInterviewRequest] " + err.Error())
@@ -578,6 +585,7 @@ func (uc *InterviewUseCase) DeleteByID(id uuid.UUID) error {
 		uc.Log.Error("[InterviewUseCase.DeleteByID] " + err.Error())
 		return err
 	}
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_INTERVIEW, id)
 
 	return nil
 }
@@ -620,6 +628,7 @@ func (uc *InterviewUseCase) UpdateStatusInterview(req *request.UpdateStatusInter
 		uc.Log.Error("[InterviewUseCase.UpdateStatusInterviewRequest] " + err.Error())
 		return nil, err
 	}
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_INTERVIEW, parsedID)
 
 	resp, err := uc.DTO.ConvertEntityToResponse(interview)
 	if err != nil {
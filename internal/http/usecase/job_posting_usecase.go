@@ -3,6 +3,7 @@ package usecase
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
@@ -12,6 +13,7 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -21,7 +23,7 @@ type IJobPostingUseCase interface {
 	CreateJobPosting(req *request.CreateJobPostingRequest) (*response.JobPostingResponse, error)
 	FindAllPaginatedWithoutUserID(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.JobPostingResponse, int64, error)
 	FindAllPaginatedWithoutUserIDShowOnly(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.JobPostingResponse, int64, error)
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, userID uuid.UUID) (*[]response.JobPostingResponse, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, userID uuid.UUID, orgScope *repository.OrganizationScope) (*[]response.JobPostingResponse, int64, error)
 	FindAllPaginatedShowOnly(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, userID uuid.UUID, userMajors []string, educationLevels []string) (*[]response.JobPostingResponse, int64, error)
 	FindByID(id uuid.UUID, userID uuid.UUID) (*response.JobPostingResponse, error)
 	UpdateJobPosting(req *request.UpdateJobPostingRequest) (*response.JobPostingResponse, error)
@@ -168,7 +170,7 @@ func (uc *JobPostingUseCase) CreateJobPosting(req *request.CreateJobPostingReque
 		return nil, fmt.Errorf("Start Date or End Date is not in the range of Project Recruitment Header[Start Date: %v, End Date: %v]", prh.StartDate, prh.EndDate)
 	}
 
-	jobPosting, err := uc.Repository.CreateJobPosting(&entity.JobPosting{
+	jobPosting, err := createJobPostingWithSlug(uc.Repository, &entity.JobPosting{
 		ProjectRecruitmentHeaderID: prhID,
 		MPRequestID:                &mpRequestID,
 		ForOrganizationID:          data["forOrgID"].(*uuid.UUID),
@@ -235,18 +237,16 @@ func (uc *JobPostingUseCase) CreateJobPosting(req *request.CreateJobPostingReque
 }
 
 func (uc *JobPostingUseCase) FindAllPaginatedWithoutUserID(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]response.JobPostingResponse, int64, error) {
-	jobPostings, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter)
+	jobPostings, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter, nil)
 	if err != nil {
 		uc.Log.Error("[JobPostingUseCase.FindAllPaginatedWithoutUserID] " + err.Error())
 		return nil, 0, err
 	}
 
-	jobPostingResponses := make([]response.JobPostingResponse, 0)
-	for _, jobPosting := range *jobPostings {
-		totalApplicant := len(jobPosting.Applicants)
-		jobPosting.TotalApplicant = totalApplicant
-		jobPostingResponses = append(jobPostingResponses, *uc.DTO.ConvertEntityToResponse(&jobPosting))
+	for i := range *jobPostings {
+		(*jobPostings)[i].TotalApplicant = len((*jobPostings)[i].Applicants)
 	}
+	jobPostingResponses := uc.DTO.ConvertEntitiesToResponses(*jobPostings)
 
 	return &jobPostingResponses, total, nil
 }
@@ -258,18 +258,16 @@ func (uc *JobPostingUseCase) FindAllPaginatedWithoutUserIDShowOnly(page, pageSiz
 		return nil, 0, err
 	}
 
-	jobPostingResponses := make([]response.JobPostingResponse, 0)
-	for _, jobPosting := range *jobPostings {
-		totalApplicant := len(jobPosting.Applicants)
-		jobPosting.TotalApplicant = totalApplicant
-		jobPostingResponses = append(jobPostingResponses, *uc.DTO.ConvertEntityToResponse(&jobPosting))
+	for i := range *jobPostings {
+		(*jobPostings)[i].TotalApplicant = len((*jobPostings)[i].Applicants)
 	}
+	jobPostingResponses := uc.DTO.ConvertEntitiesToResponses(*jobPostings)
 
 	return &jobPostingResponses, total, nil
 }
 
-func (uc *JobPostingUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, userID uuid.UUID) (*[]response.JobPostingResponse, int64, error) {
-	jobPostings, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter)
+func (uc *JobPostingUseCase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, userID uuid.UUID, orgScope *repository.OrganizationScope) (*[]response.JobPostingResponse, int64, error) {
+	jobPostings, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, filter, orgScope)
 	if err != nil {
 		uc.Log.Error("[JobPostingUseCase.FindAllPaginated] " + err.Error())
 		return nil, 0, err
@@ -283,6 +281,7 @@ func (uc *JobPostingUseCase) FindAllPaginated(page, pageSize int, search string,
 
 	jobPostingResponses := make([]response.JobPostingResponse, 0)
 	if userProfile != nil {
+		uc.DTO.PrefetchLookups(*jobPostings)
 		for _, jobPosting := range *jobPostings {
 			applicant, err := uc.ApplicantRepository.FindByKeys(map[string]interface{}{
 				"job_posting_id":  jobPosting.ID,
@@ -320,11 +319,10 @@ func (uc *JobPostingUseCase) FindAllPaginated(page, pageSize int, search string,
 			jobPostingResponses = append(jobPostingResponses, *uc.DTO.ConvertEntityToResponse(&jobPosting))
 		}
 	} else {
-		for _, jobPosting := range *jobPostings {
-			totalApplicant := len(jobPosting.Applicants)
-			jobPosting.TotalApplicant = totalApplicant
-			jobPostingResponses = append(jobPostingResponses, *uc.DTO.ConvertEntityToResponse(&jobPosting))
+		for i := range *jobPostings {
+			(*jobPostings)[i].TotalApplicant = len((*jobPostings)[i].Applicants)
 		}
+		jobPostingResponses = uc.DTO.ConvertEntitiesToResponses(*jobPostings)
 	}
 
 	return &jobPostingResponses, total, nil
@@ -351,9 +349,7 @@ func (uc *JobPostingUseCase) FindAllPaginatedShowOnly(page, pageSize int, search
 		jumlah = total
 
 		uc.Log.Info("Jumlah: ", jumlah)
-		for _, jobPosting := range *jobPostings {
-			jobPostingResponses = append(jobPostingResponses, *uc.DTO.ConvertEntityToResponse(&jobPosting))
-		}
+		jobPostingResponses = uc.DTO.ConvertEntitiesToResponses(*jobPostings)
 		return &jobPostingResponses, jumlah, nil
 	}
 
@@ -383,6 +379,7 @@ func (uc *JobPostingUseCase) FindAllPaginatedShowOnly(page, pageSize int, search
 		}
 		jumlah = total
 
+		uc.DTO.PrefetchLookups(*jobPostings)
 		for _, jobPosting := range *jobPostings {
 			applicant, err := uc.ApplicantRepository.FindByKeys(map[string]interface{}{
 				"job_posting_id":  jobPosting.ID,
@@ -431,6 +428,7 @@ func (uc *JobPostingUseCase) FindAllPaginatedShowOnly(page, pageSize int, search
 
 		uc.Log.Info("Jumlah: ", jumlah)
 
+		uc.DTO.PrefetchLookups(*jobPostings)
 		for _, jobPosting := range *jobPostings {
 			applicant, err := uc.ApplicantRepository.FindByKeys(map[string]interface{}{
 				"job_posting_id":  jobPosting.ID,
@@ -829,10 +827,7 @@ func (uc *JobPostingUseCase) FindAllSavedJobsByUserID(userID uuid.UUID, page, pa
 		return nil, 0, err
 	}
 
-	jobPostingResponses := make([]response.JobPostingResponse, 0)
-	for _, jobPosting := range *jobPostings {
-		jobPostingResponses = append(jobPostingResponses, *uc.DTO.ConvertEntityToResponse(&jobPosting))
-	}
+	jobPostingResponses := uc.DTO.ConvertEntitiesToResponses(*jobPostings)
 
 	return &jobPostingResponses, total, nil
 }
@@ -888,10 +883,93 @@ func (uc *JobPostingUseCase) FindAllByProjectRecruitmentHeaderID(prhID uuid.UUID
 		return nil, err
 	}
 
-	jobPostingResponses := make([]response.JobPostingResponse, 0)
-	for _, jobPosting := range *jobPostings {
-		jobPostingResponses = append(jobPostingResponses, *uc.DTO.ConvertEntityToResponse(&jobPosting))
-	}
+	jobPostingResponses := uc.DTO.ConvertEntitiesToResponses(*jobPostings)
 
 	return &jobPostingResponses, nil
 }
+
+// JOB_POSTING_SLUG_ATTEMPTS is how often a slug is picked again when a concurrent write took it
+const JOB_POSTING_SLUG_ATTEMPTS = 5
+
+// createJobPostingWithSlug creates the posting under a free slug. Postings created at the same
+// time can pick the same one, the unique index then rejects all but one and the others retry
+// with the next suffix.
+func createJobPostingWithSlug(repo repository.IJobPostingRepository, ent *entity.JobPosting) (*entity.JobPosting, error) {
+	for attempt := 1; ; attempt++ {
+		slug, err := uniqueJobPostingSlug(repo, ent.Name, ent.DocumentNumber)
+		if err != nil {
+			return nil, err
+		}
+		ent.Slug = slug
+
+		created, err := repo.CreateJobPosting(ent)
+		if errors.Is(err, repository.ErrJobPostingSlugTaken) && attempt < JOB_POSTING_SLUG_ATTEMPTS {
+			continue
+		}
+		return created, err
+	}
+}
+
+// assignJobPostingSlug gives a posting created before slugs existed one, retrying like
+// createJobPostingWithSlug
+func assignJobPostingSlug(repo repository.IJobPostingRepository, ent *entity.JobPosting) error {
+	for attempt := 1; ; attempt++ {
+		slug, err := uniqueJobPostingSlug(repo, ent.Name, ent.DocumentNumber)
+		if err != nil {
+			return err
+		}
+
+		err = repo.UpdateSlug(ent.ID, slug)
+		if errors.Is(err, repository.ErrJobPostingSlugTaken) && attempt < JOB_POSTING_SLUG_ATTEMPTS {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		ent.Slug = slug
+		return nil
+	}
+}
+
+// BackfillJobPostingSlugs gives every posting without a slug one, it is run by the migration.
+// Postings that fail are logged and left for the next run.
+func BackfillJobPostingSlugs(log *logrus.Logger, repo repository.IJobPostingRepository) (int, error) {
+	jobPostings, err := repo.FindAllWithoutSlug()
+	if err != nil {
+		return 0, err
+	}
+
+	filled := 0
+	for i := range *jobPostings {
+		if err := assignJobPostingSlug(repo, &(*jobPostings)[i]); err != nil {
+			log.Errorf("[BackfillJobPostingSlugs] job posting %s: %v", (*jobPostings)[i].ID, err)
+			continue
+		}
+		filled++
+	}
+	return filled, nil
+}
+
+// uniqueJobPostingSlug builds the public slug of a posting from its name, or its document number
+// when it has none. Slugs never change afterwards so shared links keep working.
+func uniqueJobPostingSlug(repo repository.IJobPostingRepository, name string, documentNumber string) (string, error) {
+	base := utils.Slugify(name)
+	if base == "" {
+		base = utils.Slugify(documentNumber)
+	}
+	if base == "" {
+		base = "job"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := repo.SlugExists(slug)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
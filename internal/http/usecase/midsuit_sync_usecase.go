@@ -0,0 +1,1325 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type IMidsuitSyncUseCase interface {
+	EnqueueMidsuitSync(documentSendingID uuid.UUID) (*response.MidsuitSyncJobResponse, error)
+	FindByDocumentSendingID(documentSendingID uuid.UUID) (*response.MidsuitSyncJobResponse, error)
+	FindAllPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]response.MidsuitSyncJobResponse, int64, error)
+	RerunByDocumentSendingID(documentSendingID uuid.UUID) (*response.MidsuitSyncJobResponse, error)
+	RunMidsuitSyncJob(jobID uuid.UUID) error
+	RunDueMidsuitSyncJobs() error
+	StartMidsuitSyncWorker()
+	PreviewMidsuitSync(documentSendingID uuid.UUID) (*response.MidsuitSyncPreviewResponse, error)
+	ReconcileMidsuitEmployees() (*response.MidsuitReconciliationReportResponse, error)
+}
+
+type MidsuitSyncUseCase struct {
+	Log                       *logrus.Logger
+	Viper                     *viper.Viper
+	Repository                repository.IMidsuitSyncJobRepository
+	DTO                       dto.IMidsuitSyncJobDTO
+	DocumentSendingRepository repository.IDocumentSendingRepository
+	ApplicantRepository       repository.IApplicantRepository
+	JobPostingRepository      repository.IJobPostingRepository
+	UserProfileRepository     repository.IUserProfileRepository
+	UserHelper                helper.IUserHelper
+	UserMessage               messaging.IUserMessage
+	EmployeeMessage           messaging.IEmployeeMessage
+	OrganizationMessage       messaging.IOrganizationMessage
+	JobPlafonMessage          messaging.IJobPlafonMessage
+	GradeMessage              messaging.IGradeMessage
+	MPRequestMessage          messaging.IMPRequestMessage
+	MPRequestService          service.IMPRequestService
+	MidsuitService            service.IMidsuitService
+}
+
+func NewMidsuitSyncUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IMidsuitSyncJobRepository,
+	msjDTO dto.IMidsuitSyncJobDTO,
+	documentSendingRepository repository.IDocumentSendingRepository,
+	applicantRepository repository.IApplicantRepository,
+	jobPostingRepository repository.IJobPostingRepository,
+	userProfileRepository repository.IUserProfileRepository,
+	userHelper helper.IUserHelper,
+	userMessage messaging.IUserMessage,
+	employeeMessage messaging.IEmployeeMessage,
+	organizationMessage messaging.IOrganizationMessage,
+	jobPlafonMessage messaging.IJobPlafonMessage,
+	gradeMessage messaging.IGradeMessage,
+	mpRequestMessage messaging.IMPRequestMessage,
+	mpRequestService service.IMPRequestService,
+	midsuitService service.IMidsuitService,
+) IMidsuitSyncUseCase {
+	return &MidsuitSyncUseCase{
+		Log:                       log,
+		Viper:                     viper,
+		Repository:                repo,
+		DTO:                       msjDTO,
+		DocumentSendingRepository: documentSendingRepository,
+		ApplicantRepository:       applicantRepository,
+		JobPostingRepository:      jobPostingRepository,
+		UserProfileRepository:     userProfileRepository,
+		UserHelper:                userHelper,
+		UserMessage:               userMessage,
+		EmployeeMessage:           employeeMessage,
+		OrganizationMessage:       organizationMessage,
+		JobPlafonMessage:          jobPlafonMessage,
+		GradeMessage:              gradeMessage,
+		MPRequestMessage:          mpRequestMessage,
+		MPRequestService:          mpRequestService,
+		MidsuitService:            midsuitService,
+	}
+}
+
+func MidsuitSyncUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IMidsuitSyncUseCase {
+	repo := repository.MidsuitSyncJobRepositoryFactory(log)
+	msjDTO := dto.MidsuitSyncJobDTOFactory(log)
+	documentSendingRepository := repository.DocumentSendingRepositoryFactory(log)
+	applicantRepository := repository.ApplicantRepositoryFactory(log)
+	jobPostingRepository := repository.JobPostingRepositoryFactory(log)
+	userProfileRepository := repository.UserProfileRepositoryFactory(log)
+	userHelper := helper.UserHelperFactory(log)
+	userMessage := messaging.UserMessageFactory(log)
+	employeeMessage := messaging.EmployeeMessageFactory(log)
+	organizationMessage := messaging.OrganizationMessageFactory(log)
+	jobPlafonMessage := messaging.JobPlafonMessageFactory(log)
+	gradeMessage := messaging.GradeMessageFactory(log)
+	mpRequestMessage := messaging.MPRequestMessageFactory(log)
+	mpRequestService := service.MPRequestServiceFactory(log)
+	midsuitService := service.MidsuitServiceFactory(viper, log)
+	return NewMidsuitSyncUseCase(
+		log,
+		viper,
+		repo,
+		msjDTO,
+		documentSendingRepository,
+		applicantRepository,
+		jobPostingRepository,
+		userProfileRepository,
+		userHelper,
+		userMessage,
+		employeeMessage,
+		organizationMessage,
+		jobPlafonMessage,
+		gradeMessage,
+		mpRequestMessage,
+		mpRequestService,
+		midsuitService,
+	)
+}
+
+// midsuitSyncContext holds everything resolved from our database and the other
+// julong services that the Midsuit payloads are built from.
+type midsuitSyncContext struct {
+	SyncJob              *entity.MidsuitSyncJob
+	DocumentSending      *entity.DocumentSending
+	Applicant            *entity.Applicant
+	JobPosting           *entity.JobPosting
+	UserEmail            string
+	EmployeeID           uuid.UUID
+	Employee             *response.EmployeeResponse
+	Organization         *response.SendFindOrganizationByIDMessageResponse
+	EmployeeOrganization *response.SendFindOrganizationByIDMessageResponse
+	EmployeeOrgStructure *response.SendFindOrganizationStructureByIDMessageResponse
+	OrgStructure         *response.SendFindOrganizationStructureByIDMessageResponse
+	ForOrganization      *response.SendFindOrganizationByIDMessageResponse
+	OrganizationLocation *response.SendFindOrganizationLocationByIDMessageResponse
+	Job                  *response.SendFindJobByIDMessageResponse
+	JobLevel             *response.SendFindJobLevelByIDMessageResponse
+	GradeMidsuitID       *string
+	AllowanceApprover    *response.EmployeeResponse
+	EducationLevel       string
+	RecruitmentTypeID    int
+	Token                string
+	MidsuitEmployeeID    string
+	UserMidsuitID        string
+	Warnings             []string
+}
+
+type midsuitAllowance struct {
+	Name   string
+	TypeID string
+	Amount float64
+	Action string
+}
+
+func midsuitAllowances(syncCtx *midsuitSyncContext) []midsuitAllowance {
+	return []midsuitAllowance{
+		{"allowance_operational", "OPR", syncCtx.DocumentSending.OperationalAllowance, "CO"},
+		{"allowance_meal", "MEL", syncCtx.DocumentSending.MealAllowance, ""},
+		{"allowance_house", "HOS", syncCtx.DocumentSending.HouseAllowance, "CO"},
+	}
+}
+
+type midsuitSyncStepDefinition struct {
+	Name string
+	// Run performs the remote call and returns the id of the Midsuit record
+	Run func(syncCtx *midsuitSyncContext) (string, error)
+	// Apply restores the result of an already completed step into the context
+	Apply func(syncCtx *midsuitSyncContext, recordID string)
+}
+
+func midsuitSyncIdempotencyKey(documentSendingID uuid.UUID) string {
+	return "midsuit-sync:" + documentSendingID.String()
+}
+
+func (uc *MidsuitSyncUseCase) EnqueueMidsuitSync(documentSendingID uuid.UUID) (*response.MidsuitSyncJobResponse, error) {
+	job, err := uc.Repository.FindByDocumentSendingID(documentSendingID)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.EnqueueMidsuitSync] " + err.Error())
+		return nil, err
+	}
+
+	// a DocumentSending is only ever synced by one job, its steps remember what
+	// has already been pushed
+	if job != nil {
+		if job.Status == entity.MIDSUIT_SYNC_STATUS_FAILED {
+			return uc.RerunByDocumentSendingID(documentSendingID)
+		}
+		return uc.DTO.ConvertEntityToResponse(job), nil
+	}
+
+	documentSending, err := uc.DocumentSendingRepository.FindByID(documentSendingID)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.EnqueueMidsuitSync] " + err.Error())
+		return nil, err
+	}
+	if documentSending == nil {
+		return nil, errors.New("document sending not found")
+	}
+
+	job, err = uc.Repository.CreateMidsuitSyncJob(&entity.MidsuitSyncJob{
+		DocumentSendingID: documentSending.ID,
+		ApplicantID:       documentSending.ApplicantID,
+		IdempotencyKey:    midsuitSyncIdempotencyKey(documentSending.ID),
+		Status:            entity.MIDSUIT_SYNC_STATUS_PENDING,
+		MaxAttempts:       uc.maxAttempts(),
+	})
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.EnqueueMidsuitSync] " + err.Error())
+		return nil, err
+	}
+
+	go func(jobID uuid.UUID) {
+		if err := uc.RunMidsuitSyncJob(jobID); err != nil {
+			uc.Log.Error("[MidsuitSyncUseCase.EnqueueMidsuitSync] " + err.Error())
+		}
+	}(job.ID)
+
+	return uc.DTO.ConvertEntityToResponse(job), nil
+}
+
+func (uc *MidsuitSyncUseCase) FindByDocumentSendingID(documentSendingID uuid.UUID) (*response.MidsuitSyncJobResponse, error) {
+	job, err := uc.Repository.FindByDocumentSendingID(documentSendingID)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.FindByDocumentSendingID] " + err.Error())
+		return nil, err
+	}
+	if job == nil {
+		return nil, nil
+	}
+
+	return uc.DTO.ConvertEntityToResponse(job), nil
+}
+
+func (uc *MidsuitSyncUseCase) FindAllPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]response.MidsuitSyncJobResponse, int64, error) {
+	jobs, total, err := uc.Repository.FindAllPaginated(page, pageSize, sort, filter)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.FindAllPaginated] " + err.Error())
+		return nil, 0, err
+	}
+
+	jobResponses := make([]response.MidsuitSyncJobResponse, 0)
+	for _, job := range *jobs {
+		jobResponses = append(jobResponses, *uc.DTO.ConvertEntityToResponse(&job))
+	}
+
+	return &jobResponses, total, nil
+}
+
+// RerunByDocumentSendingID gives a failed job a fresh set of attempts and runs
+// it right away. Steps that already completed are skipped.
+func (uc *MidsuitSyncUseCase) RerunByDocumentSendingID(documentSendingID uuid.UUID) (*response.MidsuitSyncJobResponse, error) {
+	job, err := uc.Repository.FindByDocumentSendingID(documentSendingID)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.RerunByDocumentSendingID] " + err.Error())
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.New("midsuit sync job not found")
+	}
+	if job.Status == entity.MIDSUIT_SYNC_STATUS_COMPLETED {
+		return nil, errors.New("midsuit sync job is already completed")
+	}
+	if job.Status == entity.MIDSUIT_SYNC_STATUS_RUNNING {
+		return nil, errors.New("midsuit sync job is still running")
+	}
+
+	if err := uc.Repository.UpdateMidsuitSyncJobFields(job.ID, map[string]interface{}{
+		"status":       entity.MIDSUIT_SYNC_STATUS_PENDING,
+		"attempts":     0,
+		"max_attempts": uc.maxAttempts(),
+		"next_run_at":  nil,
+	}); err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.RerunByDocumentSendingID] " + err.Error())
+		return nil, err
+	}
+
+	if err := uc.RunMidsuitSyncJob(job.ID); err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.RerunByDocumentSendingID] " + err.Error())
+	}
+
+	return uc.FindByDocumentSendingID(documentSendingID)
+}
+
+// RunMidsuitSyncJob runs every step that has not completed yet. A failing step
+// stops the job; it is retried later with exponential backoff until
+// MaxAttempts is reached, after which it stays FAILED until rerun by hand.
+func (uc *MidsuitSyncUseCase) RunMidsuitSyncJob(jobID uuid.UUID) error {
+	claimed, err := uc.Repository.ClaimMidsuitSyncJob(jobID, time.Now().Add(-uc.staleAfter()))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		uc.Log.Infof("[MidsuitSyncUseCase.RunMidsuitSyncJob] job %s is not runnable, skipping", jobID)
+		return nil
+	}
+
+	job, err := uc.Repository.FindByID(jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return errors.New("midsuit sync job not found")
+	}
+
+	runErr := uc.runSteps(job)
+	if runErr == nil {
+		now := time.Now()
+		return uc.Repository.UpdateMidsuitSyncJobFields(job.ID, map[string]interface{}{
+			"status":      entity.MIDSUIT_SYNC_STATUS_COMPLETED,
+			"finished_at": now,
+			"next_run_at": nil,
+			"last_error":  "",
+		})
+	}
+
+	uc.Log.Errorf("[MidsuitSyncUseCase.RunMidsuitSyncJob] job %s attempt %d failed: %s", job.ID, job.Attempts, runErr.Error())
+	fields := map[string]interface{}{
+		"last_error": runErr.Error(),
+	}
+	if job.Attempts >= job.MaxAttempts {
+		now := time.Now()
+		fields["status"] = entity.MIDSUIT_SYNC_STATUS_FAILED
+		fields["finished_at"] = now
+		fields["next_run_at"] = nil
+	} else {
+		fields["status"] = entity.MIDSUIT_SYNC_STATUS_PENDING
+		fields["next_run_at"] = time.Now().Add(uc.backoff(job.Attempts))
+	}
+	if err := uc.Repository.UpdateMidsuitSyncJobFields(job.ID, fields); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+func (uc *MidsuitSyncUseCase) RunDueMidsuitSyncJobs() error {
+	now := time.Now()
+	jobs, err := uc.Repository.FindAllDue(now, now.Add(-uc.staleAfter()), 10)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.RunDueMidsuitSyncJobs] " + err.Error())
+		return err
+	}
+
+	for _, job := range *jobs {
+		if err := uc.RunMidsuitSyncJob(job.ID); err != nil {
+			uc.Log.Error("[MidsuitSyncUseCase.RunDueMidsuitSyncJobs] " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// StartMidsuitSyncWorker polls for due jobs until the process exits.
+func (uc *MidsuitSyncUseCase) StartMidsuitSyncWorker() {
+	interval := uc.Viper.GetInt("midsuit.sync_job.poll_interval")
+	if interval <= 0 {
+		interval = 30 // Default to 30 seconds
+	}
+
+	uc.Log.Infof("[MidsuitSyncUseCase.StartMidsuitSyncWorker] polling every %d seconds", interval)
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := uc.RunDueMidsuitSyncJobs(); err != nil {
+			uc.Log.Error("[MidsuitSyncUseCase.StartMidsuitSyncWorker] " + err.Error())
+		}
+	}
+}
+
+// PreviewMidsuitSync builds every payload the sync job would send for a
+// DocumentSending, without sending anything.
+func (uc *MidsuitSyncUseCase) PreviewMidsuitSync(documentSendingID uuid.UUID) (*response.MidsuitSyncPreviewResponse, error) {
+	syncCtx, err := uc.resolveMidsuitSyncContext(documentSendingID, true)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.PreviewMidsuitSync] " + err.Error())
+		return nil, err
+	}
+
+	job, err := uc.Repository.FindByDocumentSendingID(documentSendingID)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.PreviewMidsuitSync] " + err.Error())
+		return nil, err
+	}
+	if job != nil && job.MidsuitEmployeeID != "" {
+		syncCtx.MidsuitEmployeeID = job.MidsuitEmployeeID
+	}
+
+	// the token is only used to read the recruitment types
+	if err := uc.authorizeMidsuitSyncContext(syncCtx); err != nil {
+		syncCtx.Warnings = append(syncCtx.Warnings, "recruitment type could not be resolved from Midsuit: "+err.Error())
+	} else if syncCtx.RecruitmentTypeID == 0 {
+		syncCtx.Warnings = append(syncCtx.Warnings, "recruitment type "+string(syncCtx.DocumentSending.RecruitmentType)+" has no Midsuit recruitment type")
+	}
+	if syncCtx.MidsuitEmployeeID == "" {
+		syncCtx.Warnings = append(syncCtx.Warnings, "employee does not exist in Midsuit yet, HC_Employee_ID is filled in once the employee is synced")
+	}
+
+	ready := syncCtx.RecruitmentTypeID != 0
+	lookups := uc.midsuitSyncLookups(syncCtx)
+	for _, lookup := range lookups {
+		if !lookup.Resolved {
+			ready = false
+			syncCtx.Warnings = append(syncCtx.Warnings, lookup.Field+" "+lookup.Name+" has no valid Midsuit ID")
+		}
+	}
+
+	preview := &response.MidsuitSyncPreviewResponse{
+		DocumentSendingID: documentSendingID,
+		Ready:             ready,
+		Warnings:          syncCtx.Warnings,
+		Lookups:           lookups,
+		Employee:          uc.employeePayload(syncCtx),
+		EmployeeJob:       uc.employeeJobPayload(syncCtx),
+		WorkExperiences:   make([]request.SyncEmployeeWorkExperienceMidsuitRequest, 0),
+		Educations:        make([]request.SyncEmployeeEducationMidsuitRequest, 0),
+		Allowances:        make([]request.SyncEmployeeAllowanceMidsuitRequest, 0),
+	}
+	if job != nil {
+		preview.Job = uc.DTO.ConvertEntityToResponse(job)
+	}
+
+	for _, workExperience := range syncCtx.Applicant.UserProfile.WorkExperiences {
+		preview.WorkExperiences = append(preview.WorkExperiences, uc.workExperiencePayload(syncCtx, workExperience))
+	}
+	for _, education := range syncCtx.Applicant.UserProfile.Educations {
+		preview.Educations = append(preview.Educations, uc.educationPayload(syncCtx, education))
+	}
+	for _, allowance := range midsuitAllowances(syncCtx) {
+		preview.Allowances = append(preview.Allowances, uc.allowancePayload(syncCtx, allowance.TypeID, allowance.Amount, allowance.Action))
+	}
+
+	return preview, nil
+}
+
+// ReconcileMidsuitEmployees compares every hired applicant with the employee
+// Midsuit holds for them and reports where the two have drifted apart.
+func (uc *MidsuitSyncUseCase) ReconcileMidsuitEmployees() (*response.MidsuitReconciliationReportResponse, error) {
+	userProfiles, err := uc.UserProfileRepository.FindAllHired()
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.ReconcileMidsuitEmployees] " + err.Error())
+		return nil, err
+	}
+
+	completedJobs, err := uc.Repository.FindAllByStatus(entity.MIDSUIT_SYNC_STATUS_COMPLETED)
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.ReconcileMidsuitEmployees] " + err.Error())
+		return nil, err
+	}
+	jobMidsuitIDs := make(map[uuid.UUID]string)
+	for _, job := range *completedJobs {
+		jobMidsuitIDs[job.ApplicantID] = job.MidsuitEmployeeID
+	}
+
+	authResp, err := uc.MidsuitService.AuthOneStep()
+	if err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.ReconcileMidsuitEmployees] " + err.Error())
+		return nil, err
+	}
+
+	report := &response.MidsuitReconciliationReportResponse{
+		CheckedAt: time.Now(),
+		Drifts:    make([]response.MidsuitReconciliationDriftResponse, 0),
+	}
+
+	for _, userProfile := range *userProfiles {
+		report.Checked++
+		drift := response.MidsuitReconciliationDriftResponse{
+			UserProfileID: userProfile.ID,
+			Name:          userProfile.Name,
+		}
+
+		if userProfile.MidsuitID == nil || *userProfile.MidsuitID == "" {
+			drift.Kind = "MISSING_MIDSUIT_ID"
+			drift.Message = "hired applicant has no Midsuit ID"
+			report.Drifts = append(report.Drifts, drift)
+			continue
+		}
+		drift.MidsuitID = *userProfile.MidsuitID
+
+		for _, applicant := range userProfile.Applicants {
+			jobMidsuitID, ok := jobMidsuitIDs[applicant.ID]
+			if ok && jobMidsuitID != "" && jobMidsuitID != drift.MidsuitID {
+				drift.Kind = "SYNC_JOB_MISMATCH"
+				drift.Message = "sync job created Midsuit employee " + jobMidsuitID + " but the profile points to " + drift.MidsuitID
+				break
+			}
+		}
+		if drift.Kind != "" {
+			report.Drifts = append(report.Drifts, drift)
+			continue
+		}
+
+		midsuitID, err := strconv.Atoi(drift.MidsuitID)
+		if err != nil {
+			drift.Kind = "INVALID_MIDSUIT_ID"
+			drift.Message = "Midsuit ID is not a number"
+			report.Drifts = append(report.Drifts, drift)
+			continue
+		}
+
+		employee, err := uc.MidsuitService.FindEmployeeMidsuit(midsuitID, authResp.Token)
+		if err != nil {
+			drift.Kind = "ERROR"
+			drift.Message = err.Error()
+			report.Drifts = append(report.Drifts, drift)
+			continue
+		}
+		if employee == nil {
+			drift.Kind = "NOT_FOUND_IN_MIDSUIT"
+			drift.Message = "Midsuit has no employee with this ID"
+			report.Drifts = append(report.Drifts, drift)
+			continue
+		}
+
+		drift.Fields = compareMidsuitEmployee(userProfile, employee)
+		if len(drift.Fields) > 0 {
+			drift.Kind = "FIELD_MISMATCH"
+			drift.Message = "employee data differs from Midsuit"
+			report.Drifts = append(report.Drifts, drift)
+			continue
+		}
+
+		report.InSync++
+	}
+
+	return report, nil
+}
+
+func compareMidsuitEmployee(userProfile entity.UserProfile, employee *service.EmployeeMidsuitResponse) []response.MidsuitFieldDriftResponse {
+	fields := make([]response.MidsuitFieldDriftResponse, 0)
+	compare := func(field, local, midsuit string) {
+		if !strings.EqualFold(strings.TrimSpace(local), strings.TrimSpace(midsuit)) {
+			fields = append(fields, response.MidsuitFieldDriftResponse{
+				Field:   field,
+				Local:   local,
+				Midsuit: midsuit,
+			})
+		}
+	}
+
+	compare("name", userProfile.Name, employee.Name)
+	compare("birth_date", userProfile.BirthDate.Format("2006-01-02"), strings.Split(employee.Birthday, "T")[0])
+	compare("birth_place", userProfile.BirthPlace, employee.City)
+	compare("ktp", userProfile.Ktp, employee.HcNationalID1)
+
+	gender := "F"
+	if userProfile.Gender == entity.MALE {
+		gender = "M"
+	}
+	midsuitGender := ""
+	if employee.HcGender != nil {
+		midsuitGender, _ = employee.HcGender.ID.(string)
+	}
+	compare("gender", gender, midsuitGender)
+
+	return fields
+}
+
+func (uc *MidsuitSyncUseCase) midsuitSyncLookups(syncCtx *midsuitSyncContext) []response.MidsuitLookupResponse {
+	lookup := func(field, id, name, midsuitID string) response.MidsuitLookupResponse {
+		_, err := strconv.Atoi(midsuitID)
+		return response.MidsuitLookupResponse{
+			Field:     field,
+			ID:        id,
+			Name:      name,
+			MidsuitID: midsuitID,
+			Resolved:  err == nil,
+		}
+	}
+
+	documentSending := syncCtx.DocumentSending
+	lookups := []response.MidsuitLookupResponse{
+		lookup("organization", syncCtx.Organization.OrganizationID, syncCtx.Organization.Name, syncCtx.Organization.MidsuitID),
+		lookup("organization_structure", syncCtx.OrgStructure.OrganizationStructureID, syncCtx.OrgStructure.Name, syncCtx.OrgStructure.MidsuitID),
+		lookup("for_organization", documentSending.ForOrganizationID.String(), syncCtx.ForOrganization.Name, syncCtx.ForOrganization.MidsuitID),
+		lookup("organization_location", documentSending.OrganizationLocationID.String(), syncCtx.OrganizationLocation.Name, syncCtx.OrganizationLocation.MidsuitID),
+		lookup("job", syncCtx.JobPosting.JobID.String(), syncCtx.Job.Name, syncCtx.Job.MidsuitID),
+		lookup("job_level", documentSending.JobLevelID.String(), syncCtx.JobLevel.Name, syncCtx.JobLevel.MidsuitID),
+		lookup("allowance_approver", documentSending.AllowanceApproval.String(), syncCtx.AllowanceApprover.Name, syncCtx.AllowanceApprover.MidsuitID),
+	}
+	if documentSending.GradeID != nil && syncCtx.GradeMidsuitID != nil {
+		lookups = append(lookups, lookup("grade", documentSending.GradeID.String(), "", *syncCtx.GradeMidsuitID))
+	}
+
+	return lookups
+}
+
+func (uc *MidsuitSyncUseCase) maxAttempts() int {
+	maxAttempts := uc.Viper.GetInt("midsuit.sync_job.max_attempts")
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	return maxAttempts
+}
+
+func (uc *MidsuitSyncUseCase) staleAfter() time.Duration {
+	staleAfter := uc.Viper.GetInt("midsuit.sync_job.stale_after")
+	if staleAfter <= 0 {
+		staleAfter = 900 // Default to 15 minutes (in seconds)
+	}
+	return time.Duration(staleAfter) * time.Second
+}
+
+func (uc *MidsuitSyncUseCase) backoff(attempts int) time.Duration {
+	base := uc.Viper.GetInt("midsuit.sync_job.backoff")
+	if base <= 0 {
+		base = 60 // Default to 1 minute (in seconds)
+	}
+	maxBackoff := uc.Viper.GetInt("midsuit.sync_job.max_backoff")
+	if maxBackoff <= 0 {
+		maxBackoff = 3600 // Default to 1 hour (in seconds)
+	}
+
+	seconds := float64(base) * math.Pow(2, float64(attempts-1))
+	if seconds > float64(maxBackoff) {
+		seconds = float64(maxBackoff)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (uc *MidsuitSyncUseCase) runSteps(job *entity.MidsuitSyncJob) error {
+	syncCtx, err := uc.resolveMidsuitSyncContext(job.DocumentSendingID, false)
+	if err != nil {
+		return err
+	}
+	if err := uc.authorizeMidsuitSyncContext(syncCtx); err != nil {
+		return err
+	}
+	syncCtx.SyncJob = job
+
+	if job.EmployeeID == nil {
+		if err := uc.Repository.UpdateMidsuitSyncJobFields(job.ID, map[string]interface{}{
+			"employee_id": syncCtx.EmployeeID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for i, definition := range uc.midsuitSyncSteps(syncCtx) {
+		key := job.IdempotencyKey + ":" + definition.Name
+		step, err := uc.Repository.FindStepByIdempotencyKey(key)
+		if err != nil {
+			return err
+		}
+
+		if step != nil && step.Status == entity.MIDSUIT_SYNC_STATUS_COMPLETED {
+			if definition.Apply != nil {
+				definition.Apply(syncCtx, step.MidsuitRecordID)
+			}
+			continue
+		}
+
+		if step == nil {
+			step, err = uc.Repository.CreateMidsuitSyncStep(&entity.MidsuitSyncStep{
+				MidsuitSyncJobID: job.ID,
+				Name:             definition.Name,
+				Order:            i + 1,
+				IdempotencyKey:   key,
+				Status:           entity.MIDSUIT_SYNC_STATUS_PENDING,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		recordID, runErr := definition.Run(syncCtx)
+		if runErr != nil {
+			if err := uc.Repository.UpdateMidsuitSyncStepFields(step.ID, map[string]interface{}{
+				"status":     entity.MIDSUIT_SYNC_STATUS_FAILED,
+				"attempts":   step.Attempts + 1,
+				"last_error": runErr.Error(),
+			}); err != nil {
+				uc.Log.Error("[MidsuitSyncUseCase.runSteps] " + err.Error())
+			}
+			return errors.New("step " + definition.Name + ": " + runErr.Error())
+		}
+
+		now := time.Now()
+		if err := uc.Repository.UpdateMidsuitSyncStepFields(step.ID, map[string]interface{}{
+			"status":            entity.MIDSUIT_SYNC_STATUS_COMPLETED,
+			"attempts":          step.Attempts + 1,
+			"last_error":        "",
+			"midsuit_record_id": recordID,
+			"completed_at":      now,
+		}); err != nil {
+			return err
+		}
+		if definition.Apply != nil {
+			definition.Apply(syncCtx, recordID)
+		}
+	}
+
+	return uc.Repository.UpdateMidsuitSyncJobFields(job.ID, map[string]interface{}{
+		"midsuit_employee_id": syncCtx.MidsuitEmployeeID,
+	})
+}
+
+// resolveMidsuitSyncContext gathers the data of a hired applicant without
+// talking to Midsuit. A preview tolerates an applicant that is not hired yet.
+func (uc *MidsuitSyncUseCase) resolveMidsuitSyncContext(documentSendingID uuid.UUID, preview bool) (*midsuitSyncContext, error) {
+	documentSending, err := uc.DocumentSendingRepository.FindByID(documentSendingID)
+	if err != nil {
+		return nil, err
+	}
+	if documentSending == nil {
+		return nil, errors.New("document sending not found")
+	}
+
+	applicant, err := uc.ApplicantRepository.FindByKeys(map[string]interface{}{
+		"id": documentSending.ApplicantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if applicant == nil || applicant.UserProfile == nil {
+		return nil, errors.New("applicant not found")
+	}
+	if len(applicant.UserProfile.Educations) == 0 {
+		return nil, errors.New("applicant has no education")
+	}
+
+	eduLevel := strings.TrimSpace(string(applicant.UserProfile.Educations[0].EducationLevel))
+	if eduLevel != "S3" && eduLevel != "S2" && eduLevel != "S1" && eduLevel != "D4" && eduLevel != "D3" && eduLevel != "D2" && eduLevel != "D1" && eduLevel != "SMA" && eduLevel != "SMP" && eduLevel != "SD" && eduLevel != "TS" {
+		return nil, errors.New("education level not found")
+	}
+
+	jobPosting, err := uc.JobPostingRepository.FindByID(documentSending.JobPostingID)
+	if err != nil {
+		return nil, err
+	}
+	if jobPosting == nil || jobPosting.MPRequest == nil {
+		return nil, errors.New("job posting not found")
+	}
+	if documentSending.JobLevelID == nil || documentSending.ForOrganizationID == nil || documentSending.OrganizationLocationID == nil || documentSending.JoinedDate == nil {
+		return nil, errors.New("document sending is missing job level, organization, location or joined date")
+	}
+	if documentSending.AllowanceApproval == nil {
+		return nil, errors.New("document sending has no allowance approval")
+	}
+
+	userMessageResponse, err := uc.UserMessage.SendGetUserMe(request.SendFindUserByIDMessageRequest{
+		ID: applicant.UserProfile.UserID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if userMessageResponse.User == nil {
+		return nil, errors.New("user not found")
+	}
+
+	userEmail, err := uc.UserHelper.GetUserEmail(userMessageResponse.User)
+	if err != nil {
+		return nil, err
+	}
+
+	mprResp, err := uc.MPRequestMessage.SendFindByIdMessage(jobPosting.MPRequest.MPRCloneID.String())
+	if err != nil {
+		return nil, err
+	}
+	convertedData, err := uc.MPRequestService.CheckPortalData(mprResp)
+	if err != nil {
+		return nil, err
+	}
+
+	syncCtx := &midsuitSyncContext{
+		DocumentSending: documentSending,
+		Applicant:       applicant,
+		JobPosting:      jobPosting,
+		UserEmail:       userEmail,
+		EducationLevel:  eduLevel,
+	}
+
+	if err := uc.resolveMidsuitSyncEmployee(syncCtx, userMessageResponse.User); err != nil {
+		if !preview {
+			return nil, err
+		}
+
+		// before hiring there is no employee yet, it will be created in the
+		// organization of the MP request
+		syncCtx.Warnings = append(syncCtx.Warnings, "employee is not created yet: "+err.Error())
+		syncCtx.Organization, err = uc.OrganizationMessage.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{
+			ID: convertedData.OrganizationID.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	orgStructure, err := uc.OrganizationMessage.SendFindOrganizationStructureByIDMessage(request.SendFindOrganizationStructureByIDMessageRequest{
+		ID: convertedData.ForOrganizationStructureID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	forOrganization, err := uc.OrganizationMessage.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{
+		ID: documentSending.ForOrganizationID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	organizationLocation, err := uc.OrganizationMessage.SendFindOrganizationLocationByIDMessage(request.SendFindOrganizationLocationByIDMessageRequest{
+		ID: documentSending.OrganizationLocationID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := uc.JobPlafonMessage.SendFindJobByIDMessage(request.SendFindJobByIDMessageRequest{
+		ID: jobPosting.JobID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobLevel, err := uc.JobPlafonMessage.SendFindJobLevelByIDMessage(request.SendFindJobLevelByIDMessageRequest{
+		ID: documentSending.JobLevelID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var gradeMidsuitID *string
+	if documentSending.GradeID != nil {
+		grade, err := uc.GradeMessage.SendFindByIDMessage(documentSending.GradeID.String())
+		if err != nil {
+			return nil, err
+		}
+		if grade == nil {
+			return nil, errors.New("grade not found")
+		}
+		gradeMidsuitID = &grade.MidsuitID
+	}
+
+	allowanceApprover, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
+		ID: documentSending.AllowanceApproval.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if allowanceApprover == nil {
+		return nil, errors.New("allowance approver not found")
+	}
+
+	syncCtx.OrgStructure = orgStructure
+	syncCtx.ForOrganization = forOrganization
+	syncCtx.OrganizationLocation = organizationLocation
+	syncCtx.Job = job
+	syncCtx.JobLevel = jobLevel
+	syncCtx.GradeMidsuitID = gradeMidsuitID
+	syncCtx.AllowanceApprover = allowanceApprover
+
+	return syncCtx, nil
+}
+
+// resolveMidsuitSyncEmployee looks up the employee created for the applicant
+// when they were hired, together with its organization and structure.
+func (uc *MidsuitSyncUseCase) resolveMidsuitSyncEmployee(syncCtx *midsuitSyncContext, user map[string]interface{}) error {
+	employeeID, err := uc.UserHelper.GetEmployeeId(user)
+	if err != nil {
+		return err
+	}
+	organizationID, err := uc.UserHelper.GetOrganizationID(user)
+	if err != nil {
+		return err
+	}
+
+	organization, err := uc.OrganizationMessage.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{
+		ID: organizationID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	employee, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
+		ID: employeeID.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if employee == nil {
+		return errors.New("employee not found")
+	}
+
+	employeeOrganization, err := uc.OrganizationMessage.SendFindOrganizationByIDMessage(request.SendFindOrganizationByIDMessageRequest{
+		ID: employee.OrganizationID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	employeeOrgStructureID, _ := employee.EmployeeJob["organization_structure_id"].(string)
+	employeeOrgStructure, err := uc.OrganizationMessage.SendFindOrganizationStructureByIDMessage(request.SendFindOrganizationStructureByIDMessageRequest{
+		ID: employeeOrgStructureID,
+	})
+	if err != nil {
+		return err
+	}
+
+	syncCtx.EmployeeID = employeeID
+	syncCtx.Employee = employee
+	syncCtx.Organization = organization
+	syncCtx.EmployeeOrganization = employeeOrganization
+	syncCtx.EmployeeOrgStructure = employeeOrgStructure
+	syncCtx.MidsuitEmployeeID = employee.MidsuitID
+	return nil
+}
+
+// authorizeMidsuitSyncContext logs in to Midsuit and resolves the recruitment
+// type of the document sending.
+func (uc *MidsuitSyncUseCase) authorizeMidsuitSyncContext(syncCtx *midsuitSyncContext) error {
+	authResp, err := uc.MidsuitService.AuthOneStep()
+	if err != nil {
+		return err
+	}
+	syncCtx.Token = authResp.Token
+
+	var filter string
+	if syncCtx.DocumentSending.RecruitmentType == entity.PROJECT_RECRUITMENT_TYPE_MT {
+		filter = "MT"
+	} else if syncCtx.DocumentSending.RecruitmentType == entity.PROJECT_RECRUITMENT_TYPE_PH {
+		filter = "PH"
+	} else if syncCtx.DocumentSending.RecruitmentType == entity.PROJECT_RECRUITMENT_TYPE_NS {
+		filter = "NS"
+	}
+
+	recTypeResp, err := uc.MidsuitService.RecruitmentTypeMidsuitAPIWithoutFilter(authResp.Token)
+	if err != nil {
+		return err
+	}
+	if recTypeResp == nil {
+		return errors.New("recruitment type not found")
+	}
+
+	for _, recType := range recTypeResp.Records {
+		if recType.Value == filter {
+			syncCtx.RecruitmentTypeID = recType.ID
+			break
+		}
+	}
+
+	return nil
+}
+
+// recordedMidsuitEmployee returns the Midsuit employee an earlier run created and remembered on
+// the job, if Midsuit still has it
+func (uc *MidsuitSyncUseCase) recordedMidsuitEmployee(syncCtx *midsuitSyncContext) (string, error) {
+	if syncCtx.SyncJob == nil || syncCtx.SyncJob.MidsuitEmployeeID == "" {
+		return "", nil
+	}
+	midsuitID, err := strconv.Atoi(syncCtx.SyncJob.MidsuitEmployeeID)
+	if err != nil {
+		return "", nil
+	}
+	employee, err := uc.MidsuitService.FindEmployeeMidsuit(midsuitID, syncCtx.Token)
+	if err != nil {
+		return "", err
+	}
+	if employee == nil {
+		return "", nil
+	}
+	return syncCtx.SyncJob.MidsuitEmployeeID, nil
+}
+
+// rememberMidsuitEmployee saves a Midsuit employee as soon as it is created, on the job and on
+// the employee, so a rerun finds it even when the step itself could not be recorded. Failures
+// are only logged, the employee exists in Midsuit either way and employee_link links it later.
+func (uc *MidsuitSyncUseCase) rememberMidsuitEmployee(syncCtx *midsuitSyncContext, midsuitEmpID string) {
+	if syncCtx.SyncJob != nil {
+		if err := uc.Repository.UpdateMidsuitSyncJobFields(syncCtx.SyncJob.ID, map[string]interface{}{
+			"midsuit_employee_id": midsuitEmpID,
+		}); err != nil {
+			uc.Log.Error("[MidsuitSyncUseCase.rememberMidsuitEmployee] " + err.Error())
+		} else {
+			syncCtx.SyncJob.MidsuitEmployeeID = midsuitEmpID
+		}
+	}
+
+	if _, err := uc.EmployeeMessage.SendUpdateEmployeeMidsuitMessage(syncCtx.EmployeeID.String(), midsuitEmpID); err != nil {
+		uc.Log.Error("[MidsuitSyncUseCase.rememberMidsuitEmployee] " + err.Error())
+		return
+	}
+	syncCtx.Employee.MidsuitID = midsuitEmpID
+}
+
+func (uc *MidsuitSyncUseCase) midsuitSyncSteps(syncCtx *midsuitSyncContext) []midsuitSyncStepDefinition {
+	steps := []midsuitSyncStepDefinition{
+		{
+			Name: "employee",
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				// the employee may already exist in Midsuit from an earlier run
+				// whose result was never recorded, never create it twice
+				if syncCtx.Employee.MidsuitID != "" {
+					return syncCtx.Employee.MidsuitID, nil
+				}
+				if recorded, err := uc.recordedMidsuitEmployee(syncCtx); err != nil || recorded != "" {
+					return recorded, err
+				}
+				midsuitEmpID, err := uc.MidsuitService.SyncEmployeeMidsuit(uc.employeePayload(syncCtx), syncCtx.Token)
+				if err != nil {
+					return "", err
+				}
+				uc.rememberMidsuitEmployee(syncCtx, *midsuitEmpID)
+				return *midsuitEmpID, nil
+			},
+			Apply: func(syncCtx *midsuitSyncContext, recordID string) {
+				syncCtx.MidsuitEmployeeID = recordID
+			},
+		},
+		{
+			Name: "employee_link",
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				if syncCtx.Employee.MidsuitID == syncCtx.MidsuitEmployeeID {
+					return syncCtx.MidsuitEmployeeID, nil
+				}
+				_, err := uc.EmployeeMessage.SendUpdateEmployeeMidsuitMessage(syncCtx.EmployeeID.String(), syncCtx.MidsuitEmployeeID)
+				if err != nil {
+					return "", err
+				}
+				return syncCtx.MidsuitEmployeeID, nil
+			},
+		},
+		{
+			Name: "employee_job",
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				return uc.derefMidsuitID(uc.MidsuitService.SyncEmployeeJobMidsuit(uc.employeeJobPayload(syncCtx), syncCtx.Token))
+			},
+		},
+	}
+
+	for _, workExperience := range syncCtx.Applicant.UserProfile.WorkExperiences {
+		workExperience := workExperience
+		steps = append(steps, midsuitSyncStepDefinition{
+			Name: "work_experience:" + workExperience.ID.String(),
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				return uc.derefMidsuitID(uc.MidsuitService.SyncEmployeeWorkExperienceMidsuit(uc.workExperiencePayload(syncCtx, workExperience), syncCtx.Token))
+			},
+		})
+	}
+
+	for _, education := range syncCtx.Applicant.UserProfile.Educations {
+		education := education
+		steps = append(steps, midsuitSyncStepDefinition{
+			Name: "education:" + education.ID.String(),
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				return uc.derefMidsuitID(uc.MidsuitService.SyncEmployeeEducationMidsuit(uc.educationPayload(syncCtx, education), syncCtx.Token))
+			},
+		})
+	}
+
+	for _, allowance := range midsuitAllowances(syncCtx) {
+		allowance := allowance
+		steps = append(steps, midsuitSyncStepDefinition{
+			Name: allowance.Name,
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				return uc.derefMidsuitID(uc.MidsuitService.SyncEmployeeAllowanceMidsuit(uc.allowancePayload(syncCtx, allowance.TypeID, allowance.Amount, allowance.Action), syncCtx.Token))
+			},
+		})
+	}
+
+	steps = append(steps,
+		midsuitSyncStepDefinition{
+			Name: "generate_user",
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				midsuitEmpIDInt, err := strconv.Atoi(syncCtx.MidsuitEmployeeID)
+				if err != nil {
+					return "", errors.New("failed to convert midsuitEmpID to int: " + err.Error())
+				}
+				return uc.derefMidsuitID(uc.MidsuitService.SyncGenerateUserMidsuit(midsuitEmpIDInt, syncCtx.Token))
+			},
+			Apply: func(syncCtx *midsuitSyncContext, recordID string) {
+				syncCtx.UserMidsuitID = recordID
+			},
+		},
+		midsuitSyncStepDefinition{
+			Name: "user_profile_link",
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				_, err := uc.UserProfileRepository.UpdateUserProfile(&entity.UserProfile{
+					ID:        syncCtx.Applicant.UserProfile.ID,
+					MidsuitID: &syncCtx.UserMidsuitID,
+				})
+				if err != nil {
+					return "", err
+				}
+				return syncCtx.UserMidsuitID, nil
+			},
+		},
+		midsuitSyncStepDefinition{
+			Name: "employee_task",
+			Run: func(syncCtx *midsuitSyncContext) (string, error) {
+				_, err := uc.EmployeeMessage.SendCreateEmployeeTaskMessage(request.SendCreateEmployeeTaskMessageRequest{
+					EmployeeID:            syncCtx.EmployeeID.String(),
+					JoinedDate:            syncCtx.DocumentSending.JoinedDate.String(),
+					OrganizationType:      syncCtx.Organization.OrganizationType,
+					EmployeeMidsuitID:     syncCtx.MidsuitEmployeeID,
+					JobMidsuitID:          syncCtx.Job.MidsuitID,
+					JobLevelMidsuitID:     syncCtx.JobLevel.MidsuitID,
+					OrgMidsuitID:          syncCtx.EmployeeOrganization.MidsuitID,
+					OrgStructureMidsuitID: syncCtx.EmployeeOrgStructure.MidsuitID,
+				})
+				if err != nil {
+					return "", err
+				}
+				return "", nil
+			},
+		},
+	)
+
+	return steps
+}
+
+func (uc *MidsuitSyncUseCase) derefMidsuitID(id *string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if id == nil {
+		return "", nil
+	}
+	return *id, nil
+}
+
+// midsuitIDToInt converts a MidsuitID to the integer Midsuit expects. Unknown
+// or malformed ids become 0, as they always have.
+func (uc *MidsuitSyncUseCase) midsuitIDToInt(midsuitID string, field string) int {
+	id, err := strconv.Atoi(midsuitID)
+	if err != nil {
+		uc.Log.Errorf("[MidsuitSyncUseCase] failed to convert %s MidsuitID %q to int: %s", field, midsuitID, err.Error())
+		return 0
+	}
+	return id
+}
+
+func (uc *MidsuitSyncUseCase) employeePayload(syncCtx *midsuitSyncContext) request.SyncEmployeeMidsuitRequest {
+	userProfile := syncCtx.Applicant.UserProfile
+	return request.SyncEmployeeMidsuitRequest{
+		AdOrgId: request.AdOrgId{
+			ID:         uc.midsuitIDToInt(syncCtx.Organization.MidsuitID, "organization"),
+			Identifier: syncCtx.Organization.Name,
+		},
+		Name:     userProfile.Name,
+		Birthday: userProfile.BirthDate.Format("2006-01-02"),
+		City:     userProfile.BirthPlace,
+		Email:    syncCtx.UserEmail,
+		HcGender: func() request.HcGender {
+			if userProfile.Gender == entity.MALE {
+				return request.HcGender{
+					ID: "M",
+				}
+			}
+			return request.HcGender{
+				ID: "F",
+			}
+		}(),
+		HcMaritalStatus: request.HcMaritalStatus{
+			Identifier: strings.Title(string(userProfile.MaritalStatus)),
+		},
+		HcNationalID1: userProfile.Ktp,
+		HcReligionID: request.HcReligionId{
+			ID: 1000002,
+		},
+		HcStatus: request.HcStatus{
+			ID: "A",
+		},
+		HcBasicAcceptance: request.HcBasicAcceptance{
+			ID:         syncCtx.EducationLevel,
+			Identifier: syncCtx.EducationLevel,
+		},
+		HCWorkStartDate: syncCtx.DocumentSending.JoinedDate.Format("2006-01-02"),
+		HcRecruitmentTypeId: request.HcRecruitmentTypeId{
+			ID: syncCtx.RecruitmentTypeID,
+		},
+	}
+}
+
+func (uc *MidsuitSyncUseCase) employeeJobPayload(syncCtx *midsuitSyncContext) request.SyncEmployeeJobMidsuitRequest {
+	documentSending := syncCtx.DocumentSending
+	return request.SyncEmployeeJobMidsuitRequest{
+		AdOrgId: request.AdOrgId{
+			ID: uc.midsuitIDToInt(syncCtx.Organization.MidsuitID, "organization"),
+		},
+		HCCompensation1: int(documentSending.BasicWage),
+		HCEmployeeID: request.HcEmployeeId{
+			ID: uc.midsuitIDToInt(syncCtx.MidsuitEmployeeID, "employee"),
+		},
+		HCEmployeeCategoryID: func() *request.HcEmployeeCategoryId {
+			if documentSending.HiredStatus == "" {
+				return nil
+			}
+			return &request.HcEmployeeCategoryId{
+				Identifier: string(documentSending.HiredStatus),
+			}
+		}(),
+		HCJobID: request.HcJobId{
+			ID: uc.midsuitIDToInt(syncCtx.Job.MidsuitID, "job"),
+		},
+		HCJobLevelID: request.HcJobLevelId{
+			ID: uc.midsuitIDToInt(syncCtx.JobLevel.MidsuitID, "job level"),
+		},
+		HCOrgID: request.HcOrgId{
+			ID: uc.midsuitIDToInt(syncCtx.OrgStructure.MidsuitID, "organization structure"),
+		},
+		HCWorkStartDate: documentSending.JoinedDate.Format("2006-01-02"),
+		ADEmploymentOrgID: request.AdOrgId{
+			ID: uc.midsuitIDToInt(syncCtx.ForOrganization.MidsuitID, "for organization"),
+		},
+		HCWorkSiteID: request.HcWorkSiteId{
+			ID: uc.midsuitIDToInt(syncCtx.OrganizationLocation.MidsuitID, "organization location"),
+		},
+		IsPrimary: true,
+		HCEmployeeGradeID: request.HcEmployeeGradeId{
+			ID: func() int {
+				if syncCtx.GradeMidsuitID == nil {
+					return 0
+				}
+				return uc.midsuitIDToInt(*syncCtx.GradeMidsuitID, "grade")
+			}(),
+		},
+		ModelName: "hc_employeejob",
+	}
+}
+
+func (uc *MidsuitSyncUseCase) workExperiencePayload(syncCtx *midsuitSyncContext, workExperience entity.WorkExperience) request.SyncEmployeeWorkExperienceMidsuitRequest {
+	return request.SyncEmployeeWorkExperienceMidsuitRequest{
+		AdOrgId: request.AdOrgId{
+			ID: uc.midsuitIDToInt(syncCtx.Organization.MidsuitID, "organization"),
+		},
+		HCEmployeeID: request.HcEmployeeId{
+			ID: uc.midsuitIDToInt(syncCtx.MidsuitEmployeeID, "employee"),
+		},
+		Name:           workExperience.Name,
+		Description:    "Mwehehe",
+		YearExperience: strconv.Itoa(workExperience.YearExperience),
+		ModelName:      "hc_workhistory",
+	}
+}
+
+func (uc *MidsuitSyncUseCase) educationPayload(syncCtx *midsuitSyncContext, education entity.Education) request.SyncEmployeeEducationMidsuitRequest {
+	return request.SyncEmployeeEducationMidsuitRequest{
+		AdOrgId: request.AdOrgId{
+			ID: uc.midsuitIDToInt(syncCtx.Organization.MidsuitID, "organization"),
+		},
+		HCEmployeeID: request.HcEmployeeId{
+			ID: uc.midsuitIDToInt(syncCtx.MidsuitEmployeeID, "employee"),
+		},
+		BidangPendidikanAkhir: education.Major,
+		HcEducationInstitute:  education.SchoolName,
+		HcGpaScore: func() int {
+			if education.Gpa == nil {
+				return 0
+			}
+			return int(*education.Gpa)
+		}(),
+		SeqNo: 10,
+		HCBasicAcceptance: request.HcBasicAcceptance{
+			ID: strings.TrimSpace(string(education.EducationLevel)),
+		},
+		ModelName: "hc_employeeeducation",
+	}
+}
+
+func (uc *MidsuitSyncUseCase) allowancePayload(syncCtx *midsuitSyncContext, allowanceType string, amount float64, docAction string) request.SyncEmployeeAllowanceMidsuitRequest {
+	documentSending := syncCtx.DocumentSending
+
+	// allowances of employees joining on the 25th start in the next payroll period
+	joinedDate := *documentSending.JoinedDate
+	if joinedDate.Day() == 25 {
+		joinedDate = joinedDate.AddDate(0, 1, 0)
+	}
+
+	return request.SyncEmployeeAllowanceMidsuitRequest{
+		AdOrgId: request.AdOrgId{
+			ID: uc.midsuitIDToInt(syncCtx.Organization.MidsuitID, "organization"),
+		},
+		CDocTypeID: request.CDocTypeID{
+			Identifier: "Allowance Provision",
+			ModelName:  "c_doctype",
+		},
+		CPeriodID: request.CPeriodID{
+			Identifier: joinedDate.Format("Jan-06"),
+			ModelName:  "c_period",
+		},
+		DateDoc: documentSending.JoinedDate.Format("2006-01-02"),
+		HCEmployee2ID: request.HcEmployeeId{
+			ID: uc.midsuitIDToInt(syncCtx.MidsuitEmployeeID, "employee"),
+		},
+		HCJob2ID: request.HcJobId{
+			ID: uc.midsuitIDToInt(syncCtx.Job.MidsuitID, "job"),
+		},
+		HCEmployeeID: request.HcEmployeeId{
+			ID: uc.midsuitIDToInt(syncCtx.AllowanceApprover.MidsuitID, "allowance approver"),
+		},
+		HCOrg2ID: request.HcOrgId{
+			ID: uc.midsuitIDToInt(syncCtx.OrgStructure.MidsuitID, "organization structure"),
+		},
+		HCAllowanceType: request.HCAllowanceType{
+			ID: allowanceType,
+		},
+		Distance: 1,
+		Amount:   int(amount),
+		HCUOM: request.HCUOM{
+			ID: "MO",
+		},
+		IsUseDate: false,
+		HCProvisionType: request.HCProvisionType{
+			ID: "GRA",
+		},
+		IsGenerated: true,
+		ModelName:   "hc_allowanceprovision",
+		DocAction:   docAction,
+	}
+}
@@ -0,0 +1,445 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrPersonalDataProfileNotFound = errors.New("user profile not found")
+	ErrErasureRequestNotFound      = errors.New("erasure request not found")
+	ErrErasureRequestPending       = errors.New("an erasure request is already pending")
+	ErrErasureRequestReviewed      = errors.New("erasure request is already reviewed")
+)
+
+// IPersonalDataUseCase covers the rights of candidates over their personal data: consent to the
+// privacy notice, a copy of everything held on them and erasure
+type IPersonalDataUseCase interface {
+	Consent(userID uuid.UUID, version, ipAddress, userAgent string) (*response.PrivacyConsentResponse, error)
+	FindConsents(userID uuid.UUID) ([]response.PrivacyConsentResponse, error)
+	Export(userID uuid.UUID) ([]byte, error)
+	RequestErasure(userID uuid.UUID, reason string) (*response.ErasureRequestResponse, error)
+	FindLatestErasureRequest(userID uuid.UUID) (*response.ErasureRequestResponse, error)
+	FindErasureRequestsPaginated(page, pageSize int, status string) (*[]response.ErasureRequestResponse, int64, error)
+	ApproveErasureRequest(id uuid.UUID, reviewedBy uuid.UUID, note string) (*response.ErasureRequestResponse, error)
+	RejectErasureRequest(id uuid.UUID, reviewedBy uuid.UUID, note string) (*response.ErasureRequestResponse, error)
+}
+
+type PersonalDataUseCase struct {
+	Log                      *logrus.Logger
+	Viper                    *viper.Viper
+	Repository               repository.IPersonalDataRepository
+	UserProfileRepository    repository.IUserProfileRepository
+	PrivacyNoticeRepository  repository.IPrivacyNoticeRepository
+	ErasureRequestRepository repository.IErasureRequestRepository
+	UserProfileDTO           dto.IUserProfileDTO
+	PrivacyNoticeDTO         dto.IPrivacyNoticeDTO
+	ErasureRequestDTO        dto.IErasureRequestDTO
+	Storage                  storage.IStorage
+}
+
+func NewPersonalDataUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IPersonalDataRepository,
+	upRepository repository.IUserProfileRepository,
+	pnRepository repository.IPrivacyNoticeRepository,
+	erRepository repository.IErasureRequestRepository,
+	upDTO dto.IUserProfileDTO,
+	pnDTO dto.IPrivacyNoticeDTO,
+	erDTO dto.IErasureRequestDTO,
+	fileStorage storage.IStorage,
+) IPersonalDataUseCase {
+	return &PersonalDataUseCase{
+		Log:                      log,
+		Viper:                    viper,
+		Repository:               repo,
+		UserProfileRepository:    upRepository,
+		PrivacyNoticeRepository:  pnRepository,
+		ErasureRequestRepository: erRepository,
+		UserProfileDTO:           upDTO,
+		PrivacyNoticeDTO:         pnDTO,
+		ErasureRequestDTO:        erDTO,
+		Storage:                  fileStorage,
+	}
+}
+
+func PersonalDataUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IPersonalDataUseCase {
+	repo := repository.PersonalDataRepositoryFactory(log)
+	upRepository := repository.UserProfileRepositoryFactory(log)
+	pnRepository := repository.PrivacyNoticeRepositoryFactory(log)
+	erRepository := repository.ErasureRequestRepositoryFactory(log)
+	upDTO := dto.UserProfileDTOFactory(log, viper)
+	pnDTO := dto.PrivacyNoticeDTOFactory(log)
+	erDTO := dto.ErasureRequestDTOFactory(log)
+	fileStorage := storage.StorageFactory(viper, log)
+	return NewPersonalDataUseCase(log, viper, repo, upRepository, pnRepository, erRepository, upDTO, pnDTO, erDTO, fileStorage)
+}
+
+// Consent records the agreement of the candidate to the current privacy notice, used when a
+// new version is published after the profile was created
+func (uc *PersonalDataUseCase) Consent(userID uuid.UUID, version, ipAddress, userAgent string) (*response.PrivacyConsentResponse, error) {
+	profile, err := uc.findProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	notice, err := uc.PrivacyNoticeRepository.FindCurrent()
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.Consent] " + err.Error())
+		return nil, err
+	}
+	if notice == nil {
+		return nil, ErrPrivacyNoticeNotFound
+	}
+	if notice.Version != version {
+		return nil, ErrPrivacyNoticeVersionOutdated
+	}
+
+	consent, err := uc.PrivacyNoticeRepository.CreateConsent(&entity.PrivacyConsent{
+		UserProfileID:   profile.ID,
+		PrivacyNoticeID: notice.ID,
+		NoticeVersion:   notice.Version,
+		ConsentedAt:     time.Now(),
+		IPAddress:       ipAddress,
+		UserAgent:       userAgent,
+	})
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.Consent] " + err.Error())
+		return nil, err
+	}
+
+	return uc.PrivacyNoticeDTO.ConvertConsentToResponse(consent), nil
+}
+
+func (uc *PersonalDataUseCase) FindConsents(userID uuid.UUID) ([]response.PrivacyConsentResponse, error) {
+	profile, err := uc.findProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := uc.PrivacyNoticeRepository.FindConsentsByUserProfileID(profile.ID)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.FindConsents] " + err.Error())
+		return nil, err
+	}
+
+	res := make([]response.PrivacyConsentResponse, 0, len(consents))
+	for i := range consents {
+		res = append(res, *uc.PrivacyNoticeDTO.ConvertConsentToResponse(&consents[i]))
+	}
+
+	return res, nil
+}
+
+// Export returns a ZIP with the profile, the applications, the answers and the consents as JSON
+// and every stored file of the candidate under documents/
+func (uc *PersonalDataUseCase) Export(userID uuid.UUID) ([]byte, error) {
+	found, err := uc.findProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.Repository.FindExportProfile(found.ID)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrPersonalDataProfileNotFound
+	}
+	files, err := uc.Repository.FindFiles(profile.ID)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+		return nil, err
+	}
+	consents, err := uc.FindConsents(userID)
+	if err != nil {
+		return nil, err
+	}
+	referralEntities, err := uc.Repository.FindReferrals(profile.ID)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+		return nil, err
+	}
+
+	profileRes, err := uc.UserProfileDTO.ConvertEntityToResponseWithoutUser(profile)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+		return nil, err
+	}
+
+	applications := make([]response.PersonalDataApplicationResponse, 0, len(profile.Applicants))
+	for _, applicant := range profile.Applicants {
+		application := response.PersonalDataApplicationResponse{
+			ID:            applicant.ID,
+			JobPostingID:  applicant.JobPostingID,
+			AppliedDate:   applicant.AppliedDate,
+			Status:        applicant.Status,
+			ProcessStatus: applicant.ProcessStatus,
+			HiredStatus:   applicant.HiredStatus,
+		}
+		if applicant.JobPosting != nil {
+			application.JobPostingName = applicant.JobPosting.Name
+		}
+		applications = append(applications, application)
+	}
+
+	answers := make([]response.PersonalDataAnswerResponse, 0, len(profile.QuestionResponses))
+	for _, questionResponse := range profile.QuestionResponses {
+		answer := response.PersonalDataAnswerResponse{
+			ID:           questionResponse.ID,
+			JobPostingID: questionResponse.JobPostingID,
+			Answer:       questionResponse.Answer,
+			AnswerFile:   questionResponse.AnswerFile,
+		}
+		if questionResponse.Question != nil {
+			answer.Question = questionResponse.Question.Name
+		}
+		if questionResponse.JobPosting != nil {
+			answer.JobPostingName = questionResponse.JobPosting.Name
+		}
+		answers = append(answers, answer)
+	}
+
+	referrals := make([]response.PersonalDataReferralResponse, 0, len(referralEntities))
+	for _, referral := range referralEntities {
+		item := response.PersonalDataReferralResponse{
+			ID:             referral.ID,
+			JobPostingID:   referral.JobPostingID,
+			CandidateName:  referral.CandidateName,
+			CandidateEmail: referral.CandidateEmail,
+			CandidatePhone: referral.CandidatePhone,
+			Note:           referral.Note,
+			Status:         referral.Status,
+			CreatedAt:      referral.CreatedAt,
+		}
+		if referral.JobPosting != nil {
+			item.JobPostingName = referral.JobPosting.Name
+		}
+		referrals = append(referrals, item)
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	// the same file can be referenced twice, e.g. the CV of the profile and of a CV draft
+	documents := make([]response.PersonalDataFileResponse, 0, len(files))
+	archived := map[string]string{}
+	for i, file := range files {
+		document := response.PersonalDataFileResponse{Category: file.Category, Label: file.Label}
+		if name, ok := archived[file.Path]; ok {
+			document.File = name
+		} else if data, err := storage.ReadFile(uc.Storage, file.Path); err == nil {
+			name := fmt.Sprintf("documents/%s/%d_%s", file.Category, i+1, filepath.Base(file.Path))
+			if err := writeZipFile(archive, name, data); err != nil {
+				uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+				return nil, err
+			}
+			archived[file.Path] = name
+			document.File = name
+		} else {
+			uc.Log.Warnf("[PersonalDataUseCase.Export] file %s of user profile %s cannot be read: %v", file.Path, profile.ID, err)
+		}
+		documents = append(documents, document)
+	}
+
+	for _, part := range []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", profileRes},
+		{"applications.json", applications},
+		{"answers.json", answers},
+		{"referrals.json", referrals},
+		{"consents.json", consents},
+		{"documents.json", documents},
+	} {
+		data, err := json.MarshalIndent(part.content, "", "  ")
+		if err != nil {
+			uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+			return nil, err
+		}
+		if err := writeZipFile(archive, part.name, data); err != nil {
+			uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		uc.Log.Error("[PersonalDataUseCase.Export] " + err.Error())
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (uc *PersonalDataUseCase) RequestErasure(userID uuid.UUID, reason string) (*response.ErasureRequestResponse, error) {
+	profile, err := uc.findProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := uc.ErasureRequestRepository.FindLatestByUserProfileID(profile.ID)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.RequestErasure] " + err.Error())
+		return nil, err
+	}
+	if latest != nil && latest.Status == entity.ERASURE_REQUEST_STATUS_PENDING {
+		return nil, ErrErasureRequestPending
+	}
+
+	request, err := uc.ErasureRequestRepository.CreateErasureRequest(&entity.ErasureRequest{
+		UserProfileID: profile.ID,
+		Status:        entity.ERASURE_REQUEST_STATUS_PENDING,
+		Reason:        reason,
+		RequestedAt:   time.Now(),
+	})
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.RequestErasure] " + err.Error())
+		return nil, err
+	}
+
+	return uc.ErasureRequestDTO.ConvertEntityToResponse(request), nil
+}
+
+func (uc *PersonalDataUseCase) FindLatestErasureRequest(userID uuid.UUID) (*response.ErasureRequestResponse, error) {
+	profile, err := uc.findProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := uc.ErasureRequestRepository.FindLatestByUserProfileID(profile.ID)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.FindLatestErasureRequest] " + err.Error())
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrErasureRequestNotFound
+	}
+
+	return uc.ErasureRequestDTO.ConvertEntityToResponse(request), nil
+}
+
+func (uc *PersonalDataUseCase) FindErasureRequestsPaginated(page, pageSize int, status string) (*[]response.ErasureRequestResponse, int64, error) {
+	requests, total, err := uc.ErasureRequestRepository.FindAllPaginated(page, pageSize, status)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.FindErasureRequestsPaginated] " + err.Error())
+		return nil, 0, err
+	}
+
+	res := make([]response.ErasureRequestResponse, 0, len(*requests))
+	for i := range *requests {
+		res = append(res, *uc.ErasureRequestDTO.ConvertEntityToResponse(&(*requests)[i]))
+	}
+
+	return &res, total, nil
+}
+
+// ApproveErasureRequest anonymises the profile and deletes its stored files
+func (uc *PersonalDataUseCase) ApproveErasureRequest(id uuid.UUID, reviewedBy uuid.UUID, note string) (*response.ErasureRequestResponse, error) {
+	request, err := uc.findPendingErasureRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := uc.Repository.Anonymise(request.ID, request.UserProfileID, reviewedBy, note)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.ApproveErasureRequest] " + err.Error())
+		return nil, err
+	}
+
+	// the data is already unreachable, a file that cannot be removed is recorded on the request
+	// so it can be removed by hand
+	failed := make([]string, 0)
+	for _, file := range files {
+		if err := uc.Storage.Delete(file.Path); err != nil {
+			uc.Log.Errorf("[PersonalDataUseCase.ApproveErasureRequest] failed to remove %s: %v", file.Path, err)
+			failed = append(failed, file.Path)
+		}
+	}
+	if len(failed) > 0 {
+		if err := uc.ErasureRequestRepository.UpdateFailedFiles(request.ID, failed); err != nil {
+			uc.Log.Error("[PersonalDataUseCase.ApproveErasureRequest] " + err.Error())
+			return nil, err
+		}
+	}
+
+	return uc.findErasureRequest(id)
+}
+
+func (uc *PersonalDataUseCase) RejectErasureRequest(id uuid.UUID, reviewedBy uuid.UUID, note string) (*response.ErasureRequestResponse, error) {
+	request, err := uc.findPendingErasureRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.ErasureRequestRepository.Reject(request.ID, reviewedBy, note); err != nil {
+		uc.Log.Error("[PersonalDataUseCase.RejectErasureRequest] " + err.Error())
+		return nil, err
+	}
+
+	return uc.findErasureRequest(id)
+}
+
+func (uc *PersonalDataUseCase) findProfile(userID uuid.UUID) (*entity.UserProfile, error) {
+	profile, err := uc.UserProfileRepository.FindByUserID(userID)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.findProfile] " + err.Error())
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrPersonalDataProfileNotFound
+	}
+	return profile, nil
+}
+
+func (uc *PersonalDataUseCase) findPendingErasureRequest(id uuid.UUID) (*entity.ErasureRequest, error) {
+	request, err := uc.ErasureRequestRepository.FindByID(id)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.findPendingErasureRequest] " + err.Error())
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrErasureRequestNotFound
+	}
+	if request.Status != entity.ERASURE_REQUEST_STATUS_PENDING {
+		return nil, ErrErasureRequestReviewed
+	}
+	return request, nil
+}
+
+func (uc *PersonalDataUseCase) findErasureRequest(id uuid.UUID) (*response.ErasureRequestResponse, error) {
+	request, err := uc.ErasureRequestRepository.FindByID(id)
+	if err != nil {
+		uc.Log.Error("[PersonalDataUseCase.findErasureRequest] " + err.Error())
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrErasureRequestNotFound
+	}
+	return uc.ErasureRequestDTO.ConvertEntityToResponse(request), nil
+}
+
+func writeZipFile(archive *zip.Writer, name string, data []byte) error {
+	writer, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
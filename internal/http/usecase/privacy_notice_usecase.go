@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrPrivacyNoticeNotFound        = errors.New("privacy notice not found")
+	ErrPrivacyNoticeVersionExists   = errors.New("privacy notice version already exists")
+	ErrPrivacyConsentRequired       = errors.New("consent to the current privacy notice is required")
+	ErrPrivacyNoticeVersionOutdated = errors.New("only the current privacy notice can be consented to")
+)
+
+type IPrivacyNoticeUseCase interface {
+	PublishPrivacyNotice(req *request.PublishPrivacyNoticeRequest, publishedBy uuid.UUID) (*response.PrivacyNoticeResponse, error)
+	FindCurrent() (*response.PrivacyNoticeResponse, error)
+	FindAll() ([]response.PrivacyNoticeResponse, error)
+}
+
+type PrivacyNoticeUseCase struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Repository repository.IPrivacyNoticeRepository
+	DTO        dto.IPrivacyNoticeDTO
+}
+
+func NewPrivacyNoticeUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IPrivacyNoticeRepository,
+	pnDTO dto.IPrivacyNoticeDTO,
+) IPrivacyNoticeUseCase {
+	return &PrivacyNoticeUseCase{
+		Log:        log,
+		Viper:      viper,
+		Repository: repo,
+		DTO:        pnDTO,
+	}
+}
+
+func PrivacyNoticeUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IPrivacyNoticeUseCase {
+	repo := repository.PrivacyNoticeRepositoryFactory(log)
+	pnDTO := dto.PrivacyNoticeDTOFactory(log)
+	return NewPrivacyNoticeUseCase(log, viper, repo, pnDTO)
+}
+
+// PublishPrivacyNotice publishes a new version, it becomes the current notice right away and
+// candidates creating a profile from then on consent to it
+func (uc *PrivacyNoticeUseCase) PublishPrivacyNotice(req *request.PublishPrivacyNoticeRequest, publishedBy uuid.UUID) (*response.PrivacyNoticeResponse, error) {
+	version := strings.TrimSpace(req.Version)
+	exist, err := uc.Repository.FindByVersion(version)
+	if err != nil {
+		uc.Log.Error("[PrivacyNoticeUseCase.PublishPrivacyNotice] " + err.Error())
+		return nil, err
+	}
+	if exist != nil {
+		return nil, ErrPrivacyNoticeVersionExists
+	}
+
+	notice, err := uc.Repository.CreatePrivacyNotice(&entity.PrivacyNotice{
+		Version:     version,
+		Title:       req.Title,
+		Content:     req.Content,
+		PublishedAt: time.Now(),
+		PublishedBy: publishedBy,
+	})
+	if err != nil {
+		uc.Log.Error("[PrivacyNoticeUseCase.PublishPrivacyNotice] " + err.Error())
+		return nil, err
+	}
+
+	return uc.DTO.ConvertEntityToResponse(notice), nil
+}
+
+func (uc *PrivacyNoticeUseCase) FindCurrent() (*response.PrivacyNoticeResponse, error) {
+	notice, err := uc.Repository.FindCurrent()
+	if err != nil {
+		uc.Log.Error("[PrivacyNoticeUseCase.FindCurrent] " + err.Error())
+		return nil, err
+	}
+	if notice == nil {
+		return nil, ErrPrivacyNoticeNotFound
+	}
+
+	return uc.DTO.ConvertEntityToResponse(notice), nil
+}
+
+func (uc *PrivacyNoticeUseCase) FindAll() ([]response.PrivacyNoticeResponse, error) {
+	notices, err := uc.Repository.FindAll()
+	if err != nil {
+		uc.Log.Error("[PrivacyNoticeUseCase.FindAll] " + err.Error())
+		return nil, err
+	}
+
+	res := make([]response.PrivacyNoticeResponse, 0, len(notices))
+	for i := range notices {
+		res = append(res, *uc.DTO.ConvertEntityToResponse(&notices[i]))
+	}
+
+	return res, nil
+}
@@ -130,6 +130,7 @@ func (uc *ProjectRecruitmentHeaderUseCase) FindAllPaginated(page, pageSize int,
 		return nil, 0, err
 	}
 
+	uc.DTO.PrefetchEmployees(*projectRecruitmentHeaders)
 	projectRecruitmentHeaderResponses := make([]response.ProjectRecruitmentHeaderResponse, 0)
 	for _, projectRecruitmentHeader := range *projectRecruitmentHeaders {
 		projectRecruitmentHeaderResponses = append(projectRecruitmentHeaderResponses, *uc.DTO.ConvertEntityToResponse(&projectRecruitmentHeader))
@@ -255,6 +256,7 @@ func (uc *ProjectRecruitmentHeaderUseCase) FindAllByEmployeeID(employeeID uuid.U
 		return nil, err
 	}
 
+	uc.DTO.PrefetchEmployees(*projectRecruitmentHeaders)
 	projectRecruitmentHeaderResponses := make([]response.ProjectRecruitmentHeaderResponse, 0)
 	for _, projectRecruitmentHeader := range *projectRecruitmentHeaders {
 		projectRecruitmentHeaderResponses = append(projectRecruitmentHeaderResponses, *uc.DTO.ConvertEntityToResponse(&projectRecruitmentHeader))
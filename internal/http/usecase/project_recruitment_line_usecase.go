@@ -262,6 +262,7 @@ func (uc *ProjectRecruitmentLineUseCase) GetAllByKeys(keys map[string]interface{
 		return nil, err
 	}
 
+	uc.DTO.PrefetchProjectPics(data)
 	responses := make([]*response.ProjectRecruitmentLineResponse, 0)
 	for _, d := range data {
 		responses = append(responses, uc.DTO.ConvertEntityToResponse(&d))
@@ -277,6 +278,7 @@ func (uc *ProjectRecruitmentLineUseCase) GetAllByKeysWithoutPic(keys map[string]
 		return nil, err
 	}
 
+	uc.DTO.PrefetchProjectPics(data)
 	responses := make([]*response.ProjectRecruitmentLineResponse, 0)
 	for _, d := range data {
 		responses = append(responses, uc.DTO.ConvertEntityToResponse(&d))
@@ -337,6 +339,7 @@ func (uc *ProjectRecruitmentLineUseCase) FindAllByFormType(formType entity.Templ
 		return nil, err
 	}
 
+	uc.DTO.PrefetchProjectPics(*data)
 	responses := make([]*response.ProjectRecruitmentLineResponse, 0)
 	for _, d := range *data {
 		responses = append(responses, uc.DTO.ConvertEntityToResponse(&d))
@@ -374,6 +377,7 @@ func (uc *ProjectRecruitmentLineUseCase) FindAllByProjectRecruitmentHeaderIDAndE
 		return nil, err
 	}
 
+	uc.DTO.PrefetchProjectPics(*data)
 	responses := make([]*response.ProjectRecruitmentLineResponse, 0)
 	for _, d := range *data {
 		responses = append(responses, uc.DTO.ConvertEntityToResponse(&d))
@@ -433,6 +437,7 @@ func (uc *ProjectRecruitmentLineUseCase) FindAllByHeaderID(headerID uuid.UUID) (
 		return nil, err
 	}
 
+	uc.DTO.PrefetchProjectPics(data)
 	responses := make([]response.ProjectRecruitmentLineResponse, 0)
 	for _, d := range data {
 		responses = append(responses, *uc.DTO.ConvertEntityToResponse(&d))
@@ -478,6 +483,7 @@ func (uc *ProjectRecruitmentLineUseCase) fetchDataForDay(date time.Time, employe
 		uc.Log.Errorf("[ProjectRecruitmentLineUseCase.fetchDataForDay] error when finding project recruitment lines by start date: %s", err.Error())
 		return nil, err
 	}
+	uc.DTO.PrefetchProjectPics(*projectRecruitmentLines)
 	var responses []*response.ProjectRecruitmentLineResponse
 	for _, prl := range *projectRecruitmentLines {
 		responses = append(responses, uc.DTO.ConvertEntityToResponse(&prl))
@@ -8,6 +8,7 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -17,6 +18,7 @@ type IQuestionResponseUseCase interface {
 	CreateOrUpdateQuestionResponses(req *request.QuestionResponseRequest) (*response.QuestionResponse, error)
 	AnswerInterviewQuestionResponses(req *request.InterviewQuestionResponseRequest) (*response.TemplateQuestionResponse, error)
 	AnswerFgdQuestionResponses(req *request.FgdQuestionResponseRequest) (*response.TemplateQuestionResponse, error)
+	FindUserProfileIDsByIDs(ids []string) ([]uuid.UUID, error)
 }
 
 type QuestionResponseUseCase struct {
@@ -218,7 +220,7 @@ func (uc *QuestionResponseUseCase) CreateOrUpdateQuestionResponses(req *request.
 	// embed url to answer file
 	for _, qr := range rQuestion.QuestionResponses {
 		if qr.AnswerFile != "" {
-			qr.AnswerFile = uc.Viper.GetString("app.url") + "/" + qr.AnswerFile
+			qr.AnswerFile = storage.URL(uc.Viper, qr.AnswerFile)
 			uc.Log.Infof("[QuestionResponseUseCase.CreateOrUpdateQuestionResponses] answer file url: %s", qr.AnswerFile)
 		}
 	}
@@ -647,3 +649,32 @@ func (uc *QuestionResponseUseCase) AnswerFgdQuestionResponses(req *request.FgdQu
 
 	return uc.TemplateQuestionDTO.ConvertEntityToResponse(tqRes), nil
 }
+
+// FindUserProfileIDsByIDs returns the owners of the given question responses
+func (uc *QuestionResponseUseCase) FindUserProfileIDsByIDs(ids []string) ([]uuid.UUID, error) {
+	var parsedIDs []uuid.UUID
+	for _, id := range ids {
+		parsedID, err := uuid.Parse(id)
+		if err != nil {
+			uc.Log.Error("[QuestionResponseUseCase.FindUserProfileIDsByIDs] " + err.Error())
+			return nil, errors.New("[QuestionResponseUseCase.FindUserProfileIDsByIDs] " + err.Error())
+		}
+		parsedIDs = append(parsedIDs, parsedID)
+	}
+	if len(parsedIDs) == 0 {
+		return nil, nil
+	}
+
+	questionResponses, err := uc.Repository.FindAllByIDs(parsedIDs)
+	if err != nil {
+		uc.Log.Error("[QuestionResponseUseCase.FindUserProfileIDsByIDs] " + err.Error())
+		return nil, errors.New("[QuestionResponseUseCase.FindUserProfileIDsByIDs] " + err.Error())
+	}
+
+	var userProfileIDs []uuid.UUID
+	for _, qr := range questionResponses {
+		userProfileIDs = append(userProfileIDs, qr.UserProfileID)
+	}
+
+	return userProfileIDs, nil
+}
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	FUNNEL_REASON_TYPE_REJECTED  = "REJECTED"
+	FUNNEL_REASON_TYPE_WITHDRAWN = "WITHDRAWN"
+)
+
+var (
+	ErrFunnelJobPostingNotFound               = errors.New("job posting not found")
+	ErrFunnelProjectRecruitmentHeaderNotFound = errors.New("project recruitment header not found")
+)
+
+type funnelReason struct {
+	Type   string
+	Reason string
+}
+
+// rejections at the offer and contract stages mean the candidate turned it down
+var funnelReasons = map[entity.TemplateQuestionFormType]funnelReason{
+	entity.TQ_FORM_TYPE_ADMINISTRATIVE_SELECTION: {FUNNEL_REASON_TYPE_REJECTED, "Did not pass the administrative selection"},
+	entity.TQ_FORM_TYPE_TEST:                     {FUNNEL_REASON_TYPE_REJECTED, "Did not pass the test"},
+	entity.TQ_FORM_TYPE_INTERVIEW:                {FUNNEL_REASON_TYPE_REJECTED, "Did not pass the interview"},
+	entity.TQ_FORM_TYPE_FINAL_INTERVIEW:          {FUNNEL_REASON_TYPE_REJECTED, "Did not pass the final interview"},
+	entity.TQ_FORM_TYPE_FGD:                      {FUNNEL_REASON_TYPE_REJECTED, "Did not pass the FGD"},
+	entity.TQ_FORM_TYPE_FINAL_RESULT:             {FUNNEL_REASON_TYPE_REJECTED, "Did not pass the final result"},
+	entity.TQ_FORM_TYPE_DOCUMENT_CHECKING:        {FUNNEL_REASON_TYPE_REJECTED, "Documents not accepted"},
+	entity.TQ_FORM_TYPE_SURAT_IZIN_ORTU:          {FUNNEL_REASON_TYPE_REJECTED, "Documents not accepted"},
+	entity.TQ_FORM_TYPE_OFFERING_LETTER:          {FUNNEL_REASON_TYPE_WITHDRAWN, "Declined the offering letter"},
+	entity.TQ_FORM_TYPE_CONTRACT_DOCUMENT:        {FUNNEL_REASON_TYPE_WITHDRAWN, "Declined the contract"},
+	entity.TQ_FORM_TYPE_KARYAWAN_TETAP:           {FUNNEL_REASON_TYPE_WITHDRAWN, "Declined the contract"},
+	entity.TQ_FORM_TYPE_PKWT:                     {FUNNEL_REASON_TYPE_WITHDRAWN, "Declined the contract"},
+	entity.TQ_FORM_TYPE_PKWTT:                    {FUNNEL_REASON_TYPE_WITHDRAWN, "Declined the contract"},
+}
+
+type IRecruitmentFunnelUseCase interface {
+	GetFunnel(req *request.RecruitmentFunnelRequest, orgScope *repository.OrganizationScope) (*response.RecruitmentFunnelResponse, error)
+}
+
+type RecruitmentFunnelUseCase struct {
+	Log                                *logrus.Logger
+	Viper                              *viper.Viper
+	Repository                         repository.IRecruitmentFunnelRepository
+	JobPostingRepository               repository.IJobPostingRepository
+	ProjectRecruitmentHeaderRepository repository.IProjectRecruitmentHeaderRepository
+}
+
+func NewRecruitmentFunnelUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IRecruitmentFunnelRepository,
+	jpRepository repository.IJobPostingRepository,
+	prhRepository repository.IProjectRecruitmentHeaderRepository,
+) IRecruitmentFunnelUseCase {
+	return &RecruitmentFunnelUseCase{
+		Log:                                log,
+		Viper:                              viper,
+		Repository:                         repo,
+		JobPostingRepository:               jpRepository,
+		ProjectRecruitmentHeaderRepository: prhRepository,
+	}
+}
+
+func RecruitmentFunnelUseCaseFactory(
+	log *logrus.Logger,
+	viper *viper.Viper,
+) IRecruitmentFunnelUseCase {
+	repo := repository.RecruitmentFunnelRepositoryFactory(log)
+	jpRepository := repository.JobPostingRepositoryFactory(log)
+	prhRepository := repository.ProjectRecruitmentHeaderRepositoryFactory(log)
+	return NewRecruitmentFunnelUseCase(log, viper, repo, jpRepository, prhRepository)
+}
+
+// applicantProgress is how far an applicant got, built from the stage history and, for
+// applicants from before the history was kept, from the applicant itself
+type applicantProgress struct {
+	MaxOrder      int
+	CurrentOrder  int
+	RejectedOrder int
+	Rejected      bool
+	Hired         bool
+}
+
+// GetFunnel computes the funnel of one job posting, or of every job posting of a project the
+// caller may see
+func (uc *RecruitmentFunnelUseCase) GetFunnel(req *request.RecruitmentFunnelRequest, orgScope *repository.OrganizationScope) (*response.RecruitmentFunnelResponse, error) {
+	headerID, jobPostingIDs, err := uc.findJobPostings(req, orgScope)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := uc.ProjectRecruitmentHeaderRepository.FindByID(headerID)
+	if err != nil {
+		uc.Log.Error("[RecruitmentFunnelUseCase.GetFunnel] " + err.Error())
+		return nil, err
+	}
+	if header == nil {
+		return nil, ErrFunnelProjectRecruitmentHeaderNotFound
+	}
+
+	stages, err := uc.Repository.FindStages(headerID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &response.RecruitmentFunnelResponse{
+		ProjectRecruitmentHeaderID:   header.ID,
+		ProjectRecruitmentHeaderName: header.Name,
+		JobPostingIDs:                jobPostingIDs,
+		Stages:                       []response.RecruitmentFunnelStageResponse{},
+		RejectionReasons:             []response.RecruitmentFunnelReasonResponse{},
+	}
+	if len(jobPostingIDs) == 0 {
+		return res, nil
+	}
+
+	applicants, err := uc.Repository.FindApplicants(jobPostingIDs)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := uc.Repository.FindStageHistories(jobPostingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	historiesByApplicant := make(map[uuid.UUID][]entity.ApplicantStageHistory)
+	for _, history := range histories {
+		historiesByApplicant[history.ApplicantID] = append(historiesByApplicant[history.ApplicantID], history)
+	}
+
+	// days spent per stage order, a stage ends with the next history entry
+	stageDays := make(map[int][]float64)
+	for _, applicantHistories := range historiesByApplicant {
+		for i := 0; i+1 < len(applicantHistories); i++ {
+			current := applicantHistories[i]
+			if current.Order <= 0 || current.Status == entity.APPLICANT_STATUS_REJECTED || current.Status == entity.APPLICANT_STATUS_HIRED {
+				continue
+			}
+			days := applicantHistories[i+1].EnteredAt.Sub(current.EnteredAt).Hours() / 24
+			stageDays[current.Order] = append(stageDays[current.Order], days)
+		}
+	}
+
+	progresses := make([]applicantProgress, 0, len(applicants))
+	for _, applicant := range applicants {
+		progresses = append(progresses, funnelProgress(applicant, historiesByApplicant[applicant.ID]))
+	}
+
+	res.TotalApplicants = len(progresses)
+	for _, progress := range progresses {
+		if progress.Hired {
+			res.TotalHired++
+		}
+	}
+	res.HiredRate = funnelRate(res.TotalHired, res.TotalApplicants)
+
+	stageNames := make(map[int]string, len(stages))
+	stageFormTypes := make(map[int]string, len(stages))
+	for _, stage := range stages {
+		stageNames[stage.Order] = stage.Name
+		stageFormTypes[stage.Order] = stage.FormType
+
+		stageRes := response.RecruitmentFunnelStageResponse{
+			Order:    stage.Order,
+			Name:     stage.Name,
+			FormType: stage.FormType,
+		}
+		for _, progress := range progresses {
+			switch {
+			case progress.Hired:
+				stageRes.Reached++
+				stageRes.Passed++
+			case progress.Rejected && progress.RejectedOrder == stage.Order:
+				stageRes.Reached++
+				stageRes.Rejected++
+			case progress.MaxOrder > stage.Order:
+				stageRes.Reached++
+				stageRes.Passed++
+			case progress.MaxOrder == stage.Order:
+				stageRes.Reached++
+				if !progress.Rejected && progress.CurrentOrder == stage.Order {
+					stageRes.InProgress++
+				}
+			}
+		}
+		stageRes.ConversionRate = funnelRate(stageRes.Passed, stageRes.Reached)
+		stageRes.CumulativeRate = funnelRate(stageRes.Reached, res.TotalApplicants)
+		if days := stageDays[stage.Order]; len(days) > 0 {
+			var total float64
+			for _, d := range days {
+				total += d
+			}
+			avg := math.Round(total/float64(len(days))*100) / 100
+			stageRes.AvgDays = &avg
+		}
+		res.Stages = append(res.Stages, stageRes)
+	}
+
+	reasons := make(map[response.RecruitmentFunnelReasonResponse]int)
+	for _, progress := range progresses {
+		if !progress.Rejected {
+			continue
+		}
+		key := response.RecruitmentFunnelReasonResponse{
+			Type:   FUNNEL_REASON_TYPE_REJECTED,
+			Reason: "Rejected",
+			Stage:  "Unknown",
+		}
+		if name, ok := stageNames[progress.RejectedOrder]; ok {
+			key.Stage = name
+			if reason, ok := funnelReasons[entity.TemplateQuestionFormType(stageFormTypes[progress.RejectedOrder])]; ok {
+				key.Type = reason.Type
+				key.Reason = reason.Reason
+			}
+		}
+		reasons[key]++
+	}
+	for key, total := range reasons {
+		key.Total = total
+		res.RejectionReasons = append(res.RejectionReasons, key)
+	}
+	sort.Slice(res.RejectionReasons, func(i, j int) bool {
+		a, b := res.RejectionReasons[i], res.RejectionReasons[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Stage+a.Reason < b.Stage+b.Reason
+	})
+
+	return res, nil
+}
+
+// findJobPostings returns the project and the job postings the funnel is computed over
+func (uc *RecruitmentFunnelUseCase) findJobPostings(req *request.RecruitmentFunnelRequest, orgScope *repository.OrganizationScope) (uuid.UUID, []uuid.UUID, error) {
+	if req.JobPostingID != "" {
+		jobPostingID, err := uuid.Parse(req.JobPostingID)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		jobPosting, err := uc.JobPostingRepository.FindByID(jobPostingID)
+		if err != nil {
+			uc.Log.Error("[RecruitmentFunnelUseCase.findJobPostings] " + err.Error())
+			return uuid.Nil, nil, err
+		}
+		if jobPosting == nil || !orgScope.Allows(jobPosting.ForOrganizationID) {
+			return uuid.Nil, nil, ErrFunnelJobPostingNotFound
+		}
+		return jobPosting.ProjectRecruitmentHeaderID, []uuid.UUID{jobPosting.ID}, nil
+	}
+
+	headerID, err := uuid.Parse(req.ProjectRecruitmentHeaderID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	jobPostings, err := uc.JobPostingRepository.GetAllByKeys(map[string]interface{}{
+		"project_recruitment_header_id": headerID,
+	})
+	if err != nil {
+		uc.Log.Error("[RecruitmentFunnelUseCase.findJobPostings] " + err.Error())
+		return uuid.Nil, nil, err
+	}
+	jobPostingIDs := make([]uuid.UUID, 0, len(*jobPostings))
+	for _, jobPosting := range *jobPostings {
+		if orgScope.Allows(jobPosting.ForOrganizationID) {
+			jobPostingIDs = append(jobPostingIDs, jobPosting.ID)
+		}
+	}
+
+	return headerID, jobPostingIDs, nil
+}
+
+func funnelProgress(applicant entity.Applicant, histories []entity.ApplicantStageHistory) applicantProgress {
+	progress := applicantProgress{
+		MaxOrder:     max(applicant.Order, 1),
+		CurrentOrder: applicant.Order,
+		Rejected:     applicant.Status == entity.APPLICANT_STATUS_REJECTED,
+		Hired:        applicant.Status == entity.APPLICANT_STATUS_HIRED,
+	}
+	if progress.Rejected {
+		// without a history the stage of an old rejection is unknown
+		progress.RejectedOrder = applicant.Order
+	}
+
+	for _, history := range histories {
+		progress.MaxOrder = max(progress.MaxOrder, history.Order)
+		if history.Status == entity.APPLICANT_STATUS_REJECTED {
+			progress.RejectedOrder = history.Order
+		}
+	}
+
+	return progress
+}
+
+// funnelRate is part of total in percent with two decimals
+func funnelRate(part int, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// IRecruitmentLifecycleUseCase moves job postings, schedules and projects along as their dates pass
+type IRecruitmentLifecycleUseCase interface {
+	RegisterJobs(scheduler ISchedulerUseCase) error
+	SyncJobPostings(ctx context.Context) (string, error)
+	CompleteSchedules(ctx context.Context) (string, error)
+	CompleteProjectRecruitments(ctx context.Context) (string, error)
+}
+
+type RecruitmentLifecycleUseCase struct {
+	Log                                *logrus.Logger
+	Viper                              *viper.Viper
+	JobPostingRepository               repository.IJobPostingRepository
+	TestScheduleHeaderRepository       repository.ITestScheduleHeaderRepository
+	InterviewRepository                repository.IInterviewRepository
+	FgdScheduleRepository              repository.IFgdScheduleRepository
+	ProjectRecruitmentHeaderRepository repository.IProjectRecruitmentHeaderRepository
+}
+
+func NewRecruitmentLifecycleUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	jpRepository repository.IJobPostingRepository,
+	tshRepository repository.ITestScheduleHeaderRepository,
+	interviewRepository repository.IInterviewRepository,
+	fsRepository repository.IFgdScheduleRepository,
+	prhRepository repository.IProjectRecruitmentHeaderRepository,
+) IRecruitmentLifecycleUseCase {
+	return &RecruitmentLifecycleUseCase{
+		Log:                                log,
+		Viper:                              viper,
+		JobPostingRepository:               jpRepository,
+		TestScheduleHeaderRepository:       tshRepository,
+		InterviewRepository:                interviewRepository,
+		FgdScheduleRepository:              fsRepository,
+		ProjectRecruitmentHeaderRepository: prhRepository,
+	}
+}
+
+func RecruitmentLifecycleUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IRecruitmentLifecycleUseCase {
+	jpRepository := repository.JobPostingRepositoryFactory(log)
+	tshRepository := repository.TestScheduleHeaderRepositoryFactory(log)
+	interviewRepository := repository.InterviewRepositoryFactory(log)
+	fsRepository := repository.FgdScheduleRepositoryFactory(log)
+	prhRepository := repository.ProjectRecruitmentHeaderRepositoryFactory(log)
+	return NewRecruitmentLifecycleUseCase(log, viper, jpRepository, tshRepository, interviewRepository, fsRepository, prhRepository)
+}
+
+func (uc *RecruitmentLifecycleUseCase) RegisterJobs(scheduler ISchedulerUseCase) error {
+	jobs := []struct {
+		name string
+		spec string
+		run  SchedulerJobFunc
+	}{
+		// dates are whole days, checking every few minutes opens and closes postings right after midnight
+		{"sync_job_postings", "*/5 * * * *", uc.SyncJobPostings},
+		{"complete_schedules", "*/5 * * * *", uc.CompleteSchedules},
+		{"complete_project_recruitments", "*/15 * * * *", uc.CompleteProjectRecruitments},
+	}
+	for _, job := range jobs {
+		if err := scheduler.RegisterJob(job.name, job.spec, job.run); err != nil {
+			uc.Log.Error("[RecruitmentLifecycleUseCase.RegisterJobs] " + err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// now is the local wall clock, dates and times of the schedules are stored without a time zone
+func (uc *RecruitmentLifecycleUseCase) now() time.Time {
+	name := uc.Viper.GetString("scheduler.timezone")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = time.FixedZone("WIB", 7*60*60)
+	}
+	return time.Now().In(loc)
+}
+
+// SyncJobPostings opens approved postings on their start date and closes them after their end date
+func (uc *RecruitmentLifecycleUseCase) SyncJobPostings(ctx context.Context) (string, error) {
+	today := uc.now()
+
+	opened, err := uc.JobPostingRepository.OpenStartedJobPostings(today)
+	if err != nil {
+		uc.Log.Error("[RecruitmentLifecycleUseCase.SyncJobPostings] " + err.Error())
+		return "", err
+	}
+	closed, err := uc.JobPostingRepository.CloseEndedJobPostings(today)
+	if err != nil {
+		uc.Log.Error("[RecruitmentLifecycleUseCase.SyncJobPostings] " + err.Error())
+		return "", err
+	}
+
+	return fmt.Sprintf("opened %d, closed %d job postings", opened, closed), nil
+}
+
+// CompleteSchedules completes test, interview and FGD schedules once their window has ended
+func (uc *RecruitmentLifecycleUseCase) CompleteSchedules(ctx context.Context) (string, error) {
+	now := uc.now()
+
+	tests, err := uc.TestScheduleHeaderRepository.CompleteEndedTestSchedules(now)
+	if err != nil {
+		uc.Log.Error("[RecruitmentLifecycleUseCase.CompleteSchedules] " + err.Error())
+		return "", err
+	}
+	interviews, err := uc.InterviewRepository.CompleteEndedInterviews(now)
+	if err != nil {
+		uc.Log.Error("[RecruitmentLifecycleUseCase.CompleteSchedules] " + err.Error())
+		return "", err
+	}
+	fgds, err := uc.FgdScheduleRepository.CompleteEndedFgdSchedules(now)
+	if err != nil {
+		uc.Log.Error("[RecruitmentLifecycleUseCase.CompleteSchedules] " + err.Error())
+		return "", err
+	}
+
+	return fmt.Sprintf("completed %d test, %d interview and %d fgd schedules", tests, interviews, fgds), nil
+}
+
+// CompleteProjectRecruitments completes projects after their end date
+func (uc *RecruitmentLifecycleUseCase) CompleteProjectRecruitments(ctx context.Context) (string, error) {
+	completed, err := uc.ProjectRecruitmentHeaderRepository.CompleteEndedProjectRecruitmentHeaders(uc.now())
+	if err != nil {
+		uc.Log.Error("[RecruitmentLifecycleUseCase.CompleteProjectRecruitments] " + err.Error())
+		return "", err
+	}
+
+	return fmt.Sprintf("completed %d project recruitments", completed), nil
+}
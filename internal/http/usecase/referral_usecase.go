@@ -0,0 +1,519 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrReferralJobPostingNotFound      = errors.New("job posting not found")
+	ErrReferralJobPostingNotOpen       = errors.New("job posting is not open for referrals")
+	ErrReferralAlreadyReferred         = errors.New("candidate was already referred to this job posting")
+	ErrReferralCandidateAlreadyApplied = errors.New("candidate already applied to this job posting")
+	ErrReferralSelfReferral            = errors.New("employees cannot refer themselves")
+)
+
+// progress of a referred candidate as the referrer sees it
+const (
+	REFERRAL_PROGRESS_NOT_APPLIED = "NOT_APPLIED"
+	REFERRAL_PROGRESS_IN_PROGRESS = "IN_PROGRESS"
+	REFERRAL_PROGRESS_REJECTED    = "REJECTED"
+	REFERRAL_PROGRESS_HIRED       = "HIRED"
+)
+
+// bonus eligibility of a hired referral
+const (
+	REFERRAL_BONUS_ELIGIBLE     = "ELIGIBLE"
+	REFERRAL_BONUS_ON_PROBATION = "ON_PROBATION"
+	REFERRAL_BONUS_NOT_JOINED   = "NOT_JOINED"
+)
+
+type IReferralUseCase interface {
+	CreateReferralLink(req *request.CreateReferralLinkRequest, employeeID, userID uuid.UUID) (*response.ReferralLinkResponse, error)
+	FindReferralLinks(employeeID uuid.UUID) ([]response.ReferralLinkResponse, error)
+	CreateReferral(req *request.CreateReferralRequest, employeeID, userID uuid.UUID) (*response.ReferralResponse, error)
+	FindReferrals(employeeID uuid.UUID) ([]response.ReferralResponse, error)
+	GetBonusReport(req *request.ReferralBonusReportRequest, orgScope *repository.OrganizationScope) (*response.ReferralBonusReportResponse, error)
+}
+
+type ReferralUseCase struct {
+	Log                   *logrus.Logger
+	Viper                 *viper.Viper
+	Repository            repository.IReferralRepository
+	JobPostingRepository  repository.IJobPostingRepository
+	UserProfileRepository repository.IUserProfileRepository
+	ApplicantRepository   repository.IApplicantRepository
+	EmployeeMessage       messaging.IEmployeeMessage
+	MailMessage           messaging.IMailMessage
+}
+
+func NewReferralUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IReferralRepository,
+	jobPostingRepository repository.IJobPostingRepository,
+	userProfileRepository repository.IUserProfileRepository,
+	applicantRepository repository.IApplicantRepository,
+	employeeMessage messaging.IEmployeeMessage,
+	mailMessage messaging.IMailMessage,
+) IReferralUseCase {
+	return &ReferralUseCase{
+		Log:                   log,
+		Viper:                 viper,
+		Repository:            repo,
+		JobPostingRepository:  jobPostingRepository,
+		UserProfileRepository: userProfileRepository,
+		ApplicantRepository:   applicantRepository,
+		EmployeeMessage:       employeeMessage,
+		MailMessage:           mailMessage,
+	}
+}
+
+func ReferralUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IReferralUseCase {
+	repo := repository.ReferralRepositoryFactory(log)
+	jobPostingRepository := repository.JobPostingRepositoryFactory(log)
+	userProfileRepository := repository.UserProfileRepositoryFactory(log)
+	applicantRepository := repository.ApplicantRepositoryFactory(log)
+	employeeMessage := messaging.EmployeeMessageFactory(log)
+	mailMessage := messaging.MailMessageFactory(log)
+	return NewReferralUseCase(log, viper, repo, jobPostingRepository, userProfileRepository, applicantRepository, employeeMessage, mailMessage)
+}
+
+// CreateReferralLink returns the referral link of the employee for the job posting, making it
+// the first time
+func (uc *ReferralUseCase) CreateReferralLink(req *request.CreateReferralLinkRequest, employeeID, userID uuid.UUID) (*response.ReferralLinkResponse, error) {
+	jobPostingID, err := uuid.Parse(req.JobPostingID)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := uc.referralLink(employeeID, userID, jobPostingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.linkResponse(link), nil
+}
+
+func (uc *ReferralUseCase) FindReferralLinks(employeeID uuid.UUID) ([]response.ReferralLinkResponse, error) {
+	links, err := uc.Repository.FindLinksByEmployeeID(employeeID)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.FindReferralLinks] " + err.Error())
+		return nil, err
+	}
+
+	res := make([]response.ReferralLinkResponse, 0, len(links))
+	for i := range links {
+		res = append(res, *uc.linkResponse(&links[i]))
+	}
+	return res, nil
+}
+
+// CreateReferral records a candidate referred by the employee and invites them to apply through
+// the employee's referral link. A candidate who has a profile already is linked to it, the
+// others are linked when they sign up and apply.
+func (uc *ReferralUseCase) CreateReferral(req *request.CreateReferralRequest, employeeID, userID uuid.UUID) (*response.ReferralResponse, error) {
+	jobPostingID, err := uuid.Parse(req.JobPostingID)
+	if err != nil {
+		return nil, err
+	}
+	email := strings.TrimSpace(req.Email)
+
+	employee, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
+		ID: employeeID.String(),
+	})
+	if err != nil {
+		uc.Log.Warnf("[ReferralUseCase.CreateReferral] employee %s: %v", employeeID, err)
+	} else if employee != nil && strings.EqualFold(employee.Email, email) {
+		return nil, ErrReferralSelfReferral
+	}
+
+	link, err := uc.referralLink(employeeID, userID, jobPostingID)
+	if err != nil {
+		return nil, err
+	}
+
+	referred, err := uc.Repository.FindReferralByJobPostingAndEmail(jobPostingID, email)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.CreateReferral] " + err.Error())
+		return nil, err
+	}
+	if referred != nil {
+		return nil, ErrReferralAlreadyReferred
+	}
+
+	referral := &entity.Referral{
+		ReferralLinkID:     link.ID,
+		ReferrerEmployeeID: employeeID,
+		JobPostingID:       jobPostingID,
+		CandidateName:      strings.TrimSpace(req.Name),
+		CandidateEmail:     email,
+		CandidatePhone:     strings.TrimSpace(req.PhoneNumber),
+		Note:               req.Note,
+		Status:             entity.REFERRAL_STATUS_SUBMITTED,
+	}
+
+	profile, err := uc.UserProfileRepository.FindByEmail(email)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.CreateReferral] " + err.Error())
+		return nil, err
+	}
+	if profile != nil {
+		if profile.UserID != nil && *profile.UserID == userID {
+			return nil, ErrReferralSelfReferral
+		}
+		applicant, err := uc.ApplicantRepository.FindByKeys(map[string]interface{}{
+			"user_profile_id": profile.ID,
+			"job_posting_id":  jobPostingID,
+		})
+		if err != nil {
+			uc.Log.Error("[ReferralUseCase.CreateReferral] " + err.Error())
+			return nil, err
+		}
+		// the candidate found the vacancy on their own, there is nobody to credit
+		if applicant != nil {
+			return nil, ErrReferralCandidateAlreadyApplied
+		}
+		referral.UserProfileID = &profile.ID
+		referral.Status = entity.REFERRAL_STATUS_LINKED
+	}
+
+	referral, err = uc.Repository.CreateReferral(referral)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.CreateReferral] " + err.Error())
+		return nil, err
+	}
+
+	// the referral stands without the invitation, the referrer can still share the link
+	if err := uc.sendInvitation(referral, link, employee); err != nil {
+		uc.Log.Error("[ReferralUseCase.CreateReferral] error when sending the invitation: " + err.Error())
+	}
+
+	res := &response.ReferralResponse{
+		ID:             referral.ID,
+		JobPostingID:   referral.JobPostingID,
+		CandidateName:  referral.CandidateName,
+		CandidateEmail: referral.CandidateEmail,
+		Status:         referral.Status,
+		Progress:       REFERRAL_PROGRESS_NOT_APPLIED,
+		CreatedAt:      referral.CreatedAt,
+	}
+	if link.JobPosting != nil {
+		res.JobPostingName = link.JobPosting.Name
+	}
+	return res, nil
+}
+
+// FindReferrals lists the referrals of the employee with how far each candidate got
+func (uc *ReferralUseCase) FindReferrals(employeeID uuid.UUID) ([]response.ReferralResponse, error) {
+	rows, err := uc.Repository.FindRowsByReferrer(employeeID)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.FindReferrals] " + err.Error())
+		return nil, err
+	}
+
+	today := uc.today()
+	probationDays := uc.probationDays()
+	res := make([]response.ReferralResponse, 0, len(rows))
+	for _, row := range rows {
+		item := response.ReferralResponse{
+			ID:             row.ID,
+			JobPostingID:   row.JobPostingID,
+			JobPostingName: row.JobPostingName,
+			CandidateName:  row.CandidateName,
+			CandidateEmail: row.CandidateEmail,
+			Status:         row.Status,
+			Progress:       REFERRAL_PROGRESS_NOT_APPLIED,
+			AppliedDate:    row.AppliedDate,
+			CreatedAt:      row.CreatedAt,
+		}
+		if row.ApplicantStatus != nil {
+			switch entity.ApplicantStatus(*row.ApplicantStatus) {
+			case entity.APPLICANT_STATUS_HIRED:
+				item.Progress = REFERRAL_PROGRESS_HIRED
+				item.JoinedDate = row.JoinedDate
+				item.BonusStatus, _ = referralBonusStatus(row.JoinedDate, probationDays, today)
+			case entity.APPLICANT_STATUS_REJECTED:
+				item.Progress = REFERRAL_PROGRESS_REJECTED
+			default:
+				item.Progress = REFERRAL_PROGRESS_IN_PROGRESS
+				if row.StageName != nil {
+					item.StageName = *row.StageName
+				}
+			}
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// GetBonusReport lists the hired referrals with whether their referrer is due a bonus, which is
+// once the candidate joined and got through referral.probation_days
+func (uc *ReferralUseCase) GetBonusReport(req *request.ReferralBonusReportRequest, orgScope *repository.OrganizationScope) (*response.ReferralBonusReportResponse, error) {
+	rows, err := uc.Repository.FindHiredRows(orgScope)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.GetBonusReport] " + err.Error())
+		return nil, err
+	}
+
+	today := uc.today()
+	res := &response.ReferralBonusReportResponse{
+		ProbationDays: uc.probationDays(),
+		Items:         []response.ReferralBonusItemResponse{},
+	}
+	referrerNames := make(map[uuid.UUID]string)
+	for _, row := range rows {
+		if row.ApplicantID == nil {
+			continue
+		}
+		status, probationEndsAt := referralBonusStatus(row.JoinedDate, res.ProbationDays, today)
+		if req.Status != "" && req.Status != status {
+			continue
+		}
+
+		name, ok := referrerNames[row.ReferrerEmployeeID]
+		if !ok {
+			name = uc.employeeName(row.ReferrerEmployeeID)
+			referrerNames[row.ReferrerEmployeeID] = name
+		}
+
+		res.Items = append(res.Items, response.ReferralBonusItemResponse{
+			ReferralID:         row.ID,
+			ReferrerEmployeeID: row.ReferrerEmployeeID,
+			ReferrerName:       name,
+			JobPostingID:       row.JobPostingID,
+			JobPostingName:     row.JobPostingName,
+			ApplicantID:        *row.ApplicantID,
+			CandidateName:      row.CandidateName,
+			CandidateEmail:     row.CandidateEmail,
+			JoinedDate:         row.JoinedDate,
+			ProbationEndsAt:    probationEndsAt,
+			Status:             status,
+		})
+		if status == REFERRAL_BONUS_ELIGIBLE {
+			res.Eligible++
+		}
+	}
+	res.Total = len(res.Items)
+
+	return res, nil
+}
+
+// referralBonusStatus tells whether a hired candidate got through probation by today
+func referralBonusStatus(joinedDate *time.Time, probationDays int, today time.Time) (string, *time.Time) {
+	if joinedDate == nil {
+		return REFERRAL_BONUS_NOT_JOINED, nil
+	}
+	endsAt := time.Date(joinedDate.Year(), joinedDate.Month(), joinedDate.Day(), 0, 0, 0, 0, today.Location()).AddDate(0, 0, probationDays)
+	if endsAt.After(today) {
+		return REFERRAL_BONUS_ON_PROBATION, &endsAt
+	}
+	return REFERRAL_BONUS_ELIGIBLE, &endsAt
+}
+
+// referralLink finds the link of the employee for the job posting, or makes one if the job
+// posting is open
+func (uc *ReferralUseCase) referralLink(employeeID, userID, jobPostingID uuid.UUID) (*entity.ReferralLink, error) {
+	link, err := uc.Repository.FindLinkByEmployeeAndJobPosting(employeeID, jobPostingID)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.referralLink] " + err.Error())
+		return nil, err
+	}
+	if link != nil {
+		return link, nil
+	}
+
+	jobPosting, err := uc.JobPostingRepository.FindByID(jobPostingID)
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.referralLink] " + err.Error())
+		return nil, err
+	}
+	if jobPosting == nil {
+		return nil, ErrReferralJobPostingNotFound
+	}
+	if jobPosting.Status != entity.JOB_POSTING_STATUS_APPROVED && jobPosting.Status != entity.JOB_POSTING_STATUS_IN_PROGRESS {
+		return nil, ErrReferralJobPostingNotOpen
+	}
+
+	link, err = uc.Repository.CreateReferralLink(&entity.ReferralLink{
+		EmployeeID:   employeeID,
+		UserID:       userID,
+		JobPostingID: jobPostingID,
+		Code:         utils.GenerateRandomStringToken(10),
+	})
+	if err != nil {
+		uc.Log.Error("[ReferralUseCase.referralLink] " + err.Error())
+		return nil, err
+	}
+	return link, nil
+}
+
+func (uc *ReferralUseCase) linkResponse(link *entity.ReferralLink) *response.ReferralLinkResponse {
+	res := &response.ReferralLinkResponse{
+		ID:           link.ID,
+		EmployeeID:   link.EmployeeID,
+		JobPostingID: link.JobPostingID,
+		Code:         link.Code,
+		URL:          referralURL(uc.Viper, link),
+		CreatedAt:    link.CreatedAt,
+	}
+	if link.JobPosting != nil {
+		res.JobPostingName = link.JobPosting.Name
+	}
+	return res
+}
+
+// referralURL is the careers site page of the job posting carrying the referral code, the
+// careers site passes it on as ref when the candidate applies
+func referralURL(viper *viper.Viper, link *entity.ReferralLink) string {
+	base := viper.GetString("careers.url")
+	if base == "" {
+		base = viper.GetString("app.url")
+	}
+	path := viper.GetString("careers.job_path")
+	if path == "" {
+		path = "/jobs"
+	}
+	page := link.JobPostingID.String()
+	if link.JobPosting != nil && link.JobPosting.Slug != "" {
+		page = link.JobPosting.Slug
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.Trim(path, "/") + "/" + page + "?ref=" + url.QueryEscape(link.Code)
+}
+
+func (uc *ReferralUseCase) sendInvitation(referral *entity.Referral, link *entity.ReferralLink, employee *response.EmployeeResponse) error {
+	jobPostingName := "an open position"
+	if link.JobPosting != nil {
+		jobPostingName = link.JobPosting.Name
+	}
+	referrer := "An employee of ours"
+	if employee != nil && employee.Name != "" {
+		referrer = employee.Name
+	}
+
+	body := fmt.Sprintf(`<p>Dear %s,</p><p>%s referred you for %s.</p><p><a href="%s">Apply here</a></p>`,
+		html.EscapeString(referral.CandidateName), html.EscapeString(referrer), html.EscapeString(jobPostingName), html.EscapeString(referralURL(uc.Viper, link)))
+	_, err := uc.MailMessage.SendMail(&request.MailRequest{
+		Email:   referral.CandidateEmail,
+		Subject: "You have been referred for " + jobPostingName,
+		Body:    body,
+		From:    uc.Viper.GetString("mail.from"),
+		To:      referral.CandidateEmail,
+	})
+	return err
+}
+
+// employeeName is the name of an employee, or an empty string when the employee service does
+// not know it
+func (uc *ReferralUseCase) employeeName(employeeID uuid.UUID) string {
+	employee, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
+		ID: employeeID.String(),
+	})
+	if err != nil {
+		uc.Log.Warnf("[ReferralUseCase.employeeName] employee %s: %v", employeeID, err)
+		return ""
+	}
+	if employee == nil {
+		return ""
+	}
+	return employee.Name
+}
+
+func (uc *ReferralUseCase) probationDays() int {
+	if !uc.Viper.IsSet("referral.probation_days") {
+		return 90
+	}
+	return uc.Viper.GetInt("referral.probation_days")
+}
+
+func (uc *ReferralUseCase) today() time.Time {
+	name := uc.Viper.GetString("scheduler.timezone")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		uc.Log.Warnf("[ReferralUseCase.today] %v, using UTC+7", err)
+		loc = time.FixedZone("WIB", 7*60*60)
+	}
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+}
+
+// linkReferral ties an application to the referral of the candidate, found by the referral code
+// the candidate applied with or by their profile and email. Without a referral, applying with a
+// referral code records one for the referrer. It returns the code of the referral link the
+// application is credited to, none when the candidate is the referrer.
+func linkReferral(repo repository.IReferralRepository, applicant *entity.Applicant, profile *entity.UserProfile, code string) (string, error) {
+	var link *entity.ReferralLink
+	if code != "" {
+		found, err := repo.FindLinkByCode(code)
+		if err != nil {
+			return "", err
+		}
+		if found != nil && found.JobPostingID == applicant.JobPostingID {
+			link = found
+		}
+		if found != nil && isSelfReferral(found, profile) {
+			return "", nil
+		}
+	}
+
+	referral, err := repo.FindOpenReferral(applicant.JobPostingID, profile.ID, profile.Email)
+	if err != nil {
+		return "", err
+	}
+	if referral != nil {
+		if referral.ReferralLink != nil && isSelfReferral(referral.ReferralLink, profile) {
+			return "", nil
+		}
+		if _, err := repo.UpdateReferral(&entity.Referral{
+			ID:            referral.ID,
+			UserProfileID: &profile.ID,
+			ApplicantID:   &applicant.ID,
+			Status:        entity.REFERRAL_STATUS_APPLIED,
+		}); err != nil {
+			return "", err
+		}
+		if referral.ReferralLink != nil {
+			return referral.ReferralLink.Code, nil
+		}
+		return code, nil
+	}
+	if link == nil {
+		return code, nil
+	}
+
+	if _, err := repo.CreateReferral(&entity.Referral{
+		ReferralLinkID:     link.ID,
+		ReferrerEmployeeID: link.EmployeeID,
+		JobPostingID:       applicant.JobPostingID,
+		CandidateName:      profile.Name,
+		CandidateEmail:     profile.Email,
+		CandidatePhone:     profile.PhoneNumber,
+		UserProfileID:      &profile.ID,
+		ApplicantID:        &applicant.ID,
+		Status:             entity.REFERRAL_STATUS_APPLIED,
+	}); err != nil {
+		return "", err
+	}
+	return link.Code, nil
+}
+
+func isSelfReferral(link *entity.ReferralLink, profile *entity.UserProfile) bool {
+	return profile.UserID != nil && *profile.UserID == link.UserID
+}
@@ -0,0 +1,597 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/helper"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// IScheduleReminderUseCase reminds applicants and assessors of their test, interview and FGD slots
+type IScheduleReminderUseCase interface {
+	RegisterJobs(scheduler ISchedulerUseCase) error
+	SendDueReminders(ctx context.Context) (string, error)
+}
+
+type ScheduleReminderUseCase struct {
+	Log                          *logrus.Logger
+	Viper                        *viper.Viper
+	Repository                   repository.IScheduleReminderRepository
+	TestScheduleHeaderRepository repository.ITestScheduleHeaderRepository
+	InterviewRepository          repository.IInterviewRepository
+	FgdScheduleRepository        repository.IFgdScheduleRepository
+	UserHelper                   helper.IUserHelper
+	UserMessage                  messaging.IUserMessage
+	EmployeeMessage              messaging.IEmployeeMessage
+	MailMessage                  messaging.IMailMessage
+	NotificationService          service.INotificationService
+}
+
+func NewScheduleReminderUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.IScheduleReminderRepository,
+	tshRepository repository.ITestScheduleHeaderRepository,
+	interviewRepository repository.IInterviewRepository,
+	fsRepository repository.IFgdScheduleRepository,
+	userHelper helper.IUserHelper,
+	userMessage messaging.IUserMessage,
+	employeeMessage messaging.IEmployeeMessage,
+	mailMessage messaging.IMailMessage,
+	notificationService service.INotificationService,
+) IScheduleReminderUseCase {
+	return &ScheduleReminderUseCase{
+		Log:                          log,
+		Viper:                        viper,
+		Repository:                   repo,
+		TestScheduleHeaderRepository: tshRepository,
+		InterviewRepository:          interviewRepository,
+		FgdScheduleRepository:        fsRepository,
+		UserHelper:                   userHelper,
+		UserMessage:                  userMessage,
+		EmployeeMessage:              employeeMessage,
+		MailMessage:                  mailMessage,
+		NotificationService:          notificationService,
+	}
+}
+
+func ScheduleReminderUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IScheduleReminderUseCase {
+	repo := repository.ScheduleReminderRepositoryFactory(log)
+	tshRepository := repository.TestScheduleHeaderRepositoryFactory(log)
+	interviewRepository := repository.InterviewRepositoryFactory(log)
+	fsRepository := repository.FgdScheduleRepositoryFactory(log)
+	userHelper := helper.UserHelperFactory(log)
+	userMessage := messaging.UserMessageFactory(log)
+	employeeMessage := messaging.EmployeeMessageFactory(log)
+	mailMessage := messaging.MailMessageFactory(log)
+	notificationService := service.NotificationServiceFactory(viper, log)
+	return NewScheduleReminderUseCase(
+		log,
+		viper,
+		repo,
+		tshRepository,
+		interviewRepository,
+		fsRepository,
+		userHelper,
+		userMessage,
+		employeeMessage,
+		mailMessage,
+		notificationService,
+	)
+}
+
+// cancelScheduleReminders drops the pending reminders of a schedule that was changed or deleted.
+// The next reminder run plans them again from what is saved now. A failure is only logged,
+// reminders that no longer match a slot are cancelled when they fall due anyway.
+func cancelScheduleReminders(log *logrus.Logger, repo repository.IScheduleReminderRepository, scheduleType entity.ScheduleReminderScheduleType, scheduleID uuid.UUID) {
+	if _, err := repo.CancelPendingBySchedule(scheduleType, scheduleID); err != nil {
+		log.Error("[cancelScheduleReminders] " + err.Error())
+	}
+}
+
+func (uc *ScheduleReminderUseCase) RegisterJobs(scheduler ISchedulerUseCase) error {
+	if err := scheduler.RegisterJob("send_schedule_reminders", "* * * * *", uc.SendDueReminders); err != nil {
+		uc.Log.Error("[ScheduleReminderUseCase.RegisterJobs] " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// reminderSlot is what a recipient is reminded of
+type reminderSlot struct {
+	ScheduleType  entity.ScheduleReminderScheduleType
+	ScheduleID    uuid.UUID
+	RecipientType entity.ScheduleReminderRecipientType
+	RecipientID   uuid.UUID
+	UserID        *uuid.UUID
+	EmployeeID    *uuid.UUID
+	RecipientName string
+	ScheduleName  string
+	JobName       string
+	StartAt       time.Time
+	EndAt         time.Time
+	Location      string
+	Link          string
+}
+
+type plannedReminder struct {
+	Reminder entity.ScheduleReminder
+	Slot     *reminderSlot
+}
+
+func (uc *ScheduleReminderUseCase) location() *time.Location {
+	name := uc.Viper.GetString("scheduler.timezone")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		uc.Log.Warnf("[ScheduleReminderUseCase.location] %v, using UTC+7", err)
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// offsets are reminders.offsets (e.g. "24h", "1h"), longest first
+func (uc *ScheduleReminderUseCase) offsets() []time.Duration {
+	values := uc.Viper.GetStringSlice("reminders.offsets")
+	if len(values) == 0 {
+		values = []string{"24h", "1h"}
+	}
+
+	offsets := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		offset, err := time.ParseDuration(value)
+		if err != nil || offset < time.Minute {
+			uc.Log.Warnf("[ScheduleReminderUseCase.offsets] skipping invalid offset %q", value)
+			continue
+		}
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] > offsets[j]
+	})
+	return offsets
+}
+
+func (uc *ScheduleReminderUseCase) channels() []entity.ScheduleReminderChannel {
+	values := uc.Viper.GetStringSlice("reminders.channels")
+	if len(values) == 0 {
+		return []entity.ScheduleReminderChannel{entity.SCHEDULE_REMINDER_CHANNEL_EMAIL, entity.SCHEDULE_REMINDER_CHANNEL_NOTIFICATION}
+	}
+
+	channels := make([]entity.ScheduleReminderChannel, 0, len(values))
+	for _, value := range values {
+		switch channel := entity.ScheduleReminderChannel(strings.ToUpper(value)); channel {
+		case entity.SCHEDULE_REMINDER_CHANNEL_EMAIL, entity.SCHEDULE_REMINDER_CHANNEL_NOTIFICATION:
+			channels = append(channels, channel)
+		default:
+			uc.Log.Warnf("[ScheduleReminderUseCase.channels] skipping unknown channel %q", value)
+		}
+	}
+	return channels
+}
+
+// SendDueReminders plans the reminders of the slots coming up, then sends the ones that are due.
+// A due reminder that no longer matches a slot (the schedule moved, was deleted or has already
+// started) is cancelled instead.
+func (uc *ScheduleReminderUseCase) SendDueReminders(ctx context.Context) (string, error) {
+	now := time.Now()
+	offsets := uc.offsets()
+	channels := uc.channels()
+	if len(offsets) == 0 || len(channels) == 0 {
+		return "no reminder offsets or channels configured", nil
+	}
+
+	slots, err := uc.upcomingSlots(now, offsets[0])
+	if err != nil {
+		uc.Log.Error("[ScheduleReminderUseCase.SendDueReminders] " + err.Error())
+		return "", err
+	}
+
+	// a reminder whose time passed before its slot was planned (e.g. an interview set up two
+	// hours ahead has no T-24h reminder) is skipped, the grace covers the gap between runs
+	grace := time.Duration(uc.Viper.GetInt("reminders.grace_minutes")) * time.Minute
+	if grace <= 0 {
+		grace = 10 * time.Minute
+	}
+
+	planned := make(map[string]plannedReminder)
+	var toSave []entity.ScheduleReminder
+	for _, slot := range slots {
+		for _, offset := range offsets {
+			remindAt := slot.StartAt.Add(-offset)
+			for _, channel := range channels {
+				reminder := entity.ScheduleReminder{
+					DedupKey:      scheduleReminderKey(slot, channel, offset),
+					ScheduleType:  slot.ScheduleType,
+					ScheduleID:    slot.ScheduleID,
+					RecipientType: slot.RecipientType,
+					RecipientID:   slot.RecipientID,
+					Channel:       channel,
+					OffsetMinutes: int(offset / time.Minute),
+					SlotAt:        slot.StartAt.UTC(),
+					RemindAt:      remindAt.UTC(),
+					Status:        entity.SCHEDULE_REMINDER_STATUS_PENDING,
+				}
+				planned[reminder.DedupKey] = plannedReminder{Reminder: reminder, Slot: slot}
+				if remindAt.After(now.Add(-grace)) {
+					toSave = append(toSave, reminder)
+				}
+			}
+		}
+	}
+
+	saved, err := uc.Repository.UpsertReminders(toSave)
+	if err != nil {
+		uc.Log.Error("[ScheduleReminderUseCase.SendDueReminders] " + err.Error())
+		return "", err
+	}
+
+	batchSize := uc.Viper.GetInt("reminders.batch_size")
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+	due, err := uc.Repository.FindDueReminders(now.UTC(), batchSize)
+	if err != nil {
+		uc.Log.Error("[ScheduleReminderUseCase.SendDueReminders] " + err.Error())
+		return "", err
+	}
+
+	var sent, failed, cancelled int
+	for _, reminder := range due {
+		if ctx.Err() != nil {
+			break
+		}
+
+		plan, ok := planned[reminder.DedupKey]
+		if !ok {
+			cancelled++
+			if err := uc.Repository.UpdateReminderFields(reminder.ID, map[string]interface{}{
+				"status":     entity.SCHEDULE_REMINDER_STATUS_CANCELLED,
+				"last_error": "slot is no longer scheduled",
+			}); err != nil {
+				uc.Log.Error("[ScheduleReminderUseCase.SendDueReminders] " + err.Error())
+			}
+			continue
+		}
+
+		if err := uc.send(reminder.Channel, plan.Slot); err != nil {
+			failed++
+			uc.Log.Errorf("[ScheduleReminderUseCase.SendDueReminders] reminder %s failed: %v", reminder.DedupKey, err)
+			if err := uc.Repository.UpdateReminderFields(reminder.ID, uc.failedFields(reminder, err, now)); err != nil {
+				uc.Log.Error("[ScheduleReminderUseCase.SendDueReminders] " + err.Error())
+			}
+			continue
+		}
+
+		sent++
+		if err := uc.Repository.UpdateReminderFields(reminder.ID, map[string]interface{}{
+			"status":     entity.SCHEDULE_REMINDER_STATUS_SENT,
+			"attempts":   reminder.Attempts + 1,
+			"sent_at":    now,
+			"last_error": nil,
+		}); err != nil {
+			uc.Log.Error("[ScheduleReminderUseCase.SendDueReminders] " + err.Error())
+		}
+	}
+
+	return fmt.Sprintf("planned %d, sent %d, failed %d, cancelled %d reminders", saved, sent, failed, cancelled), nil
+}
+
+// failedFields retries a failed reminder a few minutes later until reminders.max_attempts is reached
+func (uc *ScheduleReminderUseCase) failedFields(reminder entity.ScheduleReminder, err error, now time.Time) map[string]interface{} {
+	maxAttempts := uc.Viper.GetInt("reminders.max_attempts")
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	attempts := reminder.Attempts + 1
+	fields := map[string]interface{}{
+		"attempts":   attempts,
+		"last_error": err.Error(),
+		"remind_at":  now.Add(time.Duration(attempts) * 5 * time.Minute).UTC(),
+	}
+	if attempts >= maxAttempts {
+		fields["status"] = entity.SCHEDULE_REMINDER_STATUS_FAILED
+	}
+	return fields
+}
+
+func scheduleReminderKey(slot *reminderSlot, channel entity.ScheduleReminderChannel, offset time.Duration) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%d:%d",
+		slot.ScheduleType, slot.RecipientType, slot.RecipientID, channel, int(offset/time.Minute), slot.StartAt.Unix())
+}
+
+// upcomingSlots returns the slots of in progress schedules that start within the longest offset
+func (uc *ScheduleReminderUseCase) upcomingSlots(now time.Time, horizon time.Duration) ([]*reminderSlot, error) {
+	loc := uc.location()
+	from := now.In(loc)
+	to := now.Add(horizon).In(loc)
+	withAssessors := !uc.Viper.IsSet("reminders.assessors") || uc.Viper.GetBool("reminders.assessors")
+
+	var slots []*reminderSlot
+	add := func(slot *reminderSlot) {
+		if slot.StartAt.After(now) && !slot.StartAt.After(now.Add(horizon)) {
+			slots = append(slots, slot)
+		}
+	}
+
+	tests, err := uc.TestScheduleHeaderRepository.FindAllInProgressByScheduleDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, test := range *tests {
+		for _, applicant := range test.TestApplicants {
+			slot := &reminderSlot{
+				ScheduleType:  entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_TEST,
+				ScheduleID:    test.ID,
+				RecipientType: entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_APPLICANT,
+				RecipientID:   applicant.ID,
+				ScheduleName:  test.Name,
+				JobName:       jobPostingName(test.JobPosting),
+				StartAt:       slotTime(test.ScheduleDate, applicant.StartTime, loc),
+				EndAt:         slotTime(test.ScheduleDate, applicant.EndTime, loc),
+				Location:      test.Location,
+				Link:          test.Link,
+			}
+			setApplicantRecipient(slot, applicant.UserProfile)
+			add(slot)
+		}
+	}
+
+	interviews, err := uc.InterviewRepository.FindAllInProgressByScheduleDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, interview := range *interviews {
+		base := reminderSlot{
+			ScheduleType: entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_INTERVIEW,
+			ScheduleID:   interview.ID,
+			ScheduleName: interview.Name,
+			JobName:      jobPostingName(interview.JobPosting),
+			Location:     interview.LocationLink,
+			Link:         interview.MeetingLink,
+		}
+		for _, applicant := range interview.InterviewApplicants {
+			slot := base
+			slot.RecipientType = entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_APPLICANT
+			slot.RecipientID = applicant.ID
+			slot.StartAt = slotTime(interview.ScheduleDate, applicant.StartTime, loc)
+			slot.EndAt = slotTime(interview.ScheduleDate, applicant.EndTime, loc)
+			setApplicantRecipient(&slot, applicant.UserProfile)
+			add(&slot)
+		}
+		if !withAssessors {
+			continue
+		}
+		for _, assessor := range interview.InterviewAssessors {
+			slot := base
+			slot.RecipientType = entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_ASSESSOR
+			slot.RecipientID = assessor.ID
+			slot.EmployeeID = assessor.EmployeeID
+			slot.StartAt = slotTime(interview.ScheduleDate, interview.StartTime, loc)
+			slot.EndAt = slotTime(interview.ScheduleDate, interview.EndTime, loc)
+			add(&slot)
+		}
+	}
+
+	fgds, err := uc.FgdScheduleRepository.FindAllInProgressByScheduleDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, fgd := range *fgds {
+		base := reminderSlot{
+			ScheduleType: entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_FGD,
+			ScheduleID:   fgd.ID,
+			ScheduleName: fgd.Name,
+			JobName:      jobPostingName(fgd.JobPosting),
+			Location:     fgd.LocationLink,
+		}
+		for _, applicant := range fgd.FgdApplicants {
+			slot := base
+			slot.RecipientType = entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_APPLICANT
+			slot.RecipientID = applicant.ID
+			slot.StartAt = slotTime(fgd.ScheduleDate, applicant.StartTime, loc)
+			slot.EndAt = slotTime(fgd.ScheduleDate, applicant.EndTime, loc)
+			setApplicantRecipient(&slot, applicant.UserProfile)
+			add(&slot)
+		}
+		if !withAssessors {
+			continue
+		}
+		for _, assessor := range fgd.FgdAssessors {
+			slot := base
+			slot.RecipientType = entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_ASSESSOR
+			slot.RecipientID = assessor.ID
+			slot.EmployeeID = assessor.EmployeeID
+			slot.StartAt = slotTime(fgd.ScheduleDate, fgd.StartTime, loc)
+			slot.EndAt = slotTime(fgd.ScheduleDate, fgd.EndTime, loc)
+			add(&slot)
+		}
+	}
+
+	return slots, nil
+}
+
+// slotTime joins a date column and a time column into one moment in loc
+func slotTime(date time.Time, clock time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+}
+
+func jobPostingName(jobPosting *entity.JobPosting) string {
+	if jobPosting == nil {
+		return ""
+	}
+	return jobPosting.Name
+}
+
+func setApplicantRecipient(slot *reminderSlot, userProfile *entity.UserProfile) {
+	if userProfile == nil {
+		return
+	}
+	slot.UserID = userProfile.UserID
+	slot.RecipientName = userProfile.Name
+}
+
+func (uc *ScheduleReminderUseCase) send(channel entity.ScheduleReminderChannel, slot *reminderSlot) error {
+	switch channel {
+	case entity.SCHEDULE_REMINDER_CHANNEL_EMAIL:
+		return uc.sendMail(slot)
+	case entity.SCHEDULE_REMINDER_CHANNEL_NOTIFICATION:
+		return uc.sendNotification(slot)
+	}
+	return errors.New("unknown reminder channel " + string(channel))
+}
+
+func (uc *ScheduleReminderUseCase) sendMail(slot *reminderSlot) error {
+	email, name, err := uc.recipientEmail(slot)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = slot.RecipientName
+	}
+
+	subject := fmt.Sprintf("Reminder: %s %s", scheduleKind(slot.ScheduleType), slot.StartAt.Format("Mon, 02 Jan 2006 15:04 MST"))
+	if _, err := uc.MailMessage.SendMail(&request.MailRequest{
+		Email:   email,
+		Subject: subject,
+		Body:    reminderMailBody(slot, name),
+		From:    uc.Viper.GetString("mail.from"),
+		To:      email,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uc *ScheduleReminderUseCase) recipientEmail(slot *reminderSlot) (string, string, error) {
+	if slot.RecipientType == entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_ASSESSOR {
+		if slot.EmployeeID == nil {
+			return "", "", errors.New("assessor has no employee")
+		}
+		employee, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
+			ID: slot.EmployeeID.String(),
+		})
+		if err != nil {
+			return "", "", err
+		}
+		if employee == nil || employee.Email == "" {
+			return "", "", errors.New("assessor has no email")
+		}
+		return employee.Email, employee.Name, nil
+	}
+
+	if slot.UserID == nil {
+		return "", "", errors.New("applicant has no user")
+	}
+	user, err := uc.UserMessage.SendGetUserMe(request.SendFindUserByIDMessageRequest{
+		ID: slot.UserID.String(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if user == nil || user.User == nil {
+		return "", "", errors.New("user not found")
+	}
+	email, err := uc.UserHelper.GetUserEmail(user.User)
+	if err != nil {
+		return "", "", err
+	}
+	return email, "", nil
+}
+
+func (uc *ScheduleReminderUseCase) sendNotification(slot *reminderSlot) error {
+	var userID string
+	url := uc.Viper.GetString("reminders.applicant_url")
+	if slot.RecipientType == entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_ASSESSOR {
+		if slot.EmployeeID == nil {
+			return errors.New("assessor has no employee")
+		}
+		user, err := uc.UserMessage.SendFindUserByEmployeeIDMessage(slot.EmployeeID.String())
+		if err != nil {
+			return err
+		}
+		if user == nil || user.ID == "" {
+			return errors.New("assessor has no user")
+		}
+		userID = user.ID
+		url = uc.Viper.GetString("reminders.assessor_url")
+	} else {
+		if slot.UserID == nil {
+			return errors.New("applicant has no user")
+		}
+		userID = slot.UserID.String()
+	}
+	if url == "" {
+		url = "/"
+	}
+
+	message := fmt.Sprintf("Your %s %s starts %s.", scheduleKind(slot.ScheduleType), slot.ScheduleName, slot.StartAt.Format("Mon, 02 Jan 2006 15:04 MST"))
+	if where := slotWhere(slot); where != "" {
+		message += " " + where
+	}
+
+	// reminders are sent by the system, there is no acting user to put as the creator
+	return uc.NotificationService.CreateScheduleReminderNotification(userID, []string{userID}, "Reminder: "+scheduleKind(slot.ScheduleType), message, url)
+}
+
+func scheduleKind(scheduleType entity.ScheduleReminderScheduleType) string {
+	switch scheduleType {
+	case entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_TEST:
+		return "test"
+	case entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_INTERVIEW:
+		return "interview"
+	case entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_FGD:
+		return "FGD"
+	}
+	return strings.ToLower(string(scheduleType))
+}
+
+func slotWhere(slot *reminderSlot) string {
+	var parts []string
+	if slot.Location != "" {
+		parts = append(parts, "Location: "+slot.Location+".")
+	}
+	if slot.Link != "" && slot.Link != slot.Location {
+		parts = append(parts, "Link: "+slot.Link)
+	}
+	return strings.Join(parts, " ")
+}
+
+func reminderMailBody(slot *reminderSlot, name string) string {
+	var b strings.Builder
+	if name != "" {
+		fmt.Fprintf(&b, "<p>Dear %s,</p>", html.EscapeString(name))
+	}
+	fmt.Fprintf(&b, "<p>This is a reminder of your %s", scheduleKind(slot.ScheduleType))
+	if slot.JobName != "" {
+		fmt.Fprintf(&b, " for <b>%s</b>", html.EscapeString(slot.JobName))
+	}
+	b.WriteString(".</p><ul>")
+	fmt.Fprintf(&b, "<li>Schedule: %s</li>", html.EscapeString(slot.ScheduleName))
+	fmt.Fprintf(&b, "<li>Date: %s</li>", slot.StartAt.Format("Monday, 02 January 2006"))
+	fmt.Fprintf(&b, "<li>Time: %s - %s %s</li>", slot.StartAt.Format("15:04"), slot.EndAt.Format("15:04"), slot.StartAt.Format("MST"))
+	if slot.Location != "" {
+		fmt.Fprintf(&b, "<li>Location: %s</li>", html.EscapeString(slot.Location))
+	}
+	if slot.Link != "" && slot.Link != slot.Location {
+		fmt.Fprintf(&b, "<li>Link: <a href=\"%s\">%s</a></li>", html.EscapeString(slot.Link), html.EscapeString(slot.Link))
+	}
+	b.WriteString("</ul><p>Please be there on time.</p>")
+	return b.String()
+}
@@ -0,0 +1,413 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	SCHEDULER_LOCK_NAME      = "scheduler"
+	SCHEDULER_PURGE_JOB_NAME = "purge_scheduler_job_runs"
+)
+
+var (
+	ErrSchedulerJobNotFound = errors.New("scheduler job not found")
+	ErrSchedulerJobRunning  = errors.New("scheduler job is already running")
+)
+
+// SchedulerJobFunc runs one scheduled job. The returned string is stored as the result of the run.
+type SchedulerJobFunc func(ctx context.Context) (string, error)
+
+type ISchedulerUseCase interface {
+	RegisterJob(name string, spec string, run SchedulerJobFunc) error
+	Start()
+	RunJob(name string) (*response.SchedulerJobRunResponse, error)
+	FindAllJobs() ([]response.SchedulerJobResponse, error)
+	FindAllJobRunsPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]response.SchedulerJobRunResponse, int64, error)
+}
+
+type SchedulerUseCase struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Repository repository.ISchedulerRepository
+	DTO        dto.ISchedulerDTO
+}
+
+func NewSchedulerUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.ISchedulerRepository,
+	sDTO dto.ISchedulerDTO,
+) ISchedulerUseCase {
+	return &SchedulerUseCase{
+		Log:        log,
+		Viper:      viper,
+		Repository: repo,
+		DTO:        sDTO,
+	}
+}
+
+func SchedulerUseCaseFactory(log *logrus.Logger, viper *viper.Viper) ISchedulerUseCase {
+	repo := repository.SchedulerRepositoryFactory(log)
+	sDTO := dto.SchedulerDTOFactory(log)
+	return NewSchedulerUseCase(log, viper, repo, sDTO)
+}
+
+type schedulerJob struct {
+	name      string
+	schedule  *utils.CronSchedule
+	run       SchedulerJobFunc
+	nextRunAt time.Time
+	running   bool
+}
+
+// schedulerRegistry holds the registered jobs of this process, shared by every instance
+// of the use case so the handlers see the jobs registered at startup.
+type schedulerRegistry struct {
+	mu     sync.Mutex
+	jobs   map[string]*schedulerJob
+	owner  string
+	leader bool
+}
+
+var schedulerJobs = &schedulerRegistry{
+	jobs:  map[string]*schedulerJob{},
+	owner: schedulerOwner(),
+}
+
+// schedulerOwner identifies this replica in the leader lock and in the run history
+func schedulerOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return hostname + "-" + uuid.NewString()[:8]
+}
+
+func (uc *SchedulerUseCase) seconds(configKey string, fallback int) time.Duration {
+	value := uc.Viper.GetInt(configKey)
+	if value <= 0 {
+		value = fallback
+	}
+	return time.Duration(value) * time.Second
+}
+
+func (uc *SchedulerUseCase) location() *time.Location {
+	name := uc.Viper.GetString("scheduler.timezone")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		uc.Log.Warnf("[SchedulerUseCase.location] %v, using UTC+7", err)
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// RegisterJob adds a job that runs on the given cron expression. The expression can be
+// overridden with scheduler.jobs.<name>.schedule and the job turned off with
+// scheduler.jobs.<name>.enabled = false.
+func (uc *SchedulerUseCase) RegisterJob(name string, spec string, run SchedulerJobFunc) error {
+	configKey := "scheduler.jobs." + name
+	if uc.Viper.IsSet(configKey+".enabled") && !uc.Viper.GetBool(configKey+".enabled") {
+		uc.Log.Infof("[SchedulerUseCase.RegisterJob] job %s is disabled", name)
+		return nil
+	}
+	if override := uc.Viper.GetString(configKey + ".schedule"); override != "" {
+		spec = override
+	}
+
+	schedule, err := utils.ParseCron(spec)
+	if err != nil {
+		uc.Log.Error("[SchedulerUseCase.RegisterJob] " + err.Error())
+		return err
+	}
+
+	schedulerJobs.mu.Lock()
+	defer schedulerJobs.mu.Unlock()
+
+	if _, ok := schedulerJobs.jobs[name]; ok {
+		return fmt.Errorf("scheduler job %s is already registered", name)
+	}
+	schedulerJobs.jobs[name] = &schedulerJob{
+		name:      name,
+		schedule:  schedule,
+		run:       run,
+		nextRunAt: schedule.Next(time.Now().In(uc.location())),
+	}
+
+	uc.Log.Infof("[SchedulerUseCase.RegisterJob] registered job %s (%s)", name, spec)
+	return nil
+}
+
+// Start runs the due jobs until the process exits. Every replica ticks, but only the one
+// holding the leader lock runs jobs; another replica takes over once the lock expires.
+func (uc *SchedulerUseCase) Start() {
+	if err := uc.RegisterJob(SCHEDULER_PURGE_JOB_NAME, "30 2 * * *", uc.purgeJobRuns); err != nil {
+		uc.Log.Error("[SchedulerUseCase.Start] " + err.Error())
+	}
+
+	interval := uc.seconds("scheduler.tick_interval", 15)
+	uc.Log.Infof("[SchedulerUseCase.Start] scheduler %s ticking every %s", schedulerJobs.owner, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	uc.tick()
+	for range ticker.C {
+		uc.tick()
+	}
+}
+
+func (uc *SchedulerUseCase) tick() {
+	leader, err := uc.Repository.AcquireLock(SCHEDULER_LOCK_NAME, schedulerJobs.owner, uc.seconds("scheduler.lock_ttl", 60))
+	if err != nil {
+		uc.Log.Error("[SchedulerUseCase.tick] " + err.Error())
+		leader = false
+	}
+
+	var lastRuns map[string]time.Time
+	if leader {
+		lastRuns = uc.lastRunStarts()
+	}
+
+	now := time.Now().In(uc.location())
+	var due []*schedulerJob
+
+	schedulerJobs.mu.Lock()
+	if leader != schedulerJobs.leader {
+		uc.Log.Infof("[SchedulerUseCase.tick] scheduler %s leader: %t", schedulerJobs.owner, leader)
+		schedulerJobs.leader = leader
+	}
+	if leader {
+		// the next run follows the last run recorded by any replica, so a job the previous leader
+		// just ran is not run again after a takeover, and a job it missed runs once
+		for _, job := range schedulerJobs.jobs {
+			if startedAt, ok := lastRuns[job.name]; ok {
+				job.nextRunAt = job.schedule.Next(startedAt.In(now.Location()))
+			}
+			if job.running || now.Before(job.nextRunAt) {
+				continue
+			}
+			job.running = true
+			job.nextRunAt = job.schedule.Next(now)
+			due = append(due, job)
+		}
+	}
+	schedulerJobs.mu.Unlock()
+
+	for _, job := range due {
+		if err := uc.lockJob(job); err != nil {
+			uc.Log.Infof("[SchedulerUseCase.tick] skipping job %s: %v", job.name, err)
+			uc.finish(job)
+			continue
+		}
+		run, err := uc.startRun(job, entity.SCHEDULER_JOB_RUN_TRIGGER_SCHEDULE)
+		if err != nil {
+			uc.Log.Error("[SchedulerUseCase.tick] " + err.Error())
+			uc.unlockJob(job)
+			uc.finish(job)
+			continue
+		}
+		go uc.execute(job, run)
+	}
+}
+
+// lastRunStarts returns when each job last started on any replica, nil when the history cannot
+// be read and the jobs fall back to the schedule kept by this replica
+func (uc *SchedulerUseCase) lastRunStarts() map[string]time.Time {
+	runs, err := uc.Repository.FindLastJobRuns()
+	if err != nil {
+		uc.Log.Error("[SchedulerUseCase.lastRunStarts] " + err.Error())
+		return nil
+	}
+
+	starts := make(map[string]time.Time, len(runs))
+	for _, run := range runs {
+		// started_at is a timestamp without time zone holding the wall clock of the replica
+		t := run.StartedAt
+		starts[run.JobName] = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+	}
+	return starts
+}
+
+// lockJob takes the lock of a single job, so a job started by hand on one replica and by the
+// schedule on the leader never run at the same time. The lock outlives the job timeout so a
+// stuck run cannot be overtaken.
+func (uc *SchedulerUseCase) lockJob(job *schedulerJob) error {
+	ttl := uc.seconds("scheduler.job_timeout", 600) + uc.seconds("scheduler.lock_ttl", 60)
+	locked, err := uc.Repository.AcquireLock(SCHEDULER_LOCK_NAME+":"+job.name, schedulerJobs.owner, ttl)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return ErrSchedulerJobRunning
+	}
+	return nil
+}
+
+func (uc *SchedulerUseCase) unlockJob(job *schedulerJob) {
+	if err := uc.Repository.ReleaseLock(SCHEDULER_LOCK_NAME+":"+job.name, schedulerJobs.owner); err != nil {
+		uc.Log.Error("[SchedulerUseCase.unlockJob] " + err.Error())
+	}
+}
+
+// RunJob runs a job right away on this replica, whatever its schedule. It is refused while the
+// job runs on any replica.
+func (uc *SchedulerUseCase) RunJob(name string) (*response.SchedulerJobRunResponse, error) {
+	schedulerJobs.mu.Lock()
+	job, ok := schedulerJobs.jobs[name]
+	if !ok {
+		schedulerJobs.mu.Unlock()
+		return nil, ErrSchedulerJobNotFound
+	}
+	if job.running {
+		schedulerJobs.mu.Unlock()
+		return nil, ErrSchedulerJobRunning
+	}
+	job.running = true
+	schedulerJobs.mu.Unlock()
+
+	if err := uc.lockJob(job); err != nil {
+		if !errors.Is(err, ErrSchedulerJobRunning) {
+			uc.Log.Error("[SchedulerUseCase.RunJob] " + err.Error())
+		}
+		uc.finish(job)
+		return nil, err
+	}
+	run, err := uc.startRun(job, entity.SCHEDULER_JOB_RUN_TRIGGER_MANUAL)
+	if err != nil {
+		uc.Log.Error("[SchedulerUseCase.RunJob] " + err.Error())
+		uc.unlockJob(job)
+		uc.finish(job)
+		return nil, err
+	}
+	go uc.execute(job, run)
+
+	return uc.DTO.ConvertJobRunEntityToResponse(run), nil
+}
+
+func (uc *SchedulerUseCase) startRun(job *schedulerJob, trigger entity.SchedulerJobRunTrigger) (*entity.SchedulerJobRun, error) {
+	return uc.Repository.CreateJobRun(&entity.SchedulerJobRun{
+		JobName:   job.name,
+		Trigger:   trigger,
+		Owner:     schedulerJobs.owner,
+		Status:    entity.SCHEDULER_JOB_RUN_STATUS_RUNNING,
+		StartedAt: time.Now(),
+	})
+}
+
+func (uc *SchedulerUseCase) execute(job *schedulerJob, run *entity.SchedulerJobRun) {
+	defer uc.finish(job)
+	defer uc.unlockJob(job)
+
+	ctx, cancel := context.WithTimeout(context.Background(), uc.seconds("scheduler.job_timeout", 600))
+	defer cancel()
+
+	result, err := runSchedulerJob(ctx, job)
+	finishedAt := time.Now()
+	fields := map[string]interface{}{
+		"status":      entity.SCHEDULER_JOB_RUN_STATUS_SUCCEEDED,
+		"finished_at": finishedAt,
+		"result":      result,
+	}
+	if err != nil {
+		uc.Log.Errorf("[SchedulerUseCase.execute] job %s failed: %v", job.name, err)
+		fields["status"] = entity.SCHEDULER_JOB_RUN_STATUS_FAILED
+		fields["error"] = err.Error()
+	} else {
+		uc.Log.Infof("[SchedulerUseCase.execute] job %s done in %s: %s", job.name, finishedAt.Sub(run.StartedAt), result)
+	}
+
+	if err := uc.Repository.UpdateJobRunFields(run.ID, fields); err != nil {
+		uc.Log.Error("[SchedulerUseCase.execute] " + err.Error())
+	}
+}
+
+func runSchedulerJob(ctx context.Context, job *schedulerJob) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.run(ctx)
+}
+
+func (uc *SchedulerUseCase) finish(job *schedulerJob) {
+	schedulerJobs.mu.Lock()
+	job.running = false
+	schedulerJobs.mu.Unlock()
+}
+
+func (uc *SchedulerUseCase) purgeJobRuns(ctx context.Context) (string, error) {
+	days := uc.Viper.GetInt("scheduler.history_days")
+	if days <= 0 {
+		days = 30
+	}
+	deleted, err := uc.Repository.DeleteJobRunsBefore(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d runs older than %d days", deleted, days), nil
+}
+
+func (uc *SchedulerUseCase) FindAllJobs() ([]response.SchedulerJobResponse, error) {
+	lastRuns, err := uc.Repository.FindLastJobRuns()
+	if err != nil {
+		uc.Log.Error("[SchedulerUseCase.FindAllJobs] " + err.Error())
+		return nil, err
+	}
+	lastRunByJob := make(map[string]*response.SchedulerJobRunResponse, len(lastRuns))
+	for i := range lastRuns {
+		lastRunByJob[lastRuns[i].JobName] = uc.DTO.ConvertJobRunEntityToResponse(&lastRuns[i])
+	}
+
+	schedulerJobs.mu.Lock()
+	defer schedulerJobs.mu.Unlock()
+
+	res := make([]response.SchedulerJobResponse, 0, len(schedulerJobs.jobs))
+	for _, job := range schedulerJobs.jobs {
+		nextRunAt := job.nextRunAt
+		res = append(res, response.SchedulerJobResponse{
+			Name:      job.name,
+			Schedule:  job.schedule.Spec,
+			Running:   job.running,
+			NextRunAt: &nextRunAt,
+			LastRun:   lastRunByJob[job.name],
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res, nil
+}
+
+func (uc *SchedulerUseCase) FindAllJobRunsPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]response.SchedulerJobRunResponse, int64, error) {
+	runs, total, err := uc.Repository.FindAllJobRunsPaginated(page, pageSize, sort, filter)
+	if err != nil {
+		uc.Log.Error("[SchedulerUseCase.FindAllJobRunsPaginated] " + err.Error())
+		return nil, 0, err
+	}
+
+	res := make([]response.SchedulerJobRunResponse, 0, len(*runs))
+	for i := range *runs {
+		res = append(res, *uc.DTO.ConvertJobRunEntityToResponse(&(*runs)[i]))
+	}
+
+	return &res, total, nil
+}
@@ -0,0 +1,495 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/messaging"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ISlaUseCase tracks the stage SLAs of the template activity lines: applicants sitting in a stage
+// too long and assessors late with their results
+type ISlaUseCase interface {
+	RegisterJobs(scheduler ISchedulerUseCase) error
+	NotifyOverdue(ctx context.Context) (string, error)
+	FindOverdueQueue(req *request.SlaOverdueQueueRequest, employeeID uuid.UUID, orgScope *repository.OrganizationScope) (*response.SlaOverdueQueueResponse, error)
+}
+
+type SlaUseCase struct {
+	Log                 *logrus.Logger
+	Viper               *viper.Viper
+	Repository          repository.ISlaRepository
+	UserMessage         messaging.IUserMessage
+	EmployeeMessage     messaging.IEmployeeMessage
+	MailMessage         messaging.IMailMessage
+	NotificationService service.INotificationService
+}
+
+func NewSlaUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.ISlaRepository,
+	userMessage messaging.IUserMessage,
+	employeeMessage messaging.IEmployeeMessage,
+	mailMessage messaging.IMailMessage,
+	notificationService service.INotificationService,
+) ISlaUseCase {
+	return &SlaUseCase{
+		Log:                 log,
+		Viper:               viper,
+		Repository:          repo,
+		UserMessage:         userMessage,
+		EmployeeMessage:     employeeMessage,
+		MailMessage:         mailMessage,
+		NotificationService: notificationService,
+	}
+}
+
+func SlaUseCaseFactory(log *logrus.Logger, viper *viper.Viper) ISlaUseCase {
+	repo := repository.SlaRepositoryFactory(log)
+	userMessage := messaging.UserMessageFactory(log)
+	employeeMessage := messaging.EmployeeMessageFactory(log)
+	mailMessage := messaging.MailMessageFactory(log)
+	notificationService := service.NotificationServiceFactory(viper, log)
+	return NewSlaUseCase(
+		log,
+		viper,
+		repo,
+		userMessage,
+		employeeMessage,
+		mailMessage,
+		notificationService,
+	)
+}
+
+func (uc *SlaUseCase) RegisterJobs(scheduler ISchedulerUseCase) error {
+	if err := scheduler.RegisterJob("notify_overdue_stages", "0 8 * * *", uc.NotifyOverdue); err != nil {
+		uc.Log.Error("[SlaUseCase.RegisterJobs] " + err.Error())
+		return err
+	}
+	return nil
+}
+
+// slaOverdueItem is an overdue item with the employees it is alerted to
+type slaOverdueItem struct {
+	Key         string
+	ReferenceID uuid.UUID
+	Item        response.SlaOverdueItemResponse
+}
+
+func (uc *SlaUseCase) location() *time.Location {
+	name := uc.Viper.GetString("scheduler.timezone")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		uc.Log.Warnf("[SlaUseCase.location] %v, using UTC+7", err)
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+func (uc *SlaUseCase) escalationAfter() time.Duration {
+	days := 3
+	if uc.Viper.IsSet("sla.escalation.after_days") {
+		days = uc.Viper.GetInt("sla.escalation.after_days")
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// escalationEmployeeIDs are sla.escalation.employee_ids, alerted of every item overdue for
+// longer than sla.escalation.after_days
+func (uc *SlaUseCase) escalationEmployeeIDs() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, value := range uc.Viper.GetStringSlice("sla.escalation.employee_ids") {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			uc.Log.Warnf("[SlaUseCase.escalationEmployeeIDs] skipping invalid employee id %q", value)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// overdueItems computes the items past their SLA. An applicant is due to leave a stage
+// MaxDaysInStage days after entering it, or when the project recruitment line ends if that is
+// earlier. Assessors are due to fill their results MaxDaysToFillResult days after the schedule
+// date.
+func (uc *SlaUseCase) overdueItems(now time.Time, orgScope *repository.OrganizationScope) ([]slaOverdueItem, error) {
+	loc := uc.location()
+	escalateAfter := uc.escalationAfter()
+
+	stages, err := uc.Repository.FindStageItems(orgScope)
+	if err != nil {
+		return nil, err
+	}
+	results, err := uc.Repository.FindResultItems(orgScope)
+	if err != nil {
+		return nil, err
+	}
+
+	lineIDs := make([]uuid.UUID, 0, len(stages))
+	seen := make(map[uuid.UUID]bool)
+	for _, stage := range stages {
+		if !seen[stage.ProjectRecruitmentLineID] {
+			seen[stage.ProjectRecruitmentLineID] = true
+			lineIDs = append(lineIDs, stage.ProjectRecruitmentLineID)
+		}
+	}
+	pics, err := uc.Repository.FindPicEmployeeIDs(lineIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []slaOverdueItem
+	add := func(item slaOverdueItem) {
+		if !now.After(item.Item.DueAt) {
+			return
+		}
+		overdue := now.Sub(item.Item.DueAt)
+		item.Item.DaysOverdue = int(overdue / (24 * time.Hour))
+		item.Item.Escalated = overdue >= escalateAfter
+		if item.Item.PicEmployeeIDs == nil {
+			item.Item.PicEmployeeIDs = []uuid.UUID{}
+		}
+		items = append(items, item)
+	}
+
+	for _, stage := range stages {
+		dueAt := stage.EnteredAt.Add(time.Duration(stage.MaxDaysInStage) * 24 * time.Hour)
+		if stage.EndDate != nil && !stage.EndDate.IsZero() {
+			lineEnd := time.Date(stage.EndDate.Year(), stage.EndDate.Month(), stage.EndDate.Day()+1, 0, 0, 0, 0, loc)
+			if lineEnd.Before(dueAt) {
+				dueAt = lineEnd
+			}
+		}
+		applicantID := stage.ApplicantID
+		add(slaOverdueItem{
+			Key:         fmt.Sprintf("%s:%s:%d", entity.SLA_ALERT_TYPE_STAGE, stage.ApplicantID, stage.Order),
+			ReferenceID: stage.ApplicantID,
+			Item: response.SlaOverdueItemResponse{
+				Type:                     entity.SLA_ALERT_TYPE_STAGE,
+				ProjectRecruitmentLineID: stage.ProjectRecruitmentLineID,
+				StageName:                stage.StageName,
+				JobPostingID:             stage.JobPostingID,
+				JobPostingName:           stage.JobPostingName,
+				ApplicantID:              &applicantID,
+				ApplicantName:            stage.ApplicantName,
+				PicEmployeeIDs:           pics[stage.ProjectRecruitmentLineID],
+				StartedAt:                stage.EnteredAt,
+				DueAt:                    dueAt,
+			},
+		})
+	}
+
+	for _, result := range results {
+		scheduleDay := time.Date(result.ScheduleDate.Year(), result.ScheduleDate.Month(), result.ScheduleDate.Day(), 0, 0, 0, 0, loc)
+		scheduleID := result.ScheduleID
+		assessorEmployeeID := result.AssessorEmployeeID
+		var picEmployeeIDs []uuid.UUID
+		if result.PicEmployeeID != nil {
+			picEmployeeIDs = []uuid.UUID{*result.PicEmployeeID}
+		}
+		add(slaOverdueItem{
+			Key:         fmt.Sprintf("%s:%s:%s", entity.SLA_ALERT_TYPE_RESULT, result.ScheduleType, result.AssessorID),
+			ReferenceID: result.AssessorID,
+			Item: response.SlaOverdueItemResponse{
+				Type:                     entity.SLA_ALERT_TYPE_RESULT,
+				ProjectRecruitmentLineID: result.ProjectRecruitmentLineID,
+				StageName:                result.StageName,
+				JobPostingID:             result.JobPostingID,
+				JobPostingName:           result.JobPostingName,
+				ScheduleType:             string(result.ScheduleType),
+				ScheduleID:               &scheduleID,
+				ScheduleName:             result.ScheduleName,
+				AssessorEmployeeID:       &assessorEmployeeID,
+				PendingResults:           result.PendingResults,
+				PicEmployeeIDs:           picEmployeeIDs,
+				StartedAt:                scheduleDay,
+				DueAt:                    scheduleDay.AddDate(0, 0, result.MaxDaysToFillResult+1),
+			},
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Item.DueAt.Before(items[j].Item.DueAt)
+	})
+	return items, nil
+}
+
+// FindOverdueQueue returns the overdue items an employee is responsible for, as PIC of the stage
+// or as assessor, most overdue first
+func (uc *SlaUseCase) FindOverdueQueue(req *request.SlaOverdueQueueRequest, employeeID uuid.UUID, orgScope *repository.OrganizationScope) (*response.SlaOverdueQueueResponse, error) {
+	items, err := uc.overdueItems(time.Now(), orgScope)
+	if err != nil {
+		uc.Log.Error("[SlaUseCase.FindOverdueQueue] " + err.Error())
+		return nil, err
+	}
+
+	res := &response.SlaOverdueQueueResponse{
+		EmployeeID: employeeID,
+		Items:      []response.SlaOverdueItemResponse{},
+	}
+	for _, item := range items {
+		if req.Type != "" && string(item.Item.Type) != req.Type {
+			continue
+		}
+		if !slaResponsible(item.Item, employeeID) {
+			continue
+		}
+		res.Items = append(res.Items, item.Item)
+		if item.Item.Escalated {
+			res.Escalated++
+		}
+	}
+	res.Total = len(res.Items)
+
+	return res, nil
+}
+
+func slaResponsible(item response.SlaOverdueItemResponse, employeeID uuid.UUID) bool {
+	if item.AssessorEmployeeID != nil && *item.AssessorEmployeeID == employeeID {
+		return true
+	}
+	for _, id := range item.PicEmployeeIDs {
+		if id == employeeID {
+			return true
+		}
+	}
+	return false
+}
+
+// slaRecipient is an employee with the overdue items to alert in this run
+type slaRecipient struct {
+	EmployeeID uuid.UUID
+	Alerts     []entity.SlaAlert
+	Items      []response.SlaOverdueItemResponse
+	itemKeys   map[string]bool
+}
+
+// NotifyOverdue alerts the PICs and assessors of the items that became overdue, and the
+// escalation contacts of the ones overdue for too long. Every employee gets one digest per run,
+// an item is never alerted twice to the same employee at the same level.
+func (uc *SlaUseCase) NotifyOverdue(ctx context.Context) (string, error) {
+	now := time.Now()
+	items, err := uc.overdueItems(now, nil)
+	if err != nil {
+		uc.Log.Error("[SlaUseCase.NotifyOverdue] " + err.Error())
+		return "", err
+	}
+	if len(items) == 0 {
+		return "no overdue items", nil
+	}
+
+	escalationIDs := uc.escalationEmployeeIDs()
+	type plannedAlert struct {
+		Alert entity.SlaAlert
+		Item  slaOverdueItem
+	}
+	var planned []plannedAlert
+	plan := func(item slaOverdueItem, level entity.SlaAlertLevel, employeeID uuid.UUID) {
+		planned = append(planned, plannedAlert{
+			Alert: entity.SlaAlert{
+				DedupKey:    fmt.Sprintf("%s:%s:%s:%d", item.Key, level, employeeID, item.Item.DueAt.Unix()),
+				Type:        item.Item.Type,
+				Level:       level,
+				ReferenceID: item.ReferenceID,
+				EmployeeID:  employeeID,
+				DueAt:       item.Item.DueAt.UTC(),
+			},
+			Item: item,
+		})
+	}
+	for _, item := range items {
+		for _, picID := range item.Item.PicEmployeeIDs {
+			plan(item, entity.SLA_ALERT_LEVEL_PIC, picID)
+		}
+		if item.Item.AssessorEmployeeID != nil {
+			plan(item, entity.SLA_ALERT_LEVEL_ASSESSOR, *item.Item.AssessorEmployeeID)
+		}
+		if item.Item.Escalated {
+			for _, escalationID := range escalationIDs {
+				plan(item, entity.SLA_ALERT_LEVEL_ESCALATION, escalationID)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(planned))
+	for _, p := range planned {
+		keys = append(keys, p.Alert.DedupKey)
+	}
+	existing, err := uc.Repository.FindAlertsByDedupKeys(keys)
+	if err != nil {
+		uc.Log.Error("[SlaUseCase.NotifyOverdue] " + err.Error())
+		return "", err
+	}
+	maxAttempts := uc.Viper.GetInt("sla.max_attempts")
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	done := make(map[string]entity.SlaAlert, len(existing))
+	for _, alert := range existing {
+		done[alert.DedupKey] = alert
+	}
+
+	var recipients []*slaRecipient
+	byEmployee := make(map[uuid.UUID]*slaRecipient)
+	for _, p := range planned {
+		if alert, ok := done[p.Alert.DedupKey]; ok {
+			if alert.Status == entity.SLA_ALERT_STATUS_SENT || alert.Attempts >= maxAttempts {
+				continue
+			}
+			p.Alert.Attempts = alert.Attempts
+		}
+
+		recipient, ok := byEmployee[p.Alert.EmployeeID]
+		if !ok {
+			recipient = &slaRecipient{EmployeeID: p.Alert.EmployeeID, itemKeys: make(map[string]bool)}
+			byEmployee[p.Alert.EmployeeID] = recipient
+			recipients = append(recipients, recipient)
+		}
+		recipient.Alerts = append(recipient.Alerts, p.Alert)
+		// an employee alerted of an item at two levels (PIC and escalation contact) sees it once
+		if !recipient.itemKeys[p.Item.Key] {
+			recipient.itemKeys[p.Item.Key] = true
+			recipient.Items = append(recipient.Items, p.Item.Item)
+		}
+	}
+
+	var sent, failed int
+	for _, recipient := range recipients {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sendErr := uc.sendDigest(recipient)
+		for i := range recipient.Alerts {
+			alert := &recipient.Alerts[i]
+			alert.Attempts++
+			if sendErr != nil {
+				alert.Status = entity.SLA_ALERT_STATUS_FAILED
+				alert.LastError = sendErr.Error()
+				continue
+			}
+			sentAt := now
+			alert.Status = entity.SLA_ALERT_STATUS_SENT
+			alert.SentAt = &sentAt
+			alert.LastError = ""
+		}
+		if sendErr != nil {
+			failed++
+			uc.Log.Errorf("[SlaUseCase.NotifyOverdue] alerting employee %s failed: %v", recipient.EmployeeID, sendErr)
+		} else {
+			sent++
+		}
+
+		if err := uc.Repository.SaveAlerts(recipient.Alerts); err != nil {
+			uc.Log.Error("[SlaUseCase.NotifyOverdue] " + err.Error())
+		}
+	}
+
+	return fmt.Sprintf("%d overdue items, alerted %d employees, %d failed", len(items), sent, failed), nil
+}
+
+// sendDigest sends the overdue items of a recipient as one notification and one email. It only
+// fails when neither could be sent.
+func (uc *SlaUseCase) sendDigest(recipient *slaRecipient) error {
+	notificationErr := uc.sendNotification(recipient)
+	if notificationErr != nil {
+		uc.Log.Warnf("[SlaUseCase.sendDigest] notification to employee %s failed: %v", recipient.EmployeeID, notificationErr)
+	}
+	mailErr := uc.sendMail(recipient)
+	if mailErr != nil {
+		uc.Log.Warnf("[SlaUseCase.sendDigest] email to employee %s failed: %v", recipient.EmployeeID, mailErr)
+	}
+
+	if notificationErr != nil && mailErr != nil {
+		return errors.New(notificationErr.Error() + "; " + mailErr.Error())
+	}
+	return nil
+}
+
+func (uc *SlaUseCase) sendNotification(recipient *slaRecipient) error {
+	user, err := uc.UserMessage.SendFindUserByEmployeeIDMessage(recipient.EmployeeID.String())
+	if err != nil {
+		return err
+	}
+	if user == nil || user.ID == "" {
+		return errors.New("employee has no user")
+	}
+
+	url := uc.Viper.GetString("sla.url")
+	if url == "" {
+		url = "/"
+	}
+
+	message := fmt.Sprintf("%d recruitment items are past their SLA.", len(recipient.Items))
+	if len(recipient.Items) == 1 {
+		message = slaItemSummary(recipient.Items[0]) + " is past its SLA."
+	}
+
+	// alerts are sent by the system, there is no acting user to put as the creator
+	return uc.NotificationService.CreateSlaOverdueNotification(user.ID, []string{user.ID}, message, url)
+}
+
+func (uc *SlaUseCase) sendMail(recipient *slaRecipient) error {
+	employee, err := uc.EmployeeMessage.SendFindEmployeeByIDMessage(request.SendFindEmployeeByIDMessageRequest{
+		ID: recipient.EmployeeID.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if employee == nil || employee.Email == "" {
+		return errors.New("employee has no email")
+	}
+
+	if _, err := uc.MailMessage.SendMail(&request.MailRequest{
+		Email:   employee.Email,
+		Subject: fmt.Sprintf("Overdue recruitment stages (%d)", len(recipient.Items)),
+		Body:    slaMailBody(recipient.Items, employee.Name, uc.location()),
+		From:    uc.Viper.GetString("mail.from"),
+		To:      employee.Email,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func slaItemSummary(item response.SlaOverdueItemResponse) string {
+	if item.Type == entity.SLA_ALERT_TYPE_RESULT {
+		return fmt.Sprintf("%d %s results of %s (%s)", item.PendingResults, strings.ToLower(item.ScheduleType), item.ScheduleName, item.JobPostingName)
+	}
+	return fmt.Sprintf("%s in stage %s (%s)", item.ApplicantName, item.StageName, item.JobPostingName)
+}
+
+func slaMailBody(items []response.SlaOverdueItemResponse, name string, loc *time.Location) string {
+	var b strings.Builder
+	if name != "" {
+		fmt.Fprintf(&b, "<p>Dear %s,</p>", html.EscapeString(name))
+	}
+	b.WriteString("<p>The following recruitment items are past their SLA:</p><ul>")
+	for _, item := range items {
+		fmt.Fprintf(&b, "<li>%s, due %s (%d days overdue)", html.EscapeString(slaItemSummary(item)), item.DueAt.In(loc).Format("02 Jan 2006 15:04 MST"), item.DaysOverdue)
+		if item.Escalated {
+			b.WriteString(", <b>escalated</b>")
+		}
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul><p>Please follow them up.</p>")
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var ErrStorageFileForbidden = errors.New("you are not allowed to access this file")
+
+type IStorageUseCase interface {
+	Open(filePath string, expires string, signature string) (io.ReadCloser, string, error)
+	SignURL(filePath string, userID uuid.UUID, staff bool) (string, error)
+}
+
+type StorageUseCase struct {
+	Log                    *logrus.Logger
+	Viper                  *viper.Viper
+	Storage                storage.IStorage
+	UserProfileRepository  repository.IUserProfileRepository
+	PersonalDataRepository repository.IPersonalDataRepository
+}
+
+func NewStorageUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	fileStorage storage.IStorage,
+	upRepository repository.IUserProfileRepository,
+	pdRepository repository.IPersonalDataRepository,
+) IStorageUseCase {
+	return &StorageUseCase{
+		Log:                    log,
+		Viper:                  viper,
+		Storage:                fileStorage,
+		UserProfileRepository:  upRepository,
+		PersonalDataRepository: pdRepository,
+	}
+}
+
+func StorageUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IStorageUseCase {
+	fileStorage := storage.StorageFactory(viper, log)
+	upRepository := repository.UserProfileRepositoryFactory(log)
+	pdRepository := repository.PersonalDataRepositoryFactory(log)
+	return NewStorageUseCase(log, viper, fileStorage, upRepository, pdRepository)
+}
+
+// Open checks the signature of a download link and opens the file. Public files need no
+// signature. It returns the file and its storage key.
+func (uc *StorageUseCase) Open(filePath string, expires string, signature string) (io.ReadCloser, string, error) {
+	key, err := storage.NormalizeKey(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+	if storage.IsQuarantined(key) {
+		return nil, "", ErrStorageFileForbidden
+	}
+
+	if !storage.IsPublic(uc.Viper, key) {
+		if err := storage.VerifySignedURL(uc.Viper, key, expires, signature); err != nil {
+			return nil, "", err
+		}
+	}
+
+	body, err := uc.Storage.Get(key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrStorageFileNotFound) {
+			uc.Log.Error("[StorageUseCase.Open] " + err.Error())
+		}
+		return nil, "", err
+	}
+
+	return body, key, nil
+}
+
+// SignURL returns a fresh download link. Staff can link any file but the quarantined uploads,
+// candidates only public files and the files of their own profile.
+func (uc *StorageUseCase) SignURL(filePath string, userID uuid.UUID, staff bool) (string, error) {
+	key, err := storage.NormalizeKey(filePath)
+	if err != nil {
+		return "", err
+	}
+	if storage.IsQuarantined(key) {
+		return "", ErrStorageFileForbidden
+	}
+
+	if !staff && !storage.IsPublic(uc.Viper, key) {
+		owned, err := uc.isOwnFile(key, userID)
+		if err != nil {
+			return "", err
+		}
+		if !owned {
+			return "", ErrStorageFileForbidden
+		}
+	}
+
+	exists, err := uc.Storage.Exists(key)
+	if err != nil {
+		uc.Log.Error("[StorageUseCase.SignURL] " + err.Error())
+		return "", err
+	}
+	if !exists {
+		return "", storage.ErrStorageFileNotFound
+	}
+
+	return storage.URL(uc.Viper, key), nil
+}
+
+func (uc *StorageUseCase) isOwnFile(key string, userID uuid.UUID) (bool, error) {
+	profile, err := uc.UserProfileRepository.FindByUserID(userID)
+	if err != nil {
+		uc.Log.Error("[StorageUseCase.isOwnFile] " + err.Error())
+		return false, err
+	}
+	if profile == nil {
+		return false, nil
+	}
+
+	files, err := uc.PersonalDataRepository.FindFiles(profile.ID)
+	if err != nil {
+		uc.Log.Error("[StorageUseCase.isOwnFile] " + err.Error())
+		return false, err
+	}
+
+	for _, file := range files {
+		if fileKey, err := storage.NormalizeKey(file.Path); err == nil && fileKey == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
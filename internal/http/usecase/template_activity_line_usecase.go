@@ -99,12 +99,14 @@ func (uc *TemplateActivityLineUseCase) CreateOrUpdateTemplateActivityLine(req *r
 
 				if exist == nil {
 					_, err := uc.Repository.CreateTemplateActivityLine(&entity.TemplateActivityLine{
-						TemplateActivityID: ta.ID,
-						Name:               templateActivity.Name,
-						Description:        templateActivity.Description,
-						Status:             entity.TemplateActivityLineStatus(templateActivity.Status),
-						QuestionTemplateID: tq.ID,
-						ColorHexCode:       templateActivity.ColorHexCode,
+						TemplateActivityID:  ta.ID,
+						Name:                templateActivity.Name,
+						Description:         templateActivity.Description,
+						Status:              entity.TemplateActivityLineStatus(templateActivity.Status),
+						QuestionTemplateID:  tq.ID,
+						ColorHexCode:        templateActivity.ColorHexCode,
+						MaxDaysInStage:      templateActivity.MaxDaysInStage,
+						MaxDaysToFillResult: templateActivity.MaxDaysToFillResult,
 					})
 					if err != nil {
 						uc.Log.Errorf("[TemplateActivityLineUseCase.CreateOrUpdateTemplateActivityLine] error when creating template activity line: %s", err.Error())
@@ -112,13 +114,15 @@ func (uc *TemplateActivityLineUseCase) CreateOrUpdateTemplateActivityLine(req *r
 					}
 				} else {
 					_, err := uc.Repository.UpdateTemplateActivityLine(&entity.TemplateActivityLine{
-						ID:                 exist.ID,
-						TemplateActivityID: ta.ID,
-						Name:               templateActivity.Name,
-						Description:        templateActivity.Description,
-						Status:             entity.TemplateActivityLineStatus(templateActivity.Status),
-						QuestionTemplateID: tq.ID,
-						ColorHexCode:       templateActivity.ColorHexCode,
+						ID:                  exist.ID,
+						TemplateActivityID:  ta.ID,
+						Name:                templateActivity.Name,
+						Description:         templateActivity.Description,
+						Status:              entity.TemplateActivityLineStatus(templateActivity.Status),
+						QuestionTemplateID:  tq.ID,
+						ColorHexCode:        templateActivity.ColorHexCode,
+						MaxDaysInStage:      templateActivity.MaxDaysInStage,
+						MaxDaysToFillResult: templateActivity.MaxDaysToFillResult,
 					})
 					if err != nil {
 						uc.Log.Errorf("[TemplateActivityLineUseCase.CreateOrUpdateTemplateActivityLine] error when updating template activity line: %s", err.Error())
@@ -127,12 +131,14 @@ func (uc *TemplateActivityLineUseCase) CreateOrUpdateTemplateActivityLine(req *r
 				}
 			} else {
 				_, err := uc.Repository.CreateTemplateActivityLine(&entity.TemplateActivityLine{
-					TemplateActivityID: ta.ID,
-					Name:               templateActivity.Name,
-					Description:        templateActivity.Description,
-					Status:             entity.TemplateActivityLineStatus(templateActivity.Status),
-					QuestionTemplateID: tq.ID,
-					ColorHexCode:       templateActivity.ColorHexCode,
+					TemplateActivityID:  ta.ID,
+					Name:                templateActivity.Name,
+					Description:         templateActivity.Description,
+					Status:              entity.TemplateActivityLineStatus(templateActivity.Status),
+					QuestionTemplateID:  tq.ID,
+					ColorHexCode:        templateActivity.ColorHexCode,
+					MaxDaysInStage:      templateActivity.MaxDaysInStage,
+					MaxDaysToFillResult: templateActivity.MaxDaysToFillResult,
 				})
 				if err != nil {
 					uc.Log.Errorf("[TemplateActivityLineUseCase.CreateOrUpdateTemplateActivityLine] error when creating template activity line: %s", err.Error())
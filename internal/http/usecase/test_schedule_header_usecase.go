@@ -17,7 +17,7 @@ import (
 )
 
 type ITestScheduleHeaderUsecase interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.TestScheduleHeaderResponse, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.TestScheduleHeaderResponse, int64, error)
 	CreateTestScheduleHeader(req *request.CreateTestScheduleHeaderRequest) (*response.TestScheduleHeaderResponse, error)
 	UpdateTestScheduleHeader(req *request.UpdateTestScheduleHeaderRequest) (*response.TestScheduleHeaderResponse, error)
 	FindByID(id uuid.UUID) (*response.TestScheduleHeaderResponse, error)
@@ -41,6 +41,7 @@ type TestScheduleHeaderUsecase struct {
 	UserProfileRepository              repository.IUserProfileRepository
 	ApplicantRepository                repository.IApplicantRepository
 	TestApplicantRepository            repository.ITestApplicantRepository
+	ScheduleReminderRepository         repository.IScheduleReminderRepository
 }
 
 func NewTestScheduleHeaderUsecase(
@@ -56,6 +57,7 @@ func NewTestScheduleHeaderUsecase(
 	upRepo repository.IUserProfileRepository,
 	applicantRepo repository.IApplicantRepository,
 	taRepo repository.ITestApplicantRepository,
+	srRepo repository.IScheduleReminderRepository,
 ) ITestScheduleHeaderUsecase {
 	return &TestScheduleHeaderUsecase{
 		Log:                                log,
@@ -70,6 +72,7 @@ func NewTestScheduleHeaderUsecase(
 		UserProfileRepository:              upRepo,
 		ApplicantRepository:                applicantRepo,
 		TestApplicantRepository:            taRepo,
+		ScheduleReminderRepository:         srRepo,
 	}
 }
 
@@ -84,11 +87,12 @@ func TestScheduleHeaderUsecaseFactory(log *logrus.Logger, viper *viper.Viper) IT
 	upRepo := repository.UserProfileRepositoryFactory(log)
 	applicantRepo := repository.ApplicantRepositoryFactory(log)
 	taRepo := repository.TestApplicantRepositoryFactory(log)
-	return NewTestScheduleHeaderUsecase(log, repo, tshDTO, viper, jpRepo, ttRepo, ppRepo, prhRepo, prlRepo, upRepo, applicantRepo, taRepo)
+	srRepo := repository.ScheduleReminderRepositoryFactory(log)
+	return NewTestScheduleHeaderUsecase(log, repo, tshDTO, viper, jpRepo, ttRepo, ppRepo, prhRepo, prlRepo, upRepo, applicantRepo, taRepo, srRepo)
 }
 
-func (uc *TestScheduleHeaderUsecase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]response.TestScheduleHeaderResponse, int64, error) {
-	testScheduleHeaders, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort)
+func (uc *TestScheduleHeaderUsecase) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *repository.OrganizationScope) (*[]response.TestScheduleHeaderResponse, int64, error) {
+	testScheduleHeaders, total, err := uc.Repository.FindAllPaginated(page, pageSize, search, sort, orgScope)
 	if err != nil {
 		uc.Log.Error("[TestScheduleHeaderUsecase.FindAllPaginated] " + err.Error())
 		return nil, 0, err
@@ -568,6 +572,8 @@ func (uc *TestScheduleHeaderUsecase) UpdateTestScheduleHeader(req *request.Updat
 		}
 	}
 
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_TEST, testScheduleHeader.ID)
+
 	findByID, err := uc.Repository.FindByID(testScheduleHeader.ID)
 	if err != nil {
 		uc.Log.Error("[TestScheduleHeaderUsecase.UpdateTestScheduleHeader] " + err.Error())
@@ -618,7 +624,12 @@ func (uc *TestScheduleHeaderUsecase) DeleteTestScheduleHeader(id uuid.UUID) erro
 		return errors.New("Test Schedule Header not found")
 	}
 
-	return uc.Repository.DeleteTestScheduleHeader(id)
+	if err := uc.Repository.DeleteTestScheduleHeader(id); err != nil {
+		return err
+	}
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_TEST, id)
+
+	return nil
 }
 
 func (uc *TestScheduleHeaderUsecase) GenerateDocumentNumber(dateNow time.Time) (string, error) {
@@ -762,6 +773,7 @@ func (uc *TestScheduleHeaderUsecase) UpdateStatusTestScheduleHeader(req *request
 		uc.Log.Error("[TestScheduleHeaderUsecase.UpdateStatusTestScheduleHeader] " + err.Error())
 		return err
 	}
+	cancelScheduleReminders(uc.Log, uc.ScheduleReminderRepository, entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_TEST, exist.ID)
 
 	return nil
 }
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/dto"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/repository"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type ITokenRevocationUseCase interface {
+	RevokeToken(req *request.RevokeTokenRequest) (*response.TokenRevocationResponse, error)
+	PurgeExpired() (int64, error)
+}
+
+type TokenRevocationUseCase struct {
+	Log        *logrus.Logger
+	Viper      *viper.Viper
+	Repository repository.ITokenRevocationRepository
+	DTO        dto.ITokenRevocationDTO
+	Service    service.ITokenRevocationService
+}
+
+func NewTokenRevocationUseCase(
+	log *logrus.Logger,
+	viper *viper.Viper,
+	repo repository.ITokenRevocationRepository,
+	trDTO dto.ITokenRevocationDTO,
+	trService service.ITokenRevocationService,
+) ITokenRevocationUseCase {
+	return &TokenRevocationUseCase{
+		Log:        log,
+		Viper:      viper,
+		Repository: repo,
+		DTO:        trDTO,
+		Service:    trService,
+	}
+}
+
+func TokenRevocationUseCaseFactory(log *logrus.Logger, viper *viper.Viper) ITokenRevocationUseCase {
+	repo := repository.TokenRevocationRepositoryFactory(log)
+	trDTO := dto.TokenRevocationDTOFactory(log)
+	trService := service.TokenRevocationServiceFactory(viper, log)
+	return NewTokenRevocationUseCase(log, viper, repo, trDTO, trService)
+}
+
+func (uc *TokenRevocationUseCase) seconds(configKey string, fallback int) time.Duration {
+	value := uc.Viper.GetInt(configKey)
+	if value <= 0 {
+		value = fallback
+	}
+	return time.Duration(value) * time.Second
+}
+
+func (uc *TokenRevocationUseCase) RevokeToken(req *request.RevokeTokenRequest) (*response.TokenRevocationResponse, error) {
+	now := time.Now()
+	// tokens of a revoked user stay revoked for as long as any of them can live
+	expiresAt := now.Add(uc.seconds("jwt.max_token_lifetime", 86400))
+	if req.ExpiresAt != nil && req.TokenID != "" {
+		expiresAt = *req.ExpiresAt
+	}
+
+	ent := &entity.TokenRevocation{
+		Kind:      entity.TOKEN_REVOCATION_KIND_TOKEN,
+		TokenID:   req.TokenID,
+		RevokedAt: now,
+		ExpiresAt: expiresAt,
+		Reason:    req.Reason,
+	}
+	if req.UserID != "" {
+		parsedUserID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			uc.Log.Error("[TokenRevocationUseCase.RevokeToken] " + err.Error())
+			return nil, errors.New("[TokenRevocationUseCase.RevokeToken] " + err.Error())
+		}
+		ent.UserID = &parsedUserID
+	}
+	if req.TokenID == "" {
+		ent.Kind = entity.TOKEN_REVOCATION_KIND_USER
+	}
+
+	created, err := uc.Repository.CreateTokenRevocation(ent)
+	if err != nil {
+		uc.Log.Error("[TokenRevocationUseCase.RevokeToken] " + err.Error())
+		return nil, err
+	}
+
+	uc.Service.Add(created)
+
+	return uc.DTO.ConvertEntityToResponse(created), nil
+}
+
+func (uc *TokenRevocationUseCase) PurgeExpired() (int64, error) {
+	deleted, err := uc.Repository.DeleteExpired(time.Now())
+	if err != nil {
+		uc.Log.Error("[TokenRevocationUseCase.PurgeExpired] " + err.Error())
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -33,6 +33,7 @@ type UserProfileUseCase struct {
 	WorkExperienceRepository repository.IWorkExperienceRepository
 	EducationRepository      repository.IEducationRepository
 	SkillRepository          repository.ISkillRepository
+	PrivacyNoticeRepository  repository.IPrivacyNoticeRepository
 	Viper                    *viper.Viper
 }
 
@@ -43,6 +44,7 @@ func NewUserProfileUseCase(
 	weRepository repository.IWorkExperienceRepository,
 	edRepository repository.IEducationRepository,
 	sRepository repository.ISkillRepository,
+	pnRepository repository.IPrivacyNoticeRepository,
 	viper *viper.Viper,
 ) IUserProfileUseCase {
 	return &UserProfileUseCase{
@@ -52,6 +54,7 @@ func NewUserProfileUseCase(
 		WorkExperienceRepository: weRepository,
 		EducationRepository:      edRepository,
 		SkillRepository:          sRepository,
+		PrivacyNoticeRepository:  pnRepository,
 		Viper:                    viper,
 	}
 }
@@ -62,7 +65,8 @@ func UserProfileUseCaseFactory(log *logrus.Logger, viper *viper.Viper) IUserProf
 	weRepository := repository.WorkExperienceRepositoryFactory(log)
 	edRepository := repository.EducationRepositoryFactory(log)
 	sRepository := repository.SkillRepositoryFactory(log)
-	return NewUserProfileUseCase(log, repo, uDTO, weRepository, edRepository, sRepository, viper)
+	pnRepository := repository.PrivacyNoticeRepositoryFactory(log)
+	return NewUserProfileUseCase(log, repo, uDTO, weRepository, edRepository, sRepository, pnRepository, viper)
 }
 
 func (uc *UserProfileUseCase) FillUserProfile(req *request.FillUserProfileRequest, userID uuid.UUID) (*response.UserProfileResponse, error) {
@@ -84,6 +88,15 @@ func (uc *UserProfileUseCase) FillUserProfile(req *request.FillUserProfileReques
 			uc.Log.Errorf("[UserProfileUseCase.FillUserProfile] user profile already exist")
 			return nil, errors.New("[UserProfileUseCase.FillUserProfile] user profile already exist")
 		}
+		// a profile is only created with consent to the current privacy notice
+		notice, err := uc.PrivacyNoticeRepository.FindCurrent()
+		if err != nil {
+			uc.Log.Errorf("[UserProfileUseCase.FillUserProfile] error when finding privacy notice: %s", err.Error())
+			return nil, errors.New("[UserProfileUseCase.FillUserProfile] error when finding privacy notice: " + err.Error())
+		}
+		if notice != nil && notice.Version != req.PrivacyNoticeVersion {
+			return nil, ErrPrivacyConsentRequired
+		}
 		createdProfile, err := uc.Repository.CreateUserProfile(&entity.UserProfile{
 			UserID:          &userID,
 			Name:            req.Name,
@@ -94,6 +107,8 @@ func (uc *UserProfileUseCase) FillUserProfile(req *request.FillUserProfileReques
 			BirthDate:       parsedBirthDate,
 			BirthPlace:      req.BirthPlace,
 			Ktp:             req.KtpPath,
+			KtpNumber:       req.KtpNumber,
+			Email:           req.Email,
 			CurriculumVitae: req.CvPath,
 			Address:         req.Address,
 			Bilingual:       req.Bilingual,
@@ -106,6 +121,19 @@ func (uc *UserProfileUseCase) FillUserProfile(req *request.FillUserProfileReques
 			uc.Log.Errorf("[UserProfileUseCase.FillUserProfile] error when creating user profile: %s", err.Error())
 			return nil, errors.New("[UserProfileUseCase.FillUserProfile] error when creating user profile: " + err.Error())
 		}
+		if notice != nil {
+			if _, err := uc.PrivacyNoticeRepository.CreateConsent(&entity.PrivacyConsent{
+				UserProfileID:   createdProfile.ID,
+				PrivacyNoticeID: notice.ID,
+				NoticeVersion:   notice.Version,
+				ConsentedAt:     time.Now(),
+				IPAddress:       req.ConsentIPAddress,
+				UserAgent:       req.ConsentUserAgent,
+			}); err != nil {
+				uc.Log.Errorf("[UserProfileUseCase.FillUserProfile] error when recording privacy consent: %s", err.Error())
+				return nil, errors.New("[UserProfileUseCase.FillUserProfile] error when recording privacy consent: " + err.Error())
+			}
+		}
 		if len(req.WorkExperiences) > 0 {
 			for _, we := range req.WorkExperiences {
 				_, err := uc.WorkExperienceRepository.CreateWorkExperience(&entity.WorkExperience{
@@ -185,10 +213,11 @@ func (uc *UserProfileUseCase) FillUserProfile(req *request.FillUserProfileReques
 			return nil, errors.New("[UserProfileUseCase.FillUserProfile] user profile not found")
 		}
 
+		// the profile stays with its owner, staff filling it in do not take it over
 		updatedProfile, err := uc.Repository.UpdateUserProfile(&entity.UserProfile{
 			ID:              parsedID,
 			Name:            req.Name,
-			UserID:          &userID,
+			UserID:          exist.UserID,
 			MaritalStatus:   entity.MaritalStatusEnum(req.MaritalStatus),
 			Gender:          entity.UserGender(req.Gender),
 			PhoneNumber:     req.PhoneNumber,
@@ -196,6 +225,8 @@ func (uc *UserProfileUseCase) FillUserProfile(req *request.FillUserProfileReques
 			BirthDate:       parsedBirthDate,
 			BirthPlace:      req.BirthPlace,
 			Ktp:             req.KtpPath,
+			KtpNumber:       req.KtpNumber,
+			Email:           req.Email,
 			CurriculumVitae: req.CvPath,
 			Address:         req.Address,
 			Bilingual:       req.Bilingual,
@@ -470,6 +501,8 @@ func (uc *UserProfileUseCase) FillUserProfileMessage(req *request.FillUserProfil
 			BirthDate:       parsedBirthDate,
 			BirthPlace:      req.BirthPlace,
 			Ktp:             req.KtpPath,
+			KtpNumber:       req.KtpNumber,
+			Email:           req.Email,
 			CurriculumVitae: req.CvPath,
 			Address:         req.Address,
 			Bilingual:       req.Bilingual,
@@ -569,6 +602,8 @@ func (uc *UserProfileUseCase) FillUserProfileMessage(req *request.FillUserProfil
 			BirthDate:       parsedBirthDate,
 			BirthPlace:      req.BirthPlace,
 			Ktp:             req.KtpPath,
+			KtpNumber:       req.KtpNumber,
+			Email:           req.Email,
 			CurriculumVitae: req.CvPath,
 			Address:         req.Address,
 			Status:          entity.USER_INACTIVE,
@@ -11,7 +11,7 @@ import (
 )
 
 type IAdministrativeSelectionRepository interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.AdministrativeSelection, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *OrganizationScope) (*[]entity.AdministrativeSelection, int64, error)
 	FindAllPaginatedPic(projectPicIDs []uuid.UUID, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.AdministrativeSelection, int64, error)
 	CreateAdministrativeSelection(ent *entity.AdministrativeSelection) (*entity.AdministrativeSelection, error)
 	FindByID(id uuid.UUID) (*entity.AdministrativeSelection, error)
@@ -71,11 +71,11 @@ func (r *AdministrativeSelectionRepository) CreateAdministrativeSelection(ent *e
 	return ent, nil
 }
 
-func (r *AdministrativeSelectionRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.AdministrativeSelection, int64, error) {
+func (r *AdministrativeSelectionRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *OrganizationScope) (*[]entity.AdministrativeSelection, int64, error) {
 	var entities []entity.AdministrativeSelection
 	var total int64
 
-	query := r.DB.Preload("JobPosting.ProjectRecruitmentHeader").Preload("ProjectPIC")
+	query := r.DB.Preload("JobPosting.ProjectRecruitmentHeader").Preload("ProjectPIC").Scopes(orgScope.ByJobPosting("job_posting_id"))
 
 	if search != "" {
 		query = query.Where("name ILIKE ?", "%"+search+"%")
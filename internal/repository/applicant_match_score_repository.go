@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IApplicantMatchScoreRepository interface {
+	FindStaleApplicants(limit int) ([]entity.Applicant, error)
+	FindApplicantsByJobPostingID(jobPostingID uuid.UUID) ([]entity.Applicant, error)
+	SaveMatchScore(ent *entity.ApplicantMatchScore) (*entity.ApplicantMatchScore, error)
+}
+
+type ApplicantMatchScoreRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewApplicantMatchScoreRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *ApplicantMatchScoreRepository {
+	return &ApplicantMatchScoreRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func ApplicantMatchScoreRepositoryFactory(
+	log *logrus.Logger,
+) IApplicantMatchScoreRepository {
+	db := config.NewDatabase()
+	return NewApplicantMatchScoreRepository(log, db)
+}
+
+// FindStaleApplicants returns the applicants without a score or whose profile, educations,
+// skills, work experiences or job posting changed after they were scored
+func (r *ApplicantMatchScoreRepository) FindStaleApplicants(limit int) ([]entity.Applicant, error) {
+	var ids []uuid.UUID
+
+	if err := r.DB.Raw(`
+		SELECT a.id FROM applicants a
+		JOIN job_postings jp ON jp.id = a.job_posting_id AND jp.deleted_at IS NULL
+		JOIN user_profiles up ON up.id = a.user_profile_id AND up.deleted_at IS NULL
+		LEFT JOIN applicant_match_scores s ON s.applicant_id = a.id AND s.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL AND (
+			s.id IS NULL
+			OR up.updated_at > s.scored_at
+			OR jp.updated_at > s.scored_at
+			OR EXISTS (SELECT 1 FROM educations e WHERE e.user_profile_id = up.id AND COALESCE(e.deleted_at, e.updated_at) > s.scored_at)
+			OR EXISTS (SELECT 1 FROM skills sk WHERE sk.user_profile_id = up.id AND COALESCE(sk.deleted_at, sk.updated_at) > s.scored_at)
+			OR EXISTS (SELECT 1 FROM work_experiences w WHERE w.user_profile_id = up.id AND COALESCE(w.deleted_at, w.updated_at) > s.scored_at)
+		)
+		ORDER BY a.created_at
+		LIMIT ?
+	`, limit).Scan(&ids).Error; err != nil {
+		r.Log.Error("[ApplicantMatchScoreRepository.FindStaleApplicants] " + err.Error())
+		return nil, errors.New("[ApplicantMatchScoreRepository.FindStaleApplicants] " + err.Error())
+	}
+	if len(ids) == 0 {
+		return []entity.Applicant{}, nil
+	}
+
+	var applicants []entity.Applicant
+	if err := r.scorable().Where("id IN ?", ids).Find(&applicants).Error; err != nil {
+		r.Log.Error("[ApplicantMatchScoreRepository.FindStaleApplicants] " + err.Error())
+		return nil, errors.New("[ApplicantMatchScoreRepository.FindStaleApplicants] " + err.Error())
+	}
+
+	return applicants, nil
+}
+
+func (r *ApplicantMatchScoreRepository) FindApplicantsByJobPostingID(jobPostingID uuid.UUID) ([]entity.Applicant, error) {
+	var applicants []entity.Applicant
+
+	if err := r.scorable().Where("job_posting_id = ?", jobPostingID).Find(&applicants).Error; err != nil {
+		r.Log.Error("[ApplicantMatchScoreRepository.FindApplicantsByJobPostingID] " + err.Error())
+		return nil, errors.New("[ApplicantMatchScoreRepository.FindApplicantsByJobPostingID] " + err.Error())
+	}
+
+	return applicants, nil
+}
+
+// SaveMatchScore replaces the score of the applicant together with its items
+func (r *ApplicantMatchScoreRepository) SaveMatchScore(ent *entity.ApplicantMatchScore) (*entity.ApplicantMatchScore, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("applicant_match_score_id IN (?)", tx.Unscoped().Model(&entity.ApplicantMatchScore{}).Select("id").Where("applicant_id = ?", ent.ApplicantID)).Delete(&entity.ApplicantMatchScoreItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("applicant_id = ?", ent.ApplicantID).Delete(&entity.ApplicantMatchScore{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(ent).Error
+	})
+	if err != nil {
+		r.Log.Error("[ApplicantMatchScoreRepository.SaveMatchScore] " + err.Error())
+		return nil, errors.New("[ApplicantMatchScoreRepository.SaveMatchScore] " + err.Error())
+	}
+
+	return ent, nil
+}
+
+// scorable loads what the score is computed from
+func (r *ApplicantMatchScoreRepository) scorable() *gorm.DB {
+	return r.DB.
+		Preload("UserProfile.Educations").
+		Preload("UserProfile.Skills").
+		Preload("UserProfile.WorkExperiences").
+		Preload("JobPosting.MPRequest")
+}
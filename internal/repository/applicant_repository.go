@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
@@ -15,7 +16,8 @@ type IApplicantRepository interface {
 	UpdateApplicant(applicant *entity.Applicant) (*entity.Applicant, error)
 	FindByKeys(keys map[string]interface{}) (*entity.Applicant, error)
 	GetAllByKeys(keys map[string]interface{}) ([]entity.Applicant, error)
-	GetAllByKeysPaginated(keys map[string]interface{}, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) ([]entity.Applicant, int64, error)
+	GetAllByKeysScoped(keys map[string]interface{}, orgScope *OrganizationScope) ([]entity.Applicant, error)
+	GetAllByKeysPaginated(keys map[string]interface{}, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *OrganizationScope) ([]entity.Applicant, int64, error)
 	UpdateApplicantWhenRejected(applicant *entity.Applicant) (*entity.Applicant, error)
 	FindAllByIDs(ids []uuid.UUID) ([]entity.Applicant, error)
 }
@@ -50,6 +52,12 @@ func (r *ApplicantRepository) CreateApplicant(applicant *entity.Applicant) (*ent
 		return nil, err
 	}
 
+	if err := tx.Create(stageHistory(applicant)).Error; err != nil {
+		tx.Rollback()
+		r.Log.Error("[ApplicantRepository.CreateApplicant] " + err.Error())
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
 		r.Log.Error("[ApplicantRepository.CreateApplicant] " + err.Error())
@@ -78,8 +86,12 @@ func (r *ApplicantRepository) FindByKeys(keys map[string]interface{}) (*entity.A
 }
 
 func (r *ApplicantRepository) GetAllByKeys(keys map[string]interface{}) ([]entity.Applicant, error) {
+	return r.GetAllByKeysScoped(keys, nil)
+}
+
+func (r *ApplicantRepository) GetAllByKeysScoped(keys map[string]interface{}, orgScope *OrganizationScope) ([]entity.Applicant, error) {
 	var applicants []entity.Applicant
-	if err := r.DB.Where(keys).Preload("UserProfile.WorkExperiences").Preload("UserProfile.Skills").Preload("UserProfile.Educations").Preload("JobPosting").Preload("TemplateQuestion").Find(&applicants).Error; err != nil {
+	if err := r.DB.Scopes(orgScope.ByJobPosting("applicants.job_posting_id")).Where(keys).Preload("UserProfile.WorkExperiences").Preload("UserProfile.Skills").Preload("UserProfile.Educations").Preload("JobPosting").Preload("TemplateQuestion").Preload("Source").Find(&applicants).Error; err != nil {
 		return nil, err
 	}
 
@@ -87,6 +99,8 @@ func (r *ApplicantRepository) GetAllByKeys(keys map[string]interface{}) ([]entit
 }
 
 func (r *ApplicantRepository) UpdateApplicant(applicant *entity.Applicant) (*entity.Applicant, error) {
+	before := r.findForStageHistory(applicant.ID)
+
 	if err := r.DB.Model(&entity.Applicant{}).Where("id = ?", applicant.ID).Updates(applicant).Error; err != nil {
 		// tx.Rollback()
 		r.Log.Error("[ApplicantRepository.UpdateApplicant] " + err.Error())
@@ -98,12 +112,15 @@ func (r *ApplicantRepository) UpdateApplicant(applicant *entity.Applicant) (*ent
 		return nil, err
 	}
 
+	r.recordStageHistory(before, applicant)
+
 	return applicant, nil
 }
 
 func (r *ApplicantRepository) UpdateApplicantWhenRejected(applicant *entity.Applicant) (*entity.Applicant, error) {
 	// Use the Select option to explicitly specify the fields to be updated
 	r.Log.Infof("applicant: %+v", applicant.ID)
+	before := r.findForStageHistory(applicant.ID)
 	if err := r.DB.Model(&entity.Applicant{}).Where("id = ?", applicant.ID).Select("order", "template_question_id", "status", "process_status").Updates(map[string]interface{}{
 		"template_question_id": nil,
 		"status":               "REJECTED",
@@ -120,19 +137,34 @@ func (r *ApplicantRepository) UpdateApplicantWhenRejected(applicant *entity.Appl
 		return nil, err
 	}
 
+	r.recordStageHistory(before, applicant)
+
 	return applicant, nil
 }
 
-func (r *ApplicantRepository) GetAllByKeysPaginated(keys map[string]interface{}, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) ([]entity.Applicant, int64, error) {
+func (r *ApplicantRepository) GetAllByKeysPaginated(keys map[string]interface{}, page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *OrganizationScope) ([]entity.Applicant, int64, error) {
 	var applicants []entity.Applicant
 	var total int64
 
-	db := r.DB.Where(keys).Preload("UserProfile.WorkExperiences").Preload("UserProfile.Skills").Preload("UserProfile.Educations").Preload("JobPosting").Preload("TemplateQuestion")
+	db := r.DB.Scopes(orgScope.ByJobPosting("applicants.job_posting_id")).Where(keys).Preload("UserProfile.WorkExperiences").Preload("UserProfile.Skills").Preload("UserProfile.Educations").Preload("JobPosting").Preload("TemplateQuestion").Preload("MatchScore.Items").Preload("Source")
 	if search != "" {
 		db = db.Where("document_number ILIKE ?", "%"+search+"%")
 	}
 
+	// the scores are joined as a subquery, so its columns do not clash with the applicant columns
+	if sort["match_score"] != nil || filter["match_score_min"] != nil {
+		db = db.Joins("LEFT JOIN (SELECT applicant_id, score FROM applicant_match_scores WHERE deleted_at IS NULL) match_scores ON match_scores.applicant_id = applicants.id")
+	}
+	if filter["match_score_min"] != nil {
+		db = db.Where("match_scores.score >= ?", filter["match_score_min"])
+	}
+
 	for key, value := range sort {
+		if key == "match_score" {
+			// applicants that are not scored yet come last in both directions
+			db = db.Order("match_scores.score " + value.(string) + " NULLS LAST")
+			continue
+		}
 		db = db.Order(key + " " + value.(string))
 	}
 
@@ -181,3 +213,48 @@ func (r *ApplicantRepository) FindAllByIDs(ids []uuid.UUID) ([]entity.Applicant,
 
 	return applicants, nil
 }
+
+func (r *ApplicantRepository) findForStageHistory(id uuid.UUID) *entity.Applicant {
+	var applicant entity.Applicant
+	if err := r.DB.First(&applicant, "id = ?", id).Error; err != nil {
+		r.Log.Warn("[ApplicantRepository.findForStageHistory] " + err.Error())
+		return nil
+	}
+
+	return &applicant
+}
+
+// recordStageHistory adds a stage history entry when an update moved the applicant to another
+// stage or status. It only logs failures, the history must not block the selection itself.
+func (r *ApplicantRepository) recordStageHistory(before *entity.Applicant, after *entity.Applicant) {
+	if before == nil || (before.Order == after.Order && before.Status == after.Status) {
+		return
+	}
+
+	history := stageHistory(after)
+	if after.Status == entity.APPLICANT_STATUS_REJECTED && after.Order == 0 {
+		// rejections reset the order, keep the stage the applicant was rejected at
+		history.Order = before.Order
+		history.TemplateQuestionID = stageHistory(before).TemplateQuestionID
+	}
+
+	if err := r.DB.Create(history).Error; err != nil {
+		r.Log.Error("[ApplicantRepository.recordStageHistory] " + err.Error())
+	}
+}
+
+func stageHistory(applicant *entity.Applicant) *entity.ApplicantStageHistory {
+	history := &entity.ApplicantStageHistory{
+		ApplicantID:  applicant.ID,
+		JobPostingID: applicant.JobPostingID,
+		Order:        applicant.Order,
+		Status:       applicant.Status,
+		EnteredAt:    time.Now(),
+	}
+	if applicant.TemplateQuestionID != uuid.Nil {
+		templateQuestionID := applicant.TemplateQuestionID
+		history.TemplateQuestionID = &templateQuestionID
+	}
+
+	return history
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApplicantSourceCount counts the applicants of a job posting coming from one source
+type ApplicantSourceCount struct {
+	JobPostingID   uuid.UUID
+	JobPostingName string
+	Channel        string
+	UtmSource      string
+	Applications   int
+	Conversions    int
+	Hires          int
+}
+
+type IApplicantSourceRepository interface {
+	SaveApplicantSource(ent *entity.ApplicantSource) (*entity.ApplicantSource, error)
+	FindByApplicantID(applicantID uuid.UUID) (*entity.ApplicantSource, error)
+	CountBySource(filter *DashboardFilter, jobPostingID *uuid.UUID, orgScope *OrganizationScope) ([]ApplicantSourceCount, error)
+}
+
+type ApplicantSourceRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewApplicantSourceRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *ApplicantSourceRepository {
+	return &ApplicantSourceRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func ApplicantSourceRepositoryFactory(
+	log *logrus.Logger,
+) IApplicantSourceRepository {
+	db := config.NewDatabase()
+	return NewApplicantSourceRepository(log, db)
+}
+
+// SaveApplicantSource creates the source of an applicant or replaces the one it has
+func (r *ApplicantSourceRepository) SaveApplicantSource(ent *entity.ApplicantSource) (*entity.ApplicantSource, error) {
+	if err := r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "applicant_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"channel":       ent.Channel,
+			"utm_source":    ent.UtmSource,
+			"utm_medium":    ent.UtmMedium,
+			"utm_campaign":  ent.UtmCampaign,
+			"referral_code": ent.ReferralCode,
+			"note":          ent.Note,
+			"set_by":        ent.SetBy,
+			"deleted_at":    nil,
+			"updated_at":    time.Now(),
+		}),
+	}).Create(ent).Error; err != nil {
+		r.Log.Error("[ApplicantSourceRepository.SaveApplicantSource] " + err.Error())
+		return nil, errors.New("[ApplicantSourceRepository.SaveApplicantSource] " + err.Error())
+	}
+
+	return r.FindByApplicantID(ent.ApplicantID)
+}
+
+func (r *ApplicantSourceRepository) FindByApplicantID(applicantID uuid.UUID) (*entity.ApplicantSource, error) {
+	var source entity.ApplicantSource
+	if err := r.DB.Where("applicant_id = ?", applicantID).First(&source).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[ApplicantSourceRepository.FindByApplicantID] " + err.Error())
+		return nil, errors.New("[ApplicantSourceRepository.FindByApplicantID] " + err.Error())
+	}
+
+	return &source, nil
+}
+
+// CountBySource counts the applications, conversions and hires per job posting and source.
+// An applicant converted once it got past the first stage, which the stage history remembers
+// for applicants rejected later on.
+func (r *ApplicantSourceRepository) CountBySource(filter *DashboardFilter, jobPostingID *uuid.UUID, orgScope *OrganizationScope) ([]ApplicantSourceCount, error) {
+	var counts []ApplicantSourceCount
+
+	db := r.DB.Model(&entity.Applicant{}).
+		Joins("JOIN job_postings ON job_postings.id = applicants.job_posting_id").
+		Joins("LEFT JOIN applicant_sources ON applicant_sources.applicant_id = applicants.id AND applicant_sources.deleted_at IS NULL").
+		Select(`applicants.job_posting_id AS job_posting_id, job_postings.name AS job_posting_name,
+			COALESCE(applicant_sources.channel, 'UNKNOWN') AS channel, COALESCE(applicant_sources.utm_source, '') AS utm_source,
+			COUNT(*) AS applications,
+			COUNT(*) FILTER (WHERE applicants.status = ? OR applicants."order" > 1 OR EXISTS (
+				SELECT 1 FROM applicant_stage_histories h WHERE h.applicant_id = applicants.id AND h."order" > 1 AND h.deleted_at IS NULL)) AS conversions,
+			COUNT(*) FILTER (WHERE applicants.status = ?) AS hires`, entity.APPLICANT_STATUS_HIRED, entity.APPLICANT_STATUS_HIRED).
+		Scopes(
+			orgScope.ByJobPosting("applicants.job_posting_id"),
+			filter.ByJobPosting("applicants.job_posting_id"),
+			filter.ByPeriod("applicants.applied_date"),
+		)
+	if jobPostingID != nil {
+		db = db.Where("applicants.job_posting_id = ?", *jobPostingID)
+	}
+
+	if err := db.Group("applicants.job_posting_id, job_postings.name, COALESCE(applicant_sources.channel, 'UNKNOWN'), COALESCE(applicant_sources.utm_source, '')").
+		Order("job_postings.name, applications DESC").
+		Scan(&counts).Error; err != nil {
+		r.Log.Error("[ApplicantSourceRepository.CountBySource] " + err.Error())
+		return nil, errors.New("[ApplicantSourceRepository.CountBySource] " + err.Error())
+	}
+
+	return counts, nil
+}
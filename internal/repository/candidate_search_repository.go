@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// profiles mix Indonesian and English, the simple configuration only lower-cases the words
+// so "D3", "SAP" and "Medan" are matched as written in any language
+const CANDIDATE_SEARCH_TEXT_CONFIG = "simple"
+
+// the expected salary filter works on bands of this width, the exact salaries stay encrypted
+const CANDIDATE_SEARCH_SALARY_BAND = 1000000
+
+const (
+	// the vector and the content can be large, the results only need the rank and the headline
+	candidateSearchColumns         = "candidate_search_documents.user_profile_id, candidate_search_documents.indexed_at"
+	candidateSearchTsQuery         = "websearch_to_tsquery('" + CANDIDATE_SEARCH_TEXT_CONFIG + "', ?)"
+	candidateSearchHeadlineOptions = "StartSel=\"" + entity.CANDIDATE_SEARCH_HIGHLIGHT_START + "\", StopSel=\"" + entity.CANDIDATE_SEARCH_HIGHLIGHT_STOP +
+		"\", FragmentDelimiter=\"" + entity.CANDIDATE_SEARCH_FRAGMENT_DELIMITER + "\", MaxFragments=3, MaxWords=20, MinWords=8"
+)
+
+type CandidateSearchFacetCount struct {
+	Value string `json:"value"`
+	Total int64  `json:"total"`
+}
+
+type ICandidateSearchRepository interface {
+	FindStaleUserProfileIDs(limit int) ([]uuid.UUID, error)
+	RefreshDocuments(userProfileIDs []uuid.UUID) (int64, error)
+	DeleteOrphanDocuments() (int64, error)
+	SaveCvText(userProfileID uuid.UUID, cvText string) error
+	Search(page, pageSize int, search string, filter map[string]interface{}) (*[]entity.CandidateSearchDocument, int64, error)
+	FindFacetCounts(search string, filter map[string]interface{}) (map[string][]CandidateSearchFacetCount, error)
+}
+
+type CandidateSearchRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewCandidateSearchRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *CandidateSearchRepository {
+	return &CandidateSearchRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func CandidateSearchRepositoryFactory(
+	log *logrus.Logger,
+) ICandidateSearchRepository {
+	db := config.NewDatabase()
+	return NewCandidateSearchRepository(log, db)
+}
+
+// FindStaleUserProfileIDs returns the profiles without a search document or with a profile,
+// education, skill or work experience changed or deleted after the document was built
+func (r *CandidateSearchRepository) FindStaleUserProfileIDs(limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	if err := r.DB.Raw(`
+		SELECT up.id FROM user_profiles up
+		LEFT JOIN candidate_search_documents d ON d.user_profile_id = up.id
+		WHERE up.deleted_at IS NULL AND up.anonymised_at IS NULL AND (
+			d.user_profile_id IS NULL
+			OR up.updated_at > d.indexed_at
+			OR (up.expected_salary IS NOT NULL AND d.expected_salary_band IS NULL)
+			OR EXISTS (SELECT 1 FROM educations e WHERE e.user_profile_id = up.id AND COALESCE(e.deleted_at, e.updated_at) > d.indexed_at)
+			OR EXISTS (SELECT 1 FROM skills s WHERE s.user_profile_id = up.id AND COALESCE(s.deleted_at, s.updated_at) > d.indexed_at)
+			OR EXISTS (SELECT 1 FROM work_experiences w WHERE w.user_profile_id = up.id AND COALESCE(w.deleted_at, w.updated_at) > d.indexed_at)
+		)
+		ORDER BY up.updated_at
+		LIMIT ?
+	`, limit).Scan(&ids).Error; err != nil {
+		r.Log.Error("[CandidateSearchRepository.FindStaleUserProfileIDs] " + err.Error())
+		return nil, errors.New("[CandidateSearchRepository.FindStaleUserProfileIDs] " + err.Error())
+	}
+
+	return ids, nil
+}
+
+// RefreshDocuments rebuilds the search documents of the given profiles in one statement.
+// Names and educations weigh the most, then skills and work experiences, then the location
+// and last the CV text, which is kept as it is.
+func (r *CandidateSearchRepository) RefreshDocuments(userProfileIDs []uuid.UUID) (int64, error) {
+	if len(userProfileIDs) == 0 {
+		return 0, nil
+	}
+
+	// taken before the statement, changes made while it runs are picked up on the next refresh
+	now := time.Now()
+	var refreshed int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(`
+			INSERT INTO candidate_search_documents (user_profile_id, content, search_vector, indexed_at, created_at, updated_at)
+			SELECT up.id,
+				concat_ws(E'\n', up.name, NULLIF(concat_ws(', ', up.address, up.birth_place), ''), edu.text, sk.text, we.text, d.cv_text),
+				setweight(to_tsvector('`+CANDIDATE_SEARCH_TEXT_CONFIG+`', COALESCE(up.name, '')), 'A') ||
+				setweight(to_tsvector('`+CANDIDATE_SEARCH_TEXT_CONFIG+`', COALESCE(edu.text, '')), 'A') ||
+				setweight(to_tsvector('`+CANDIDATE_SEARCH_TEXT_CONFIG+`', concat_ws(' ', sk.text, we.text)), 'B') ||
+				setweight(to_tsvector('`+CANDIDATE_SEARCH_TEXT_CONFIG+`', concat_ws(' ', up.address, up.birth_place)), 'C') ||
+				setweight(to_tsvector('`+CANDIDATE_SEARCH_TEXT_CONFIG+`', COALESCE(d.cv_text, '')), 'D'),
+				?, ?, ?
+			FROM user_profiles up
+			LEFT JOIN candidate_search_documents d ON d.user_profile_id = up.id
+			LEFT JOIN LATERAL (
+				SELECT string_agg(concat_ws(' ', e.education_level, e.major, e.school_name, e.graduate_year, 'IPK ' || e.gpa), '; ') AS text
+				FROM educations e WHERE e.user_profile_id = up.id AND e.deleted_at IS NULL
+			) edu ON true
+			LEFT JOIN LATERAL (
+				SELECT string_agg(concat_ws(' ', s.name, s.description), '; ') AS text
+				FROM skills s WHERE s.user_profile_id = up.id AND s.deleted_at IS NULL
+			) sk ON true
+			LEFT JOIN LATERAL (
+				SELECT string_agg(concat_ws(' ', w.name, w.company_name, w.job_description), '; ') AS text
+				FROM work_experiences w WHERE w.user_profile_id = up.id AND w.deleted_at IS NULL
+			) we ON true
+			WHERE up.id IN ? AND up.deleted_at IS NULL AND up.anonymised_at IS NULL
+			ON CONFLICT (user_profile_id) DO UPDATE
+			SET content = EXCLUDED.content, search_vector = EXCLUDED.search_vector, indexed_at = EXCLUDED.indexed_at, updated_at = EXCLUDED.updated_at
+		`, now, now, now, userProfileIDs)
+		if res.Error != nil {
+			return res.Error
+		}
+		refreshed = res.RowsAffected
+
+		return r.refreshSalaryBands(tx, userProfileIDs)
+	})
+	if err != nil {
+		r.Log.Error("[CandidateSearchRepository.RefreshDocuments] " + err.Error())
+		return 0, errors.New("[CandidateSearchRepository.RefreshDocuments] " + err.Error())
+	}
+
+	return refreshed, nil
+}
+
+// refreshSalaryBands decrypts the expected salaries of the refreshed profiles only and stores
+// the band they fall in, one statement per band
+func (r *CandidateSearchRepository) refreshSalaryBands(tx *gorm.DB, userProfileIDs []uuid.UUID) error {
+	var profiles []entity.UserProfile
+	if err := tx.Select("id", "expected_salary").Where("id IN ? AND expected_salary IS NOT NULL", userProfileIDs).Find(&profiles).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.CandidateSearchDocument{}).Where("user_profile_id IN ?", userProfileIDs).Update("expected_salary_band", nil).Error; err != nil {
+		return err
+	}
+	byBand := map[int][]uuid.UUID{}
+	for _, profile := range profiles {
+		band := profile.ExpectedSalary / CANDIDATE_SEARCH_SALARY_BAND * CANDIDATE_SEARCH_SALARY_BAND
+		byBand[band] = append(byBand[band], profile.ID)
+	}
+	for band, ids := range byBand {
+		if err := tx.Model(&entity.CandidateSearchDocument{}).Where("user_profile_id IN ?", ids).Update("expected_salary_band", band).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *CandidateSearchRepository) DeleteOrphanDocuments() (int64, error) {
+	res := r.DB.Exec(`
+		DELETE FROM candidate_search_documents d
+		WHERE NOT EXISTS (SELECT 1 FROM user_profiles up WHERE up.id = d.user_profile_id AND up.deleted_at IS NULL AND up.anonymised_at IS NULL)
+	`)
+	if res.Error != nil {
+		r.Log.Error("[CandidateSearchRepository.DeleteOrphanDocuments] " + res.Error.Error())
+		return 0, errors.New("[CandidateSearchRepository.DeleteOrphanDocuments] " + res.Error.Error())
+	}
+
+	return res.RowsAffected, nil
+}
+
+// SaveCvText stores the text read from the CV of a profile and marks its document stale, the
+// next refresh puts it in the search vector
+func (r *CandidateSearchRepository) SaveCvText(userProfileID uuid.UUID, cvText string) error {
+	now := time.Now()
+	if err := r.DB.Exec(`
+		INSERT INTO candidate_search_documents (user_profile_id, content, cv_text, indexed_at, created_at, updated_at)
+		VALUES (?, '', ?, ?, ?, ?)
+		ON CONFLICT (user_profile_id) DO UPDATE
+		SET cv_text = EXCLUDED.cv_text, indexed_at = EXCLUDED.indexed_at, updated_at = EXCLUDED.updated_at
+	`, userProfileID, cvText, time.Unix(0, 0), now, now).Error; err != nil {
+		r.Log.Error("[CandidateSearchRepository.SaveCvText] " + err.Error())
+		return errors.New("[CandidateSearchRepository.SaveCvText] " + err.Error())
+	}
+
+	return nil
+}
+
+// Search ranks the documents matching the web search style query and returns the headline
+// of the matching parts, without a query every profile matching the filter is returned
+func (r *CandidateSearchRepository) Search(page, pageSize int, search string, filter map[string]interface{}) (*[]entity.CandidateSearchDocument, int64, error) {
+	var res []entity.CandidateSearchDocument
+	var total int64
+
+	query := r.filteredQuery(search, filter)
+
+	if err := query.Count(&total).Error; err != nil {
+		r.Log.Error("[CandidateSearchRepository.Search] " + err.Error())
+		return nil, 0, errors.New("[CandidateSearchRepository.Search] " + err.Error())
+	}
+
+	if search != "" {
+		query = query.Select(
+			candidateSearchColumns+", ts_rank_cd(search_vector, "+candidateSearchTsQuery+") AS rank, ts_headline('"+CANDIDATE_SEARCH_TEXT_CONFIG+"', content, "+candidateSearchTsQuery+", ?) AS highlight",
+			search, search, candidateSearchHeadlineOptions,
+		).Order("rank DESC")
+	} else {
+		query = query.Select(candidateSearchColumns)
+	}
+
+	if err := query.
+		Order("user_profiles.updated_at DESC").
+		Preload("UserProfile.Educations").
+		Preload("UserProfile.Skills").
+		Preload("UserProfile.WorkExperiences").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&res).Error; err != nil {
+		r.Log.Error("[CandidateSearchRepository.Search] " + err.Error())
+		return nil, 0, errors.New("[CandidateSearchRepository.Search] " + err.Error())
+	}
+
+	return &res, total, nil
+}
+
+// FindFacetCounts counts the matching candidates per education level and per gender
+func (r *CandidateSearchRepository) FindFacetCounts(search string, filter map[string]interface{}) (map[string][]CandidateSearchFacetCount, error) {
+	res := map[string][]CandidateSearchFacetCount{}
+
+	facets := []struct {
+		name   string
+		column string
+		join   string
+	}{
+		{"education_level", "fe.education_level", "JOIN educations fe ON fe.user_profile_id = user_profiles.id AND fe.deleted_at IS NULL"},
+		{"gender", "user_profiles.gender", ""},
+	}
+	for _, facet := range facets {
+		var counts []CandidateSearchFacetCount
+		query := r.filteredQuery(search, filter)
+		if facet.join != "" {
+			query = query.Joins(facet.join)
+		}
+		if err := query.
+			Select(facet.column + " AS value, COUNT(DISTINCT candidate_search_documents.user_profile_id) AS total").
+			Where(facet.column + " IS NOT NULL AND " + facet.column + " <> ''").
+			Group(facet.column).
+			Order("total DESC").
+			Scan(&counts).Error; err != nil {
+			r.Log.Error("[CandidateSearchRepository.FindFacetCounts] " + err.Error())
+			return nil, errors.New("[CandidateSearchRepository.FindFacetCounts] " + err.Error())
+		}
+		res[facet.name] = counts
+	}
+
+	return res, nil
+}
+
+func (r *CandidateSearchRepository) filteredQuery(search string, filter map[string]interface{}) *gorm.DB {
+	query := r.DB.Model(&entity.CandidateSearchDocument{}).Joins("JOIN user_profiles ON user_profiles.id = candidate_search_documents.user_profile_id AND user_profiles.deleted_at IS NULL")
+
+	if search != "" {
+		query = query.Where("search_vector @@ "+candidateSearchTsQuery, search)
+	}
+
+	// the education filters have to hold for the same education
+	educationFilter := ""
+	var educationArgs []interface{}
+	if levels, ok := filter["education_levels"].([]string); ok && len(levels) > 0 {
+		educationFilter += " AND e.education_level IN ?"
+		educationArgs = append(educationArgs, levels)
+	}
+	if year, ok := filter["graduate_year_min"].(int); ok && year > 0 {
+		educationFilter += " AND e.graduate_year >= ?"
+		educationArgs = append(educationArgs, year)
+	}
+	if year, ok := filter["graduate_year_max"].(int); ok && year > 0 {
+		educationFilter += " AND e.graduate_year <= ?"
+		educationArgs = append(educationArgs, year)
+	}
+	if educationFilter != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM educations e WHERE e.user_profile_id = user_profiles.id AND e.deleted_at IS NULL"+educationFilter+")", educationArgs...)
+	}
+
+	if gender, ok := filter["gender"].(string); ok && gender != "" {
+		query = query.Where("user_profiles.gender = ?", gender)
+	}
+	if age, ok := filter["age_min"].(int); ok && age > 0 {
+		query = query.Where("user_profiles.age >= ?", age)
+	}
+	if age, ok := filter["age_max"].(int); ok && age > 0 {
+		query = query.Where("user_profiles.age <= ?", age)
+	}
+	// a band matches when it overlaps the requested range
+	if salary, ok := filter["expected_salary_min"].(int); ok && salary > 0 {
+		query = query.Where("candidate_search_documents.expected_salary_band > ?", salary-CANDIDATE_SEARCH_SALARY_BAND)
+	}
+	if salary, ok := filter["expected_salary_max"].(int); ok && salary > 0 {
+		query = query.Where("candidate_search_documents.expected_salary_band <= ?", salary)
+	}
+	if location, ok := filter["location"].(string); ok && location != "" {
+		query = query.Where("(user_profiles.address ILIKE ? OR user_profiles.birth_place ILIKE ?)", "%"+location+"%", "%"+location+"%")
+	}
+
+	return query
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ICurriculumVitaeDraftRepository interface {
+	CreateDraft(ent *entity.CurriculumVitaeDraft) (*entity.CurriculumVitaeDraft, error)
+	FindByID(id uuid.UUID) (*entity.CurriculumVitaeDraft, error)
+	FindLatestByUserID(userID uuid.UUID) (*entity.CurriculumVitaeDraft, error)
+	UpdateDraft(ent *entity.CurriculumVitaeDraft) (*entity.CurriculumVitaeDraft, error)
+}
+
+type CurriculumVitaeDraftRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewCurriculumVitaeDraftRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *CurriculumVitaeDraftRepository {
+	return &CurriculumVitaeDraftRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func CurriculumVitaeDraftRepositoryFactory(
+	log *logrus.Logger,
+) ICurriculumVitaeDraftRepository {
+	db := config.NewDatabase()
+	return NewCurriculumVitaeDraftRepository(log, db)
+}
+
+func (r *CurriculumVitaeDraftRepository) CreateDraft(ent *entity.CurriculumVitaeDraft) (*entity.CurriculumVitaeDraft, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[CurriculumVitaeDraftRepository.CreateDraft] " + err.Error())
+		return nil, errors.New("[CurriculumVitaeDraftRepository.CreateDraft] " + err.Error())
+	}
+
+	return r.FindByID(ent.ID)
+}
+
+func (r *CurriculumVitaeDraftRepository) FindByID(id uuid.UUID) (*entity.CurriculumVitaeDraft, error) {
+	var draft entity.CurriculumVitaeDraft
+
+	if err := r.withEntries().Where("id = ?", id).First(&draft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[CurriculumVitaeDraftRepository.FindByID] " + err.Error())
+		return nil, errors.New("[CurriculumVitaeDraftRepository.FindByID] " + err.Error())
+	}
+
+	return &draft, nil
+}
+
+func (r *CurriculumVitaeDraftRepository) FindLatestByUserID(userID uuid.UUID) (*entity.CurriculumVitaeDraft, error) {
+	var draft entity.CurriculumVitaeDraft
+
+	if err := r.withEntries().Where("user_id = ?", userID).Order("created_at DESC").First(&draft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[CurriculumVitaeDraftRepository.FindLatestByUserID] " + err.Error())
+		return nil, errors.New("[CurriculumVitaeDraftRepository.FindLatestByUserID] " + err.Error())
+	}
+
+	return &draft, nil
+}
+
+// UpdateDraft saves the draft and replaces its entries with the ones it holds, so the edits
+// the candidate made during the review are kept with it
+func (r *CurriculumVitaeDraftRepository) UpdateDraft(ent *entity.CurriculumVitaeDraft) (*entity.CurriculumVitaeDraft, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Educations", "WorkExperiences", "Skills").Save(ent).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("curriculum_vitae_draft_id = ?", ent.ID).Delete(&entity.CurriculumVitaeDraftEducation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("curriculum_vitae_draft_id = ?", ent.ID).Delete(&entity.CurriculumVitaeDraftWorkExperience{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("curriculum_vitae_draft_id = ?", ent.ID).Delete(&entity.CurriculumVitaeDraftSkill{}).Error; err != nil {
+			return err
+		}
+		for i := range ent.Educations {
+			ent.Educations[i].CurriculumVitaeDraftID = ent.ID
+			if err := tx.Create(&ent.Educations[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range ent.WorkExperiences {
+			ent.WorkExperiences[i].CurriculumVitaeDraftID = ent.ID
+			if err := tx.Create(&ent.WorkExperiences[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range ent.Skills {
+			ent.Skills[i].CurriculumVitaeDraftID = ent.ID
+			if err := tx.Create(&ent.Skills[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.Log.Error("[CurriculumVitaeDraftRepository.UpdateDraft] " + err.Error())
+		return nil, errors.New("[CurriculumVitaeDraftRepository.UpdateDraft] " + err.Error())
+	}
+
+	return r.FindByID(ent.ID)
+}
+
+func (r *CurriculumVitaeDraftRepository) withEntries() *gorm.DB {
+	return r.DB.
+		Preload("Educations", func(db *gorm.DB) *gorm.DB { return db.Order("graduate_year DESC") }).
+		Preload("WorkExperiences", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Preload("Skills", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") })
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DashboardFilter narrows every dashboard metric to a period and to the job postings matching
+// the organization, location, recruitment type and project. Empty fields are not filtered on.
+type DashboardFilter struct {
+	StartDate                  *time.Time
+	EndDate                    *time.Time
+	ForOrganizationID          *uuid.UUID
+	OrganizationLocationID     *uuid.UUID
+	RecruitmentType            entity.ProjectRecruitmentType
+	ProjectRecruitmentHeaderID *uuid.UUID
+}
+
+// HasPeriod reports whether the filter is limited to a date range
+func (f *DashboardFilter) HasPeriod() bool {
+	return f != nil && f.StartDate != nil && f.EndDate != nil
+}
+
+// Previous returns the same filter for the period of equal length right before this one
+func (f *DashboardFilter) Previous() *DashboardFilter {
+	if !f.HasPeriod() {
+		return nil
+	}
+	days := int(f.EndDate.Sub(*f.StartDate).Hours()/24) + 1
+	endDate := f.StartDate.AddDate(0, 0, -1)
+	startDate := endDate.AddDate(0, 0, -(days - 1))
+
+	previous := *f
+	previous.StartDate = &startDate
+	previous.EndDate = &endDate
+	return &previous
+}
+
+// ByPeriod filters on a date or timestamp column, the end date is included as a whole day
+func (f *DashboardFilter) ByPeriod(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !f.HasPeriod() {
+			return db
+		}
+		return db.Where(column+" >= ? AND "+column+" < ?", f.StartDate.Format("2006-01-02"), f.EndDate.AddDate(0, 0, 1).Format("2006-01-02"))
+	}
+}
+
+// ByJobPosting filters on a column referencing job_postings.id, e.g. "applicants.job_posting_id"
+func (f *DashboardFilter) ByJobPosting(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !f.filtersJobPostings() {
+			return db
+		}
+		return db.Where(column+" IN (?)", f.jobPostings(db, "id"))
+	}
+}
+
+// ByMPRequest filters on a column referencing mp_requests.id through the job posting made for it
+func (f *DashboardFilter) ByMPRequest(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !f.filtersJobPostings() {
+			return db
+		}
+		return db.Where(column+" IN (?)", f.jobPostings(db, "mp_request_id"))
+	}
+}
+
+func (f *DashboardFilter) filtersJobPostings() bool {
+	return f != nil && (f.ForOrganizationID != nil || f.OrganizationLocationID != nil || f.RecruitmentType != "" || f.ProjectRecruitmentHeaderID != nil)
+}
+
+func (f *DashboardFilter) jobPostings(db *gorm.DB, column string) *gorm.DB {
+	query := db.Session(&gorm.Session{NewDB: true}).Model(&entity.JobPosting{}).Select(column)
+	if f.ForOrganizationID != nil {
+		query = query.Where("for_organization_id = ?", *f.ForOrganizationID)
+	}
+	if f.OrganizationLocationID != nil {
+		query = query.Where("for_organization_location_id = ?", *f.OrganizationLocationID)
+	}
+	if f.RecruitmentType != "" {
+		query = query.Where("recruitment_type = ?", f.RecruitmentType)
+	}
+	if f.ProjectRecruitmentHeaderID != nil {
+		query = query.Where("project_recruitment_header_id = ?", *f.ProjectRecruitmentHeaderID)
+	}
+	return query
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type DashboardApplicantCount struct {
+	Total          int
+	Hired          int
+	HiredBilingual int
+}
+
+// DashboardDaysToHire counts hires by the days between the manpower request and the join date
+type DashboardDaysToHire struct {
+	Over30        int     `gorm:"column:over_30"`
+	From21To30    int     `gorm:"column:from_21_to_30"`
+	From11To20    int     `gorm:"column:from_11_to_20"`
+	UpTo10        int     `gorm:"column:up_to_10"`
+	AverageDays   float64 `gorm:"column:average_days"`
+	TotalEmployed int     `gorm:"column:total_employed"`
+}
+
+type DashboardJobLevelCount struct {
+	JobLevelID uuid.UUID
+	Total      int
+}
+
+type DashboardMPRequestCount struct {
+	MPRCloneID uuid.UUID
+	Total      int
+}
+
+type IDashboardRepository interface {
+	FindMPRequests(filter *DashboardFilter, orgScope *OrganizationScope) ([]entity.MPRequest, error)
+	CountApplicants(filter *DashboardFilter, orgScope *OrganizationScope) (*DashboardApplicantCount, error)
+	CountDaysToHire(filter *DashboardFilter, orgScope *OrganizationScope) (*DashboardDaysToHire, error)
+	CountDocumentSendingsByJobLevel(filter *DashboardFilter, orgScope *OrganizationScope) ([]DashboardJobLevelCount, error)
+	CountHiredByMPRequest(filter *DashboardFilter, orgScope *OrganizationScope) ([]DashboardMPRequestCount, error)
+}
+
+type DashboardRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewDashboardRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *DashboardRepository {
+	return &DashboardRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func DashboardRepositoryFactory(
+	log *logrus.Logger,
+) IDashboardRepository {
+	db := config.NewDatabase()
+	return NewDashboardRepository(log, db)
+}
+
+// FindMPRequests returns the manpower requests created in the period
+func (r *DashboardRepository) FindMPRequests(filter *DashboardFilter, orgScope *OrganizationScope) ([]entity.MPRequest, error) {
+	var mpRequests []entity.MPRequest
+	if err := r.DB.Scopes(
+		filter.ByPeriod("mp_requests.created_at"),
+		filter.ByMPRequest("mp_requests.id"),
+		orgScope.ByMPRequest("mp_requests.id"),
+	).Find(&mpRequests).Error; err != nil {
+		r.Log.Error("[DashboardRepository.FindMPRequests] " + err.Error())
+		return nil, errors.New("[DashboardRepository.FindMPRequests] " + err.Error())
+	}
+
+	return mpRequests, nil
+}
+
+// CountApplicants counts the applicants who applied in the period, the hired ones and the hired
+// ones speaking more than one language
+func (r *DashboardRepository) CountApplicants(filter *DashboardFilter, orgScope *OrganizationScope) (*DashboardApplicantCount, error) {
+	var count DashboardApplicantCount
+	if err := r.DB.Model(&entity.Applicant{}).
+		Joins("LEFT JOIN user_profiles ON user_profiles.id = applicants.user_profile_id").
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE applicants.status = ?) AS hired,
+			COUNT(*) FILTER (WHERE applicants.status = ? AND user_profiles.bilingual = 'yes') AS hired_bilingual`,
+			entity.APPLICANT_STATUS_HIRED, entity.APPLICANT_STATUS_HIRED).
+		Scopes(
+			filter.ByPeriod("applicants.applied_date"),
+			filter.ByJobPosting("applicants.job_posting_id"),
+			orgScope.ByJobPosting("applicants.job_posting_id"),
+		).Scan(&count).Error; err != nil {
+		r.Log.Error("[DashboardRepository.CountApplicants] " + err.Error())
+		return nil, errors.New("[DashboardRepository.CountApplicants] " + err.Error())
+	}
+
+	return &count, nil
+}
+
+// CountDaysToHire buckets the employees who joined in the period by the days since their
+// manpower request was created
+func (r *DashboardRepository) CountDaysToHire(filter *DashboardFilter, orgScope *OrganizationScope) (*DashboardDaysToHire, error) {
+	var count DashboardDaysToHire
+	days := "(ds.joined_date - mr.created_at::date)"
+	if err := r.DB.Table("document_sendings ds").
+		Joins("JOIN job_postings jp ON jp.id = ds.job_posting_id").
+		Joins("JOIN mp_requests mr ON mr.id = jp.mp_request_id").
+		Select(`COUNT(*) FILTER (WHERE `+days+` > 30) AS over_30,
+			COUNT(*) FILTER (WHERE `+days+` BETWEEN 21 AND 30) AS from_21_to_30,
+			COUNT(*) FILTER (WHERE `+days+` BETWEEN 11 AND 20) AS from_11_to_20,
+			COUNT(*) FILTER (WHERE `+days+` <= 10) AS up_to_10,
+			COALESCE(AVG(`+days+`), 0) AS average_days,
+			COUNT(*) AS total_employed`).
+		Where("ds.joined_date IS NOT NULL AND ds.deleted_at IS NULL").
+		Scopes(
+			filter.ByPeriod("ds.joined_date"),
+			filter.ByJobPosting("ds.job_posting_id"),
+			orgScope.ByOrganization("ds.for_organization_id"),
+		).Scan(&count).Error; err != nil {
+		r.Log.Error("[DashboardRepository.CountDaysToHire] " + err.Error())
+		return nil, errors.New("[DashboardRepository.CountDaysToHire] " + err.Error())
+	}
+
+	return &count, nil
+}
+
+// CountDocumentSendingsByJobLevel counts the documents sent in the period per job level
+func (r *DashboardRepository) CountDocumentSendingsByJobLevel(filter *DashboardFilter, orgScope *OrganizationScope) ([]DashboardJobLevelCount, error) {
+	var counts []DashboardJobLevelCount
+	if err := r.DB.Model(&entity.DocumentSending{}).
+		Select("document_sendings.job_level_id, COUNT(*) AS total").
+		Where("document_sendings.job_level_id IS NOT NULL").
+		Scopes(
+			filter.ByPeriod("document_sendings.document_date"),
+			filter.ByJobPosting("document_sendings.job_posting_id"),
+			orgScope.ByOrganization("document_sendings.for_organization_id"),
+		).
+		Group("document_sendings.job_level_id").
+		Order("total DESC").
+		Scan(&counts).Error; err != nil {
+		r.Log.Error("[DashboardRepository.CountDocumentSendingsByJobLevel] " + err.Error())
+		return nil, errors.New("[DashboardRepository.CountDocumentSendingsByJobLevel] " + err.Error())
+	}
+
+	return counts, nil
+}
+
+// CountHiredByMPRequest counts the hired applicants who applied in the period per manpower
+// request, keyed by the id of the request in the manpower service
+func (r *DashboardRepository) CountHiredByMPRequest(filter *DashboardFilter, orgScope *OrganizationScope) ([]DashboardMPRequestCount, error) {
+	var counts []DashboardMPRequestCount
+	if err := r.DB.Model(&entity.Applicant{}).
+		Joins("JOIN job_postings ON job_postings.id = applicants.job_posting_id").
+		Joins("JOIN mp_requests ON mp_requests.id = job_postings.mp_request_id").
+		Select("mp_requests.mpr_clone_id, COUNT(*) AS total").
+		Where("applicants.status = ?", entity.APPLICANT_STATUS_HIRED).
+		Scopes(
+			filter.ByPeriod("applicants.applied_date"),
+			filter.ByJobPosting("applicants.job_posting_id"),
+			orgScope.ByJobPosting("applicants.job_posting_id"),
+		).
+		Group("mp_requests.mpr_clone_id").
+		Scan(&counts).Error; err != nil {
+		r.Log.Error("[DashboardRepository.CountHiredByMPRequest] " + err.Error())
+		return nil, errors.New("[DashboardRepository.CountHiredByMPRequest] " + err.Error())
+	}
+
+	return counts, nil
+}
@@ -16,7 +16,7 @@ type IDocumentAgreementRepository interface {
 	FindByKeys(keys map[string]interface{}) (*entity.DocumentAgreement, error)
 	FindAllByKeys(keys map[string]interface{}) (*[]entity.DocumentAgreement, error)
 	FindAllByDocumentSendingIDs(documentSendings []uuid.UUID) (*[]entity.DocumentAgreement, error)
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, iDs []uuid.UUID) (*[]entity.DocumentAgreement, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, iDs []uuid.UUID, orgScope *OrganizationScope) (*[]entity.DocumentAgreement, int64, error)
 }
 
 type DocumentAgreementRepository struct {
@@ -104,11 +104,11 @@ func (r *DocumentAgreementRepository) FindByKeys(keys map[string]interface{}) (*
 	return &ent, nil
 }
 
-func (r *DocumentAgreementRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, iDs []uuid.UUID) (*[]entity.DocumentAgreement, int64, error) {
+func (r *DocumentAgreementRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, iDs []uuid.UUID, orgScope *OrganizationScope) (*[]entity.DocumentAgreement, int64, error) {
 	var documentAgreements []entity.DocumentAgreement
 	var total int64
 
-	db := r.DB.Model(&entity.DocumentAgreement{})
+	db := r.DB.Model(&entity.DocumentAgreement{}).Scopes(orgScope.ByDocumentSending("document_sending_id"))
 
 	if search != "" {
 		db = db.Where("document_agreement.applicant.user_profile.name LIKE ?", "%"+search+"%")
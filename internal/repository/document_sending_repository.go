@@ -11,7 +11,7 @@ import (
 type IDocumentSendingRepository interface {
 	CreateDocumentSending(ent *entity.DocumentSending) (*entity.DocumentSending, error)
 	UpdateDocumentSending(ent *entity.DocumentSending) (*entity.DocumentSending, error)
-	FindAllPaginatedByDocumentSetupIDs(documentSetupIDs []uuid.UUID, page, pageSize int, search string, sort map[string]interface{}) (*[]entity.DocumentSending, int64, error)
+	FindAllPaginatedByDocumentSetupIDs(documentSetupIDs []uuid.UUID, page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.DocumentSending, int64, error)
 	FindByDocumentSetupIDsAndApplicantID(documentSetupIDs []uuid.UUID, applicantID uuid.UUID) (*entity.DocumentSending, error)
 	FindByID(id uuid.UUID) (*entity.DocumentSending, error)
 	DeleteDocumentSending(id uuid.UUID) error
@@ -19,8 +19,6 @@ type IDocumentSendingRepository interface {
 	GetHighestDocumentNumberByDate(date string) (int, error)
 	FindByKeys(keys map[string]interface{}) (*entity.DocumentSending, error)
 	FindAllByDocumentSetupIDs(documentSetupIDs []uuid.UUID) (*[]entity.DocumentSending, error)
-	GetJobLevelIdDistinct() ([]uuid.UUID, error)
-	CountByJobLevelID(jobLevelID uuid.UUID) (int, error)
 	FindAllByKeys(keys map[string]interface{}) (*[]entity.DocumentSending, error)
 }
 
@@ -102,12 +100,12 @@ func (r *DocumentSendingRepository) UpdateDocumentSending(ent *entity.DocumentSe
 	return ent, nil
 }
 
-func (r *DocumentSendingRepository) FindAllPaginatedByDocumentSetupIDs(documentSetupIDs []uuid.UUID, page, pageSize int, search string, sort map[string]interface{}) (*[]entity.DocumentSending, int64, error) {
+func (r *DocumentSendingRepository) FindAllPaginatedByDocumentSetupIDs(documentSetupIDs []uuid.UUID, page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.DocumentSending, int64, error) {
 	var documentSendings []entity.DocumentSending
 	var total int64
 
 	query := r.DB.Preload("DocumentSetup").Preload("ProjectRecruitmentLine").Preload("Applicant.UserProfile").Preload("JobPosting.ProjectRecruitmentHeader").Where("document_setup_id IN (?)", documentSetupIDs).
-		Where("document_setup_id IN (?)", documentSetupIDs)
+		Where("document_setup_id IN (?)", documentSetupIDs).Scopes(orgScope.ByOrganization("for_organization_id"))
 
 	if search != "" {
 		query = query.Where("document_number LIKE ?", "%"+search+"%")
@@ -235,33 +233,6 @@ func (r *DocumentSendingRepository) FindAllByDocumentSetupIDs(documentSetupIDs [
 	return &documentSendings, nil
 }
 
-func (r *DocumentSendingRepository) GetJobLevelIdDistinct() ([]uuid.UUID, error) {
-	var jobLevelIds []uuid.UUID
-	err := r.DB.Raw(`
-		SELECT DISTINCT job_level_id
-		FROM document_sendings
-	`).Scan(&jobLevelIds).Error
-	if err != nil {
-		r.Log.Errorf("[DocumentSendingRepository.GetJobLevelIdDistinct] error when querying distinct job level id: %v", err)
-		return nil, err
-	}
-	return jobLevelIds, nil
-}
-
-func (r *DocumentSendingRepository) CountByJobLevelID(jobLevelID uuid.UUID) (int, error) {
-	var count int
-	err := r.DB.Raw(`
-		SELECT COUNT(*)
-		FROM document_sendings
-		WHERE job_level_id = ?
-	`, jobLevelID).Scan(&count).Error
-	if err != nil {
-		r.Log.Errorf("[DocumentSendingRepository.CountByJobLevelID] error when querying count by job level id: %v", err)
-		return 0, err
-	}
-	return count, nil
-}
-
 func (r *DocumentSendingRepository) FindAllByKeys(keys map[string]interface{}) (*[]entity.DocumentSending, error) {
 	var ent []entity.DocumentSending
 	if err := r.DB.Where(keys).Preload("DocumentSetup").Preload("ProjectRecruitmentLine").Preload("Applicant.UserProfile").Preload("JobPosting.ProjectRecruitmentHeader").Find(&ent).Error; err != nil {
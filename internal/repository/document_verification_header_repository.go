@@ -11,7 +11,7 @@ import (
 )
 
 type IDocumentVerificationHeaderRepository interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.DocumentVerificationHeader, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.DocumentVerificationHeader, int64, error)
 	CreateDocumentVerificationHeader(ent *entity.DocumentVerificationHeader) (*entity.DocumentVerificationHeader, error)
 	UpdateDocumentVerificationHeader(ent *entity.DocumentVerificationHeader) (*entity.DocumentVerificationHeader, error)
 	FindByID(id uuid.UUID) (*entity.DocumentVerificationHeader, error)
@@ -109,11 +109,11 @@ func (r *DocumentVerificationHeaderRepository) FindByID(id uuid.UUID) (*entity.D
 	return &ent, nil
 }
 
-func (r *DocumentVerificationHeaderRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.DocumentVerificationHeader, int64, error) {
+func (r *DocumentVerificationHeaderRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.DocumentVerificationHeader, int64, error) {
 	var documentVerificationHeaders []entity.DocumentVerificationHeader
 	var total int64
 
-	query := r.DB.Preload("DocumentVerificationLines.DocumentVerification").Preload("ProjectRecruitmentLine.ProjectRecruitmentHeader").Preload("Applicant.UserProfile").Preload("JobPosting.ProjectRecruitmentHeader")
+	query := r.DB.Preload("DocumentVerificationLines.DocumentVerification").Preload("ProjectRecruitmentLine.ProjectRecruitmentHeader").Preload("Applicant.UserProfile").Preload("JobPosting.ProjectRecruitmentHeader").Scopes(orgScope.ByJobPosting("job_posting_id"))
 
 	if search != "" {
 		query = query.Where("name ILIKE ?", "%"+search+"%")
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IDuplicateCandidateRepository interface {
+	FindProbableDuplicates() ([]entity.DuplicateCandidate, error)
+	UpsertOpenCandidate(ent *entity.DuplicateCandidate) error
+	DeleteStaleOpenCandidates(detectedBefore time.Time) (int64, error)
+	FindAllPaginated(page, pageSize int, status string) (*[]entity.DuplicateCandidate, int64, error)
+	FindByID(id uuid.UUID) (*entity.DuplicateCandidate, error)
+	Dismiss(id uuid.UUID, resolvedBy uuid.UUID) error
+	Merge(candidateID *uuid.UUID, survivingProfileID, mergedProfileID, mergedBy uuid.UUID) (*entity.UserProfileMerge, error)
+}
+
+type DuplicateCandidateRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewDuplicateCandidateRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *DuplicateCandidateRepository {
+	return &DuplicateCandidateRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func DuplicateCandidateRepositoryFactory(
+	log *logrus.Logger,
+) IDuplicateCandidateRepository {
+	db := config.NewDatabase()
+	return NewDuplicateCandidateRepository(log, db)
+}
+
+// FindProbableDuplicates pairs the profiles sharing a KTP number, a phone number, an email or a
+// name and birth date. KTP numbers are encrypted and compared on their blind index, phone
+// numbers on their digits with +62 read as 0, names on their letters only, so spacing and case
+// do not hide a duplicate. Each key is joined on its own so the indexes can be used.
+func (r *DuplicateCandidateRepository) FindProbableDuplicates() ([]entity.DuplicateCandidate, error) {
+	var pairs []entity.DuplicateCandidate
+
+	if err := r.DB.Raw(`
+		WITH p AS (
+			SELECT id,
+				ktp_number_bidx AS ktp,
+				CASE WHEN length(regexp_replace(COALESCE(phone_number, ''), '\D', '', 'g')) >= 9
+					THEN regexp_replace(regexp_replace(phone_number, '\D', '', 'g'), '^62', '0') END AS phone,
+				NULLIF(lower(trim(email)), '') AS email,
+				CASE WHEN birth_date > DATE '1900-01-01' AND regexp_replace(lower(COALESCE(name, '')), '[^a-z]', '', 'g') <> ''
+					THEN regexp_replace(lower(name), '[^a-z]', '', 'g') || '|' || birth_date::text END AS name_birth
+			FROM user_profiles
+			WHERE deleted_at IS NULL AND anonymised_at IS NULL
+		), pairs AS (
+			SELECT a.id AS a_id, b.id AS b_id FROM p a JOIN p b ON a.ktp = b.ktp AND a.id < b.id
+			UNION SELECT a.id, b.id FROM p a JOIN p b ON a.phone = b.phone AND a.id < b.id
+			UNION SELECT a.id, b.id FROM p a JOIN p b ON a.email = b.email AND a.id < b.id
+			UNION SELECT a.id, b.id FROM p a JOIN p b ON a.name_birth = b.name_birth AND a.id < b.id
+		)
+		SELECT pairs.a_id AS user_profile_id, pairs.b_id AS duplicate_profile_id,
+			COALESCE(a.ktp = b.ktp, false) AS match_ktp_number,
+			COALESCE(a.phone = b.phone, false) AS match_phone_number,
+			COALESCE(a.email = b.email, false) AS match_email,
+			COALESCE(a.name_birth = b.name_birth, false) AS match_name_birth_date
+		FROM pairs
+		JOIN p a ON a.id = pairs.a_id
+		JOIN p b ON b.id = pairs.b_id
+	`).Scan(&pairs).Error; err != nil {
+		r.Log.Error("[DuplicateCandidateRepository.FindProbableDuplicates] " + err.Error())
+		return nil, errors.New("[DuplicateCandidateRepository.FindProbableDuplicates] " + err.Error())
+	}
+
+	return pairs, nil
+}
+
+// UpsertOpenCandidate records a detected pair. A pair HR already merged or dismissed is left
+// as it is.
+func (r *DuplicateCandidateRepository) UpsertOpenCandidate(ent *entity.DuplicateCandidate) error {
+	if err := r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_profile_id"}, {Name: "duplicate_profile_id"}},
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: "duplicate_candidates", Name: "status"}, Value: entity.DUPLICATE_CANDIDATE_STATUS_OPEN},
+		}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "match_ktp_number", "match_phone_number", "match_email", "match_name_birth_date", "detected_at", "updated_at"}),
+	}).Create(ent).Error; err != nil {
+		r.Log.Error("[DuplicateCandidateRepository.UpsertOpenCandidate] " + err.Error())
+		return errors.New("[DuplicateCandidateRepository.UpsertOpenCandidate] " + err.Error())
+	}
+
+	return nil
+}
+
+// DeleteStaleOpenCandidates removes the open pairs the last detection did not find again,
+// because a profile was corrected or deleted
+func (r *DuplicateCandidateRepository) DeleteStaleOpenCandidates(detectedBefore time.Time) (int64, error) {
+	res := r.DB.Unscoped().Where("status = ? AND detected_at < ?", entity.DUPLICATE_CANDIDATE_STATUS_OPEN, detectedBefore).Delete(&entity.DuplicateCandidate{})
+	if res.Error != nil {
+		r.Log.Error("[DuplicateCandidateRepository.DeleteStaleOpenCandidates] " + res.Error.Error())
+		return 0, errors.New("[DuplicateCandidateRepository.DeleteStaleOpenCandidates] " + res.Error.Error())
+	}
+
+	return res.RowsAffected, nil
+}
+
+func (r *DuplicateCandidateRepository) FindAllPaginated(page, pageSize int, status string) (*[]entity.DuplicateCandidate, int64, error) {
+	var candidates []entity.DuplicateCandidate
+	var total int64
+
+	filtered := func() *gorm.DB {
+		query := r.DB.Model(&entity.DuplicateCandidate{})
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	if err := filtered().Count(&total).Error; err != nil {
+		r.Log.Error("[DuplicateCandidateRepository.FindAllPaginated] " + err.Error())
+		return nil, 0, errors.New("[DuplicateCandidateRepository.FindAllPaginated] " + err.Error())
+	}
+
+	if err := r.withProfiles(filtered()).Order("score DESC").Order("detected_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&candidates).Error; err != nil {
+		r.Log.Error("[DuplicateCandidateRepository.FindAllPaginated] " + err.Error())
+		return nil, 0, errors.New("[DuplicateCandidateRepository.FindAllPaginated] " + err.Error())
+	}
+
+	return &candidates, total, nil
+}
+
+func (r *DuplicateCandidateRepository) FindByID(id uuid.UUID) (*entity.DuplicateCandidate, error) {
+	var candidate entity.DuplicateCandidate
+
+	if err := r.withProfiles(r.DB).Where("id = ?", id).First(&candidate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[DuplicateCandidateRepository.FindByID] " + err.Error())
+		return nil, errors.New("[DuplicateCandidateRepository.FindByID] " + err.Error())
+	}
+
+	return &candidate, nil
+}
+
+func (r *DuplicateCandidateRepository) Dismiss(id uuid.UUID, resolvedBy uuid.UUID) error {
+	now := time.Now()
+	if err := r.DB.Model(&entity.DuplicateCandidate{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      entity.DUPLICATE_CANDIDATE_STATUS_DISMISSED,
+		"resolved_at": now,
+		"resolved_by": resolvedBy,
+		"updated_at":  now,
+	}).Error; err != nil {
+		r.Log.Error("[DuplicateCandidateRepository.Dismiss] " + err.Error())
+		return errors.New("[DuplicateCandidateRepository.Dismiss] " + err.Error())
+	}
+
+	return nil
+}
+
+// Merge moves everything of the merged profile to the surviving one in one transaction and
+// deletes the merged profile. Educations, work experiences and skills the surviving profile
+// already has are dropped instead of copied, and so are the applications and question
+// responses for a job posting the surviving profile applied to as well. Empty fields of the
+// surviving profile are filled from the merged one.
+func (r *DuplicateCandidateRepository) Merge(candidateID *uuid.UUID, survivingProfileID, mergedProfileID, mergedBy uuid.UUID) (*entity.UserProfileMerge, error) {
+	now := time.Now()
+	audit := &entity.UserProfileMerge{
+		DuplicateCandidateID: candidateID,
+		SurvivingProfileID:   survivingProfileID,
+		MergedProfileID:      mergedProfileID,
+		MergedBy:             mergedBy,
+		MergedAt:             now,
+	}
+	s, m := survivingProfileID, mergedProfileID
+	keepID := uuid.Nil
+	if candidateID != nil {
+		keepID = *candidateID
+	}
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var merged entity.UserProfile
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", m).First(&merged).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", s).First(&entity.UserProfile{}).Error; err != nil {
+			return err
+		}
+		audit.MergedUserID = merged.UserID
+
+		exec := func(counter *int, sql string, values ...interface{}) error {
+			res := tx.Exec(sql, values...)
+			if res.Error != nil {
+				return res.Error
+			}
+			if counter != nil {
+				*counter = int(res.RowsAffected)
+			}
+			return nil
+		}
+
+		steps := []struct {
+			counter *int
+			sql     string
+			values  []interface{}
+		}{
+			// applications
+			{&audit.DroppedApplicants, `UPDATE applicants SET deleted_at = ? WHERE user_profile_id = ? AND deleted_at IS NULL
+				AND job_posting_id IN (SELECT job_posting_id FROM applicants WHERE user_profile_id = ? AND deleted_at IS NULL)`, []interface{}{now, m, s}},
+			{&audit.MovedApplicants, `UPDATE applicants SET user_profile_id = ?, updated_at = ? WHERE user_profile_id = ? AND deleted_at IS NULL`, []interface{}{s, now, m}},
+			{nil, `UPDATE test_applicants SET user_profile_id = ? WHERE user_profile_id = ? AND applicant_id IN (SELECT id FROM applicants WHERE user_profile_id = ?)`, []interface{}{s, m, s}},
+			{nil, `UPDATE interview_applicants SET user_profile_id = ? WHERE user_profile_id = ? AND applicant_id IN (SELECT id FROM applicants WHERE user_profile_id = ?)`, []interface{}{s, m, s}},
+			{nil, `UPDATE fgd_applicants SET user_profile_id = ? WHERE user_profile_id = ? AND applicant_id IN (SELECT id FROM applicants WHERE user_profile_id = ?)`, []interface{}{s, m, s}},
+			{nil, `UPDATE administrative_results ar SET user_profile_id = ? FROM administrative_selections sel
+				WHERE sel.id = ar.administrative_selection_id AND ar.user_profile_id = ?
+				AND sel.job_posting_id IN (SELECT job_posting_id FROM applicants WHERE user_profile_id = ? AND deleted_at IS NULL)`, []interface{}{s, m, s}},
+			{&audit.MovedQuestionResponses, `UPDATE question_responses SET user_profile_id = ?, updated_at = ? WHERE user_profile_id = ? AND deleted_at IS NULL
+				AND job_posting_id NOT IN (SELECT job_posting_id FROM question_responses WHERE user_profile_id = ? AND deleted_at IS NULL)`, []interface{}{s, now, m, s}},
+			// profile entries
+			{nil, `UPDATE educations e SET deleted_at = ? WHERE e.user_profile_id = ? AND e.deleted_at IS NULL AND EXISTS (
+				SELECT 1 FROM educations o WHERE o.user_profile_id = ? AND o.deleted_at IS NULL
+				AND o.education_level = e.education_level AND lower(trim(o.school_name)) = lower(trim(e.school_name)))`, []interface{}{now, m, s}},
+			{&audit.MovedEducations, `UPDATE educations SET user_profile_id = ?, updated_at = ? WHERE user_profile_id = ? AND deleted_at IS NULL`, []interface{}{s, now, m}},
+			{nil, `UPDATE work_experiences w SET deleted_at = ? WHERE w.user_profile_id = ? AND w.deleted_at IS NULL AND EXISTS (
+				SELECT 1 FROM work_experiences o WHERE o.user_profile_id = ? AND o.deleted_at IS NULL
+				AND lower(trim(o.name)) = lower(trim(w.name)) AND lower(trim(o.company_name)) = lower(trim(w.company_name)))`, []interface{}{now, m, s}},
+			{&audit.MovedWorkExperiences, `UPDATE work_experiences SET user_profile_id = ?, updated_at = ? WHERE user_profile_id = ? AND deleted_at IS NULL`, []interface{}{s, now, m}},
+			{nil, `UPDATE skills k SET deleted_at = ? WHERE k.user_profile_id = ? AND k.deleted_at IS NULL AND EXISTS (
+				SELECT 1 FROM skills o WHERE o.user_profile_id = ? AND o.deleted_at IS NULL AND lower(trim(o.name)) = lower(trim(k.name)))`, []interface{}{now, m, s}},
+			{&audit.MovedSkills, `UPDATE skills SET user_profile_id = ?, updated_at = ? WHERE user_profile_id = ? AND deleted_at IS NULL`, []interface{}{s, now, m}},
+			// saved jobs and CV drafts
+			{nil, `INSERT INTO saved_jobs (job_posting_id, user_profile_id, created_at, updated_at)
+				SELECT job_posting_id, ?, created_at, ? FROM saved_jobs WHERE user_profile_id = ? ON CONFLICT DO NOTHING`, []interface{}{s, now, m}},
+			{nil, `DELETE FROM saved_jobs WHERE user_profile_id = ?`, []interface{}{m}},
+			{nil, `UPDATE curriculum_vitae_drafts SET user_profile_id = ? WHERE user_profile_id = ?`, []interface{}{s, m}},
+			// the profile itself
+			{nil, `UPDATE user_profiles sp SET
+					phone_number = COALESCE(NULLIF(sp.phone_number, ''), mp.phone_number),
+					ktp = COALESCE(NULLIF(sp.ktp, ''), mp.ktp),
+					ktp_number = COALESCE(NULLIF(sp.ktp_number, ''), mp.ktp_number),
+					ktp_number_bidx = CASE WHEN NULLIF(sp.ktp_number, '') IS NULL THEN mp.ktp_number_bidx ELSE sp.ktp_number_bidx END,
+					email = COALESCE(NULLIF(sp.email, ''), mp.email),
+					address = COALESCE(NULLIF(sp.address, ''), mp.address),
+					birth_place = COALESCE(NULLIF(sp.birth_place, ''), mp.birth_place),
+					curriculum_vitae = COALESCE(NULLIF(sp.curriculum_vitae, ''), mp.curriculum_vitae),
+					avatar = COALESCE(NULLIF(sp.avatar, ''), mp.avatar),
+					religion = COALESCE(NULLIF(sp.religion, ''), mp.religion),
+					bilingual = COALESCE(NULLIF(sp.bilingual, ''), mp.bilingual),
+					midsuit_id = COALESCE(sp.midsuit_id, mp.midsuit_id),
+					updated_at = ?
+				FROM user_profiles mp WHERE sp.id = ? AND mp.id = ?`, []interface{}{now, s, m}},
+			{nil, `UPDATE user_profiles SET deleted_at = ?, updated_at = ? WHERE id = ?`, []interface{}{now, now, m}},
+			// pairs with the merged profile are settled, the surviving profile is checked again on the next detection
+			{nil, `DELETE FROM duplicate_candidates WHERE status = ? AND (user_profile_id = ? OR duplicate_profile_id = ?) AND id <> ?`,
+				[]interface{}{entity.DUPLICATE_CANDIDATE_STATUS_OPEN, m, m, keepID}},
+		}
+		for _, step := range steps {
+			if err := exec(step.counter, step.sql, step.values...); err != nil {
+				return err
+			}
+		}
+
+		if candidateID != nil {
+			if err := tx.Model(&entity.DuplicateCandidate{}).Where("id = ?", *candidateID).Updates(map[string]interface{}{
+				"status":      entity.DUPLICATE_CANDIDATE_STATUS_MERGED,
+				"resolved_at": now,
+				"resolved_by": mergedBy,
+				"updated_at":  now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(audit).Error
+	})
+	if err != nil {
+		r.Log.Error("[DuplicateCandidateRepository.Merge] " + err.Error())
+		return nil, errors.New("[DuplicateCandidateRepository.Merge] " + err.Error())
+	}
+
+	return audit, nil
+}
+
+func (r *DuplicateCandidateRepository) withProfiles(db *gorm.DB) *gorm.DB {
+	// a merged profile is deleted, it is still shown on its pair
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	return db.
+		Preload("UserProfile", unscoped).
+		Preload("UserProfile.Applicants").
+		Preload("DuplicateProfile", unscoped).
+		Preload("DuplicateProfile.Applicants")
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IErasureRequestRepository interface {
+	CreateErasureRequest(ent *entity.ErasureRequest) (*entity.ErasureRequest, error)
+	FindByID(id uuid.UUID) (*entity.ErasureRequest, error)
+	FindLatestByUserProfileID(userProfileID uuid.UUID) (*entity.ErasureRequest, error)
+	FindAllPaginated(page, pageSize int, status string) (*[]entity.ErasureRequest, int64, error)
+	Reject(id uuid.UUID, reviewedBy uuid.UUID, reviewNote string) error
+	UpdateFailedFiles(id uuid.UUID, paths []string) error
+}
+
+type ErasureRequestRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewErasureRequestRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *ErasureRequestRepository {
+	return &ErasureRequestRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func ErasureRequestRepositoryFactory(
+	log *logrus.Logger,
+) IErasureRequestRepository {
+	db := config.NewDatabase()
+	return NewErasureRequestRepository(log, db)
+}
+
+func (r *ErasureRequestRepository) CreateErasureRequest(ent *entity.ErasureRequest) (*entity.ErasureRequest, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[ErasureRequestRepository.CreateErasureRequest] " + err.Error())
+		return nil, errors.New("[ErasureRequestRepository.CreateErasureRequest] " + err.Error())
+	}
+
+	return r.FindByID(ent.ID)
+}
+
+func (r *ErasureRequestRepository) FindByID(id uuid.UUID) (*entity.ErasureRequest, error) {
+	var request entity.ErasureRequest
+
+	if err := r.DB.Preload("UserProfile").Preload("UserProfile.Applicants").Where("id = ?", id).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[ErasureRequestRepository.FindByID] " + err.Error())
+		return nil, errors.New("[ErasureRequestRepository.FindByID] " + err.Error())
+	}
+
+	return &request, nil
+}
+
+func (r *ErasureRequestRepository) FindLatestByUserProfileID(userProfileID uuid.UUID) (*entity.ErasureRequest, error) {
+	var request entity.ErasureRequest
+
+	if err := r.DB.Preload("UserProfile").Preload("UserProfile.Applicants").Where("user_profile_id = ?", userProfileID).Order("requested_at DESC").First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[ErasureRequestRepository.FindLatestByUserProfileID] " + err.Error())
+		return nil, errors.New("[ErasureRequestRepository.FindLatestByUserProfileID] " + err.Error())
+	}
+
+	return &request, nil
+}
+
+func (r *ErasureRequestRepository) FindAllPaginated(page, pageSize int, status string) (*[]entity.ErasureRequest, int64, error) {
+	var requests []entity.ErasureRequest
+	var total int64
+
+	filtered := func() *gorm.DB {
+		query := r.DB.Model(&entity.ErasureRequest{})
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	if err := filtered().Count(&total).Error; err != nil {
+		r.Log.Error("[ErasureRequestRepository.FindAllPaginated] " + err.Error())
+		return nil, 0, errors.New("[ErasureRequestRepository.FindAllPaginated] " + err.Error())
+	}
+
+	if err := filtered().Preload("UserProfile").Preload("UserProfile.Applicants").Order("requested_at ASC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&requests).Error; err != nil {
+		r.Log.Error("[ErasureRequestRepository.FindAllPaginated] " + err.Error())
+		return nil, 0, errors.New("[ErasureRequestRepository.FindAllPaginated] " + err.Error())
+	}
+
+	return &requests, total, nil
+}
+
+func (r *ErasureRequestRepository) Reject(id uuid.UUID, reviewedBy uuid.UUID, reviewNote string) error {
+	now := time.Now()
+	if err := r.DB.Model(&entity.ErasureRequest{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      entity.ERASURE_REQUEST_STATUS_REJECTED,
+		"reviewed_by": reviewedBy,
+		"reviewed_at": now,
+		"review_note": reviewNote,
+		"updated_at":  now,
+	}).Error; err != nil {
+		r.Log.Error("[ErasureRequestRepository.Reject] " + err.Error())
+		return errors.New("[ErasureRequestRepository.Reject] " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *ErasureRequestRepository) UpdateFailedFiles(id uuid.UUID, paths []string) error {
+	if err := r.DB.Model(&entity.ErasureRequest{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_files": strings.Join(paths, "\n"),
+		"updated_at":   time.Now(),
+	}).Error; err != nil {
+		r.Log.Error("[ErasureRequestRepository.UpdateFailedFiles] " + err.Error())
+		return errors.New("[ErasureRequestRepository.UpdateFailedFiles] " + err.Error())
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
@@ -11,7 +12,7 @@ import (
 )
 
 type IFgdScheduleRepository interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.FgdSchedule, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.FgdSchedule, int64, error)
 	CreateFgdSchedule(fgdSchedule *entity.FgdSchedule) (*entity.FgdSchedule, error)
 	FindByID(id uuid.UUID) (*entity.FgdSchedule, error)
 	FindByIDForMyself(id uuid.UUID, userProfile uuid.UUID) (*entity.FgdSchedule, error)
@@ -24,6 +25,8 @@ type IFgdScheduleRepository interface {
 	FindByIDForMyselfAssessor(id uuid.UUID, fgdScheduleAssessorID uuid.UUID) (*entity.FgdSchedule, error)
 	FindByIDsForMyselfAssessor(ids []uuid.UUID, fgdScheduleAssessorID uuid.UUID) (*[]entity.FgdSchedule, error)
 	FindByIDForAnswer(id, jobPostingID uuid.UUID) (*entity.FgdSchedule, error)
+	CompleteEndedFgdSchedules(now time.Time) (int64, error)
+	FindAllInProgressByScheduleDateRange(from, to time.Time) (*[]entity.FgdSchedule, error)
 }
 
 type FgdScheduleRepository struct {
@@ -43,11 +46,11 @@ func FgdScheduleRepositoryFactory(log *logrus.Logger) IFgdScheduleRepository {
 	return NewFgdScheduleRepository(log, db)
 }
 
-func (r *FgdScheduleRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.FgdSchedule, int64, error) {
+func (r *FgdScheduleRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.FgdSchedule, int64, error) {
 	var fgdSchedules []entity.FgdSchedule
 	var total int64
 
-	query := r.DB.Preload("JobPosting").Preload("ProjectPic").Preload("ProjectRecruitmentHeader").Preload("ProjectRecruitmentLine.TemplateActivityLine")
+	query := r.DB.Preload("JobPosting").Preload("ProjectPic").Preload("ProjectRecruitmentHeader").Preload("ProjectRecruitmentLine.TemplateActivityLine").Scopes(orgScope.ByJobPosting("job_posting_id"))
 
 	if search != "" {
 		query = query.Where("document_number ILIKE ?", "%"+search+"%")
@@ -307,3 +310,27 @@ func (r *FgdScheduleRepository) FindByIDForAnswer(id, jobPostingID uuid.UUID) (*
 	}
 	return &fgdSchedule, nil
 }
+
+// CompleteEndedFgdSchedules moves in progress schedules whose last slot has ended to COMPLETED, now is the local wall clock
+func (r *FgdScheduleRepository) CompleteEndedFgdSchedules(now time.Time) (int64, error) {
+	res := r.DB.Model(&entity.FgdSchedule{}).
+		Where("status = ? AND (schedule_date + end_time) < ?", entity.FGD_SCHEDULE_STATUS_IN_PROGRESS, now.Format("2006-01-02 15:04:05")).
+		Update("status", entity.FGD_SCHEDULE_STATUS_COMPLETED)
+	if res.Error != nil {
+		r.Log.Error("[FgdScheduleRepository.CompleteEndedFgdSchedules] " + res.Error.Error())
+		return 0, errors.New("[FgdScheduleRepository.CompleteEndedFgdSchedules] " + res.Error.Error())
+	}
+	return res.RowsAffected, nil
+}
+
+// FindAllInProgressByScheduleDateRange returns the in progress schedules held between from and to (both days included) with their participants
+func (r *FgdScheduleRepository) FindAllInProgressByScheduleDateRange(from, to time.Time) (*[]entity.FgdSchedule, error) {
+	var entities []entity.FgdSchedule
+	if err := r.DB.Preload("JobPosting").Preload("FgdApplicants.UserProfile").Preload("FgdAssessors").
+		Where("status = ? AND schedule_date BETWEEN ? AND ?", entity.FGD_SCHEDULE_STATUS_IN_PROGRESS, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&entities).Error; err != nil {
+		r.Log.Error("[FgdScheduleRepository.FindAllInProgressByScheduleDateRange] " + err.Error())
+		return nil, errors.New("[FgdScheduleRepository.FindAllInProgressByScheduleDateRange] " + err.Error())
+	}
+	return &entities, nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	userProfileEncryptedColumns     = []string{"ktp_number", "current_salary", "expected_salary", "religion"}
+	documentSendingEncryptedColumns = []string{"basic_wage", "positional_allowance", "operational_allowance", "meal_allowance", "house_allowance"}
+)
+
+type IFieldEncryptionRepository interface {
+	ReencryptUserProfiles(batchSize int) (int, error)
+	ReencryptDocumentSendings(batchSize int) (int, error)
+}
+
+type FieldEncryptionRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewFieldEncryptionRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *FieldEncryptionRepository {
+	return &FieldEncryptionRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func FieldEncryptionRepositoryFactory(
+	log *logrus.Logger,
+) IFieldEncryptionRepository {
+	db := config.NewDatabase()
+	return NewFieldEncryptionRepository(log, db)
+}
+
+// ReencryptUserProfiles writes back every profile, deleted ones included, that still holds
+// plaintext or values sealed with a retired data key, and fills missing KTP blind indexes.
+// It returns the number of profiles written.
+func (r *FieldEncryptionRepository) ReencryptUserProfiles(batchSize int) (int, error) {
+	count, err := r.reencryptTable("user_profiles", append([]string{"ktp_number_bidx"}, userProfileEncryptedColumns...), batchSize, func(row map[string]interface{}) (bool, error) {
+		needed, err := needsReencryption(row, userProfileEncryptedColumns)
+		if err != nil || needed {
+			return needed, err
+		}
+		// encrypted before the blind index existed
+		return row["ktp_number"] != nil && row["ktp_number_bidx"] == nil, nil
+	}, func(id string) error {
+		var profile entity.UserProfile
+		if err := r.DB.Unscoped().First(&profile, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := SetKtpNumberBidx(&profile); err != nil {
+			return err
+		}
+
+		columns := userProfileEncryptedColumns
+		if profile.KtpNumberBidx != "" {
+			columns = append([]string{"ktp_number_bidx"}, columns...)
+		}
+		// UpdateColumns so re-encrypting does not count as an edit of the profile
+		return r.DB.Unscoped().Model(&profile).Select(columns).UpdateColumns(&profile).Error
+	})
+	if err != nil {
+		r.Log.Error("[FieldEncryptionRepository.ReencryptUserProfiles] " + err.Error())
+		return count, errors.New("[FieldEncryptionRepository.ReencryptUserProfiles] " + err.Error())
+	}
+
+	return count, nil
+}
+
+// ReencryptDocumentSendings writes back every document sending whose compensation is still
+// plaintext or sealed with a retired data key. It returns the number of rows written.
+func (r *FieldEncryptionRepository) ReencryptDocumentSendings(batchSize int) (int, error) {
+	count, err := r.reencryptTable("document_sendings", documentSendingEncryptedColumns, batchSize, func(row map[string]interface{}) (bool, error) {
+		return needsReencryption(row, documentSendingEncryptedColumns)
+	}, func(id string) error {
+		var documentSending entity.DocumentSending
+		if err := r.DB.Unscoped().First(&documentSending, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return r.DB.Unscoped().Model(&documentSending).Select(documentSendingEncryptedColumns).UpdateColumns(&documentSending).Error
+	})
+	if err != nil {
+		r.Log.Error("[FieldEncryptionRepository.ReencryptDocumentSendings] " + err.Error())
+		return count, errors.New("[FieldEncryptionRepository.ReencryptDocumentSendings] " + err.Error())
+	}
+
+	return count, nil
+}
+
+// reencryptTable walks the table by id reading the raw column values, the rows needing it are
+// loaded through their entity and written back so the serializer seals them with the active key
+func (r *FieldEncryptionRepository) reencryptTable(table string, columns []string, batchSize int, needed func(row map[string]interface{}) (bool, error), reencrypt func(id string) error) (int, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	selects := append([]string{"id"}, columns...)
+
+	count := 0
+	lastID := ""
+	for {
+		var rows []map[string]interface{}
+		if err := r.DB.Table(table).Select(selects).Where("id > ?", lastID).Order("id ASC").Limit(batchSize).Find(&rows).Error; err != nil {
+			return count, err
+		}
+		if len(rows) == 0 {
+			return count, nil
+		}
+
+		for _, row := range rows {
+			lastID = rawColumnString(row["id"])
+
+			ok, err := needed(row)
+			if err != nil {
+				return count, fmt.Errorf("%s %s: %w", table, lastID, err)
+			}
+			if !ok {
+				continue
+			}
+
+			if err := reencrypt(lastID); err != nil {
+				return count, fmt.Errorf("%s %s: %w", table, lastID, err)
+			}
+			count++
+		}
+	}
+}
+
+func needsReencryption(row map[string]interface{}, columns []string) (bool, error) {
+	encryptor := config.NewFieldEncryptor()
+	for _, column := range columns {
+		if row[column] == nil {
+			continue
+		}
+
+		needed, err := encryptor.NeedsReencryption(rawColumnString(row[column]))
+		if err != nil || needed {
+			return needed, err
+		}
+	}
+
+	return false, nil
+}
+
+func rawColumnString(value interface{}) string {
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+	return fmt.Sprint(value)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
@@ -11,7 +12,7 @@ import (
 )
 
 type IInterviewRepository interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.Interview, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.Interview, int64, error)
 	CreateInterview(interview *entity.Interview) (*entity.Interview, error)
 	FindByID(id uuid.UUID) (*entity.Interview, error)
 	FindByIDForMyself(id uuid.UUID, userProfile uuid.UUID) (*entity.Interview, error)
@@ -23,6 +24,8 @@ type IInterviewRepository interface {
 	FindAllByKeys(keys map[string]interface{}) (*[]entity.Interview, error)
 	FindByIDsForMyselfAssessor(ids []uuid.UUID, interviewAssessorID uuid.UUID) (*[]entity.Interview, error)
 	FindByIDForAnswer(id, jobPostingID uuid.UUID) (*entity.Interview, error)
+	CompleteEndedInterviews(now time.Time) (int64, error)
+	FindAllInProgressByScheduleDateRange(from, to time.Time) (*[]entity.Interview, error)
 }
 
 type InterviewRepository struct {
@@ -42,11 +45,11 @@ func InterviewRepositoryFactory(log *logrus.Logger) IInterviewRepository {
 	return NewInterviewRepository(log, db)
 }
 
-func (r *InterviewRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.Interview, int64, error) {
+func (r *InterviewRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.Interview, int64, error) {
 	var interviews []entity.Interview
 	var total int64
 
-	query := r.DB.Preload("ProjectRecruitmentHeader").Preload("JobPosting").Preload("ProjectRecruitmentLine.TemplateActivityLine")
+	query := r.DB.Preload("ProjectRecruitmentHeader").Preload("JobPosting").Preload("ProjectRecruitmentLine.TemplateActivityLine").Scopes(orgScope.ByJobPosting("job_posting_id"))
 
 	if search != "" {
 		query = query.Where("document_number ILIKE ?", "%"+search+"%")
@@ -283,3 +286,27 @@ func (r *InterviewRepository) FindByIDForAnswer(id, jobPostingID uuid.UUID) (*en
 	}
 	return &interview, nil
 }
+
+// CompleteEndedInterviews moves in progress schedules whose last slot has ended to COMPLETED, now is the local wall clock
+func (r *InterviewRepository) CompleteEndedInterviews(now time.Time) (int64, error) {
+	res := r.DB.Model(&entity.Interview{}).
+		Where("status = ? AND (schedule_date + end_time) < ?", entity.INTERVIEW_STATUS_IN_PROGRESS, now.Format("2006-01-02 15:04:05")).
+		Update("status", entity.INTERVIEW_STATUS_COMPLETED)
+	if res.Error != nil {
+		r.Log.Error("[InterviewRepository.CompleteEndedInterviews] " + res.Error.Error())
+		return 0, errors.New("[InterviewRepository.CompleteEndedInterviews] " + res.Error.Error())
+	}
+	return res.RowsAffected, nil
+}
+
+// FindAllInProgressByScheduleDateRange returns the in progress schedules held between from and to (both days included) with their participants
+func (r *InterviewRepository) FindAllInProgressByScheduleDateRange(from, to time.Time) (*[]entity.Interview, error) {
+	var entities []entity.Interview
+	if err := r.DB.Preload("JobPosting").Preload("InterviewApplicants.UserProfile").Preload("InterviewAssessors").
+		Where("status = ? AND schedule_date BETWEEN ? AND ?", entity.INTERVIEW_STATUS_IN_PROGRESS, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&entities).Error; err != nil {
+		r.Log.Error("[InterviewRepository.FindAllInProgressByScheduleDateRange] " + err.Error())
+		return nil, errors.New("[InterviewRepository.FindAllInProgressByScheduleDateRange] " + err.Error())
+	}
+	return &entities, nil
+}
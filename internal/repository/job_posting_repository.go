@@ -2,17 +2,19 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type IJobPostingRepository interface {
 	CreateJobPosting(ent *entity.JobPosting) (*entity.JobPosting, error)
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.JobPosting, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *OrganizationScope) (*[]entity.JobPosting, int64, error)
 	FindAllPaginatedShowOnly(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.JobPosting, int64, error)
 	FindAlPaginatedByMPRequestIDs(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, mpRequestIDs []uuid.UUID) (*[]entity.JobPosting, int64, error)
 	FindByID(id uuid.UUID) (*entity.JobPosting, error)
@@ -28,6 +30,13 @@ type IJobPostingRepository interface {
 	DeleteSavedJob(userProfileID, jobPostingID uuid.UUID) error
 	FindSavedJob(userProfileID, jobPostingID uuid.UUID) (*entity.SavedJob, error)
 	GetAllByKeys(keys map[string]interface{}) (*[]entity.JobPosting, error)
+	FindAllPublished(today time.Time) (*[]entity.JobPosting, error)
+	FindBySlug(slug string) (*entity.JobPosting, error)
+	SlugExists(slug string) (bool, error)
+	UpdateSlug(id uuid.UUID, slug string) error
+	FindAllWithoutSlug() (*[]entity.JobPosting, error)
+	OpenStartedJobPostings(today time.Time) (int64, error)
+	CloseEndedJobPostings(today time.Time) (int64, error)
 }
 
 type JobPostingRepository struct {
@@ -35,6 +44,15 @@ type JobPostingRepository struct {
 	DB  *gorm.DB
 }
 
+// ErrJobPostingSlugTaken is returned when another posting got the slug between the check and the write
+var ErrJobPostingSlugTaken = errors.New("job posting slug is already taken")
+
+// isJobPostingSlugConflict reports whether err is the unique violation of the slug index
+func isJobPostingSlugConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_job_postings_slug"
+}
+
 func NewJobPostingRepository(log *logrus.Logger, db *gorm.DB) *JobPostingRepository {
 	return &JobPostingRepository{Log: log, DB: db}
 }
@@ -52,6 +70,9 @@ func (r *JobPostingRepository) CreateJobPosting(ent *entity.JobPosting) (*entity
 
 	if err := tx.Create(ent).Error; err != nil {
 		tx.Rollback()
+		if isJobPostingSlugConflict(err) {
+			return nil, ErrJobPostingSlugTaken
+		}
 		return nil, errors.New("[JobPostingRepository.Create] " + err.Error())
 	}
 
@@ -66,11 +87,11 @@ func (r *JobPostingRepository) CreateJobPosting(ent *entity.JobPosting) (*entity
 	return ent, nil
 }
 
-func (r *JobPostingRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.JobPosting, int64, error) {
+func (r *JobPostingRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}, orgScope *OrganizationScope) (*[]entity.JobPosting, int64, error) {
 	var entities []entity.JobPosting
 	var total int64
 
-	query := r.DB.Preload("ProjectRecruitmentHeader").Preload("Applicants")
+	query := r.DB.Preload("ProjectRecruitmentHeader").Preload("Applicants").Scopes(orgScope.ByOrganization("for_organization_id"))
 
 	if search != "" {
 		query = query.Where("document_number ILIKE ? OR name ILIKE ?", "%"+search+"%", "%"+search+"%")
@@ -402,3 +423,84 @@ func (r *JobPostingRepository) GetAllByKeys(keys map[string]interface{}) (*[]ent
 	}
 	return &entities, nil
 }
+
+// FindAllPublished returns the approved or opened postings shown to candidates that are open on the given day, newest first
+func (r *JobPostingRepository) FindAllPublished(today time.Time) (*[]entity.JobPosting, error) {
+	var entities []entity.JobPosting
+	day := today.Format("2006-01-02")
+	if err := r.DB.Where("status IN ? AND is_show = ? AND start_date <= ? AND end_date >= ?", []entity.JobPostingStatus{entity.JOB_POSTING_STATUS_APPROVED, entity.JOB_POSTING_STATUS_IN_PROGRESS}, "YES", day, day).
+		Order("document_date DESC").Order("created_at DESC").
+		Find(&entities).Error; err != nil {
+		r.Log.Error("[JobPostingRepository.FindAllPublished] " + err.Error())
+		return nil, errors.New("[JobPostingRepository.FindAllPublished] " + err.Error())
+	}
+	return &entities, nil
+}
+
+func (r *JobPostingRepository) FindBySlug(slug string) (*entity.JobPosting, error) {
+	var ent entity.JobPosting
+	if err := r.DB.Where("slug = ?", slug).First(&ent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[JobPostingRepository.FindBySlug] " + err.Error())
+		return nil, errors.New("[JobPostingRepository.FindBySlug] " + err.Error())
+	}
+	return &ent, nil
+}
+
+// SlugExists also looks at deleted postings so a slug is never handed out twice
+func (r *JobPostingRepository) SlugExists(slug string) (bool, error) {
+	var count int64
+	if err := r.DB.Unscoped().Model(&entity.JobPosting{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		r.Log.Error("[JobPostingRepository.SlugExists] " + err.Error())
+		return false, errors.New("[JobPostingRepository.SlugExists] " + err.Error())
+	}
+	return count > 0, nil
+}
+
+func (r *JobPostingRepository) UpdateSlug(id uuid.UUID, slug string) error {
+	if err := r.DB.Model(&entity.JobPosting{}).Where("id = ?", id).Update("slug", slug).Error; err != nil {
+		if isJobPostingSlugConflict(err) {
+			return ErrJobPostingSlugTaken
+		}
+		r.Log.Error("[JobPostingRepository.UpdateSlug] " + err.Error())
+		return errors.New("[JobPostingRepository.UpdateSlug] " + err.Error())
+	}
+	return nil
+}
+
+// FindAllWithoutSlug returns the postings created before slugs existed
+func (r *JobPostingRepository) FindAllWithoutSlug() (*[]entity.JobPosting, error) {
+	var entities []entity.JobPosting
+	if err := r.DB.Where("slug IS NULL OR slug = ''").Order("created_at ASC").Find(&entities).Error; err != nil {
+		r.Log.Error("[JobPostingRepository.FindAllWithoutSlug] " + err.Error())
+		return nil, errors.New("[JobPostingRepository.FindAllWithoutSlug] " + err.Error())
+	}
+	return &entities, nil
+}
+
+// OpenStartedJobPostings moves approved postings whose period has started to IN PROGRESS
+func (r *JobPostingRepository) OpenStartedJobPostings(today time.Time) (int64, error) {
+	day := today.Format("2006-01-02")
+	res := r.DB.Model(&entity.JobPosting{}).
+		Where("status = ? AND start_date <= ? AND end_date >= ?", entity.JOB_POSTING_STATUS_APPROVED, day, day).
+		Update("status", entity.JOB_POSTING_STATUS_IN_PROGRESS)
+	if res.Error != nil {
+		r.Log.Error("[JobPostingRepository.OpenStartedJobPostings] " + res.Error.Error())
+		return 0, errors.New("[JobPostingRepository.OpenStartedJobPostings] " + res.Error.Error())
+	}
+	return res.RowsAffected, nil
+}
+
+// CloseEndedJobPostings closes approved and in progress postings whose end date has passed
+func (r *JobPostingRepository) CloseEndedJobPostings(today time.Time) (int64, error) {
+	res := r.DB.Model(&entity.JobPosting{}).
+		Where("status IN ? AND end_date < ?", []entity.JobPostingStatus{entity.JOB_POSTING_STATUS_APPROVED, entity.JOB_POSTING_STATUS_IN_PROGRESS}, today.Format("2006-01-02")).
+		Update("status", entity.JOB_POSTING_STATUS_CLOSE)
+	if res.Error != nil {
+		r.Log.Error("[JobPostingRepository.CloseEndedJobPostings] " + res.Error.Error())
+		return 0, errors.New("[JobPostingRepository.CloseEndedJobPostings] " + res.Error.Error())
+	}
+	return res.RowsAffected, nil
+}
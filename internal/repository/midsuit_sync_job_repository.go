@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IMidsuitSyncJobRepository interface {
+	CreateMidsuitSyncJob(ent *entity.MidsuitSyncJob) (*entity.MidsuitSyncJob, error)
+	FindByID(id uuid.UUID) (*entity.MidsuitSyncJob, error)
+	FindByDocumentSendingID(documentSendingID uuid.UUID) (*entity.MidsuitSyncJob, error)
+	FindAllPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.MidsuitSyncJob, int64, error)
+	FindAllDue(now time.Time, staleBefore time.Time, limit int) (*[]entity.MidsuitSyncJob, error)
+	FindAllByStatus(status entity.MidsuitSyncStatus) (*[]entity.MidsuitSyncJob, error)
+	ClaimMidsuitSyncJob(id uuid.UUID, staleBefore time.Time) (bool, error)
+	UpdateMidsuitSyncJobFields(id uuid.UUID, fields map[string]interface{}) error
+	FindStepByIdempotencyKey(key string) (*entity.MidsuitSyncStep, error)
+	CreateMidsuitSyncStep(ent *entity.MidsuitSyncStep) (*entity.MidsuitSyncStep, error)
+	UpdateMidsuitSyncStepFields(id uuid.UUID, fields map[string]interface{}) error
+}
+
+type MidsuitSyncJobRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewMidsuitSyncJobRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *MidsuitSyncJobRepository {
+	return &MidsuitSyncJobRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func MidsuitSyncJobRepositoryFactory(
+	log *logrus.Logger,
+) IMidsuitSyncJobRepository {
+	db := config.NewDatabase()
+	return NewMidsuitSyncJobRepository(log, db)
+}
+
+func (r *MidsuitSyncJobRepository) CreateMidsuitSyncJob(ent *entity.MidsuitSyncJob) (*entity.MidsuitSyncJob, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.CreateMidsuitSyncJob] " + err.Error())
+		return nil, errors.New("[MidsuitSyncJobRepository.CreateMidsuitSyncJob] " + err.Error())
+	}
+
+	return r.FindByID(ent.ID)
+}
+
+func (r *MidsuitSyncJobRepository) FindByID(id uuid.UUID) (*entity.MidsuitSyncJob, error) {
+	var ent entity.MidsuitSyncJob
+
+	if err := r.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderByColumn{Column: clause.Column{Name: "order"}})
+	}).First(&ent, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			r.Log.Error("[MidsuitSyncJobRepository.FindByID] " + err.Error())
+			return nil, errors.New("[MidsuitSyncJobRepository.FindByID] " + err.Error())
+		}
+	}
+
+	return &ent, nil
+}
+
+func (r *MidsuitSyncJobRepository) FindByDocumentSendingID(documentSendingID uuid.UUID) (*entity.MidsuitSyncJob, error) {
+	var ent entity.MidsuitSyncJob
+
+	if err := r.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderByColumn{Column: clause.Column{Name: "order"}})
+	}).Where("document_sending_id = ?", documentSendingID).First(&ent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			r.Log.Error("[MidsuitSyncJobRepository.FindByDocumentSendingID] " + err.Error())
+			return nil, errors.New("[MidsuitSyncJobRepository.FindByDocumentSendingID] " + err.Error())
+		}
+	}
+
+	return &ent, nil
+}
+
+func (r *MidsuitSyncJobRepository) FindAllPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.MidsuitSyncJob, int64, error) {
+	var res []entity.MidsuitSyncJob
+	var total int64
+
+	query := r.DB.Model(&entity.MidsuitSyncJob{})
+
+	if status, ok := filter["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	for key, value := range sort {
+		query = query.Order(key + " " + value.(string))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.FindAllPaginated] " + err.Error())
+		return nil, 0, errors.New("[MidsuitSyncJobRepository.FindAllPaginated] " + err.Error())
+	}
+
+	if err := query.Offset((page-1)*pageSize).Limit(pageSize).Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderByColumn{Column: clause.Column{Name: "order"}})
+	}).Find(&res).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.FindAllPaginated] " + err.Error())
+		return nil, 0, errors.New("[MidsuitSyncJobRepository.FindAllPaginated] " + err.Error())
+	}
+
+	return &res, total, nil
+}
+
+// FindAllDue returns pending jobs whose backoff has elapsed, plus running jobs
+// that were started before staleBefore and are assumed to be abandoned.
+func (r *MidsuitSyncJobRepository) FindAllDue(now time.Time, staleBefore time.Time, limit int) (*[]entity.MidsuitSyncJob, error) {
+	var res []entity.MidsuitSyncJob
+
+	if err := r.DB.
+		Where("(status = ? AND (next_run_at IS NULL OR next_run_at <= ?)) OR (status = ? AND started_at < ?)",
+			entity.MIDSUIT_SYNC_STATUS_PENDING, now, entity.MIDSUIT_SYNC_STATUS_RUNNING, staleBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&res).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.FindAllDue] " + err.Error())
+		return nil, errors.New("[MidsuitSyncJobRepository.FindAllDue] " + err.Error())
+	}
+
+	return &res, nil
+}
+
+func (r *MidsuitSyncJobRepository) FindAllByStatus(status entity.MidsuitSyncStatus) (*[]entity.MidsuitSyncJob, error) {
+	var res []entity.MidsuitSyncJob
+
+	if err := r.DB.Where("status = ?", status).Order("created_at ASC").Find(&res).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.FindAllByStatus] " + err.Error())
+		return nil, errors.New("[MidsuitSyncJobRepository.FindAllByStatus] " + err.Error())
+	}
+
+	return &res, nil
+}
+
+// ClaimMidsuitSyncJob marks the job as running only if nobody else holds it, so
+// the worker, a manual rerun and other replicas never run the same job twice.
+func (r *MidsuitSyncJobRepository) ClaimMidsuitSyncJob(id uuid.UUID, staleBefore time.Time) (bool, error) {
+	now := time.Now()
+	result := r.DB.Model(&entity.MidsuitSyncJob{}).
+		Where("id = ?", id).
+		Where("status IN ? OR (status = ? AND started_at < ?)",
+			[]entity.MidsuitSyncStatus{entity.MIDSUIT_SYNC_STATUS_PENDING, entity.MIDSUIT_SYNC_STATUS_FAILED},
+			entity.MIDSUIT_SYNC_STATUS_RUNNING, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     entity.MIDSUIT_SYNC_STATUS_RUNNING,
+			"started_at": now,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.ClaimMidsuitSyncJob] " + result.Error.Error())
+		return false, errors.New("[MidsuitSyncJobRepository.ClaimMidsuitSyncJob] " + result.Error.Error())
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *MidsuitSyncJobRepository) UpdateMidsuitSyncJobFields(id uuid.UUID, fields map[string]interface{}) error {
+	fields["updated_at"] = time.Now()
+	if err := r.DB.Model(&entity.MidsuitSyncJob{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.UpdateMidsuitSyncJobFields] " + err.Error())
+		return errors.New("[MidsuitSyncJobRepository.UpdateMidsuitSyncJobFields] " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *MidsuitSyncJobRepository) FindStepByIdempotencyKey(key string) (*entity.MidsuitSyncStep, error) {
+	var ent entity.MidsuitSyncStep
+
+	if err := r.DB.Where("idempotency_key = ?", key).First(&ent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			r.Log.Error("[MidsuitSyncJobRepository.FindStepByIdempotencyKey] " + err.Error())
+			return nil, errors.New("[MidsuitSyncJobRepository.FindStepByIdempotencyKey] " + err.Error())
+		}
+	}
+
+	return &ent, nil
+}
+
+func (r *MidsuitSyncJobRepository) CreateMidsuitSyncStep(ent *entity.MidsuitSyncStep) (*entity.MidsuitSyncStep, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.CreateMidsuitSyncStep] " + err.Error())
+		return nil, errors.New("[MidsuitSyncJobRepository.CreateMidsuitSyncStep] " + err.Error())
+	}
+
+	return ent, nil
+}
+
+func (r *MidsuitSyncJobRepository) UpdateMidsuitSyncStepFields(id uuid.UUID, fields map[string]interface{}) error {
+	fields["updated_at"] = time.Now()
+	if err := r.DB.Model(&entity.MidsuitSyncStep{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		r.Log.Error("[MidsuitSyncJobRepository.UpdateMidsuitSyncStepFields] " + err.Error())
+		return errors.New("[MidsuitSyncJobRepository.UpdateMidsuitSyncStepFields] " + err.Error())
+	}
+
+	return nil
+}
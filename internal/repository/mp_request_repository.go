@@ -15,7 +15,7 @@ type IMPRequestRepository interface {
 	FindAllPaginated(page int, pageSize int, search string, filter map[string]interface{}) (*[]entity.MPRequest, int64, error)
 	FindAllPaginatedWhereDoesntHaveJobPosting(jobPostingID string, page int, pageSize int, search string, filter map[string]interface{}) (*[]entity.MPRequest, int64, error)
 	FindByID(id uuid.UUID) (*entity.MPRequest, error)
-	FindAll() (*[]entity.MPRequest, error)
+	FindAll(orgScope *OrganizationScope) (*[]entity.MPRequest, error)
 	Update(ent *entity.MPRequest) (*entity.MPRequest, error)
 	FindAllByMPRCloneIDs(mprCloneIDs []*string) (*[]entity.MPRequest, error)
 }
@@ -94,10 +94,10 @@ func (r *MPRequestRepository) FindByID(id uuid.UUID) (*entity.MPRequest, error)
 	return &mpRequest, nil
 }
 
-func (r *MPRequestRepository) FindAll() (*[]entity.MPRequest, error) {
+func (r *MPRequestRepository) FindAll(orgScope *OrganizationScope) (*[]entity.MPRequest, error) {
 	var mpRequests []entity.MPRequest
 
-	if err := r.DB.Find(&mpRequests).Error; err != nil {
+	if err := r.DB.Scopes(orgScope.ByMPRequest("id")).Find(&mpRequests).Error; err != nil {
 		r.Log.Errorf("[MPRequestRepository.FindAll] error when find all mp request headers: %v", err)
 		return nil, errors.New("[MPRequestRepository.FindAll] error when find all mp request headers " + err.Error())
 	}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrganizationScope limits list queries to the companies a caller works for.
+// A nil scope is unrestricted, a scope without organizations matches nothing.
+type OrganizationScope struct {
+	OrganizationIDs []uuid.UUID
+}
+
+// Allows reports whether a record of the given organization is visible in the scope
+func (s *OrganizationScope) Allows(organizationID *uuid.UUID) bool {
+	if s == nil {
+		return true
+	}
+	if organizationID == nil {
+		return false
+	}
+	for _, id := range s.OrganizationIDs {
+		if id == *organizationID {
+			return true
+		}
+	}
+	return false
+}
+
+// ByOrganization filters on a column holding the organization id itself,
+// e.g. "job_postings.for_organization_id"
+func (s *OrganizationScope) ByOrganization(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s == nil {
+			return db
+		}
+		return db.Where(column+" IN ?", s.organizationIDs())
+	}
+}
+
+// ByJobPosting filters on a column referencing job_postings.id, e.g. "interviews.job_posting_id"
+func (s *OrganizationScope) ByJobPosting(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s == nil {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM job_postings WHERE for_organization_id IN ? AND deleted_at IS NULL)", s.organizationIDs())
+	}
+}
+
+// ByDocumentSending filters on a column referencing document_sendings.id, e.g. "document_agreements.document_sending_id"
+func (s *OrganizationScope) ByDocumentSending(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s == nil {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM document_sendings WHERE for_organization_id IN ? AND deleted_at IS NULL)", s.organizationIDs())
+	}
+}
+
+// ByMPRequest filters on a column referencing mp_requests.id through the job posting made for it
+func (s *OrganizationScope) ByMPRequest(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s == nil {
+			return db
+		}
+		return db.Where(column+" IN (SELECT mp_request_id FROM job_postings WHERE for_organization_id IN ? AND deleted_at IS NULL)", s.organizationIDs())
+	}
+}
+
+// RawCondition returns an " AND column IN ?" clause with its argument for raw queries,
+// or nothing when the scope is unrestricted
+func (s *OrganizationScope) RawCondition(column string) (string, []interface{}) {
+	if s == nil {
+		return "", nil
+	}
+	return " AND " + column + " IN ?", []interface{}{s.organizationIDs()}
+}
+
+func (s *OrganizationScope) organizationIDs() []uuid.UUID {
+	if len(s.OrganizationIDs) == 0 {
+		// IN over an empty list is invalid SQL, the nil uuid never matches a real organization
+		return []uuid.UUID{uuid.Nil}
+	}
+	return s.OrganizationIDs
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// IOrganizationScopeRepository checks single records against an organization scope, so a record
+// read, changed or deleted by id is held to the same scope as the lists
+type IOrganizationScopeRepository interface {
+	HasJobPosting(id uuid.UUID, orgScope *OrganizationScope) (bool, error)
+	HasDocumentSending(id uuid.UUID, orgScope *OrganizationScope) (bool, error)
+	HasTestScheduleHeader(id uuid.UUID, orgScope *OrganizationScope) (bool, error)
+	HasInterview(id uuid.UUID, orgScope *OrganizationScope) (bool, error)
+	HasFgdSchedule(id uuid.UUID, orgScope *OrganizationScope) (bool, error)
+	HasApplicant(id uuid.UUID, orgScope *OrganizationScope) (bool, error)
+}
+
+type OrganizationScopeRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewOrganizationScopeRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *OrganizationScopeRepository {
+	return &OrganizationScopeRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func OrganizationScopeRepositoryFactory(
+	log *logrus.Logger,
+) IOrganizationScopeRepository {
+	db := config.NewDatabase()
+	return NewOrganizationScopeRepository(log, db)
+}
+
+func (r *OrganizationScopeRepository) HasJobPosting(id uuid.UUID, orgScope *OrganizationScope) (bool, error) {
+	return r.has(&entity.JobPosting{}, "job_postings.id", id, orgScope, orgScope.ByOrganization("job_postings.for_organization_id"))
+}
+
+func (r *OrganizationScopeRepository) HasDocumentSending(id uuid.UUID, orgScope *OrganizationScope) (bool, error) {
+	return r.has(&entity.DocumentSending{}, "document_sendings.id", id, orgScope, orgScope.ByDocumentSending("document_sendings.id"))
+}
+
+func (r *OrganizationScopeRepository) HasTestScheduleHeader(id uuid.UUID, orgScope *OrganizationScope) (bool, error) {
+	return r.has(&entity.TestScheduleHeader{}, "test_schedule_headers.id", id, orgScope, orgScope.ByJobPosting("test_schedule_headers.job_posting_id"))
+}
+
+func (r *OrganizationScopeRepository) HasInterview(id uuid.UUID, orgScope *OrganizationScope) (bool, error) {
+	return r.has(&entity.Interview{}, "interviews.id", id, orgScope, orgScope.ByJobPosting("interviews.job_posting_id"))
+}
+
+func (r *OrganizationScopeRepository) HasFgdSchedule(id uuid.UUID, orgScope *OrganizationScope) (bool, error) {
+	return r.has(&entity.FgdSchedule{}, "fgd_schedules.id", id, orgScope, orgScope.ByJobPosting("fgd_schedules.job_posting_id"))
+}
+
+func (r *OrganizationScopeRepository) HasApplicant(id uuid.UUID, orgScope *OrganizationScope) (bool, error) {
+	return r.has(&entity.Applicant{}, "applicants.id", id, orgScope, orgScope.ByJobPosting("applicants.job_posting_id"))
+}
+
+// has is true for an unrestricted scope, the caller then finds out by itself whether the
+// record exists
+func (r *OrganizationScopeRepository) has(model interface{}, idColumn string, id uuid.UUID, orgScope *OrganizationScope, scope func(db *gorm.DB) *gorm.DB) (bool, error) {
+	if orgScope == nil {
+		return true, nil
+	}
+
+	var count int64
+	if err := r.DB.Model(model).Scopes(scope).Where(idColumn+" = ?", id).Count(&count).Error; err != nil {
+		r.Log.Error("[OrganizationScopeRepository.has] " + err.Error())
+		return false, errors.New("[OrganizationScopeRepository.has] " + err.Error())
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PersonalDataFile is a stored file holding personal data of a candidate. Category groups the
+// files in the export, Label tells which record the file belongs to.
+type PersonalDataFile struct {
+	Category string
+	Label    string
+	Path     string
+}
+
+// referralsOfProfile matches the referrals of a candidate: by their profile, their applications,
+// or the email the referrer gave for a candidate who has not applied yet
+const referralsOfProfile = `user_profile_id = @id OR applicant_id IN (SELECT id FROM applicants WHERE user_profile_id = @id)
+	OR LOWER(candidate_email) = (SELECT LOWER(email) FROM user_profiles WHERE id = @id AND COALESCE(email, '') <> '')`
+
+type IPersonalDataRepository interface {
+	FindExportProfile(userProfileID uuid.UUID) (*entity.UserProfile, error)
+	FindReferrals(userProfileID uuid.UUID) ([]entity.Referral, error)
+	FindFiles(userProfileID uuid.UUID) ([]PersonalDataFile, error)
+	Anonymise(erasureRequestID, userProfileID, reviewedBy uuid.UUID, reviewNote string) ([]PersonalDataFile, error)
+}
+
+type PersonalDataRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewPersonalDataRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *PersonalDataRepository {
+	return &PersonalDataRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func PersonalDataRepositoryFactory(
+	log *logrus.Logger,
+) IPersonalDataRepository {
+	db := config.NewDatabase()
+	return NewPersonalDataRepository(log, db)
+}
+
+// FindExportProfile returns the profile with everything the candidate entered or was given
+// during recruitment: entries, applications with their job postings and answers
+func (r *PersonalDataRepository) FindExportProfile(userProfileID uuid.UUID) (*entity.UserProfile, error) {
+	var profile entity.UserProfile
+
+	if err := r.DB.
+		Preload("WorkExperiences").
+		Preload("Educations").
+		Preload("Skills").
+		Preload("Applicants").
+		Preload("Applicants.JobPosting").
+		Preload("QuestionResponses").
+		Preload("QuestionResponses.Question").
+		Preload("QuestionResponses.JobPosting").
+		Where("id = ?", userProfileID).
+		First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[PersonalDataRepository.FindExportProfile] " + err.Error())
+		return nil, errors.New("[PersonalDataRepository.FindExportProfile] " + err.Error())
+	}
+
+	return &profile, nil
+}
+
+// FindReferrals returns the referrals of the candidate with their job postings
+func (r *PersonalDataRepository) FindReferrals(userProfileID uuid.UUID) ([]entity.Referral, error) {
+	var referrals []entity.Referral
+	if err := r.DB.Preload("JobPosting").
+		Where(referralsOfProfile, map[string]interface{}{"id": userProfileID}).
+		Order("created_at").
+		Find(&referrals).Error; err != nil {
+		r.Log.Error("[PersonalDataRepository.FindReferrals] " + err.Error())
+		return nil, errors.New("[PersonalDataRepository.FindReferrals] " + err.Error())
+	}
+
+	return referrals, nil
+}
+
+// FindFiles lists every stored file of the candidate, deleted records included since their
+// files are still kept
+func (r *PersonalDataRepository) FindFiles(userProfileID uuid.UUID) ([]PersonalDataFile, error) {
+	files, err := r.findFiles(r.DB, userProfileID)
+	if err != nil {
+		r.Log.Error("[PersonalDataRepository.FindFiles] " + err.Error())
+		return nil, errors.New("[PersonalDataRepository.FindFiles] " + err.Error())
+	}
+
+	return files, nil
+}
+
+// Anonymise removes the personal data of the profile and completes the erasure request, in
+// one transaction. What statistics are built on stays: the applications and their statuses,
+// gender, age, the birth year, education levels, majors and schools, skills and salaries. The
+// profile is detached from the account, so the candidate can register again. The returned
+// files are no longer referenced and are left to the caller to delete.
+func (r *PersonalDataRepository) Anonymise(erasureRequestID, userProfileID, reviewedBy uuid.UUID, reviewNote string) ([]PersonalDataFile, error) {
+	now := time.Now()
+	var files []PersonalDataFile
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userProfileID).First(&entity.UserProfile{}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		files, err = r.findFiles(tx, userProfileID)
+		if err != nil {
+			return err
+		}
+
+		// the referral stays for the referrer's bonus, the candidate is no longer named in it. It
+		// runs before the profile step since it matches on the email of the profile.
+		if err := tx.Unscoped().Model(&entity.Referral{}).
+			Where(referralsOfProfile, map[string]interface{}{"id": userProfileID}).
+			Updates(map[string]interface{}{
+				"candidate_name":  entity.ANONYMISED_USER_PROFILE_NAME,
+				"candidate_email": "",
+				"candidate_phone": nil,
+				"note":            nil,
+				"updated_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+
+		steps := []struct {
+			sql    string
+			values []interface{}
+		}{
+			// documents exchanged during recruitment
+			{`UPDATE document_sendings SET path = NULL, detail_content = NULL, home_location = NULL, updated_at = ? WHERE applicant_id IN (
+					SELECT id FROM applicants WHERE user_profile_id = ?)`, []interface{}{now, userProfileID}},
+			{`UPDATE document_agreements SET path = '', updated_at = ? WHERE applicant_id IN (
+					SELECT id FROM applicants WHERE user_profile_id = ?)`, []interface{}{now, userProfileID}},
+			{`UPDATE document_verification_lines SET path = '', answer = NULL, updated_at = ? WHERE document_verification_header_id IN (
+					SELECT h.id FROM document_verification_headers h JOIN applicants a ON a.id = h.applicant_id WHERE a.user_profile_id = ?)`,
+				[]interface{}{now, userProfileID}},
+			{`UPDATE question_responses SET answer = NULL, answer_file = NULL, updated_at = ? WHERE user_profile_id = ?`, []interface{}{now, userProfileID}},
+			// reminders that are still to be sent
+			{`UPDATE schedule_reminders SET status = ?, updated_at = ? WHERE status = ? AND recipient_type = ? AND recipient_id IN (
+					SELECT id FROM test_applicants WHERE user_profile_id = ?
+					UNION SELECT id FROM interview_applicants WHERE user_profile_id = ?
+					UNION SELECT id FROM fgd_applicants WHERE user_profile_id = ?)`,
+				[]interface{}{entity.SCHEDULE_REMINDER_STATUS_CANCELLED, now, entity.SCHEDULE_REMINDER_STATUS_PENDING, entity.SCHEDULE_REMINDER_RECIPIENT_TYPE_APPLICANT,
+					userProfileID, userProfileID, userProfileID}},
+			// profile entries
+			{`UPDATE educations SET certificate = '', updated_at = ? WHERE user_profile_id = ?`, []interface{}{now, userProfileID}},
+			{`UPDATE work_experiences SET company_name = '', job_description = '', certificate = '', updated_at = ? WHERE user_profile_id = ?`, []interface{}{now, userProfileID}},
+			{`UPDATE skills SET certificate = '', updated_at = ? WHERE user_profile_id = ?`, []interface{}{now, userProfileID}},
+			// copies of the profile
+			{`DELETE FROM curriculum_vitae_drafts WHERE user_profile_id = ?`, []interface{}{userProfileID}},
+			{`DELETE FROM candidate_search_documents WHERE user_profile_id = ?`, []interface{}{userProfileID}},
+			{`DELETE FROM duplicate_candidates WHERE user_profile_id = ? OR duplicate_profile_id = ?`, []interface{}{userProfileID, userProfileID}},
+			// the consents stay as the record of what was agreed to
+			{`UPDATE privacy_consents SET ip_address = NULL, user_agent = NULL, updated_at = ? WHERE user_profile_id = ?`, []interface{}{now, userProfileID}},
+			{`UPDATE user_profiles SET
+					user_id = ?, name = ?, status = ?, anonymised_at = ?, updated_at = ?,
+					birth_date = date_trunc('year', birth_date)::date,
+					phone_number = NULL, birth_place = NULL, address = NULL, ktp = NULL, ktp_number = NULL, ktp_number_bidx = NULL, email = NULL,
+					curriculum_vitae = NULL, avatar = NULL, religion = NULL, marital_status = NULL, midsuit_id = NULL
+				WHERE id = ?`,
+				[]interface{}{uuid.New(), entity.ANONYMISED_USER_PROFILE_NAME, entity.USER_INACTIVE, now, now, userProfileID}},
+		}
+		for _, step := range steps {
+			if err := tx.Exec(step.sql, step.values...).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.ErasureRequest{}).Where("id = ?", erasureRequestID).Updates(map[string]interface{}{
+			"status":      entity.ERASURE_REQUEST_STATUS_COMPLETED,
+			"reviewed_by": reviewedBy,
+			"reviewed_at": now,
+			"review_note": reviewNote,
+			"updated_at":  now,
+		}).Error
+	})
+	if err != nil {
+		r.Log.Error("[PersonalDataRepository.Anonymise] " + err.Error())
+		return nil, errors.New("[PersonalDataRepository.Anonymise] " + err.Error())
+	}
+
+	return files, nil
+}
+
+func (r *PersonalDataRepository) findFiles(db *gorm.DB, userProfileID uuid.UUID) ([]PersonalDataFile, error) {
+	var files []PersonalDataFile
+
+	err := db.Raw(`
+		SELECT * FROM (
+			SELECT 'profile' AS category, 'ktp' AS label, ktp AS path FROM user_profiles WHERE id = @id
+			UNION ALL SELECT 'profile', 'curriculum_vitae', curriculum_vitae FROM user_profiles WHERE id = @id
+			UNION ALL SELECT 'profile', 'avatar', avatar FROM user_profiles WHERE id = @id
+			UNION ALL SELECT 'educations', school_name, certificate FROM educations WHERE user_profile_id = @id
+			UNION ALL SELECT 'work_experiences', company_name, certificate FROM work_experiences WHERE user_profile_id = @id
+			UNION ALL SELECT 'skills', name, certificate FROM skills WHERE user_profile_id = @id
+			UNION ALL SELECT 'answers', q.name, qr.answer_file FROM question_responses qr
+				JOIN questions q ON q.id = qr.question_id WHERE qr.user_profile_id = @id
+			UNION ALL SELECT 'curriculum_vitae_drafts', 'curriculum_vitae', file_path FROM curriculum_vitae_drafts WHERE user_profile_id = @id
+			UNION ALL SELECT 'document_sendings', ds.document_number, ds.path FROM document_sendings ds
+				JOIN applicants a ON a.id = ds.applicant_id WHERE a.user_profile_id = @id
+			UNION ALL SELECT 'document_agreements', 'agreement', da.path FROM document_agreements da
+				JOIN applicants a ON a.id = da.applicant_id WHERE a.user_profile_id = @id
+			UNION ALL SELECT 'document_verifications', dv.name, l.path FROM document_verification_lines l
+				JOIN document_verifications dv ON dv.id = l.document_verification_id
+				JOIN document_verification_headers h ON h.id = l.document_verification_header_id
+				JOIN applicants a ON a.id = h.applicant_id WHERE a.user_profile_id = @id
+		) f
+		WHERE COALESCE(f.path, '') <> ''
+	`, map[string]interface{}{"id": userProfileID}).Scan(&files).Error
+
+	return files, err
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IPrivacyNoticeRepository interface {
+	CreatePrivacyNotice(ent *entity.PrivacyNotice) (*entity.PrivacyNotice, error)
+	FindCurrent() (*entity.PrivacyNotice, error)
+	FindByVersion(version string) (*entity.PrivacyNotice, error)
+	FindAll() ([]entity.PrivacyNotice, error)
+	CreateConsent(ent *entity.PrivacyConsent) (*entity.PrivacyConsent, error)
+	FindConsentsByUserProfileID(userProfileID uuid.UUID) ([]entity.PrivacyConsent, error)
+}
+
+type PrivacyNoticeRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewPrivacyNoticeRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *PrivacyNoticeRepository {
+	return &PrivacyNoticeRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func PrivacyNoticeRepositoryFactory(
+	log *logrus.Logger,
+) IPrivacyNoticeRepository {
+	db := config.NewDatabase()
+	return NewPrivacyNoticeRepository(log, db)
+}
+
+func (r *PrivacyNoticeRepository) CreatePrivacyNotice(ent *entity.PrivacyNotice) (*entity.PrivacyNotice, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[PrivacyNoticeRepository.CreatePrivacyNotice] " + err.Error())
+		return nil, errors.New("[PrivacyNoticeRepository.CreatePrivacyNotice] " + err.Error())
+	}
+
+	return ent, nil
+}
+
+func (r *PrivacyNoticeRepository) FindCurrent() (*entity.PrivacyNotice, error) {
+	var notice entity.PrivacyNotice
+
+	if err := r.DB.Order("published_at DESC").First(&notice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[PrivacyNoticeRepository.FindCurrent] " + err.Error())
+		return nil, errors.New("[PrivacyNoticeRepository.FindCurrent] " + err.Error())
+	}
+
+	return &notice, nil
+}
+
+func (r *PrivacyNoticeRepository) FindByVersion(version string) (*entity.PrivacyNotice, error) {
+	var notice entity.PrivacyNotice
+
+	if err := r.DB.Where("version = ?", version).First(&notice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[PrivacyNoticeRepository.FindByVersion] " + err.Error())
+		return nil, errors.New("[PrivacyNoticeRepository.FindByVersion] " + err.Error())
+	}
+
+	return &notice, nil
+}
+
+func (r *PrivacyNoticeRepository) FindAll() ([]entity.PrivacyNotice, error) {
+	var notices []entity.PrivacyNotice
+
+	if err := r.DB.Order("published_at DESC").Find(&notices).Error; err != nil {
+		r.Log.Error("[PrivacyNoticeRepository.FindAll] " + err.Error())
+		return nil, errors.New("[PrivacyNoticeRepository.FindAll] " + err.Error())
+	}
+
+	return notices, nil
+}
+
+func (r *PrivacyNoticeRepository) CreateConsent(ent *entity.PrivacyConsent) (*entity.PrivacyConsent, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[PrivacyNoticeRepository.CreateConsent] " + err.Error())
+		return nil, errors.New("[PrivacyNoticeRepository.CreateConsent] " + err.Error())
+	}
+
+	return ent, nil
+}
+
+func (r *PrivacyNoticeRepository) FindConsentsByUserProfileID(userProfileID uuid.UUID) ([]entity.PrivacyConsent, error) {
+	var consents []entity.PrivacyConsent
+
+	if err := r.DB.Where("user_profile_id = ?", userProfileID).Order("consented_at DESC").Find(&consents).Error; err != nil {
+		r.Log.Error("[PrivacyNoticeRepository.FindConsentsByUserProfileID] " + err.Error())
+		return nil, errors.New("[PrivacyNoticeRepository.FindConsentsByUserProfileID] " + err.Error())
+	}
+
+	return consents, nil
+}
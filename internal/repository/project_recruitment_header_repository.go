@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
@@ -18,8 +19,7 @@ type IProjectRecruitmentHeaderRepository interface {
 	DeleteProjectRecruitmentHeader(id uuid.UUID) error
 	GetHighestDocumentNumberByDate(date string) (int, error)
 	FindAllByIDs(ids []uuid.UUID, status string) (*[]entity.ProjectRecruitmentHeader, error)
-	CountDaysToHireByTotalDays(daysRange string) (int, error)
-	CountAverageDaysToHireAll() (float64, error)
+	CompleteEndedProjectRecruitmentHeaders(today time.Time) (int64, error)
 }
 
 type ProjectRecruitmentHeaderRepository struct {
@@ -224,78 +224,14 @@ func (r *ProjectRecruitmentHeaderRepository) FindAllByIDs(ids []uuid.UUID, statu
 	return &projectRecruitmentHeaders, nil
 }
 
-func (r *ProjectRecruitmentHeaderRepository) CountDaysToHireByTotalDays(daysRange string) (int, error) {
-	var count int
-	var query string
-
-	switch daysRange {
-	case "> 30 Hari":
-		query = `
-		SELECT 
-    COUNT(*)
-FROM document_sendings ds 
-LEFT JOIN job_postings jp ON ds.job_posting_id = jp.id 
-LEFT JOIN mp_requests mr ON mr.id = jp.mp_request_id 
-WHERE ds.joined_date IS NOT NULL 
-  AND (ds.joined_date - mr.created_at) > INTERVAL '30 days'
-		`
-	case "21 - 30 Hari":
-		query = `
-			SELECT 
-    COUNT(*) AS total_days
-FROM document_sendings ds 
-LEFT JOIN job_postings jp ON ds.job_posting_id = jp.id 
-LEFT JOIN mp_requests mr ON mr.id = jp.mp_request_id 
-WHERE ds.joined_date IS NOT NULL 
-  AND (ds.joined_date - mr.created_at) BETWEEN INTERVAL '21 days' AND INTERVAL '30 days'
-		`
-	case "11 - 20 Hari":
-		query = `
-			SELECT 
-    COUNT(*) AS total_days
-FROM document_sendings ds 
-LEFT JOIN job_postings jp ON ds.job_posting_id = jp.id 
-LEFT JOIN mp_requests mr ON mr.id = jp.mp_request_id 
-WHERE ds.joined_date IS NOT NULL 
-  AND (ds.joined_date - mr.created_at) BETWEEN INTERVAL '11 days' AND INTERVAL '10 days'
-		`
-	case "1 - 10 Hari":
-		query = `
-			SELECT 
-    COUNT(*) AS total_days
-FROM document_sendings ds 
-LEFT JOIN job_postings jp ON ds.job_posting_id = jp.id 
-LEFT JOIN mp_requests mr ON mr.id = jp.mp_request_id 
-WHERE ds.joined_date IS NOT NULL 
-  AND (ds.joined_date - mr.created_at) BETWEEN INTERVAL '1 day' AND INTERVAL '30 days'
-		`
-	default:
-		r.Log.Errorf("[ProjectRecruitmentHeaderRepository.CountDaysToHireByTotalDays] invalid days range: %v", daysRange)
-		return 0, errors.New("invalid days range")
+// CompleteEndedProjectRecruitmentHeaders completes approved and in progress projects whose end date has passed
+func (r *ProjectRecruitmentHeaderRepository) CompleteEndedProjectRecruitmentHeaders(today time.Time) (int64, error) {
+	res := r.DB.Model(&entity.ProjectRecruitmentHeader{}).
+		Where("status IN ? AND end_date < ?", []entity.ProjectRecruitmentHeaderStatus{entity.PROJECT_RECRUITMENT_HEADER_STATUS_APPROVED, entity.PROJECT_RECRUITMENT_HEADER_STATUS_IN_PROGRESS}, today.Format("2006-01-02")).
+		Update("status", entity.PROJECT_RECRUITMENT_HEADER_STATUS_COMPLETED)
+	if res.Error != nil {
+		r.Log.Error("[ProjectRecruitmentHeaderRepository.CompleteEndedProjectRecruitmentHeaders] " + res.Error.Error())
+		return 0, errors.New("[ProjectRecruitmentHeaderRepository.CompleteEndedProjectRecruitmentHeaders] " + res.Error.Error())
 	}
-
-	err := r.DB.Raw(query).Scan(&count).Error
-	if err != nil {
-		r.Log.Errorf("[ProjectRecruitmentHeaderRepository.CountDaysToHireByTotalDays] error when querying count days to hire by total days: %v", err)
-		return 0, err
-	}
-
-	return count, nil
-}
-
-func (r *ProjectRecruitmentHeaderRepository) CountAverageDaysToHireAll() (float64, error) {
-	var avg float64
-	err := r.DB.Raw(`
-		SELECT 
-    AVG(EXTRACT(EPOCH FROM (ds.joined_date - mr.created_at)) / 86400)
-FROM document_sendings ds 
-LEFT JOIN job_postings jp ON ds.job_posting_id = jp.id 
-LEFT JOIN mp_requests mr ON mr.id = jp.mp_request_id 
-WHERE ds.joined_date IS NOT NULL
-	`).Scan(&avg).Error
-	if err != nil {
-		r.Log.Errorf("[ProjectRecruitmentHeaderRepository.CountAverageDaysToHireAll] error when querying average days to hire all: %v", err)
-		return 0, err
-	}
-	return avg, nil
+	return res.RowsAffected, nil
 }
@@ -16,6 +16,7 @@ type IQuestionResponseRepository interface {
 	DeleteQuestionResponse(id uuid.UUID) error
 	FindByID(id uuid.UUID) (*entity.QuestionResponse, error)
 	FindAllByQuestionID(questionID uuid.UUID) ([]entity.QuestionResponse, error)
+	FindAllByIDs(ids []uuid.UUID) ([]entity.QuestionResponse, error)
 	GetAllByKeys(keys map[string]interface{}) ([]entity.QuestionResponse, error)
 	DeleteByQuestionID(questionID uuid.UUID) error
 	DeleteByQuestionIDs(questionIDs []uuid.UUID) error
@@ -137,6 +138,16 @@ func (r *QuestionResponseRepository) FindAllByQuestionID(questionID uuid.UUID) (
 	return questions, nil
 }
 
+func (r *QuestionResponseRepository) FindAllByIDs(ids []uuid.UUID) ([]entity.QuestionResponse, error) {
+	var questions []entity.QuestionResponse
+
+	if err := r.DB.Where("id IN ?", ids).Find(&questions).Error; err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
 func (r *QuestionResponseRepository) GetAllByKeys(keys map[string]interface{}) ([]entity.QuestionResponse, error) {
 	var questions []entity.QuestionResponse
 
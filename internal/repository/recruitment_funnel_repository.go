@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// RecruitmentFunnelStage is a project recruitment line with the activity it runs
+type RecruitmentFunnelStage struct {
+	Order    int
+	Name     string
+	FormType string
+}
+
+type IRecruitmentFunnelRepository interface {
+	FindStages(projectRecruitmentHeaderID uuid.UUID) ([]RecruitmentFunnelStage, error)
+	FindApplicants(jobPostingIDs []uuid.UUID) ([]entity.Applicant, error)
+	FindStageHistories(jobPostingIDs []uuid.UUID) ([]entity.ApplicantStageHistory, error)
+}
+
+type RecruitmentFunnelRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewRecruitmentFunnelRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *RecruitmentFunnelRepository {
+	return &RecruitmentFunnelRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func RecruitmentFunnelRepositoryFactory(
+	log *logrus.Logger,
+) IRecruitmentFunnelRepository {
+	db := config.NewDatabase()
+	return NewRecruitmentFunnelRepository(log, db)
+}
+
+// FindStages returns the lines of a project in their order
+func (r *RecruitmentFunnelRepository) FindStages(projectRecruitmentHeaderID uuid.UUID) ([]RecruitmentFunnelStage, error) {
+	var stages []RecruitmentFunnelStage
+	if err := r.DB.Model(&entity.ProjectRecruitmentLine{}).
+		Joins("JOIN template_activity_lines ON template_activity_lines.id = project_recruitment_lines.template_activity_line_id").
+		Joins("LEFT JOIN template_questions ON template_questions.id = template_activity_lines.question_template_id").
+		Select(`project_recruitment_lines."order" AS "order", template_activity_lines.name AS name, template_questions.form_type AS form_type`).
+		Where("project_recruitment_lines.project_recruitment_header_id = ?", projectRecruitmentHeaderID).
+		Order(`project_recruitment_lines."order"`).
+		Scan(&stages).Error; err != nil {
+		r.Log.Error("[RecruitmentFunnelRepository.FindStages] " + err.Error())
+		return nil, errors.New("[RecruitmentFunnelRepository.FindStages] " + err.Error())
+	}
+
+	return stages, nil
+}
+
+func (r *RecruitmentFunnelRepository) FindApplicants(jobPostingIDs []uuid.UUID) ([]entity.Applicant, error) {
+	var applicants []entity.Applicant
+	if err := r.DB.Where("job_posting_id IN ?", jobPostingIDs).Find(&applicants).Error; err != nil {
+		r.Log.Error("[RecruitmentFunnelRepository.FindApplicants] " + err.Error())
+		return nil, errors.New("[RecruitmentFunnelRepository.FindApplicants] " + err.Error())
+	}
+
+	return applicants, nil
+}
+
+// FindStageHistories returns the stage histories of the applicants of the job postings, oldest
+// first per applicant
+func (r *RecruitmentFunnelRepository) FindStageHistories(jobPostingIDs []uuid.UUID) ([]entity.ApplicantStageHistory, error) {
+	var histories []entity.ApplicantStageHistory
+	if err := r.DB.Where("job_posting_id IN ?", jobPostingIDs).Order("applicant_id, entered_at").Find(&histories).Error; err != nil {
+		r.Log.Error("[RecruitmentFunnelRepository.FindStageHistories] " + err.Error())
+		return nil, errors.New("[RecruitmentFunnelRepository.FindStageHistories] " + err.Error())
+	}
+
+	return histories, nil
+}
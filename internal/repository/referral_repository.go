@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ReferralRow is a referral with the progress of the referred candidate: the application, the
+// stage the applicant is in and the date they joined, taken from their document sendings
+type ReferralRow struct {
+	ID                 uuid.UUID
+	ReferrerEmployeeID uuid.UUID
+	JobPostingID       uuid.UUID
+	JobPostingName     string
+	CandidateName      string
+	CandidateEmail     string
+	Status             entity.ReferralStatus
+	ApplicantID        *uuid.UUID
+	ApplicantStatus    *string
+	AppliedDate        *time.Time
+	StageName          *string
+	JoinedDate         *time.Time
+	CreatedAt          time.Time
+}
+
+type IReferralRepository interface {
+	CreateReferralLink(ent *entity.ReferralLink) (*entity.ReferralLink, error)
+	FindLinkByEmployeeAndJobPosting(employeeID, jobPostingID uuid.UUID) (*entity.ReferralLink, error)
+	FindLinkByCode(code string) (*entity.ReferralLink, error)
+	FindLinksByEmployeeID(employeeID uuid.UUID) ([]entity.ReferralLink, error)
+	CreateReferral(ent *entity.Referral) (*entity.Referral, error)
+	UpdateReferral(ent *entity.Referral) (*entity.Referral, error)
+	FindReferralByJobPostingAndEmail(jobPostingID uuid.UUID, email string) (*entity.Referral, error)
+	FindOpenReferral(jobPostingID, userProfileID uuid.UUID, email string) (*entity.Referral, error)
+	FindRowsByReferrer(employeeID uuid.UUID) ([]ReferralRow, error)
+	FindHiredRows(orgScope *OrganizationScope) ([]ReferralRow, error)
+}
+
+type ReferralRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewReferralRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *ReferralRepository {
+	return &ReferralRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func ReferralRepositoryFactory(
+	log *logrus.Logger,
+) IReferralRepository {
+	db := config.NewDatabase()
+	return NewReferralRepository(log, db)
+}
+
+func (r *ReferralRepository) CreateReferralLink(ent *entity.ReferralLink) (*entity.ReferralLink, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[ReferralRepository.CreateReferralLink] " + err.Error())
+		return nil, errors.New("[ReferralRepository.CreateReferralLink] " + err.Error())
+	}
+
+	return r.findLink(r.DB.Where("id = ?", ent.ID))
+}
+
+func (r *ReferralRepository) FindLinkByEmployeeAndJobPosting(employeeID, jobPostingID uuid.UUID) (*entity.ReferralLink, error) {
+	return r.findLink(r.DB.Where("employee_id = ? AND job_posting_id = ?", employeeID, jobPostingID))
+}
+
+func (r *ReferralRepository) FindLinkByCode(code string) (*entity.ReferralLink, error) {
+	return r.findLink(r.DB.Where("code = ?", code))
+}
+
+func (r *ReferralRepository) findLink(db *gorm.DB) (*entity.ReferralLink, error) {
+	var link entity.ReferralLink
+	if err := db.Preload("JobPosting").First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[ReferralRepository.findLink] " + err.Error())
+		return nil, errors.New("[ReferralRepository.findLink] " + err.Error())
+	}
+
+	return &link, nil
+}
+
+func (r *ReferralRepository) FindLinksByEmployeeID(employeeID uuid.UUID) ([]entity.ReferralLink, error) {
+	var links []entity.ReferralLink
+	if err := r.DB.Preload("JobPosting").Where("employee_id = ?", employeeID).Order("created_at DESC").Find(&links).Error; err != nil {
+		r.Log.Error("[ReferralRepository.FindLinksByEmployeeID] " + err.Error())
+		return nil, errors.New("[ReferralRepository.FindLinksByEmployeeID] " + err.Error())
+	}
+
+	return links, nil
+}
+
+func (r *ReferralRepository) CreateReferral(ent *entity.Referral) (*entity.Referral, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[ReferralRepository.CreateReferral] " + err.Error())
+		return nil, errors.New("[ReferralRepository.CreateReferral] " + err.Error())
+	}
+
+	return r.findReferral(r.DB.Where("id = ?", ent.ID))
+}
+
+func (r *ReferralRepository) UpdateReferral(ent *entity.Referral) (*entity.Referral, error) {
+	if err := r.DB.Model(&entity.Referral{}).Where("id = ?", ent.ID).Updates(ent).Error; err != nil {
+		r.Log.Error("[ReferralRepository.UpdateReferral] " + err.Error())
+		return nil, errors.New("[ReferralRepository.UpdateReferral] " + err.Error())
+	}
+
+	return r.findReferral(r.DB.Where("id = ?", ent.ID))
+}
+
+// FindReferralByJobPostingAndEmail finds whether a candidate was referred to a job posting already
+func (r *ReferralRepository) FindReferralByJobPostingAndEmail(jobPostingID uuid.UUID, email string) (*entity.Referral, error) {
+	return r.findReferral(r.DB.Where("job_posting_id = ? AND LOWER(candidate_email) = LOWER(?)", jobPostingID, email))
+}
+
+// FindOpenReferral finds the earliest referral of a candidate to a job posting not tied to an
+// application yet, by their profile or by the email the referrer gave
+func (r *ReferralRepository) FindOpenReferral(jobPostingID, userProfileID uuid.UUID, email string) (*entity.Referral, error) {
+	return r.findReferral(r.DB.
+		Where("job_posting_id = ? AND applicant_id IS NULL", jobPostingID).
+		Where("user_profile_id = ? OR (? <> '' AND LOWER(candidate_email) = LOWER(?))", userProfileID, email, email).
+		Order("created_at"))
+}
+
+func (r *ReferralRepository) findReferral(db *gorm.DB) (*entity.Referral, error) {
+	var referral entity.Referral
+	if err := db.Preload("ReferralLink").First(&referral).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.Log.Error("[ReferralRepository.findReferral] " + err.Error())
+		return nil, errors.New("[ReferralRepository.findReferral] " + err.Error())
+	}
+
+	return &referral, nil
+}
+
+func (r *ReferralRepository) FindRowsByReferrer(employeeID uuid.UUID) ([]ReferralRow, error) {
+	var rows []ReferralRow
+	if err := r.rows().
+		Where("referrals.referrer_employee_id = ?", employeeID).
+		Order("referrals.created_at DESC").
+		Scan(&rows).Error; err != nil {
+		r.Log.Error("[ReferralRepository.FindRowsByReferrer] " + err.Error())
+		return nil, errors.New("[ReferralRepository.FindRowsByReferrer] " + err.Error())
+	}
+
+	return rows, nil
+}
+
+// FindHiredRows finds the referrals whose candidate got hired, the ones joining first first
+func (r *ReferralRepository) FindHiredRows(orgScope *OrganizationScope) ([]ReferralRow, error) {
+	var rows []ReferralRow
+	if err := r.rows().
+		Where("applicants.status = ?", entity.APPLICANT_STATUS_HIRED).
+		Scopes(orgScope.ByJobPosting("referrals.job_posting_id")).
+		Order("document_sendings.joined_date NULLS LAST, referrals.created_at").
+		Scan(&rows).Error; err != nil {
+		r.Log.Error("[ReferralRepository.FindHiredRows] " + err.Error())
+		return nil, errors.New("[ReferralRepository.FindHiredRows] " + err.Error())
+	}
+
+	return rows, nil
+}
+
+// rows joins a referral with the application of the candidate and the project recruitment line
+// of the stage the applicant is in. An applicant may have several document sendings, the
+// latest joined date is the one that counts.
+func (r *ReferralRepository) rows() *gorm.DB {
+	return r.DB.Model(&entity.Referral{}).
+		Joins("JOIN job_postings ON job_postings.id = referrals.job_posting_id").
+		Joins("LEFT JOIN applicants ON applicants.id = referrals.applicant_id AND applicants.deleted_at IS NULL").
+		Joins(`LEFT JOIN project_recruitment_lines ON project_recruitment_lines.project_recruitment_header_id = job_postings.project_recruitment_header_id
+			AND project_recruitment_lines."order" = applicants."order" AND project_recruitment_lines.deleted_at IS NULL`).
+		Joins("LEFT JOIN template_activity_lines ON template_activity_lines.id = project_recruitment_lines.template_activity_line_id").
+		Joins(`LEFT JOIN (SELECT applicant_id, MAX(joined_date) AS joined_date FROM document_sendings
+			WHERE deleted_at IS NULL GROUP BY applicant_id) document_sendings ON document_sendings.applicant_id = referrals.applicant_id`).
+		Select(`referrals.id AS id, referrals.referrer_employee_id AS referrer_employee_id, referrals.job_posting_id AS job_posting_id,
+			job_postings.name AS job_posting_name, referrals.candidate_name AS candidate_name, referrals.candidate_email AS candidate_email,
+			referrals.status AS status, referrals.applicant_id AS applicant_id, applicants.status AS applicant_status,
+			applicants.applied_date AS applied_date, template_activity_lines.name AS stage_name,
+			document_sendings.joined_date AS joined_date, referrals.created_at AS created_at`)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IScheduleReminderRepository interface {
+	UpsertReminders(ents []entity.ScheduleReminder) (int64, error)
+	FindDueReminders(now time.Time, limit int) ([]entity.ScheduleReminder, error)
+	UpdateReminderFields(id uuid.UUID, fields map[string]interface{}) error
+	CancelPendingBySchedule(scheduleType entity.ScheduleReminderScheduleType, scheduleID uuid.UUID) (int64, error)
+}
+
+type ScheduleReminderRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewScheduleReminderRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *ScheduleReminderRepository {
+	return &ScheduleReminderRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func ScheduleReminderRepositoryFactory(
+	log *logrus.Logger,
+) IScheduleReminderRepository {
+	db := config.NewDatabase()
+	return NewScheduleReminderRepository(log, db)
+}
+
+// UpsertReminders inserts the planned reminders that do not exist yet. A reminder cancelled
+// earlier with the same key (the schedule was saved again without moving the slot) is
+// planned again, sent and failed reminders are left alone.
+func (r *ScheduleReminderRepository) UpsertReminders(ents []entity.ScheduleReminder) (int64, error) {
+	if len(ents) == 0 {
+		return 0, nil
+	}
+
+	res := r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "dedup_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":     entity.SCHEDULE_REMINDER_STATUS_PENDING,
+			"attempts":   0,
+			"last_error": nil,
+			"updated_at": time.Now(),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: "schedule_reminders", Name: "status"}, Value: entity.SCHEDULE_REMINDER_STATUS_CANCELLED},
+		}},
+	}).CreateInBatches(&ents, 100)
+	if res.Error != nil {
+		r.Log.Error("[ScheduleReminderRepository.UpsertReminders] " + res.Error.Error())
+		return 0, errors.New("[ScheduleReminderRepository.UpsertReminders] " + res.Error.Error())
+	}
+
+	return res.RowsAffected, nil
+}
+
+func (r *ScheduleReminderRepository) FindDueReminders(now time.Time, limit int) ([]entity.ScheduleReminder, error) {
+	var res []entity.ScheduleReminder
+
+	if err := r.DB.Where("status = ? AND remind_at <= ?", entity.SCHEDULE_REMINDER_STATUS_PENDING, now).
+		Order("remind_at ASC").Limit(limit).Find(&res).Error; err != nil {
+		r.Log.Error("[ScheduleReminderRepository.FindDueReminders] " + err.Error())
+		return nil, errors.New("[ScheduleReminderRepository.FindDueReminders] " + err.Error())
+	}
+
+	return res, nil
+}
+
+func (r *ScheduleReminderRepository) UpdateReminderFields(id uuid.UUID, fields map[string]interface{}) error {
+	if err := r.DB.Model(&entity.ScheduleReminder{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		r.Log.Error("[ScheduleReminderRepository.UpdateReminderFields] " + err.Error())
+		return errors.New("[ScheduleReminderRepository.UpdateReminderFields] " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *ScheduleReminderRepository) CancelPendingBySchedule(scheduleType entity.ScheduleReminderScheduleType, scheduleID uuid.UUID) (int64, error) {
+	res := r.DB.Model(&entity.ScheduleReminder{}).
+		Where("schedule_type = ? AND schedule_id = ? AND status = ?", scheduleType, scheduleID, entity.SCHEDULE_REMINDER_STATUS_PENDING).
+		Update("status", entity.SCHEDULE_REMINDER_STATUS_CANCELLED)
+	if res.Error != nil {
+		r.Log.Error("[ScheduleReminderRepository.CancelPendingBySchedule] " + res.Error.Error())
+		return 0, errors.New("[ScheduleReminderRepository.CancelPendingBySchedule] " + res.Error.Error())
+	}
+
+	return res.RowsAffected, nil
+}
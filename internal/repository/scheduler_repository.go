@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ISchedulerRepository interface {
+	AcquireLock(name string, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(name string, owner string) error
+	CreateJobRun(ent *entity.SchedulerJobRun) (*entity.SchedulerJobRun, error)
+	UpdateJobRunFields(id uuid.UUID, fields map[string]interface{}) error
+	FindAllJobRunsPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.SchedulerJobRun, int64, error)
+	FindLastJobRuns() ([]entity.SchedulerJobRun, error)
+	DeleteJobRunsBefore(before time.Time) (int64, error)
+}
+
+type SchedulerRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewSchedulerRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *SchedulerRepository {
+	return &SchedulerRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func SchedulerRepositoryFactory(
+	log *logrus.Logger,
+) ISchedulerRepository {
+	db := config.NewDatabase()
+	return NewSchedulerRepository(log, db)
+}
+
+// AcquireLock takes the lock when it is free or expired, or renews it when owner already holds it.
+// The check and the write are one statement, so two replicas can never both get the lock.
+func (r *SchedulerRepository) AcquireLock(name string, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	res := r.DB.Exec(`
+		INSERT INTO scheduler_locks (name, owner, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE
+		SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at
+		WHERE scheduler_locks.owner = EXCLUDED.owner OR scheduler_locks.expires_at < ?
+	`, name, owner, now.Add(ttl), now, now, now)
+	if res.Error != nil {
+		r.Log.Error("[SchedulerRepository.AcquireLock] " + res.Error.Error())
+		return false, errors.New("[SchedulerRepository.AcquireLock] " + res.Error.Error())
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// ReleaseLock frees the lock if owner still holds it
+func (r *SchedulerRepository) ReleaseLock(name string, owner string) error {
+	if err := r.DB.Where("name = ? AND owner = ?", name, owner).Delete(&entity.SchedulerLock{}).Error; err != nil {
+		r.Log.Error("[SchedulerRepository.ReleaseLock] " + err.Error())
+		return errors.New("[SchedulerRepository.ReleaseLock] " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *SchedulerRepository) CreateJobRun(ent *entity.SchedulerJobRun) (*entity.SchedulerJobRun, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[SchedulerRepository.CreateJobRun] " + err.Error())
+		return nil, errors.New("[SchedulerRepository.CreateJobRun] " + err.Error())
+	}
+
+	return ent, nil
+}
+
+func (r *SchedulerRepository) UpdateJobRunFields(id uuid.UUID, fields map[string]interface{}) error {
+	if err := r.DB.Model(&entity.SchedulerJobRun{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		r.Log.Error("[SchedulerRepository.UpdateJobRunFields] " + err.Error())
+		return errors.New("[SchedulerRepository.UpdateJobRunFields] " + err.Error())
+	}
+
+	return nil
+}
+
+func (r *SchedulerRepository) FindAllJobRunsPaginated(page, pageSize int, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.SchedulerJobRun, int64, error) {
+	var res []entity.SchedulerJobRun
+	var total int64
+
+	query := r.DB.Model(&entity.SchedulerJobRun{})
+
+	if jobName, ok := filter["job_name"].(string); ok && jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	if status, ok := filter["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	for key, value := range sort {
+		query = query.Order(key + " " + value.(string))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		r.Log.Error("[SchedulerRepository.FindAllJobRunsPaginated] " + err.Error())
+		return nil, 0, errors.New("[SchedulerRepository.FindAllJobRunsPaginated] " + err.Error())
+	}
+
+	if err := query.Offset((page - 1) * pageSize).Limit(pageSize).Find(&res).Error; err != nil {
+		r.Log.Error("[SchedulerRepository.FindAllJobRunsPaginated] " + err.Error())
+		return nil, 0, errors.New("[SchedulerRepository.FindAllJobRunsPaginated] " + err.Error())
+	}
+
+	return &res, total, nil
+}
+
+// FindLastJobRuns returns the latest run of every job
+func (r *SchedulerRepository) FindLastJobRuns() ([]entity.SchedulerJobRun, error) {
+	var res []entity.SchedulerJobRun
+
+	if err := r.DB.Where("id IN (?)", r.DB.Raw(`
+		SELECT DISTINCT ON (job_name) id FROM scheduler_job_runs
+		WHERE deleted_at IS NULL
+		ORDER BY job_name, started_at DESC
+	`)).Find(&res).Error; err != nil {
+		r.Log.Error("[SchedulerRepository.FindLastJobRuns] " + err.Error())
+		return nil, errors.New("[SchedulerRepository.FindLastJobRuns] " + err.Error())
+	}
+
+	return res, nil
+}
+
+func (r *SchedulerRepository) DeleteJobRunsBefore(before time.Time) (int64, error) {
+	res := r.DB.Unscoped().Where("started_at < ?", before).Delete(&entity.SchedulerJobRun{})
+	if res.Error != nil {
+		r.Log.Error("[SchedulerRepository.DeleteJobRunsBefore] " + res.Error.Error())
+		return 0, errors.New("[SchedulerRepository.DeleteJobRunsBefore] " + res.Error.Error())
+	}
+
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SlaStageItem is an applicant in progress in a stage that has a max days in stage SLA.
+// EnteredAt is when the applicant entered the stage, or when it was last updated for
+// applicants that moved before stages were recorded.
+type SlaStageItem struct {
+	ApplicantID              uuid.UUID
+	ApplicantName            string
+	JobPostingID             uuid.UUID
+	JobPostingName           string
+	ProjectRecruitmentLineID uuid.UUID
+	Order                    int
+	StageName                string
+	MaxDaysInStage           int
+	EndDate                  *time.Time
+	EnteredAt                time.Time
+}
+
+// SlaResultItem is an assessor of an interview or FGD with results still to fill, in a stage
+// that has a max days to fill result SLA
+type SlaResultItem struct {
+	ScheduleType             entity.ScheduleReminderScheduleType
+	ScheduleID               uuid.UUID
+	ScheduleName             string
+	ScheduleDate             time.Time
+	JobPostingID             uuid.UUID
+	JobPostingName           string
+	ProjectRecruitmentLineID uuid.UUID
+	StageName                string
+	MaxDaysToFillResult      int
+	AssessorID               uuid.UUID
+	AssessorEmployeeID       uuid.UUID
+	PicEmployeeID            *uuid.UUID
+	PendingResults           int
+}
+
+type ISlaRepository interface {
+	FindStageItems(scope *OrganizationScope) ([]SlaStageItem, error)
+	FindResultItems(scope *OrganizationScope) ([]SlaResultItem, error)
+	FindPicEmployeeIDs(projectRecruitmentLineIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+	FindAlertsByDedupKeys(keys []string) ([]entity.SlaAlert, error)
+	SaveAlerts(ents []entity.SlaAlert) error
+}
+
+type SlaRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewSlaRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *SlaRepository {
+	return &SlaRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func SlaRepositoryFactory(
+	log *logrus.Logger,
+) ISlaRepository {
+	db := config.NewDatabase()
+	return NewSlaRepository(log, db)
+}
+
+func (r *SlaRepository) FindStageItems(scope *OrganizationScope) ([]SlaStageItem, error) {
+	var items []SlaStageItem
+	if err := r.DB.Model(&entity.Applicant{}).
+		Joins("JOIN job_postings ON job_postings.id = applicants.job_posting_id AND job_postings.deleted_at IS NULL").
+		Joins(`JOIN project_recruitment_lines ON project_recruitment_lines.project_recruitment_header_id = job_postings.project_recruitment_header_id AND project_recruitment_lines."order" = applicants."order" AND project_recruitment_lines.deleted_at IS NULL`).
+		Joins("JOIN template_activity_lines ON template_activity_lines.id = project_recruitment_lines.template_activity_line_id").
+		Joins("LEFT JOIN user_profiles ON user_profiles.id = applicants.user_profile_id").
+		Select(`applicants.id AS applicant_id, user_profiles.name AS applicant_name,
+			job_postings.id AS job_posting_id, job_postings.name AS job_posting_name,
+			project_recruitment_lines.id AS project_recruitment_line_id, applicants."order" AS "order",
+			template_activity_lines.name AS stage_name, template_activity_lines.max_days_in_stage AS max_days_in_stage,
+			project_recruitment_lines.end_date AS end_date,
+			COALESCE((SELECT MAX(h.entered_at) FROM applicant_stage_histories h
+				WHERE h.applicant_id = applicants.id AND h."order" = applicants."order" AND h.deleted_at IS NULL), applicants.updated_at) AS entered_at`).
+		Where("applicants.status NOT IN ?", []entity.ApplicantStatus{entity.APPLICANT_STATUS_REJECTED, entity.APPLICANT_STATUS_HIRED}).
+		Where("template_activity_lines.max_days_in_stage > 0").
+		Scopes(scope.ByOrganization("job_postings.for_organization_id")).
+		Scan(&items).Error; err != nil {
+		r.Log.Error("[SlaRepository.FindStageItems] " + err.Error())
+		return nil, errors.New("[SlaRepository.FindStageItems] " + err.Error())
+	}
+
+	return items, nil
+}
+
+// slaResultTables names the tables of a schedule type that has assessors filling results
+type slaResultTables struct {
+	ScheduleType entity.ScheduleReminderScheduleType
+	Schedules    string
+	Assessors    string
+	Applicants   string
+	Results      string
+	ScheduleKey  string
+	ApplicantKey string
+	AssessorKey  string
+	DraftStatus  string
+}
+
+var slaResultSchedules = []slaResultTables{
+	{
+		ScheduleType: entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_INTERVIEW,
+		Schedules:    "interviews",
+		Assessors:    "interview_assessors",
+		Applicants:   "interview_applicants",
+		Results:      "interview_results",
+		ScheduleKey:  "interview_id",
+		ApplicantKey: "interview_applicant_id",
+		AssessorKey:  "interview_assessor_id",
+		DraftStatus:  string(entity.INTERVIEW_STATUS_DRAFT),
+	},
+	{
+		ScheduleType: entity.SCHEDULE_REMINDER_SCHEDULE_TYPE_FGD,
+		Schedules:    "fgd_schedules",
+		Assessors:    "fgd_assessors",
+		Applicants:   "fgd_applicants",
+		Results:      "fgd_results",
+		ScheduleKey:  "fgd_schedule_id",
+		ApplicantKey: "fgd_applicant_id",
+		AssessorKey:  "fgd_assessor_id",
+		DraftStatus:  string(entity.FGD_SCHEDULE_STATUS_DRAFT),
+	},
+}
+
+// FindResultItems returns, per interview and FGD assessor, how many applicants of a schedule
+// still have no result from that assessor
+func (r *SlaRepository) FindResultItems(scope *OrganizationScope) ([]SlaResultItem, error) {
+	var items []SlaResultItem
+	for _, t := range slaResultSchedules {
+		var rows []SlaResultItem
+		if err := r.DB.Table(t.Schedules).
+			Joins(fmt.Sprintf("JOIN %[1]s ON %[1]s.%[2]s = %[3]s.id AND %[1]s.deleted_at IS NULL", t.Assessors, t.ScheduleKey, t.Schedules)).
+			Joins(fmt.Sprintf("JOIN %[1]s ON %[1]s.%[2]s = %[3]s.id AND %[1]s.deleted_at IS NULL", t.Applicants, t.ScheduleKey, t.Schedules)).
+			Joins(fmt.Sprintf("JOIN project_recruitment_lines ON project_recruitment_lines.id = %s.project_recruitment_line_id", t.Schedules)).
+			Joins("JOIN template_activity_lines ON template_activity_lines.id = project_recruitment_lines.template_activity_line_id").
+			Joins(fmt.Sprintf("JOIN job_postings ON job_postings.id = %s.job_posting_id", t.Schedules)).
+			Joins(fmt.Sprintf("LEFT JOIN project_pics ON project_pics.id = %s.project_pic_id AND project_pics.deleted_at IS NULL", t.Schedules)).
+			Select(fmt.Sprintf(`'%[1]s' AS schedule_type, %[2]s.id AS schedule_id, %[2]s.name AS schedule_name, %[2]s.schedule_date AS schedule_date,
+				job_postings.id AS job_posting_id, job_postings.name AS job_posting_name,
+				project_recruitment_lines.id AS project_recruitment_line_id, template_activity_lines.name AS stage_name,
+				template_activity_lines.max_days_to_fill_result AS max_days_to_fill_result,
+				%[3]s.id AS assessor_id, %[3]s.employee_id AS assessor_employee_id, project_pics.employee_id AS pic_employee_id,
+				COUNT(%[4]s.id) AS pending_results`, t.ScheduleType, t.Schedules, t.Assessors, t.Applicants)).
+			Where(fmt.Sprintf("%s.deleted_at IS NULL AND %s.status <> ?", t.Schedules, t.Schedules), t.DraftStatus).
+			Where("template_activity_lines.max_days_to_fill_result > 0").
+			Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %[1]s WHERE %[1]s.%[2]s = %[3]s.id AND %[1]s.%[4]s = %[5]s.id AND %[1]s.deleted_at IS NULL)",
+				t.Results, t.ApplicantKey, t.Applicants, t.AssessorKey, t.Assessors)).
+			Scopes(scope.ByOrganization("job_postings.for_organization_id")).
+			Group(fmt.Sprintf(`%[1]s.id, %[1]s.name, %[1]s.schedule_date, job_postings.id, job_postings.name,
+				project_recruitment_lines.id, template_activity_lines.name, template_activity_lines.max_days_to_fill_result,
+				%[2]s.id, %[2]s.employee_id, project_pics.employee_id`, t.Schedules, t.Assessors)).
+			Scan(&rows).Error; err != nil {
+			r.Log.Error("[SlaRepository.FindResultItems] " + err.Error())
+			return nil, errors.New("[SlaRepository.FindResultItems] " + err.Error())
+		}
+		items = append(items, rows...)
+	}
+
+	return items, nil
+}
+
+// FindPicEmployeeIDs returns the employees in charge of each project recruitment line
+func (r *SlaRepository) FindPicEmployeeIDs(projectRecruitmentLineIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	res := make(map[uuid.UUID][]uuid.UUID)
+	if len(projectRecruitmentLineIDs) == 0 {
+		return res, nil
+	}
+
+	var pics []entity.ProjectPic
+	if err := r.DB.Where("project_recruitment_line_id IN ? AND employee_id IS NOT NULL", projectRecruitmentLineIDs).Find(&pics).Error; err != nil {
+		r.Log.Error("[SlaRepository.FindPicEmployeeIDs] " + err.Error())
+		return nil, errors.New("[SlaRepository.FindPicEmployeeIDs] " + err.Error())
+	}
+	for _, pic := range pics {
+		res[pic.ProjectRecruitmentLineID] = append(res[pic.ProjectRecruitmentLineID], *pic.EmployeeID)
+	}
+
+	return res, nil
+}
+
+func (r *SlaRepository) FindAlertsByDedupKeys(keys []string) ([]entity.SlaAlert, error) {
+	var alerts []entity.SlaAlert
+	if len(keys) == 0 {
+		return alerts, nil
+	}
+
+	if err := r.DB.Where("dedup_key IN ?", keys).Find(&alerts).Error; err != nil {
+		r.Log.Error("[SlaRepository.FindAlertsByDedupKeys] " + err.Error())
+		return nil, errors.New("[SlaRepository.FindAlertsByDedupKeys] " + err.Error())
+	}
+
+	return alerts, nil
+}
+
+// SaveAlerts inserts the alerts, or records the new outcome of the ones sent or tried before
+func (r *SlaRepository) SaveAlerts(ents []entity.SlaAlert) error {
+	if len(ents) == 0 {
+		return nil
+	}
+
+	if err := r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedup_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "attempts", "sent_at", "last_error", "due_at", "updated_at"}),
+	}).CreateInBatches(&ents, 100).Error; err != nil {
+		r.Log.Error("[SlaRepository.SaveAlerts] " + err.Error())
+		return errors.New("[SlaRepository.SaveAlerts] " + err.Error())
+	}
+
+	return nil
+}
@@ -74,6 +74,15 @@ func (r *TemplateActivityLineRepository) UpdateTemplateActivityLine(ent *entity.
 		return nil, err
 	}
 
+	// Updates skips zero values, a 0 SLA has to be written on its own to clear it
+	if err := tx.Model(&entity.TemplateActivityLine{}).Where("id = ?", ent.ID).Updates(map[string]interface{}{
+		"max_days_in_stage":       ent.MaxDaysInStage,
+		"max_days_to_fill_result": ent.MaxDaysToFillResult,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
@@ -11,7 +12,7 @@ import (
 )
 
 type ITestScheduleHeaderRepository interface {
-	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.TestScheduleHeader, int64, error)
+	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.TestScheduleHeader, int64, error)
 	CreateTestScheduleHeader(tsh *entity.TestScheduleHeader) (*entity.TestScheduleHeader, error)
 	FindByID(id uuid.UUID) (*entity.TestScheduleHeader, error)
 	FindByIDForMyself(id uuid.UUID, userProfile uuid.UUID, jobPostingID uuid.UUID) (*entity.TestScheduleHeader, error)
@@ -21,6 +22,8 @@ type ITestScheduleHeaderRepository interface {
 	GetHighestDocumentNumberByDate(date string) (int, error)
 	FindByKeys(keys map[string]interface{}) (*entity.TestScheduleHeader, error)
 	FindAllByKeys(keys map[string]interface{}) (*[]entity.TestScheduleHeader, error)
+	CompleteEndedTestSchedules(now time.Time) (int64, error)
+	FindAllInProgressByScheduleDateRange(from, to time.Time) (*[]entity.TestScheduleHeader, error)
 }
 
 type TestScheduleHeaderRepository struct {
@@ -40,11 +43,11 @@ func TestScheduleHeaderRepositoryFactory(log *logrus.Logger) ITestScheduleHeader
 	return NewTestScheduleHeaderRepository(log, db)
 }
 
-func (r *TestScheduleHeaderRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}) (*[]entity.TestScheduleHeader, int64, error) {
+func (r *TestScheduleHeaderRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, orgScope *OrganizationScope) (*[]entity.TestScheduleHeader, int64, error) {
 	var testScheduleHeaders []entity.TestScheduleHeader
 	var total int64
 
-	query := r.DB.Preload("JobPosting").Preload("ProjectRecruitmentHeader").Preload("TestType").Preload("ProjectPic").Preload("TestApplicants.UserProfile")
+	query := r.DB.Preload("JobPosting").Preload("ProjectRecruitmentHeader").Preload("TestType").Preload("ProjectPic").Preload("TestApplicants.UserProfile").Scopes(orgScope.ByJobPosting("job_posting_id"))
 
 	if search != "" {
 		query = query.Where("document_number ILIKE ?", "%"+search+"%")
@@ -227,3 +230,27 @@ func (r *TestScheduleHeaderRepository) FindByIDForAnswer(id, jobPostingID uuid.U
 	}
 	return &tsh, nil
 }
+
+// CompleteEndedTestSchedules moves in progress schedules whose last slot has ended to COMPLETED, now is the local wall clock
+func (r *TestScheduleHeaderRepository) CompleteEndedTestSchedules(now time.Time) (int64, error) {
+	res := r.DB.Model(&entity.TestScheduleHeader{}).
+		Where("status = ? AND (end_date + end_time) < ?", entity.TEST_SCHEDULE_STATUS_IN_PROGRESS, now.Format("2006-01-02 15:04:05")).
+		Update("status", entity.TEST_SCHEDULE_STATUS_COMPLETED)
+	if res.Error != nil {
+		r.Log.Error("[TestScheduleHeaderRepository.CompleteEndedTestSchedules] " + res.Error.Error())
+		return 0, errors.New("[TestScheduleHeaderRepository.CompleteEndedTestSchedules] " + res.Error.Error())
+	}
+	return res.RowsAffected, nil
+}
+
+// FindAllInProgressByScheduleDateRange returns the in progress schedules held between from and to (both days included) with their participants
+func (r *TestScheduleHeaderRepository) FindAllInProgressByScheduleDateRange(from, to time.Time) (*[]entity.TestScheduleHeader, error) {
+	var entities []entity.TestScheduleHeader
+	if err := r.DB.Preload("JobPosting").Preload("TestApplicants.UserProfile").
+		Where("status = ? AND schedule_date BETWEEN ? AND ?", entity.TEST_SCHEDULE_STATUS_IN_PROGRESS, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&entities).Error; err != nil {
+		r.Log.Error("[TestScheduleHeaderRepository.FindAllInProgressByScheduleDateRange] " + err.Error())
+		return nil, errors.New("[TestScheduleHeaderRepository.FindAllInProgressByScheduleDateRange] " + err.Error())
+	}
+	return &entities, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ITokenRevocationRepository interface {
+	CreateTokenRevocation(ent *entity.TokenRevocation) (*entity.TokenRevocation, error)
+	FindAllActive(now time.Time) ([]entity.TokenRevocation, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type TokenRevocationRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewTokenRevocationRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *TokenRevocationRepository {
+	return &TokenRevocationRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func TokenRevocationRepositoryFactory(
+	log *logrus.Logger,
+) ITokenRevocationRepository {
+	db := config.NewDatabase()
+	return NewTokenRevocationRepository(log, db)
+}
+
+func (r *TokenRevocationRepository) CreateTokenRevocation(ent *entity.TokenRevocation) (*entity.TokenRevocation, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[TokenRevocationRepository.CreateTokenRevocation] " + err.Error())
+		return nil, errors.New("[TokenRevocationRepository.CreateTokenRevocation] " + err.Error())
+	}
+
+	return ent, nil
+}
+
+func (r *TokenRevocationRepository) FindAllActive(now time.Time) ([]entity.TokenRevocation, error) {
+	var revocations []entity.TokenRevocation
+
+	if err := r.DB.Where("expires_at > ?", now).Find(&revocations).Error; err != nil {
+		r.Log.Error("[TokenRevocationRepository.FindAllActive] " + err.Error())
+		return nil, errors.New("[TokenRevocationRepository.FindAllActive] " + err.Error())
+	}
+
+	return revocations, nil
+}
+
+func (r *TokenRevocationRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.DB.Unscoped().Where("expires_at <= ?", now).Delete(&entity.TokenRevocation{})
+	if result.Error != nil {
+		r.Log.Error("[TokenRevocationRepository.DeleteExpired] " + result.Error.Error())
+		return 0, errors.New("[TokenRevocationRepository.DeleteExpired] " + result.Error.Error())
+	}
+
+	return result.RowsAffected, nil
+}
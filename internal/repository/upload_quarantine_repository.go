@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IUploadQuarantineRepository interface {
+	CreateUploadQuarantine(ent *entity.UploadQuarantine) (*entity.UploadQuarantine, error)
+}
+
+type UploadQuarantineRepository struct {
+	Log *logrus.Logger
+	DB  *gorm.DB
+}
+
+func NewUploadQuarantineRepository(
+	log *logrus.Logger,
+	db *gorm.DB,
+) *UploadQuarantineRepository {
+	return &UploadQuarantineRepository{
+		Log: log,
+		DB:  db,
+	}
+}
+
+func UploadQuarantineRepositoryFactory(
+	log *logrus.Logger,
+) IUploadQuarantineRepository {
+	db := config.NewDatabase()
+	return NewUploadQuarantineRepository(log, db)
+}
+
+func (r *UploadQuarantineRepository) CreateUploadQuarantine(ent *entity.UploadQuarantine) (*entity.UploadQuarantine, error) {
+	if err := r.DB.Create(ent).Error; err != nil {
+		r.Log.Error("[UploadQuarantineRepository.CreateUploadQuarantine] " + err.Error())
+		return nil, errors.New("[UploadQuarantineRepository.CreateUploadQuarantine] " + err.Error())
+	}
+
+	return ent, nil
+}
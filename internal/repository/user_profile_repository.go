@@ -2,6 +2,8 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"unicode"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/entity"
@@ -15,8 +17,10 @@ type IUserProfileRepository interface {
 	UpdateUserProfile(ent *entity.UserProfile) (*entity.UserProfile, error)
 	FindByID(id uuid.UUID) (*entity.UserProfile, error)
 	FindByUserID(userID uuid.UUID) (*entity.UserProfile, error)
+	FindByEmail(email string) (*entity.UserProfile, error)
 	FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.UserProfile, int64, error)
 	DeleteUserProfile(id uuid.UUID) error
+	FindAllHired() (*[]entity.UserProfile, error)
 }
 
 type UserProfileRepository struct {
@@ -42,6 +46,11 @@ func UserProfileRepositoryFactory(
 }
 
 func (r *UserProfileRepository) CreateUserProfile(ent *entity.UserProfile) (*entity.UserProfile, error) {
+	if err := SetKtpNumberBidx(ent); err != nil {
+		r.Log.Error("[UserProfileRepository.CreateUserProfile] " + err.Error())
+		return nil, err
+	}
+
 	tx := r.DB.Begin()
 	if tx.Error != nil {
 		r.Log.Error("[UserProfileRepository.CreateUserProfile] " + tx.Error.Error())
@@ -83,6 +92,11 @@ func (r *UserProfileRepository) FindByID(id uuid.UUID) (*entity.UserProfile, err
 }
 
 func (r *UserProfileRepository) UpdateUserProfile(ent *entity.UserProfile) (*entity.UserProfile, error) {
+	if err := SetKtpNumberBidx(ent); err != nil {
+		r.Log.Error("[UserProfileRepository.UpdateUserProfile] " + err.Error())
+		return nil, err
+	}
+
 	tx := r.DB.Begin()
 	if tx.Error != nil {
 		r.Log.Error("[UserProfileRepository.UpdateUserProfile] " + tx.Error.Error())
@@ -123,6 +137,20 @@ func (r *UserProfileRepository) FindByUserID(userID uuid.UUID) (*entity.UserProf
 	return &ent, nil
 }
 
+func (r *UserProfileRepository) FindByEmail(email string) (*entity.UserProfile, error) {
+	var ent entity.UserProfile
+	if err := r.DB.Where("LOWER(email) = LOWER(?)", email).First(&ent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else {
+			r.Log.Error("[UserProfileRepository.FindByEmail] " + err.Error())
+			return nil, err
+		}
+	}
+
+	return &ent, nil
+}
+
 func (r *UserProfileRepository) FindAllPaginated(page, pageSize int, search string, sort map[string]interface{}, filter map[string]interface{}) (*[]entity.UserProfile, int64, error) {
 	var userProfiles []entity.UserProfile
 	var total int64
@@ -182,3 +210,34 @@ func (r *UserProfileRepository) DeleteUserProfile(id uuid.UUID) error {
 
 	return nil
 }
+
+func (r *UserProfileRepository) FindAllHired() (*[]entity.UserProfile, error) {
+	var userProfiles []entity.UserProfile
+
+	hiredApplicants := r.DB.Model(&entity.Applicant{}).Select("user_profile_id").Where("status = ?", entity.APPLICANT_STATUS_HIRED)
+	if err := r.DB.Preload("Applicants").Where("id IN (?)", hiredApplicants).Find(&userProfiles).Error; err != nil {
+		r.Log.Error("[UserProfileRepository.FindAllHired] " + err.Error())
+		return nil, errors.New("[UserProfileRepository.FindAllHired] " + err.Error())
+	}
+
+	return &userProfiles, nil
+}
+
+// SetKtpNumberBidx fills the blind index of the encrypted KTP number. Only the digits are hashed
+// so the same number typed with spaces or dashes still matches.
+func SetKtpNumberBidx(ent *entity.UserProfile) error {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, ent.KtpNumber)
+
+	bidx, err := config.NewFieldEncryptor().BlindIndex(digits)
+	if err != nil {
+		return err
+	}
+
+	ent.KtpNumberBidx = bidx
+	return nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+type MigrationReport struct {
+	Copied  int   `json:"copied"`
+	Skipped int   `json:"skipped"`
+	Deleted int   `json:"deleted"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// Migrate copies every file of the local storage to target under the same key, so the paths
+// saved in the database stay valid. Files already in target are skipped, which makes it safe to
+// run again after a failure. With deleteSource the local file is removed once it is in target.
+func Migrate(source *LocalStorage, target IStorage, deleteSource bool, dryRun bool, log *logrus.Logger) (*MigrationReport, error) {
+	report := &MigrationReport{}
+
+	err := source.Walk(func(key string, size int64) error {
+		exists, err := target.Exists(key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		if exists {
+			report.Skipped++
+		} else {
+			if !dryRun {
+				if err := copyFile(source, target, key, size); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+			}
+			report.Copied++
+			report.Bytes += size
+			log.Infof("[Storage.Migrate] copied %s (%d bytes)", key, size)
+		}
+
+		if deleteSource && !dryRun {
+			if err := source.Delete(key); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			report.Deleted++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func copyFile(source IStorage, target IStorage, key string, size int64) error {
+	body, err := source.Get(key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return target.Put(key, body, size, ContentType(key))
+}
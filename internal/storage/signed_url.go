@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrStorageSignatureInvalid = errors.New("invalid download link")
+	ErrStorageURLExpired       = errors.New("download link has expired")
+	ErrStorageSigningKeyUnset  = errors.New("storage.signed_url.key or app.secret has to be set to sign download links")
+)
+
+// URL returns the download URL of a stored path. Files under storage.public_prefixes (job posting
+// logos and posters by default) are linked as they are, anything else gets a signed link valid
+// for storage.signed_url.ttl seconds.
+func URL(viper *viper.Viper, filePath string) string {
+	return SignedURL(viper, filePath, secondsOr(viper, "storage.signed_url.ttl", 3600))
+}
+
+// MailURL is URL with the longer storage.signed_url.mail_ttl, for links sent by email
+func MailURL(viper *viper.Viper, filePath string) string {
+	return SignedURL(viper, filePath, secondsOr(viper, "storage.signed_url.mail_ttl", 7*24*3600))
+}
+
+func SignedURL(viper *viper.Viper, filePath string, ttl time.Duration) string {
+	if filePath == "" || strings.HasPrefix(filePath, "http://") || strings.HasPrefix(filePath, "https://") {
+		return filePath
+	}
+
+	appURL := strings.TrimRight(viper.GetString("app.url"), "/")
+	key, err := NormalizeKey(filePath)
+	if err != nil {
+		// not a stored file, left as it was
+		return appURL + "/" + strings.TrimLeft(filePath, "/")
+	}
+
+	link := appURL + (&url.URL{Path: "/" + key}).EscapedPath()
+	if IsQuarantined(key) {
+		// never signed, the link is refused
+		return link
+	}
+	if IsPublic(viper, key) {
+		return link
+	}
+
+	expires := time.Now().Add(ttl).Unix()
+	signature, err := signKey(viper, key, expires)
+	if err != nil {
+		// left unsigned, the link is refused
+		return link
+	}
+	return link + "?" + url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {signature},
+	}.Encode()
+}
+
+// VerifySignedURL checks the expires and signature query parameters of a download link
+func VerifySignedURL(viper *viper.Viper, key string, expires string, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || signature == "" || IsQuarantined(key) {
+		return ErrStorageSignatureInvalid
+	}
+
+	expected, err := signKey(viper, key, expiresAt)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrStorageSignatureInvalid
+	}
+	if time.Now().Unix() > expiresAt {
+		return ErrStorageURLExpired
+	}
+
+	return nil
+}
+
+// IsQuarantined reports whether the key is an upload the malware scanner rejected. Those files
+// are kept for review only and are never signed or served.
+func IsQuarantined(key string) bool {
+	return strings.HasPrefix(key, QUARANTINE_KEY_PREFIX)
+}
+
+// IsPublic reports whether the file can be downloaded without a signed link
+func IsPublic(viper *viper.Viper, key string) bool {
+	if IsQuarantined(key) {
+		return false
+	}
+
+	prefixes := []string{"storage/job_posting/"}
+	if viper.IsSet("storage.public_prefixes") {
+		prefixes = viper.GetStringSlice("storage.public_prefixes")
+	}
+
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSigningKey fails when no key to sign download links is configured, links signed with an
+// empty key could be forged by anyone
+func CheckSigningKey(viper *viper.Viper) error {
+	_, err := signingSecret(viper)
+	return err
+}
+
+// signingSecret is storage.signed_url.key, app.secret when it is not set
+func signingSecret(viper *viper.Viper) (string, error) {
+	secret := viper.GetString("storage.signed_url.key")
+	if secret == "" {
+		secret = viper.GetString("app.secret")
+	}
+	if secret == "" {
+		return "", ErrStorageSigningKeyUnset
+	}
+	return secret, nil
+}
+
+// signKey signs the key and expiry with the signing secret
+func signKey(viper *viper.Viper, key string, expires int64) (string, error) {
+	secret, err := signingSecret(viper)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func secondsOr(viper *viper.Viper, key string, fallback int) time.Duration {
+	seconds := viper.GetInt(key)
+	if seconds <= 0 {
+		seconds = fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
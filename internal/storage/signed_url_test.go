@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func newSignedURLViper(key string) *viper.Viper {
+	v := viper.New()
+	v.Set("app.url", "https://recruitment.example.com/")
+	v.Set("storage.signed_url.key", key)
+	return v
+}
+
+// parseSignedURL returns the key, expires and signature of a link made by SignedURL
+func parseSignedURL(t *testing.T, link string) (string, string, string) {
+	t.Helper()
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("invalid link %q: %v", link, err)
+	}
+	return strings.TrimPrefix(parsed.Path, "/"), parsed.Query().Get("expires"), parsed.Query().Get("signature")
+}
+
+func TestSignedURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     string
+		signed   bool
+	}{
+		{name: "private file is signed", filePath: "storage/cv/cv.pdf", want: "https://recruitment.example.com/storage/cv/cv.pdf", signed: true},
+		{name: "leading slash is dropped", filePath: "/storage/cv/cv.pdf", want: "https://recruitment.example.com/storage/cv/cv.pdf", signed: true},
+		{name: "public file is not signed", filePath: "storage/job_posting/logo.png", want: "https://recruitment.example.com/storage/job_posting/logo.png"},
+		{name: "quarantined file is never signed", filePath: QUARANTINE_KEY_PREFIX + "cv.pdf", want: "https://recruitment.example.com/" + QUARANTINE_KEY_PREFIX + "cv.pdf"},
+		{name: "absolute URL is kept", filePath: "https://cdn.example.com/a.png", want: "https://cdn.example.com/a.png"},
+		{name: "empty path is kept", filePath: "", want: ""},
+	}
+
+	v := newSignedURLViper("secret")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := SignedURL(v, tt.filePath, time.Hour)
+			if !strings.HasPrefix(link, tt.want) {
+				t.Fatalf("SignedURL() = %q, want prefix %q", link, tt.want)
+			}
+
+			_, expires, signature := parseSignedURL(t, link)
+			if signed := expires != "" && signature != ""; signed != tt.signed {
+				t.Fatalf("SignedURL() = %q, signed = %t, want %t", link, signed, tt.signed)
+			}
+		})
+	}
+}
+
+func TestVerifySignedURL(t *testing.T) {
+	v := newSignedURLViper("secret")
+	key, expires, signature := parseSignedURL(t, SignedURL(v, "storage/cv/cv.pdf", time.Hour))
+	expiredKey, expiredExpires, expiredSignature := parseSignedURL(t, SignedURL(v, "storage/cv/cv.pdf", -time.Minute))
+	_, _, otherSignature := parseSignedURL(t, SignedURL(newSignedURLViper("other"), "storage/cv/cv.pdf", time.Hour))
+
+	tests := []struct {
+		name      string
+		viper     *viper.Viper
+		key       string
+		expires   string
+		signature string
+		wantErr   error
+	}{
+		{name: "valid link", viper: v, key: key, expires: expires, signature: signature},
+		{name: "other key", viper: v, key: "storage/cv/other.pdf", expires: expires, signature: signature, wantErr: ErrStorageSignatureInvalid},
+		{name: "extended expiry", viper: v, key: key, expires: expires + "0", signature: signature, wantErr: ErrStorageSignatureInvalid},
+		{name: "signed with another secret", viper: v, key: key, expires: expires, signature: otherSignature, wantErr: ErrStorageSignatureInvalid},
+		{name: "missing signature", viper: v, key: key, expires: expires, wantErr: ErrStorageSignatureInvalid},
+		{name: "malformed expiry", viper: v, key: key, expires: "soon", signature: signature, wantErr: ErrStorageSignatureInvalid},
+		{name: "expired link", viper: v, key: expiredKey, expires: expiredExpires, signature: expiredSignature, wantErr: ErrStorageURLExpired},
+		{name: "quarantined key", viper: v, key: QUARANTINE_KEY_PREFIX + "cv.pdf", expires: expires, signature: signature, wantErr: ErrStorageSignatureInvalid},
+		{name: "no signing key configured", viper: newSignedURLViper(""), key: key, expires: expires, signature: signature, wantErr: ErrStorageSigningKeyUnset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignedURL(tt.viper, tt.key, tt.expires, tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifySignedURL() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSigningKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		appSecret string
+		wantErr   error
+	}{
+		{name: "signed url key", key: "secret"},
+		{name: "falls back to the app secret", appSecret: "secret"},
+		{name: "no key at all", wantErr: ErrStorageSigningKeyUnset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newSignedURLViper(tt.key)
+			v.Set("app.secret", tt.appSecret)
+
+			if err := CheckSigningKey(v); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckSigningKey() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// without a key links are left unsigned instead of signed with an empty key
+			_, _, signature := parseSignedURL(t, SignedURL(v, "storage/cv/cv.pdf", time.Hour))
+			if signed := signature != ""; signed != (tt.wantErr == nil) {
+				t.Fatalf("SignedURL() signed = %t, want %t", signed, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	STORAGE_DRIVER_LOCAL = "local"
+	STORAGE_DRIVER_S3    = "s3"
+)
+
+// every stored file lives under this prefix, which is also the first segment of the paths
+// saved in the database
+const STORAGE_KEY_PREFIX = "storage/"
+
+var (
+	ErrStorageFileNotFound = errors.New("file not found")
+	ErrStorageInvalidKey   = errors.New("invalid file path")
+)
+
+// IStorage keeps the uploaded and generated files. Keys are the paths saved in the database,
+// e.g. storage/user_profiles/ktp/1700000000_ktp.jpg.
+type IStorage interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	Exists(key string) (bool, error)
+}
+
+var (
+	storageInstance IStorage
+	storageOnce     sync.Once
+)
+
+// StorageFactory returns the storage selected by storage.driver, local disk unless it is s3
+func StorageFactory(viper *viper.Viper, log *logrus.Logger) IStorage {
+	storageOnce.Do(func() {
+		switch viper.GetString("storage.driver") {
+		case STORAGE_DRIVER_S3:
+			storageInstance = NewS3Storage(S3StorageConfig{
+				Endpoint:  viper.GetString("storage.s3.endpoint"),
+				Region:    viper.GetString("storage.s3.region"),
+				Bucket:    viper.GetString("storage.s3.bucket"),
+				AccessKey: viper.GetString("storage.s3.access_key"),
+				SecretKey: viper.GetString("storage.s3.secret_key"),
+				PathStyle: viper.GetBool("storage.s3.path_style"),
+			})
+		default:
+			storageInstance = NewLocalStorage(LocalStorageRoot(viper))
+		}
+		log.Infof("[Storage] using %s storage", StorageDriver(viper))
+	})
+
+	return storageInstance
+}
+
+func StorageDriver(viper *viper.Viper) string {
+	if viper.GetString("storage.driver") == STORAGE_DRIVER_S3 {
+		return STORAGE_DRIVER_S3
+	}
+	return STORAGE_DRIVER_LOCAL
+}
+
+// LocalStorageRoot is the directory holding the storage/ folder, the working directory by default
+func LocalStorageRoot(viper *viper.Viper) string {
+	if root := viper.GetString("storage.local.root"); root != "" {
+		return root
+	}
+	return "."
+}
+
+// NormalizeKey turns a stored path into a storage key. Leading slashes and ./ are dropped, and
+// paths escaping the storage/ prefix are rejected.
+func NormalizeKey(filePath string) (string, error) {
+	key := strings.TrimLeft(strings.TrimPrefix(filepath.ToSlash(filePath), "./"), "/")
+	if key == "" {
+		return "", ErrStorageInvalidKey
+	}
+
+	key = path.Clean(key)
+	if !strings.HasPrefix(key, STORAGE_KEY_PREFIX) {
+		return "", ErrStorageInvalidKey
+	}
+
+	return key, nil
+}
+
+// ContentType guesses the content type from the extension of the key
+func ContentType(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// PutBytes stores data under key
+func PutBytes(storage IStorage, key string, data []byte) error {
+	return storage.Put(key, bytes.NewReader(data), int64(len(data)), ContentType(key))
+}
+
+// ReadFile reads a whole file
+func ReadFile(storage IStorage, key string) ([]byte, error) {
+	body, err := storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/spf13/viper"
+)
+
+// UploadPurpose selects the types and size an upload may have
+type UploadPurpose string
+
+const (
+	UPLOAD_PURPOSE_AVATAR           UploadPurpose = "avatar"
+	UPLOAD_PURPOSE_IMAGE            UploadPurpose = "image"
+	UPLOAD_PURPOSE_DOCUMENT         UploadPurpose = "document"
+	UPLOAD_PURPOSE_CURRICULUM_VITAE UploadPurpose = "curriculum_vitae"
+	UPLOAD_PURPOSE_SPREADSHEET      UploadPurpose = "spreadsheet"
+	UPLOAD_PURPOSE_GENERIC          UploadPurpose = "generic"
+)
+
+const (
+	CONTENT_TYPE_JPEG = "image/jpeg"
+	CONTENT_TYPE_PNG  = "image/png"
+	CONTENT_TYPE_PDF  = "application/pdf"
+	CONTENT_TYPE_DOC  = "application/msword"
+	CONTENT_TYPE_DOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	CONTENT_TYPE_XLS  = "application/vnd.ms-excel"
+	CONTENT_TYPE_XLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// QUARANTINE_KEY_PREFIX holds the uploads the malware scanner rejected
+const QUARANTINE_KEY_PREFIX = "storage/quarantine/"
+
+var (
+	ErrUploadEmpty           = errors.New("the uploaded file is empty")
+	ErrUploadTooLarge        = errors.New("the uploaded file is too large")
+	ErrUploadTypeNotAllowed  = errors.New("the uploaded file type is not allowed")
+	ErrUploadInfected        = errors.New("the uploaded file did not pass the malware scan")
+	ErrUploadScanUnavailable = errors.New("the malware scanner is not available, try again later")
+)
+
+type UploadPolicy struct {
+	AllowedTypes []string
+	MaxSize      int64
+}
+
+var defaultUploadPolicies = map[UploadPurpose]UploadPolicy{
+	UPLOAD_PURPOSE_AVATAR: {
+		AllowedTypes: []string{CONTENT_TYPE_JPEG, CONTENT_TYPE_PNG},
+		MaxSize:      2 << 20,
+	},
+	UPLOAD_PURPOSE_IMAGE: {
+		AllowedTypes: []string{CONTENT_TYPE_JPEG, CONTENT_TYPE_PNG},
+		MaxSize:      5 << 20,
+	},
+	UPLOAD_PURPOSE_DOCUMENT: {
+		AllowedTypes: []string{CONTENT_TYPE_PDF, CONTENT_TYPE_JPEG, CONTENT_TYPE_PNG, CONTENT_TYPE_DOC, CONTENT_TYPE_DOCX},
+		MaxSize:      10 << 20,
+	},
+	UPLOAD_PURPOSE_CURRICULUM_VITAE: {
+		AllowedTypes: []string{CONTENT_TYPE_PDF, CONTENT_TYPE_DOC, CONTENT_TYPE_DOCX},
+		MaxSize:      10 << 20,
+	},
+	UPLOAD_PURPOSE_SPREADSHEET: {
+		AllowedTypes: []string{CONTENT_TYPE_XLSX},
+		MaxSize:      10 << 20,
+	},
+	UPLOAD_PURPOSE_GENERIC: {
+		AllowedTypes: []string{CONTENT_TYPE_PDF, CONTENT_TYPE_JPEG, CONTENT_TYPE_PNG, CONTENT_TYPE_DOC, CONTENT_TYPE_DOCX, CONTENT_TYPE_XLS, CONTENT_TYPE_XLSX},
+		MaxSize:      10 << 20,
+	},
+}
+
+func init() {
+	// office files are zip archives whose entries can sit past the default 3KB the detector
+	// reads, uploads are in memory anyway so let it read all of them
+	mimetype.SetLimit(0)
+}
+
+// UploadPolicyFor returns the policy of a purpose, upload.policies.<purpose>.allowed_types and
+// max_size (bytes) override the defaults
+func UploadPolicyFor(viper *viper.Viper, purpose UploadPurpose) UploadPolicy {
+	policy, ok := defaultUploadPolicies[purpose]
+	if !ok {
+		policy = defaultUploadPolicies[UPLOAD_PURPOSE_GENERIC]
+	}
+
+	key := "upload.policies." + string(purpose)
+	if types := viper.GetStringSlice(key + ".allowed_types"); len(types) > 0 {
+		policy.AllowedTypes = types
+	}
+	if maxSize := viper.GetInt64(key + ".max_size"); maxSize > 0 {
+		policy.MaxSize = maxSize
+	}
+
+	return policy
+}
+
+// SniffUpload detects the type of an upload from its content, the name and content type sent
+// by the client are ignored. It returns the content type and the extension that goes with it.
+func SniffUpload(policy UploadPolicy, data []byte) (string, string, error) {
+	if len(data) == 0 {
+		return "", "", ErrUploadEmpty
+	}
+	if int64(len(data)) > policy.MaxSize {
+		return "", "", TooLargeError(policy.MaxSize)
+	}
+
+	detected := mimetype.Detect(data)
+	for _, allowed := range policy.AllowedTypes {
+		if detected.Is(allowed) {
+			contentType, _, _ := strings.Cut(detected.String(), ";")
+			return contentType, detected.Extension(), nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w (%s)", ErrUploadTypeNotAllowed, detected.String())
+}
+
+// TooLargeError is ErrUploadTooLarge with the limit in it
+func TooLargeError(maxSize int64) error {
+	if maxSize >= 1<<20 {
+		return fmt.Errorf("%w, the limit is %d MB", ErrUploadTooLarge, maxSize>>20)
+	}
+	return fmt.Errorf("%w, the limit is %d KB", ErrUploadTooLarge, max(maxSize>>10, 1))
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SanitizeFilename keeps the base name of a client filename with only letters, digits, dots,
+// dashes and underscores, and replaces its extension with ext
+func SanitizeFilename(filename string, ext string) string {
+	name := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = unsafeFilenameChars.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "._-")
+	if len(name) > 80 {
+		name = strings.Trim(name[:80], "._-")
+	}
+	if name == "" {
+		name = "file"
+	}
+
+	return name + ext
+}
+
+// IsImageContentType reports whether uploads of the type are re-encoded
+func IsImageContentType(contentType string) bool {
+	return contentType == CONTENT_TYPE_JPEG || contentType == CONTENT_TYPE_PNG
+}
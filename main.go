@@ -14,6 +14,9 @@ import (
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/config"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/rabbitmq"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/route"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/service"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/usecase"
+	"github.com/IlhamSetiaji/julong-recruitment-be/internal/storage"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
@@ -72,20 +75,86 @@ func main() {
 	log := config.NewLogrus(viper)
 	// generateSwaggerDocs(viper.GetString("app.env"))
 
+	if err := storage.CheckSigningKey(viper); err != nil {
+		log.Panicf("Failed to start: %v", err)
+	}
+
 	var wg sync.WaitGroup
-	wg.Add(2)
+	if viper.GetString("rabbitmq.driver") == "memory" {
+		// in-process broker with fake julong services, for local development
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			rabbitmq.InitMemoryBroker(viper, log)
+		}()
+	} else {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			rabbitmq.InitConsumer(viper, log)
+		}()
+
+		go func() {
+			defer wg.Done()
+			rabbitmq.InitProducer(viper, log)
+		}()
+	}
 
-	go func() {
-		defer wg.Done()
-		rabbitmq.InitConsumer(viper, log)
-	}()
+	if viper.GetString("midsuit.driver") == "simulator" {
+		// in-process Midsuit API, for local development
+		midsuitURL, err := service.MidsuitSimulatorFactory(viper, log).Start(viper.GetString("midsuit.simulator.address"))
+		if err != nil {
+			log.Panicf("Failed to start midsuit simulator: %v", err)
+		}
+		viper.Set("midsuit.url", midsuitURL)
+	}
 
-	go func() {
-		defer wg.Done()
-		rabbitmq.InitProducer(viper, log)
-	}()
+	if viper.GetString("midsuit.sync") == "ACTIVE" {
+		// retries pending and failed midsuit sync jobs
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			usecase.MidsuitSyncUseCaseFactory(log, viper).StartMidsuitSyncWorker()
+		}()
+	}
+
+	if !viper.IsSet("scheduler.enabled") || viper.GetBool("scheduler.enabled") {
+		// time-driven jobs, only the replica holding the leader lock runs them
+		scheduler := usecase.SchedulerUseCaseFactory(log, viper)
+		if err := usecase.RecruitmentLifecycleUseCaseFactory(log, viper).RegisterJobs(scheduler); err != nil {
+			log.Panicf("Failed to register scheduler jobs: %v", err)
+		}
+		if err := usecase.ScheduleReminderUseCaseFactory(log, viper).RegisterJobs(scheduler); err != nil {
+			log.Panicf("Failed to register scheduler jobs: %v", err)
+		}
+		if err := usecase.CandidateSearchUseCaseFactory(log, viper).RegisterJobs(scheduler); err != nil {
+			log.Panicf("Failed to register scheduler jobs: %v", err)
+		}
+		if err := usecase.ApplicantMatchScoreUseCaseFactory(log, viper).RegisterJobs(scheduler); err != nil {
+			log.Panicf("Failed to register scheduler jobs: %v", err)
+		}
+		if err := usecase.DuplicateCandidateUseCaseFactory(log, viper).RegisterJobs(scheduler); err != nil {
+			log.Panicf("Failed to register scheduler jobs: %v", err)
+		}
+		if err := usecase.SlaUseCaseFactory(log, viper).RegisterJobs(scheduler); err != nil {
+			log.Panicf("Failed to register scheduler jobs: %v", err)
+		}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			scheduler.Start()
+		}()
+	}
 
 	app := gin.Default()
+	// without trusted proxies the client IP is the peer address, X-Forwarded-For is ignored
+	if err := app.SetTrustedProxies(viper.GetStringSlice("web.trusted_proxies")); err != nil {
+		log.Panicf("Invalid web.trusted_proxies: %v", err)
+	}
 	app.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("App-Name", viper.GetString("app.name"))
 	})
@@ -106,7 +175,6 @@ func main() {
 		MaxAge: 12 * time.Hour,
 	}))
 
-	app.Static("/storage", "./storage")
 	// setup custom csrf middleware
 	app.Use(func(c *gin.Context) {
 		if !shouldExcludeFromCSRF(c.Request.URL.Path) {
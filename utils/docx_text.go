@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrDocumentTooLarge is returned when a document decompresses to more than the allowed size
+var ErrDocumentTooLarge = errors.New("the document is too large to be read")
+
+// ExtractDOCXText returns the text of the main document part of a DOCX file, one line per
+// paragraph. maxDecodedSize caps the uncompressed size of that part.
+func ExtractDOCXText(data []byte, maxDecodedSize int64) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", errors.New("not a DOCX document: " + err.Error())
+	}
+
+	var document *zip.File
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			document = file
+			break
+		}
+	}
+	if document == nil {
+		return "", errors.New("not a DOCX document: word/document.xml not found")
+	}
+
+	reader, err := document.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	var out strings.Builder
+	decoder := xml.NewDecoder(newCappedReader(reader, maxDecodedSize))
+	inText := false
+	// table rows become lines with the cells separated by tabs
+	cellDepth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, ErrDocumentTooLarge) {
+			return "", err
+		}
+		if err != nil {
+			return "", errors.New("invalid DOCX document: " + err.Error())
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tc":
+				cellDepth++
+			case "tab":
+				out.WriteString("\t")
+			case "br", "cr":
+				out.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if cellDepth > 0 {
+					out.WriteString(" ")
+				} else {
+					out.WriteString("\n")
+				}
+			case "tc":
+				cellDepth--
+				out.WriteString("\t")
+			case "tr":
+				out.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				out.Write(t)
+			}
+		}
+	}
+
+	return normalizeExtractedText(out.String()), nil
+}
+
+// ExtractDocumentText returns the text of a PDF or DOCX file, the type is taken from the extension of name.
+// Reading stops with ErrDocumentTooLarge once more than maxDecodedSize bytes were decompressed.
+func ExtractDocumentText(name string, data []byte, maxDecodedSize int64) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".pdf":
+		return ExtractPDFText(data, maxDecodedSize)
+	case ".docx":
+		return ExtractDOCXText(data, maxDecodedSize)
+	}
+	return "", errors.New("unsupported document type, only PDF and DOCX can be read")
+}
+
+// cappedReader fails with ErrDocumentTooLarge instead of reading past its limit
+type cappedReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func newCappedReader(reader io.Reader, limit int64) *cappedReader {
+	return &cappedReader{reader: io.LimitReader(reader, limit+1), limit: limit}
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.read += int64(n)
+	if c.read > c.limit {
+		return n, ErrDocumentTooLarge
+	}
+	return n, err
+}
+
+var extractedTextTabs = regexp.MustCompile(`[ \x{00a0}]*\t[\t \x{00a0}]*`)
+var extractedTextSpaces = regexp.MustCompile(`[ \x{00a0}]+`)
+var extractedTextBlankLines = regexp.MustCompile(`\n{3,}`)
+
+func normalizeExtractedText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = extractedTextTabs.ReplaceAllString(line, "\t")
+		lines[i] = strings.TrimSpace(extractedTextSpaces.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(extractedTextBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
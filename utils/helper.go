@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/rand"
 	"math/big"
+	"sync"
 
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/request"
 	"github.com/IlhamSetiaji/julong-recruitment-be/internal/http/response"
@@ -10,7 +11,9 @@ import (
 
 var ResponseChannel = make(chan map[string]interface{}, 100)
 
-var Rchans = make(map[string](chan response.RabbitMQResponse))
+// Rchans holds the channel of every request waiting for its reply, keyed by the request id. It is
+// a sync.Map since requests register and forget channels while the consumer delivers replies.
+var Rchans sync.Map // map[string]chan response.RabbitMQResponse
 
 type RabbitMsgPublisher struct {
 	QueueName string                  `json:"queueName"`
@@ -0,0 +1,849 @@
+package utils
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// A small PDF text extractor for CVs: it follows the page tree, inflates the content streams
+// and maps the shown strings through the ToUnicode CMaps of the fonts. Positioning is only
+// used to tell new lines from spaces, images and form XObjects are ignored.
+
+type pdfName string
+type pdfKeyword string
+type pdfString []byte
+type pdfRef int
+
+type pdfObject struct {
+	value  interface{}
+	stream []byte
+}
+
+type pdfFont struct {
+	codeLen int
+	cmap    map[string]string
+}
+
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// pdfMaxNesting bounds how deeply arrays and dictionaries may nest
+const pdfMaxNesting = 64
+
+// ExtractPDFText returns the text of a PDF document, one line per text line. maxDecodedSize
+// caps the bytes inflated from all streams together.
+func ExtractPDFText(data []byte, maxDecodedSize int64) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF")) {
+		return "", errors.New("not a PDF document")
+	}
+	doc := &pdfDocument{objects: map[int]*pdfObject{}, fonts: map[int]*pdfFont{}, remaining: maxDecodedSize}
+	doc.readObjects(data)
+	if doc.err != nil {
+		return "", doc.err
+	}
+
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return "", errors.New("no pages found, the document may be encrypted or damaged")
+	}
+
+	var out strings.Builder
+	for _, page := range pages {
+		doc.extractPage(page, &out)
+		if doc.err != nil {
+			return "", doc.err
+		}
+		out.WriteString("\n")
+	}
+
+	return normalizeExtractedText(out.String()), nil
+}
+
+type pdfDocument struct {
+	objects map[int]*pdfObject
+	fonts   map[int]*pdfFont
+	// remaining is what may still be inflated, err is set once it runs out
+	remaining int64
+	err       error
+}
+
+func (d *pdfDocument) readObjects(data []byte) {
+	for _, match := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[match[2]:match[3]]))
+		if err != nil {
+			continue
+		}
+		lexer := &pdfLexer{data: data, pos: match[1]}
+		value, err := lexer.value()
+		if err != nil {
+			continue
+		}
+		obj := &pdfObject{value: value}
+		lexer.skipSpace()
+		if bytes.HasPrefix(data[lexer.pos:], []byte("stream")) {
+			obj.stream = readPDFStream(data, lexer.pos+len("stream"), value)
+		}
+		// later definitions belong to incremental updates and win
+		d.objects[num] = obj
+	}
+
+	// objects packed in object streams of PDF 1.5 and later
+	for _, obj := range d.objects {
+		dict, ok := obj.value.(map[string]interface{})
+		if !ok || dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		decoded, err := d.decodeStream(obj)
+		if err != nil {
+			continue
+		}
+		count, _ := d.resolve(dict["N"]).(float64)
+		first, _ := d.resolve(dict["First"]).(float64)
+		header := &pdfLexer{data: decoded}
+		for i := 0; i < int(count); i++ {
+			num, err1 := header.value()
+			offset, err2 := header.value()
+			n, ok1 := num.(float64)
+			o, ok2 := offset.(float64)
+			if err1 != nil || err2 != nil || !ok1 || !ok2 {
+				break
+			}
+			if _, exists := d.objects[int(n)]; exists {
+				continue
+			}
+			if first < 0 || o < 0 || first+o >= float64(len(decoded)) {
+				continue
+			}
+			lexer := &pdfLexer{data: decoded, pos: int(first) + int(o)}
+			if value, err := lexer.value(); err == nil {
+				d.objects[int(n)] = &pdfObject{value: value}
+			}
+		}
+	}
+}
+
+func readPDFStream(data []byte, pos int, value interface{}) []byte {
+	if bytes.HasPrefix(data[pos:], []byte("\r\n")) {
+		pos += 2
+	} else if pos < len(data) && (data[pos] == '\n' || data[pos] == '\r') {
+		pos++
+	}
+	if dict, ok := value.(map[string]interface{}); ok {
+		if length, ok := dict["Length"].(float64); ok && length > 0 && length <= float64(len(data)-pos) {
+			rest := bytes.TrimLeft(data[pos+int(length):], " \t\r\n")
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				return data[pos : pos+int(length)]
+			}
+		}
+	}
+	end := bytes.Index(data[pos:], []byte("endstream"))
+	if end < 0 {
+		return nil
+	}
+	return bytes.TrimRight(data[pos:pos+end], "\r\n")
+}
+
+func (d *pdfDocument) resolve(value interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := value.(pdfRef)
+		if !ok {
+			return value
+		}
+		obj, ok := d.objects[int(ref)]
+		if !ok {
+			return nil
+		}
+		value = obj.value
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(value interface{}) map[string]interface{} {
+	dict, _ := d.resolve(value).(map[string]interface{})
+	return dict
+}
+
+func (d *pdfDocument) decodeStream(obj *pdfObject) ([]byte, error) {
+	dict, _ := obj.value.(map[string]interface{})
+	var filters []interface{}
+	switch filter := d.resolve(dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{filter}
+	case []interface{}:
+		filters = filter
+	}
+
+	data := obj.stream
+	for _, filter := range filters {
+		switch d.resolve(filter) {
+		case pdfName("FlateDecode"):
+			inflated, err := inflate(data, d.remaining)
+			if errors.Is(err, ErrDocumentTooLarge) {
+				d.err = err
+			}
+			if err != nil {
+				return nil, err
+			}
+			d.remaining -= int64(len(inflated))
+			data = inflated
+		case pdfName("ASCIIHexDecode"):
+			data = decodeHexString(bytes.TrimSuffix(bytes.TrimSpace(data), []byte(">")))
+		default:
+			return nil, errors.New("unsupported stream filter")
+		}
+	}
+	return data, nil
+}
+
+func inflate(data []byte, limit int64) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return io.ReadAll(newCappedReader(flate.NewReader(bytes.NewReader(data)), limit))
+	}
+	defer reader.Close()
+	inflated, err := io.ReadAll(newCappedReader(reader, limit))
+	if errors.Is(err, ErrDocumentTooLarge) {
+		return nil, err
+	}
+	// a truncated stream still holds most of the text
+	if err != nil && len(inflated) == 0 {
+		return nil, err
+	}
+	return inflated, nil
+}
+
+// pages walks the page tree in reading order and returns the page dictionaries with their
+// inherited resources
+func (d *pdfDocument) pages() []map[string]interface{} {
+	var root interface{}
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		if dict, ok := d.objects[num].value.(map[string]interface{}); ok && dict["Type"] == pdfName("Catalog") {
+			root = dict["Pages"]
+		}
+	}
+
+	var pages []map[string]interface{}
+	visited := map[interface{}]bool{}
+	var walk func(node interface{}, resources interface{})
+	walk = func(node interface{}, resources interface{}) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		dict := d.dict(node)
+		if dict == nil {
+			return
+		}
+		if dict["Resources"] != nil {
+			resources = dict["Resources"]
+		}
+		if kids, ok := d.resolve(dict["Kids"]).([]interface{}); ok {
+			for _, kid := range kids {
+				walk(kid, resources)
+			}
+			return
+		}
+		page := map[string]interface{}{}
+		for key, value := range dict {
+			page[key] = value
+		}
+		page["Resources"] = resources
+		pages = append(pages, page)
+	}
+	if root != nil {
+		walk(root, nil)
+	}
+
+	if len(pages) == 0 {
+		for _, num := range nums {
+			if dict, ok := d.objects[num].value.(map[string]interface{}); ok && dict["Type"] == pdfName("Page") {
+				pages = append(pages, dict)
+			}
+		}
+	}
+	return pages
+}
+
+func (d *pdfDocument) extractPage(page map[string]interface{}, out *strings.Builder) {
+	fonts := map[string]*pdfFont{}
+	if resources := d.dict(page["Resources"]); resources != nil {
+		for name, ref := range d.dict(resources["Font"]) {
+			fonts[name] = d.font(ref)
+		}
+	}
+
+	var contents []interface{}
+	switch value := page["Contents"].(type) {
+	case []interface{}:
+		contents = value
+	case pdfRef:
+		if array, ok := d.resolve(value).([]interface{}); ok {
+			contents = array
+		} else {
+			contents = []interface{}{value}
+		}
+	}
+
+	var content []byte
+	for _, ref := range contents {
+		r, ok := ref.(pdfRef)
+		if !ok {
+			continue
+		}
+		obj, ok := d.objects[int(r)]
+		if !ok || obj.stream == nil {
+			continue
+		}
+		decoded, err := d.decodeStream(obj)
+		if err != nil {
+			continue
+		}
+		content = append(content, decoded...)
+		content = append(content, '\n')
+	}
+
+	(&pdfTextWriter{out: out, fonts: fonts}).run(content)
+}
+
+func (d *pdfDocument) font(value interface{}) *pdfFont {
+	ref, isRef := value.(pdfRef)
+	if isRef {
+		if font, ok := d.fonts[int(ref)]; ok {
+			return font
+		}
+	}
+
+	font := &pdfFont{codeLen: 1}
+	dict := d.dict(value)
+	if dict != nil {
+		if dict["Subtype"] == pdfName("Type0") {
+			font.codeLen = 2
+		}
+		if cmapRef, ok := dict["ToUnicode"].(pdfRef); ok {
+			if obj, ok := d.objects[int(cmapRef)]; ok && obj.stream != nil {
+				if decoded, err := d.decodeStream(obj); err == nil {
+					font.cmap, font.codeLen = parseToUnicodeCMap(decoded, font.codeLen)
+				}
+			}
+		}
+	}
+
+	if isRef {
+		d.fonts[int(ref)] = font
+	}
+	return font
+}
+
+func parseToUnicodeCMap(data []byte, codeLen int) (map[string]string, int) {
+	cmap := map[string]string{}
+	lexer := &pdfLexer{data: data}
+	var operands []interface{}
+	for {
+		token, err := lexer.value()
+		if err != nil {
+			break
+		}
+		keyword, ok := token.(pdfKeyword)
+		if !ok {
+			operands = append(operands, token)
+			continue
+		}
+		switch keyword {
+		case "endcodespacerange":
+			if len(operands) >= 1 {
+				if lo, ok := operands[0].(pdfString); ok && len(lo) > 0 {
+					codeLen = len(lo)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					cmap[string(src)] = decodeUTF16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) {
+					continue
+				}
+				start, end := bytesToInt(lo), bytesToInt(hi)
+				if end < start || end-start > 0xffff {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					base := bytesToInt(dst)
+					for code := start; code <= end; code++ {
+						cmap[string(intToBytes(code, len(lo)))] = decodeUTF16BE(intToBytes(base+code-start, len(dst)))
+					}
+				case []interface{}:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && start+j <= end {
+							cmap[string(intToBytes(start+j, len(lo)))] = decodeUTF16BE(s)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return cmap, codeLen
+}
+
+func (f *pdfFont) decode(s pdfString) string {
+	if f == nil {
+		f = &pdfFont{codeLen: 1}
+	}
+	var out strings.Builder
+	for i := 0; i < len(s); i += f.codeLen {
+		end := i + f.codeLen
+		if end > len(s) {
+			end = len(s)
+		}
+		code := string(s[i:end])
+		if text, ok := f.cmap[code]; ok {
+			out.WriteString(text)
+			continue
+		}
+		if f.codeLen == 1 {
+			out.WriteRune(winAnsiRune(s[i]))
+		}
+	}
+	return out.String()
+}
+
+// pdfTextWriter plays the text operators of a content stream
+type pdfTextWriter struct {
+	out        *strings.Builder
+	fonts      map[string]*pdfFont
+	font       *pdfFont
+	y          float64
+	shownY     float64
+	shown      bool
+	newLine    bool
+	space      bool
+	lastSpaced bool
+}
+
+func (w *pdfTextWriter) run(content []byte) {
+	lexer := &pdfLexer{data: content}
+	var operands []interface{}
+	for {
+		token, err := lexer.value()
+		if err != nil {
+			return
+		}
+		op, ok := token.(pdfKeyword)
+		if !ok {
+			operands = append(operands, token)
+			continue
+		}
+		w.operator(string(op), operands)
+		if op == "BI" {
+			lexer.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+func (w *pdfTextWriter) operator(op string, operands []interface{}) {
+	number := func(i int) float64 {
+		if i < 0 || i >= len(operands) {
+			return 0
+		}
+		n, _ := operands[i].(float64)
+		return n
+	}
+
+	switch op {
+	case "BT":
+		w.y = 0
+		w.space = true
+	case "Tf":
+		if len(operands) >= 1 {
+			if name, ok := operands[0].(pdfName); ok {
+				w.font = w.fonts[string(name)]
+			}
+		}
+	case "Td", "TD":
+		// a move on the same line still separates words, show decides between space and line
+		w.y += number(1)
+		w.space = true
+	case "Tm":
+		w.y = number(5)
+		w.space = true
+	case "T*":
+		w.newLine = true
+	case "Tj":
+		if len(operands) >= 1 {
+			w.show(operands[0])
+		}
+	case "'", "\"":
+		w.newLine = true
+		if len(operands) >= 1 {
+			w.show(operands[len(operands)-1])
+		}
+	case "TJ":
+		if len(operands) >= 1 {
+			if array, ok := operands[0].([]interface{}); ok {
+				for _, item := range array {
+					if kerning, ok := item.(float64); ok {
+						// a gap wider than a quarter of the font size separates words
+						if kerning < -250 {
+							w.space = true
+						}
+						continue
+					}
+					w.show(item)
+				}
+			}
+		}
+	}
+}
+
+func (w *pdfTextWriter) show(value interface{}) {
+	s, ok := value.(pdfString)
+	if !ok {
+		return
+	}
+	text := w.font.decode(s)
+	if text == "" {
+		return
+	}
+
+	if w.shown && (w.newLine || math.Abs(w.y-w.shownY) > 0.5) {
+		w.out.WriteString("\n")
+	} else if w.shown && w.space && !w.lastSpaced && !strings.HasPrefix(text, " ") {
+		w.out.WriteString(" ")
+	}
+	w.out.WriteString(text)
+	w.shown, w.newLine, w.space = true, false, false
+	w.shownY = w.y
+	w.lastSpaced = strings.HasSuffix(text, " ")
+}
+
+// pdfLexer reads PDF values and, in content streams and CMaps, operators
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *pdfLexer) value() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+
+	c := l.data[l.pos]
+	if c == '[' || (c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<') {
+		if l.depth >= pdfMaxNesting {
+			return nil, errors.New("PDF values nested too deeply")
+		}
+		l.depth++
+		defer func() { l.depth-- }()
+	}
+	switch {
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		dict := map[string]interface{}{}
+		for {
+			l.skipSpace()
+			if l.pos+1 < len(l.data) && l.data[l.pos] == '>' && l.data[l.pos+1] == '>' {
+				l.pos += 2
+				return dict, nil
+			}
+			key, err := l.value()
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(pdfName)
+			if !ok {
+				continue
+			}
+			value, err := l.value()
+			if err != nil {
+				return nil, err
+			}
+			dict[string(name)] = value
+		}
+	case c == '[':
+		l.pos++
+		array := []interface{}{}
+		for {
+			l.skipSpace()
+			if l.pos < len(l.data) && l.data[l.pos] == ']' {
+				l.pos++
+				return array, nil
+			}
+			value, err := l.value()
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+	case c == '(':
+		return l.literalString(), nil
+	case c == '<':
+		end := bytes.IndexByte(l.data[l.pos:], '>')
+		if end < 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		s := decodeHexString(l.data[l.pos+1 : l.pos+end])
+		l.pos += end + 1
+		return pdfString(s), nil
+	case c == '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfName(decodeNameEscapes(string(l.data[start:l.pos]))), nil
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.pos++
+		return pdfKeyword(string(c)), nil
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	token := string(l.data[start:l.pos])
+	if token == "" {
+		l.pos++
+		return pdfKeyword(string(c)), nil
+	}
+	number, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		switch token {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return pdfKeyword(token), nil
+	}
+
+	// "12 0 R" is a reference
+	if number == math.Trunc(number) && number >= 0 {
+		save := l.pos
+		l.skipSpace()
+		genStart := l.pos
+		for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+			l.pos++
+		}
+		if l.pos > genStart {
+			l.skipSpace()
+			if l.pos < len(l.data) && l.data[l.pos] == 'R' && (l.pos+1 == len(l.data) || isPDFSpace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+				l.pos++
+				return pdfRef(int(number)), nil
+			}
+		}
+		l.pos = save
+	}
+	return number, nil
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					value := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(value))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// skipInlineImage moves past the binary data of an inline image, up to its EI operator
+func (l *pdfLexer) skipInlineImage() {
+	id := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if id < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += id + 3
+	for l.pos < len(l.data) {
+		ei := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if ei < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		at := l.pos + ei
+		l.pos = at + 2
+		if at > 0 && isPDFSpace(l.data[at-1]) && (l.pos == len(l.data) || isPDFSpace(l.data[l.pos])) {
+			return
+		}
+	}
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func decodeHexString(data []byte) []byte {
+	digits := make([]byte, 0, len(data))
+	for _, c := range data {
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	hex.Decode(out, digits)
+	return out
+}
+
+func decodeNameEscapes(name string) string {
+	if !strings.Contains(name, "#") {
+		return name
+	}
+	var out strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '#' && i+2 < len(name) {
+			if b, err := hex.DecodeString(name[i+1 : i+3]); err == nil {
+				out.WriteByte(b[0])
+				i += 2
+				continue
+			}
+		}
+		out.WriteByte(name[i])
+	}
+	return out.String()
+}
+
+func decodeUTF16BE(data []byte) string {
+	if len(data)%2 == 1 {
+		return string(data)
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func bytesToInt(data []byte) int {
+	value := 0
+	for _, b := range data {
+		value = value<<8 | int(b)
+	}
+	return value
+}
+
+func intToBytes(value, length int) []byte {
+	out := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		out[i] = byte(value)
+		value >>= 8
+	}
+	return out
+}
+
+// winAnsiRune maps the bytes of simple fonts without a ToUnicode CMap, Windows-1252 is close
+// enough to the standard encodings for the letters, digits and punctuation of a CV
+func winAnsiRune(b byte) rune {
+	switch b {
+	case 0x91, 0x92:
+		return '\''
+	case 0x93, 0x94:
+		return '"'
+	case 0x95:
+		return '•'
+	case 0x96, 0x97:
+		return '-'
+	}
+	if b < 0x20 && b != '\t' {
+		return ' '
+	}
+	return rune(b)
+}